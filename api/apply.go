@@ -0,0 +1,367 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"emperror.dev/emperror"
+	"emperror.dev/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/cluster"
+	"github.com/banzaicloud/pipeline/internal/apply"
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/pkg/cluster/ack"
+	"github.com/banzaicloud/pipeline/pkg/cluster/aks"
+	"github.com/banzaicloud/pipeline/pkg/cluster/eks"
+	"github.com/banzaicloud/pipeline/pkg/cluster/gke"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+// ApplyAPI reconciles the resources of an organization from declarative manifests.
+type ApplyAPI struct {
+	applier *apply.Applier
+
+	logger       logrus.FieldLogger
+	errorHandler emperror.Handler
+}
+
+// NewApplyAPI returns a new ApplyAPI.
+func NewApplyAPI(applier *apply.Applier, logger logrus.FieldLogger, errorHandler emperror.Handler) *ApplyAPI {
+	return &ApplyAPI{
+		applier:      applier,
+		logger:       logger,
+		errorHandler: errorHandler,
+	}
+}
+
+// Apply computes the plan of a multi-document manifest of clusters, secrets, deployments and cluster features,
+// and executes it unless dryRun is set.
+func (a *ApplyAPI) Apply(c *gin.Context) {
+	resources, err := apply.ParseManifest(c.Request.Body)
+	if err != nil {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid manifest",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	options := apply.Options{}
+	options.DryRun, _ = strconv.ParseBool(c.Query("dryRun"))
+	options.Prune, _ = strconv.ParseBool(c.Query("prune"))
+
+	organization := auth.GetCurrentOrganization(c.Request)
+	user := auth.GetCurrentUser(c.Request)
+
+	scope := apply.Scope{
+		OrganizationID:   organization.ID,
+		OrganizationName: organization.Name,
+		UserID:           user.ID,
+		UserLogin:        user.Login,
+	}
+
+	// cluster creation outlives the request
+	ctx := ginutils.Context(context.Background(), c)
+
+	result, err := a.applier.Apply(ctx, scope, resources, options)
+	if err != nil {
+		a.errorHandler.Handle(err)
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "failed to apply manifest",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ClusterReconciler returns a reconciler creating, updating and deleting clusters the same way the cluster API does.
+func (a *ClusterAPI) ClusterReconciler() apply.Reconciler {
+	return clusterReconciler{api: a}
+}
+
+// clusterReconciler creates missing clusters and updates the node pools of existing ones.
+// Differences in the immutable properties of existing clusters are reported as errors.
+type clusterReconciler struct {
+	api *ClusterAPI
+}
+
+func (r clusterReconciler) Plan(ctx context.Context, scope apply.Scope, resource apply.Resource) (apply.Action, error) {
+	commonCluster, err := r.api.clusterManager.GetClusterByName(ctx, scope.OrganizationID, resource.Name)
+	if intCluster.IsClusterNotFoundError(err) {
+		return apply.ActionCreate, nil
+	} else if err != nil {
+		return "", errors.WrapIf(err, "failed to get cluster")
+	}
+
+	status, err := commonCluster.GetStatus()
+	if err != nil {
+		return "", errors.WrapIf(err, "failed to get cluster status")
+	}
+
+	updateRequest, err := clusterUpdateRequest(resource.Name, resource.Spec.(*pkgCluster.CreateClusterRequest), status)
+	if err != nil {
+		return "", err
+	}
+
+	if updateRequest != nil {
+		return apply.ActionUpdate, nil
+	}
+
+	return apply.ActionNoop, nil
+}
+
+// clusterUpdateRequest returns the request updating an existing cluster to the manifest, or nil if it is up to date.
+func clusterUpdateRequest(name string, request *pkgCluster.CreateClusterRequest, status *pkgCluster.GetClusterStatusResponse) (*pkgCluster.UpdateClusterRequest, error) {
+	if diffs := clusterDrift(request, status); len(diffs) > 0 {
+		return nil, errors.Errorf("cluster %s differs from the manifest (%s), it has to be recreated", name, strings.Join(diffs, ", "))
+	}
+
+	distribution, requested := requestedNodePools(request)
+	if distribution == "" || !nodePoolsDiffer(requested, status.NodePools) {
+		return nil, nil
+	}
+
+	updateRequest := &pkgCluster.UpdateClusterRequest{
+		Cloud:        request.Cloud,
+		ScaleOptions: request.ScaleOptions,
+		TtlMinutes:   request.TtlMinutes,
+	}
+
+	properties := request.Properties
+	switch {
+	case properties.CreateClusterEKS != nil:
+		updateRequest.EKS = &eks.UpdateClusterAmazonEKS{
+			NodePools: properties.CreateClusterEKS.NodePools,
+		}
+	case properties.CreateClusterAKS != nil:
+		nodePools := make(map[string]*aks.NodePoolUpdate, len(properties.CreateClusterAKS.NodePools))
+		for name, np := range properties.CreateClusterAKS.NodePools {
+			nodePools[name] = &aks.NodePoolUpdate{
+				Autoscaling: np.Autoscaling,
+				MinCount:    np.MinCount,
+				MaxCount:    np.MaxCount,
+				Count:       np.Count,
+				Labels:      np.Labels,
+				Taints:      np.Taints,
+			}
+		}
+
+		updateRequest.AKS = &aks.UpdateClusterAzure{
+			NodePools: nodePools,
+		}
+	case properties.CreateClusterGKE != nil:
+		updateRequest.GKE = &gke.UpdateClusterGoogle{
+			NodePools: properties.CreateClusterGKE.NodePools,
+		}
+	case properties.CreateClusterACK != nil:
+		updateRequest.ACK = &ack.UpdateClusterACK{
+			NodePools: properties.CreateClusterACK.NodePools,
+		}
+	}
+
+	return updateRequest, nil
+}
+
+// clusterDrift returns the differences in the immutable properties of the requested and the existing cluster.
+func clusterDrift(request *pkgCluster.CreateClusterRequest, status *pkgCluster.GetClusterStatusResponse) []string {
+	var diffs []string
+
+	if request.Cloud != "" && request.Cloud != status.Cloud {
+		diffs = append(diffs, fmt.Sprintf("cloud: %s != %s", status.Cloud, request.Cloud))
+	}
+
+	if request.Location != "" && request.Location != status.Location {
+		diffs = append(diffs, fmt.Sprintf("location: %s != %s", status.Location, request.Location))
+	}
+
+	if distribution, _ := requestedNodePools(request); distribution != "" && distribution != status.Distribution {
+		diffs = append(diffs, fmt.Sprintf("distribution: %s != %s", status.Distribution, distribution))
+	}
+
+	sort.Strings(diffs)
+
+	return diffs
+}
+
+// nodePoolSpec contains the node pool settings compared to the existing node pools.
+type nodePoolSpec struct {
+	InstanceType string
+	// Autoscaling is nil if the distribution has no autoscaling switch (the node count is always managed by the autoscaler)
+	Autoscaling *bool
+	MinCount    int
+	MaxCount    int
+	Count       int
+}
+
+// differsFrom decides whether a node pool has to be updated.
+// Node counts are not compared when the node pool is autoscaled, as they are changed by the autoscaler.
+func (s nodePoolSpec) differsFrom(status *pkgCluster.NodePoolStatus) bool {
+	if s.InstanceType != "" && s.InstanceType != status.InstanceType {
+		return true
+	}
+
+	if s.Autoscaling != nil && *s.Autoscaling != status.Autoscaling {
+		return true
+	}
+
+	if s.Autoscaling == nil || *s.Autoscaling {
+		return s.MinCount != status.MinCount || s.MaxCount != status.MaxCount
+	}
+
+	return s.Count != status.Count
+}
+
+func nodePoolsDiffer(requested map[string]nodePoolSpec, existing map[string]*pkgCluster.NodePoolStatus) bool {
+	if len(requested) != len(existing) {
+		return true
+	}
+
+	for name, spec := range requested {
+		status, ok := existing[name]
+		if !ok || spec.differsFrom(status) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// requestedNodePools returns the distribution and the node pools of a cluster request.
+// The distribution is empty if updating its node pools is not supported.
+func requestedNodePools(request *pkgCluster.CreateClusterRequest) (string, map[string]nodePoolSpec) {
+	properties := request.Properties
+	if properties == nil {
+		return "", nil
+	}
+
+	var distribution string
+	nodePools := make(map[string]nodePoolSpec)
+
+	switch {
+	case properties.CreateClusterEKS != nil:
+		distribution = pkgCluster.EKS
+		for name, np := range properties.CreateClusterEKS.NodePools {
+			autoscaling := np.Autoscaling
+			nodePools[name] = nodePoolSpec{InstanceType: np.InstanceType, Autoscaling: &autoscaling, MinCount: np.MinCount, MaxCount: np.MaxCount, Count: np.Count}
+		}
+	case properties.CreateClusterAKS != nil:
+		distribution = pkgCluster.AKS
+		for name, np := range properties.CreateClusterAKS.NodePools {
+			autoscaling := np.Autoscaling
+			nodePools[name] = nodePoolSpec{InstanceType: np.NodeInstanceType, Autoscaling: &autoscaling, MinCount: np.MinCount, MaxCount: np.MaxCount, Count: np.Count}
+		}
+	case properties.CreateClusterGKE != nil:
+		distribution = pkgCluster.GKE
+		for name, np := range properties.CreateClusterGKE.NodePools {
+			autoscaling := np.Autoscaling
+			nodePools[name] = nodePoolSpec{InstanceType: np.NodeInstanceType, Autoscaling: &autoscaling, MinCount: np.MinCount, MaxCount: np.MaxCount, Count: np.Count}
+		}
+	case properties.CreateClusterACK != nil:
+		distribution = pkgCluster.ACK
+		for name, np := range properties.CreateClusterACK.NodePools {
+			nodePools[name] = nodePoolSpec{InstanceType: np.InstanceType, MinCount: np.MinCount, MaxCount: np.MaxCount}
+		}
+	default:
+		return "", nil
+	}
+
+	return distribution, nodePools
+}
+
+func (r clusterReconciler) Apply(ctx context.Context, scope apply.Scope, resource apply.Resource, action apply.Action) error {
+	if action == apply.ActionUpdate {
+		return r.update(ctx, scope, resource)
+	}
+
+	request := *resource.Spec.(*pkgCluster.CreateClusterRequest)
+
+	if request.SecretId == "" && len(request.SecretIds) == 0 {
+		if request.SecretName == "" {
+			return errors.New("either secretId or secretName has to be set")
+		}
+
+		request.SecretId = secret.GenerateSecretIDFromName(request.SecretName)
+	}
+
+	_, errResponse := r.api.createCluster(ctx, &request, scope.OrganizationID, scope.UserID, request.PostHooks, false)
+	if errResponse != nil {
+		if errResponse.Error != "" {
+			return errors.New(errResponse.Error)
+		}
+
+		return errors.New(errResponse.Message)
+	}
+
+	return nil
+}
+
+// update starts updating the node pools of an existing cluster.
+func (r clusterReconciler) update(ctx context.Context, scope apply.Scope, resource apply.Resource) error {
+	commonCluster, err := r.api.clusterManager.GetClusterByName(ctx, scope.OrganizationID, resource.Name)
+	if err != nil {
+		return errors.WrapIf(err, "failed to get cluster")
+	}
+
+	status, err := commonCluster.GetStatus()
+	if err != nil {
+		return errors.WrapIf(err, "failed to get cluster status")
+	}
+
+	updateRequest, err := clusterUpdateRequest(resource.Name, resource.Spec.(*pkgCluster.CreateClusterRequest), status)
+	if err != nil || updateRequest == nil {
+		return err
+	}
+
+	updateCtx := cluster.UpdateContext{
+		OrganizationID: scope.OrganizationID,
+		UserID:         scope.UserID,
+		ClusterID:      commonCluster.GetID(),
+	}
+
+	updater := cluster.NewCommonClusterUpdater(updateRequest, commonCluster, scope.UserID, r.api.workflowClient, r.api.externalBaseURL, r.api.externalBaseURLInsecure)
+
+	return errors.WrapIf(r.api.clusterManager.UpdateCluster(ctx, updateCtx, updater), "failed to update cluster")
+}
+
+func (r clusterReconciler) Delete(ctx context.Context, scope apply.Scope, ref apply.Reference) error {
+	commonCluster, err := r.api.clusterManager.GetClusterByName(ctx, scope.OrganizationID, ref.Name)
+	if intCluster.IsClusterNotFoundError(err) {
+		return nil
+	} else if err != nil {
+		return errors.WrapIf(err, "failed to get cluster")
+	}
+
+	if err := r.api.clusterGroupManager.RemoveClusterFromGroup(ctx, commonCluster.GetID()); err != nil {
+		return errors.WrapIf(err, "failed to remove cluster from its group")
+	}
+
+	return r.api.startClusterDeletion(ctx, commonCluster, false)
+}
@@ -0,0 +1,131 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/pkg/cluster/eks"
+)
+
+func TestClusterDrift(t *testing.T) {
+	status := &pkgCluster.GetClusterStatusResponse{
+		Cloud:        pkgCluster.Amazon,
+		Distribution: pkgCluster.EKS,
+		Location:     "eu-west-1",
+	}
+
+	request := &pkgCluster.CreateClusterRequest{
+		Cloud:    pkgCluster.Amazon,
+		Location: "eu-west-1",
+		Properties: &pkgCluster.CreateClusterProperties{
+			CreateClusterEKS: &eks.CreateClusterEKS{},
+		},
+	}
+
+	assert.Empty(t, clusterDrift(request, status))
+
+	request.Location = "eu-central-1"
+	status.Distribution = pkgCluster.PKE
+
+	assert.Equal(t, []string{
+		"distribution: pke != eks",
+		"location: eu-west-1 != eu-central-1",
+	}, clusterDrift(request, status))
+}
+
+func TestClusterUpdateRequest(t *testing.T) {
+	status := &pkgCluster.GetClusterStatusResponse{
+		Cloud:        pkgCluster.Amazon,
+		Distribution: pkgCluster.EKS,
+		Location:     "eu-west-1",
+		NodePools: map[string]*pkgCluster.NodePoolStatus{
+			"pool1": {InstanceType: "m5.large", Autoscaling: true, MinCount: 1, MaxCount: 3, Count: 2},
+			"pool2": {InstanceType: "m5.large", Count: 1},
+		},
+	}
+
+	newRequest := func(nodePools map[string]*eks.NodePool) *pkgCluster.CreateClusterRequest {
+		return &pkgCluster.CreateClusterRequest{
+			Cloud:    pkgCluster.Amazon,
+			Location: "eu-west-1",
+			Properties: &pkgCluster.CreateClusterProperties{
+				CreateClusterEKS: &eks.CreateClusterEKS{NodePools: nodePools},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		nodePools map[string]*eks.NodePool
+		update    bool
+	}{
+		"up to date": {
+			nodePools: map[string]*eks.NodePool{
+				"pool1": {InstanceType: "m5.large", Autoscaling: true, MinCount: 1, MaxCount: 3, Count: 1},
+				"pool2": {InstanceType: "m5.large", Count: 1},
+			},
+		},
+		"scaled": {
+			nodePools: map[string]*eks.NodePool{
+				"pool1": {InstanceType: "m5.large", Autoscaling: true, MinCount: 1, MaxCount: 3, Count: 1},
+				"pool2": {InstanceType: "m5.large", Count: 2},
+			},
+			update: true,
+		},
+		"autoscaling limits changed": {
+			nodePools: map[string]*eks.NodePool{
+				"pool1": {InstanceType: "m5.large", Autoscaling: true, MinCount: 1, MaxCount: 5, Count: 1},
+				"pool2": {InstanceType: "m5.large", Count: 1},
+			},
+			update: true,
+		},
+		"node pool added and removed": {
+			nodePools: map[string]*eks.NodePool{
+				"pool1": {InstanceType: "m5.large", Autoscaling: true, MinCount: 1, MaxCount: 3, Count: 1},
+				"pool3": {InstanceType: "m5.large", Count: 1},
+			},
+			update: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			updateRequest, err := clusterUpdateRequest("test", newRequest(test.nodePools), status)
+			require.NoError(t, err)
+
+			if !test.update {
+				assert.Nil(t, updateRequest)
+
+				return
+			}
+
+			require.NotNil(t, updateRequest)
+			assert.Equal(t, pkgCluster.Amazon, updateRequest.Cloud)
+			assert.Equal(t, test.nodePools, updateRequest.EKS.NodePools)
+		})
+	}
+
+	request := newRequest(nil)
+	request.Location = "eu-central-1"
+
+	_, err := clusterUpdateRequest("test", request, status)
+	assert.Error(t, err)
+}
@@ -0,0 +1,290 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"emperror.dev/emperror"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/audit"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/correlationid"
+	"github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// AuditAPI implements the audit event query functions.
+type AuditAPI struct {
+	store        *audit.EventStore
+	db           *gorm.DB
+	logger       logrus.FieldLogger
+	errorHandler emperror.Handler
+}
+
+// NewAuditAPI returns a new AuditAPI instance.
+func NewAuditAPI(store *audit.EventStore, db *gorm.DB, logger logrus.FieldLogger, errorHandler emperror.Handler) *AuditAPI {
+	return &AuditAPI{
+		store:        store,
+		db:           db,
+		logger:       logger,
+		errorHandler: errorHandler,
+	}
+}
+
+// AuditEventResponse describes a recorded API request.
+type AuditEventResponse struct {
+	ID            uint            `json:"id"`
+	Time          time.Time       `json:"time"`
+	CorrelationID string          `json:"correlationId"`
+	ClientIP      string          `json:"clientIp"`
+	UserAgent     string          `json:"userAgent"`
+	UserID        uint            `json:"userId"`
+	UserLogin     string          `json:"userLogin,omitempty"`
+	Method        string          `json:"method"`
+	Path          string          `json:"path"`
+	StatusCode    int             `json:"statusCode"`
+	ResponseTime  int             `json:"responseTime"`
+	ResponseSize  int             `json:"responseSize"`
+	Body          json.RawMessage `json:"body,omitempty"`
+	Headers       json.RawMessage `json:"headers,omitempty"`
+	Errors        json.RawMessage `json:"errors,omitempty"`
+}
+
+// ListAuditEventsResponse is a page of audit events.
+type ListAuditEventsResponse struct {
+	Events     []AuditEventResponse `json:"events"`
+	NextCursor string               `json:"nextCursor,omitempty"`
+}
+
+// ListEvents lists the audit events of an organization.
+// The result can be filtered by user, time range, method, path prefix, status code and correlation ID,
+// and exported as CSV with format=csv.
+func (a *AuditAPI) ListEvents(c *gin.Context) {
+	logger := correlationid.Logger(a.logger, c)
+
+	organization := auth.GetCurrentOrganization(c.Request)
+
+	query, err := parseAuditEventQuery(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, common.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Error:   err.Error(),
+		})
+		return
+	}
+	query.OrganizationID = organization.ID
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		message := fmt.Sprintf("unsupported format: %q", format)
+		c.AbortWithStatusJSON(http.StatusBadRequest, common.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: message,
+			Error:   message,
+		})
+		return
+	}
+
+	logger.WithField("organization", organization.ID).Debug("listing audit events")
+
+	result, err := a.store.ListEvents(query)
+	if err != nil {
+		message := "failed to list audit events"
+		a.errorHandler.Handle(emperror.Wrap(err, message))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, common.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: message,
+			Error:   message,
+		})
+		return
+	}
+
+	response := ListAuditEventsResponse{
+		Events: make([]AuditEventResponse, 0, len(result.Events)),
+	}
+
+	if result.NextCursor != 0 {
+		response.NextCursor = strconv.FormatUint(uint64(result.NextCursor), 10)
+		c.Header("X-Next-Cursor", response.NextCursor)
+	}
+
+	userLogins := a.getUserLogins(result.Events)
+
+	for _, event := range result.Events {
+		response.Events = append(response.Events, AuditEventResponse{
+			ID:            event.ID,
+			Time:          event.Time,
+			CorrelationID: event.CorrelationID,
+			ClientIP:      event.ClientIP,
+			UserAgent:     event.UserAgent,
+			UserID:        event.UserID,
+			UserLogin:     userLogins[event.UserID],
+			Method:        event.Method,
+			Path:          event.Path,
+			StatusCode:    event.StatusCode,
+			ResponseTime:  event.ResponseTime,
+			ResponseSize:  event.ResponseSize,
+			Body:          rawJSON(event.Body),
+			Headers:       rawJSON(&event.Headers),
+			Errors:        rawJSON(event.Errors),
+		})
+	}
+
+	if format == "csv" {
+		a.writeEventsCSV(c, response.Events)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (a *AuditAPI) getUserLogins(events []audit.AuditEvent) map[uint]string {
+	userIDs := make([]uint, 0, len(events))
+	for _, event := range events {
+		if event.UserID != 0 {
+			userIDs = append(userIDs, event.UserID)
+		}
+	}
+
+	logins := make(map[uint]string, len(userIDs))
+	if len(userIDs) == 0 {
+		return logins
+	}
+
+	var users []auth.User
+	if err := a.db.Where("id IN (?)", userIDs).Find(&users).Error; err != nil {
+		a.errorHandler.Handle(emperror.Wrap(err, "failed to fetch audit event users"))
+		return logins
+	}
+
+	for _, user := range users {
+		logins[user.ID] = user.Login
+	}
+
+	return logins
+}
+
+func (a *AuditAPI) writeEventsCSV(c *gin.Context, events []AuditEventResponse) {
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", `attachment; filename="audit_events.csv"`)
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+
+	_ = writer.Write([]string{
+		"id", "time", "correlationId", "clientIp", "userAgent", "userId", "userLogin",
+		"method", "path", "statusCode", "responseTime", "responseSize", "body", "errors",
+	})
+
+	for _, event := range events {
+		_ = writer.Write([]string{
+			strconv.FormatUint(uint64(event.ID), 10),
+			event.Time.Format(time.RFC3339),
+			event.CorrelationID,
+			event.ClientIP,
+			event.UserAgent,
+			strconv.FormatUint(uint64(event.UserID), 10),
+			event.UserLogin,
+			event.Method,
+			event.Path,
+			strconv.Itoa(event.StatusCode),
+			strconv.Itoa(event.ResponseTime),
+			strconv.Itoa(event.ResponseSize),
+			string(event.Body),
+			string(event.Errors),
+		})
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		a.errorHandler.Handle(emperror.Wrap(err, "failed to write audit events CSV"))
+	}
+}
+
+func parseAuditEventQuery(c *gin.Context) (audit.EventQuery, error) {
+	query := audit.EventQuery{
+		Method:        c.Query("method"),
+		PathPrefix:    c.Query("path"),
+		CorrelationID: c.Query("correlationId"),
+	}
+
+	if userID := c.Query("userId"); userID != "" {
+		id, err := strconv.ParseUint(userID, 10, 32)
+		if err != nil {
+			return query, fmt.Errorf("invalid user ID: %q", userID)
+		}
+		uid := uint(id)
+		query.UserID = &uid
+	}
+
+	if from := c.Query("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return query, fmt.Errorf("invalid from time (RFC3339 expected): %q", from)
+		}
+		query.From = &t
+	}
+
+	if to := c.Query("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return query, fmt.Errorf("invalid to time (RFC3339 expected): %q", to)
+		}
+		query.To = &t
+	}
+
+	if statusCode := c.Query("statusCode"); statusCode != "" {
+		code, err := strconv.Atoi(statusCode)
+		if err != nil {
+			return query, fmt.Errorf("invalid status code: %q", statusCode)
+		}
+		query.StatusCode = code
+	}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		id, err := strconv.ParseUint(cursor, 10, 32)
+		if err != nil {
+			return query, fmt.Errorf("invalid cursor: %q", cursor)
+		}
+		query.Cursor = uint(id)
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l < 1 || l > audit.MaxQueryLimit {
+			return query, fmt.Errorf("invalid limit (1-%d expected): %q", audit.MaxQueryLimit, limit)
+		}
+		query.Limit = l
+	}
+
+	return query, nil
+}
+
+func rawJSON(s *string) json.RawMessage {
+	if s == nil || *s == "" || !json.Valid([]byte(*s)) {
+		return nil
+	}
+
+	return json.RawMessage(*s)
+}
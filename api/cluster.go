@@ -36,8 +36,11 @@ import (
 	"github.com/banzaicloud/pipeline/config"
 	"github.com/banzaicloud/pipeline/internal/cloudinfo"
 	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterclone"
+	"github.com/banzaicloud/pipeline/internal/cluster/clustercost"
 	"github.com/banzaicloud/pipeline/internal/cluster/resourcesummary"
 	intClusterGroup "github.com/banzaicloud/pipeline/internal/clustergroup"
+	"github.com/banzaicloud/pipeline/internal/network/ipam"
 	"github.com/banzaicloud/pipeline/internal/providers/azure/pke/driver"
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
 	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
@@ -57,6 +60,9 @@ type ClusterAPI struct {
 	externalBaseURLInsecure bool
 	workflowClient          client.Client
 	cloudInfoClient         *cloudinfo.Client
+	costEstimator           *clustercost.Estimator
+	cidrPlanner             *ipam.Planner
+	cloneTasks              *clusterclone.Manager
 
 	logger          logrus.FieldLogger
 	errorHandler    emperror.Handler
@@ -91,13 +97,15 @@ func NewClusterAPI(
 	clusterCreators ClusterCreators,
 	clusterDeleters ClusterDeleters,
 	clusterUpdaters ClusterUpdaters,
-
+	cloneTasks *clusterclone.Manager,
 ) *ClusterAPI {
 	return &ClusterAPI{
 		clusterManager:          clusterManager,
 		clusterGetter:           clusterGetter,
 		workflowClient:          workflowClient,
 		cloudInfoClient:         cloudInfoClient,
+		costEstimator:           clustercost.NewEstimator(cloudInfoClient),
+		cidrPlanner:             ipam.NewPlanner(clusterManager, logger),
 		clusterGroupManager:     clusterGroupManager,
 		externalBaseURL:         externalBaseURL,
 		externalBaseURLInsecure: externalBaseURLInsecure,
@@ -106,6 +114,7 @@ func NewClusterAPI(
 		clusterCreators:         clusterCreators,
 		clusterDeleters:         clusterDeleters,
 		clusterUpdaters:         clusterUpdaters,
+		cloneTasks:              cloneTasks,
 	}
 }
 
@@ -0,0 +1,61 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// CreateNamespaceRequest describes a namespace to be created
+type CreateNamespaceRequest struct {
+	Name string `json:"name" binding:"required"`
+
+	NamespaceSpec
+}
+
+// Create creates a namespace in a cluster with its labels, resource quota, limit range and network policy.
+func (a *API) Create(c *gin.Context) {
+	var request CreateNamespaceRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error parsing request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := validateSpec(request.NamespaceSpec); err != nil {
+		a.replyWithError(c, err, "Invalid namespace")
+		return
+	}
+
+	client, ok := a.getClient(c)
+	if !ok {
+		return
+	}
+
+	namespace, err := createNamespace(client, request.Name, request.NamespaceSpec)
+	if err != nil {
+		a.replyWithError(c, err, "Error creating namespace")
+		return
+	}
+
+	c.JSON(http.StatusCreated, namespace)
+}
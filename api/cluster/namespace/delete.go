@@ -18,7 +18,6 @@ import (
 	"net/http"
 
 	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
-	"github.com/banzaicloud/pipeline/pkg/k8sclient"
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -27,37 +26,12 @@ import (
 
 // Delete deletes a kuberenetes namespace.
 func (a *API) Delete(c *gin.Context) {
-	cluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	client, ok := a.getClient(c)
 	if !ok {
 		return
 	}
 
-	// TODO: factor out to a common method
-	kubeConfig, err := cluster.GetK8sConfig()
-	if err != nil {
-		a.errorHandler.Handle(errors.Wrap(err, "failed to get kube config"))
-
-		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Error getting kubeconfig",
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
-	if err != nil {
-		a.errorHandler.Handle(errors.Wrap(err, "failed to get kube client"))
-
-		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Error getting kube client",
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	err = client.CoreV1().Namespaces().Delete(c.Param("namespace"), &meta_v1.DeleteOptions{})
+	err := client.CoreV1().Namespaces().Delete(c.Param("namespace"), &meta_v1.DeleteOptions{})
 	if err != nil && !k8serrors.IsNotFound(err) {
 		a.errorHandler.Handle(errors.Wrap(err, "failed to delete namespace"))
 
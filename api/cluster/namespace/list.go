@@ -0,0 +1,53 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// List lists the namespaces of a cluster.
+func (a *API) List(c *gin.Context) {
+	client, ok := a.getClient(c)
+	if !ok {
+		return
+	}
+
+	namespaces, err := listNamespaces(client)
+	if err != nil {
+		a.replyWithError(c, err, "Error listing namespaces")
+		return
+	}
+
+	c.JSON(http.StatusOK, namespaces)
+}
+
+// Get returns a namespace of a cluster.
+func (a *API) Get(c *gin.Context) {
+	client, ok := a.getClient(c)
+	if !ok {
+		return
+	}
+
+	namespace, err := getNamespace(client, c.Param("namespace"))
+	if err != nil {
+		a.replyWithError(c, err, "Error getting namespace")
+		return
+	}
+
+	c.JSON(http.StatusOK, namespace)
+}
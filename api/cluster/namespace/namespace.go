@@ -15,24 +15,108 @@
 package namespace
 
 import (
+	"net/http"
+
 	"emperror.dev/emperror"
 	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 
 	"github.com/banzaicloud/pipeline/api/common"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/banzaicloud/pipeline/pkg/k8sclient"
 )
 
 type API struct {
-	clusterGetter common.ClusterGetter
-	errorHandler  emperror.Handler
+	clusterGetter       common.ClusterGetter
+	protectedNamespaces []string
+	errorHandler        emperror.Handler
 }
 
-func NewAPI(clusterGetter common.ClusterGetter, errorHandler emperror.Handler) *API {
+// NewAPI returns a new namespace API.
+// The system namespaces (and the given Pipeline system namespace) cannot be updated through the API.
+func NewAPI(clusterGetter common.ClusterGetter, pipelineSystemNamespace string, errorHandler emperror.Handler) *API {
 	return &API{
 		clusterGetter: clusterGetter,
-		errorHandler:  errorHandler,
+		protectedNamespaces: []string{
+			meta_v1.NamespaceSystem,
+			meta_v1.NamespacePublic,
+			corev1.NamespaceNodeLease,
+			meta_v1.NamespaceDefault,
+			pipelineSystemNamespace,
+		},
+		errorHandler: errorHandler,
 	}
 }
 
 func (a *API) RegisterRoutes(r gin.IRouter) {
-	r.DELETE("", a.Delete)
+	r.GET("", a.List)
+	r.POST("", a.Create)
+	r.GET("/:namespace", a.Get)
+	r.PUT("/:namespace", a.Update)
+	r.DELETE("/:namespace", a.Delete)
+}
+
+// getClient returns a Kubernetes client for the cluster of the request
+func (a *API) getClient(c *gin.Context) (kubernetes.Interface, bool) {
+	cluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return nil, false
+	}
+
+	kubeConfig, err := cluster.GetK8sConfig()
+	if err != nil {
+		a.errorHandler.Handle(errors.Wrap(err, "failed to get kube config"))
+
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error getting kubeconfig",
+			Error:   err.Error(),
+		})
+		return nil, false
+	}
+
+	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	if err != nil {
+		a.errorHandler.Handle(errors.Wrap(err, "failed to get kube client"))
+
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error getting kube client",
+			Error:   err.Error(),
+		})
+		return nil, false
+	}
+
+	return client, true
+}
+
+// replyWithError maps namespace errors to the matching HTTP status
+func (a *API) replyWithError(c *gin.Context, err error, message string) {
+	status := http.StatusInternalServerError
+
+	cause := errors.Cause(err)
+	switch {
+	case k8serrors.IsNotFound(cause):
+		status = http.StatusNotFound
+	case k8serrors.IsAlreadyExists(cause):
+		status = http.StatusConflict
+	case k8serrors.IsInvalid(cause):
+		status = http.StatusBadRequest
+	default:
+		if _, ok := cause.(validationError); ok {
+			status = http.StatusBadRequest
+		} else {
+			a.errorHandler.Handle(err)
+		}
+	}
+
+	c.JSON(status, pkgCommon.ErrorResponse{
+		Code:    status,
+		Message: message,
+		Error:   err.Error(),
+	})
 }
@@ -0,0 +1,425 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/banzaicloud/pipeline/pkg/k8sutil"
+)
+
+// defaultObjectName is the name of the resource quota, limit range and network policy managed by Pipeline
+const defaultObjectName = "pipeline-default"
+
+const ownerLabel = "owner"
+
+// Default network policies of a namespace
+const (
+	// NetworkPolicyNone does not restrict the traffic of the namespace
+	NetworkPolicyNone = ""
+	// NetworkPolicyIsolated allows incoming traffic only from the pods of the same namespace
+	NetworkPolicyIsolated = "isolated"
+	// NetworkPolicyDenyAll denies all incoming traffic
+	NetworkPolicyDenyAll = "deny-all"
+)
+
+// LimitRange describes the default and allowed compute resources of the containers in a namespace
+type LimitRange struct {
+	DefaultRequest map[string]string `json:"defaultRequest,omitempty"`
+	Default        map[string]string `json:"default,omitempty"`
+	Min            map[string]string `json:"min,omitempty"`
+	Max            map[string]string `json:"max,omitempty"`
+}
+
+// NamespaceSpec describes the settings of a namespace managed through Pipeline
+type NamespaceSpec struct {
+	Labels        map[string]string `json:"labels,omitempty"`
+	ResourceQuota map[string]string `json:"resourceQuota,omitempty"`
+	LimitRange    *LimitRange       `json:"limitRange,omitempty"`
+	NetworkPolicy string            `json:"networkPolicy,omitempty"`
+}
+
+// Namespace describes a namespace of a cluster
+type Namespace struct {
+	Name          string            `json:"name"`
+	Status        string            `json:"status"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	ResourceQuota map[string]string `json:"resourceQuota,omitempty"`
+	ResourceUsage map[string]string `json:"resourceUsage,omitempty"`
+	LimitRange    *LimitRange       `json:"limitRange,omitempty"`
+	NetworkPolicy string            `json:"networkPolicy,omitempty"`
+}
+
+// validationError is returned when a namespace request is invalid
+type validationError struct {
+	message string
+}
+
+func (e validationError) Error() string {
+	return e.message
+}
+
+func validateSpec(spec NamespaceSpec) error {
+	if v, ok := spec.Labels[ownerLabel]; ok && v != "pipeline" {
+		return validationError{fmt.Sprintf("%q namespace label is reserved for internal use", ownerLabel)}
+	}
+
+	if _, err := toResourceList(spec.ResourceQuota); err != nil {
+		return err
+	}
+
+	if spec.LimitRange != nil {
+		if _, err := toLimitRangeItem(*spec.LimitRange); err != nil {
+			return err
+		}
+	}
+
+	switch spec.NetworkPolicy {
+	case NetworkPolicyNone, NetworkPolicyIsolated, NetworkPolicyDenyAll:
+	default:
+		return validationError{fmt.Sprintf("unknown network policy: %q", spec.NetworkPolicy)}
+	}
+
+	return nil
+}
+
+// validateProtected refuses changing the settings of system namespaces
+func validateProtected(name string, protected []string) error {
+	for _, ns := range protected {
+		if ns != "" && ns == name {
+			return validationError{fmt.Sprintf("namespace %q is managed by the system and cannot be updated", name)}
+		}
+	}
+
+	return nil
+}
+
+func listNamespaces(client kubernetes.Interface) ([]Namespace, error) {
+	namespaceList, err := client.CoreV1().Namespaces().List(meta_v1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list namespaces")
+	}
+
+	quotas, err := client.CoreV1().ResourceQuotas(meta_v1.NamespaceAll).List(meta_v1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list resource quotas")
+	}
+
+	limitRanges, err := client.CoreV1().LimitRanges(meta_v1.NamespaceAll).List(meta_v1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list limit ranges")
+	}
+
+	policies, err := client.NetworkingV1().NetworkPolicies(meta_v1.NamespaceAll).List(meta_v1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list network policies")
+	}
+
+	namespaces := make(map[string]*Namespace, len(namespaceList.Items))
+	for _, ns := range namespaceList.Items {
+		namespaces[ns.Name] = fromNamespace(ns)
+	}
+
+	for _, quota := range quotas.Items {
+		if ns, ok := namespaces[quota.Namespace]; ok && quota.Name == defaultObjectName {
+			ns.setResourceQuota(quota)
+		}
+	}
+
+	for _, limitRange := range limitRanges.Items {
+		if ns, ok := namespaces[limitRange.Namespace]; ok && limitRange.Name == defaultObjectName {
+			ns.setLimitRange(limitRange)
+		}
+	}
+
+	for _, policy := range policies.Items {
+		if ns, ok := namespaces[policy.Namespace]; ok && policy.Name == defaultObjectName {
+			ns.setNetworkPolicy(policy)
+		}
+	}
+
+	result := make([]Namespace, 0, len(namespaces))
+	for _, ns := range namespaces {
+		result = append(result, *ns)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+
+	return result, nil
+}
+
+func getNamespace(client kubernetes.Interface, name string) (*Namespace, error) {
+	namespace, err := client.CoreV1().Namespaces().Get(name, meta_v1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get namespace")
+	}
+
+	ns := fromNamespace(*namespace)
+
+	quota, err := client.CoreV1().ResourceQuotas(name).Get(defaultObjectName, meta_v1.GetOptions{})
+	if err == nil {
+		ns.setResourceQuota(*quota)
+	} else if !k8serrors.IsNotFound(err) {
+		return nil, errors.Wrap(err, "failed to get resource quota")
+	}
+
+	limitRange, err := client.CoreV1().LimitRanges(name).Get(defaultObjectName, meta_v1.GetOptions{})
+	if err == nil {
+		ns.setLimitRange(*limitRange)
+	} else if !k8serrors.IsNotFound(err) {
+		return nil, errors.Wrap(err, "failed to get limit range")
+	}
+
+	policy, err := client.NetworkingV1().NetworkPolicies(name).Get(defaultObjectName, meta_v1.GetOptions{})
+	if err == nil {
+		ns.setNetworkPolicy(*policy)
+	} else if !k8serrors.IsNotFound(err) {
+		return nil, errors.Wrap(err, "failed to get network policy")
+	}
+
+	return ns, nil
+}
+
+func createNamespace(client kubernetes.Interface, name string, spec NamespaceSpec) (*Namespace, error) {
+	_, err := client.CoreV1().Namespaces().Get(name, meta_v1.GetOptions{})
+	if err == nil {
+		return nil, k8serrors.NewAlreadyExists(schema.GroupResource{Resource: "namespaces"}, name)
+	} else if !k8serrors.IsNotFound(err) {
+		return nil, errors.Wrap(err, "failed to get namespace")
+	}
+
+	if err := k8sutil.EnsureNamespaceWithLabel(client, name, spec.Labels); err != nil {
+		return nil, err
+	}
+
+	if err := applySpec(client, name, spec); err != nil {
+		return nil, err
+	}
+
+	return getNamespace(client, name)
+}
+
+// updateNamespace merges the given labels into the labels of the namespace (removing the ones with a nil value)
+// and replaces the rest of its settings.
+func updateNamespace(client kubernetes.Interface, name string, labels map[string]*string, spec NamespaceSpec) (*Namespace, error) {
+	namespace, err := client.CoreV1().Namespaces().Get(name, meta_v1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get namespace")
+	}
+
+	mergedLabels := make(map[string]string, len(namespace.Labels)+len(labels))
+	for k, v := range namespace.Labels {
+		mergedLabels[k] = v
+	}
+	for k, v := range labels {
+		if k == ownerLabel {
+			continue
+		}
+
+		if v == nil {
+			delete(mergedLabels, k)
+		} else {
+			mergedLabels[k] = *v
+		}
+	}
+	namespace.Labels = mergedLabels
+
+	if _, err := client.CoreV1().Namespaces().Update(namespace); err != nil {
+		return nil, errors.Wrap(err, "failed to update namespace")
+	}
+
+	if err := applySpec(client, name, spec); err != nil {
+		return nil, err
+	}
+
+	return getNamespace(client, name)
+}
+
+// applySpec creates, updates or deletes the resource quota, limit range and network policy of a namespace
+func applySpec(client kubernetes.Interface, namespace string, spec NamespaceSpec) error {
+	meta := meta_v1.ObjectMeta{
+		Name:      defaultObjectName,
+		Namespace: namespace,
+	}
+
+	quotas := client.CoreV1().ResourceQuotas(namespace)
+	if len(spec.ResourceQuota) > 0 {
+		hard, err := toResourceList(spec.ResourceQuota)
+		if err != nil {
+			return err
+		}
+
+		quota := &corev1.ResourceQuota{ObjectMeta: meta, Spec: corev1.ResourceQuotaSpec{Hard: hard}}
+		if current, err := quotas.Get(defaultObjectName, meta_v1.GetOptions{}); err == nil {
+			current.Spec = quota.Spec
+			_, err = quotas.Update(current)
+			if err != nil {
+				return errors.Wrap(err, "failed to update resource quota")
+			}
+		} else if _, err := quotas.Create(quota); err != nil {
+			return errors.Wrap(err, "failed to create resource quota")
+		}
+	} else if err := quotas.Delete(defaultObjectName, &meta_v1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to delete resource quota")
+	}
+
+	limitRanges := client.CoreV1().LimitRanges(namespace)
+	if spec.LimitRange != nil {
+		item, err := toLimitRangeItem(*spec.LimitRange)
+		if err != nil {
+			return err
+		}
+
+		limitRange := &corev1.LimitRange{ObjectMeta: meta, Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{item}}}
+		if current, err := limitRanges.Get(defaultObjectName, meta_v1.GetOptions{}); err == nil {
+			current.Spec = limitRange.Spec
+			_, err = limitRanges.Update(current)
+			if err != nil {
+				return errors.Wrap(err, "failed to update limit range")
+			}
+		} else if _, err := limitRanges.Create(limitRange); err != nil {
+			return errors.Wrap(err, "failed to create limit range")
+		}
+	} else if err := limitRanges.Delete(defaultObjectName, &meta_v1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to delete limit range")
+	}
+
+	policies := client.NetworkingV1().NetworkPolicies(namespace)
+	if spec.NetworkPolicy != NetworkPolicyNone {
+		policy := &networkingv1.NetworkPolicy{ObjectMeta: meta, Spec: networkPolicySpec(spec.NetworkPolicy)}
+		if current, err := policies.Get(defaultObjectName, meta_v1.GetOptions{}); err == nil {
+			current.Spec = policy.Spec
+			_, err = policies.Update(current)
+			if err != nil {
+				return errors.Wrap(err, "failed to update network policy")
+			}
+		} else if _, err := policies.Create(policy); err != nil {
+			return errors.Wrap(err, "failed to create network policy")
+		}
+	} else if err := policies.Delete(defaultObjectName, &meta_v1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to delete network policy")
+	}
+
+	return nil
+}
+
+func networkPolicySpec(policy string) networkingv1.NetworkPolicySpec {
+	spec := networkingv1.NetworkPolicySpec{
+		PodSelector: meta_v1.LabelSelector{},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+	}
+
+	if policy == NetworkPolicyIsolated {
+		spec.Ingress = []networkingv1.NetworkPolicyIngressRule{
+			{From: []networkingv1.NetworkPolicyPeer{{PodSelector: &meta_v1.LabelSelector{}}}},
+		}
+	}
+
+	return spec
+}
+
+func fromNamespace(namespace corev1.Namespace) *Namespace {
+	return &Namespace{
+		Name:   namespace.Name,
+		Status: string(namespace.Status.Phase),
+		Labels: namespace.Labels,
+	}
+}
+
+func (ns *Namespace) setResourceQuota(quota corev1.ResourceQuota) {
+	ns.ResourceQuota = fromResourceList(quota.Spec.Hard)
+	ns.ResourceUsage = fromResourceList(quota.Status.Used)
+}
+
+func (ns *Namespace) setLimitRange(limitRange corev1.LimitRange) {
+	for _, item := range limitRange.Spec.Limits {
+		if item.Type == corev1.LimitTypeContainer {
+			ns.LimitRange = &LimitRange{
+				DefaultRequest: fromResourceList(item.DefaultRequest),
+				Default:        fromResourceList(item.Default),
+				Min:            fromResourceList(item.Min),
+				Max:            fromResourceList(item.Max),
+			}
+		}
+	}
+}
+
+func (ns *Namespace) setNetworkPolicy(policy networkingv1.NetworkPolicy) {
+	if len(policy.Spec.Ingress) == 0 {
+		ns.NetworkPolicy = NetworkPolicyDenyAll
+	} else {
+		ns.NetworkPolicy = NetworkPolicyIsolated
+	}
+}
+
+func toLimitRangeItem(limitRange LimitRange) (corev1.LimitRangeItem, error) {
+	item := corev1.LimitRangeItem{Type: corev1.LimitTypeContainer}
+
+	var err error
+	if item.DefaultRequest, err = toResourceList(limitRange.DefaultRequest); err != nil {
+		return item, err
+	}
+	if item.Default, err = toResourceList(limitRange.Default); err != nil {
+		return item, err
+	}
+	if item.Min, err = toResourceList(limitRange.Min); err != nil {
+		return item, err
+	}
+	if item.Max, err = toResourceList(limitRange.Max); err != nil {
+		return item, err
+	}
+
+	return item, nil
+}
+
+func toResourceList(resources map[string]string) (corev1.ResourceList, error) {
+	if len(resources) == 0 {
+		return nil, nil
+	}
+
+	list := make(corev1.ResourceList, len(resources))
+	for name, value := range resources {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, validationError{fmt.Sprintf("invalid quantity for %s: %q", name, value)}
+		}
+
+		list[corev1.ResourceName(name)] = quantity
+	}
+
+	return list, nil
+}
+
+func fromResourceList(list corev1.ResourceList) map[string]string {
+	if len(list) == 0 {
+		return nil
+	}
+
+	resources := make(map[string]string, len(list))
+	for name, quantity := range list {
+		resources[string(name)] = quantity.String()
+	}
+
+	return resources
+}
@@ -0,0 +1,120 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestValidateSpec(t *testing.T) {
+	tests := map[string]NamespaceSpec{
+		"owner label":    {Labels: map[string]string{"owner": "team"}},
+		"invalid quota":  {ResourceQuota: map[string]string{"cpu": "a lot"}},
+		"invalid limit":  {LimitRange: &LimitRange{Max: map[string]string{"memory": "1X"}}},
+		"unknown policy": {NetworkPolicy: "allow-some"},
+	}
+
+	for name, spec := range tests {
+		err := validateSpec(spec)
+
+		_, ok := errors.Cause(err).(validationError)
+		assert.True(t, ok, name)
+	}
+
+	assert.NoError(t, validateSpec(NamespaceSpec{
+		Labels:        map[string]string{"team": "a"},
+		ResourceQuota: map[string]string{"requests.cpu": "4", "requests.memory": "8Gi"},
+		LimitRange:    &LimitRange{DefaultRequest: map[string]string{"cpu": "100m"}},
+		NetworkPolicy: NetworkPolicyIsolated,
+	}))
+}
+
+func TestNamespaceLifecycle(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "default"},
+	})
+
+	spec := NamespaceSpec{
+		Labels:        map[string]string{"team": "a"},
+		ResourceQuota: map[string]string{"requests.cpu": "4"},
+		LimitRange:    &LimitRange{Default: map[string]string{"memory": "512Mi"}},
+		NetworkPolicy: NetworkPolicyIsolated,
+	}
+
+	namespace, err := createNamespace(client, "team-a", spec)
+	require.NoError(t, err)
+
+	assert.Equal(t, &Namespace{
+		Name:          "team-a",
+		Labels:        map[string]string{"team": "a", "owner": "pipeline"},
+		ResourceQuota: map[string]string{"requests.cpu": "4"},
+		LimitRange:    &LimitRange{Default: map[string]string{"memory": "512Mi"}},
+		NetworkPolicy: NetworkPolicyIsolated,
+	}, namespace)
+
+	_, err = createNamespace(client, "team-a", spec)
+	assert.True(t, k8serrors.IsAlreadyExists(errors.Cause(err)))
+
+	env := "dev"
+	namespace, err = updateNamespace(client, "team-a", map[string]*string{"env": &env, "owner": nil}, NamespaceSpec{
+		NetworkPolicy: NetworkPolicyDenyAll,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, &Namespace{
+		Name:          "team-a",
+		Labels:        map[string]string{"team": "a", "env": "dev", "owner": "pipeline"},
+		NetworkPolicy: NetworkPolicyDenyAll,
+	}, namespace)
+
+	namespace, err = updateNamespace(client, "team-a", map[string]*string{"team": nil}, NamespaceSpec{
+		NetworkPolicy: NetworkPolicyDenyAll,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, &Namespace{
+		Name:          "team-a",
+		Labels:        map[string]string{"env": "dev", "owner": "pipeline"},
+		NetworkPolicy: NetworkPolicyDenyAll,
+	}, namespace)
+
+	namespaces, err := listNamespaces(client)
+	require.NoError(t, err)
+	require.Len(t, namespaces, 2)
+	assert.Equal(t, "default", namespaces[0].Name)
+	assert.Equal(t, *namespace, namespaces[1])
+
+	_, err = updateNamespace(client, "team-c", nil, spec)
+	assert.True(t, k8serrors.IsNotFound(errors.Cause(err)))
+}
+
+func TestValidateProtected(t *testing.T) {
+	protected := NewAPI(nil, "pipeline-system", nil).protectedNamespaces
+
+	for _, name := range []string{"kube-system", "kube-public", "kube-node-lease", "default", "pipeline-system"} {
+		_, ok := errors.Cause(validateProtected(name, protected)).(validationError)
+		assert.True(t, ok, name)
+	}
+
+	assert.NoError(t, validateProtected("team-a", protected))
+}
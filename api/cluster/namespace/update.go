@@ -0,0 +1,68 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// UpdateNamespaceRequest describes the new settings of a namespace.
+// Labels are merged into the existing ones, omitted resource quota, limit range and network policy are removed from the namespace.
+type UpdateNamespaceRequest struct {
+	NamespaceSpec
+
+	// Labels with a null value are removed from the namespace
+	Labels map[string]*string `json:"labels,omitempty"`
+}
+
+// Update updates the labels and replaces the resource quota, limit range and network policy of a namespace.
+func (a *API) Update(c *gin.Context) {
+	var request UpdateNamespaceRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error parsing request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := validateSpec(request.NamespaceSpec); err != nil {
+		a.replyWithError(c, err, "Invalid namespace")
+		return
+	}
+
+	if err := validateProtected(c.Param("namespace"), a.protectedNamespaces); err != nil {
+		a.replyWithError(c, err, "Invalid namespace")
+		return
+	}
+
+	client, ok := a.getClient(c)
+	if !ok {
+		return
+	}
+
+	namespace, err := updateNamespace(client, c.Param("namespace"), request.Labels, request.NamespaceSpec)
+	if err != nil {
+		a.replyWithError(c, err, "Error updating namespace")
+		return
+	}
+
+	c.JSON(http.StatusOK, namespace)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/banzaicloud/pipeline/internal/providers/azure/pke"
 	"github.com/banzaicloud/pipeline/internal/providers/azure/pke/driver"
 	"github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
 )
 
 const PKEOnAzure = pke.PKEOnAzure
@@ -99,6 +100,7 @@ func requestToClusterNodepools(request []client.PkeOnAzureNodePool, userID uint)
 			Zones:       node.Zones,
 			Roles:       node.Roles,
 			Labels:      node.Labels,
+			Taints:      requestToNodePoolTaints(node.Taints),
 			Autoscaling: node.Autoscaling,
 			Count:       int(node.Count),
 			Min:         int(node.MinCount),
@@ -107,3 +109,19 @@ func requestToClusterNodepools(request []client.PkeOnAzureNodePool, userID uint)
 	}
 	return nodepools
 }
+
+func requestToNodePoolTaints(request []client.NodePoolTaint) pkgCommon.NodePoolTaints {
+	if len(request) == 0 {
+		return nil
+	}
+
+	taints := make(pkgCommon.NodePoolTaints, len(request))
+	for i, taint := range request {
+		taints[i] = pkgCommon.NodePoolTaint{
+			Key:    taint.Key,
+			Value:  taint.Value,
+			Effect: taint.Effect,
+		}
+	}
+	return taints
+}
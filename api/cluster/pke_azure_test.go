@@ -22,6 +22,7 @@ import (
 	"github.com/banzaicloud/pipeline/internal/pke"
 	"github.com/banzaicloud/pipeline/internal/providers/azure/pke/driver"
 	"github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/pkg/common"
 	"gotest.tools/assert"
 )
 
@@ -49,6 +50,7 @@ func TestToAzurePKEClusterCreationParams(t *testing.T) {
 			Labels:       nil,
 			Name:         "nodepool1",
 			Roles:        []string{"role"},
+			Taints:       []client.NodePoolTaint{{Key: "dedicated", Value: "gpu", Effect: "NoSchedule"}},
 			Subnet:       Azuresubnet,
 			Zones:        []string{"zone"},
 			InstanceType: Instancetype,
@@ -152,6 +154,7 @@ func TestToAzurePKEClusterCreationParams(t *testing.T) {
 						Zones:       Nodepool.Zones,
 						Roles:       Nodepool.Roles,
 						Labels:      Nodepool.Labels,
+						Taints:      common.NodePoolTaints{{Key: "dedicated", Value: "gpu", Effect: common.TaintEffectNoSchedule}},
 						Autoscaling: Nodepool.Autoscaling,
 						Count:       int(Nodepool.Count),
 						Min:         int(Nodepool.MinCount),
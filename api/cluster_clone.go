@@ -0,0 +1,137 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"emperror.dev/emperror"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/cluster"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/correlationid"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+// CloneClusterRequest describes the settings of a cloned cluster which differ from the ones of the source cluster.
+type CloneClusterRequest struct {
+	Name       string `json:"name" binding:"required"`
+	Location   string `json:"location,omitempty"`
+	SecretID   string `json:"secretId,omitempty"`
+	SecretName string `json:"secretName,omitempty"`
+
+	// PostHooks replace the post hooks reconstructed from the source cluster.
+	PostHooks pkgCluster.PostHooks `json:"postHooks,omitempty"`
+
+	// CopyReleases installs the Helm releases of the source cluster on the new one once it is created.
+	CopyReleases bool `json:"copyReleases,omitempty"`
+
+	// CopyFeatures activates the active features of the source cluster on the new one once it is created.
+	CopyFeatures bool `json:"copyFeatures,omitempty"`
+}
+
+// CloneCluster creates a new cluster with the settings of an existing one.
+func (a *ClusterAPI) CloneCluster(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+	logger := correlationid.Logger(a.logger, c)
+
+	source, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	var request CloneClusterRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "error parsing request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	orgID := auth.GetCurrentOrganization(c.Request).ID
+	userID := auth.GetCurrentUser(c.Request).ID
+
+	logger = logger.WithFields(logrus.Fields{
+		"organization":  orgID,
+		"sourceCluster": source.GetID(),
+		"cluster":       request.Name,
+	})
+
+	secretID := request.SecretID
+	if secretID == "" && request.SecretName != "" {
+		secretID = secret.GenerateSecretIDFromName(request.SecretName)
+	}
+
+	var nodePoolLabels map[string]map[string]string
+	if status, err := source.GetStatus(); err == nil && (status.Status == pkgCluster.Running || status.Status == pkgCluster.Warning) {
+		nodePoolLabels, err = cluster.GetNodePoolUserLabels(source)
+		if err != nil {
+			logger.WithError(err).Warn("failed to get node pool labels of the source cluster, cloning without labels")
+		}
+	}
+
+	createClusterRequest, err := cluster.NewCloneRequest(source, cluster.CloneOptions{
+		Name:           request.Name,
+		Location:       request.Location,
+		SecretID:       secretID,
+		PostHooks:      request.PostHooks,
+		NodePoolLabels: nodePoolLabels,
+	})
+	if isInvalid(err) {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Error:   err.Error(),
+		})
+		return
+	} else if err != nil {
+		err = emperror.Wrap(err, "failed to reconstruct create request of the source cluster")
+		a.errorHandler.Handle(err)
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	logger.Info("cloning cluster")
+
+	allowCIDROverlap, _ := strconv.ParseBool(c.Query("allowCIDROverlap"))
+
+	commonCluster, errResponse := a.createCluster(ctx, createClusterRequest, orgID, userID, createClusterRequest.PostHooks, allowCIDROverlap)
+	if errResponse != nil {
+		c.JSON(errResponse.Code, errResponse)
+		return
+	}
+
+	if request.CopyReleases || request.CopyFeatures {
+		_, err := a.cloneTasks.CreateTask(source.GetID(), commonCluster.GetID(), request.CopyReleases, request.CopyFeatures, userID)
+		if err != nil {
+			// the cluster is already being created, so the request is not failed
+			a.errorHandler.Handle(emperror.WrapWith(err, "failed to save clone task", "clusterID", commonCluster.GetID()))
+		}
+	}
+
+	c.JSON(http.StatusAccepted, pkgCluster.CreateClusterResponse{
+		Name:       commonCluster.GetName(),
+		ResourceID: commonCluster.GetID(),
+	})
+}
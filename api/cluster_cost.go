@@ -0,0 +1,77 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+
+	"emperror.dev/emperror"
+	"github.com/gin-gonic/gin"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/clustercost"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	"github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// ClusterCostResponse describes the running cost of a cluster.
+type ClusterCostResponse struct {
+	ClusterID    uint   `json:"clusterId"`
+	ClusterName  string `json:"clusterName"`
+	Cloud        string `json:"cloud"`
+	Distribution string `json:"distribution"`
+	Region       string `json:"region"`
+
+	clustercost.Cost
+}
+
+// CreateClusterDryRunResponse describes a cluster which would be created by the request.
+type CreateClusterDryRunResponse struct {
+	Name         string            `json:"name"`
+	Cloud        string            `json:"cloud"`
+	Distribution string            `json:"distribution"`
+	Region       string            `json:"region"`
+	Cost         *clustercost.Cost `json:"cost"`
+}
+
+// GetClusterCost returns the hourly and monthly cost of the node pools of a cluster.
+func (a *ClusterAPI) GetClusterCost(c *gin.Context) {
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	clusterStatus, err := commonCluster.GetStatus()
+	if err != nil {
+		a.errorHandler.Handle(emperror.WrapWith(err, "failed to get cluster status", "clusterId", commonCluster.GetID()))
+
+		ginutils.ReplyWithErrorResponse(c, &common.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error getting cluster",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	cost := a.costEstimator.Estimate(clusterStatus, commonCluster.GetScaleOptions())
+
+	c.JSON(http.StatusOK, ClusterCostResponse{
+		ClusterID:    commonCluster.GetID(),
+		ClusterName:  clusterStatus.Name,
+		Cloud:        clusterStatus.Cloud,
+		Distribution: clusterStatus.Distribution,
+		Region:       clusterStatus.Region,
+		Cost:         *cost,
+	})
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/mitchellh/mapstructure"
 
@@ -31,6 +32,7 @@ import (
 	"github.com/banzaicloud/pipeline/client"
 	"github.com/banzaicloud/pipeline/cluster"
 	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/network/ipam"
 	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
 	"github.com/banzaicloud/pipeline/model/defaults"
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
@@ -120,7 +122,14 @@ func (a *ClusterAPI) CreateCluster(c *gin.Context) {
 			createClusterRequest.SecretId = secret.GenerateSecretIDFromName(createClusterRequest.SecretName)
 		}
 
-		commonCluster, err := a.createCluster(ctx, &createClusterRequest, orgID, userID, createClusterRequest.PostHooks)
+		if dryRun, _ := strconv.ParseBool(c.Query("dryRun")); dryRun {
+			a.estimateCluster(c, &createClusterRequest, orgID, userID)
+			return
+		}
+
+		allowCIDROverlap, _ := strconv.ParseBool(c.Query("allowCIDROverlap"))
+
+		commonCluster, err := a.createCluster(ctx, &createClusterRequest, orgID, userID, createClusterRequest.PostHooks, allowCIDROverlap)
 		if err != nil {
 			c.JSON(err.Code, err)
 			return
@@ -152,6 +161,14 @@ func (a *ClusterAPI) CreateCluster(c *gin.Context) {
 		}
 	}
 
+	if dryRun, _ := strconv.ParseBool(c.Query("dryRun")); dryRun {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("dry run is not supported for cluster type: %s", createClusterRequestBase.Type),
+		})
+		return
+	}
+
 	var cluster intCluster.Cluster
 
 	switch createClusterRequestBase.Type {
@@ -184,6 +201,18 @@ func (a *ClusterAPI) CreateCluster(c *gin.Context) {
 				}
 			}
 		}
+		if allowCIDROverlap, _ := strconv.ParseBool(c.Query("allowCIDROverlap")); !allowCIDROverlap {
+			requested := ipam.RequestedPKEOnAzureAllocations(
+				req.Name,
+				req.Network.Cidr,
+				req.Kubernetes.Network.PodCIDR,
+				req.Kubernetes.Network.ServiceCIDR,
+			)
+			if errResponse := a.checkCIDROverlaps(ctx, orgID, requested); errResponse != nil {
+				ginutils.ReplyWithErrorResponse(c, errResponse)
+				return
+			}
+		}
 		params := req.ToAzurePKEClusterCreationParams(orgID, userID)
 		azurePKECluster, err := a.clusterCreators.PKEOnAzure.Create(ctx, params)
 		if err = emperror.Wrap(err, "failed to create cluster from request"); err != nil {
@@ -205,6 +234,41 @@ func (a *ClusterAPI) CreateCluster(c *gin.Context) {
 	})
 }
 
+// estimateCluster returns the cost of the cluster described by the request without creating it.
+func (a *ClusterAPI) estimateCluster(
+	c *gin.Context,
+	createClusterRequest *pkgCluster.CreateClusterRequest,
+	organizationID uint,
+	userID uint,
+) {
+	logger := a.logger.WithFields(logrus.Fields{
+		"organization": organizationID,
+		"user":         userID,
+		"cluster":      createClusterRequest.Name,
+	})
+
+	commonCluster, createClusterRequest, errResponse := a.newCommonCluster(logger, createClusterRequest, organizationID, userID)
+	if errResponse != nil {
+		c.JSON(errResponse.Code, errResponse)
+		return
+	}
+
+	clusterStatus, err := commonCluster.GetStatus()
+	if err != nil {
+		a.errorHandler.Handle(emperror.Wrap(err, "failed to get status of cluster to be created"))
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, CreateClusterDryRunResponse{
+		Name:         clusterStatus.Name,
+		Cloud:        clusterStatus.Cloud,
+		Distribution: clusterStatus.Distribution,
+		Region:       clusterStatus.Region,
+		Cost:         a.costEstimator.Estimate(clusterStatus, createClusterRequest.ScaleOptions),
+	})
+}
+
 // createCluster creates a K8S cluster in the cloud.
 func (a *ClusterAPI) createCluster(
 	ctx context.Context,
@@ -212,6 +276,7 @@ func (a *ClusterAPI) createCluster(
 	organizationID uint,
 	userID uint,
 	postHooks pkgCluster.PostHooks,
+	allowCIDROverlap bool,
 ) (cluster.CommonCluster, *pkgCommon.ErrorResponse) {
 	logger := a.logger.WithFields(logrus.Fields{
 		"organization": organizationID,
@@ -219,6 +284,85 @@ func (a *ClusterAPI) createCluster(
 		"cluster":      createClusterRequest.Name,
 	})
 
+	commonCluster, createClusterRequest, errResponse := a.newCommonCluster(logger, createClusterRequest, organizationID, userID)
+	if errResponse != nil {
+		return nil, errResponse
+	}
+
+	if !allowCIDROverlap {
+		if errResponse := a.checkCIDROverlaps(ctx, organizationID, ipam.RequestedAllocations(createClusterRequest)); errResponse != nil {
+			return nil, errResponse
+		}
+	}
+
+	creationCtx := cluster.CreationContext{
+		OrganizationID:          organizationID,
+		UserID:                  userID,
+		Name:                    createClusterRequest.Name,
+		SecretID:                createClusterRequest.SecretId,
+		SecretIDs:               createClusterRequest.SecretIds,
+		Provider:                createClusterRequest.Cloud,
+		PostHooks:               postHooks,
+		ExternalBaseURL:         a.externalBaseURL,
+		ExternalBaseURLInsecure: a.externalBaseURLInsecure,
+	}
+
+	switch c := commonCluster.(type) {
+	case *cluster.EKSCluster:
+		c.CloudInfoClient = a.cloudInfoClient
+	}
+
+	creator := cluster.NewClusterCreator(createClusterRequest, commonCluster, a.workflowClient)
+
+	commonCluster, err := a.clusterManager.CreateCluster(ctx, creationCtx, creator)
+
+	if err == cluster.ErrAlreadyExists || isInvalid(err) {
+		logger.Debugf("invalid cluster creation: %s", err.Error())
+
+		return nil, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Error:   err.Error(),
+		}
+	} else if err != nil {
+		logger.Errorf("error during cluster creation: %s", err.Error())
+
+		return nil, &pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+			Error:   err.Error(),
+		}
+	}
+
+	return commonCluster, nil
+}
+
+// checkCIDROverlaps checks that the requested address ranges do not overlap with the ones of the other clusters of the organization.
+func (a *ClusterAPI) checkCIDROverlaps(ctx context.Context, organizationID uint, requested []ipam.Allocation) *pkgCommon.ErrorResponse {
+	err := a.cidrPlanner.CheckOverlaps(ctx, organizationID, requested)
+
+	if overlapErr, ok := errors.Cause(err).(ipam.OverlapError); ok {
+		return cidrOverlapErrorResponse(overlapErr)
+	} else if err != nil {
+		a.errorHandler.Handle(err)
+
+		return &pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "failed to check the address ranges of the cluster",
+			Error:   err.Error(),
+		}
+	}
+
+	return nil
+}
+
+// newCommonCluster fills the request from the selected profile and creates the (not yet persisted) cluster from it.
+func (a *ClusterAPI) newCommonCluster(
+	logger logrus.FieldLogger,
+	createClusterRequest *pkgCluster.CreateClusterRequest,
+	organizationID uint,
+	userID uint,
+) (cluster.CommonCluster, *pkgCluster.CreateClusterRequest, *pkgCommon.ErrorResponse) {
 	// TODO: refactor profile handling as well?
 	if len(createClusterRequest.ProfileName) != 0 {
 		logger = logger.WithField("profile", createClusterRequest.ProfileName)
@@ -236,7 +380,7 @@ func (a *ClusterAPI) createCluster(
 		case pkgCluster.Oracle:
 			distribution = pkgCluster.OKE
 		default:
-			return nil, &pkgCommon.ErrorResponse{
+			return nil, nil, &pkgCommon.ErrorResponse{
 				Code:    http.StatusBadRequest,
 				Message: "unsupported cloud type",
 				Error:   "unsupported cloud type",
@@ -245,7 +389,7 @@ func (a *ClusterAPI) createCluster(
 
 		profile, err := defaults.GetProfile(distribution, createClusterRequest.ProfileName)
 		if err != nil {
-			return nil, &pkgCommon.ErrorResponse{
+			return nil, nil, &pkgCommon.ErrorResponse{
 				Code:    http.StatusNotFound,
 				Message: "error during getting profile",
 				Error:   err.Error(),
@@ -260,7 +404,7 @@ func (a *ClusterAPI) createCluster(
 		if err != nil {
 			logger.Errorf("error during getting cluster request from profile: %s", err.Error())
 
-			return nil, &pkgCommon.ErrorResponse{
+			return nil, nil, &pkgCommon.ErrorResponse{
 				Code:    http.StatusBadRequest,
 				Message: "Error creating request from profile",
 				Error:   err.Error(),
@@ -284,51 +428,12 @@ func (a *ClusterAPI) createCluster(
 	commonCluster, err := cluster.CreateCommonClusterFromRequest(createClusterRequest, organizationID, userID)
 	if err != nil {
 		log.Errorf("error during create common cluster from request: %s", err.Error())
-		return nil, &pkgCommon.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: err.Error(),
-			Error:   err.Error(),
-		}
-	}
-
-	creationCtx := cluster.CreationContext{
-		OrganizationID:          organizationID,
-		UserID:                  userID,
-		Name:                    createClusterRequest.Name,
-		SecretID:                createClusterRequest.SecretId,
-		SecretIDs:               createClusterRequest.SecretIds,
-		Provider:                createClusterRequest.Cloud,
-		PostHooks:               postHooks,
-		ExternalBaseURL:         a.externalBaseURL,
-		ExternalBaseURLInsecure: a.externalBaseURLInsecure,
-	}
-
-	switch c := commonCluster.(type) {
-	case *cluster.EKSCluster:
-		c.CloudInfoClient = a.cloudInfoClient
-	}
-
-	creator := cluster.NewClusterCreator(createClusterRequest, commonCluster, a.workflowClient)
-
-	commonCluster, err = a.clusterManager.CreateCluster(ctx, creationCtx, creator)
-
-	if err == cluster.ErrAlreadyExists || isInvalid(err) {
-		logger.Debugf("invalid cluster creation: %s", err.Error())
-
-		return nil, &pkgCommon.ErrorResponse{
+		return nil, nil, &pkgCommon.ErrorResponse{
 			Code:    http.StatusBadRequest,
 			Message: err.Error(),
 			Error:   err.Error(),
 		}
-	} else if err != nil {
-		logger.Errorf("error during cluster creation: %s", err.Error())
-
-		return nil, &pkgCommon.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: err.Error(),
-			Error:   err.Error(),
-		}
 	}
 
-	return commonCluster, nil
+	return commonCluster, createClusterRequest, nil
 }
@@ -15,9 +15,11 @@
 package api
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 
+	"github.com/banzaicloud/pipeline/cluster"
 	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
 	anchore "github.com/banzaicloud/pipeline/internal/security"
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
@@ -58,11 +60,24 @@ func (a *ClusterAPI) DeleteCluster(c *gin.Context) {
 		return
 	}
 
+	if err := a.startClusterDeletion(ctx, commonCluster, force); err != nil {
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, DeleteClusterResponse{
+		Status:     http.StatusAccepted,
+		Name:       clusterName,
+		ResourceID: clusterID,
+	})
+}
+
+// startClusterDeletion starts deleting a cluster with the deleter of its distribution.
+func (a *ClusterAPI) startClusterDeletion(ctx context.Context, commonCluster cluster.CommonCluster, force bool) error {
 	switch {
 	case commonCluster.GetDistribution() == pkgCluster.PKE && commonCluster.GetCloud() == pkgCluster.Azure:
 		if err := a.clusterDeleters.PKEOnAzure.DeleteByID(ctx, commonCluster.GetID(), force); err != nil {
-			pkgCommon.ErrorResponseWithStatus(c, http.StatusInternalServerError, err)
-			return
+			return err
 		}
 	default:
 		_ = a.clusterManager.DeleteCluster(ctx, commonCluster, force)
@@ -72,9 +87,5 @@ func (a *ClusterAPI) DeleteCluster(c *gin.Context) {
 		anchore.RemoveAnchoreUser(commonCluster.GetOrganizationId(), commonCluster.GetUID())
 	}
 
-	c.JSON(http.StatusAccepted, DeleteClusterResponse{
-		Status:     http.StatusAccepted,
-		Name:       clusterName,
-		ResourceID: clusterID,
-	})
+	return nil
 }
@@ -114,6 +114,7 @@ func (a *ClusterAPI) GetCluster(c *gin.Context) {
 			Image:        nodePool.Image,
 			Version:      nodePool.Version,
 			Labels:       nodePool.Labels,
+			Taints:       nodePool.Taints,
 
 			CreatedAt:   nodePool.CreatedAt,
 			CreatorName: nodePool.CreatorName,
@@ -283,6 +284,7 @@ type GetClusterNodePool struct {
 	Version         string                         `json:"version,omitempty"`
 	ResourceSummary map[string]NodeResourceSummary `json:"resourceSummary,omitempty"`
 	Labels          map[string]string              `json:"labels,omitempty"`
+	Taints          []common.NodePoolTaint         `json:"taints,omitempty"`
 
 	CreatedAt   time.Time `json:"createdAt,omitempty"`
 	CreatorName string    `json:"creatorName,omitempty"`
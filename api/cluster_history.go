@@ -0,0 +1,95 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"emperror.dev/emperror"
+	"github.com/gin-gonic/gin"
+
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	"github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// ClusterStatusTransition describes a status change of a cluster.
+type ClusterStatusTransition struct {
+	FromStatus        string    `json:"fromStatus"`
+	FromStatusMessage string    `json:"fromStatusMessage"`
+	ToStatus          string    `json:"toStatus"`
+	ToStatusMessage   string    `json:"toStatusMessage"`
+	CreatedAt         time.Time `json:"createdAt"`
+}
+
+// GetClusterStatusHistoryResponse describes the status history of a cluster.
+type GetClusterStatusHistoryResponse struct {
+	ClusterID   uint                      `json:"clusterId"`
+	ClusterName string                    `json:"clusterName,omitempty"`
+	Transitions []ClusterStatusTransition `json:"transitions"`
+}
+
+// GetClusterStatusHistory returns the status transitions of a cluster.
+// It works for deleted clusters as well, so it is not registered behind the cluster check middleware.
+func (a *ClusterAPI) GetClusterStatusHistory(c *gin.Context) {
+	clusterID, ok := ginutils.UintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	orgID, ok := ginutils.UintParam(c, "orgid")
+	if !ok {
+		return
+	}
+
+	history, err := a.clusterManager.GetClusterStatusHistory(c.Request.Context(), orgID, clusterID)
+	if intCluster.IsClusterNotFoundError(err) {
+		ginutils.ReplyWithErrorResponse(c, &common.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "cluster not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		a.errorHandler.Handle(emperror.With(err, "clusterId", clusterID))
+
+		ginutils.ReplyWithErrorResponse(c, &common.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error getting cluster status history",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response := GetClusterStatusHistoryResponse{
+		ClusterID:   clusterID,
+		Transitions: make([]ClusterStatusTransition, 0, len(history)),
+	}
+
+	for _, item := range history {
+		response.ClusterName = item.ClusterName
+		response.Transitions = append(response.Transitions, ClusterStatusTransition{
+			FromStatus:        item.FromStatus,
+			FromStatusMessage: item.FromStatusMessage,
+			ToStatus:          item.ToStatus,
+			ToStatusMessage:   item.ToStatusMessage,
+			CreatedAt:         item.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
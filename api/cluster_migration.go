@@ -0,0 +1,244 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"emperror.dev/emperror"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/cluster"
+	"github.com/banzaicloud/pipeline/config"
+	"github.com/banzaicloud/pipeline/internal/ark"
+	arkAPI "github.com/banzaicloud/pipeline/internal/ark/api"
+	arkMigration "github.com/banzaicloud/pipeline/internal/ark/migration"
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/correlationid"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+)
+
+const defaultMigrationBackupTTL = 720 * time.Hour
+
+// CreateClusterMigration backs up the workloads of a cluster and restores them into another (new or existing) cluster.
+func (a *ClusterAPI) CreateClusterMigration(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+	logger := correlationid.Logger(a.logger, c)
+
+	source, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	var request arkAPI.CreateMigrationRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "error parsing request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	backupTTL := defaultMigrationBackupTTL
+	if request.TTL != "" {
+		var err error
+		backupTTL, err = time.ParseDuration(request.TTL)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "invalid backup TTL",
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
+	org := auth.GetCurrentOrganization(c.Request)
+	userID := auth.GetCurrentUser(c.Request).ID
+
+	logger = logger.WithFields(logrus.Fields{
+		"organization":  org.ID,
+		"sourceCluster": source.GetID(),
+	})
+
+	_, err := ark.DeploymentsServiceFactory(org, source, config.DB(), logger).GetActiveDeployment()
+	if gorm.IsRecordNotFoundError(err) {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "backup service is not enabled on the source cluster",
+			Error:   "backup service is not enabled on the source cluster",
+		})
+		return
+	} else if err != nil {
+		err = emperror.Wrap(err, "failed to get backup service of the source cluster")
+		a.errorHandler.Handle(err)
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var target cluster.CommonCluster
+	switch {
+	case request.TargetClusterID != 0:
+		if request.TargetClusterID == source.GetID() {
+			c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "target cluster must differ from the source cluster",
+				Error:   "target cluster must differ from the source cluster",
+			})
+			return
+		}
+
+		target, err = a.clusterManager.GetClusterByID(ctx, org.ID, request.TargetClusterID)
+		if intCluster.IsClusterNotFoundError(err) {
+			c.JSON(http.StatusNotFound, pkgCommon.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "target cluster not found",
+				Error:   err.Error(),
+			})
+			return
+		} else if err != nil {
+			err = emperror.Wrap(err, "failed to get target cluster")
+			a.errorHandler.Handle(err)
+			pkgCommon.ErrorResponseWithStatus(c, http.StatusInternalServerError, err)
+			return
+		}
+
+	case request.TargetCluster != nil:
+		logger.WithField("cluster", request.TargetCluster.Name).Info("creating target cluster")
+
+		allowCIDROverlap, _ := strconv.ParseBool(c.Query("allowCIDROverlap"))
+
+		var errResponse *pkgCommon.ErrorResponse
+		target, errResponse = a.createCluster(ctx, request.TargetCluster, org.ID, userID, request.TargetCluster.PostHooks, allowCIDROverlap)
+		if errResponse != nil {
+			c.JSON(errResponse.Code, errResponse)
+			return
+		}
+
+	default:
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "either targetClusterId or targetCluster is required",
+			Error:   "either targetClusterId or targetCluster is required",
+		})
+		return
+	}
+
+	logger = logger.WithField("targetCluster", target.GetID())
+
+	migrations := ark.MigrationsServiceFactory(org, config.DB(), logger)
+
+	migration, err := migrations.Create(&arkAPI.PersistMigrationRequest{
+		SourceClusterID: source.GetID(),
+		TargetClusterID: target.GetID(),
+		BackupName:      fmt.Sprintf("%s-migration-%s", source.GetName(), time.Now().UTC().Format("20060102150405")),
+		Options:         request.Options,
+		CreatedBy:       userID,
+	})
+	if err != nil {
+		err = emperror.Wrap(err, "failed to save migration")
+		a.errorHandler.Handle(err)
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	logger.WithField("migration", migration.ID).Info("starting cluster migration")
+
+	_, err = arkMigration.StartMigration(ctx, a.workflowClient, arkMigration.MigrateClusterWorkflowInput{
+		OrganizationID:   org.ID,
+		MigrationID:      migration.ID,
+		SourceClusterID:  source.GetID(),
+		SourceClusterUID: source.GetUID(),
+		TargetClusterID:  target.GetID(),
+		BackupName:       migration.BackupName,
+		BackupTTL:        backupTTL,
+		Options:          migration.Options,
+	})
+	if err != nil {
+		_ = migrations.UpdateStatus(migration.ID, arkAPI.MigrationStatusFailed, err.Error())
+
+		err = emperror.Wrap(err, "failed to start migration")
+		a.errorHandler.Handle(err)
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, arkAPI.CreateMigrationResponse{
+		Migration: migration,
+		Status:    http.StatusAccepted,
+	})
+}
+
+// ListClusterMigrations lists the migrations started from a cluster.
+func (a *ClusterAPI) ListClusterMigrations(c *gin.Context) {
+	source, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	org := auth.GetCurrentOrganization(c.Request)
+
+	migrations, err := ark.MigrationsServiceFactory(org, config.DB(), a.logger).ListBySourceCluster(source.GetID())
+	if err != nil {
+		err = emperror.Wrap(err, "failed to list migrations")
+		a.errorHandler.Handle(err)
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, migrations)
+}
+
+// GetClusterMigration returns the status and the restore results of a migration.
+func (a *ClusterAPI) GetClusterMigration(c *gin.Context) {
+	source, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	migrationID, ok := ginutils.UintParam(c, "migrationId")
+	if !ok {
+		return
+	}
+
+	org := auth.GetCurrentOrganization(c.Request)
+
+	migration, err := ark.MigrationsServiceFactory(org, config.DB(), a.logger).GetByID(migrationID)
+	if (err == nil && migration.SourceClusterID != source.GetID()) || gorm.IsRecordNotFoundError(errors.Cause(err)) {
+		c.JSON(http.StatusNotFound, pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "migration not found",
+			Error:   "migration not found",
+		})
+		return
+	} else if err != nil {
+		err = emperror.Wrap(err, "failed to get migration")
+		a.errorHandler.Handle(err)
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, migration)
+}
@@ -0,0 +1,293 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"emperror.dev/emperror"
+	"emperror.dev/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/api/common"
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/sleepschedule"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/correlationid"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// SleepScheduleAPI implements the cluster sleep schedule management functions.
+type SleepScheduleAPI struct {
+	manager       *sleepschedule.Manager
+	clusterGetter common.ClusterGetter
+	logger        logrus.FieldLogger
+	errorHandler  emperror.Handler
+}
+
+// NewSleepScheduleAPI returns a new SleepScheduleAPI instance.
+func NewSleepScheduleAPI(
+	manager *sleepschedule.Manager,
+	clusterGetter common.ClusterGetter,
+	logger logrus.FieldLogger,
+	errorHandler emperror.Handler,
+) *SleepScheduleAPI {
+	return &SleepScheduleAPI{
+		manager:       manager,
+		clusterGetter: clusterGetter,
+		logger:        logger,
+		errorHandler:  errorHandler,
+	}
+}
+
+// SleepScheduleRequest describes the sleep schedule of a cluster.
+type SleepScheduleRequest struct {
+	// SleepAt is a cron expression (eg. "0 20 * * 1-5") of the times the cluster is put to sleep.
+	SleepAt string `json:"sleepAt" binding:"required"`
+
+	// WakeAt is a cron expression (eg. "0 7 * * 1-5") of the times the cluster is woken up.
+	WakeAt string `json:"wakeAt" binding:"required"`
+
+	// Timezone is the IANA name of the timezone the cron expressions are evaluated in (defaults to UTC).
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// SleepScheduleOverrideRequest keeps a cluster asleep or awake regardless of its schedule.
+type SleepScheduleOverrideRequest struct {
+	// State is either asleep or awake.
+	State string `json:"state" binding:"required"`
+
+	// Until is the end of the override. Defaults to the next scheduled transition into the opposite state.
+	Until *time.Time `json:"until,omitempty"`
+}
+
+// SleepScheduleResponse describes the sleep schedule of a cluster.
+type SleepScheduleResponse struct {
+	ClusterID        uint                        `json:"clusterId"`
+	SleepAt          string                      `json:"sleepAt"`
+	WakeAt           string                      `json:"wakeAt"`
+	Timezone         string                      `json:"timezone"`
+	State            string                      `json:"state"`
+	NodePoolSizes    sleepschedule.NodePoolSizes `json:"nodePoolSizes,omitempty"`
+	OverrideState    string                      `json:"overrideState,omitempty"`
+	OverrideUntil    *time.Time                  `json:"overrideUntil,omitempty"`
+	NextTransitionAt *time.Time                  `json:"nextTransitionAt,omitempty"`
+	LastTransitionAt *time.Time                  `json:"lastTransitionAt,omitempty"`
+	LastError        string                      `json:"lastError,omitempty"`
+	CreatedAt        time.Time                   `json:"createdAt"`
+	CreatedBy        uint                        `json:"createdBy,omitempty"`
+}
+
+// GetSchedule returns the sleep schedule of a cluster.
+func (a *SleepScheduleAPI) GetSchedule(c *gin.Context) {
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	schedule, err := a.manager.GetSchedule(commonCluster.GetID())
+	if err != nil {
+		a.handleError(c, err, "failed to get sleep schedule")
+		return
+	}
+
+	c.JSON(http.StatusOK, newSleepScheduleResponse(*schedule))
+}
+
+// SetSchedule creates or replaces the sleep schedule of a cluster.
+func (a *SleepScheduleAPI) SetSchedule(c *gin.Context) {
+	logger := correlationid.Logger(a.logger, c)
+
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	if !cluster.SleepScheduleSupported(commonCluster.GetDistribution()) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "sleep schedules are not supported for " + commonCluster.GetDistribution() + " clusters",
+			Error:   "the node pools of the cluster cannot be scaled",
+		})
+		return
+	}
+
+	var request SleepScheduleRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "error parsing request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var userID uint
+	if user := auth.GetCurrentUser(c.Request); user != nil {
+		userID = user.ID
+	}
+
+	logger.WithFields(logrus.Fields{
+		"organization": commonCluster.GetOrganizationId(),
+		"cluster":      commonCluster.GetID(),
+	}).Debug("setting cluster sleep schedule")
+
+	schedule, err := a.manager.SetSchedule(sleepschedule.SetScheduleRequest{
+		ClusterID: commonCluster.GetID(),
+		UserID:    userID,
+		SleepAt:   request.SleepAt,
+		WakeAt:    request.WakeAt,
+		Timezone:  request.Timezone,
+	})
+	if err != nil {
+		a.handleError(c, err, "failed to set sleep schedule")
+		return
+	}
+
+	c.JSON(http.StatusOK, newSleepScheduleResponse(*schedule))
+}
+
+// DeleteSchedule deletes the sleep schedule of a cluster.
+func (a *SleepScheduleAPI) DeleteSchedule(c *gin.Context) {
+	logger := correlationid.Logger(a.logger, c)
+
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	logger.WithFields(logrus.Fields{
+		"organization": commonCluster.GetOrganizationId(),
+		"cluster":      commonCluster.GetID(),
+	}).Debug("deleting cluster sleep schedule")
+
+	if err := a.manager.DeleteSchedule(commonCluster.GetID()); err != nil {
+		a.handleError(c, err, "failed to delete sleep schedule")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SetOverride keeps a cluster asleep or awake regardless of its schedule.
+func (a *SleepScheduleAPI) SetOverride(c *gin.Context) {
+	logger := correlationid.Logger(a.logger, c)
+
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	var request SleepScheduleOverrideRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "error parsing request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logger.WithFields(logrus.Fields{
+		"organization": commonCluster.GetOrganizationId(),
+		"cluster":      commonCluster.GetID(),
+		"state":        request.State,
+	}).Debug("overriding cluster sleep schedule")
+
+	schedule, err := a.manager.Override(commonCluster.GetID(), request.State, request.Until)
+	if err != nil {
+		a.handleError(c, err, "failed to override sleep schedule")
+		return
+	}
+
+	c.JSON(http.StatusOK, newSleepScheduleResponse(*schedule))
+}
+
+// ClearOverride makes a cluster follow its sleep schedule again.
+func (a *SleepScheduleAPI) ClearOverride(c *gin.Context) {
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	schedule, err := a.manager.ClearOverride(commonCluster.GetID())
+	if err != nil {
+		a.handleError(c, err, "failed to clear sleep schedule override")
+		return
+	}
+
+	c.JSON(http.StatusOK, newSleepScheduleResponse(*schedule))
+}
+
+func (a *SleepScheduleAPI) handleError(c *gin.Context, err error, message string) {
+	var validationErr sleepschedule.ValidationError
+	var notFoundErr sleepschedule.ScheduleNotFoundError
+
+	switch {
+	case errors.As(err, &validationErr):
+		c.AbortWithStatusJSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: validationErr.Error(),
+			Error:   validationErr.Error(),
+		})
+
+	case errors.As(err, &notFoundErr):
+		c.AbortWithStatusJSON(http.StatusNotFound, pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: notFoundErr.Error(),
+			Error:   notFoundErr.Error(),
+		})
+
+	default:
+		a.errorHandler.Handle(errors.WrapIf(err, message))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: message,
+			Error:   message,
+		})
+	}
+}
+
+func newSleepScheduleResponse(schedule sleepschedule.Schedule) SleepScheduleResponse {
+	response := SleepScheduleResponse{
+		ClusterID:        schedule.ClusterID,
+		SleepAt:          schedule.SleepAt,
+		WakeAt:           schedule.WakeAt,
+		Timezone:         schedule.Timezone,
+		State:            sleepschedule.StateAwake,
+		NodePoolSizes:    schedule.NodePoolSizes,
+		LastTransitionAt: schedule.LastTransitionAt,
+		LastError:        schedule.LastError,
+		CreatedAt:        schedule.CreatedAt,
+		CreatedBy:        schedule.CreatedBy,
+	}
+
+	if schedule.Asleep {
+		response.State = sleepschedule.StateAsleep
+	}
+
+	if schedule.OverrideUntil != nil && schedule.OverrideUntil.After(time.Now()) {
+		response.OverrideState = schedule.OverrideState
+		response.OverrideUntil = schedule.OverrideUntil
+	}
+
+	if _, next, err := schedule.DesiredState(time.Now()); err == nil {
+		response.NextTransitionAt = &next
+	}
+
+	return response
+}
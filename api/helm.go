@@ -18,6 +18,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -460,6 +461,99 @@ func DeleteDeployment(c *gin.Context) {
 	})
 }
 
+// defaultDeploymentHistoryMax is the default number of revisions returned by GetDeploymentHistory (same as helm history)
+const defaultDeploymentHistoryMax = 256
+
+// GetDeploymentHistory returns the revisions of a helm deployment with the changed values
+func GetDeploymentHistory(c *gin.Context) {
+	name := c.Param("name")
+	log.Infof("getting history for deployment: [%s]", name)
+
+	max := int64(defaultDeploymentHistoryMax)
+	if maxStr := c.Query("max"); maxStr != "" {
+		var err error
+		max, err = strconv.ParseInt(maxStr, 10, 32)
+		if err != nil || max < 1 {
+			c.JSON(http.StatusBadRequest, pkgCommmon.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid max parameter",
+				Error:   fmt.Sprintf("positive integer expected: %q", maxStr),
+			})
+			return
+		}
+	}
+
+	kubeConfig, ok := GetK8sConfig(c)
+	if !ok {
+		log.Errorf("could not get the k8s config for querying the history of deployment: [%s]", name)
+		return
+	}
+
+	history, err := helm.GetDeploymentHistory(name, kubeConfig, int32(max))
+	if err != nil {
+		httpStatusCode := http.StatusInternalServerError
+		if _, ok := err.(*helm.DeploymentNotFoundError); ok {
+			httpStatusCode = http.StatusNotFound
+		} else {
+			log.Error("Error during getting deployment history: ", err.Error())
+		}
+
+		c.JSON(httpStatusCode, pkgCommmon.ErrorResponse{
+			Code:    httpStatusCode,
+			Message: "Error getting deployment history",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// RollbackDeployment rolls back a helm deployment to a previous revision
+func RollbackDeployment(c *gin.Context) {
+	name := c.Param("name")
+	log.Infof("rolling back deployment: %s", name)
+
+	var request pkgHelm.RollbackDeploymentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommmon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error during parsing request!",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	kubeConfig, ok := GetK8sConfig(c)
+	if !ok {
+		return
+	}
+
+	rollbackRes, err := helm.RollbackDeployment(name, kubeConfig, request.Version, request.Wait, request.Timeout)
+	if err != nil {
+		httpStatusCode := http.StatusInternalServerError
+		if _, ok := err.(*helm.DeploymentNotFoundError); ok {
+			httpStatusCode = http.StatusNotFound
+		} else {
+			log.Errorf("Error during rolling back deployment: %s", err.Error())
+		}
+
+		c.JSON(httpStatusCode, pkgCommmon.ErrorResponse{
+			Code:    httpStatusCode,
+			Message: "Error rolling back deployment",
+			Error:   err.Error(),
+		})
+		return
+	}
+	log.Info("Rollback deployment succeeded")
+
+	c.JSON(http.StatusOK, pkgHelm.RollbackDeploymentResponse{
+		ReleaseName: name,
+		Version:     rollbackRes.GetRelease().GetVersion(),
+		Status:      rollbackRes.GetRelease().GetInfo().GetStatus().GetCode().String(),
+	})
+}
+
 type parsedDeploymentRequest struct {
 	deploymentName        string
 	deploymentVersion     string
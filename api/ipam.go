@@ -0,0 +1,119 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"emperror.dev/errors"
+	"github.com/gin-gonic/gin"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/network/ipam"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// SuggestCIDRResponse describes a free address range
+type SuggestCIDRResponse struct {
+	CIDR string `json:"cidr"`
+}
+
+// ListCIDRAllocations lists the address ranges used by the clusters of the organization.
+// The ranges of the VPC networks are also listed when a cloud type is specified.
+func (a *NetworkAPI) ListCIDRAllocations(ctx *gin.Context) {
+	list, ok := a.listCIDRAllocations(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, list)
+}
+
+// SuggestCIDR returns the first free address range of the requested size
+// which does not overlap with the ones used in the organization
+func (a *NetworkAPI) SuggestCIDR(ctx *gin.Context) {
+	prefixLength, err := strconv.Atoi(ctx.Query("prefixLength"))
+	if err != nil {
+		ginutils.ReplyWithErrorResponse(ctx, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid prefix length",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	list, ok := a.listCIDRAllocations(ctx)
+	if !ok {
+		return
+	}
+
+	cidr, err := ipam.NextFreeBlock(prefixLength, ctx.QueryArray("pool"), list.Allocations)
+	if err != nil {
+		if errors.As(err, &ipam.NoFreeBlockError{}) {
+			ginutils.ReplyWithErrorResponse(ctx, &pkgCommon.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		replyWithNetworkError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, SuggestCIDRResponse{
+		CIDR: cidr,
+	})
+}
+
+func (a *NetworkAPI) listCIDRAllocations(ctx *gin.Context) (*ipam.AllocationList, bool) {
+	organization := auth.GetCurrentOrganization(ctx.Request)
+
+	list, err := a.cidrPlanner.ListAllocations(context.Background(), organization.ID)
+	if err != nil {
+		replyWithError(ctx, err)
+		return nil, false
+	}
+
+	if ctx.Query("cloudType") != "" {
+		svc, _, ok := a.getNetworkService(ctx)
+		if !ok {
+			return nil, false
+		}
+
+		networks, err := svc.ListNetworks()
+		if err != nil {
+			replyWithError(ctx, errors.Cause(err))
+			return nil, false
+		}
+
+		list.AddNetworks(networks)
+	}
+
+	return list, true
+}
+
+// cidrOverlapErrorResponse describes the overlapping address ranges of a new cluster
+func cidrOverlapErrorResponse(err ipam.OverlapError) *pkgCommon.ErrorResponse {
+	return &pkgCommon.ErrorResponse{
+		Code:    http.StatusConflict,
+		Message: "address ranges of the cluster overlap with existing ones, set allowCIDROverlap to create it anyway",
+		Error:   err.Error(),
+	}
+}
@@ -17,10 +17,15 @@ package api
 import (
 	"net/http"
 
+	"emperror.dev/errors"
+
 	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/network"
+	"github.com/banzaicloud/pipeline/internal/network/ipam"
 	"github.com/banzaicloud/pipeline/internal/platform/gin/correlationid"
 	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
 	"github.com/banzaicloud/pipeline/internal/providers"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
 	pkgProviders "github.com/banzaicloud/pipeline/pkg/providers"
 	"github.com/banzaicloud/pipeline/secret"
 	"github.com/gin-gonic/gin"
@@ -29,13 +34,15 @@ import (
 
 // NetworkAPI implements network functions
 type NetworkAPI struct {
-	logger logrus.FieldLogger
+	cidrPlanner *ipam.Planner
+	logger      logrus.FieldLogger
 }
 
 // NewNetworkAPI returns a new NetworkAPI instance
-func NewNetworkAPI(logger logrus.FieldLogger) *NetworkAPI {
+func NewNetworkAPI(cidrPlanner *ipam.Planner, logger logrus.FieldLogger) *NetworkAPI {
 	return &NetworkAPI{
-		logger: logger,
+		cidrPlanner: cidrPlanner,
+		logger:      logger,
 	}
 }
 
@@ -60,6 +67,20 @@ type RouteTableInfo struct {
 	Name string `json:"name,omitempty"`
 }
 
+// CreateVPCNetworkRequest describes a VPC network to be created
+type CreateVPCNetworkRequest struct {
+	Name     string `json:"name" binding:"required"`
+	CIDR     string `json:"cidr,omitempty"`
+	Location string `json:"location,omitempty"`
+}
+
+// CreateVPCSubnetRequest describes a VPC subnetwork to be created
+type CreateVPCSubnetRequest struct {
+	Name     string `json:"name" binding:"required"`
+	CIDR     string `json:"cidr" binding:"required"`
+	Location string `json:"location,omitempty"`
+}
+
 func filterEmpty(strings []string) (result []string) {
 	for _, s := range strings {
 		if len(s) != 0 {
@@ -268,6 +289,202 @@ func (a *NetworkAPI) ListRouteTables(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, routeTableInfos)
 }
 
+// CreateVPCNetwork creates a VPC network after validating its CIDR block against the existing ones
+func (a *NetworkAPI) CreateVPCNetwork(ctx *gin.Context) {
+	var request CreateVPCNetworkRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ginutils.ReplyWithErrorResponse(ctx, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "error parsing request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	svc, logger, ok := a.getNetworkService(ctx)
+	if !ok {
+		return
+	}
+
+	logger.WithField("cidr", request.CIDR).Info("creating VPC network")
+
+	net, err := network.CreateNetwork(svc, network.CreateNetworkRequest{
+		Name:     request.Name,
+		CIDR:     request.CIDR,
+		Location: request.Location,
+	})
+	if err != nil {
+		replyWithNetworkError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, NetworkInfo{
+		CIDRs: filterEmpty(net.CIDRs()),
+		ID:    net.ID(),
+		Name:  net.Name(),
+	})
+}
+
+// DeleteVPCNetwork deletes a VPC network
+func (a *NetworkAPI) DeleteVPCNetwork(ctx *gin.Context) {
+	svc, logger, ok := a.getNetworkService(ctx)
+	if !ok {
+		return
+	}
+	networkID := ctx.Param("id")
+
+	logger.WithField("networkID", networkID).Info("deleting VPC network")
+
+	if err := svc.DeleteNetwork(networkID); err != nil {
+		replyWithNetworkError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// CreateVPCSubnet creates a subnetwork in the specified VPC network
+// after validating its CIDR block against the network and its existing subnetworks
+func (a *NetworkAPI) CreateVPCSubnet(ctx *gin.Context) {
+	var request CreateVPCSubnetRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ginutils.ReplyWithErrorResponse(ctx, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "error parsing request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	svc, logger, ok := a.getNetworkService(ctx)
+	if !ok {
+		return
+	}
+	networkID := ctx.Param("id")
+
+	logger.WithFields(logrus.Fields{
+		"networkID": networkID,
+		"cidr":      request.CIDR,
+	}).Info("creating VPC subnetwork")
+
+	subnet, err := network.CreateSubnet(svc, network.CreateSubnetRequest{
+		NetworkID: networkID,
+		Name:      request.Name,
+		CIDR:      request.CIDR,
+		Location:  request.Location,
+	})
+	if err != nil {
+		replyWithNetworkError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, SubnetInfo{
+		CIDRs:    filterEmpty(subnet.CIDRs()),
+		ID:       subnet.ID(),
+		Location: subnet.Location(),
+		Name:     subnet.Name(),
+	})
+}
+
+// DeleteVPCSubnet deletes a subnetwork of the specified VPC network
+func (a *NetworkAPI) DeleteVPCSubnet(ctx *gin.Context) {
+	svc, logger, ok := a.getNetworkService(ctx)
+	if !ok {
+		return
+	}
+	networkID := ctx.Param("id")
+	subnetID := ctx.Param("subnetId")
+
+	logger.WithFields(logrus.Fields{
+		"networkID": networkID,
+		"subnetID":  subnetID,
+	}).Info("deleting VPC subnetwork")
+
+	if err := svc.DeleteSubnet(networkID, subnetID); err != nil {
+		replyWithNetworkError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// getNetworkService returns the network service of the provider, region and secret specified in the request
+func (a *NetworkAPI) getNetworkService(ctx *gin.Context) (network.Service, logrus.FieldLogger, bool) {
+	logger := correlationid.Logger(a.logger, ctx)
+
+	organization := auth.GetCurrentOrganization(ctx.Request)
+	provider, ok := getRequiredProviderFromContext(ctx, logger)
+	if !ok {
+		return nil, nil, false
+	}
+	region, resourceGroup, ok := getRequiredRegionOrResourceGroupFromContext(ctx, provider, logger)
+	if !ok {
+		return nil, nil, false
+	}
+	secretID, ok := getRequiredSecretIDFromContext(ctx, logger)
+	if !ok {
+		return nil, nil, false
+	}
+
+	logger = logger.WithFields(logrus.Fields{
+		"organization":  organization.ID,
+		"provider":      provider,
+		"region":        region,
+		"resourceGroup": resourceGroup,
+		"secretID":      secretID,
+	})
+
+	sir, err := secret.Store.Get(organization.ID, secretID)
+	if err != nil {
+		replyWithError(ctx, err)
+		return nil, nil, false
+	}
+
+	err = sir.ValidateSecretType(provider)
+	if err != nil {
+		replyWithError(ctx, err)
+		return nil, nil, false
+	}
+
+	svc, err := providers.NewNetworkService(providers.ServiceParams{
+		Logger:            logger,
+		Provider:          provider,
+		Region:            region,
+		ResourceGroupName: resourceGroup,
+		Secret:            sir,
+	})
+	if err != nil {
+		replyWithError(ctx, err)
+		return nil, nil, false
+	}
+
+	return svc, logger, true
+}
+
+func replyWithNetworkError(ctx *gin.Context, err error) {
+	var validationErr network.ValidationError
+	var notFoundErr network.NetworkNotFoundError
+
+	switch {
+	case errors.As(err, &validationErr):
+		ginutils.ReplyWithErrorResponse(ctx, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: validationErr.Error(),
+			Error:   validationErr.Error(),
+		})
+
+	case errors.As(err, &notFoundErr):
+		ginutils.ReplyWithErrorResponse(ctx, &pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: notFoundErr.Error(),
+			Error:   notFoundErr.Error(),
+		})
+
+	default:
+		replyWithError(ctx, errors.Cause(err))
+	}
+}
+
 func getRequiredProviderFromContext(ctx *gin.Context, logger logrus.FieldLogger) (string, bool) {
 	provider, ok := ginutils.RequiredQueryOrAbort(ctx, "cloudType")
 	return provider, ok
@@ -190,6 +190,14 @@ func (n *NodepoolManagerAPI) SetNodepoolLabelSets(c *gin.Context) {
 		return
 	}
 
+	// updating the label sets drops the node pool taints stored in them
+	err = cluster.SyncNodePoolTaints(commonCluster)
+	if err != nil {
+		errorHandler.Handle(err)
+		ginutils.ReplyWithErrorResponse(c, ErrorResponseFrom(err))
+		return
+	}
+
 	c.JSON(http.StatusOK, "")
 }
 
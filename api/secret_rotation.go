@@ -0,0 +1,283 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"emperror.dev/emperror"
+	"emperror.dev/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/cadence/.gen/go/shared"
+	"go.uber.org/cadence/client"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/correlationid"
+	"github.com/banzaicloud/pipeline/internal/secret/rotation"
+	"github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+// SecretRotationAPI implements the secret rotation management functions.
+type SecretRotationAPI struct {
+	manager        *rotation.Manager
+	workflowClient client.Client
+	logger         logrus.FieldLogger
+	errorHandler   emperror.Handler
+}
+
+// NewSecretRotationAPI returns a new SecretRotationAPI instance.
+func NewSecretRotationAPI(
+	manager *rotation.Manager,
+	workflowClient client.Client,
+	logger logrus.FieldLogger,
+	errorHandler emperror.Handler,
+) *SecretRotationAPI {
+	return &SecretRotationAPI{
+		manager:        manager,
+		workflowClient: workflowClient,
+		logger:         logger,
+		errorHandler:   errorHandler,
+	}
+}
+
+// SecretRotationPolicyRequest describes the rotation policy of a secret.
+// Exactly one of Interval and DaysBeforeExpiry must be set.
+type SecretRotationPolicyRequest struct {
+	// Interval is a duration (eg. 720h) after which the secret is rotated again.
+	Interval string `json:"interval,omitempty"`
+
+	// DaysBeforeExpiry rotates a TLS secret the given number of days before its certificate expires.
+	DaysBeforeExpiry int `json:"daysBeforeExpiry,omitempty"`
+}
+
+// SecretRotationPolicyResponse describes the rotation policy of a secret.
+type SecretRotationPolicyResponse struct {
+	SecretID         string     `json:"secretId"`
+	Interval         string     `json:"interval,omitempty"`
+	DaysBeforeExpiry int        `json:"daysBeforeExpiry,omitempty"`
+	NextRotationAt   time.Time  `json:"nextRotationAt"`
+	LastRotatedAt    *time.Time `json:"lastRotatedAt,omitempty"`
+	LastError        string     `json:"lastError,omitempty"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	CreatedBy        uint       `json:"createdBy,omitempty"`
+}
+
+// RotateSecretResponse describes a started secret rotation.
+type RotateSecretResponse struct {
+	WorkflowID string `json:"workflowId"`
+}
+
+// GetPolicy returns the rotation policy of a secret.
+func (a *SecretRotationAPI) GetPolicy(c *gin.Context) {
+	organization := auth.GetCurrentOrganization(c.Request)
+
+	policy, err := a.manager.GetPolicy(organization.ID, getSecretID(c))
+	if err != nil {
+		a.handleError(c, err, "failed to get secret rotation policy")
+		return
+	}
+
+	c.JSON(http.StatusOK, newSecretRotationPolicyResponse(*policy))
+}
+
+// SetPolicy creates or replaces the rotation policy of a secret.
+func (a *SecretRotationAPI) SetPolicy(c *gin.Context) {
+	logger := correlationid.Logger(a.logger, c)
+
+	organization := auth.GetCurrentOrganization(c.Request)
+	secretID := getSecretID(c)
+
+	var request SecretRotationPolicyRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, common.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "error parsing request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var interval time.Duration
+	if request.Interval != "" {
+		var err error
+
+		interval, err = time.ParseDuration(request.Interval)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, common.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "invalid interval",
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
+	var userID uint
+	if user := auth.GetCurrentUser(c.Request); user != nil {
+		userID = user.ID
+	}
+
+	logger.WithFields(logrus.Fields{
+		"organization": organization.ID,
+		"secret":       secretID,
+	}).Debug("setting secret rotation policy")
+
+	policy, err := a.manager.SetPolicy(rotation.SetPolicyRequest{
+		OrganizationID:   organization.ID,
+		SecretID:         secretID,
+		UserID:           userID,
+		Interval:         interval,
+		DaysBeforeExpiry: request.DaysBeforeExpiry,
+	})
+	if err != nil {
+		a.handleError(c, err, "failed to set secret rotation policy")
+		return
+	}
+
+	c.JSON(http.StatusOK, newSecretRotationPolicyResponse(*policy))
+}
+
+// DeletePolicy deletes the rotation policy of a secret.
+func (a *SecretRotationAPI) DeletePolicy(c *gin.Context) {
+	logger := correlationid.Logger(a.logger, c)
+
+	organization := auth.GetCurrentOrganization(c.Request)
+	secretID := getSecretID(c)
+
+	logger.WithFields(logrus.Fields{
+		"organization": organization.ID,
+		"secret":       secretID,
+	}).Debug("deleting secret rotation policy")
+
+	if err := a.manager.DeletePolicy(organization.ID, secretID); err != nil {
+		a.handleError(c, err, "failed to delete secret rotation policy")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RotateSecret starts the rotation of a secret.
+func (a *SecretRotationAPI) RotateSecret(c *gin.Context) {
+	logger := correlationid.Logger(a.logger, c)
+
+	organization := auth.GetCurrentOrganization(c.Request)
+	secretID := getSecretID(c)
+
+	if err := a.manager.ValidateRotation(organization.ID, secretID); err != nil {
+		a.handleError(c, err, "failed to rotate secret")
+		return
+	}
+
+	logger.WithFields(logrus.Fields{
+		"organization": organization.ID,
+		"secret":       secretID,
+	}).Debug("starting secret rotation")
+
+	workflowID, err := rotation.StartRotation(c.Request.Context(), a.workflowClient, rotation.RotateSecretWorkflowInput{
+		OrganizationID: organization.ID,
+		SecretID:       secretID,
+		UpdatedBy:      auth.GetCurrentUser(c.Request).Login,
+	})
+	var alreadyStartedErr *shared.WorkflowExecutionAlreadyStartedError
+	if errors.As(err, &alreadyStartedErr) {
+		c.AbortWithStatusJSON(http.StatusConflict, common.ErrorResponse{
+			Code:    http.StatusConflict,
+			Message: "secret rotation is already in progress",
+			Error:   err.Error(),
+		})
+		return
+	} else if err != nil {
+		a.handleError(c, err, "failed to start secret rotation")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, RotateSecretResponse{
+		WorkflowID: workflowID,
+	})
+}
+
+func (a *SecretRotationAPI) handleError(c *gin.Context, err error, message string) {
+	var validationErr rotation.ValidationError
+	var notFoundErr rotation.PolicyNotFoundError
+	var forbiddenErr secret.ForbiddenError
+	var readOnlyErr secret.ReadOnlyError
+
+	switch {
+	case errors.As(err, &validationErr):
+		c.AbortWithStatusJSON(http.StatusBadRequest, common.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: validationErr.Error(),
+			Error:   validationErr.Error(),
+		})
+
+	case errors.As(err, &forbiddenErr):
+		c.AbortWithStatusJSON(http.StatusBadRequest, common.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: forbiddenErr.Error(),
+			Error:   forbiddenErr.Error(),
+		})
+
+	case errors.As(err, &readOnlyErr):
+		c.AbortWithStatusJSON(http.StatusBadRequest, common.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: readOnlyErr.Error(),
+			Error:   readOnlyErr.Error(),
+		})
+
+	case errors.As(err, &notFoundErr):
+		c.AbortWithStatusJSON(http.StatusNotFound, common.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: notFoundErr.Error(),
+			Error:   notFoundErr.Error(),
+		})
+
+	case errors.Is(err, secret.ErrSecretNotExists):
+		c.AbortWithStatusJSON(http.StatusNotFound, common.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: secret.ErrSecretNotExists.Error(),
+			Error:   secret.ErrSecretNotExists.Error(),
+		})
+
+	default:
+		a.errorHandler.Handle(errors.WrapIf(err, message))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, common.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: message,
+			Error:   message,
+		})
+	}
+}
+
+func newSecretRotationPolicyResponse(policy rotation.Policy) SecretRotationPolicyResponse {
+	response := SecretRotationPolicyResponse{
+		SecretID:         policy.SecretID,
+		DaysBeforeExpiry: policy.DaysBeforeExpiry,
+		NextRotationAt:   policy.NextRotationAt,
+		LastRotatedAt:    policy.LastRotatedAt,
+		LastError:        policy.LastError,
+		CreatedAt:        policy.CreatedAt,
+		CreatedBy:        policy.CreatedBy,
+	}
+
+	if policy.Interval > 0 {
+		response.Interval = policy.Interval.String()
+	}
+
+	return response
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/banzaicloud/pipeline/cluster"
 	"github.com/banzaicloud/pipeline/config"
 	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	intSecret "github.com/banzaicloud/pipeline/internal/secret"
 	"github.com/banzaicloud/pipeline/pkg/common"
 	"github.com/banzaicloud/pipeline/pkg/providers"
 	secretTypes "github.com/banzaicloud/pipeline/pkg/secret"
@@ -361,6 +362,44 @@ func DeleteSecrets(c *gin.Context) {
 
 	secretID := getSecretID(c)
 
+	force, err := strconv.ParseBool(c.DefaultQuery("force", "false"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, common.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid force parameter",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if !force {
+		log.Infof("Check usages before delete secret[%s]", secretID)
+
+		usages, err := intSecret.NewUsageFinder(config.DB()).FindUsages(organizationID, secretID)
+		if err != nil {
+			log.Errorf("Error during finding secret usages: %s", err.Error())
+			c.AbortWithStatusJSON(http.StatusInternalServerError, common.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Error during finding secret usages",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		if len(usages) > 0 {
+			log.Infof("Secret[%s] is still in use, refusing to delete it", secretID)
+			c.AbortWithStatusJSON(http.StatusConflict, SecretInUseResponse{
+				ErrorResponse: common.ErrorResponse{
+					Code:    http.StatusConflict,
+					Message: fmt.Sprintf("Secret[%s] is still in use", secretID),
+					Error:   "the secret is referenced by other resources, use force=true to delete it anyway",
+				},
+				Usages: usages,
+			})
+			return
+		}
+	}
+
 	log.Infof("Check clusters before delete secret[%s]", secretID)
 	if err := checkClustersBeforeDelete(organizationID, secretID); err != nil {
 		log.Errorf("Cluster found with this secret[%s]: %s", secretID, err.Error())
@@ -384,6 +423,169 @@ func DeleteSecrets(c *gin.Context) {
 	}
 }
 
+// SecretInUseResponse describes the resources which prevent a secret from being deleted
+type SecretInUseResponse struct {
+	common.ErrorResponse
+	Usages []intSecret.Usage `json:"usages"`
+}
+
+// GetSecretUsages returns the resources referencing a secret
+func GetSecretUsages(c *gin.Context) {
+	organizationID := auth.GetCurrentOrganization(c.Request).ID
+	secretID := getSecretID(c)
+	log.Debugf("listing secret usages: %d/%s", organizationID, secretID)
+
+	if _, err := secret.RestrictedStore.Get(organizationID, secretID); err != nil {
+		log.Errorf("error during getting secret: %s", err.Error())
+		abortWithSecretVersionError(c, err, "Error during getting secret")
+		return
+	}
+
+	usages, err := intSecret.NewUsageFinder(config.DB()).FindUsages(organizationID, secretID)
+	if err != nil {
+		log.Errorf("error during finding secret usages: %s", err.Error())
+		c.AbortWithStatusJSON(http.StatusInternalServerError, common.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error during finding secret usages",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, usages)
+}
+
+// ListSecretVersions returns the versions of a secret, latest first
+func ListSecretVersions(c *gin.Context) {
+	organizationID := auth.GetCurrentOrganization(c.Request).ID
+	secretID := getSecretID(c)
+	log.Debugf("listing secret versions: %d/%s", organizationID, secretID)
+
+	versions, err := secret.RestrictedStore.ListVersions(organizationID, secretID)
+	if err != nil {
+		log.Errorf("error during listing secret versions: %s", err.Error())
+		abortWithSecretVersionError(c, err, "Error during listing secret versions")
+		return
+	}
+
+	c.JSON(http.StatusOK, versions)
+}
+
+// GetSecretVersion returns a version of a secret, values are hidden unless requested with values=true
+func GetSecretVersion(c *gin.Context) {
+	organizationID := auth.GetCurrentOrganization(c.Request).ID
+	secretID := getSecretID(c)
+
+	version, ok := getSecretVersion(c)
+	if !ok {
+		return
+	}
+
+	values, err := strconv.ParseBool(c.DefaultQuery("values", "false"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, common.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid values parameter",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	log.Debugf("getting secret version: %d/%s/%d", organizationID, secretID, version)
+
+	secretItem, err := secret.RestrictedStore.GetVersion(organizationID, secretID, version)
+	if err != nil {
+		log.Errorf("error during getting secret version: %s", err.Error())
+		abortWithSecretVersionError(c, err, "Error during getting secret version")
+		return
+	}
+
+	if !values {
+		for k := range secretItem.Values {
+			secretItem.Values[k] = "<hidden>"
+		}
+	}
+
+	c.JSON(http.StatusOK, secretItem)
+}
+
+// RestoreSecretVersion writes a previous version of a secret as its new current version
+func RestoreSecretVersion(c *gin.Context) {
+	organizationID := auth.GetCurrentOrganization(c.Request).ID
+	secretID := getSecretID(c)
+
+	version, ok := getSecretVersion(c)
+	if !ok {
+		return
+	}
+
+	log.Infof("restoring secret version: %d/%s/%d", organizationID, secretID, version)
+
+	err := secret.RestrictedStore.RestoreVersion(organizationID, secretID, version, auth.GetCurrentUser(c.Request).Login)
+	if err != nil {
+		log.Errorf("error during restoring secret version: %s", err.Error())
+		abortWithSecretVersionError(c, err, "Error during restoring secret version")
+		return
+	}
+
+	s, err := secret.RestrictedStore.Get(organizationID, secretID)
+	if err != nil {
+		log.Errorf("error during getting secret: %s", err.Error())
+		c.AbortWithStatusJSON(http.StatusInternalServerError, common.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error during getting secret",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, client.CreateSecretResponse{
+		Name:      s.Name,
+		Type:      s.Type,
+		Id:        secretID,
+		UpdatedAt: s.UpdatedAt,
+		UpdatedBy: s.UpdatedBy,
+		Version:   int32(s.Version),
+		Tags:      s.Tags,
+	})
+}
+
+func getSecretVersion(c *gin.Context) (int, bool) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, common.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid secret version",
+			Error:   fmt.Sprintf("invalid secret version: %q", c.Param("version")),
+		})
+		return 0, false
+	}
+
+	return version, true
+}
+
+func abortWithSecretVersionError(c *gin.Context, err error, message string) {
+	code := http.StatusInternalServerError
+
+	switch cause := errors.Cause(err); cause.(type) {
+	case secret.ForbiddenError, secret.ReadOnlyError:
+		code = http.StatusBadRequest
+
+	default:
+		if cause == secret.ErrSecretNotExists || cause == secret.ErrSecretVersionNotExists {
+			code = http.StatusNotFound
+		} else if secret.IsCASError(err) {
+			code = http.StatusConflict
+		}
+	}
+
+	c.AbortWithStatusJSON(code, common.ErrorResponse{
+		Code:    code,
+		Message: message,
+		Error:   err.Error(),
+	})
+}
+
 // GetSecretTags returns tags of a secret by ID
 func GetSecretTags(c *gin.Context) {
 	organizationID := auth.GetCurrentOrganization(c.Request).ID
@@ -255,12 +255,14 @@ const (
 )
 
 const (
-	OCIUserOCID          = "OCIUserOCID"
-	OCITenancyOCID       = "OCITenancyOCID"
-	OCIAPIKey            = "OCIAPIKey"
-	OCIAPIKeyFingerprint = "OCIAPIKeyFingerprint"
-	OCIRegion            = "OCIRegion"
-	OCICompartmentOCID   = "OCICompartmentOCID"
+	OCIUserOCID            = "OCIUserOCID"
+	OCITenancyOCID         = "OCITenancyOCID"
+	OCIAPIKey              = "OCIAPIKey"
+	OCIAPIKeyFingerprint   = "OCIAPIKeyFingerprint"
+	OCIRegion              = "OCIRegion"
+	OCICompartmentOCID     = "OCICompartmentOCID"
+	OCICustomerSecretKeyID = "OCICustomerSecretKeyID"
+	OCICustomerSecretKey   = "OCICustomerSecretKey"
 )
 
 // Create requests
@@ -309,12 +311,14 @@ var (
 		Name: secretNameOracle,
 		Type: clusterTypes.Oracle,
 		Values: map[string]string{
-			"user_ocid":           OCIUserOCID,
-			"tenancy_ocid":        OCITenancyOCID,
-			"api_key":             OCIAPIKey,
-			"api_key_fingerprint": OCIAPIKeyFingerprint,
-			"region":              OCIRegion,
-			"compartment_ocid":    OCICompartmentOCID,
+			"user_ocid":              OCIUserOCID,
+			"tenancy_ocid":           OCITenancyOCID,
+			"api_key":                OCIAPIKey,
+			"api_key_fingerprint":    OCIAPIKeyFingerprint,
+			"region":                 OCIRegion,
+			"compartment_ocid":       OCICompartmentOCID,
+			"customer_secret_key_id": OCICustomerSecretKeyID,
+			"customer_secret_key":    OCICustomerSecretKey,
 		},
 	}
 
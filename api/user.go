@@ -135,7 +135,21 @@ func (a *UserAPI) GetUsers(c *gin.Context) {
 			Error:   message,
 		})
 		return
-	} else if id == 0 {
+	}
+
+	err = a.fillUserRoles(organization, users)
+	if err != nil {
+		message := "failed to fetch user roles"
+		a.errorHandler.Handle(emperror.Wrap(err, message))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, common.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: message,
+			Error:   message,
+		})
+		return
+	}
+
+	if id == 0 {
 		c.JSON(http.StatusOK, users)
 	} else if len(users) == 1 {
 		c.JSON(http.StatusOK, users[0])
@@ -158,7 +172,27 @@ func (a *UserAPI) GetUsers(c *gin.Context) {
 	}
 }
 
-// AddUser adds a user to an organization, role=admin|member has to be in the body, otherwise member is the default role.
+func (a *UserAPI) fillUserRoles(organization *auth.Organization, users []auth.User) error {
+	var memberships []auth.UserOrganization
+
+	err := a.db.Where(&auth.UserOrganization{OrganizationID: organization.ID}).Find(&memberships).Error
+	if err != nil {
+		return err
+	}
+
+	roles := make(map[uint]string, len(memberships))
+	for _, membership := range memberships {
+		roles[membership.UserID] = membership.Role
+	}
+
+	for i := range users {
+		users[i].Role = roles[users[i].ID]
+	}
+
+	return nil
+}
+
+// AddUser adds a user to an organization, role=admin|member|viewer has to be in the body, otherwise member is the default role.
 func (a *UserAPI) AddUser(c *gin.Context) {
 
 	log.Info("Adding user to organization")
@@ -177,8 +211,8 @@ func (a *UserAPI) AddUser(c *gin.Context) {
 	}
 
 	role := struct {
-		Role string `json:"role" binding:"required,eq=member|eq=admin"`
-	}{Role: "member"}
+		Role string `json:"role" binding:"required,eq=member|eq=admin|eq=viewer"`
+	}{Role: auth.RoleMember}
 
 	if c.Request.ContentLength != 0 {
 		err = c.ShouldBindJSON(&role)
@@ -237,6 +271,99 @@ func (a *UserAPI) addUserToOrgInDb(organization *auth.Organization, user *auth.U
 	return tx.Commit().Error
 }
 
+// UpdateUser changes the role of a user in an organization, role=admin|member|viewer has to be in the body.
+func (a *UserAPI) UpdateUser(c *gin.Context) {
+
+	log.Info("Updating user role in organization")
+
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		message := fmt.Sprintf("error parsing user id: %s", err)
+		log.Info(message)
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: message,
+			Error:   message,
+		})
+		return
+	}
+
+	var role struct {
+		Role string `json:"role" binding:"required,eq=member|eq=admin|eq=viewer"`
+	}
+
+	err = c.ShouldBindJSON(&role)
+	if err != nil {
+		message := fmt.Sprintf("error parsing role from request: %s", err)
+		log.Info(message)
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: message,
+			Error:   message,
+		})
+		return
+	}
+
+	organization := auth.GetCurrentOrganization(c.Request)
+
+	var membership auth.UserOrganization
+	err = a.db.Where(&auth.UserOrganization{UserID: uint(id), OrganizationID: organization.ID}).First(&membership).Error
+	if err != nil {
+		message := fmt.Sprintf("user not found with id: %d", id)
+		statusCode := auth.GormErrorToStatusCode(err)
+		if statusCode != http.StatusNotFound {
+			message = "failed to fetch user"
+			a.errorHandler.Handle(emperror.Wrap(err, message))
+		}
+		c.AbortWithStatusJSON(statusCode, common.ErrorResponse{
+			Code:    statusCode,
+			Message: message,
+			Error:   message,
+		})
+		return
+	}
+
+	if membership.Role == auth.RoleAdmin && role.Role != auth.RoleAdmin {
+		var adminCount int
+		err = a.db.Model(&auth.UserOrganization{}).Where(&auth.UserOrganization{OrganizationID: organization.ID, Role: auth.RoleAdmin}).Count(&adminCount).Error
+		if err != nil {
+			message := "failed to count organization admins"
+			a.errorHandler.Handle(emperror.Wrap(err, message))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, common.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: message,
+				Error:   message,
+			})
+			return
+		}
+
+		if adminCount <= 1 {
+			message := "the organization must have at least one admin"
+			c.AbortWithStatusJSON(http.StatusConflict, common.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: message,
+				Error:   message,
+			})
+			return
+		}
+	}
+
+	err = a.db.Model(&auth.UserOrganization{}).Where(&membership).Update("role", role.Role).Error
+	if err != nil {
+		message := "failed to update user role"
+		a.errorHandler.Handle(emperror.Wrap(err, message))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, common.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: message,
+			Error:   message,
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // RemoveUser removes a user from an organization
 func (a *UserAPI) RemoveUser(c *gin.Context) {
 
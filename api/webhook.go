@@ -0,0 +1,257 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"emperror.dev/emperror"
+	"emperror.dev/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/correlationid"
+	"github.com/banzaicloud/pipeline/internal/webhook"
+	"github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// WebhookAPI implements the webhook subscription management functions.
+type WebhookAPI struct {
+	manager      *webhook.Manager
+	logger       logrus.FieldLogger
+	errorHandler emperror.Handler
+}
+
+// NewWebhookAPI returns a new WebhookAPI instance.
+func NewWebhookAPI(manager *webhook.Manager, logger logrus.FieldLogger, errorHandler emperror.Handler) *WebhookAPI {
+	return &WebhookAPI{
+		manager:      manager,
+		logger:       logger,
+		errorHandler: errorHandler,
+	}
+}
+
+// CreateWebhookRequest describes a new webhook subscription.
+type CreateWebhookRequest struct {
+	URL string `json:"url" binding:"required"`
+
+	// Events filters the delivered event types, every event is delivered when empty.
+	Events []string `json:"events,omitempty"`
+
+	// Secret is used for signing the payloads, a random one is generated when empty.
+	Secret string `json:"secret,omitempty"`
+}
+
+// WebhookResponse describes a webhook subscription.
+type WebhookResponse struct {
+	ID        uint      `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"createdAt"`
+	CreatedBy uint      `json:"createdBy,omitempty"`
+}
+
+// CreateWebhookResponse describes a newly created webhook subscription.
+type CreateWebhookResponse struct {
+	WebhookResponse
+
+	// Secret is only returned once, on creation.
+	Secret string `json:"secret"`
+}
+
+// ListSubscriptions lists the webhook subscriptions of an organization.
+func (a *WebhookAPI) ListSubscriptions(c *gin.Context) {
+	organization := auth.GetCurrentOrganization(c.Request)
+
+	subscriptions, err := a.manager.ListSubscriptions(organization.ID)
+	if err != nil {
+		a.handleError(c, err, "failed to list webhooks")
+		return
+	}
+
+	response := make([]WebhookResponse, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		response = append(response, newWebhookResponse(subscription))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateSubscription creates a new webhook subscription.
+func (a *WebhookAPI) CreateSubscription(c *gin.Context) {
+	logger := correlationid.Logger(a.logger, c)
+
+	organization := auth.GetCurrentOrganization(c.Request)
+
+	var request CreateWebhookRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, common.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "error parsing request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var userID uint
+	if user := auth.GetCurrentUser(c.Request); user != nil {
+		userID = user.ID
+	}
+
+	logger.WithField("organization", organization.ID).Debug("creating webhook")
+
+	subscription, secret, err := a.manager.CreateSubscription(webhook.CreateSubscriptionRequest{
+		OrganizationID: organization.ID,
+		UserID:         userID,
+		URL:            request.URL,
+		Events:         request.Events,
+		SigningKey:     request.Secret,
+	})
+	if err != nil {
+		a.handleError(c, err, "failed to create webhook")
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreateWebhookResponse{
+		WebhookResponse: newWebhookResponse(*subscription),
+		Secret:          secret,
+	})
+}
+
+// GetSubscription returns a webhook subscription.
+func (a *WebhookAPI) GetSubscription(c *gin.Context) {
+	organization := auth.GetCurrentOrganization(c.Request)
+
+	subscriptionID, ok := a.getSubscriptionID(c)
+	if !ok {
+		return
+	}
+
+	subscription, err := a.manager.GetSubscription(organization.ID, subscriptionID)
+	if err != nil {
+		a.handleError(c, err, "failed to get webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, newWebhookResponse(*subscription))
+}
+
+// DeleteSubscription deletes a webhook subscription.
+func (a *WebhookAPI) DeleteSubscription(c *gin.Context) {
+	logger := correlationid.Logger(a.logger, c)
+
+	organization := auth.GetCurrentOrganization(c.Request)
+
+	subscriptionID, ok := a.getSubscriptionID(c)
+	if !ok {
+		return
+	}
+
+	logger.WithFields(logrus.Fields{
+		"organization": organization.ID,
+		"webhook":      subscriptionID,
+	}).Debug("deleting webhook")
+
+	if err := a.manager.DeleteSubscription(organization.ID, subscriptionID); err != nil {
+		a.handleError(c, err, "failed to delete webhook")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries lists the latest delivery attempts of a webhook subscription.
+func (a *WebhookAPI) ListDeliveries(c *gin.Context) {
+	organization := auth.GetCurrentOrganization(c.Request)
+
+	subscriptionID, ok := a.getSubscriptionID(c)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil || limit < 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, common.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid limit",
+			Error:   "limit must be a non-negative integer",
+		})
+		return
+	}
+
+	deliveries, err := a.manager.ListDeliveries(organization.ID, subscriptionID, limit)
+	if err != nil {
+		a.handleError(c, err, "failed to list webhook deliveries")
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+func (a *WebhookAPI) getSubscriptionID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, common.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid webhook ID",
+			Error:   err.Error(),
+		})
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+func (a *WebhookAPI) handleError(c *gin.Context, err error, message string) {
+	var validationErr webhook.ValidationError
+	var notFoundErr webhook.SubscriptionNotFoundError
+
+	switch {
+	case errors.As(err, &validationErr):
+		c.AbortWithStatusJSON(http.StatusBadRequest, common.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: validationErr.Error(),
+			Error:   validationErr.Error(),
+		})
+
+	case errors.As(err, &notFoundErr):
+		c.AbortWithStatusJSON(http.StatusNotFound, common.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: notFoundErr.Error(),
+			Error:   notFoundErr.Error(),
+		})
+
+	default:
+		a.errorHandler.Handle(errors.WrapIf(err, message))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, common.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: message,
+			Error:   message,
+		})
+	}
+}
+
+func newWebhookResponse(subscription webhook.Subscription) WebhookResponse {
+	return WebhookResponse{
+		ID:        subscription.ID,
+		URL:       subscription.URL,
+		Events:    subscription.EventTypes(),
+		CreatedAt: subscription.CreatedAt,
+		CreatedBy: subscription.CreatedBy,
+	}
+}
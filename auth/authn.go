@@ -610,7 +610,7 @@ func (h *banzaiDeregisterHandler) handler(context *auth.Context) {
 		GROUP BY user_id, organization_id
 		HAVING COUNT(*) = 1`
 
-	if err := db.Raw(sql, "admin", user.ID, "admin").Scan(&userAdminOrganizations).Error; err != nil {
+	if err := db.Raw(sql, RoleAdmin, user.ID, RoleAdmin).Scan(&userAdminOrganizations).Error; err != nil {
 		errorHandler.Handle(errors.Wrap(err, "failed select user only owned organizations"))
 		http.Error(context.Writer, err.Error(), http.StatusInternalServerError)
 		return
@@ -104,7 +104,7 @@ func getGithubOrganizations(token string) ([]organization, error) {
 
 	userOrg := organization{
 		name:     *user.Login,
-		role:     "admin",
+		role:     RoleAdmin,
 		provider: ProviderGithub,
 	}
 
@@ -73,8 +73,9 @@ type User struct {
 	Login         string         `gorm:"unique;not null" form:"login" json:"login"`
 	Image         string         `form:"image" json:"image,omitempty"`
 	Organizations []Organization `gorm:"many2many:user_organizations" json:"organizations,omitempty"`
-	Virtual       bool           `json:"-" gorm:"-"` // Used only internally
-	APIToken      string         `json:"-" gorm:"-"` // Used only internally
+	Role          string         `json:"role,omitempty" gorm:"-"` // Role in the current organization, filled only when listing organization users
+	Virtual       bool           `json:"-" gorm:"-"`              // Used only internally
+	APIToken      string         `json:"-" gorm:"-"`              // Used only internally
 }
 
 // CICDUser struct
@@ -92,6 +93,25 @@ type CICDUser struct {
 	Synced int64  `gorm:"column:user_synced"`
 }
 
+const (
+	// RoleAdmin is the role of organization administrators with full access to the organization
+	RoleAdmin = "admin"
+	// RoleMember is the role of regular organization members who can manage resources but not the organization itself
+	RoleMember = "member"
+	// RoleViewer is the role of read-only organization members
+	RoleViewer = "viewer"
+)
+
+// IsValidRole returns true if the given role is a known organization role.
+func IsValidRole(role string) bool {
+	switch role {
+	case RoleAdmin, RoleMember, RoleViewer:
+		return true
+	default:
+		return false
+	}
+}
+
 // UserOrganization describes the user organization
 type UserOrganization struct {
 	UserID         uint
@@ -155,6 +175,15 @@ func GetCurrentOrganizationID(ctx context.Context) (uint, bool) {
 	return 0, false
 }
 
+// GetCurrentUserID return the current user ID if present
+func GetCurrentUserID(ctx context.Context) (uint, bool) {
+	if user, ok := ctx.Value(bauth.CurrentUser).(*User); ok && user != nil {
+		return user.ID, true
+	}
+
+	return 0, false
+}
+
 // NewCICDClient creates an authenticated CICD client for the user specified by the JWT in the HTTP request
 func NewCICDClient(apiToken string) cicd.Client {
 	cicdURL := viper.GetString("cicd.url")
@@ -20,4 +20,5 @@ type NodePoolStatusAmazon struct {
 	MaxCount     int32             `json:"maxCount,omitempty"`
 	Image        string            `json:"image,omitempty"`
 	Labels       map[string]string `json:"labels,omitempty"`
+	Taints       []NodePoolTaint   `json:"taints,omitempty"`
 }
@@ -12,7 +12,8 @@
 package client
 
 type NodePoolsAck struct {
-	InstanceType string `json:"instanceType"`
-	MinCount     int32  `json:"minCount,omitempty"`
-	MaxCount     int32  `json:"maxCount,omitempty"`
+	InstanceType string          `json:"instanceType"`
+	MinCount     int32           `json:"minCount,omitempty"`
+	MaxCount     int32           `json:"maxCount,omitempty"`
+	Taints       []NodePoolTaint `json:"taints,omitempty"`
 }
@@ -20,4 +20,5 @@ type NodePoolsAmazon struct {
 	MaxCount     int32             `json:"maxCount"`
 	Labels       map[string]string `json:"labels,omitempty"`
 	Image        string            `json:"image,omitempty"`
+	Taints       []NodePoolTaint   `json:"taints,omitempty"`
 }
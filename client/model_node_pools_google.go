@@ -19,4 +19,5 @@ type NodePoolsGoogle struct {
 	MaxCount     int32             `json:"maxCount,omitempty"`
 	InstanceType string            `json:"instanceType"`
 	Labels       map[string]string `json:"labels,omitempty"`
+	Taints       []NodePoolTaint   `json:"taints,omitempty"`
 }
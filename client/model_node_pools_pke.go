@@ -21,4 +21,5 @@ type NodePoolsPke struct {
 	Provider       string                 `json:"provider"`
 	ProviderConfig map[string]interface{} `json:"providerConfig"`
 	Hosts          []PkeHosts             `json:"hosts,omitempty"`
+	Taints         []NodePoolTaint        `json:"taints,omitempty"`
 }
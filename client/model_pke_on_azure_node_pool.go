@@ -15,6 +15,7 @@ type PkeOnAzureNodePool struct {
 	Name         string                   `json:"name"`
 	Roles        []string                 `json:"roles"`
 	Labels       map[string]string        `json:"labels,omitempty"`
+	Taints       []NodePoolTaint          `json:"taints,omitempty"`
 	Subnet       PkeOnAzureNodePoolSubnet `json:"subnet,omitempty"`
 	Zones        []string                 `json:"zones,omitempty"`
 	Autoscaling  bool                     `json:"autoscaling,omitempty"`
@@ -12,5 +12,7 @@
 package client
 
 type UpdateEksPropertiesEks struct {
-	NodePools map[string]UpdateNodePoolsEks `json:"nodePools"`
+	// Kubernetes version to upgrade the cluster to. The control plane is upgraded first, then the node pools one by one. Node pools can be omitted when only the version is changed.
+	Version   string                        `json:"version,omitempty"`
+	NodePools map[string]UpdateNodePoolsEks `json:"nodePools,omitempty"`
 }
@@ -19,4 +19,5 @@ type UpdateNodePoolsAzure struct {
 	InstanceType string            `json:"instanceType,omitempty"`
 	Labels       map[string]string `json:"labels,omitempty"`
 	VnetSubnetID string            `json:"vnetSubnetID,omitempty"`
+	Taints       []NodePoolTaint   `json:"taints,omitempty"`
 }
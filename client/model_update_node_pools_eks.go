@@ -12,11 +12,12 @@
 package client
 
 type UpdateNodePoolsEks struct {
-	InstanceType string `json:"instanceType,omitempty"`
-	SpotPrice    string `json:"spotPrice,omitempty"`
-	Autoscaling  bool   `json:"autoscaling,omitempty"`
-	Count        int32  `json:"count,omitempty"`
-	MinCount     int32  `json:"minCount,omitempty"`
-	MaxCount     int32  `json:"maxCount,omitempty"`
-	Image        string `json:"image,omitempty"`
+	InstanceType string          `json:"instanceType,omitempty"`
+	SpotPrice    string          `json:"spotPrice,omitempty"`
+	Autoscaling  bool            `json:"autoscaling,omitempty"`
+	Count        int32           `json:"count,omitempty"`
+	MinCount     int32           `json:"minCount,omitempty"`
+	MaxCount     int32           `json:"maxCount,omitempty"`
+	Image        string          `json:"image,omitempty"`
+	Taints       []NodePoolTaint `json:"taints,omitempty"`
 }
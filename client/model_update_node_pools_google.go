@@ -12,9 +12,10 @@
 package client
 
 type UpdateNodePoolsGoogle struct {
-	Autoscaling  bool   `json:"autoscaling,omitempty"`
-	Count        int32  `json:"count"`
-	MinCount     int32  `json:"minCount,omitempty"`
-	MaxCount     int32  `json:"maxCount,omitempty"`
-	InstanceType string `json:"instanceType,omitempty"`
+	Autoscaling  bool            `json:"autoscaling,omitempty"`
+	Count        int32           `json:"count"`
+	MinCount     int32           `json:"minCount,omitempty"`
+	MaxCount     int32           `json:"maxCount,omitempty"`
+	InstanceType string          `json:"instanceType,omitempty"`
+	Taints       []NodePoolTaint `json:"taints,omitempty"`
 }
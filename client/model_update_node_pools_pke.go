@@ -25,5 +25,6 @@ type UpdateNodePoolsPke struct {
 	// If cluster autoscaler is not enabled this specifies the desired ndoe count in the node pool. If cluster autoscaler is enabled this specifies the initial node count in the ndoe pool.
 	Count int32 `json:"count,omitempty"`
 	// The subnet to create the node pool into. If this field is omitted than the subnet from the cluster level network configuration is used.
-	Subnets []string `json:"subnets,omitempty"`
+	Subnets []string        `json:"subnets,omitempty"`
+	Taints  []NodePoolTaint `json:"taints,omitempty"`
 }
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -257,8 +258,12 @@ func createACKNodePoolsFromRequest(pools ack.NodePools, userId uint) ([]*model.A
 			InstanceType: pool.InstanceType,
 			MinCount:     pool.MinCount,
 			MaxCount:     pool.MaxCount,
-			Count:        pool.MinCount,
+			Count:        pool.Count,
 			Labels:       pool.Labels,
+			Taints:       pool.Taints,
+		}
+		if res[i].Count < pool.MinCount {
+			res[i].Count = pool.MinCount
 		}
 		i++
 	}
@@ -293,7 +298,8 @@ func (c *ACKCluster) createACKNodePoolsModelFromUpdateRequestData(pools ack.Node
 		if currentNodePoolMap[nodePoolName] != nil {
 			if currentNodePoolMap[nodePoolName].MinCount != nodePool.MinCount ||
 				currentNodePoolMap[nodePoolName].MaxCount != nodePool.MaxCount ||
-				currentNodePoolMap[nodePoolName].InstanceType != nodePool.InstanceType {
+				currentNodePoolMap[nodePoolName].InstanceType != nodePool.InstanceType ||
+				!reflect.DeepEqual([]pkgCommon.NodePoolTaint(currentNodePoolMap[nodePoolName].Taints), nodePool.Taints) {
 				updatedNodePools = append(updatedNodePools, &model.ACKNodePoolModel{
 					ID:              currentNodePoolMap[nodePoolName].ID,
 					CreatedBy:       currentNodePoolMap[nodePoolName].CreatedBy,
@@ -306,6 +312,7 @@ func (c *ACKCluster) createACKNodePoolsModelFromUpdateRequestData(pools ack.Node
 					Count:           currentNodePoolMap[nodePoolName].Count,
 					AsgID:           currentNodePoolMap[nodePoolName].AsgID,
 					ScalingConfigID: currentNodePoolMap[nodePoolName].ScalingConfigID,
+					Taints:          nodePool.Taints,
 					Delete:          false,
 				})
 			}
@@ -325,6 +332,7 @@ func (c *ACKCluster) createACKNodePoolsModelFromUpdateRequestData(pools ack.Node
 				MinCount:     nodePool.MinCount,
 				MaxCount:     nodePool.MaxCount,
 				Count:        nodePool.MinCount,
+				Taints:       nodePool.Taints,
 				Delete:       false,
 			})
 		}
@@ -631,6 +639,7 @@ func (c *ACKCluster) GetStatus() (*pkgCluster.GetClusterStatusResponse, error) {
 				MaxCount:          np.MaxCount,
 				Count:             np.Count,
 				Labels:            np.Labels,
+				Taints:            np.Taints,
 			}
 		}
 	}
@@ -817,6 +826,7 @@ func (c *ACKCluster) CheckEqualityToUpdate(r *pkgCluster.UpdateClusterRequest) e
 			InstanceType: preNp.InstanceType,
 			MinCount:     preNp.MinCount,
 			MaxCount:     preNp.MaxCount,
+			Taints:       preNp.Taints,
 		}
 	}
 
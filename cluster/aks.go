@@ -74,6 +74,7 @@ func CreateAKSClusterFromRequest(request *pkgCluster.CreateClusterRequest, orgID
 			NodeInstanceType: np.NodeInstanceType,
 			VNetSubnetID:     np.VNetSubnetID,
 			Labels:           np.Labels,
+			Taints:           np.Taints,
 		})
 	}
 
@@ -489,6 +490,7 @@ func (c *AKSCluster) GetStatus() (*pkgCluster.GetClusterStatusResponse, error) {
 				MaxCount:          np.NodeMaxCount,
 				CreatorBaseFields: *NewCreatorBaseFields(np.CreatedAt, np.CreatedBy),
 				Labels:            np.Labels,
+				Taints:            np.Taints,
 			}
 		}
 	}
@@ -587,6 +589,7 @@ func (c *AKSCluster) UpdateCluster(request *pkgCluster.UpdateClusterRequest, use
 				npm.NodeMinCount = np.MinCount
 				npm.NodeMaxCount = np.MaxCount
 				npm.Count = int(*app.Count)
+				npm.Taints = np.Taints
 			}
 		}
 	}
@@ -724,6 +727,7 @@ func (c *AKSCluster) CheckEqualityToUpdate(r *pkgCluster.UpdateClusterRequest) e
 				MinCount:    preP.NodeMinCount,
 				MaxCount:    preP.NodeMaxCount,
 				Count:       preP.Count,
+				Taints:      preP.Taints,
 			}
 		}
 	}
@@ -0,0 +1,377 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+
+	"emperror.dev/errors"
+	"github.com/aws/aws-sdk-go/aws"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgClusterACK "github.com/banzaicloud/pipeline/pkg/cluster/ack"
+	pkgClusterAzure "github.com/banzaicloud/pipeline/pkg/cluster/aks"
+	pkgClusterDummy "github.com/banzaicloud/pipeline/pkg/cluster/dummy"
+	pkgEks "github.com/banzaicloud/pipeline/pkg/cluster/eks"
+	pkgClusterGoogle "github.com/banzaicloud/pipeline/pkg/cluster/gke"
+	pkgClusterPKE "github.com/banzaicloud/pipeline/pkg/cluster/pke"
+	oracle "github.com/banzaicloud/pipeline/pkg/providers/oracle/cluster"
+)
+
+// CloneOptions contains the settings of a cloned cluster which differ from the ones of the source cluster.
+type CloneOptions struct {
+	// Name of the new cluster (required)
+	Name string
+
+	// Location of the new cluster (defaults to the location of the source cluster)
+	Location string
+
+	// SecretID of the new cluster (defaults to the secret of the source cluster)
+	SecretID string
+
+	// PostHooks to run on the new cluster (defaults to the ones reconstructed from the source cluster)
+	PostHooks pkgCluster.PostHooks
+
+	// NodePoolLabels are the user defined node pool labels of the source cluster (see GetNodePoolUserLabels)
+	NodePoolLabels map[string]map[string]string
+}
+
+// NewCloneRequest reconstructs a create request from the stored model of a cluster.
+// Provider resources bound to the location of the source cluster (eg. images, existing networks) are left to defaults
+// when the clone is created in a different location.
+func NewCloneRequest(commonCluster CommonCluster, options CloneOptions) (*pkgCluster.CreateClusterRequest, error) {
+	if options.Name == "" {
+		return nil, &invalidError{errors.New("name of the new cluster is required")}
+	}
+
+	location := commonCluster.GetLocation()
+	relocate := options.Location != "" && options.Location != location
+	if relocate {
+		location = options.Location
+	}
+
+	secretID := options.SecretID
+	if secretID == "" {
+		secretID = commonCluster.GetSecretId()
+	}
+
+	request := &pkgCluster.CreateClusterRequest{
+		Name:         options.Name,
+		Location:     location,
+		Cloud:        commonCluster.GetCloud(),
+		SecretId:     secretID,
+		PostHooks:    options.PostHooks,
+		Properties:   &pkgCluster.CreateClusterProperties{},
+		ScaleOptions: commonCluster.GetScaleOptions(),
+		TtlMinutes:   uint(commonCluster.GetTTL().Minutes()),
+	}
+
+	if request.PostHooks == nil {
+		request.PostHooks = clonePostHooks(commonCluster)
+	}
+
+	labels := func(nodePoolName string) map[string]string {
+		return options.NodePoolLabels[nodePoolName]
+	}
+
+	switch c := commonCluster.(type) {
+	case *EKSCluster:
+		request.Properties.CreateClusterEKS = cloneEKSProperties(c, relocate, labels)
+
+	case *AKSCluster:
+		request.Properties.CreateClusterAKS = cloneAKSProperties(c, relocate, labels)
+
+	case *GKECluster:
+		request.Properties.CreateClusterGKE = cloneGKEProperties(c, relocate, labels)
+
+	case *ACKCluster:
+		if relocate {
+			return nil, &invalidError{errors.New("ACK clusters cannot be cloned to a different location")}
+		}
+
+		request.Properties.CreateClusterACK = cloneACKProperties(c, labels)
+
+	case *OKECluster:
+		request.Properties.CreateClusterOKE = cloneOKEProperties(c, labels)
+
+	case *EC2ClusterPKE:
+		if relocate {
+			return nil, &invalidError{errors.New("PKE clusters cannot be cloned to a different location")}
+		}
+
+		request.Properties.CreateClusterPKE = clonePKEProperties(c, labels)
+
+	case *DummyCluster:
+		request.Properties.CreateClusterDummy = &pkgClusterDummy.CreateClusterDummy{
+			Node: &pkgClusterDummy.Node{
+				KubernetesVersion: c.modelCluster.Dummy.KubernetesVersion,
+				Count:             c.modelCluster.Dummy.NodeCount,
+			},
+		}
+
+	default:
+		return nil, &invalidError{
+			fmt.Errorf("cloning %s clusters is not supported", commonCluster.GetDistribution()),
+		}
+	}
+
+	return request, nil
+}
+
+// clonePostHooks returns the post hooks which installed the optional components of a cluster.
+// Logging is not included, because its storage settings are not stored.
+func clonePostHooks(commonCluster CommonCluster) pkgCluster.PostHooks {
+	postHooks := make(pkgCluster.PostHooks)
+
+	if commonCluster.GetMonitoring() {
+		postHooks[pkgCluster.InstallMonitoring] = nil
+	}
+
+	if commonCluster.GetServiceMesh() {
+		postHooks[pkgCluster.InstallServiceMesh] = InstallServiceMeshParams{}
+	}
+
+	if commonCluster.GetSecurityScan() {
+		postHooks[pkgCluster.InstallAnchoreImageValidator] = pkgCluster.AnchoreParam{}
+	}
+
+	return postHooks
+}
+
+func cloneEKSProperties(c *EKSCluster, relocate bool, labels func(string) map[string]string) *pkgEks.CreateClusterEKS {
+	eksModel := c.modelCluster.EKS
+
+	properties := &pkgEks.CreateClusterEKS{
+		Version:   eksModel.Version,
+		NodePools: make(map[string]*pkgEks.NodePool, len(eksModel.NodePools)),
+		Vpc:       &pkgEks.ClusterVPC{},
+	}
+
+	for _, np := range eksModel.NodePools {
+		nodePool := &pkgEks.NodePool{
+			InstanceType: np.NodeInstanceType,
+			SpotPrice:    np.NodeSpotPrice,
+			Autoscaling:  np.Autoscaling,
+			MinCount:     np.NodeMinCount,
+			MaxCount:     np.NodeMaxCount,
+			Count:        np.Count,
+			Image:        np.NodeImage,
+			Labels:       labels(np.Name),
+			Taints:       np.Taints,
+		}
+
+		// images are region specific, let the defaults pick the ones of the new region
+		if relocate {
+			nodePool.Image = ""
+		}
+
+		properties.NodePools[np.Name] = nodePool
+	}
+
+	// EKS clusters are created either in an existing VPC and subnets (referenced by ID)
+	// or in a new VPC and subnets (given by CIDR), IDs and CIDRs cannot be mixed.
+	// Existing VPCs and subnets are region specific and new subnets cannot be created with the same ranges
+	// in the same VPC, so clones of clusters using an existing VPC with new subnets get a new default VPC.
+	vpcID := aws.StringValue(eksModel.VpcId)
+	vpcCidr := aws.StringValue(eksModel.VpcCidr)
+
+	subnetIDs := make([]string, 0, len(eksModel.Subnets))
+	subnetCidrs := make([]string, 0, len(eksModel.Subnets))
+	for _, subnet := range eksModel.Subnets {
+		if id := aws.StringValue(subnet.SubnetId); id != "" {
+			subnetIDs = append(subnetIDs, id)
+		} else if cidr := aws.StringValue(subnet.Cidr); cidr != "" {
+			subnetCidrs = append(subnetCidrs, cidr)
+		}
+	}
+
+	switch {
+	case vpcID != "" && !relocate && len(subnetIDs) > 0 && len(subnetCidrs) == 0:
+		properties.Vpc.VpcId = vpcID
+		for _, id := range subnetIDs {
+			properties.Subnets = append(properties.Subnets, &pkgEks.ClusterSubnet{SubnetId: id})
+		}
+
+	case vpcID == "" && vpcCidr != "":
+		properties.Vpc.Cidr = vpcCidr
+		for _, cidr := range subnetCidrs {
+			properties.Subnets = append(properties.Subnets, &pkgEks.ClusterSubnet{Cidr: cidr})
+		}
+
+	default:
+		// let the defaults create a new VPC
+		properties.Vpc = nil
+	}
+
+	return properties
+}
+
+func cloneAKSProperties(c *AKSCluster, relocate bool, labels func(string) map[string]string) *pkgClusterAzure.CreateClusterAKS {
+	aksModel := c.modelCluster.AKS
+
+	properties := &pkgClusterAzure.CreateClusterAKS{
+		ResourceGroup:     aksModel.ResourceGroup,
+		KubernetesVersion: aksModel.KubernetesVersion,
+		NodePools:         make(map[string]*pkgClusterAzure.NodePoolCreate, len(aksModel.NodePools)),
+	}
+
+	for _, np := range aksModel.NodePools {
+		nodePool := &pkgClusterAzure.NodePoolCreate{
+			Autoscaling:      np.Autoscaling,
+			MinCount:         np.NodeMinCount,
+			MaxCount:         np.NodeMaxCount,
+			Count:            np.Count,
+			NodeInstanceType: np.NodeInstanceType,
+			VNetSubnetID:     np.VNetSubnetID,
+			Labels:           labels(np.Name),
+			Taints:           np.Taints,
+		}
+
+		// virtual networks are region specific
+		if relocate {
+			nodePool.VNetSubnetID = ""
+		}
+
+		properties.NodePools[np.Name] = nodePool
+	}
+
+	return properties
+}
+
+func cloneGKEProperties(c *GKECluster, relocate bool, labels func(string) map[string]string) *pkgClusterGoogle.CreateClusterGKE {
+	properties := &pkgClusterGoogle.CreateClusterGKE{
+		NodeVersion: c.model.NodeVersion,
+		NodePools:   make(map[string]*pkgClusterGoogle.NodePool, len(c.model.NodePools)),
+		Master: &pkgClusterGoogle.Master{
+			Version: c.model.MasterVersion,
+		},
+		ProjectId: c.model.ProjectId,
+	}
+
+	// subnets are region specific
+	if !relocate {
+		properties.Vpc = c.model.Vpc
+		properties.Subnet = c.model.Subnet
+	}
+
+	for _, np := range c.model.NodePools {
+		properties.NodePools[np.Name] = &pkgClusterGoogle.NodePool{
+			Autoscaling:      np.Autoscaling,
+			MinCount:         np.NodeMinCount,
+			MaxCount:         np.NodeMaxCount,
+			Count:            np.NodeCount,
+			NodeInstanceType: np.NodeInstanceType,
+			Preemptible:      np.Preemptible,
+			Labels:           labels(np.Name),
+			Taints:           np.Taints,
+		}
+	}
+
+	return properties
+}
+
+func cloneACKProperties(c *ACKCluster, labels func(string) map[string]string) *pkgClusterACK.CreateClusterACK {
+	ackModel := c.modelCluster.ACK
+
+	properties := &pkgClusterACK.CreateClusterACK{
+		RegionID:                 ackModel.RegionID,
+		ZoneID:                   ackModel.ZoneID,
+		MasterInstanceType:       ackModel.MasterInstanceType,
+		MasterSystemDiskCategory: ackModel.MasterSystemDiskCategory,
+		MasterSystemDiskSize:     ackModel.MasterSystemDiskSize,
+		NodePools:                make(pkgClusterACK.NodePools, len(ackModel.NodePools)),
+		VSwitchID:                ackModel.VSwitchID,
+	}
+
+	for _, np := range ackModel.NodePools {
+		properties.NodePools[np.Name] = &pkgClusterACK.NodePool{
+			InstanceType: np.InstanceType,
+			MinCount:     np.MinCount,
+			MaxCount:     np.MaxCount,
+			Count:        np.Count,
+			Labels:       labels(np.Name),
+			Taints:       np.Taints,
+		}
+	}
+
+	return properties
+}
+
+// cloneOKEProperties leaves the network settings empty, as every OKE cluster gets its own preconfigured VCN.
+func cloneOKEProperties(c *OKECluster, labels func(string) map[string]string) *oracle.Cluster {
+	okeModel := c.modelCluster.OKE
+
+	properties := &oracle.Cluster{
+		Version:   okeModel.Version,
+		NodePools: make(map[string]*oracle.NodePool, len(okeModel.NodePools)),
+	}
+
+	for _, np := range okeModel.NodePools {
+		properties.NodePools[np.Name] = &oracle.NodePool{
+			Version: np.Version,
+			Count:   np.QuantityPerSubnet * uint(len(np.Subnets)),
+			Labels:  labels(np.Name),
+			Image:   np.Image,
+			Shape:   np.Shape,
+		}
+	}
+
+	return properties
+}
+
+// clonePKEProperties copies the topology of a PKE cluster without its hosts and the address of its API server.
+func clonePKEProperties(c *EC2ClusterPKE, labels func(string) map[string]string) *pkgClusterPKE.CreateClusterPKE {
+	properties := &pkgClusterPKE.CreateClusterPKE{
+		Network: pkgClusterPKE.Network{
+			ServiceCIDR: c.model.Network.ServiceCIDR,
+			PodCIDR:     c.model.Network.PodCIDR,
+			Provider:    pkgClusterPKE.NetworkProvider(c.model.Network.Provider),
+		},
+		Kubernetes: pkgClusterPKE.Kubernetes{
+			Version: c.model.Kubernetes.Version,
+			RBAC: pkgClusterPKE.RBAC{
+				Enabled: c.model.Kubernetes.RBAC.Enabled,
+			},
+		},
+		CRI: pkgClusterPKE.CRI{
+			Runtime:       pkgClusterPKE.Runtime(c.model.CRI.Runtime),
+			RuntimeConfig: c.model.CRI.RuntimeConfig,
+		},
+		DexEnabled: c.model.DexEnabled,
+	}
+
+	for _, extraArg := range c.model.KubeADM.ExtraArgs {
+		properties.KubeADM.ExtraArgs = append(properties.KubeADM.ExtraArgs, pkgClusterPKE.ExtraArg(extraArg))
+	}
+
+	for _, np := range c.model.NodePools {
+		nodePool := pkgClusterPKE.NodePool{
+			Name:           np.Name,
+			Provider:       pkgClusterPKE.NodePoolProvider(np.Provider),
+			ProviderConfig: np.ProviderConfig,
+			Labels:         labels(np.Name),
+			Taints:         np.Taints,
+			Autoscaling:    np.Autoscaling,
+		}
+
+		for _, role := range np.Roles {
+			nodePool.Roles = append(nodePool.Roles, pkgClusterPKE.Role(role))
+		}
+
+		properties.NodePools = append(properties.NodePools, nodePool)
+	}
+
+	return properties
+}
@@ -0,0 +1,238 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgClusterACK "github.com/banzaicloud/pipeline/pkg/cluster/ack"
+	pkgClusterDummy "github.com/banzaicloud/pipeline/pkg/cluster/dummy"
+	pkgEks "github.com/banzaicloud/pipeline/pkg/cluster/eks"
+)
+
+func TestNewCloneRequest_Dummy(t *testing.T) {
+	source, err := CreateDummyClusterFromRequest(&pkgCluster.CreateClusterRequest{
+		Name:     "source",
+		Location: "dummy-location",
+		Cloud:    pkgCluster.Dummy,
+		SecretId: "secret",
+		Properties: &pkgCluster.CreateClusterProperties{
+			CreateClusterDummy: &pkgClusterDummy.CreateClusterDummy{
+				Node: &pkgClusterDummy.Node{
+					KubernetesVersion: "1.14.0",
+					Count:             3,
+				},
+			},
+		},
+		TtlMinutes: 60,
+	}, 1, 1)
+	require.NoError(t, err)
+
+	source.SetMonitoring(true)
+
+	request, err := NewCloneRequest(source, CloneOptions{Name: "clone"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "clone", request.Name)
+	assert.Equal(t, "dummy-location", request.Location)
+	assert.Equal(t, "secret", request.SecretId)
+	assert.Equal(t, uint(60), request.TtlMinutes)
+	assert.Contains(t, request.PostHooks, pkgCluster.InstallMonitoring)
+	assert.Equal(t, &pkgClusterDummy.Node{KubernetesVersion: "1.14.0", Count: 3}, request.Properties.CreateClusterDummy.Node)
+
+	clone, err := CreateDummyClusterFromRequest(request, 1, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, source.modelCluster.Dummy, clone.modelCluster.Dummy)
+}
+
+func TestNewCloneRequest_NameRequired(t *testing.T) {
+	source, err := CreateDummyClusterFromRequest(&pkgCluster.CreateClusterRequest{
+		Name: "source",
+		Properties: &pkgCluster.CreateClusterProperties{
+			CreateClusterDummy: &pkgClusterDummy.CreateClusterDummy{Node: &pkgClusterDummy.Node{}},
+		},
+	}, 1, 1)
+	require.NoError(t, err)
+
+	_, err = NewCloneRequest(source, CloneOptions{})
+	require.Error(t, err)
+
+	var invalid interface{ IsInvalid() bool }
+	assert.True(t, errors.As(err, &invalid))
+}
+
+func newEKSSourceCluster(t *testing.T, vpc *pkgEks.ClusterVPC, routeTableID string, subnets []*pkgEks.ClusterSubnet) *EKSCluster {
+	source, err := CreateEKSClusterFromRequest(&pkgCluster.CreateClusterRequest{
+		Name:     "source",
+		Location: "us-east-2",
+		Cloud:    pkgCluster.Amazon,
+		SecretId: "secret",
+		Properties: &pkgCluster.CreateClusterProperties{
+			CreateClusterEKS: &pkgEks.CreateClusterEKS{
+				Version: "1.13.7",
+				NodePools: map[string]*pkgEks.NodePool{
+					"pool1": {
+						InstanceType: "m4.xlarge",
+						MinCount:     1,
+						MaxCount:     3,
+						Count:        2,
+						Image:        "ami-12345",
+					},
+				},
+				Vpc:          vpc,
+				RouteTableId: routeTableID,
+				Subnets:      subnets,
+			},
+		},
+	}, 1, 1)
+	require.NoError(t, err)
+
+	return source
+}
+
+// validateCloneRequest validates a clone request the same way the cluster API validates create requests
+func validateCloneRequest(t *testing.T, request *pkgCluster.CreateClusterRequest) {
+	require.NoError(t, request.AddDefaults())
+	require.NoError(t, request.Validate())
+}
+
+func TestNewCloneRequest_EKS(t *testing.T) {
+	labels := map[string]map[string]string{
+		"pool1": {"team": "backend"},
+	}
+
+	t.Run("existing VPC", func(t *testing.T) {
+		source := newEKSSourceCluster(
+			t,
+			&pkgEks.ClusterVPC{VpcId: "vpc-12345"},
+			"",
+			[]*pkgEks.ClusterSubnet{{SubnetId: "subnet-12345"}, {SubnetId: "subnet-67890"}},
+		)
+
+		request, err := NewCloneRequest(source, CloneOptions{Name: "clone", NodePoolLabels: labels})
+		require.NoError(t, err)
+
+		properties := request.Properties.CreateClusterEKS
+		assert.Equal(t, "1.13.7", properties.Version)
+		assert.Equal(t, &pkgEks.ClusterVPC{VpcId: "vpc-12345"}, properties.Vpc)
+		assert.Empty(t, properties.RouteTableId)
+		assert.Equal(t, []*pkgEks.ClusterSubnet{{SubnetId: "subnet-12345"}, {SubnetId: "subnet-67890"}}, properties.Subnets)
+		assert.Equal(t, &pkgEks.NodePool{
+			InstanceType: "m4.xlarge",
+			MinCount:     1,
+			MaxCount:     3,
+			Count:        2,
+			Image:        "ami-12345",
+			Labels:       map[string]string{"team": "backend"},
+		}, properties.NodePools["pool1"])
+
+		validateCloneRequest(t, request)
+	})
+
+	t.Run("new VPC", func(t *testing.T) {
+		source := newEKSSourceCluster(
+			t,
+			&pkgEks.ClusterVPC{Cidr: "192.168.0.0/16"},
+			"",
+			[]*pkgEks.ClusterSubnet{{Cidr: "192.168.64.0/20"}, {Cidr: "192.168.80.0/20"}},
+		)
+
+		for _, location := range []string{"", "eu-west-1"} {
+			request, err := NewCloneRequest(source, CloneOptions{Name: "clone", Location: location})
+			require.NoError(t, err)
+
+			properties := request.Properties.CreateClusterEKS
+			assert.Equal(t, &pkgEks.ClusterVPC{Cidr: "192.168.0.0/16"}, properties.Vpc)
+			assert.Equal(t, []*pkgEks.ClusterSubnet{{Cidr: "192.168.64.0/20"}, {Cidr: "192.168.80.0/20"}}, properties.Subnets)
+
+			validateCloneRequest(t, request)
+		}
+	})
+
+	t.Run("new subnets in existing VPC", func(t *testing.T) {
+		source := newEKSSourceCluster(
+			t,
+			&pkgEks.ClusterVPC{VpcId: "vpc-12345"},
+			"rtb-12345",
+			[]*pkgEks.ClusterSubnet{{Cidr: "192.168.64.0/20"}, {Cidr: "192.168.80.0/20"}},
+		)
+
+		request, err := NewCloneRequest(source, CloneOptions{Name: "clone"})
+		require.NoError(t, err)
+
+		properties := request.Properties.CreateClusterEKS
+		assert.Nil(t, properties.Vpc)
+		assert.Empty(t, properties.RouteTableId)
+		assert.Empty(t, properties.Subnets)
+
+		validateCloneRequest(t, request)
+	})
+
+	t.Run("different location", func(t *testing.T) {
+		source := newEKSSourceCluster(
+			t,
+			&pkgEks.ClusterVPC{VpcId: "vpc-12345"},
+			"",
+			[]*pkgEks.ClusterSubnet{{SubnetId: "subnet-12345"}, {SubnetId: "subnet-67890"}},
+		)
+
+		request, err := NewCloneRequest(source, CloneOptions{Name: "clone", Location: "eu-west-1"})
+		require.NoError(t, err)
+
+		properties := request.Properties.CreateClusterEKS
+		assert.Equal(t, "eu-west-1", request.Location)
+		assert.Nil(t, properties.Vpc)
+		assert.Empty(t, properties.RouteTableId)
+		assert.Empty(t, properties.Subnets)
+		assert.Empty(t, properties.NodePools["pool1"].Image)
+
+		validateCloneRequest(t, request)
+	})
+}
+
+func TestNewCloneRequest_ACK(t *testing.T) {
+	source, err := CreateACKClusterFromRequest(&pkgCluster.CreateClusterRequest{
+		Name:     "source",
+		Cloud:    pkgCluster.Alibaba,
+		SecretId: "secret",
+		Properties: &pkgCluster.CreateClusterProperties{
+			CreateClusterACK: &pkgClusterACK.CreateClusterACK{
+				RegionID: "eu-central-1",
+				ZoneID:   "eu-central-1a",
+				NodePools: pkgClusterACK.NodePools{
+					"pool1": {InstanceType: "ecs.sn1ne.large", MinCount: 1, MaxCount: 3, Count: 2},
+				},
+			},
+		},
+	}, 1, 1)
+	require.NoError(t, err)
+
+	request, err := NewCloneRequest(source, CloneOptions{Name: "clone"})
+	require.NoError(t, err)
+	require.NoError(t, request.Validate())
+
+	assert.Equal(t, &pkgClusterACK.NodePool{
+		InstanceType: "ecs.sn1ne.large",
+		MinCount:     1,
+		MaxCount:     3,
+		Count:        2,
+	}, request.Properties.CreateClusterACK.NodePools["pool1"])
+}
@@ -484,6 +484,7 @@ func getNodePoolsFromUpdateRequest(updateRequest *pkgCluster.UpdateClusterReques
 				MinCount:     np.MinCount,
 				MaxCount:     np.MaxCount,
 				SpotPrice:    np.SpotPrice,
+				Taints:       np.Taints,
 				// Labels:       np.Labels,
 			}
 		}
@@ -499,6 +500,7 @@ func getNodePoolsFromUpdateRequest(updateRequest *pkgCluster.UpdateClusterReques
 					MinCount:     np.MinCount,
 					MaxCount:     np.MaxCount,
 					Labels:       np.Labels,
+					Taints:       np.Taints,
 				}
 			}
 		}
@@ -513,6 +515,7 @@ func getNodePoolsFromUpdateRequest(updateRequest *pkgCluster.UpdateClusterReques
 					MaxCount:     np.MaxCount,
 					SpotPrice:    np.SpotPrice,
 					Labels:       np.Labels,
+					Taints:       np.Taints,
 				}
 			}
 		}
@@ -525,6 +528,7 @@ func getNodePoolsFromUpdateRequest(updateRequest *pkgCluster.UpdateClusterReques
 					MinCount: np.MinCount,
 					MaxCount: np.MaxCount,
 					Labels:   np.Labels,
+					Taints:   np.Taints,
 				}
 			}
 		}
@@ -539,6 +543,7 @@ func getNodePoolsFromUpdateRequest(updateRequest *pkgCluster.UpdateClusterReques
 					MaxCount:     np.MaxCount,
 					Preemptible:  np.Preemptible,
 					Labels:       np.Labels,
+					Taints:       np.Taints,
 				}
 			}
 		}
@@ -22,7 +22,9 @@ import (
 	"encoding/pem"
 	"fmt"
 	"net"
+	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	"emperror.dev/emperror"
@@ -521,6 +523,12 @@ func (c *EC2ClusterPKE) UpdatePKECluster(ctx context.Context, request *pkgCluste
 			providerConfig.AutoScalingGroup.Size.Max = reqNodePool.MaxCount
 			np.ProviderConfig["autoScalingGroup"] = providerConfig.AutoScalingGroup
 
+			// the launch configuration of the pool registers new nodes with the taints the pool was created with
+			taints := request.PKE.NodePools[np.Name].Taints
+			if (len(np.Taints) > 0 || len(taints) > 0) && !reflect.DeepEqual([]common.NodePoolTaint(np.Taints), taints) {
+				return errors.Errorf("taints of node pool %q cannot be changed", np.Name)
+			}
+
 			newModelNodePools = append(newModelNodePools, np)
 		} else {
 			deletedModelNodePools = append(deletedModelNodePools, np)
@@ -551,6 +559,7 @@ func (c *EC2ClusterPKE) UpdatePKECluster(ctx context.Context, request *pkgCluste
 				Provider:    internalPke.NPPAmazon,
 				ProviderConfig: internalPke.Config{
 					"autoScalingGroup": providerConfig.AutoScalingGroup},
+				Taints: request.PKE.NodePools[np.Name].Taints,
 			}
 			newModelNodePools = append(newModelNodePools, modelNodepool)
 		}
@@ -709,6 +718,7 @@ func (c *EC2ClusterPKE) GetStatus() (*pkgCluster.GetClusterStatusResponse, error
 			InstanceType:      providerConfig.AutoScalingGroup.InstanceType,
 			SpotPrice:         providerConfig.AutoScalingGroup.SpotPrice,
 			CreatorBaseFields: *NewCreatorBaseFields(np.CreatedAt, np.CreatedBy),
+			Taints:            np.Taints,
 		}
 
 		if p, err := strconv.ParseFloat(providerConfig.AutoScalingGroup.SpotPrice, 64); err == nil && p > 0.0 {
@@ -984,7 +994,7 @@ func (c *EC2ClusterPKE) GetBootstrapCommand(nodePoolName, url string, urlInsecur
 	}
 
 	// worker
-	return fmt.Sprintf("pke install %s "+
+	command := fmt.Sprintf("pke install %s "+
 		"--pipeline-url=%q "+
 		"--pipeline-insecure=%q "+
 		"--pipeline-token=%q "+
@@ -1003,7 +1013,14 @@ func (c *EC2ClusterPKE) GetBootstrapCommand(nodePoolName, url string, urlInsecur
 		nodePoolName,
 		version,
 		infrastructureCIDR,
-	), nil
+	)
+
+	// node pool taints are registered by kubelet, so nodes added by the autoscaler get them as well
+	if len(np.Taints) > 0 {
+		command = fmt.Sprintf("%s --taints=%q", command, strings.Join(np.Taints.Strings(), ","))
+	}
+
+	return command, nil
 }
 
 func (c *EC2ClusterPKE) GetKubernetesVersion() (string, error) {
@@ -1157,6 +1174,7 @@ func createEC2ClusterPKENodePoolsFromRequest(pools pke.NodePools, userId uint) i
 			Provider:       convertNodePoolProvider(pool.Provider),
 			ProviderConfig: pool.ProviderConfig,
 			Labels:         pool.Labels,
+			Taints:         pool.Taints,
 			Autoscaling:    pool.Autoscaling,
 		}
 		np.CreatedBy = userId
@@ -0,0 +1,382 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	pipConfig "github.com/banzaicloud/pipeline/config"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterupgrade"
+	internalPke "github.com/banzaicloud/pipeline/internal/providers/pke"
+	"github.com/banzaicloud/pipeline/internal/providers/pke/pkeworkflow"
+	"github.com/banzaicloud/pipeline/pkg/k8sclient"
+)
+
+const (
+	pkeMasterNodeLabel       = "node-role.kubernetes.io/master"
+	pkeUpgradeJobImage       = "busybox:1.31"
+	pkeUpgradePollInterval   = 10 * time.Second
+	pkeUpgradeMasterTimeout  = 30 * time.Minute
+	pkeKubernetesVersionFlag = "--kubernetes-version"
+)
+
+// nolint: gochecknoglobals
+var pkeKubernetesVersionFlagRegexp = regexp.MustCompile(pkeKubernetesVersionFlag + `="[^"]*"`)
+
+// ListWorkerNodePools returns the names of the worker node pools of the cluster.
+func (c *EC2ClusterPKE) ListWorkerNodePools() []string {
+	var nodePools []string
+	for _, np := range c.model.NodePools {
+		if isPKEMasterNodePool(np) {
+			continue
+		}
+
+		nodePools = append(nodePools, np.Name)
+	}
+
+	sort.Strings(nodePools)
+
+	return nodePools
+}
+
+func (c *EC2ClusterPKE) reloadModel() error {
+	m := internalPke.EC2PKEClusterModel{
+		ClusterID: c.model.ClusterID,
+	}
+
+	err := c.db.Where(m).
+		Preload("Cluster").
+		Preload("Network").
+		Preload("NodePools").
+		Preload("Kubernetes").
+		Preload("KubeADM").
+		Preload("CRI").
+		First(&m).
+		Error
+	if err != nil {
+		return errors.WrapIf(err, "failed to load cluster from database")
+	}
+
+	*c.model = m
+
+	return nil
+}
+
+func isPKEMasterNodePool(np internalPke.NodePool) bool {
+	for _, role := range np.Roles {
+		if role == "master" {
+			return true
+		}
+	}
+
+	return np.Name == "master"
+}
+
+// UpgradeControlPlane upgrades the master nodes of the cluster one by one by running pke on them.
+func (c *EC2ClusterPKE) UpgradeControlPlane(ctx context.Context, version string) error {
+	kubeConfig, err := c.GetK8sConfig()
+	if err != nil {
+		return err
+	}
+
+	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	if err != nil {
+		return err
+	}
+
+	nodes, err := client.CoreV1().Nodes().List(metav1.ListOptions{LabelSelector: pkeMasterNodeLabel})
+	if err != nil {
+		return errors.WrapIf(err, "failed to list master nodes")
+	}
+
+	if len(nodes.Items) == 0 {
+		return errors.New("no master nodes found")
+	}
+
+	for _, node := range nodes.Items {
+		if node.Status.NodeInfo.KubeletVersion == "v"+strings.TrimPrefix(version, "v") {
+			c.log.WithField("node", node.Name).Info("master node is already upgraded")
+
+			continue
+		}
+
+		c.log.WithField("node", node.Name).Infof("upgrading master node to %s", version)
+
+		if err := runPKEUpgradeJob(ctx, client, node.Name, version); err != nil {
+			return err
+		}
+	}
+
+	c.model.Kubernetes.Version = version
+
+	return errors.WrapIf(c.db.Save(&c.model.Kubernetes).Error, "failed to save Kubernetes version")
+}
+
+// runPKEUpgradeJob runs pke upgrade in the host namespaces of a master node and waits for it to finish.
+func runPKEUpgradeJob(ctx context.Context, client kubernetes.Interface, nodeName string, version string) error {
+	namespace := viper.GetString(pipConfig.PipelineSystemNamespace)
+	jobName := fmt.Sprintf("pke-upgrade-%s", nodeName)
+	if len(jobName) > 63 {
+		jobName = jobName[:63]
+	}
+
+	privileged := true
+	backoffLimit := int32(0)
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName,
+			Namespace: namespace,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					NodeName:      nodeName,
+					HostPID:       true,
+					RestartPolicy: corev1.RestartPolicyNever,
+					Tolerations: []corev1.Toleration{
+						{Operator: corev1.TolerationOpExists},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:  "pke-upgrade",
+							Image: pkeUpgradeJobImage,
+							Command: []string{
+								"nsenter", "-t", "1", "-m", "-u", "-i", "-n", "-p", "--",
+								"pke", "upgrade", "master", fmt.Sprintf("%s=%s", pkeKubernetesVersionFlag, version),
+							},
+							SecurityContext: &corev1.SecurityContext{
+								Privileged: &privileged,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	propagation := metav1.DeletePropagationBackground
+	deleteOptions := &metav1.DeleteOptions{PropagationPolicy: &propagation}
+
+	// remove the job of a previous attempt
+	err := client.BatchV1().Jobs(namespace).Delete(jobName, deleteOptions)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return errors.WrapIfWithDetails(err, "failed to delete previous upgrade job", "job", jobName)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pkeUpgradeMasterTimeout)
+	defer cancel()
+
+	for {
+		_, err := client.BatchV1().Jobs(namespace).Create(job)
+		if err == nil {
+			break
+		}
+
+		// the job of the previous attempt is still being deleted
+		if !k8serrors.IsAlreadyExists(err) {
+			return errors.WrapIfWithDetails(err, "failed to create upgrade job", "job", jobName)
+		}
+
+		if err := sleepContext(ctx, pkeUpgradePollInterval); err != nil {
+			return errors.WrapIfWithDetails(err, "failed to create upgrade job", "job", jobName)
+		}
+	}
+
+	for {
+		if err := sleepContext(ctx, pkeUpgradePollInterval); err != nil {
+			return errors.WrapIfWithDetails(err, "waiting for upgrade job timed out", "job", jobName, "node", nodeName)
+		}
+
+		// the API server may be unavailable while it is being upgraded
+		current, err := client.BatchV1().Jobs(namespace).Get(jobName, metav1.GetOptions{})
+		if err != nil {
+			continue
+		}
+
+		if current.Status.Succeeded > 0 {
+			break
+		}
+
+		if current.Status.Failed > 0 {
+			return errors.NewWithDetails("upgrade job failed", "job", jobName, "node", nodeName)
+		}
+	}
+
+	return errors.WrapIfWithDetails(client.BatchV1().Jobs(namespace).Delete(jobName, deleteOptions), "failed to delete upgrade job", "job", jobName)
+}
+
+// UpgradeNodePool updates the worker stack of a node pool to join new nodes with the given Kubernetes version,
+// then replaces its nodes one by one.
+func (c *EC2ClusterPKE) UpgradeNodePool(ctx context.Context, nodePoolName string, fromVersion string, version string, maxSurge int) error {
+	var nodePool *internalPke.NodePool
+	for i := range c.model.NodePools {
+		if c.model.NodePools[i].Name == nodePoolName {
+			nodePool = &c.model.NodePools[i]
+		}
+	}
+
+	if nodePool == nil {
+		return errors.NewWithDetails("node pool not found", "nodePool", nodePoolName)
+	}
+
+	providerConfig := internalPke.NodePoolProviderConfigAmazon{}
+	if err := mapstructure.Decode(nodePool.ProviderConfig, &providerConfig); err != nil {
+		return errors.WrapIfWithDetails(err, "decoding node pool config", "nodePool", nodePoolName)
+	}
+
+	logger := c.log.WithField("nodePool", nodePoolName)
+
+	awsSession, err := c.GetAWSClient()
+	if err != nil {
+		return err
+	}
+
+	cloudformationSvc := cloudformation.New(awsSession)
+	stackName := fmt.Sprintf("pke-pool-%s-worker-%s", c.GetName(), nodePoolName)
+
+	describeStacksOutput, err := cloudformationSvc.DescribeStacks(&cloudformation.DescribeStacksInput{StackName: aws.String(stackName)})
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to describe node pool stack", "stack", stackName)
+	}
+
+	if len(describeStacksOutput.Stacks) == 0 {
+		return errors.NewWithDetails("node pool stack not found", "stack", stackName)
+	}
+
+	stack := describeStacksOutput.Stacks[0]
+
+	var parameters []*cloudformation.Parameter
+	var image string
+	for _, param := range stack.Parameters {
+		switch aws.StringValue(param.ParameterKey) {
+		case "PkeCommand":
+			command := pkeKubernetesVersionFlagRegexp.ReplaceAllString(
+				aws.StringValue(param.ParameterValue),
+				fmt.Sprintf("%s=%q", pkeKubernetesVersionFlag, version),
+			)
+
+			parameters = append(parameters, &cloudformation.Parameter{
+				ParameterKey:   param.ParameterKey,
+				ParameterValue: aws.String(command),
+			})
+
+		case "ImageId":
+			image = aws.StringValue(param.ParameterValue)
+
+			// custom images are kept
+			if defaultImage, _ := pkeworkflow.GetDefaultImageID(c.GetLocation(), fromVersion); image == defaultImage {
+				newImage, err := pkeworkflow.GetDefaultImageID(c.GetLocation(), version)
+				if err != nil {
+					return err
+				}
+
+				image = newImage
+			} else {
+				logger.Warn("node pool uses a custom image")
+			}
+
+			parameters = append(parameters, &cloudformation.Parameter{
+				ParameterKey:   param.ParameterKey,
+				ParameterValue: aws.String(image),
+			})
+
+		default:
+			parameters = append(parameters, &cloudformation.Parameter{
+				ParameterKey:     param.ParameterKey,
+				UsePreviousValue: aws.Bool(true),
+			})
+		}
+	}
+
+	_, err = cloudformationSvc.UpdateStack(&cloudformation.UpdateStackInput{
+		StackName:           aws.String(stackName),
+		UsePreviousTemplate: aws.Bool(true),
+		Capabilities:        stack.Capabilities,
+		Parameters:          parameters,
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); !ok || awsErr.Code() != "ValidationError" || !strings.HasPrefix(awsErr.Message(), "No updates are to be performed.") {
+			return errors.WrapIfWithDetails(err, "failed to update node pool stack", "stack", stackName)
+		}
+	} else {
+		err := cloudformationSvc.WaitUntilStackUpdateComplete(&cloudformation.DescribeStacksInput{StackName: aws.String(stackName)})
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "failed to wait for node pool stack update", "stack", stackName)
+		}
+	}
+
+	if image != "" && image != providerConfig.AutoScalingGroup.Image {
+		providerConfig.AutoScalingGroup.Image = image
+		nodePool.ProviderConfig["autoScalingGroup"] = providerConfig.AutoScalingGroup
+
+		if err := c.db.Save(nodePool).Error; err != nil {
+			return errors.WrapIf(err, "failed to save node pool")
+		}
+	}
+
+	var groupName string
+	for _, output := range stack.Outputs {
+		if aws.StringValue(output.OutputKey) == "AutoScalingGroupId" {
+			groupName = aws.StringValue(output.OutputValue)
+		}
+	}
+
+	if groupName == "" {
+		return errors.NewWithDetails("auto scaling group not found in node pool stack outputs", "stack", stackName)
+	}
+
+	kubeConfig, err := c.GetK8sConfig()
+	if err != nil {
+		return err
+	}
+
+	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	if err != nil {
+		return err
+	}
+
+	roller := clusterupgrade.NewAutoScalingGroupRoller(autoscaling.New(awsSession), client, logger)
+
+	return roller.Roll(ctx, groupName, maxSurge)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
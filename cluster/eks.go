@@ -116,6 +116,7 @@ func createNodePoolsFromRequest(nodePools map[string]*pkgEks.NodePool, userId ui
 			NodeImage:        nodePool.Image,
 			NodeInstanceType: nodePool.InstanceType,
 			Labels:           nodePool.Labels,
+			Taints:           nodePool.Taints,
 			Delete:           false,
 		}
 		i++
@@ -489,6 +490,7 @@ func (c *EKSCluster) createNodePoolsFromUpdateRequest(requestedNodePools map[str
 				NodeMinCount:     nodePool.MinCount,
 				NodeMaxCount:     nodePool.MaxCount,
 				Count:            nodePool.Count,
+				Taints:           nodePool.Taints,
 				Delete:           false,
 			})
 
@@ -522,6 +524,7 @@ func (c *EKSCluster) createNodePoolsFromUpdateRequest(requestedNodePools map[str
 				NodeMinCount:     nodePool.MinCount,
 				NodeMaxCount:     nodePool.MaxCount,
 				Count:            nodePool.Count,
+				Taints:           nodePool.Taints,
 				Delete:           false,
 			})
 		}
@@ -753,13 +756,15 @@ func (c *EKSCluster) UpdateNodePools(request *pkgCluster.UpdateNodePoolsRequest,
 			c.log.Errorf("Error ASG not found for node pool %v. %v", poolName, err.Error())
 			continue
 		}
-		params := &autoscaling.SetDesiredCapacityInput{
+		minSize, maxSize := c.nodePoolSizeLimits(poolName, nodePool.Count)
+		params := &autoscaling.UpdateAutoScalingGroupInput{
 			AutoScalingGroupName: aws.String(*asgName),
 			DesiredCapacity:      aws.Int64(int64(nodePool.Count)),
-			HonorCooldown:        aws.Bool(false),
+			MinSize:              aws.Int64(int64(minSize)),
+			MaxSize:              aws.Int64(int64(maxSize)),
 		}
 		c.log.Infof("Setting node pool %s size to %d", poolName, nodePool.Count)
-		_, err = autoscalingSrv.SetDesiredCapacity(params)
+		_, err = autoscalingSrv.UpdateAutoScalingGroup(params)
 		if err != nil {
 			c.log.Errorf("Error setting node pool %s size: %v", poolName, err)
 			caughtErrors.Add(err)
@@ -918,6 +923,7 @@ func (c *EKSCluster) GetStatus() (*pkgCluster.GetClusterStatusResponse, error) {
 				Image:             np.NodeImage,
 				CreatorBaseFields: *NewCreatorBaseFields(np.CreatedAt, np.CreatedBy),
 				Labels:            np.Labels,
+				Taints:            np.Taints,
 			}
 			if np.NodeSpotPrice != "" && np.NodeSpotPrice != "0" {
 				hasSpotNodePool = true
@@ -975,10 +981,12 @@ func (c *EKSCluster) CheckEqualityToUpdate(r *pkgCluster.UpdateClusterRequest) e
 			MaxCount:     preNp.NodeMaxCount,
 			Count:        preNp.Count,
 			Image:        preNp.NodeImage,
+			Taints:       preNp.Taints,
 		}
 	}
 
 	preCl := &pkgEks.UpdateClusterAmazonEKS{
+		Version:   c.modelCluster.EKS.Version,
 		NodePools: preNodePools,
 	}
 
@@ -990,7 +998,17 @@ func (c *EKSCluster) CheckEqualityToUpdate(r *pkgCluster.UpdateClusterRequest) e
 
 // AddDefaultsToUpdate adds defaults to update request
 func (c *EKSCluster) AddDefaultsToUpdate(r *pkgCluster.UpdateClusterRequest) {
-	defaultImage, _ := pkgEks.GetDefaultImageID(c.modelCluster.Location, c.modelCluster.EKS.Version)
+	if r != nil && r.EKS != nil && r.EKS.Version == "" {
+		r.EKS.Version = c.modelCluster.EKS.Version
+	}
+
+	// node pools are updated after a version upgrade, so use the default image of the requested version
+	version := c.modelCluster.EKS.Version
+	if r != nil && r.EKS != nil {
+		version = r.EKS.Version
+	}
+
+	defaultImage, _ := pkgEks.GetDefaultImageID(c.modelCluster.Location, version)
 
 	// add default node image(s) if needed
 	if r != nil && r.EKS != nil && r.EKS.NodePools != nil {
@@ -1033,6 +1051,28 @@ func (c *EKSCluster) NodePoolExists(nodePoolName string) bool {
 	return false
 }
 
+// nodePoolSizeLimits returns the auto scaling group size limits of a node pool which allow the requested node count.
+// Node pools scaled to zero get zero limits, so the cluster autoscaler cannot scale them up.
+func (c *EKSCluster) nodePoolSizeLimits(nodePoolName string, count int) (int, int) {
+	if count == 0 {
+		return 0, 0
+	}
+
+	minSize, maxSize := count, count
+	for _, np := range c.modelCluster.EKS.NodePools {
+		if np != nil && np.Name == nodePoolName {
+			if np.NodeMinCount < minSize {
+				minSize = np.NodeMinCount
+			}
+			if np.NodeMaxCount > maxSize {
+				maxSize = np.NodeMaxCount
+			}
+		}
+	}
+
+	return minSize, maxSize
+}
+
 func (c *EKSCluster) setNodePoolSize(nodePoolName string, count int) bool {
 	for _, np := range c.modelCluster.EKS.NodePools {
 		if np != nil && np.Name == nodePoolName {
@@ -1111,17 +1151,12 @@ func (c *EKSCluster) ValidateCreationFields(r *pkgCluster.CreateClusterRequest)
 		return emperror.Wrap(err, "failed to create AWS session")
 	}
 
+	if err := r.Properties.CreateClusterEKS.ValidateNetwork(); err != nil {
+		return err
+	}
+
 	netSvc := pkgEC2.NewNetworkSvc(ec2.New(session), NewLogurLogger(c.log))
 	if r.Properties.CreateClusterEKS.Vpc != nil {
-
-		if r.Properties.CreateClusterEKS.Vpc.VpcId != "" && r.Properties.CreateClusterEKS.Vpc.Cidr != "" {
-			return errors.New("specifying both CIDR and ID for VPC is not allowed")
-		}
-
-		if r.Properties.CreateClusterEKS.Vpc.VpcId == "" && r.Properties.CreateClusterEKS.Vpc.Cidr == "" {
-			return errors.New("either CIDR or ID is required for VPC")
-		}
-
 		if r.Properties.CreateClusterEKS.Vpc.VpcId != "" {
 			// verify that the provided VPC exists and is in available state
 			exists, err := netSvc.VpcAvailable(r.Properties.CreateClusterEKS.Vpc.VpcId)
@@ -1143,29 +1178,7 @@ func (c *EKSCluster) ValidateCreationFields(r *pkgCluster.CreateClusterRequest)
 
 	subnetCidrUsed := r.Properties.CreateClusterEKS.Subnets[0].Cidr != ""
 
-	if !subnetCidrUsed && r.Properties.CreateClusterEKS.Vpc.Cidr != "" {
-		return errors.New("if Subnet ID is specified than VPC ID must be provided as well")
-	}
-
 	for _, subnet := range r.Properties.CreateClusterEKS.Subnets {
-		if subnet.Cidr != "" && subnet.SubnetId != "" {
-			return errors.New("specifying both CIDR and ID for a Subnet is not allowed")
-		}
-
-		if subnet.Cidr == "" && subnet.SubnetId == "" {
-			return errors.New("either CIDR or ID is required for Subnet")
-		}
-
-		if subnetCidrUsed {
-			if subnet.SubnetId != "" && subnet.Cidr == "" {
-				return errors.New("specify either CIDR or ID for all Subnets")
-			}
-		} else {
-			if subnet.SubnetId == "" && subnet.Cidr != "" {
-				return errors.New("specify either CIDR or ID for all Subnets")
-			}
-		}
-
 		if subnet.SubnetId != "" {
 			exists, err := netSvc.SubnetAvailable(subnet.SubnetId, r.Properties.CreateClusterEKS.Vpc.VpcId)
 			if err != nil {
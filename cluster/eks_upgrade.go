@@ -0,0 +1,337 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/gofrs/uuid"
+
+	"github.com/banzaicloud/pipeline/config"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterupgrade"
+	"github.com/banzaicloud/pipeline/model"
+	pkgEks "github.com/banzaicloud/pipeline/pkg/cluster/eks"
+	"github.com/banzaicloud/pipeline/pkg/cluster/eks/action"
+	"github.com/banzaicloud/pipeline/pkg/k8sclient"
+)
+
+const eksUpdatePollInterval = 30 * time.Second
+
+// GetKubernetesVersion returns the Kubernetes version of the cluster.
+func (c *EKSCluster) GetKubernetesVersion() (string, error) {
+	return c.modelCluster.EKS.Version, nil
+}
+
+// ListWorkerNodePools returns the names of the node pools of the cluster.
+func (c *EKSCluster) ListWorkerNodePools() []string {
+	var nodePools []string
+	for _, np := range c.modelCluster.EKS.NodePools {
+		if np != nil {
+			nodePools = append(nodePools, np.Name)
+		}
+	}
+
+	sort.Strings(nodePools)
+
+	return nodePools
+}
+
+func (c *EKSCluster) reloadModel() error {
+	var eksModel model.EKSClusterModel
+
+	err := config.DB().
+		Preload("NodePools").
+		Preload("Subnets").
+		Where(model.EKSClusterModel{ClusterID: c.modelCluster.ID}).
+		First(&eksModel).Error
+	if err != nil {
+		return errors.WrapIf(err, "failed to load EKS cluster from database")
+	}
+
+	c.modelCluster.EKS = eksModel
+
+	return nil
+}
+
+func (c *EKSCluster) newSession() (*session.Session, error) {
+	awsCred, err := c.createAWSCredentialsFromSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	return session.NewSession(&aws.Config{
+		Region:      aws.String(c.modelCluster.Location),
+		Credentials: awsCred,
+	})
+}
+
+// UpgradeControlPlane upgrades the EKS control plane to the given Kubernetes version.
+func (c *EKSCluster) UpgradeControlPlane(ctx context.Context, version string) error {
+	minorVersion, err := clusterupgrade.MinorVersion(version)
+	if err != nil {
+		return err
+	}
+
+	awsSession, err := c.newSession()
+	if err != nil {
+		return err
+	}
+
+	eksSvc := eks.New(awsSession)
+
+	describeClusterOutput, err := eksSvc.DescribeCluster(&eks.DescribeClusterInput{Name: aws.String(c.modelCluster.Name)})
+	if err != nil {
+		return errors.WrapIf(err, "failed to describe EKS cluster")
+	}
+
+	// the upgrade may have already been done by a previous attempt
+	if aws.StringValue(describeClusterOutput.Cluster.Version) != minorVersion {
+		c.log.Infof("upgrading EKS control plane to %s", minorVersion)
+
+		updateOutput, err := eksSvc.UpdateClusterVersion(&eks.UpdateClusterVersionInput{
+			ClientRequestToken: aws.String(uuid.Must(uuid.NewV4()).String()),
+			Name:               aws.String(c.modelCluster.Name),
+			Version:            aws.String(minorVersion),
+		})
+		if err != nil {
+			return errors.WrapIf(err, "failed to update EKS cluster version")
+		}
+
+		if err := c.waitForEKSUpdate(ctx, eksSvc, aws.StringValue(updateOutput.Update.Id)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.updateAddons(version); err != nil {
+		return err
+	}
+
+	c.modelCluster.EKS.Version = version
+
+	return c.Persist()
+}
+
+// updateAddons updates kube-proxy, CoreDNS and the Amazon VPC CNI plugin to the versions recommended
+// for the Kubernetes version of the upgraded control plane.
+func (c *EKSCluster) updateAddons(version string) error {
+	addonVersions, err := pkgEks.GetAddonVersions(version)
+	if err != nil {
+		return err
+	}
+
+	kubeConfig, err := c.GetK8sConfig()
+	if err != nil {
+		return err
+	}
+
+	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	if err != nil {
+		return err
+	}
+
+	if _, err := clusterupgrade.UpdateDaemonSetImage(client, "kube-system", "kube-proxy", "kube-proxy", addonVersions.KubeProxy); err != nil {
+		return err
+	}
+
+	found, err := clusterupgrade.UpdateDeploymentImage(client, "kube-system", "coredns", "coredns", addonVersions.CoreDNS)
+	if err != nil {
+		return err
+	}
+	if !found {
+		c.log.Warn("CoreDNS deployment not found, skipping its upgrade")
+	}
+
+	_, err = clusterupgrade.UpdateDaemonSetImage(client, "kube-system", "aws-node", "aws-node", addonVersions.AmazonVPCCNI)
+
+	return err
+}
+
+func (c *EKSCluster) waitForEKSUpdate(ctx context.Context, eksSvc *eks.EKS, updateID string) error {
+	for {
+		output, err := eksSvc.DescribeUpdate(&eks.DescribeUpdateInput{
+			Name:     aws.String(c.modelCluster.Name),
+			UpdateId: aws.String(updateID),
+		})
+		if err != nil {
+			return errors.WrapIf(err, "failed to describe EKS cluster update")
+		}
+
+		switch aws.StringValue(output.Update.Status) {
+		case eks.UpdateStatusSuccessful:
+			return nil
+
+		case eks.UpdateStatusFailed, eks.UpdateStatusCancelled:
+			var messages []string
+			for _, updateErr := range output.Update.Errors {
+				messages = append(messages, aws.StringValue(updateErr.ErrorMessage))
+			}
+
+			return errors.NewWithDetails(
+				"EKS cluster update failed",
+				"status", aws.StringValue(output.Update.Status),
+				"errors", strings.Join(messages, "; "),
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.WrapIf(ctx.Err(), "waiting for EKS cluster update")
+		case <-time.After(eksUpdatePollInterval):
+		}
+	}
+}
+
+// UpgradeNodePool moves a node pool to the default image of the new Kubernetes version (unless it uses a custom image),
+// then replaces its nodes one by one.
+func (c *EKSCluster) UpgradeNodePool(ctx context.Context, nodePoolName string, fromVersion string, version string, maxSurge int) error {
+	var nodePool *model.AmazonNodePoolsModel
+	for _, np := range c.modelCluster.EKS.NodePools {
+		if np != nil && np.Name == nodePoolName {
+			nodePool = np
+		}
+	}
+
+	if nodePool == nil {
+		return errors.NewWithDetails("node pool not found", "nodePool", nodePoolName)
+	}
+
+	logger := c.log.WithField("nodePool", nodePoolName)
+
+	image := nodePool.NodeImage
+	if defaultImage, _ := pkgEks.GetDefaultImageID(c.modelCluster.Location, fromVersion); image == "" || image == defaultImage {
+		newImage, err := pkgEks.GetDefaultImageID(c.modelCluster.Location, version)
+		if err != nil {
+			return err
+		}
+
+		image = newImage
+	} else {
+		logger.Warn("node pool uses a custom image, upgrade the image to get a new kubelet version")
+	}
+
+	awsSession, err := c.newSession()
+	if err != nil {
+		return err
+	}
+
+	cloudformationSvc := cloudformation.New(awsSession)
+
+	if err := c.updateNodePoolImage(cloudformationSvc, nodePoolName, image); err != nil {
+		return err
+	}
+
+	nodePool.NodeImage = image
+	if err := c.Persist(); err != nil {
+		return err
+	}
+
+	autoscalingSvc := autoscaling.New(awsSession)
+
+	groupName, err := c.getAutoScalingGroupName(cloudformationSvc, autoscalingSvc, nodePoolName)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to get auto scaling group of node pool", "nodePool", nodePoolName)
+	}
+
+	kubeConfig, err := c.GetK8sConfig()
+	if err != nil {
+		return err
+	}
+
+	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	if err != nil {
+		return err
+	}
+
+	roller := clusterupgrade.NewAutoScalingGroupRoller(autoscalingSvc, client, logger)
+
+	return roller.Roll(ctx, aws.StringValue(groupName), maxSurge)
+}
+
+// updateNodePoolImage updates the node pool stack with the current template and the given image
+// with CloudFormation rolling updates disabled, so that nodes can be drained before they are replaced.
+func (c *EKSCluster) updateNodePoolImage(cloudformationSvc *cloudformation.CloudFormation, nodePoolName string, image string) error {
+	stackName := action.GenerateNodePoolStackName(c.modelCluster.Name, nodePoolName)
+
+	describeStacksOutput, err := cloudformationSvc.DescribeStacks(&cloudformation.DescribeStacksInput{StackName: aws.String(stackName)})
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to describe node pool stack", "stack", stackName)
+	}
+
+	if len(describeStacksOutput.Stacks) == 0 {
+		return errors.NewWithDetails("node pool stack not found", "stack", stackName)
+	}
+
+	stack := describeStacksOutput.Stacks[0]
+
+	var parameters []*cloudformation.Parameter
+	for _, param := range stack.Parameters {
+		switch aws.StringValue(param.ParameterKey) {
+		case "NodeImageId", "RollingUpdateEnabled":
+			continue
+		}
+
+		parameters = append(parameters, &cloudformation.Parameter{
+			ParameterKey:     param.ParameterKey,
+			UsePreviousValue: aws.Bool(true),
+		})
+	}
+
+	parameters = append(
+		parameters,
+		&cloudformation.Parameter{
+			ParameterKey:   aws.String("NodeImageId"),
+			ParameterValue: aws.String(image),
+		},
+		&cloudformation.Parameter{
+			ParameterKey:   aws.String("RollingUpdateEnabled"),
+			ParameterValue: aws.String("false"),
+		},
+	)
+
+	template, err := pkgEks.GetNodePoolTemplate()
+	if err != nil {
+		return errors.WrapIf(err, "failed to get node pool template")
+	}
+
+	_, err = cloudformationSvc.UpdateStack(&cloudformation.UpdateStackInput{
+		ClientRequestToken: aws.String(uuid.Must(uuid.NewV4()).String()),
+		StackName:          aws.String(stackName),
+		Capabilities:       []*string{aws.String(cloudformation.CapabilityCapabilityIam)},
+		Parameters:         parameters,
+		Tags:               stack.Tags,
+		TemplateBody:       aws.String(template),
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "ValidationError" && strings.HasPrefix(awsErr.Message(), "No updates are to be performed.") {
+			return nil
+		}
+
+		return errors.WrapIfWithDetails(err, "failed to update node pool stack", "stack", stackName)
+	}
+
+	err = cloudformationSvc.WaitUntilStackUpdateComplete(&cloudformation.DescribeStacksInput{StackName: aws.String(stackName)})
+
+	return errors.WrapIfWithDetails(err, "failed to wait for node pool stack update", "stack", stackName)
+}
@@ -14,6 +14,24 @@
 
 package cluster
 
+import (
+	"time"
+)
+
+// StatusChange describes a cluster status transition.
+type StatusChange struct {
+	OrganizationID uint
+	ClusterID      uint
+	ClusterName    string
+
+	FromStatus        string
+	FromStatusMessage string
+	ToStatus          string
+	ToStatusMessage   string
+
+	Time time.Time
+}
+
 type clusterEvents interface {
 	// ClusterCreated event is emitted when a cluster creation workflow finishes.
 	ClusterCreated(clusterID uint)
@@ -25,6 +43,9 @@ type clusterEvents interface {
 	// this event is fired regardless of whether the cluster update succeeded or
 	// only partially succeeded (cluster is in warning state)
 	ClusterUpdated(clusterID uint)
+
+	// ClusterStatusChanged event is emitted when a cluster status transition is recorded in the status history.
+	ClusterStatusChanged(change StatusChange)
 }
 
 type nopClusterEvents struct {
@@ -43,6 +64,9 @@ func (*nopClusterEvents) ClusterDeleted(orgID uint, clusterName string) {
 func (*nopClusterEvents) ClusterUpdated(clusterID uint) {
 }
 
+func (*nopClusterEvents) ClusterStatusChanged(change StatusChange) {
+}
+
 type eventBus interface {
 	Publish(topic string, args ...interface{})
 }
@@ -55,6 +79,8 @@ const (
 	clusterCreatedTopic = "cluster_created"
 	clusterDeletedTopic = "cluster_deleted"
 	clusterUpdatedTopic = "cluster_updated"
+
+	clusterStatusChangedTopic = "cluster_status_changed"
 )
 
 func NewClusterEvents(eb eventBus) *clusterEventBus {
@@ -74,3 +100,7 @@ func (c *clusterEventBus) ClusterDeleted(orgID uint, clusterName string) {
 func (c *clusterEventBus) ClusterUpdated(clusterID uint) {
 	c.eb.Publish(clusterUpdatedTopic, clusterID)
 }
+
+func (c *clusterEventBus) ClusterStatusChanged(change StatusChange) {
+	c.eb.Publish(clusterStatusChangedTopic, change)
+}
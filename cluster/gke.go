@@ -397,6 +397,7 @@ func (c *GKECluster) GetStatus() (*pkgCluster.GetClusterStatusResponse, error) {
 				Version:           c.model.NodeVersion,
 				CreatorBaseFields: *NewCreatorBaseFields(np.CreatedAt, np.CreatedBy),
 				Labels:            np.Labels,
+				Taints:            np.Taints,
 			}
 			if np.Preemptible {
 				hasSpotNodePool = true
@@ -717,6 +718,13 @@ func (c *GKECluster) UpdateCluster(updateRequest *pkgCluster.UpdateClusterReques
 	// update model to save
 	c.updateModel(res, updatedNodePools)
 
+	// node pool taints are not read back from Google, existing node pools are reconciled by SyncNodePoolTaints
+	for _, nodePoolModel := range c.model.NodePools {
+		if nodePool, ok := updateRequest.GKE.NodePools[nodePoolModel.Name]; ok && nodePool != nil {
+			nodePoolModel.Taints = nodePool.Taints
+		}
+	}
+
 	return nil
 
 }
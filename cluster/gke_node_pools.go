@@ -42,6 +42,7 @@ func createNodePoolsModelFromRequest(nodePoolsData map[string]*pkgClusterGoogle.
 			NodeInstanceType: nodePoolData.NodeInstanceType,
 			Preemptible:      nodePoolData.Preemptible,
 			Labels:           nodePoolData.Labels,
+			Taints:           nodePoolData.Taints,
 		}
 
 		i++
@@ -80,6 +81,7 @@ func createNodePoolsFromClusterModel(clusterModel *google.GKEClusterModel) ([]*g
 					"https://www.googleapis.com/auth/compute",
 				},
 				Preemptible: nodePoolModel.Preemptible,
+				Taints:      createNodeTaintsFromNodePoolModel(nodePoolModel),
 			},
 			InitialNodeCount: int64(nodePoolModel.NodeCount),
 			Version:          clusterModel.NodeVersion,
@@ -120,8 +122,41 @@ func createNodePoolsRequestDataFromNodePoolModel(nodePoolsModel []*google.GKENod
 			Count:            nodePoolModel.NodeCount,
 			NodeInstanceType: nodePoolModel.NodeInstanceType,
 			Preemptible:      nodePoolModel.Preemptible,
+			Taints:           nodePoolModel.Taints,
 		}
 	}
 
 	return nodePools, nil
 }
+
+// createNodeTaintsFromNodePoolModel converts the node pool taints to GKE node taints
+func createNodeTaintsFromNodePoolModel(nodePoolModel *google.GKENodePoolModel) []*gke.NodeTaint {
+	if len(nodePoolModel.Taints) == 0 {
+		return nil
+	}
+
+	taints := make([]*gke.NodeTaint, len(nodePoolModel.Taints))
+	for i, taint := range nodePoolModel.Taints {
+		taints[i] = &gke.NodeTaint{
+			Key:    taint.Key,
+			Value:  taint.Value,
+			Effect: gkeTaintEffect(taint.Effect),
+		}
+	}
+
+	return taints
+}
+
+// gkeTaintEffect maps Kubernetes taint effects to GKE node taint effects
+func gkeTaintEffect(effect string) string {
+	switch effect {
+	case pkgCommon.TaintEffectNoSchedule:
+		return "NO_SCHEDULE"
+	case pkgCommon.TaintEffectPreferNoSchedule:
+		return "PREFER_NO_SCHEDULE"
+	case pkgCommon.TaintEffectNoExecute:
+		return "NO_EXECUTE"
+	default:
+		return "EFFECT_UNSPECIFIED"
+	}
+}
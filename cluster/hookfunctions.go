@@ -82,6 +82,10 @@ var HookMap = map[string]PostFunctioner{
 		f:            TaintHeadNodes,
 		ErrorHandler: ErrorHandler{},
 	},
+	pkgCluster.SyncNodePoolTaints: &BasePostFunction{
+		f:            SyncNodePoolTaints,
+		ErrorHandler: ErrorHandler{},
+	},
 	pkgCluster.InstallPVCOperator: &BasePostFunction{
 		f:            InstallPVCOperatorPostHook,
 		ErrorHandler: ErrorHandler{},
@@ -128,6 +132,7 @@ var BasePostHookFunctions = []string{
 	pkgCluster.InstallHelmPostHook,
 	pkgCluster.InstallNodePoolLabelSetOperator,
 	pkgCluster.SetupNodePoolLabelsSet,
+	pkgCluster.SyncNodePoolTaints,
 	pkgCluster.RegisterDomainPostHook,
 	pkgCluster.InstallIngressControllerPostHook,
 	pkgCluster.InstallKubernetesDashboardPostHook,
@@ -17,11 +17,14 @@ package cluster
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"emperror.dev/emperror"
 	"go.uber.org/cadence/client"
 
+	"github.com/banzaicloud/pipeline/helm"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterupgrade"
 	"github.com/banzaicloud/pipeline/pkg/cluster"
 )
 
@@ -35,6 +38,8 @@ type commonUpdater struct {
 	workflowClient           client.Client
 	externalBaseURL          string
 	externalBaseURLInsecure  bool
+	fromVersion              string
+	upgradeVersion           string
 }
 
 type commonUpdateValidationError struct {
@@ -120,6 +125,12 @@ func (c *commonUpdater) Prepare(ctx context.Context) (CommonCluster, error) {
 		}
 	}
 
+	if version := getVersionFromUpdateRequest(c.request); version != "" {
+		if err := c.prepareUpgrade(version); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := c.cluster.SetStatus(cluster.Updating, cluster.UpdatingMessage); err != nil {
 		return nil, err
 	}
@@ -152,15 +163,25 @@ func (c *commonUpdater) Update(ctx context.Context) error {
 		return err
 	}
 
-	if updater, ok := c.cluster.(interface {
-		UpdatePKECluster(context.Context, *cluster.UpdateClusterRequest, uint, client.Client, string, bool) error
-	}); ok {
-		err = updater.UpdatePKECluster(ctx, c.request, c.userID, c.workflowClient, c.externalBaseURL, c.externalBaseURLInsecure)
-	} else {
-		err = c.cluster.UpdateCluster(c.request, c.userID)
+	// the control plane has to be upgraded before the node pools are changed,
+	// so that new nodes do not join with a kubelet newer than the API server
+	if c.upgradeVersion != "" {
+		if err := c.upgrade(ctx); err != nil {
+			return emperror.Wrap(err, "upgrading Kubernetes version failed")
+		}
 	}
-	if err != nil {
-		return err
+
+	if updatesNodePools(c.request) {
+		if updater, ok := c.cluster.(interface {
+			UpdatePKECluster(context.Context, *cluster.UpdateClusterRequest, uint, client.Client, string, bool) error
+		}); ok {
+			err = updater.UpdatePKECluster(ctx, c.request, c.userID, c.workflowClient, c.externalBaseURL, c.externalBaseURLInsecure)
+		} else {
+			err = c.cluster.UpdateCluster(c.request, c.userID)
+		}
+		if err != nil {
+			return err
+		}
 	}
 
 	if err := DeployClusterAutoscaler(c.cluster); err != nil {
@@ -171,5 +192,135 @@ func (c *commonUpdater) Update(ctx context.Context) error {
 	if err := LabelNodesWithNodePoolName(c.cluster); err != nil {
 		return emperror.Wrap(err, "adding labels to nodes failed")
 	}
+
+	if err := SyncNodePoolTaints(c.cluster); err != nil {
+		return emperror.Wrap(err, "syncing node pool taints failed")
+	}
+	return nil
+}
+
+// upgradableCluster is implemented by clusters supporting Kubernetes version upgrades.
+type upgradableCluster interface {
+	clusterUpgrader
+
+	GetKubernetesVersion() (string, error)
+	ListWorkerNodePools() []string
+
+	// reloadModel refreshes the cluster state persisted by the upgrade workflow activities.
+	reloadModel() error
+}
+
+// prepareUpgrade validates the requested Kubernetes version and checks whether the deployed Helm releases
+// use APIs which are no longer served by it.
+func (c *commonUpdater) prepareUpgrade(version string) error {
+	upgrader, ok := c.cluster.(upgradableCluster)
+	if !ok {
+		return &commonUpdateValidationError{
+			msg:            fmt.Sprintf("Kubernetes version upgrade is not supported for %s clusters", c.cluster.GetCloud()),
+			invalidRequest: true,
+		}
+	}
+
+	currentVersion, err := upgrader.GetKubernetesVersion()
+	if err != nil {
+		return emperror.Wrap(err, "could not get current Kubernetes version")
+	}
+
+	if version == currentVersion {
+		return nil
+	}
+
+	if err := clusterupgrade.ValidateVersionUpgrade(currentVersion, version); err != nil {
+		return &commonUpdateValidationError{
+			msg:            err.Error(),
+			invalidRequest: true,
+		}
+	}
+
+	kubeConfig, err := c.cluster.GetK8sConfig()
+	if err != nil {
+		return emperror.Wrap(err, "could not get k8s config")
+	}
+
+	releases, err := helm.ListDeployments(nil, "", kubeConfig)
+	if err != nil {
+		return emperror.Wrap(err, "could not list Helm releases")
+	}
+
+	var problems []string
+	for _, release := range releases.GetReleases() {
+		apis, err := clusterupgrade.FindRemovedAPIs(release.GetManifest(), version)
+		if err != nil {
+			return emperror.Wrap(err, "could not check Helm release manifest")
+		}
+
+		for _, api := range apis {
+			problems = append(problems, fmt.Sprintf("release %s uses %s %s (removed in %s)", release.GetName(), api.APIVersion, api.Kind, api.RemovedIn))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &commonUpdateValidationError{
+			msg:                fmt.Sprintf("deployed Helm releases use APIs not served by Kubernetes %s: %s", version, strings.Join(problems, "; ")),
+			preconditionFailed: true,
+		}
+	}
+
+	c.fromVersion = currentVersion
+	c.upgradeVersion = version
+
 	return nil
 }
+
+// upgrade runs the upgrade workflow and waits for it to finish.
+func (c *commonUpdater) upgrade(ctx context.Context) error {
+	upgrader := c.cluster.(upgradableCluster)
+
+	input := UpgradeClusterWorkflowInput{
+		ClusterID:   c.cluster.GetID(),
+		FromVersion: c.fromVersion,
+		Version:     c.upgradeVersion,
+		NodePools:   upgrader.ListWorkerNodePools(),
+		MaxSurge:    1,
+	}
+
+	workflowOptions := client.StartWorkflowOptions{
+		TaskList:                     "pipeline",
+		ExecutionStartToCloseTimeout: 24 * time.Hour,
+	}
+
+	exec, err := c.workflowClient.ExecuteWorkflow(ctx, workflowOptions, UpgradeClusterWorkflowName, input)
+	if err != nil {
+		return err
+	}
+
+	if setter, ok := c.cluster.(interface{ SetCurrentWorkflowID(string) error }); ok {
+		if err := setter.SetCurrentWorkflowID(exec.GetID()); err != nil {
+			return err
+		}
+	}
+
+	if err := exec.Get(ctx, nil); err != nil {
+		return err
+	}
+
+	return emperror.Wrap(upgrader.reloadModel(), "could not reload upgraded cluster")
+}
+
+// updatesNodePools tells whether an update request changes the node pools of the cluster:
+// a version upgrade request without node pools leaves them intact, even if the version is the current one.
+func updatesNodePools(request *cluster.UpdateClusterRequest) bool {
+	return getVersionFromUpdateRequest(request) == "" || len(getNodePoolsFromUpdateRequest(request)) > 0
+}
+
+func getVersionFromUpdateRequest(request *cluster.UpdateClusterRequest) string {
+	if request.PKE != nil {
+		return request.PKE.Version
+	}
+
+	if request.EKS != nil {
+		return request.EKS.Version
+	}
+
+	return ""
+}
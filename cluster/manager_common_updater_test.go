@@ -0,0 +1,86 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/pkg/cluster/eks"
+	"github.com/banzaicloud/pipeline/pkg/cluster/pke"
+)
+
+func TestUpdatesNodePools(t *testing.T) {
+	tests := map[string]struct {
+		request  pkgCluster.UpdateClusterRequest
+		expected bool
+	}{
+		"EKS node pool update": {
+			request: pkgCluster.UpdateClusterRequest{
+				Cloud: pkgCluster.Amazon,
+				UpdateProperties: pkgCluster.UpdateProperties{
+					EKS: &eks.UpdateClusterAmazonEKS{
+						NodePools: map[string]*eks.NodePool{"pool1": {Count: 2}},
+					},
+				},
+			},
+			expected: true,
+		},
+		"EKS version upgrade with node pools": {
+			request: pkgCluster.UpdateClusterRequest{
+				Cloud: pkgCluster.Amazon,
+				UpdateProperties: pkgCluster.UpdateProperties{
+					EKS: &eks.UpdateClusterAmazonEKS{
+						Version:   "1.13",
+						NodePools: map[string]*eks.NodePool{"pool1": {Count: 2}},
+					},
+				},
+			},
+			expected: true,
+		},
+		"EKS current version without node pools": {
+			request: pkgCluster.UpdateClusterRequest{
+				Cloud: pkgCluster.Amazon,
+				UpdateProperties: pkgCluster.UpdateProperties{
+					EKS: &eks.UpdateClusterAmazonEKS{
+						Version: "1.12",
+					},
+				},
+			},
+			expected: false,
+		},
+		"PKE version without node pools": {
+			request: pkgCluster.UpdateClusterRequest{
+				Cloud: pkgCluster.Amazon,
+				UpdateProperties: pkgCluster.UpdateProperties{
+					PKE: &pke.UpdateClusterPKE{
+						Version: "1.14.3",
+					},
+				},
+			},
+			expected: false,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, updatesNodePools(&test.request))
+		})
+	}
+}
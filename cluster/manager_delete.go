@@ -284,6 +284,11 @@ func (m *Manager) deleteCluster(ctx context.Context, cluster CommonCluster, forc
 		logger.Error(err)
 	}
 
+	// record the deletion as the last transition of the status history (which is kept after the cluster is deleted)
+	if err := cluster.SetStatus(pkgCluster.Deleted, pkgCluster.DeletedMessage); err != nil {
+		logger.Error(emperror.Wrap(err, "failed to record cluster deletion in status history"))
+	}
+
 	// delete cluster from database
 	orgID := cluster.GetOrganizationId()
 	deleteName := cluster.GetName()
@@ -21,6 +21,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
+	"github.com/banzaicloud/pipeline/internal/cluster/metrics"
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
 )
 
@@ -42,8 +43,55 @@ type clusterUpdater interface {
 	Update(ctx context.Context) error
 }
 
-// UpdateCluster updates a cluster.
+// UpdateCluster updates a cluster in the background.
 func (m *Manager) UpdateCluster(ctx context.Context, updateCtx UpdateContext, updater clusterUpdater) error {
+	cluster, timer, err := m.prepareUpdate(ctx, updateCtx, updater)
+	if err != nil {
+		return err
+	}
+
+	errorHandler := m.getClusterErrorHandler(ctx, cluster)
+
+	go func() {
+		defer emperror.HandleRecover(errorHandler.WithStatus(pkgCluster.Warning, "internal error while updating cluster"))
+
+		err := m.updateCluster(ctx, updateCtx, cluster, updater)
+		if err != nil {
+			errorHandler.Handle(err)
+			return
+		}
+		timer.RecordDuration()
+	}()
+
+	return nil
+}
+
+// UpdateClusterAndWait updates a cluster and returns once the update finished.
+func (m *Manager) UpdateClusterAndWait(ctx context.Context, updateCtx UpdateContext, updater clusterUpdater) (err error) {
+	cluster, timer, err := m.prepareUpdate(ctx, updateCtx, updater)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.WithMessage(emperror.Recover(r), "internal error while updating cluster")
+
+			if setErr := cluster.SetStatus(pkgCluster.Warning, "internal error while updating cluster"); setErr != nil {
+				log.Error(setErr, "could not set cluster status")
+			}
+		}
+	}()
+
+	if err := m.updateCluster(ctx, updateCtx, cluster, updater); err != nil {
+		return err
+	}
+	timer.RecordDuration()
+
+	return nil
+}
+
+func (m *Manager) prepareUpdate(ctx context.Context, updateCtx UpdateContext, updater clusterUpdater) (CommonCluster, metrics.DurationMetricTimer, error) {
 	logger := m.getLogger(ctx).WithFields(logrus.Fields{
 		"organization": updateCtx.OrganizationID,
 		"user":         updateCtx.UserID,
@@ -54,40 +102,28 @@ func (m *Manager) UpdateCluster(ctx context.Context, updateCtx UpdateContext, up
 
 	err := updater.Validate(ctx)
 	if err != nil {
-		return errors.WithMessage(err, "cluster update validation failed")
+		return nil, nil, errors.WithMessage(err, "cluster update validation failed")
 	}
 
 	logger.Debug("preparing cluster update")
 
 	cluster, err := updater.Prepare(ctx)
 	if err != nil {
-		return errors.WithMessage(err, "could not prepare cluster")
+		return nil, nil, errors.WithMessage(err, "could not prepare cluster")
 	}
 
 	timer, err := m.getClusterStatusChangeMetricTimer(cluster.GetCloud(), cluster.GetLocation(), pkgCluster.Updating, cluster.GetOrganizationId(), cluster.GetName())
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	if err := cluster.SetStatus(pkgCluster.Updating, pkgCluster.UpdatingMessage); err != nil {
-		return emperror.With(err, "could not update cluster status")
+		return nil, nil, emperror.With(err, "could not update cluster status")
 	}
 
 	logger.Info("updating cluster")
-	errorHandler := m.getClusterErrorHandler(ctx, cluster)
 
-	go func() {
-		defer emperror.HandleRecover(errorHandler.WithStatus(pkgCluster.Warning, "internal error while updating cluster"))
-
-		err := m.updateCluster(ctx, updateCtx, cluster, updater)
-		if err != nil {
-			errorHandler.Handle(err)
-			return
-		}
-		timer.RecordDuration()
-	}()
-
-	return nil
+	return cluster, timer, nil
 }
 
 func (m *Manager) updateCluster(ctx context.Context, updateCtx UpdateContext, cluster CommonCluster, updater clusterUpdater) error {
@@ -184,3 +184,42 @@ func DeployNodePoolLabelsSet(cluster CommonCluster, nodePoolLabels map[string]ma
 
 	return nil
 }
+
+// GetNodePoolUserLabels returns the user defined (not reserved) labels of each node pool of a running cluster.
+func GetNodePoolUserLabels(cluster CommonCluster) (map[string]map[string]string, error) {
+	pipelineSystemNamespace := viper.GetString(config.PipelineSystemNamespace)
+
+	k8sConfig, err := cluster.GetK8sConfig()
+	if err != nil {
+		return nil, emperror.Wrap(err, "failed to get node pool labels of cluster")
+	}
+	k8sClientConfig, err := k8sclient.NewClientConfig(k8sConfig)
+	if err != nil {
+		return nil, emperror.Wrap(err, "failed to get node pool labels of cluster")
+	}
+	m, err := npls.NewNPLSManager(k8sClientConfig, pipelineSystemNamespace)
+	if err != nil {
+		return nil, emperror.Wrap(err, "failed to get node pool labels of cluster")
+	}
+
+	sets, err := m.GetAll()
+	if err != nil {
+		return nil, emperror.Wrap(err, "failed to get node pool labels of cluster")
+	}
+
+	nodePoolLabels := make(map[string]map[string]string, len(sets))
+	for nodePoolName, labelSet := range sets {
+		labels := make(map[string]string)
+		for labelKey, labelValue := range labelSet {
+			if !IsReservedDomainKey(labelKey) {
+				labels[labelKey] = labelValue
+			}
+		}
+
+		if len(labels) > 0 {
+			nodePoolLabels[nodePoolName] = labels
+		}
+	}
+
+	return nodePoolLabels, nil
+}
@@ -0,0 +1,177 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"emperror.dev/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/banzaicloud/pipeline/pkg/k8sclient"
+)
+
+// managedTaintsAnnotationKey stores the node pool taints applied to a node by Pipeline,
+// so that taints removed from the node pool can be removed from the node without touching other taints.
+const managedTaintsAnnotationKey = "nodepool.banzaicloud.io/managed-taints"
+
+// SyncNodePoolTaints applies the taints of each node pool to the current nodes of the pool,
+// including the taint changes of existing node pools.
+// Nodes joining a pool later are registered with its taints on EKS (kubelet flags), GKE (node config)
+// and PKE (pke install flags). AKS and ACK nodes cannot be registered with taints, but they only join
+// through cluster updates, which run this again: Pipeline deploys no autoscaler on ACK
+// and autoscaled AKS node pools cannot have taints.
+func SyncNodePoolTaints(commonCluster CommonCluster) error {
+	logger := log.WithField("cluster", commonCluster.GetName())
+
+	status, err := commonCluster.GetStatus()
+	if err != nil {
+		return errors.WrapIf(err, "failed to get cluster status")
+	}
+
+	kubeConfig, err := commonCluster.GetK8sConfig()
+	if err != nil {
+		return errors.WrapIf(err, "failed to get cluster k8s config")
+	}
+
+	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	if err != nil {
+		return errors.WrapIf(err, "failed to create k8s client")
+	}
+
+	for nodePoolName, nodePool := range status.NodePools {
+		if nodePool == nil {
+			continue
+		}
+
+		err := syncNodePoolTaints(client, nodePoolName, nodePool.Taints)
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "failed to sync node pool taints", "nodePool", nodePoolName)
+		}
+
+		logger.WithField("nodePool", nodePoolName).Debug("node pool taints synced")
+	}
+
+	return nil
+}
+
+func syncNodePoolTaints(client kubernetes.Interface, nodePoolName string, taints common.NodePoolTaints) error {
+	nodes, err := client.CoreV1().Nodes().List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", common.LabelKey, nodePoolName),
+	})
+	if err != nil {
+		return errors.WrapIf(err, "failed to list nodes")
+	}
+
+	for _, node := range nodes.Items {
+		nodeName := node.Name
+
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			node, err := client.CoreV1().Nodes().Get(nodeName, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+
+			if !setNodeTaints(node, taints) {
+				return nil
+			}
+
+			_, err = client.CoreV1().Nodes().Update(node)
+
+			return err
+		})
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "failed to update node taints", "node", nodeName)
+		}
+	}
+
+	return nil
+}
+
+// setNodeTaints replaces the taints previously applied by Pipeline with the given ones
+// and reports whether the node has been changed.
+func setNodeTaints(node *corev1.Node, taints common.NodePoolTaints) bool {
+	var managedTaints common.NodePoolTaints
+	if value, ok := node.Annotations[managedTaintsAnnotationKey]; ok {
+		// an invalid annotation is overwritten with the current taints
+		_ = json.Unmarshal([]byte(value), &managedTaints)
+	}
+
+	replaced := make(map[string]bool, len(managedTaints)+len(taints))
+	for _, taint := range append(managedTaints, taints...) {
+		replaced[taint.Key+":"+taint.Effect] = true
+	}
+
+	nodeTaints := make([]corev1.Taint, 0, len(node.Spec.Taints)+len(taints))
+	for _, taint := range node.Spec.Taints {
+		if !replaced[taint.Key+":"+string(taint.Effect)] {
+			nodeTaints = append(nodeTaints, taint)
+		}
+	}
+
+	for _, taint := range taints {
+		nodeTaints = append(nodeTaints, corev1.Taint{
+			Key:    taint.Key,
+			Value:  taint.Value,
+			Effect: corev1.TaintEffect(taint.Effect),
+		})
+	}
+
+	var annotation string
+	if len(taints) > 0 {
+		value, _ := json.Marshal(taints)
+		annotation = string(value)
+	}
+
+	if taintsEqual(node.Spec.Taints, nodeTaints) && node.Annotations[managedTaintsAnnotationKey] == annotation {
+		return false
+	}
+
+	node.Spec.Taints = nodeTaints
+
+	if node.Annotations == nil {
+		node.Annotations = make(map[string]string)
+	}
+	node.Annotations[managedTaintsAnnotationKey] = annotation
+
+	return true
+}
+
+func taintsEqual(a []corev1.Taint, b []corev1.Taint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for _, taint := range a {
+		found := false
+		for _, t := range b {
+			if taint.Key == t.Key && taint.Value == t.Value && taint.Effect == t.Effect {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,123 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/banzaicloud/pipeline/pkg/common"
+)
+
+func TestSetNodeTaints(t *testing.T) {
+	headTaint := corev1.Taint{Key: common.NodePoolNameTaintKey, Value: "head", Effect: corev1.TaintEffectNoSchedule}
+
+	tests := []struct {
+		name           string
+		node           corev1.Node
+		taints         common.NodePoolTaints
+		expectedTaints []corev1.Taint
+		changed        bool
+	}{
+		{
+			name:           "add taints",
+			node:           corev1.Node{Spec: corev1.NodeSpec{Taints: []corev1.Taint{headTaint}}},
+			taints:         common.NodePoolTaints{{Key: "dedicated", Value: "gpu", Effect: common.TaintEffectNoSchedule}},
+			expectedTaints: []corev1.Taint{headTaint, {Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}},
+			changed:        true,
+		},
+		{
+			name: "replace registered taint",
+			node: corev1.Node{Spec: corev1.NodeSpec{Taints: []corev1.Taint{
+				{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+			}}},
+			taints:         common.NodePoolTaints{{Key: "dedicated", Value: "gpu", Effect: common.TaintEffectNoSchedule}},
+			expectedTaints: []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}},
+			changed:        true,
+		},
+		{
+			name: "remove managed taints",
+			node: corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+					managedTaintsAnnotationKey: `[{"key":"dedicated","value":"gpu","effect":"NoSchedule"}]`,
+				}},
+				Spec: corev1.NodeSpec{Taints: []corev1.Taint{
+					headTaint,
+					{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+				}},
+			},
+			expectedTaints: []corev1.Taint{headTaint},
+			changed:        true,
+		},
+		{
+			name: "unchanged",
+			node: corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+					managedTaintsAnnotationKey: `[{"key":"dedicated","effect":"NoExecute"}]`,
+				}},
+				Spec: corev1.NodeSpec{Taints: []corev1.Taint{
+					{Key: "dedicated", Effect: corev1.TaintEffectNoExecute},
+				}},
+			},
+			taints:         common.NodePoolTaints{{Key: "dedicated", Effect: common.TaintEffectNoExecute}},
+			expectedTaints: []corev1.Taint{{Key: "dedicated", Effect: corev1.TaintEffectNoExecute}},
+			changed:        false,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			changed := setNodeTaints(&test.node, test.taints)
+
+			assert.Equal(t, test.changed, changed)
+			assert.Equal(t, test.expectedTaints, test.node.Spec.Taints)
+		})
+	}
+}
+
+func TestSyncNodePoolTaints(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{common.LabelKey: "pool1"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node2", Labels: map[string]string{common.LabelKey: "pool2"}}},
+	)
+
+	taints := common.NodePoolTaints{{Key: "dedicated", Value: "gpu", Effect: common.TaintEffectNoSchedule}}
+
+	err := syncNodePoolTaints(client, "pool1", taints)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node1, err := client.CoreV1().Nodes().Get("node1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}, node1.Spec.Taints)
+	assert.Equal(t, `[{"key":"dedicated","value":"gpu","effect":"NoSchedule"}]`, node1.Annotations[managedTaintsAnnotationKey])
+
+	node2, err := client.CoreV1().Nodes().Get("node2", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Empty(t, node2.Spec.Taints)
+}
@@ -15,6 +15,7 @@
 package cluster
 
 import (
+	"encoding/json"
 	stderrors "errors"
 
 	"emperror.dev/emperror"
@@ -23,6 +24,7 @@ import (
 	k8sapierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/banzaicloud/pipeline/config"
 	intSecret "github.com/banzaicloud/pipeline/internal/secret"
 	"github.com/banzaicloud/pipeline/pkg/k8sclient"
 	"github.com/banzaicloud/pipeline/pkg/k8sutil"
@@ -40,7 +42,16 @@ func InstallSecrets(cc CommonCluster, query *secretTypes.ListSecretsQuery, names
 		return nil, err
 	}
 
-	return InstallSecretsByK8SConfig(kubeConfig, cc.GetOrganizationId(), query, namespace)
+	secretSources, err := InstallSecretsByK8SConfig(kubeConfig, cc.GetOrganizationId(), query, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, source := range secretSources {
+		recordInstalledSecret(cc, source.Name, namespace, source.Name, nil, false)
+	}
+
+	return secretSources, nil
 }
 
 // InstallSecretsByK8SConfig is the same as InstallSecrets but use this if you already have a K8S config at hand.
@@ -157,7 +168,16 @@ func InstallSecret(cc CommonCluster, secretName string, req InstallSecretRequest
 		return nil, errors.Wrap(err, "failed to get k8s config")
 	}
 
-	return InstallSecretByK8SConfig(kubeConfig, cc.GetOrganizationId(), secretName, req)
+	sourceMeta, err := InstallSecretByK8SConfig(kubeConfig, cc.GetOrganizationId(), secretName, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.SourceSecretName != "" {
+		recordInstalledSecret(cc, req.SourceSecretName, req.Namespace, secretName, req.Spec, true)
+	}
+
+	return sourceMeta, nil
 }
 
 // InstallSecretByK8SConfig is the same as InstallSecret but use this if you already have a K8S config at hand.
@@ -231,7 +251,16 @@ func MergeSecret(cc CommonCluster, secretName string, req InstallSecretRequest)
 		return nil, errors.Wrap(err, "failed to get k8s config")
 	}
 
-	return MergeSecretByK8SConfig(kubeConfig, cc.GetOrganizationId(), secretName, req)
+	sourceMeta, err := MergeSecretByK8SConfig(kubeConfig, cc.GetOrganizationId(), secretName, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.SourceSecretName != "" {
+		recordInstalledSecret(cc, req.SourceSecretName, req.Namespace, secretName, req.Spec, false)
+	}
+
+	return sourceMeta, nil
 }
 
 // MergeSecretByK8SConfig is the same as MergeSecret but use this if you already have a K8S config at hand.
@@ -303,3 +332,38 @@ func MergeSecretByK8SConfig(kubeConfig []byte, orgID uint, secretName string, re
 
 	return &sourceMeta, nil
 }
+
+// recordInstalledSecret records the installation of a secret so that it shows up among the usages of the secret
+// and it can be installed again when the secret is rotated.
+// Failing to do so is logged only, the secret is already installed at this point.
+func recordInstalledSecret(
+	cc CommonCluster,
+	sourceSecretName string,
+	namespace string,
+	name string,
+	spec map[string]InstallSecretRequestSpecItem,
+	merged bool,
+) {
+	installed := intSecret.InstalledSecretModel{
+		OrganizationID: cc.GetOrganizationId(),
+		SecretID:       secret.GenerateSecretIDFromName(sourceSecretName),
+		ClusterID:      cc.GetID(),
+		Namespace:      namespace,
+		Name:           name,
+		Merged:         merged,
+	}
+
+	if len(spec) > 0 {
+		encodedSpec, err := json.Marshal(spec)
+		if err != nil {
+			log.Warnf("could not encode spec of installed secret %s/%s: %s", namespace, name, err.Error())
+			return
+		}
+
+		installed.Spec = string(encodedSpec)
+	}
+
+	if err := intSecret.RecordInstalledSecret(config.DB(), installed); err != nil {
+		log.Warnf("could not record installed secret %s/%s: %s", namespace, name, err.Error())
+	}
+}
@@ -0,0 +1,304 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"emperror.dev/emperror"
+	"emperror.dev/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/util/workqueue"
+
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/sleepschedule"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+const clusterSleepScheduleChangedTopic = "cluster_sleep_schedule_changed"
+
+// sleepRecheckInterval is the delay after which a cluster which could not be put to sleep or woken up is checked again.
+const sleepRecheckInterval = 5 * time.Minute
+
+// SleepScheduleSupported returns whether the node pools of a cluster distribution can be scaled by the sleep controller.
+func SleepScheduleSupported(distribution string) bool {
+	switch distribution {
+	case pkgCluster.EKS, pkgCluster.AKS, pkgCluster.GKE:
+		return true
+	default:
+		return false
+	}
+}
+
+type sleepScheduleEvents struct {
+	eb eventBus
+}
+
+// NewSleepScheduleEvents returns a sleep schedule event publisher which notifies the sleep controller through the event bus.
+func NewSleepScheduleEvents(eb eventBus) *sleepScheduleEvents {
+	return &sleepScheduleEvents{
+		eb: eb,
+	}
+}
+
+// SleepScheduleChanged implements the sleepschedule.Events interface.
+func (e *sleepScheduleEvents) SleepScheduleChanged(clusterID uint) {
+	e.eb.Publish(clusterSleepScheduleChangedTopic, clusterID)
+}
+
+// SleepController puts clusters to sleep (scales their node pools to zero) and wakes them up (restores their node pool sizes)
+// according to their sleep schedules
+type SleepController struct {
+	manager   *Manager
+	schedules *sleepschedule.Manager
+
+	// clusterEvents is the event bus through which cluster updated and sleep schedule changed notifications are received
+	clusterEvents clusterEventsSubscriber
+
+	// queue is where incoming work is placed to de-dup and to allow "easy"
+	// rate limited re-queues on errors
+	queue workqueue.RateLimitingInterface
+
+	logger       logrus.FieldLogger
+	errorHandler emperror.Handler
+}
+
+// NewSleepController instantiates a new cluster sleep controller
+func NewSleepController(
+	manager *Manager,
+	schedules *sleepschedule.Manager,
+	clusterEvents clusterEventsSubscriber,
+	logger logrus.FieldLogger,
+	errorHandler emperror.Handler,
+) *SleepController {
+	return &SleepController{
+		manager:       manager,
+		schedules:     schedules,
+		clusterEvents: clusterEvents,
+		queue:         workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "sleep-controller"),
+		logger:        logger,
+		errorHandler:  errorHandler,
+	}
+}
+
+func (c *SleepController) Start() error {
+	c.logger.Info("starting cluster sleep controller")
+
+	schedules, err := c.schedules.ListSchedules()
+	if err != nil {
+		return emperror.Wrap(err, "retrieving sleep schedules failed")
+	}
+
+	for _, schedule := range schedules {
+		c.enqueueCluster(schedule.ClusterID)
+	}
+
+	// we are interested in new, changed and overridden schedules
+	c.clusterEvents.SubscribeAsync(clusterSleepScheduleChangedTopic, c.enqueueCluster, false) // nolint: errcheck
+
+	// a finished update (eg. scaling the node pools) may allow a pending transition
+	c.clusterEvents.SubscribeAsync(clusterUpdatedTopic, c.enqueueCluster, false) // nolint: errcheck
+
+	go c.runWorker()
+
+	return nil
+}
+
+func (c *SleepController) Stop() {
+	c.logger.Info("shutting cluster sleep controller")
+	c.queue.ShutDown()
+}
+
+func (c *SleepController) enqueueCluster(clusterID uint) {
+	if !c.queue.ShuttingDown() {
+		c.queue.Add(clusterID)
+	}
+}
+
+// runWorker runs the loop that processes clusters taken from the workqueue
+func (c *SleepController) runWorker() {
+	// loop until we are told to quit
+	for c.processNextCluster() {
+	}
+}
+
+// processNextCluster takes one cluster id off the queue for processing.
+// It returns false when it's time to quit
+func (c *SleepController) processNextCluster() bool {
+	clusterID, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+
+	defer c.queue.Done(clusterID)
+
+	err := c.handleCluster(clusterID.(uint))
+	if err != nil {
+		c.errorHandler.Handle(err)
+
+		c.queue.AddRateLimited(clusterID)
+	} else {
+		c.queue.Forget(clusterID)
+	}
+
+	return true
+}
+
+func (c *SleepController) handleCluster(clusterID uint) error {
+	schedule, err := c.schedules.GetSchedule(clusterID)
+	if errors.As(err, &sleepschedule.ScheduleNotFoundError{}) {
+		return nil
+	} else if err != nil {
+		return emperror.WrapWith(err, "failed to retrieve sleep schedule", "clusterID", clusterID)
+	}
+
+	cluster, err := c.manager.GetClusterByIDOnly(context.Background(), clusterID)
+	if intCluster.IsClusterNotFoundError(err) {
+		c.logger.WithField("clusterID", clusterID).Info("cluster is deleted, removing its sleep schedule")
+
+		return c.schedules.PurgeSchedule(clusterID)
+	} else if err != nil {
+		return emperror.WrapWith(err, "failed to retrieve cluster", "clusterID", clusterID)
+	}
+
+	clusterDetail, err := cluster.GetStatus()
+	if err != nil {
+		return emperror.WrapWith(err, "failed to retrieve cluster details", "clusterID", clusterID)
+	}
+
+	log := c.logger.WithFields(logrus.Fields{
+		"organization": cluster.GetOrganizationId(),
+		"clusterID":    cluster.GetID(),
+		"cluster":      cluster.GetName(),
+		"status":       clusterDetail.Status,
+		"asleep":       schedule.Asleep,
+	})
+
+	asleep, next, err := schedule.DesiredState(time.Now())
+	if err != nil {
+		return emperror.WrapWith(err, "invalid sleep schedule", "clusterID", clusterID)
+	}
+
+	log = log.WithField("next", next)
+
+	if asleep == schedule.Asleep {
+		log.Debug("cluster is in the scheduled state, schedule it for re-check at the next transition")
+		c.queue.AddAfter(clusterID, time.Until(next))
+
+		return nil
+	}
+
+	if !SleepScheduleSupported(cluster.GetDistribution()) {
+		message := fmt.Sprintf("node pools of %s clusters cannot be scaled", cluster.GetDistribution())
+		log.Warn(message + ", skip transition")
+
+		if err := c.schedules.RecordError(clusterID, message); err != nil {
+			return emperror.WrapWith(err, "failed to record sleep schedule error", "clusterID", clusterID)
+		}
+
+		return nil
+	}
+
+	// updating clusters are processed once they finished updating (see the cluster updated subscription)
+	if clusterDetail.Status != pkgCluster.Running && clusterDetail.Status != pkgCluster.Warning {
+		log.Infof("cluster is not in any of [%s, %s] states, postpone transition", pkgCluster.Running, pkgCluster.Warning)
+		c.queue.AddAfter(clusterID, sleepRecheckInterval)
+
+		return nil
+	}
+
+	var sizes sleepschedule.NodePoolSizes
+	request := &pkgCluster.UpdateNodePoolsRequest{
+		NodePools: make(map[string]*pkgCluster.NodePoolData),
+	}
+
+	if asleep {
+		log.Info("putting cluster to sleep")
+
+		sizes = make(sleepschedule.NodePoolSizes, len(clusterDetail.NodePools))
+		for name, nodePool := range clusterDetail.NodePools {
+			sizes[name] = nodePool.Count
+			request.NodePools[name] = &pkgCluster.NodePoolData{Count: 0}
+		}
+	} else {
+		log.Info("waking cluster up")
+
+		for name, count := range schedule.NodePoolSizes {
+			// node pools deleted while the cluster was asleep are not restored
+			if cluster.NodePoolExists(name) {
+				request.NodePools[name] = &pkgCluster.NodePoolData{Count: count}
+			}
+		}
+	}
+
+	updateCtx := UpdateContext{
+		OrganizationID: cluster.GetOrganizationId(),
+		UserID:         schedule.CreatedBy,
+		ClusterID:      clusterID,
+	}
+
+	// the transition is recorded only if the node pools are scaled, otherwise it is retried
+	err = c.manager.UpdateClusterAndWait(context.Background(), updateCtx, NewCommonNodepoolUpdater(request, cluster, schedule.CreatedBy))
+	if err == nil {
+		err = c.checkNodePoolSizes(clusterID, request)
+	}
+	if err != nil {
+		if recordErr := c.schedules.RecordError(clusterID, err.Error()); recordErr != nil {
+			c.errorHandler.Handle(recordErr)
+		}
+
+		return emperror.WrapWith(err, "failed to update node pools", "clusterID", clusterID, "asleep", asleep)
+	}
+
+	if err := c.schedules.RecordTransition(clusterID, asleep, sizes); err != nil {
+		return emperror.WrapWith(err, "failed to record sleep schedule transition", "clusterID", clusterID)
+	}
+
+	c.queue.AddAfter(clusterID, time.Until(next))
+
+	return nil
+}
+
+// checkNodePoolSizes returns an error if the node pools of the updated cluster do not have the requested sizes
+func (c *SleepController) checkNodePoolSizes(clusterID uint, request *pkgCluster.UpdateNodePoolsRequest) error {
+	cluster, err := c.manager.GetClusterByIDOnly(context.Background(), clusterID)
+	if err != nil {
+		return emperror.WrapWith(err, "failed to retrieve cluster", "clusterID", clusterID)
+	}
+
+	clusterDetail, err := cluster.GetStatus()
+	if err != nil {
+		return emperror.WrapWith(err, "failed to retrieve cluster details", "clusterID", clusterID)
+	}
+
+	if clusterDetail.Status != pkgCluster.Running {
+		return errors.Errorf("cluster is in %s state after updating its node pools: %s", clusterDetail.Status, clusterDetail.StatusMessage)
+	}
+
+	for name, nodePool := range request.NodePools {
+		current, ok := clusterDetail.NodePools[name]
+		if !ok {
+			continue
+		}
+
+		if current.Count != nodePool.Count {
+			return errors.Errorf("node pool %s has %d nodes instead of %d", name, current.Count, nodePool.Count)
+		}
+	}
+
+	return nil
+}
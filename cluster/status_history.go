@@ -0,0 +1,106 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/model"
+)
+
+// GetClusterStatusHistory returns the status transitions of a cluster in chronological order.
+// The history of deleted clusters is kept, so it can be used to investigate failed provisioning after the fact.
+func (m *Manager) GetClusterStatusHistory(ctx context.Context, organizationID uint, clusterID uint) ([]cluster.StatusHistoryModel, error) {
+	logger := m.getLogger(ctx).WithFields(logrus.Fields{
+		"organization": organizationID,
+		"cluster":      clusterID,
+	})
+
+	logger.Debug("getting cluster status history from database")
+
+	history, err := m.clusters.FindStatusHistory(organizationID, clusterID)
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not get cluster status history from database")
+	}
+
+	return history, nil
+}
+
+// RegisterStatusChangeEvents emits a ClusterStatusChanged event for every status transition recorded in the status history.
+//
+// Status transitions are recorded by the different cluster implementations (and models) directly,
+// so the database is the only common point where they can be observed.
+func RegisterStatusChangeEvents(db *gorm.DB, events clusterEvents, logger logrus.FieldLogger) {
+	historyTableName := cluster.StatusHistoryModel{}.TableName()
+
+	db.Callback().Create().
+		After("gorm:commit_or_rollback_transaction").
+		Register("pipeline:cluster_status_changed", func(scope *gorm.Scope) {
+			if scope.HasError() || scope.TableName() != historyTableName {
+				return
+			}
+
+			change := StatusChange{
+				ClusterID:         statusHistoryUintField(scope, "ClusterID"),
+				ClusterName:       statusHistoryStringField(scope, "ClusterName"),
+				FromStatus:        statusHistoryStringField(scope, "FromStatus"),
+				FromStatusMessage: statusHistoryStringField(scope, "FromStatusMessage"),
+				ToStatus:          statusHistoryStringField(scope, "ToStatus"),
+				ToStatusMessage:   statusHistoryStringField(scope, "ToStatusMessage"),
+				Time:              time.Now(),
+			}
+
+			if field, ok := scope.FieldByName("CreatedAt"); ok {
+				if createdAt, ok := field.Field.Interface().(time.Time); ok && !createdAt.IsZero() {
+					change.Time = createdAt
+				}
+			}
+
+			var clusterModel model.ClusterModel
+			err := scope.NewDB().Unscoped().Select("organization_id").Where("id = ?", change.ClusterID).First(&clusterModel).Error
+			if err != nil {
+				logger.WithField("cluster", change.ClusterID).Warnf("failed to get organization of cluster status change: %s", err.Error())
+			}
+			change.OrganizationID = clusterModel.OrganizationId
+
+			events.ClusterStatusChanged(change)
+		})
+}
+
+func statusHistoryStringField(scope *gorm.Scope, name string) string {
+	if field, ok := scope.FieldByName(name); ok {
+		if value, ok := field.Field.Interface().(string); ok {
+			return value
+		}
+	}
+
+	return ""
+}
+
+func statusHistoryUintField(scope *gorm.Scope, name string) uint {
+	if field, ok := scope.FieldByName(name); ok {
+		if value, ok := field.Field.Interface().(uint); ok {
+			return value
+		}
+	}
+
+	return 0
+}
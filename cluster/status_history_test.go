@@ -0,0 +1,90 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/model"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+type statusChangeRecorder struct {
+	nopClusterEvents
+
+	changes []StatusChange
+}
+
+func (r *statusChangeRecorder) ClusterStatusChanged(change StatusChange) {
+	r.changes = append(r.changes, change)
+}
+
+func TestRegisterStatusChangeEvents(t *testing.T) {
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, db.AutoMigrate(&model.ClusterModel{}, &cluster.StatusHistoryModel{}).Error)
+
+	clusterModel := model.ClusterModel{ID: 1, Name: "test", OrganizationId: 2}
+	require.NoError(t, db.Create(&clusterModel).Error)
+
+	events := &statusChangeRecorder{}
+	RegisterStatusChangeEvents(db, events, logrus.New())
+
+	require.NoError(t, db.Save(&model.StatusHistoryModel{
+		ClusterID:         1,
+		ClusterName:       "test",
+		FromStatus:        pkgCluster.Creating,
+		FromStatusMessage: pkgCluster.CreatingMessage,
+		ToStatus:          pkgCluster.Running,
+		ToStatusMessage:   pkgCluster.RunningMessage,
+	}).Error)
+
+	// Other models should not emit events
+	require.NoError(t, db.Save(&model.ClusterModel{ID: 3, Name: "other", OrganizationId: 2}).Error)
+
+	require.NoError(t, db.Delete(&clusterModel).Error)
+
+	require.NoError(t, db.Save(&cluster.StatusHistoryModel{
+		ClusterID:         1,
+		ClusterName:       "test",
+		FromStatus:        pkgCluster.Deleting,
+		FromStatusMessage: pkgCluster.DeletingMessage,
+		ToStatus:          pkgCluster.Deleted,
+		ToStatusMessage:   pkgCluster.DeletedMessage,
+	}).Error)
+
+	require.Len(t, events.changes, 2)
+
+	assert.Equal(t, uint(2), events.changes[0].OrganizationID)
+	assert.Equal(t, uint(1), events.changes[0].ClusterID)
+	assert.Equal(t, "test", events.changes[0].ClusterName)
+	assert.Equal(t, pkgCluster.Creating, events.changes[0].FromStatus)
+	assert.Equal(t, pkgCluster.Running, events.changes[0].ToStatus)
+	assert.Equal(t, pkgCluster.RunningMessage, events.changes[0].ToStatusMessage)
+	assert.False(t, events.changes[0].Time.IsZero())
+
+	// The organization of deleted clusters is resolved as well
+	assert.Equal(t, uint(2), events.changes[1].OrganizationID)
+	assert.Equal(t, pkgCluster.Deleted, events.changes[1].ToStatus)
+}
@@ -0,0 +1,179 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"emperror.dev/errors"
+	"go.uber.org/cadence/workflow"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+const UpgradeClusterWorkflowName = "upgrade-cluster"
+
+// UpgradeClusterWorkflowInput contains the parameters of a Kubernetes version upgrade.
+type UpgradeClusterWorkflowInput struct {
+	ClusterID   uint
+	FromVersion string
+	Version     string
+	NodePools   []string
+	MaxSurge    int
+}
+
+// UpgradeClusterWorkflow upgrades the control plane of a cluster first, then its node pools one by one.
+func UpgradeClusterWorkflow(ctx workflow.Context, input UpgradeClusterWorkflowInput) error {
+	ao := workflow.ActivityOptions{
+		ScheduleToStartTimeout: 10 * time.Minute,
+		StartToCloseTimeout:    5 * time.Minute,
+		WaitForCancellation:    true,
+	}
+
+	ctx = workflow.WithActivityOptions(ctx, ao)
+
+	setStatus := func(message string) error {
+		activityInput := UpdateClusterStatusActivityInput{
+			ClusterID:     input.ClusterID,
+			Status:        pkgCluster.Updating,
+			StatusMessage: message,
+		}
+
+		return workflow.ExecuteActivity(ctx, UpdateClusterStatusActivityName, activityInput).Get(ctx, nil)
+	}
+
+	// Upgrade control plane
+	{
+		if err := setStatus(fmt.Sprintf("upgrading control plane to %s", input.Version)); err != nil {
+			return err
+		}
+
+		activityInput := UpgradeControlPlaneActivityInput{
+			ClusterID: input.ClusterID,
+			Version:   input.Version,
+		}
+
+		ctx := workflow.WithStartToCloseTimeout(ctx, time.Hour)
+
+		err := workflow.ExecuteActivity(ctx, UpgradeControlPlaneActivityName, activityInput).Get(ctx, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Upgrade node pools
+	for i, nodePool := range input.NodePools {
+		if err := setStatus(fmt.Sprintf("upgrading node pool %s to %s (%d/%d)", nodePool, input.Version, i+1, len(input.NodePools))); err != nil {
+			return err
+		}
+
+		activityInput := UpgradeNodePoolActivityInput{
+			ClusterID:   input.ClusterID,
+			NodePool:    nodePool,
+			FromVersion: input.FromVersion,
+			Version:     input.Version,
+			MaxSurge:    input.MaxSurge,
+		}
+
+		ctx := workflow.WithStartToCloseTimeout(ctx, 3*time.Hour)
+
+		err := workflow.ExecuteActivity(ctx, UpgradeNodePoolActivityName, activityInput).Get(ctx, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// clusterUpgrader is implemented by clusters supporting Kubernetes version upgrades.
+type clusterUpgrader interface {
+	// UpgradeControlPlane upgrades the master nodes of the cluster to the given Kubernetes version.
+	UpgradeControlPlane(ctx context.Context, version string) error
+
+	// UpgradeNodePool replaces the nodes of a node pool with ones running the given Kubernetes version.
+	UpgradeNodePool(ctx context.Context, nodePool string, fromVersion string, version string, maxSurge int) error
+}
+
+const UpgradeControlPlaneActivityName = "upgrade-cluster-control-plane"
+
+type UpgradeControlPlaneActivityInput struct {
+	ClusterID uint
+	Version   string
+}
+
+type UpgradeControlPlaneActivity struct {
+	manager *Manager
+}
+
+func NewUpgradeControlPlaneActivity(manager *Manager) *UpgradeControlPlaneActivity {
+	return &UpgradeControlPlaneActivity{
+		manager: manager,
+	}
+}
+
+func (a *UpgradeControlPlaneActivity) Execute(ctx context.Context, input UpgradeControlPlaneActivityInput) error {
+	upgrader, err := getClusterUpgrader(ctx, a.manager, input.ClusterID)
+	if err != nil {
+		return err
+	}
+
+	return upgrader.UpgradeControlPlane(ctx, input.Version)
+}
+
+const UpgradeNodePoolActivityName = "upgrade-cluster-node-pool"
+
+type UpgradeNodePoolActivityInput struct {
+	ClusterID   uint
+	NodePool    string
+	FromVersion string
+	Version     string
+	MaxSurge    int
+}
+
+type UpgradeNodePoolActivity struct {
+	manager *Manager
+}
+
+func NewUpgradeNodePoolActivity(manager *Manager) *UpgradeNodePoolActivity {
+	return &UpgradeNodePoolActivity{
+		manager: manager,
+	}
+}
+
+func (a *UpgradeNodePoolActivity) Execute(ctx context.Context, input UpgradeNodePoolActivityInput) error {
+	upgrader, err := getClusterUpgrader(ctx, a.manager, input.ClusterID)
+	if err != nil {
+		return err
+	}
+
+	return upgrader.UpgradeNodePool(ctx, input.NodePool, input.FromVersion, input.Version, input.MaxSurge)
+}
+
+func getClusterUpgrader(ctx context.Context, manager *Manager, clusterID uint) (clusterUpgrader, error) {
+	cluster, err := manager.GetClusterByIDOnly(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	upgrader, ok := cluster.(clusterUpgrader)
+	if !ok {
+		return nil, errors.NewWithDetails("cluster does not support Kubernetes version upgrades", "clusterID", clusterID, "cloud", cluster.GetCloud())
+	}
+
+	return upgrader, nil
+}
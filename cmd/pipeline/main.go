@@ -50,6 +50,8 @@ import (
 	"github.com/banzaicloud/pipeline/cluster"
 	"github.com/banzaicloud/pipeline/config"
 	"github.com/banzaicloud/pipeline/dns"
+	"github.com/banzaicloud/pipeline/internal/apply"
+	"github.com/banzaicloud/pipeline/internal/apply/applyadapter"
 	arkClusterManager "github.com/banzaicloud/pipeline/internal/ark/clustermanager"
 	arkEvents "github.com/banzaicloud/pipeline/internal/ark/events"
 	arkSync "github.com/banzaicloud/pipeline/internal/ark/sync"
@@ -58,13 +60,19 @@ import (
 	"github.com/banzaicloud/pipeline/internal/cloudinfo"
 	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
 	intClusterAuth "github.com/banzaicloud/pipeline/internal/cluster/auth"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterclone"
+	"github.com/banzaicloud/pipeline/internal/cluster/clustercost"
 	"github.com/banzaicloud/pipeline/internal/cluster/clustersecret"
 	"github.com/banzaicloud/pipeline/internal/cluster/clustersecret/clustersecretadapter"
 	prometheusMetrics "github.com/banzaicloud/pipeline/internal/cluster/metrics/adapters/prometheus"
+	"github.com/banzaicloud/pipeline/internal/cluster/sleepschedule"
 	"github.com/banzaicloud/pipeline/internal/clusterfeature"
 	"github.com/banzaicloud/pipeline/internal/clusterfeature/clusterfeatureadapter"
 	"github.com/banzaicloud/pipeline/internal/clusterfeature/clusterfeaturedriver"
 	featureDns "github.com/banzaicloud/pipeline/internal/clusterfeature/features/dns"
+	featureLogging "github.com/banzaicloud/pipeline/internal/clusterfeature/features/logging"
+	featureMonitoring "github.com/banzaicloud/pipeline/internal/clusterfeature/features/monitoring"
+	featureVault "github.com/banzaicloud/pipeline/internal/clusterfeature/features/vault"
 	"github.com/banzaicloud/pipeline/internal/clustergroup"
 	cgroupAdapter "github.com/banzaicloud/pipeline/internal/clustergroup/adapter"
 	"github.com/banzaicloud/pipeline/internal/clustergroup/deployment"
@@ -76,6 +84,7 @@ import (
 	"github.com/banzaicloud/pipeline/internal/helm/helmadapter"
 	cgFeatureIstio "github.com/banzaicloud/pipeline/internal/istio/istiofeature"
 	"github.com/banzaicloud/pipeline/internal/monitor"
+	"github.com/banzaicloud/pipeline/internal/network/ipam"
 	"github.com/banzaicloud/pipeline/internal/notification"
 	"github.com/banzaicloud/pipeline/internal/platform/buildinfo"
 	"github.com/banzaicloud/pipeline/internal/platform/errorhandler"
@@ -87,7 +96,10 @@ import (
 	platformlog "github.com/banzaicloud/pipeline/internal/platform/log"
 	azurePKEAdapter "github.com/banzaicloud/pipeline/internal/providers/azure/pke/adapter"
 	azurePKEDriver "github.com/banzaicloud/pipeline/internal/providers/azure/pke/driver"
+	intSecret "github.com/banzaicloud/pipeline/internal/secret"
+	secretrotation "github.com/banzaicloud/pipeline/internal/secret/rotation"
 	anchore "github.com/banzaicloud/pipeline/internal/security"
+	"github.com/banzaicloud/pipeline/internal/webhook"
 	"github.com/banzaicloud/pipeline/model/defaults"
 	"github.com/banzaicloud/pipeline/pkg/ctxutil"
 	"github.com/banzaicloud/pipeline/pkg/k8sclient"
@@ -202,6 +214,7 @@ func main() {
 
 	clusterEventBus := evbus.New()
 	clusterEvents := cluster.NewClusterEvents(clusterEventBus)
+	cluster.RegisterStatusChangeEvents(db, clusterEvents, logrusLogger.WithField("subsystem", "cluster-status-history"))
 	clusters := intCluster.NewClusters(db)
 	secretValidator := providers.NewSecretValidator(secret.Store)
 	statusChangeDurationMetric := prometheusMetrics.MakePrometheusClusterStatusChangeDurationMetric()
@@ -256,6 +269,12 @@ func main() {
 	err = clusterTTLController.Start()
 	emperror.Panic(err)
 
+	sleepScheduleManager := sleepschedule.NewManager(db, cluster.NewSleepScheduleEvents(clusterEventBus))
+	clusterSleepController := cluster.NewSleepController(clusterManager, sleepScheduleManager, clusterEventBus, logrusLogger.WithField("subsystem", "sleep-controller"), errorHandler)
+	defer clusterSleepController.Stop()
+	err = clusterSleepController.Start()
+	emperror.Panic(err)
+
 	if viper.GetBool(config.MonitorEnabled) {
 		client, err := k8sclient.NewInClusterClient()
 		if err != nil {
@@ -288,6 +307,21 @@ func main() {
 		go monitor.NewSpotMetricsExporter(context.Background(), clusterManager, logrusLogger.WithField("subsystem", "spot-metrics-exporter")).Run(viper.GetDuration(config.SpotMetricsCollectionInterval))
 	}
 
+	webhookManager := webhook.NewManager(db, secret.Store)
+	webhookDispatcher := webhook.NewDispatcher(
+		db,
+		secret.Store,
+		webhook.DispatcherConfig{
+			MaxAttempts:   viper.GetInt(config.WebhookMaxAttempts),
+			RetryInterval: viper.GetDuration(config.WebhookRetryInterval),
+			Timeout:       viper.GetDuration(config.WebhookTimeout),
+		},
+		logrusLogger.WithField("subsystem", "webhook"),
+		errorHandler,
+	)
+	err = webhook.NewClusterEventSubscriber(clusterManager, webhookDispatcher, errorHandler).Register(clusterEventBus)
+	emperror.Panic(err)
+
 	cloudInfoEndPoint := viper.GetString(config.CloudInfoEndPoint)
 	if cloudInfoEndPoint == "" {
 		errorHandler.Handle(errors.New("missing CloudInfo endpoint"))
@@ -337,7 +371,9 @@ func main() {
 		),
 	}
 
-	clusterAPI := api.NewClusterAPI(clusterManager, clusterGetter, workflowClient, cloudInfoClient, clusterGroupManager, logrusLogger, errorHandler, externalBaseURL, externalURLInsecure, clusterCreators, clusterDeleters, clusterUpdaters)
+	cloneTaskManager := clusterclone.NewManager(db)
+
+	clusterAPI := api.NewClusterAPI(clusterManager, clusterGetter, workflowClient, cloudInfoClient, clusterGroupManager, logrusLogger, errorHandler, externalBaseURL, externalURLInsecure, clusterCreators, clusterDeleters, clusterUpdaters, cloneTaskManager)
 
 	nplsApi := api.NewNodepoolManagerAPI(clusterGetter, logrusLogger, errorHandler)
 
@@ -384,7 +420,7 @@ func main() {
 
 	authorizationMiddleware := intAuth.NewMiddleware(enforcer, basePath, errorHandler)
 
-	dashboardAPI := dashboard.NewDashboardAPI(clusterManager, clusterGroupManager, logrusLogger, errorHandler)
+	dashboardAPI := dashboard.NewDashboardAPI(clusterManager, clusterGroupManager, clustercost.NewEstimator(cloudInfoClient), logrusLogger, errorHandler)
 	dgroup := base.Group(path.Join("dashboard", "orgs"))
 	dgroup.Use(auth.Handler)
 	dgroup.Use(api.OrganizationMiddleware)
@@ -401,7 +437,16 @@ func main() {
 	domainAPI := api.NewDomainAPI(clusterManager, logrusLogger, errorHandler)
 	organizationAPI := api.NewOrganizationAPI(orgImporter)
 	userAPI := api.NewUserAPI(accessManager, db, logrusLogger, errorHandler)
-	networkAPI := api.NewNetworkAPI(logrusLogger)
+	networkAPI := api.NewNetworkAPI(ipam.NewPlanner(clusterManager, logrusLogger), logrusLogger)
+	auditAPI := api.NewAuditAPI(audit.NewEventStore(db), db, logrusLogger, errorHandler)
+	webhookAPI := api.NewWebhookAPI(webhookManager, logrusLogger, errorHandler)
+	sleepScheduleAPI := api.NewSleepScheduleAPI(sleepScheduleManager, clusterGetter, logrusLogger, errorHandler)
+	secretRotationAPI := api.NewSecretRotationAPI(
+		secretrotation.NewManager(db, secret.RestrictedStore),
+		workflowClient,
+		logrusLogger,
+		errorHandler,
+	)
 
 	switch viper.GetString(config.DNSBaseDomain) {
 	case "", "example.com", "example.org":
@@ -458,6 +503,11 @@ func main() {
 		errorHandler.Handle(errors.WrapIf(err, "failed to schedule syncing shared spotguides"))
 	}
 
+	// periodically rotate secrets with a rotation policy
+	if err := secretrotation.ScheduleRotation(workflowClient, viper.GetDuration(config.SecretRotationCheckInterval)); err != nil {
+		errorHandler.Handle(errors.WrapIf(err, "failed to schedule secret rotation"))
+	}
+
 	spotguideAPI := api.NewSpotguideAPI(logrusLogger, errorHandler, spotguideManager)
 
 	v1 := base.Group("api/v1")
@@ -484,6 +534,8 @@ func main() {
 			orgs.POST("/:orgid/clusters", clusterAPI.CreateCluster)
 			// v1.GET("/status", api.Status)
 			orgs.GET("/:orgid/clusters", clusterAPI.GetClusters)
+			// the status history is available for deleted clusters as well, so it's not part of the cluster API group
+			orgs.GET("/:orgid/clusters/:id/history", clusterAPI.GetClusterStatusHistory)
 
 			// cluster API
 			cRouter := orgs.Group("/:orgid/clusters/:id")
@@ -491,8 +543,18 @@ func main() {
 				cRouter.Use(cluster.NewClusterCheckMiddleware(clusterManager, errorHandler))
 
 				cRouter.GET("", clusterAPI.GetCluster)
+				cRouter.POST("/clone", clusterAPI.CloneCluster)
+				cRouter.GET("/migrations", clusterAPI.ListClusterMigrations)
+				cRouter.POST("/migrations", clusterAPI.CreateClusterMigration)
+				cRouter.GET("/migrations/:migrationId", clusterAPI.GetClusterMigration)
 				cRouter.GET("/pods", api.GetPodDetails)
 				cRouter.GET("/bootstrap", clusterAPI.GetBootstrapInfo)
+				cRouter.GET("/cost", clusterAPI.GetClusterCost)
+				cRouter.GET("/sleepschedule", sleepScheduleAPI.GetSchedule)
+				cRouter.PUT("/sleepschedule", sleepScheduleAPI.SetSchedule)
+				cRouter.DELETE("/sleepschedule", sleepScheduleAPI.DeleteSchedule)
+				cRouter.PUT("/sleepschedule/override", sleepScheduleAPI.SetOverride)
+				cRouter.DELETE("/sleepschedule/override", sleepScheduleAPI.ClearOverride)
 				cRouter.PUT("", clusterAPI.UpdateCluster)
 
 				cRouter.PUT("/posthooks", clusterAPI.ReRunPostHooks)
@@ -511,6 +573,8 @@ func main() {
 				cRouter.POST("/deployments", api.CreateDeployment)
 				cRouter.GET("/deployments/:name", api.GetDeployment)
 				cRouter.GET("/deployments/:name/resources", api.GetDeploymentResources)
+				cRouter.GET("/deployments/:name/history", api.GetDeploymentHistory)
+				cRouter.POST("/deployments/:name/rollback", api.RollbackDeployment)
 				cRouter.GET("/hpa", api.GetHpaResource)
 				cRouter.PUT("/hpa", api.PutHpaResource)
 				cRouter.DELETE("/hpa", api.DeleteHpaResource)
@@ -548,21 +612,49 @@ func main() {
 				clustersecretadapter.NewSecretStore(secret.Store),
 			)
 
+			var featureService *clusterfeature.FeatureService
+
 			// ClusterInfo Feature API
 			{
 				logger := commonadapter.NewLogger(logger) // TODO: make this a context aware logger
-				featureRepository := clusterfeatureadapter.NewGormFeatureRepository(db, logger)
+				featureRepository := clusterfeatureadapter.NewGormFeatureRepository(db, clusterfeatureadapter.UserIDContextExtractorFunc(auth.GetCurrentUserID), logger)
 				helmService := helm.NewHelmService(helmadapter.NewClusterService(clusterManager), logger)
 				secretStore := commonadapter.NewSecretStore(secret.Store, commonadapter.OrgIDContextExtractorFunc(auth.GetCurrentOrganizationID))
 				clusterService := clusterfeatureadapter.NewClusterService(clusterManager)
 				orgDomainService := featureDns.NewOrgDomainService(clusterManager, dnsSvc, logger)
 				dnsFeatureManager := featureDns.NewDnsFeatureManager(featureRepository, secretStore, clusterService, clusterManager, helmService, orgDomainService, logger)
-				featureRegistry := clusterfeature.NewFeatureRegistry(map[string]clusterfeature.FeatureManager{
-					dnsFeatureManager.Name(): clusterfeatureadapter.NewAsyncFeatureManagerStub(dnsFeatureManager, featureRepository, workflowClient, logger),
-				})
+				secretInstaller := clusterfeatureadapter.NewKubernetesSecretInstaller(clusterManager)
+				loggingFeatureManager := featureLogging.NewLoggingFeatureManager(featureRepository, secretStore, clusterSecretStore, secretInstaller, clusterService, clusterManager, helmService, logger)
+				serviceProxy := clusterfeatureadapter.NewKubernetesServiceProxy(clusterManager)
+				monitoringFeatureManager := featureMonitoring.NewMonitoringFeatureManager(featureRepository, secretStore, secretInstaller, serviceProxy, clusterService, clusterManager, helmService, logger)
+				featureManagers := map[string]clusterfeature.FeatureManager{
+					dnsFeatureManager.Name():        clusterfeatureadapter.NewAsyncFeatureManagerStub(dnsFeatureManager, featureRepository, workflowClient, logger),
+					loggingFeatureManager.Name():    clusterfeatureadapter.NewAsyncFeatureManagerStub(loggingFeatureManager, featureRepository, workflowClient, logger),
+					monitoringFeatureManager.Name(): clusterfeatureadapter.NewAsyncFeatureManagerStub(monitoringFeatureManager, featureRepository, workflowClient, logger),
+				}
+
+				// Vault integration is only available when Pipeline stores secrets in Vault
+				if viper.GetString(config.SecretStoreBackend) == secret.VaultBackend {
+					vaultFeatureManager := featureVault.NewVaultFeatureManager(featureRepository, secretStore, secret.VaultClient().Vault(), viper.GetString(config.VaultExternalAddress), clusterService, clusterManager, helmService, logger)
+					featureManagers[vaultFeatureManager.Name()] = clusterfeatureadapter.NewAsyncFeatureManagerStub(vaultFeatureManager, featureRepository, workflowClient, logger)
+				}
+
+				featureRegistry := clusterfeature.NewFeatureRegistry(featureManagers)
+
+				featureService = clusterfeature.NewFeatureService(featureRegistry, featureRepository, logger)
 
-				service := clusterfeature.NewFeatureService(featureRegistry, featureRepository, logger)
-				endpoints := clusterfeaturedriver.MakeEndpoints(service)
+				cloneRunner := clusterclone.NewRunner(
+					cloneTaskManager,
+					clusterManager,
+					featureService,
+					clusterclone.NewHelmReleaseCopier(viper.GetString(config.PipelineSystemNamespace)),
+					logrusLogger.WithField("subsystem", "cluster-clone"),
+					errorHandler,
+				)
+				err = cloneRunner.Register(clusterEventBus)
+				emperror.Panic(err)
+
+				endpoints := clusterfeaturedriver.MakeEndpoints(featureService)
 				handlers := clusterfeaturedriver.MakeHTTPHandlers(endpoints, errorHandler)
 
 				router := cRouter.Group("/features")
@@ -572,6 +664,8 @@ func main() {
 				router.DELETE("/:featureName", ginutils.HTTPHandlerToGinHandlerFunc(handlers.Deactivate))
 				router.POST("/:featureName", ginutils.HTTPHandlerToGinHandlerFunc(handlers.Activate))
 				router.PUT("/:featureName", ginutils.HTTPHandlerToGinHandlerFunc(handlers.Update))
+				router.GET("/:featureName/revisions", ginutils.HTTPHandlerToGinHandlerFunc(handlers.Revisions))
+				router.POST("/:featureName/revisions/:revision/apply", ginutils.HTTPHandlerToGinHandlerFunc(handlers.ApplyRevision))
 			}
 
 			// ClusterGroupAPI
@@ -581,8 +675,8 @@ func main() {
 			cRouter.GET("/nodepools/labels", nplsApi.GetNodepoolLabelSets)
 			cRouter.POST("/nodepools/labels", nplsApi.SetNodepoolLabelSets)
 
-			namespaceAPI := namespace.NewAPI(clusterGetter, errorHandler)
-			namespaceAPI.RegisterRoutes(cRouter.Group("/namespaces/:namespace"))
+			namespaceAPI := namespace.NewAPI(clusterGetter, viper.GetString(config.PipelineSystemNamespace), errorHandler)
+			namespaceAPI.RegisterRoutes(cRouter.Group("/namespaces"))
 
 			pkeGroup := cRouter.Group("/pke")
 
@@ -629,14 +723,40 @@ func main() {
 			orgs.PUT("/:orgid/secrets/:id", api.UpdateSecrets)
 			orgs.DELETE("/:orgid/secrets/:id", api.DeleteSecrets)
 			orgs.GET("/:orgid/secrets/:id/validate", api.ValidateSecret)
+			orgs.GET("/:orgid/secrets/:id/usages", api.GetSecretUsages)
+			orgs.GET("/:orgid/secrets/:id/rotation", secretRotationAPI.GetPolicy)
+			orgs.PUT("/:orgid/secrets/:id/rotation", secretRotationAPI.SetPolicy)
+			orgs.DELETE("/:orgid/secrets/:id/rotation", secretRotationAPI.DeletePolicy)
+			orgs.POST("/:orgid/secrets/:id/rotate", secretRotationAPI.RotateSecret)
+			orgs.GET("/:orgid/secrets/:id/versions", api.ListSecretVersions)
+			orgs.GET("/:orgid/secrets/:id/versions/:version", api.GetSecretVersion)
+			orgs.POST("/:orgid/secrets/:id/versions/:version/restore", api.RestoreSecretVersion)
 			orgs.GET("/:orgid/secrets/:id/tags", api.GetSecretTags)
 			orgs.PUT("/:orgid/secrets/:id/tags/*tag", api.AddSecretTag)
 			orgs.DELETE("/:orgid/secrets/:id/tags/*tag", api.DeleteSecretTag)
 			orgs.GET("/:orgid/users", userAPI.GetUsers)
 			orgs.GET("/:orgid/users/:id", userAPI.GetUsers)
 			orgs.POST("/:orgid/users/:id", userAPI.AddUser)
+			orgs.PUT("/:orgid/users/:id", userAPI.UpdateUser)
 			orgs.DELETE("/:orgid/users/:id", userAPI.RemoveUser)
 
+			orgs.GET("/:orgid/audit", auditAPI.ListEvents)
+
+			orgs.GET("/:orgid/webhooks", webhookAPI.ListSubscriptions)
+			orgs.POST("/:orgid/webhooks", webhookAPI.CreateSubscription)
+			orgs.GET("/:orgid/webhooks/:id", webhookAPI.GetSubscription)
+			orgs.DELETE("/:orgid/webhooks/:id", webhookAPI.DeleteSubscription)
+			orgs.GET("/:orgid/webhooks/:id/deliveries", webhookAPI.ListDeliveries)
+
+			applier := apply.NewApplier(apply.NewGormStore(db), map[string]apply.Reconciler{
+				apply.KindSecret:         applyadapter.NewSecretReconciler(secret.RestrictedStore, intSecret.NewUsageFinder(db)),
+				apply.KindCluster:        clusterAPI.ClusterReconciler(),
+				apply.KindDeployment:     applyadapter.NewDeploymentReconciler(clusterManager),
+				apply.KindClusterFeature: applyadapter.NewFeatureReconciler(clusterManager, featureService),
+			}, logrusLogger)
+			applyAPI := api.NewApplyAPI(applier, logrusLogger, errorHandler)
+			orgs.POST("/:orgid/apply", applyAPI.Apply)
+
 			orgs.GET("/:orgid/buckets", api.ListAllBuckets)
 			orgs.POST("/:orgid/buckets", api.CreateBucket)
 			orgs.HEAD("/:orgid/buckets/:name", api.CheckBucket)
@@ -644,8 +764,14 @@ func main() {
 			orgs.DELETE("/:orgid/buckets/:name", api.DeleteBucket)
 
 			orgs.GET("/:orgid/networks", networkAPI.ListVPCNetworks)
+			orgs.POST("/:orgid/networks", networkAPI.CreateVPCNetwork)
+			orgs.DELETE("/:orgid/networks/:id", networkAPI.DeleteVPCNetwork)
 			orgs.GET("/:orgid/networks/:id/subnets", networkAPI.ListVPCSubnets)
+			orgs.POST("/:orgid/networks/:id/subnets", networkAPI.CreateVPCSubnet)
+			orgs.DELETE("/:orgid/networks/:id/subnets/:subnetId", networkAPI.DeleteVPCSubnet)
 			orgs.GET("/:orgid/networks/:id/routeTables", networkAPI.ListRouteTables)
+			orgs.GET("/:orgid/ipam", networkAPI.ListCIDRAllocations)
+			orgs.GET("/:orgid/ipam/suggest", networkAPI.SuggestCIDR)
 
 			orgs.GET("/:orgid/azure/resourcegroups", api.GetResourceGroups)
 			orgs.POST("/:orgid/azure/resourcegroups", api.AddResourceGroups)
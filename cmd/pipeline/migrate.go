@@ -23,14 +23,21 @@ import (
 
 	"github.com/banzaicloud/pipeline/auth"
 	route53model "github.com/banzaicloud/pipeline/dns/route53/model"
+	"github.com/banzaicloud/pipeline/internal/apply"
 	"github.com/banzaicloud/pipeline/internal/ark"
 	"github.com/banzaicloud/pipeline/internal/audit"
 	"github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterclone"
+	"github.com/banzaicloud/pipeline/internal/cluster/sleepschedule"
 	"github.com/banzaicloud/pipeline/internal/clustergroup"
 	"github.com/banzaicloud/pipeline/internal/notification"
 	"github.com/banzaicloud/pipeline/internal/providers"
+	intSecret "github.com/banzaicloud/pipeline/internal/secret"
+	secretrotation "github.com/banzaicloud/pipeline/internal/secret/rotation"
+	"github.com/banzaicloud/pipeline/internal/webhook"
 	"github.com/banzaicloud/pipeline/model"
 	"github.com/banzaicloud/pipeline/model/defaults"
+	"github.com/banzaicloud/pipeline/secret"
 	"github.com/banzaicloud/pipeline/spotguide"
 )
 
@@ -64,6 +71,14 @@ func Migrate(db *gorm.DB, logger logrus.FieldLogger) error {
 		return err
 	}
 
+	if err := sleepschedule.Migrate(db, logger); err != nil {
+		return err
+	}
+
+	if err := clusterclone.Migrate(db, logger); err != nil {
+		return err
+	}
+
 	if err := clustergroup.Migrate(db, logger); err != nil {
 		return err
 	}
@@ -88,5 +103,25 @@ func Migrate(db *gorm.DB, logger logrus.FieldLogger) error {
 		return err
 	}
 
+	if err := webhook.Migrate(db, logger); err != nil {
+		return err
+	}
+
+	if err := secret.Migrate(db, logger); err != nil {
+		return err
+	}
+
+	if err := intSecret.Migrate(db, logger); err != nil {
+		return err
+	}
+
+	if err := secretrotation.Migrate(db, logger); err != nil {
+		return err
+	}
+
+	if err := apply.Migrate(db, logger); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -53,6 +53,15 @@ func main() {
 	flags.Bool("verify", true, "Verify root CA")
 	_ = viper.BindPFlag("api.verify", flags.Lookup("verify"))
 
+	flags.StringP("token", "t", "", "Pipeline API access token")
+	_ = viper.BindPFlag("api.token", flags.Lookup("token"))
+
+	flags.Int("organization", 0, "Organization ID")
+	_ = viper.BindPFlag("organization", flags.Lookup("organization"))
+
+	flags.StringP("output", "o", "table", "Output format (table, json or yaml)")
+	_ = viper.BindPFlag("output.format", flags.Lookup("output"))
+
 	viper.SetEnvPrefix(envPrefix)
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
 	viper.AutomaticEnv()
@@ -60,6 +69,7 @@ func main() {
 	// Pipeline configuration
 	viper.SetDefault("api.url", "http://127.0.0.1:9090")
 	viper.SetDefault("api.verify", true)
+	viper.SetDefault("output.format", "table")
 
 	cobra.OnInitialize(func() {
 		if !viper.GetBool("api.verify") {
@@ -50,6 +50,11 @@ func registerClusterFeatureWorkflows(featureRegistry clusterfeature.FeatureRegis
 		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: clusterfeatureworkflow.ClusterFeatureSetStatusActivityName})
 	}
 
+	{
+		a := clusterfeatureworkflow.MakeClusterFeatureSetRevisionStatusActivity(featureRepository)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: clusterfeatureworkflow.ClusterFeatureSetRevisionStatusActivityName})
+	}
+
 	{
 		a := clusterfeatureworkflow.MakeClusterFeatureUpdateActivity(featureRegistry)
 		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: clusterfeatureworkflow.ClusterFeatureUpdateActivityName})
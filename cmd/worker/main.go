@@ -34,6 +34,8 @@ import (
 	"github.com/banzaicloud/pipeline/cluster"
 	conf "github.com/banzaicloud/pipeline/config"
 	"github.com/banzaicloud/pipeline/dns"
+	arkClusterManager "github.com/banzaicloud/pipeline/internal/ark/clustermanager"
+	arkMigration "github.com/banzaicloud/pipeline/internal/ark/migration"
 	intAuth "github.com/banzaicloud/pipeline/internal/auth"
 	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
 	intClusterAuth "github.com/banzaicloud/pipeline/internal/cluster/auth"
@@ -45,6 +47,9 @@ import (
 	"github.com/banzaicloud/pipeline/internal/clusterfeature"
 	"github.com/banzaicloud/pipeline/internal/clusterfeature/clusterfeatureadapter"
 	featureDns "github.com/banzaicloud/pipeline/internal/clusterfeature/features/dns"
+	featureLogging "github.com/banzaicloud/pipeline/internal/clusterfeature/features/logging"
+	featureMonitoring "github.com/banzaicloud/pipeline/internal/clusterfeature/features/monitoring"
+	featureVault "github.com/banzaicloud/pipeline/internal/clusterfeature/features/vault"
 	"github.com/banzaicloud/pipeline/internal/common/commonadapter"
 	"github.com/banzaicloud/pipeline/internal/global"
 	"github.com/banzaicloud/pipeline/internal/helm"
@@ -58,6 +63,8 @@ import (
 	"github.com/banzaicloud/pipeline/internal/providers/pke/pkeworkflow"
 	"github.com/banzaicloud/pipeline/internal/providers/pke/pkeworkflow/pkeworkflowadapter"
 	intSecret "github.com/banzaicloud/pipeline/internal/secret"
+	secretrotation "github.com/banzaicloud/pipeline/internal/secret/rotation"
+	secretrotationadapter "github.com/banzaicloud/pipeline/internal/secret/rotation/rotationadapter"
 	"github.com/banzaicloud/pipeline/secret"
 	"github.com/banzaicloud/pipeline/spotguide"
 	"github.com/banzaicloud/pipeline/spotguide/scm"
@@ -237,6 +244,14 @@ func main() {
 		updateClusterStatusActivity := cluster.NewUpdateClusterStatusActivity(clusterManager)
 		activity.RegisterWithOptions(updateClusterStatusActivity.Execute, activity.RegisterOptions{Name: cluster.UpdateClusterStatusActivityName})
 
+		workflow.RegisterWithOptions(cluster.UpgradeClusterWorkflow, workflow.RegisterOptions{Name: cluster.UpgradeClusterWorkflowName})
+
+		upgradeControlPlaneActivity := cluster.NewUpgradeControlPlaneActivity(clusterManager)
+		activity.RegisterWithOptions(upgradeControlPlaneActivity.Execute, activity.RegisterOptions{Name: cluster.UpgradeControlPlaneActivityName})
+
+		upgradeNodePoolActivity := cluster.NewUpgradeNodePoolActivity(clusterManager)
+		activity.RegisterWithOptions(upgradeNodePoolActivity.Execute, activity.RegisterOptions{Name: cluster.UpgradeNodePoolActivityName})
+
 		deleteUnusedClusterSecretsActivity := intClusterWorkflow.MakeDeleteUnusedClusterSecretsActivity(secret.Store)
 		activity.RegisterWithOptions(deleteUnusedClusterSecretsActivity.Execute, activity.RegisterOptions{Name: intClusterWorkflow.DeleteUnusedClusterSecretsActivityName})
 
@@ -265,6 +280,52 @@ func main() {
 		waitPersistentVolumesDeletionActivity := intClusterWorkflow.MakeWaitPersistentVolumesDeletionActivity(k8sConfigGetter, conf.Logger())
 		activity.RegisterWithOptions(waitPersistentVolumesDeletionActivity.Execute, activity.RegisterOptions{Name: intClusterWorkflow.WaitPersistentVolumesDeletionActivityName})
 
+		secretRotationManager := secretrotation.NewManager(db, secret.RestrictedStore)
+
+		workflow.RegisterWithOptions(secretrotation.RotateSecretsWorkflow, workflow.RegisterOptions{Name: secretrotation.RotateSecretsWorkflowName})
+		workflow.RegisterWithOptions(secretrotation.RotateSecretWorkflow, workflow.RegisterOptions{Name: secretrotation.RotateSecretWorkflowName})
+
+		listDueSecretRotationsActivity := secretrotation.NewListDueRotationsActivity(secretRotationManager)
+		activity.RegisterWithOptions(listDueSecretRotationsActivity.Execute, activity.RegisterOptions{Name: secretrotation.ListDueRotationsActivityName})
+
+		rotateSecretActivity := secretrotation.NewRotateSecretActivity(secretRotationManager)
+		activity.RegisterWithOptions(rotateSecretActivity.Execute, activity.RegisterOptions{Name: secretrotation.RotateSecretActivityName})
+
+		installRotatedSecretActivity := secretrotation.NewInstallSecretActivity(secretrotationadapter.NewSecretInstaller(clusterManager))
+		activity.RegisterWithOptions(installRotatedSecretActivity.Execute, activity.RegisterOptions{Name: secretrotation.InstallSecretActivityName})
+
+		arkClusters := arkClusterManager.New(clusterManager)
+
+		workflow.RegisterWithOptions(arkMigration.MigrateClusterWorkflow, workflow.RegisterOptions{Name: arkMigration.MigrateClusterWorkflowName})
+
+		getMigrationClusterStatusActivity := arkMigration.NewGetClusterStatusActivity(arkClusters)
+		activity.RegisterWithOptions(getMigrationClusterStatusActivity.Execute, activity.RegisterOptions{Name: arkMigration.GetClusterStatusActivityName})
+
+		createMigrationBackupActivity := arkMigration.NewCreateBackupActivity(arkClusters, db, conf.Logger())
+		activity.RegisterWithOptions(createMigrationBackupActivity.Execute, activity.RegisterOptions{Name: arkMigration.CreateBackupActivityName})
+
+		getMigrationBackupPhaseActivity := arkMigration.NewGetBackupPhaseActivity(arkClusters, db, conf.Logger())
+		activity.RegisterWithOptions(getMigrationBackupPhaseActivity.Execute, activity.RegisterOptions{Name: arkMigration.GetBackupPhaseActivityName})
+
+		createMigrationRestoreActivity := arkMigration.NewCreateRestoreActivity(arkClusters, db, conf.Logger())
+		activity.RegisterWithOptions(createMigrationRestoreActivity.Execute, activity.RegisterOptions{Name: arkMigration.CreateRestoreActivityName})
+
+		getMigrationRestorePhaseActivity := arkMigration.NewGetRestorePhaseActivity(arkClusters, db, conf.Logger())
+		activity.RegisterWithOptions(getMigrationRestorePhaseActivity.Execute, activity.RegisterOptions{Name: arkMigration.GetRestorePhaseActivityName})
+
+		finishMigrationRestoreActivity := arkMigration.NewFinishRestoreActivity(arkClusters, db, conf.Logger())
+		activity.RegisterWithOptions(finishMigrationRestoreActivity.Execute, activity.RegisterOptions{Name: arkMigration.FinishRestoreActivityName})
+
+		stopMigrationExternalDNSActivity := arkMigration.NewStopExternalDNSActivity(
+			arkClusters,
+			viper.GetString(conf.PipelineSystemNamespace),
+			viper.GetString(conf.DNSExternalDnsReleaseName),
+		)
+		activity.RegisterWithOptions(stopMigrationExternalDNSActivity.Execute, activity.RegisterOptions{Name: arkMigration.StopExternalDNSActivityName})
+
+		updateMigrationStatusActivity := arkMigration.NewUpdateStatusActivity(db, conf.Logger())
+		activity.RegisterWithOptions(updateMigrationStatusActivity.Execute, activity.RegisterOptions{Name: arkMigration.UpdateStatusActivityName})
+
 		{
 			// External DNS service
 			dnsSvc, err := dns.GetExternalDnsServiceClient()
@@ -278,15 +339,29 @@ func main() {
 			}
 
 			logger := commonadapter.NewLogger(logger) // TODO: make this a context aware logger
-			featureRepository := clusterfeatureadapter.NewGormFeatureRepository(db, logger)
+			featureRepository := clusterfeatureadapter.NewGormFeatureRepository(db, clusterfeatureadapter.UserIDContextExtractorFunc(auth.GetCurrentUserID), logger)
 			helmService := helm.NewHelmService(helmadapter.NewClusterService(clusterManager), logger)
 			secretStore := commonadapter.NewSecretStore(secret.Store, commonadapter.OrgIDContextExtractorFunc(auth.GetCurrentOrganizationID))
 			clusterService := clusterfeatureadapter.NewClusterService(clusterManager)
 			orgDomainService := featureDns.NewOrgDomainService(clusterManager, dnsSvc, logger)
 			dnsFeatureManager := featureDns.NewDnsFeatureManager(featureRepository, secretStore, clusterService, clusterManager, helmService, orgDomainService, logger)
-			featureRegistry := clusterfeature.NewFeatureRegistry(map[string]clusterfeature.FeatureManager{
-				dnsFeatureManager.Name(): dnsFeatureManager,
-			})
+			secretInstaller := clusterfeatureadapter.NewKubernetesSecretInstaller(clusterManager)
+			loggingFeatureManager := featureLogging.NewLoggingFeatureManager(featureRepository, secretStore, clusterSecretStore, secretInstaller, clusterService, clusterManager, helmService, logger)
+			serviceProxy := clusterfeatureadapter.NewKubernetesServiceProxy(clusterManager)
+			monitoringFeatureManager := featureMonitoring.NewMonitoringFeatureManager(featureRepository, secretStore, secretInstaller, serviceProxy, clusterService, clusterManager, helmService, logger)
+			featureManagers := map[string]clusterfeature.FeatureManager{
+				dnsFeatureManager.Name():        dnsFeatureManager,
+				loggingFeatureManager.Name():    loggingFeatureManager,
+				monitoringFeatureManager.Name(): monitoringFeatureManager,
+			}
+
+			// Vault integration is only available when Pipeline stores secrets in Vault
+			if viper.GetString(conf.SecretStoreBackend) == secret.VaultBackend {
+				vaultFeatureManager := featureVault.NewVaultFeatureManager(featureRepository, secretStore, secret.VaultClient().Vault(), viper.GetString(conf.VaultExternalAddress), clusterService, clusterManager, helmService, logger)
+				featureManagers[vaultFeatureManager.Name()] = vaultFeatureManager
+			}
+
+			featureRegistry := clusterfeature.NewFeatureRegistry(featureManagers)
 
 			registerClusterFeatureWorkflows(featureRegistry, featureRepository)
 		}
@@ -149,6 +149,19 @@ const (
 	SpotguideSyncInterval                    = "spotguide.syncInterval"
 	SpotguideSharedLibraryGitHubOrganization = "spotguide.sharedLibraryGitHubOrganization"
 
+	// Secret store constants
+	SecretStoreBackend          = "secret.backend"
+	SecretStoreEncryptionKey    = "secret.encryptionKey"
+	SecretRotationCheckInterval = "secret.rotationCheckInterval"
+
+	// VaultExternalAddress is the Vault address workloads of managed clusters use (defaults to VAULT_ADDR)
+	VaultExternalAddress = "vault.externalAddress"
+
+	// Webhook constants
+	WebhookMaxAttempts   = "webhook.maxAttempts"
+	WebhookRetryInterval = "webhook.retryInterval"
+	WebhookTimeout       = "webhook.timeout"
+
 	// full endpoint url of CloudInfo for ex: https://alpha.dev.banzaicloud.com/cloudinfo/api/v1
 	CloudInfoEndPoint = "cloudinfo.endpointUrl"
 
@@ -318,6 +331,13 @@ func init() {
 	viper.SetDefault(SpotguideSyncInterval, 15*time.Minute)
 	viper.SetDefault(SpotguideSharedLibraryGitHubOrganization, "spotguides")
 
+	viper.SetDefault(SecretStoreBackend, "vault")
+	viper.SetDefault(SecretRotationCheckInterval, time.Hour)
+
+	viper.SetDefault(WebhookMaxAttempts, 5)
+	viper.SetDefault(WebhookRetryInterval, 10*time.Second)
+	viper.SetDefault(WebhookTimeout, 10*time.Second)
+
 	viper.SetDefault("issue.type", "github")
 	viper.SetDefault("issue.githubLabels", []string{"community"})
 	viper.SetDefault("issue.githubOwner", "banzaicloud")
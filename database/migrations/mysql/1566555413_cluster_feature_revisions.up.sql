@@ -0,0 +1,16 @@
+create table cluster_feature_revisions
+(
+    id           int unsigned auto_increment
+        primary key,
+    created_at   timestamp    null,
+    updated_at   timestamp    null,
+    cluster_id   int unsigned null,
+    feature_name varchar(255) null,
+    revision     int unsigned null,
+    action       varchar(255) null,
+    spec         text         null,
+    status       varchar(255) null,
+    created_by   int unsigned null
+);
+
+CREATE UNIQUE INDEX idx_cluster_feature_revision ON `cluster_feature_revisions`(cluster_id, feature_name, revision);
@@ -0,0 +1,32 @@
+create table webhook_subscriptions
+(
+    id              int unsigned auto_increment
+        primary key,
+    created_at      timestamp    null,
+    updated_at      timestamp    null,
+    organization_id int unsigned not null,
+    url             varchar(255) not null,
+    events          varchar(255) null,
+    secret_id       varchar(255) not null,
+    created_by      int unsigned null
+);
+
+CREATE INDEX idx_webhook_subscriptions_organization_id ON `webhook_subscriptions`(organization_id);
+
+create table webhook_deliveries
+(
+    id              int unsigned auto_increment
+        primary key,
+    created_at      timestamp    null,
+    subscription_id int unsigned not null,
+    event_id        varchar(36)  not null,
+    event_type      varchar(255) not null,
+    payload         text         null,
+    attempt         int          null,
+    status_code     int          null,
+    error           text         null,
+    duration        bigint       null,
+    succeeded       tinyint(1)   null
+);
+
+CREATE INDEX idx_webhook_deliveries_subscription_id ON `webhook_deliveries`(subscription_id);
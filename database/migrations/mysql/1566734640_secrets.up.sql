@@ -0,0 +1,13 @@
+create table secrets
+(
+    id              int unsigned auto_increment
+        primary key,
+    organization_id int unsigned not null,
+    secret_id       varchar(64)  not null,
+    version         int          not null,
+    data            text         not null,
+    created_at      timestamp    null,
+    updated_at      timestamp    null
+);
+
+CREATE UNIQUE INDEX idx_secrets_organization_secret ON `secrets`(organization_id, secret_id);
@@ -0,0 +1,12 @@
+create table secret_versions
+(
+    id              int unsigned auto_increment
+        primary key,
+    organization_id int unsigned not null,
+    secret_id       varchar(64)  not null,
+    version         int          not null,
+    data            text         not null,
+    created_at      timestamp    null
+);
+
+CREATE UNIQUE INDEX idx_secret_versions_organization_secret_version ON `secret_versions`(organization_id, secret_id, version);
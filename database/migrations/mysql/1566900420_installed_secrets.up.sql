@@ -0,0 +1,16 @@
+create table installed_secrets
+(
+    id              int unsigned auto_increment
+        primary key,
+    organization_id int unsigned not null,
+    secret_id       varchar(64)  not null,
+    cluster_id      int unsigned not null,
+    namespace       varchar(255) not null,
+    name            varchar(255) not null,
+    created_at      timestamp    null,
+    updated_at      timestamp    null
+);
+
+CREATE INDEX idx_installed_secrets_organization_id ON `installed_secrets`(organization_id);
+CREATE INDEX idx_installed_secrets_secret_id ON `installed_secrets`(secret_id);
+CREATE UNIQUE INDEX idx_installed_secrets_cluster_namespace_name ON `installed_secrets`(cluster_id, namespace, name);
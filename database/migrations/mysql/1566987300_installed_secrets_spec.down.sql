@@ -0,0 +1,2 @@
+ALTER TABLE `installed_secrets` DROP COLUMN `spec`;
+ALTER TABLE `installed_secrets` DROP COLUMN `merged`;
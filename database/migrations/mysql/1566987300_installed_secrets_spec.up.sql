@@ -0,0 +1,2 @@
+ALTER TABLE `installed_secrets` ADD COLUMN `spec` text;
+ALTER TABLE `installed_secrets` ADD COLUMN `merged` tinyint(1) NOT NULL DEFAULT 0;
@@ -0,0 +1,18 @@
+create table secret_rotation_policies
+(
+    id                 int unsigned auto_increment
+        primary key,
+    organization_id    int unsigned not null,
+    secret_id          varchar(64)  not null,
+    rotation_interval  bigint       null,
+    days_before_expiry int          null,
+    next_rotation_at   timestamp    not null,
+    last_rotated_at    timestamp    null,
+    last_error         text         null,
+    created_at         timestamp    null,
+    updated_at         timestamp    null,
+    created_by         int unsigned null
+);
+
+CREATE UNIQUE INDEX idx_secret_rotation_policies_organization_secret ON `secret_rotation_policies`(organization_id, secret_id);
+CREATE INDEX idx_secret_rotation_policies_next_rotation_at ON `secret_rotation_policies`(next_rotation_at);
@@ -0,0 +1,13 @@
+create table applied_resources
+(
+    id              int unsigned auto_increment
+        primary key,
+    organization_id int unsigned not null,
+    kind            varchar(32)  not null,
+    cluster         varchar(255) default '' not null,
+    name            varchar(255) not null,
+    created_at      timestamp    null,
+    updated_at      timestamp    null
+);
+
+CREATE UNIQUE INDEX idx_applied_resources_org_kind_cluster_name ON `applied_resources`(organization_id, kind, cluster, name);
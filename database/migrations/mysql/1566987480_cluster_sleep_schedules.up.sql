@@ -0,0 +1,20 @@
+create table cluster_sleep_schedules
+(
+    id                 int unsigned auto_increment
+        primary key,
+    cluster_id         int unsigned not null,
+    sleep_at           varchar(255) not null,
+    wake_at            varchar(255) not null,
+    timezone           varchar(255) not null,
+    asleep             tinyint(1)   default 0 not null,
+    node_pool_sizes    text         null,
+    override_state     varchar(255) null,
+    override_until     timestamp    null,
+    last_transition_at timestamp    null,
+    last_error         text         null,
+    created_at         timestamp    null,
+    updated_at         timestamp    null,
+    created_by         int unsigned null
+);
+
+CREATE UNIQUE INDEX idx_cluster_sleep_schedules_cluster_id ON `cluster_sleep_schedules`(cluster_id);
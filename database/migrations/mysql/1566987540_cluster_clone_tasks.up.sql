@@ -0,0 +1,16 @@
+create table cluster_clone_tasks
+(
+    id                int unsigned auto_increment
+        primary key,
+    source_cluster_id int unsigned not null,
+    target_cluster_id int unsigned not null,
+    copy_releases     tinyint(1)   default 0 not null,
+    copy_features     tinyint(1)   default 0 not null,
+    status            varchar(255) not null,
+    status_message    text         null,
+    created_at        timestamp    null,
+    updated_at        timestamp    null,
+    created_by        int unsigned null
+);
+
+CREATE UNIQUE INDEX idx_cluster_clone_tasks_target_cluster_id ON `cluster_clone_tasks`(target_cluster_id);
@@ -0,0 +1,21 @@
+create table ark_migrations
+(
+    id                int unsigned auto_increment
+        primary key,
+    source_cluster_id int unsigned not null,
+    target_cluster_id int unsigned not null,
+    backup_name       varchar(255) null,
+    restore_name      varchar(255) null,
+    options           json         null,
+    results           json         null,
+    organization_id   int unsigned not null,
+    status            varchar(255) null,
+    status_message    text         null,
+    created_at        timestamp    null,
+    updated_at        timestamp    null,
+    created_by        int unsigned null
+);
+
+CREATE INDEX idx_ark_migrations_source_cluster_id ON `ark_migrations`(source_cluster_id);
+CREATE INDEX idx_ark_migrations_target_cluster_id ON `ark_migrations`(target_cluster_id);
+CREATE INDEX idx_ark_migrations_organization_id ON `ark_migrations`(organization_id);
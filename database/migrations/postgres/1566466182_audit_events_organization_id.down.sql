@@ -0,0 +1,2 @@
+DROP INDEX idx_audit_events_organization_id;
+ALTER TABLE "audit_events" DROP COLUMN "organization_id";
@@ -0,0 +1,2 @@
+ALTER TABLE "audit_events" ADD COLUMN "organization_id" integer;
+CREATE INDEX idx_audit_events_organization_id ON "audit_events"("organization_id");
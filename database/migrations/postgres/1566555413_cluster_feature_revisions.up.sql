@@ -0,0 +1,17 @@
+create table cluster_feature_revisions
+(
+    id           serial not null
+        constraint cluster_feature_revisions_pkey
+            primary key,
+    created_at   timestamp with time zone,
+    updated_at   timestamp with time zone,
+    cluster_id   integer,
+    feature_name text,
+    revision     integer,
+    action       text,
+    spec         text,
+    status       text,
+    created_by   integer
+);
+
+CREATE UNIQUE INDEX idx_cluster_feature_revision ON "cluster_feature_revisions" (cluster_id, feature_name, revision);
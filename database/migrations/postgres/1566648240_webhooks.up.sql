@@ -0,0 +1,34 @@
+create table webhook_subscriptions
+(
+    id              serial  not null
+        constraint webhook_subscriptions_pkey
+            primary key,
+    created_at      timestamp with time zone,
+    updated_at      timestamp with time zone,
+    organization_id integer not null,
+    url             text    not null,
+    events          text,
+    secret_id       text    not null,
+    created_by      integer
+);
+
+CREATE INDEX idx_webhook_subscriptions_organization_id ON "webhook_subscriptions" (organization_id);
+
+create table webhook_deliveries
+(
+    id              serial     not null
+        constraint webhook_deliveries_pkey
+            primary key,
+    created_at      timestamp with time zone,
+    subscription_id integer    not null,
+    event_id        varchar(36) not null,
+    event_type      text       not null,
+    payload         text,
+    attempt         integer,
+    status_code     integer,
+    error           text,
+    duration        bigint,
+    succeeded       boolean
+);
+
+CREATE INDEX idx_webhook_deliveries_subscription_id ON "webhook_deliveries" (subscription_id);
@@ -0,0 +1,14 @@
+create table secrets
+(
+    id              serial      not null
+        constraint secrets_pkey
+            primary key,
+    organization_id integer     not null,
+    secret_id       varchar(64) not null,
+    version         integer     not null,
+    data            text        not null,
+    created_at      timestamp with time zone,
+    updated_at      timestamp with time zone
+);
+
+CREATE UNIQUE INDEX idx_secrets_organization_secret ON "secrets" (organization_id, secret_id);
@@ -0,0 +1,13 @@
+create table secret_versions
+(
+    id              serial      not null
+        constraint secret_versions_pkey
+            primary key,
+    organization_id integer     not null,
+    secret_id       varchar(64) not null,
+    version         integer     not null,
+    data            text        not null,
+    created_at      timestamp with time zone
+);
+
+CREATE UNIQUE INDEX idx_secret_versions_organization_secret_version ON "secret_versions" (organization_id, secret_id, version);
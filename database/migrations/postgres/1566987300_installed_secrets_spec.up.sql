@@ -0,0 +1,2 @@
+ALTER TABLE "installed_secrets" ADD COLUMN "spec" text;
+ALTER TABLE "installed_secrets" ADD COLUMN "merged" boolean NOT NULL DEFAULT false;
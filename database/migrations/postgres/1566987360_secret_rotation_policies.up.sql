@@ -0,0 +1,19 @@
+create table secret_rotation_policies
+(
+    id                 serial      not null
+        constraint secret_rotation_policies_pkey
+            primary key,
+    organization_id    integer     not null,
+    secret_id          varchar(64) not null,
+    rotation_interval  bigint,
+    days_before_expiry integer,
+    next_rotation_at   timestamp with time zone not null,
+    last_rotated_at    timestamp with time zone,
+    last_error         text,
+    created_at         timestamp with time zone,
+    updated_at         timestamp with time zone,
+    created_by         integer
+);
+
+CREATE UNIQUE INDEX idx_secret_rotation_policies_organization_secret ON "secret_rotation_policies" (organization_id, secret_id);
+CREATE INDEX idx_secret_rotation_policies_next_rotation_at ON "secret_rotation_policies" (next_rotation_at);
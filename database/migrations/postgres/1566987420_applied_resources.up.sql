@@ -0,0 +1,14 @@
+create table applied_resources
+(
+    id              serial       not null
+        constraint applied_resources_pkey
+            primary key,
+    organization_id integer      not null,
+    kind            varchar(32)  not null,
+    cluster         varchar(255) default '' not null,
+    name            varchar(255) not null,
+    created_at      timestamp with time zone,
+    updated_at      timestamp with time zone
+);
+
+CREATE UNIQUE INDEX idx_applied_resources_org_kind_cluster_name ON "applied_resources" (organization_id, kind, cluster, name);
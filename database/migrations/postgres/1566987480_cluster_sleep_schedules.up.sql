@@ -0,0 +1,21 @@
+create table cluster_sleep_schedules
+(
+    id                 serial       not null
+        constraint cluster_sleep_schedules_pkey
+            primary key,
+    cluster_id         integer      not null,
+    sleep_at           varchar(255) not null,
+    wake_at            varchar(255) not null,
+    timezone           varchar(255) not null,
+    asleep             boolean      default false not null,
+    node_pool_sizes    text,
+    override_state     varchar(255),
+    override_until     timestamp with time zone,
+    last_transition_at timestamp with time zone,
+    last_error         text,
+    created_at         timestamp with time zone,
+    updated_at         timestamp with time zone,
+    created_by         integer
+);
+
+CREATE UNIQUE INDEX idx_cluster_sleep_schedules_cluster_id ON "cluster_sleep_schedules" (cluster_id);
@@ -0,0 +1,17 @@
+create table cluster_clone_tasks
+(
+    id                serial       not null
+        constraint cluster_clone_tasks_pkey
+            primary key,
+    source_cluster_id integer      not null,
+    target_cluster_id integer      not null,
+    copy_releases     boolean      default false not null,
+    copy_features     boolean      default false not null,
+    status            varchar(255) not null,
+    status_message    text,
+    created_at        timestamp with time zone,
+    updated_at        timestamp with time zone,
+    created_by        integer
+);
+
+CREATE UNIQUE INDEX idx_cluster_clone_tasks_target_cluster_id ON "cluster_clone_tasks" (target_cluster_id);
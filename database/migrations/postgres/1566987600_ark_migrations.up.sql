@@ -0,0 +1,22 @@
+create table ark_migrations
+(
+    id                serial       not null
+        constraint ark_migrations_pkey
+            primary key,
+    source_cluster_id integer      not null,
+    target_cluster_id integer      not null,
+    backup_name       text,
+    restore_name      text,
+    options           json,
+    results           json,
+    organization_id   integer      not null,
+    status            text,
+    status_message    text,
+    created_at        timestamp with time zone,
+    updated_at        timestamp with time zone,
+    created_by        integer
+);
+
+CREATE INDEX idx_ark_migrations_source_cluster_id ON "ark_migrations" (source_cluster_id);
+CREATE INDEX idx_ark_migrations_target_cluster_id ON "ark_migrations" (target_cluster_id);
+CREATE INDEX idx_ark_migrations_organization_id ON "ark_migrations" (organization_id);
@@ -78,7 +78,7 @@ func newExternalDnsServiceClientInstance() {
 	// vault kv put secret/banzaicloud/aws AWS_REGION=... AWS_ACCESS_KEY_ID=... AWS_SECRET_ACCESS_KEY=...
 	awsCredentialsPath := viper.GetString(config.AwsCredentialPath)
 
-	secret, err := secret.Store.Logical.Read(awsCredentialsPath)
+	secret, err := secret.VaultClient().Vault().Logical().Read(awsCredentialsPath)
 	if err != nil {
 		log.Errorf("Failed to read AWS credentials from Vault: %s", err.Error())
 		errCreate = err
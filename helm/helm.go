@@ -28,6 +28,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 	"text/template"
 	"time"
@@ -523,11 +524,150 @@ func DeleteDeployment(releaseName string, kubeConfig []byte) error {
 	}
 	_, err = hClient.DeleteRelease(releaseName, opts...)
 	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return &DeploymentNotFoundError{HelmError: err}
+		}
 		return err
 	}
 	return nil
 }
 
+// CopyDeployments installs the deployed releases of a cluster with the same charts and values on another cluster.
+// Releases which already exist on the target cluster or live in one of the skipped namespaces are left out.
+// The names of the installed releases are returned.
+func CopyDeployments(sourceKubeConfig []byte, targetKubeConfig []byte, skipNamespaces ...string) ([]string, error) {
+	sourceReleases, err := ListDeployments(nil, "", sourceKubeConfig)
+	if err != nil {
+		return nil, emperror.Wrap(err, "failed to list releases of the source cluster")
+	}
+
+	targetReleases, err := ListDeployments(nil, "", targetKubeConfig)
+	if err != nil {
+		return nil, emperror.Wrap(err, "failed to list releases of the target cluster")
+	}
+
+	existing := make(map[string]bool, len(targetReleases.GetReleases()))
+	for _, r := range targetReleases.GetReleases() {
+		existing[r.GetName()] = true
+	}
+
+	skipped := make(map[string]bool, len(skipNamespaces))
+	for _, namespace := range skipNamespaces {
+		skipped[namespace] = true
+	}
+
+	hClient, err := pkgHelm.NewClient(targetKubeConfig, log)
+	if err != nil {
+		return nil, err
+	}
+	defer hClient.Close()
+
+	var copied []string
+	for _, r := range sourceReleases.GetReleases() {
+		if r.GetInfo().GetStatus().GetCode() != release.Status_DEPLOYED || existing[r.GetName()] || skipped[r.GetNamespace()] {
+			continue
+		}
+
+		installOptions := append(
+			DefaultInstallOptions,
+			helm.ReleaseName(r.GetName()),
+			helm.ValueOverrides([]byte(r.GetConfig().GetRaw())),
+		)
+
+		_, err := hClient.InstallReleaseFromChart(r.GetChart(), r.GetNamespace(), installOptions...)
+		if err != nil {
+			return copied, emperror.WrapWith(err, "failed to install release", "release", r.GetName())
+		}
+
+		copied = append(copied, r.GetName())
+	}
+
+	return copied, nil
+}
+
+// GetDeploymentHistory returns the revisions of a Helm deployment (latest first)
+// with the user supplied values changed compared to the previous revision
+func GetDeploymentHistory(releaseName string, kubeConfig []byte, max int32) (*pkgHelm.GetDeploymentHistoryResponse, error) {
+	hClient, err := pkgHelm.NewClient(kubeConfig, log)
+	if err != nil {
+		return nil, err
+	}
+	defer hClient.Close()
+
+	historyRes, err := hClient.ReleaseHistory(releaseName, helm.WithMaxHistory(max))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &DeploymentNotFoundError{HelmError: err}
+		}
+		return nil, errors.Wrap(err, "failed to get deployment history")
+	}
+
+	releases := historyRes.GetReleases()
+	sort.Slice(releases, func(i, j int) bool {
+		return releases[i].GetVersion() < releases[j].GetVersion()
+	})
+
+	revisions := make([]pkgHelm.DeploymentRevision, len(releases))
+
+	var previousValues map[string]interface{}
+	for i, release := range releases {
+		values, err := chartutil.ReadValues([]byte(release.GetConfig().GetRaw()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read values of revision %d", release.GetVersion())
+		}
+
+		chartName := release.GetChart().GetMetadata().GetName()
+		chartVersion := release.GetChart().GetMetadata().GetVersion()
+
+		// revisions are returned latest first
+		revisions[len(releases)-1-i] = pkgHelm.DeploymentRevision{
+			Version:      release.GetVersion(),
+			Status:       release.GetInfo().GetStatus().GetCode().String(),
+			Chart:        GetVersionedChartName(chartName, chartVersion),
+			ChartName:    chartName,
+			ChartVersion: chartVersion,
+			Description:  release.GetInfo().GetDescription(),
+			UpdatedAt:    time.Unix(release.GetInfo().GetLastDeployed().GetSeconds(), 0),
+			ValuesDiff:   pkgHelm.DiffValues(previousValues, values.AsMap()),
+		}
+
+		previousValues = values.AsMap()
+	}
+
+	return &pkgHelm.GetDeploymentHistoryResponse{
+		ReleaseName: releaseName,
+		Revisions:   revisions,
+	}, nil
+}
+
+// RollbackDeployment rolls back a Helm deployment to the given revision
+func RollbackDeployment(releaseName string, kubeConfig []byte, version int32, wait bool, timeout int64) (*rls.RollbackReleaseResponse, error) {
+	hClient, err := pkgHelm.NewClient(kubeConfig, log)
+	if err != nil {
+		return nil, err
+	}
+	defer hClient.Close()
+
+	opts := []helm.RollbackOption{
+		helm.RollbackVersion(version),
+		helm.RollbackWait(wait),
+		helm.RollbackDescription(fmt.Sprintf("Rollback to %d", version)),
+	}
+	if timeout > 0 {
+		opts = append(opts, helm.RollbackTimeout(timeout))
+	}
+
+	rollbackRes, err := hClient.RollbackRelease(releaseName, opts...)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &DeploymentNotFoundError{HelmError: err}
+		}
+		return nil, errors.Wrap(err, "rollback failed")
+	}
+
+	return rollbackRes, nil
+}
+
 // GetDeploymentK8sResources returns K8s resources of a helm deployment
 func GetDeploymentK8sResources(releaseName string, kubeConfig []byte, resourceTypes []string) ([]pkgHelm.DeploymentResource, error) {
 	hClient, err := pkgHelm.NewClient(kubeConfig, log)
@@ -0,0 +1,296 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apply
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"emperror.dev/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Action is the change needed to bring a resource to its desired state.
+type Action string
+
+// Actions of a plan
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionNoop   Action = "noop"
+	ActionDelete Action = "delete"
+)
+
+// Status is the outcome of a planned action.
+type Status string
+
+// Statuses of the plan items
+const (
+	// StatusPlanned is the status of every item of a dry run.
+	StatusPlanned Status = "planned"
+	StatusApplied Status = "applied"
+	// StatusPending means the resource could not be applied yet (eg. its cluster is still being created)
+	// and the manifest should be applied again later.
+	StatusPending Status = "pending"
+	StatusFailed  Status = "failed"
+)
+
+// Scope is the organization and the user a manifest is applied for.
+type Scope struct {
+	OrganizationID   uint
+	OrganizationName string
+	UserID           uint
+	UserLogin        string
+}
+
+// Reconciler compares and applies the resources of a kind.
+type Reconciler interface {
+	// Plan returns the action needed to bring the current state of the resource to the desired one.
+	Plan(ctx context.Context, scope Scope, resource Resource) (Action, error)
+
+	// Apply creates or updates the resource.
+	Apply(ctx context.Context, scope Scope, resource Resource, action Action) error
+
+	// Delete deletes the resource. Deleting a missing resource is not an error.
+	Delete(ctx context.Context, scope Scope, ref Reference) error
+}
+
+// ClusterNotReadyError is returned by reconcilers when the cluster of a resource is not running (yet).
+// Such resources are reported as pending instead of failed.
+type ClusterNotReadyError struct {
+	ClusterName string
+}
+
+// Error implements the error interface.
+func (e ClusterNotReadyError) Error() string {
+	return fmt.Sprintf("cluster %s is not running", e.ClusterName)
+}
+
+// Options controls how a manifest is applied.
+type Options struct {
+	// DryRun only computes the plan.
+	DryRun bool
+
+	// Prune deletes the resources created by applying earlier manifests which are missing from the manifest.
+	Prune bool
+}
+
+// Item is a planned change of a resource.
+type Item struct {
+	Reference
+
+	Action  Action `json:"action,omitempty"`
+	Status  Status `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// Result is the plan of a manifest and the outcome of its execution.
+type Result struct {
+	DryRun bool   `json:"dryRun"`
+	Items  []Item `json:"items"`
+}
+
+// Failed returns whether any of the items failed.
+func (r Result) Failed() bool {
+	for _, item := range r.Items {
+		if item.Status == StatusFailed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Applier reconciles the resources of an organization from manifests.
+type Applier struct {
+	store       Store
+	reconcilers map[string]Reconciler
+	logger      logrus.FieldLogger
+}
+
+// NewApplier returns a new Applier with a reconciler for each supported kind.
+func NewApplier(store Store, reconcilers map[string]Reconciler, logger logrus.FieldLogger) *Applier {
+	return &Applier{
+		store:       store,
+		reconcilers: reconcilers,
+		logger:      logger,
+	}
+}
+
+type plannedItem struct {
+	Item
+
+	resource Resource
+}
+
+// Apply computes the plan of the manifest and executes it unless it is a dry run.
+// Resources are created and updated in dependency order (secrets, clusters, deployments, features),
+// pruned resources are deleted in reverse order.
+// A failing item does not stop the execution of the others.
+func (a *Applier) Apply(ctx context.Context, scope Scope, resources []Resource, options Options) (*Result, error) {
+	logger := a.logger.WithFields(logrus.Fields{
+		"organization": scope.OrganizationID,
+		"dryRun":       options.DryRun,
+	})
+
+	for _, resource := range resources {
+		if _, ok := a.reconcilers[resource.Kind]; !ok {
+			return nil, errors.Errorf("unsupported kind: %s", resource.Kind)
+		}
+	}
+
+	var plan []plannedItem
+
+	wanted := make(map[Reference]bool, len(resources))
+	for _, resource := range sortResources(resources) {
+		wanted[resource.Reference] = true
+
+		item := plannedItem{
+			Item:     Item{Reference: resource.Reference},
+			resource: resource,
+		}
+
+		action, err := a.reconcilers[resource.Kind].Plan(ctx, scope, resource)
+		if errors.As(err, &ClusterNotReadyError{}) {
+			item.Status, item.Message = StatusPending, err.Error()
+		} else if err != nil {
+			logger.WithField("resource", resource.Reference.String()).Warnf("failed to plan resource: %s", err)
+
+			item.Status, item.Message = StatusFailed, err.Error()
+		}
+		item.Action = action
+
+		plan = append(plan, item)
+	}
+
+	if options.Prune {
+		managed, err := a.store.List(scope.OrganizationID)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to list applied resources")
+		}
+
+		var pruned []Reference
+		for _, ref := range managed {
+			if !wanted[ref] {
+				pruned = append(pruned, ref)
+			}
+		}
+
+		sortReferences(pruned)
+		for i := len(pruned) - 1; i >= 0; i-- {
+			plan = append(plan, plannedItem{
+				Item: Item{Reference: pruned[i], Action: ActionDelete},
+			})
+		}
+	}
+
+	result := &Result{
+		DryRun: options.DryRun,
+		Items:  make([]Item, 0, len(plan)),
+	}
+
+	for _, item := range plan {
+		if item.Status == "" {
+			if options.DryRun {
+				item.Status = StatusPlanned
+			} else {
+				item.Status, item.Message = a.execute(ctx, scope, item)
+			}
+		}
+
+		result.Items = append(result.Items, item.Item)
+	}
+
+	return result, nil
+}
+
+func (a *Applier) execute(ctx context.Context, scope Scope, item plannedItem) (Status, string) {
+	logger := a.logger.WithFields(logrus.Fields{
+		"organization": scope.OrganizationID,
+		"resource":     item.Reference.String(),
+		"action":       item.Action,
+	})
+
+	reconciler := a.reconcilers[item.Kind]
+
+	var err error
+	switch item.Action {
+	case ActionDelete:
+		if reconciler == nil {
+			err = errors.Errorf("unsupported kind: %s", item.Kind)
+		} else if err = reconciler.Delete(ctx, scope, item.Reference); err == nil {
+			err = a.store.Delete(scope.OrganizationID, item.Reference)
+		}
+
+	case ActionNoop:
+
+	default:
+		// only the resources created by applying manifests are pruned later
+		if err = reconciler.Apply(ctx, scope, item.resource, item.Action); err == nil && item.Action == ActionCreate {
+			err = a.store.Save(scope.OrganizationID, item.Reference)
+		}
+	}
+
+	if errors.As(err, &ClusterNotReadyError{}) {
+		logger.Info(err.Error())
+
+		return StatusPending, err.Error()
+	} else if err != nil {
+		logger.Errorf("failed to apply resource: %s", err)
+
+		return StatusFailed, err.Error()
+	}
+
+	logger.Info("resource applied")
+
+	return StatusApplied, ""
+}
+
+func kindIndex(kind string) int {
+	for i, k := range kindOrder {
+		if k == kind {
+			return i
+		}
+	}
+
+	return len(kindOrder)
+}
+
+// sortResources orders the resources by kind, keeping the order of the manifest within a kind.
+func sortResources(resources []Resource) []Resource {
+	sorted := make([]Resource, len(resources))
+	copy(sorted, resources)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return kindIndex(sorted[i].Kind) < kindIndex(sorted[j].Kind)
+	})
+
+	return sorted
+}
+
+func sortReferences(refs []Reference) {
+	sort.SliceStable(refs, func(i, j int) bool {
+		if ki, kj := kindIndex(refs[i].Kind), kindIndex(refs[j].Kind); ki != kj {
+			return ki < kj
+		}
+
+		if refs[i].Cluster != refs[j].Cluster {
+			return refs[i].Cluster < refs[j].Cluster
+		}
+
+		return refs[i].Name < refs[j].Name
+	})
+}
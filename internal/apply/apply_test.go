@@ -0,0 +1,182 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apply_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/apply"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+const manifest = `
+kind: Deployment
+cluster: dev
+spec:
+  releaseName: ingress
+  chart: stable/nginx-ingress
+  values:
+    controller:
+      replicaCount: 2
+---
+kind: Cluster
+spec:
+  name: dev
+  location: eu-west-1
+  cloud: amazon
+  secretName: aws
+---
+kind: Secret
+spec:
+  name: aws
+  type: amazon
+  values:
+    AWS_ACCESS_KEY_ID: id
+    AWS_SECRET_ACCESS_KEY: key
+---
+`
+
+func TestParseManifest(t *testing.T) {
+	resources, err := apply.ParseManifest(strings.NewReader(manifest))
+	require.NoError(t, err)
+	require.Len(t, resources, 3)
+
+	assert.Equal(t, apply.Reference{Kind: apply.KindDeployment, Cluster: "dev", Name: "ingress"}, resources[0].Reference)
+	assert.Equal(t, map[string]interface{}{"controller": map[string]interface{}{"replicaCount": float64(2)}}, resources[0].Spec.(*apply.DeploymentSpec).Values)
+
+	assert.Equal(t, apply.Reference{Kind: apply.KindCluster, Name: "dev"}, resources[1].Reference)
+	assert.Equal(t, "aws", resources[1].Spec.(*pkgCluster.CreateClusterRequest).SecretName)
+
+	assert.Equal(t, apply.Reference{Kind: apply.KindSecret, Name: "aws"}, resources[2].Reference)
+
+	invalid := map[string]string{
+		"unknown kind":       "kind: Bucket\nspec:\n  name: a",
+		"missing spec":       "kind: Secret",
+		"missing cluster":    "kind: ClusterFeature\nspec:\n  name: dns",
+		"unexpected cluster": "kind: Secret\ncluster: dev\nspec:\n  name: a\n  type: password",
+		"duplicate":          "kind: Cluster\nspec:\n  name: a\n---\nkind: Cluster\nspec:\n  name: a",
+	}
+
+	for name, doc := range invalid {
+		_, err := apply.ParseManifest(strings.NewReader(doc))
+		assert.True(t, errors.As(err, &apply.ManifestError{}), name)
+	}
+}
+
+type reconcilerStub struct {
+	actions map[string]apply.Action
+	applied []string
+	deleted []string
+	err     error
+}
+
+func (r *reconcilerStub) Plan(ctx context.Context, scope apply.Scope, resource apply.Resource) (apply.Action, error) {
+	if action, ok := r.actions[resource.Name]; ok {
+		return action, nil
+	}
+
+	return apply.ActionCreate, nil
+}
+
+func (r *reconcilerStub) Apply(ctx context.Context, scope apply.Scope, resource apply.Resource, action apply.Action) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	r.applied = append(r.applied, resource.Name)
+
+	return nil
+}
+
+func (r *reconcilerStub) Delete(ctx context.Context, scope apply.Scope, ref apply.Reference) error {
+	r.deleted = append(r.deleted, ref.Name)
+
+	return nil
+}
+
+func TestApplier(t *testing.T) {
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	db.DB().SetMaxOpenConns(1)
+
+	logger, _ := test.NewNullLogger()
+	require.NoError(t, apply.Migrate(db, logger))
+
+	store := apply.NewGormStore(db)
+	require.NoError(t, store.Save(1, apply.Reference{Kind: apply.KindSecret, Name: "old"}))
+	require.NoError(t, store.Save(1, apply.Reference{Kind: apply.KindDeployment, Cluster: "dev", Name: "old"}))
+	require.NoError(t, store.Save(2, apply.Reference{Kind: apply.KindSecret, Name: "other"}))
+
+	secrets := &reconcilerStub{actions: map[string]apply.Action{"aws": apply.ActionNoop}}
+	clusters := &reconcilerStub{}
+	deployments := &reconcilerStub{err: apply.ClusterNotReadyError{ClusterName: "dev"}}
+
+	applier := apply.NewApplier(store, map[string]apply.Reconciler{
+		apply.KindSecret:     secrets,
+		apply.KindCluster:    clusters,
+		apply.KindDeployment: deployments,
+	}, logger)
+
+	resources, err := apply.ParseManifest(strings.NewReader(manifest))
+	require.NoError(t, err)
+
+	scope := apply.Scope{OrganizationID: 1}
+
+	result, err := applier.Apply(context.Background(), scope, resources, apply.Options{DryRun: true, Prune: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, []apply.Item{
+		{Reference: apply.Reference{Kind: apply.KindSecret, Name: "aws"}, Action: apply.ActionNoop, Status: apply.StatusPlanned},
+		{Reference: apply.Reference{Kind: apply.KindCluster, Name: "dev"}, Action: apply.ActionCreate, Status: apply.StatusPlanned},
+		{Reference: apply.Reference{Kind: apply.KindDeployment, Cluster: "dev", Name: "ingress"}, Action: apply.ActionCreate, Status: apply.StatusPlanned},
+		{Reference: apply.Reference{Kind: apply.KindDeployment, Cluster: "dev", Name: "old"}, Action: apply.ActionDelete, Status: apply.StatusPlanned},
+		{Reference: apply.Reference{Kind: apply.KindSecret, Name: "old"}, Action: apply.ActionDelete, Status: apply.StatusPlanned},
+	}, result.Items)
+	assert.Empty(t, clusters.applied)
+
+	result, err = applier.Apply(context.Background(), scope, resources, apply.Options{Prune: true})
+	require.NoError(t, err)
+
+	var statuses []apply.Status
+	for _, item := range result.Items {
+		statuses = append(statuses, item.Status)
+	}
+
+	assert.Equal(t, []apply.Status{apply.StatusApplied, apply.StatusApplied, apply.StatusPending, apply.StatusApplied, apply.StatusApplied}, statuses)
+	assert.False(t, result.Failed())
+	assert.Equal(t, []string{"dev"}, clusters.applied)
+	assert.Equal(t, []string{"old"}, deployments.deleted)
+	assert.Equal(t, []string{"old"}, secrets.deleted)
+
+	managed, err := store.List(1)
+	require.NoError(t, err)
+	assert.Equal(t, []apply.Reference{
+		{Kind: apply.KindCluster, Name: "dev"},
+	}, managed)
+
+	_, err = applier.Apply(context.Background(), scope, []apply.Resource{{Reference: apply.Reference{Kind: apply.KindClusterFeature}}}, apply.Options{})
+	assert.Error(t, err)
+}
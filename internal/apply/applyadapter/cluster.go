@@ -0,0 +1,70 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applyadapter
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/cluster"
+	"github.com/banzaicloud/pipeline/internal/apply"
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// ClusterManager returns clusters.
+type ClusterManager interface {
+	GetClusterByName(ctx context.Context, organizationID uint, clusterName string) (cluster.CommonCluster, error)
+}
+
+// getCluster returns a cluster, or nil if it does not exist.
+func getCluster(ctx context.Context, clusters ClusterManager, organizationID uint, clusterName string) (cluster.CommonCluster, error) {
+	c, err := clusters.GetClusterByName(ctx, organizationID, clusterName)
+	if intCluster.IsClusterNotFoundError(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.WrapIf(err, "failed to get cluster")
+	}
+
+	return c, nil
+}
+
+// getRunningCluster returns a cluster, or apply.ClusterNotReadyError if it does not exist or it is not running.
+func getRunningCluster(ctx context.Context, clusters ClusterManager, organizationID uint, clusterName string) (cluster.CommonCluster, error) {
+	c, err := getCluster(ctx, clusters, organizationID, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, apply.ClusterNotReadyError{ClusterName: clusterName}
+	}
+
+	return c, checkRunning(c)
+}
+
+// checkRunning returns apply.ClusterNotReadyError if a cluster is not running.
+func checkRunning(c cluster.CommonCluster) error {
+	status, err := c.GetStatus()
+	if err != nil {
+		return errors.WrapIf(err, "failed to get cluster status")
+	}
+
+	if status.Status != pkgCluster.Running {
+		return apply.ClusterNotReadyError{ClusterName: c.GetName()}
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applyadapter
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/ghodss/yaml"
+	k8sHelm "k8s.io/helm/pkg/helm"
+
+	"github.com/banzaicloud/pipeline/helm"
+	"github.com/banzaicloud/pipeline/internal/apply"
+)
+
+// DeploymentReconciler reconciles helm deployments.
+type DeploymentReconciler struct {
+	clusters ClusterManager
+}
+
+// NewDeploymentReconciler returns a new DeploymentReconciler.
+func NewDeploymentReconciler(clusters ClusterManager) *DeploymentReconciler {
+	return &DeploymentReconciler{
+		clusters: clusters,
+	}
+}
+
+// Plan compares the chart, its version and the values listed in the manifest with the deployed release.
+// Deployments of clusters which do not exist yet are planned to be created.
+func (r *DeploymentReconciler) Plan(ctx context.Context, scope apply.Scope, resource apply.Resource) (apply.Action, error) {
+	spec := resource.Spec.(*apply.DeploymentSpec)
+
+	c, err := getCluster(ctx, r.clusters, scope.OrganizationID, resource.Cluster)
+	if err != nil {
+		return "", err
+	}
+	if c == nil {
+		return apply.ActionCreate, nil
+	}
+
+	if err := checkRunning(c); err != nil {
+		return "", err
+	}
+
+	kubeConfig, err := c.GetK8sConfig()
+	if err != nil {
+		return "", errors.WrapIf(err, "failed to get kubeconfig")
+	}
+
+	current, err := helm.GetDeployment(spec.ReleaseName, kubeConfig)
+	if errors.As(err, new(*helm.DeploymentNotFoundError)) {
+		return apply.ActionCreate, nil
+	} else if err != nil {
+		return "", errors.WrapIf(err, "failed to get deployment")
+	}
+
+	if spec.Namespace != "" && spec.Namespace != current.Namespace {
+		return "", errors.Errorf("namespace of release %s cannot be changed from %s to %s", spec.ReleaseName, current.Namespace, spec.Namespace)
+	}
+
+	chartName := spec.Chart[strings.LastIndex(spec.Chart, "/")+1:]
+	if chartName != current.ChartName || (spec.Version != "" && spec.Version != current.ChartVersion) {
+		return apply.ActionUpdate, nil
+	}
+
+	contains, err := containsValues(current.Values, spec.Values)
+	if err != nil {
+		return "", err
+	}
+	if !contains {
+		return apply.ActionUpdate, nil
+	}
+
+	return apply.ActionNoop, nil
+}
+
+// Apply installs or upgrades a helm deployment.
+func (r *DeploymentReconciler) Apply(ctx context.Context, scope apply.Scope, resource apply.Resource, action apply.Action) error {
+	spec := resource.Spec.(*apply.DeploymentSpec)
+
+	c, err := getRunningCluster(ctx, r.clusters, scope.OrganizationID, resource.Cluster)
+	if err != nil {
+		return err
+	}
+
+	kubeConfig, err := c.GetK8sConfig()
+	if err != nil {
+		return errors.WrapIf(err, "failed to get kubeconfig")
+	}
+
+	values, err := yaml.Marshal(spec.Values)
+	if err != nil {
+		return errors.WrapIf(err, "failed to marshal values")
+	}
+
+	env := helm.GenerateHelmRepoEnv(scope.OrganizationName)
+
+	if action == apply.ActionCreate {
+		_, err = helm.CreateDeployment(
+			spec.Chart,
+			spec.Version,
+			nil,
+			spec.Namespace,
+			spec.ReleaseName,
+			false,
+			nil,
+			kubeConfig,
+			env,
+			k8sHelm.ValueOverrides(values),
+		)
+
+		return errors.WrapIf(err, "failed to create deployment")
+	}
+
+	_, err = helm.UpgradeDeployment(spec.ReleaseName, spec.Chart, spec.Version, nil, values, false, kubeConfig, env)
+
+	return errors.WrapIf(err, "failed to upgrade deployment")
+}
+
+// Delete deletes a helm deployment. Deployments of deleted clusters are considered deleted.
+func (r *DeploymentReconciler) Delete(ctx context.Context, scope apply.Scope, ref apply.Reference) error {
+	c, err := getCluster(ctx, r.clusters, scope.OrganizationID, ref.Cluster)
+	if err != nil || c == nil {
+		return err
+	}
+
+	kubeConfig, err := c.GetK8sConfig()
+	if err != nil {
+		return errors.WrapIf(err, "failed to get kubeconfig")
+	}
+
+	err = helm.DeleteDeployment(ref.Name, kubeConfig)
+	if errors.As(err, new(*helm.DeploymentNotFoundError)) {
+		return nil
+	}
+
+	return errors.WrapIf(err, "failed to delete deployment")
+}
+
+// containsValues returns whether every value of desired is set to the same in current.
+func containsValues(current map[string]interface{}, desired map[string]interface{}) (bool, error) {
+	var normalized [2]map[string]interface{}
+
+	for i, values := range []map[string]interface{}{current, desired} {
+		data, err := json.Marshal(values)
+		if err != nil {
+			return false, errors.WrapIf(err, "failed to marshal values")
+		}
+
+		if err := json.Unmarshal(data, &normalized[i]); err != nil {
+			return false, errors.WrapIf(err, "failed to unmarshal values")
+		}
+	}
+
+	return containsNormalizedValues(normalized[0], normalized[1]), nil
+}
+
+func containsNormalizedValues(current map[string]interface{}, desired map[string]interface{}) bool {
+	for key, desiredValue := range desired {
+		currentValue, ok := current[key]
+		if !ok {
+			return false
+		}
+
+		desiredMap, desiredIsMap := desiredValue.(map[string]interface{})
+		currentMap, currentIsMap := currentValue.(map[string]interface{})
+
+		if desiredIsMap && currentIsMap {
+			if !containsNormalizedValues(currentMap, desiredMap) {
+				return false
+			}
+		} else if !reflect.DeepEqual(currentValue, desiredValue) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,132 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applyadapter
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/apply"
+	"github.com/banzaicloud/pipeline/internal/clusterfeature"
+)
+
+// FeatureService manages cluster features.
+type FeatureService interface {
+	Details(ctx context.Context, clusterID uint, featureName string) (*clusterfeature.Feature, error)
+	Activate(ctx context.Context, clusterID uint, featureName string, spec clusterfeature.FeatureSpec) error
+	Update(ctx context.Context, clusterID uint, featureName string, spec clusterfeature.FeatureSpec) error
+	Deactivate(ctx context.Context, clusterID uint, featureName string) error
+}
+
+// FeatureReconciler reconciles cluster features.
+type FeatureReconciler struct {
+	clusters ClusterManager
+	features FeatureService
+}
+
+// NewFeatureReconciler returns a new FeatureReconciler.
+func NewFeatureReconciler(clusters ClusterManager, features FeatureService) *FeatureReconciler {
+	return &FeatureReconciler{
+		clusters: clusters,
+		features: features,
+	}
+}
+
+// Plan compares the spec of the feature with the active one.
+// Features of clusters which do not exist yet are planned to be activated.
+func (r *FeatureReconciler) Plan(ctx context.Context, scope apply.Scope, resource apply.Resource) (apply.Action, error) {
+	spec := resource.Spec.(*apply.ClusterFeatureSpec)
+
+	c, err := getCluster(ctx, r.clusters, scope.OrganizationID, resource.Cluster)
+	if err != nil {
+		return "", err
+	}
+	if c == nil {
+		return apply.ActionCreate, nil
+	}
+
+	if err := checkRunning(c); err != nil {
+		return "", err
+	}
+
+	current, err := r.features.Details(ctx, c.GetID(), spec.Name)
+	if errors.As(err, &clusterfeature.FeatureNotFoundError{}) {
+		return apply.ActionCreate, nil
+	} else if err != nil {
+		return "", errors.WrapIf(err, "failed to get feature details")
+	}
+
+	equal, err := equalSpecs(current.Spec, spec.Spec)
+	if err != nil {
+		return "", err
+	}
+	if !equal {
+		return apply.ActionUpdate, nil
+	}
+
+	return apply.ActionNoop, nil
+}
+
+// Apply activates or updates a cluster feature.
+func (r *FeatureReconciler) Apply(ctx context.Context, scope apply.Scope, resource apply.Resource, action apply.Action) error {
+	spec := resource.Spec.(*apply.ClusterFeatureSpec)
+
+	c, err := getRunningCluster(ctx, r.clusters, scope.OrganizationID, resource.Cluster)
+	if err != nil {
+		return err
+	}
+
+	if action == apply.ActionCreate {
+		return errors.WrapIf(r.features.Activate(ctx, c.GetID(), spec.Name, spec.Spec), "failed to activate feature")
+	}
+
+	return errors.WrapIf(r.features.Update(ctx, c.GetID(), spec.Name, spec.Spec), "failed to update feature")
+}
+
+// Delete deactivates a cluster feature. Features of deleted clusters are considered deactivated.
+func (r *FeatureReconciler) Delete(ctx context.Context, scope apply.Scope, ref apply.Reference) error {
+	c, err := getCluster(ctx, r.clusters, scope.OrganizationID, ref.Cluster)
+	if err != nil || c == nil {
+		return err
+	}
+
+	err = r.features.Deactivate(ctx, c.GetID(), ref.Name)
+	if errors.As(err, &clusterfeature.FeatureNotFoundError{}) {
+		return nil
+	}
+
+	return errors.WrapIf(err, "failed to deactivate feature")
+}
+
+// equalSpecs compares two feature specs by their JSON representation.
+func equalSpecs(a clusterfeature.FeatureSpec, b clusterfeature.FeatureSpec) (bool, error) {
+	var normalized [2]interface{}
+
+	for i, spec := range []clusterfeature.FeatureSpec{a, b} {
+		data, err := json.Marshal(spec)
+		if err != nil {
+			return false, errors.WrapIf(err, "failed to marshal feature spec")
+		}
+
+		if err := json.Unmarshal(data, &normalized[i]); err != nil {
+			return false, errors.WrapIf(err, "failed to unmarshal feature spec")
+		}
+	}
+
+	return reflect.DeepEqual(normalized[0], normalized[1]), nil
+}
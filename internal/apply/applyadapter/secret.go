@@ -0,0 +1,240 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applyadapter
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/apply"
+	intSecret "github.com/banzaicloud/pipeline/internal/secret"
+	pkgSecret "github.com/banzaicloud/pipeline/pkg/secret"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+// SecretStore stores secrets.
+type SecretStore interface {
+	Get(organizationID uint, secretID string) (*secret.SecretItemResponse, error)
+	Store(organizationID uint, request *secret.CreateSecretRequest) (string, error)
+	Update(organizationID uint, secretID string, request *secret.CreateSecretRequest) error
+	Delete(organizationID uint, secretID string) error
+}
+
+// SecretUsageFinder finds the resources referencing a secret.
+type SecretUsageFinder interface {
+	FindUsages(organizationID uint, secretID string) ([]intSecret.Usage, error)
+}
+
+// SecretReconciler reconciles secrets.
+type SecretReconciler struct {
+	secrets SecretStore
+	usages  SecretUsageFinder
+}
+
+// NewSecretReconciler returns a new SecretReconciler.
+func NewSecretReconciler(secrets SecretStore, usages SecretUsageFinder) *SecretReconciler {
+	return &SecretReconciler{
+		secrets: secrets,
+		usages:  usages,
+	}
+}
+
+// Plan compares the type, the tags and the values listed in the manifest with the stored secret.
+// Values missing from the manifest (eg. generated ones) are ignored,
+// values generated from the manifest (eg. a password from "randAlphaNum,12") are compared to their generator
+// and cannot be changed, as secret updates do not generate values.
+func (r *SecretReconciler) Plan(ctx context.Context, scope apply.Scope, resource apply.Resource) (apply.Action, error) {
+	spec := resource.Spec.(*apply.SecretSpec)
+
+	current, err := r.get(scope.OrganizationID, spec.Name)
+	if err != nil {
+		return "", err
+	}
+	if current == nil {
+		return apply.ActionCreate, nil
+	}
+
+	if current.Type != spec.Type {
+		return "", errors.Errorf("type of secret %s cannot be changed from %s to %s", spec.Name, current.Type, spec.Type)
+	}
+
+	action := apply.ActionNoop
+	if !equalTags(current.Tags, spec.Tags) {
+		action = apply.ActionUpdate
+	}
+
+	for key, value := range spec.Values {
+		if currentValue, ok := current.Values[key]; ok && (currentValue == value || generatedFrom(spec.Type, key, value, currentValue)) {
+			continue
+		}
+
+		if generates(spec, key) {
+			return "", errors.Errorf("value %s of secret %s is generated, it cannot be changed", key, spec.Name)
+		}
+
+		action = apply.ActionUpdate
+	}
+
+	return action, nil
+}
+
+// Apply creates or updates a secret.
+func (r *SecretReconciler) Apply(ctx context.Context, scope apply.Scope, resource apply.Resource, action apply.Action) error {
+	spec := resource.Spec.(*apply.SecretSpec)
+
+	request := &secret.CreateSecretRequest{
+		Name:      spec.Name,
+		Type:      spec.Type,
+		Tags:      spec.Tags,
+		Values:    make(map[string]string, len(spec.Values)),
+		UpdatedBy: scope.UserLogin,
+	}
+
+	for key, value := range spec.Values {
+		request.Values[key] = value
+	}
+
+	if action == apply.ActionCreate {
+		if err := request.ValidateAsNew(nil); err != nil {
+			return err
+		}
+
+		_, err := r.secrets.Store(scope.OrganizationID, request)
+
+		return errors.WrapIf(err, "failed to create secret")
+	}
+
+	current, err := r.get(scope.OrganizationID, spec.Name)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return errors.Errorf("secret %s does not exist", spec.Name)
+	}
+
+	for key, value := range request.Values {
+		if currentValue, ok := current.Values[key]; ok && generatedFrom(spec.Type, key, value, currentValue) {
+			request.Values[key] = currentValue
+		}
+	}
+
+	// keep the values not listed in the manifest
+	for key, value := range current.Values {
+		if _, ok := request.Values[key]; !ok {
+			request.Values[key] = value
+		}
+	}
+
+	if err := request.Validate(nil); err != nil {
+		return err
+	}
+
+	version := current.Version
+	request.Version = &version
+
+	err = r.secrets.Update(scope.OrganizationID, current.ID, request)
+
+	return errors.WrapIf(err, "failed to update secret")
+}
+
+// Delete deletes a secret unless it is still referenced by other resources.
+func (r *SecretReconciler) Delete(ctx context.Context, scope apply.Scope, ref apply.Reference) error {
+	secretID := secret.GenerateSecretIDFromName(ref.Name)
+
+	usages, err := r.usages.FindUsages(scope.OrganizationID, secretID)
+	if err != nil {
+		return errors.WrapIf(err, "failed to find secret usages")
+	}
+	if len(usages) > 0 {
+		return errors.Errorf("secret %s is still used by %s %s", ref.Name, usages[0].Type, usages[0].Name)
+	}
+
+	err = r.secrets.Delete(scope.OrganizationID, secretID)
+	if errors.Cause(err) == secret.ErrSecretNotExists {
+		return nil
+	}
+
+	return errors.WrapIf(err, "failed to delete secret")
+}
+
+func (r *SecretReconciler) get(organizationID uint, name string) (*secret.SecretItemResponse, error) {
+	current, err := r.secrets.Get(organizationID, secret.GenerateSecretIDFromName(name))
+	if err == secret.ErrSecretNotExists {
+		return nil, nil
+	}
+
+	return current, errors.WrapIf(err, "failed to get secret")
+}
+
+// generates tells whether a value of the manifest is used to generate the values of the secret.
+func generates(spec *apply.SecretSpec, key string) bool {
+	switch spec.Type {
+	case pkgSecret.PasswordSecretType:
+		_, ok := passwordLength(spec.Values[key])
+
+		return key == pkgSecret.Password && ok
+	case pkgSecret.TLSSecretType:
+		// a TLS secret is generated unless the certificates are listed as well
+		return len(spec.Values) <= 2
+	default:
+		return false
+	}
+}
+
+// generatedFrom tells whether a stored value could have been generated from the value in the manifest.
+func generatedFrom(secretType string, key string, value string, currentValue string) bool {
+	if secretType != pkgSecret.PasswordSecretType || key != pkgSecret.Password {
+		return false
+	}
+
+	length, ok := passwordLength(value)
+
+	return ok && len(currentValue) == length
+}
+
+// passwordLength returns the length of the password generated from a "method,length" pair.
+func passwordLength(value string) (int, bool) {
+	methodAndLength := strings.Split(value, ",")
+	if len(methodAndLength) != 2 {
+		return 0, false
+	}
+
+	length, err := strconv.Atoi(methodAndLength[1])
+
+	return length, err == nil
+}
+
+func equalTags(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
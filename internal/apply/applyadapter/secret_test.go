@@ -0,0 +1,156 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applyadapter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/apply"
+	intSecret "github.com/banzaicloud/pipeline/internal/secret"
+	pkgSecret "github.com/banzaicloud/pipeline/pkg/secret"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+type usageFinderStub struct {
+	usages []intSecret.Usage
+}
+
+func (f *usageFinderStub) FindUsages(organizationID uint, secretID string) ([]intSecret.Usage, error) {
+	return f.usages, nil
+}
+
+func TestSecretReconciler(t *testing.T) {
+	store := secret.NewInMemorySecretStore()
+	usages := &usageFinderStub{}
+	reconciler := NewSecretReconciler(store, usages)
+
+	scope := apply.Scope{OrganizationID: 1, UserLogin: "admin"}
+	resource := apply.Resource{
+		Reference: apply.Reference{Kind: apply.KindSecret, Name: "db"},
+		Spec: &apply.SecretSpec{
+			Name:   "db",
+			Type:   pkgSecret.PasswordSecretType,
+			Tags:   []string{"b", "a"},
+			Values: map[string]string{pkgSecret.Username: "admin", pkgSecret.Password: "secret"},
+		},
+	}
+
+	action, err := reconciler.Plan(context.Background(), scope, resource)
+	require.NoError(t, err)
+	assert.Equal(t, apply.ActionCreate, action)
+
+	require.NoError(t, reconciler.Apply(context.Background(), scope, resource, action))
+
+	action, err = reconciler.Plan(context.Background(), scope, resource)
+	require.NoError(t, err)
+	assert.Equal(t, apply.ActionNoop, action)
+
+	resource.Spec = &apply.SecretSpec{
+		Name:   "db",
+		Type:   pkgSecret.PasswordSecretType,
+		Tags:   []string{"a", "b"},
+		Values: map[string]string{pkgSecret.Username: "root"},
+	}
+
+	action, err = reconciler.Plan(context.Background(), scope, resource)
+	require.NoError(t, err)
+	assert.Equal(t, apply.ActionUpdate, action)
+
+	require.NoError(t, reconciler.Apply(context.Background(), scope, resource, action))
+
+	current, err := store.Get(1, secret.GenerateSecretIDFromName("db"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{pkgSecret.Username: "root", pkgSecret.Password: "secret"}, current.Values)
+
+	resource.Spec.(*apply.SecretSpec).Type = pkgSecret.GenericSecret
+	_, err = reconciler.Plan(context.Background(), scope, resource)
+	assert.Error(t, err)
+
+	usages.usages = []intSecret.Usage{{Type: intSecret.UsageTypeCluster, Name: "dev"}}
+	assert.Error(t, reconciler.Delete(context.Background(), scope, resource.Reference))
+
+	_, err = store.Get(1, secret.GenerateSecretIDFromName("db"))
+	require.NoError(t, err)
+
+	usages.usages = nil
+	require.NoError(t, reconciler.Delete(context.Background(), scope, resource.Reference))
+	require.NoError(t, reconciler.Delete(context.Background(), scope, resource.Reference))
+}
+
+func TestContainsValues(t *testing.T) {
+	current := map[string]interface{}{
+		"replicaCount": int64(2),
+		"image":        map[string]interface{}{"repository": "nginx", "tag": "1.17"},
+	}
+
+	contains, err := containsValues(current, map[string]interface{}{"replicaCount": 2.0, "image": map[string]interface{}{"tag": "1.17"}})
+	require.NoError(t, err)
+	assert.True(t, contains)
+
+	contains, err = containsValues(current, map[string]interface{}{"image": map[string]interface{}{"tag": "1.16"}})
+	require.NoError(t, err)
+	assert.False(t, contains)
+
+	contains, err = containsValues(current, map[string]interface{}{"service": map[string]interface{}{}})
+	require.NoError(t, err)
+	assert.False(t, contains)
+}
+
+func TestSecretReconciler_GeneratedPassword(t *testing.T) {
+	store := secret.NewInMemorySecretStore()
+	reconciler := NewSecretReconciler(store, &usageFinderStub{})
+
+	scope := apply.Scope{OrganizationID: 1, UserLogin: "admin"}
+	spec := &apply.SecretSpec{
+		Name:   "db",
+		Type:   pkgSecret.PasswordSecretType,
+		Values: map[string]string{pkgSecret.Username: "admin", pkgSecret.Password: "randAlphaNum,12"},
+	}
+	resource := apply.Resource{
+		Reference: apply.Reference{Kind: apply.KindSecret, Name: "db"},
+		Spec:      spec,
+	}
+
+	require.NoError(t, reconciler.Apply(context.Background(), scope, resource, apply.ActionCreate))
+
+	generated, err := store.Get(1, secret.GenerateSecretIDFromName("db"))
+	require.NoError(t, err)
+	require.Len(t, generated.Values[pkgSecret.Password], 12)
+
+	action, err := reconciler.Plan(context.Background(), scope, resource)
+	require.NoError(t, err)
+	assert.Equal(t, apply.ActionNoop, action)
+
+	spec.Tags = []string{"db"}
+
+	action, err = reconciler.Plan(context.Background(), scope, resource)
+	require.NoError(t, err)
+	assert.Equal(t, apply.ActionUpdate, action)
+
+	require.NoError(t, reconciler.Apply(context.Background(), scope, resource, action))
+
+	current, err := store.Get(1, secret.GenerateSecretIDFromName("db"))
+	require.NoError(t, err)
+	assert.Equal(t, generated.Values, current.Values)
+
+	spec.Values[pkgSecret.Password] = "randAlphaNum,16"
+
+	_, err = reconciler.Plan(context.Background(), scope, resource)
+	assert.Error(t, err)
+}
@@ -0,0 +1,223 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apply
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"emperror.dev/errors"
+	k8sYaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// Kinds of the resources a manifest can contain
+const (
+	KindSecret         = "Secret"
+	KindCluster        = "Cluster"
+	KindDeployment     = "Deployment"
+	KindClusterFeature = "ClusterFeature"
+)
+
+// kindOrder is the order resources are created and updated in, deletion happens in reverse order.
+// nolint: gochecknoglobals
+var kindOrder = []string{KindSecret, KindCluster, KindDeployment, KindClusterFeature}
+
+// Reference identifies a resource of an organization.
+// Cluster is only set for resources living in a cluster (deployments and cluster features).
+type Reference struct {
+	Kind    string `json:"kind"`
+	Cluster string `json:"cluster,omitempty"`
+	Name    string `json:"name"`
+}
+
+func (r Reference) String() string {
+	if r.Cluster != "" {
+		return fmt.Sprintf("%s %s/%s", r.Kind, r.Cluster, r.Name)
+	}
+
+	return fmt.Sprintf("%s %s", r.Kind, r.Name)
+}
+
+// Resource is a desired resource parsed from a manifest.
+// Spec is one of *pkgCluster.CreateClusterRequest, *SecretSpec, *DeploymentSpec and *ClusterFeatureSpec
+// depending on the kind of the resource.
+type Resource struct {
+	Reference
+
+	Spec interface{}
+}
+
+// SecretSpec describes a secret.
+type SecretSpec struct {
+	Name   string            `json:"name"`
+	Type   string            `json:"type"`
+	Tags   []string          `json:"tags,omitempty"`
+	Values map[string]string `json:"values"`
+}
+
+// DeploymentSpec describes a helm deployment.
+type DeploymentSpec struct {
+	ReleaseName string                 `json:"releaseName"`
+	Chart       string                 `json:"chart"`
+	Version     string                 `json:"version,omitempty"`
+	Namespace   string                 `json:"namespace,omitempty"`
+	Values      map[string]interface{} `json:"values,omitempty"`
+}
+
+// ClusterFeatureSpec describes a cluster feature.
+type ClusterFeatureSpec struct {
+	Name string                 `json:"name"`
+	Spec map[string]interface{} `json:"spec"`
+}
+
+// document is a single document of a manifest.
+type document struct {
+	Kind    string          `json:"kind"`
+	Cluster string          `json:"cluster,omitempty"`
+	Spec    json.RawMessage `json:"spec"`
+}
+
+// ManifestError is returned when a manifest is invalid.
+type ManifestError struct {
+	Document int
+	Message  string
+}
+
+// Error implements the error interface.
+func (e ManifestError) Error() string {
+	return fmt.Sprintf("document %d: %s", e.Document, e.Message)
+}
+
+// ParseManifest parses a multi-document YAML (or JSON) manifest.
+//
+// Every document has a kind, a spec and, for deployments and cluster features, the name of the cluster:
+//
+//	kind: Deployment
+//	cluster: my-cluster
+//	spec:
+//	  releaseName: my-release
+//	  chart: stable/nginx-ingress
+func ParseManifest(r io.Reader) ([]Resource, error) {
+	decoder := k8sYaml.NewYAMLOrJSONDecoder(r, 4096)
+
+	var resources []Resource
+	seen := make(map[Reference]bool)
+
+	for i := 1; ; i++ {
+		var doc document
+		err := decoder.Decode(&doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, ManifestError{Document: i, Message: err.Error()}
+		}
+
+		// skip empty documents
+		if doc.Kind == "" && len(doc.Spec) == 0 {
+			continue
+		}
+
+		resource, err := parseDocument(doc)
+		if err != nil {
+			return nil, ManifestError{Document: i, Message: err.Error()}
+		}
+
+		if seen[resource.Reference] {
+			return nil, ManifestError{Document: i, Message: fmt.Sprintf("duplicate resource: %s", resource.Reference)}
+		}
+		seen[resource.Reference] = true
+
+		resources = append(resources, resource)
+	}
+
+	return resources, nil
+}
+
+func parseDocument(doc document) (Resource, error) {
+	resource := Resource{
+		Reference: Reference{Kind: doc.Kind, Cluster: doc.Cluster},
+	}
+
+	if len(doc.Spec) == 0 {
+		return resource, errors.New("spec is required")
+	}
+
+	switch doc.Kind {
+	case KindSecret:
+		var spec SecretSpec
+		if err := json.Unmarshal(doc.Spec, &spec); err != nil {
+			return resource, err
+		}
+		if spec.Name == "" || spec.Type == "" {
+			return resource, errors.New("secret name and type are required")
+		}
+
+		resource.Name, resource.Spec = spec.Name, &spec
+
+	case KindCluster:
+		var spec pkgCluster.CreateClusterRequest
+		if err := json.Unmarshal(doc.Spec, &spec); err != nil {
+			return resource, err
+		}
+		if spec.Name == "" {
+			return resource, errors.New("cluster name is required")
+		}
+
+		resource.Name, resource.Spec = spec.Name, &spec
+
+	case KindDeployment:
+		var spec DeploymentSpec
+		if err := json.Unmarshal(doc.Spec, &spec); err != nil {
+			return resource, err
+		}
+		if spec.ReleaseName == "" || spec.Chart == "" {
+			return resource, errors.New("release name and chart are required")
+		}
+
+		resource.Name, resource.Spec = spec.ReleaseName, &spec
+
+	case KindClusterFeature:
+		var spec ClusterFeatureSpec
+		if err := json.Unmarshal(doc.Spec, &spec); err != nil {
+			return resource, err
+		}
+		if spec.Name == "" {
+			return resource, errors.New("feature name is required")
+		}
+
+		resource.Name, resource.Spec = spec.Name, &spec
+
+	default:
+		return resource, errors.Errorf("unknown kind %q, expected one of %s", doc.Kind, strings.Join(kindOrder, ", "))
+	}
+
+	switch doc.Kind {
+	case KindDeployment, KindClusterFeature:
+		if doc.Cluster == "" {
+			return resource, errors.New("cluster is required")
+		}
+	default:
+		if doc.Cluster != "" {
+			return resource, errors.Errorf("cluster cannot be set for %s resources", doc.Kind)
+		}
+	}
+
+	return resource, nil
+}
@@ -0,0 +1,118 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apply
+
+import (
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+)
+
+// Store keeps track of the resources created by applying manifests.
+// Only these resources are deleted when pruning.
+type Store interface {
+	List(organizationID uint) ([]Reference, error)
+	Save(organizationID uint, ref Reference) error
+	Delete(organizationID uint, ref Reference) error
+}
+
+// appliedResourceModel is a resource managed by applying manifests.
+type appliedResourceModel struct {
+	ID             uint   `gorm:"primary_key"`
+	OrganizationID uint   `gorm:"unique_index:idx_applied_resources_org_kind_cluster_name;not null"`
+	Kind           string `gorm:"unique_index:idx_applied_resources_org_kind_cluster_name;size:32;not null"`
+	Cluster        string `gorm:"unique_index:idx_applied_resources_org_kind_cluster_name;not null;default:''"`
+	Name           string `gorm:"unique_index:idx_applied_resources_org_kind_cluster_name;not null"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TableName changes the default table name.
+func (appliedResourceModel) TableName() string {
+	return "applied_resources"
+}
+
+// Migrate executes the table migrations for the applied resources.
+func Migrate(db *gorm.DB, logger logrus.FieldLogger) error {
+	logger.WithFields(logrus.Fields{
+		"table_names": appliedResourceModel{}.TableName(),
+	}).Info("migrating applied resource tables")
+
+	return db.AutoMigrate(&appliedResourceModel{}).Error
+}
+
+// GormStore is a Store persisted in a database.
+type GormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore returns a new GormStore.
+func NewGormStore(db *gorm.DB) *GormStore {
+	return &GormStore{
+		db: db,
+	}
+}
+
+// List returns the resources managed in an organization.
+func (s *GormStore) List(organizationID uint) ([]Reference, error) {
+	var models []appliedResourceModel
+
+	err := s.db.Where(&appliedResourceModel{OrganizationID: organizationID}).Order("id").Find(&models).Error
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to list applied resources")
+	}
+
+	refs := make([]Reference, 0, len(models))
+	for _, model := range models {
+		refs = append(refs, Reference{Kind: model.Kind, Cluster: model.Cluster, Name: model.Name})
+	}
+
+	return refs, nil
+}
+
+// Save marks a resource as managed.
+func (s *GormStore) Save(organizationID uint, ref Reference) error {
+	model := appliedResourceModel{
+		OrganizationID: organizationID,
+		Kind:           ref.Kind,
+		Cluster:        ref.Cluster,
+		Name:           ref.Name,
+	}
+
+	err := s.db.Where(referenceConditions(organizationID, ref)).FirstOrCreate(&model).Error
+
+	return errors.WrapIf(err, "failed to save applied resource")
+}
+
+// Delete removes a resource from the managed ones.
+func (s *GormStore) Delete(organizationID uint, ref Reference) error {
+	err := s.db.Where(referenceConditions(organizationID, ref)).Delete(&appliedResourceModel{}).Error
+
+	return errors.WrapIf(err, "failed to delete applied resource")
+}
+
+// referenceConditions returns query conditions matching empty cluster names as well
+// (struct conditions would ignore them).
+func referenceConditions(organizationID uint, ref Reference) map[string]interface{} {
+	return map[string]interface{}{
+		"organization_id": organizationID,
+		"kind":            ref.Kind,
+		"cluster":         ref.Cluster,
+		"name":            ref.Name,
+	}
+}
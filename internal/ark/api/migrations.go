@@ -0,0 +1,97 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"time"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// Migration statuses
+const (
+	MigrationStatusPending     = "Pending"
+	MigrationStatusBackingUp   = "BackingUp"
+	MigrationStatusRestoring   = "Restoring"
+	MigrationStatusCuttingOver = "CuttingOver"
+	MigrationStatusCompleted   = "Completed"
+	MigrationStatusFailed      = "Failed"
+)
+
+// MigrationOptions defines what is migrated from the source cluster and how
+type MigrationOptions struct {
+	// Namespaces is a slice of namespace names to migrate.
+	// If empty, all namespaces are migrated (except kube-system).
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// NamespaceMapping is a map of source namespace names
+	// to target namespace names to restore into. Any source
+	// namespaces not included in the map will be restored into
+	// namespaces of the same name.
+	NamespaceMapping map[string]string `json:"namespaceMapping,omitempty"`
+
+	// StorageClassMapping is a map of source storage class names
+	// to target storage class names. Persistent volume claims of a mapped
+	// storage class are provisioned by the target storage class.
+	StorageClassMapping map[string]string `json:"storageClassMapping,omitempty"`
+
+	// CutoverDNS moves the DNS records of the source cluster to the target cluster
+	// once the restore is completed.
+	CutoverDNS bool `json:"cutoverDns,omitempty"`
+}
+
+// CreateMigrationRequest describes a create migration request
+type CreateMigrationRequest struct {
+	// TargetClusterID is the ID of an existing cluster to migrate to.
+	TargetClusterID uint `json:"targetClusterId,omitempty"`
+
+	// TargetCluster is a create request of a new cluster to migrate to (if TargetClusterID is not set).
+	TargetCluster *pkgCluster.CreateClusterRequest `json:"targetCluster,omitempty"`
+
+	// TTL of the backup taken of the source cluster.
+	TTL string `json:"ttl,omitempty"`
+
+	Options MigrationOptions `json:"options"`
+}
+
+// PersistMigrationRequest describes a persist migration request
+type PersistMigrationRequest struct {
+	SourceClusterID uint
+	TargetClusterID uint
+	BackupName      string
+	Options         MigrationOptions
+	CreatedBy       uint
+}
+
+// Migration describes a migration of workloads between clusters using an ARK backup
+type Migration struct {
+	ID              uint             `json:"id"`
+	SourceClusterID uint             `json:"sourceClusterId"`
+	TargetClusterID uint             `json:"targetClusterId"`
+	BackupName      string           `json:"backupName"`
+	RestoreName     string           `json:"restoreName,omitempty"`
+	Status          string           `json:"status"`
+	StatusMessage   string           `json:"statusMessage,omitempty"`
+	Options         MigrationOptions `json:"options"`
+	Results         *RestoreResults  `json:"results,omitempty"`
+	CreatedAt       time.Time        `json:"createdAt"`
+	UpdatedAt       time.Time        `json:"updatedAt"`
+}
+
+// CreateMigrationResponse describes a create migration response
+type CreateMigrationResponse struct {
+	Migration *Migration `json:"migration"`
+	Status    int        `json:"status"`
+}
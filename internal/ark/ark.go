@@ -17,6 +17,7 @@ package ark
 import (
 	"github.com/pkg/errors"
 
+	"github.com/banzaicloud/pipeline/internal/ark/providers/s3"
 	pkgErrors "github.com/banzaicloud/pipeline/pkg/errors"
 	"github.com/banzaicloud/pipeline/pkg/providers"
 	"github.com/banzaicloud/pipeline/secret"
@@ -32,6 +33,12 @@ func IsProviderSupported(provider string) error {
 		return nil
 	case providers.Azure:
 		return nil
+	case providers.Alibaba:
+		return nil
+	case providers.Oracle:
+		return nil
+	case s3.Provider:
+		return nil
 	default:
 		return pkgErrors.ErrorNotSupportedCloudType
 	}
@@ -41,6 +41,7 @@ func (c *Client) CreateRestore(req api.CreateRestoreRequest) (*arkAPI.Restore, e
 			IncludedResources:       req.Options.IncludedResources,
 			ExcludedNamespaces:      req.Options.ExcludedNamespaces,
 			ExcludedResources:       req.Options.ExcludedResources,
+			NamespaceMapping:        req.Options.NamespaceMapping,
 			IncludeClusterResources: req.Options.IncludeClusterResources,
 			LabelSelector:           req.Options.LabelSelector,
 			RestorePVs:              req.Options.RestorePVs,
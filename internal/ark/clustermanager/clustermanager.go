@@ -45,3 +45,8 @@ func (cm *ClusterManager) GetClusters(ctx context.Context, organizationID uint)
 
 	return apiClusters, nil
 }
+
+// GetClusterByID returns a cluster of an organization by its ID
+func (cm *ClusterManager) GetClusterByID(ctx context.Context, organizationID uint, clusterID uint) (api.Cluster, error) {
+	return cm.clusterManager.GetClusterByID(ctx, organizationID, clusterID)
+}
@@ -18,11 +18,15 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/banzaicloud/pipeline/config"
+	"github.com/banzaicloud/pipeline/internal/ark/providers/alibaba"
 	"github.com/banzaicloud/pipeline/internal/ark/providers/amazon"
 	"github.com/banzaicloud/pipeline/internal/ark/providers/azure"
 	"github.com/banzaicloud/pipeline/internal/ark/providers/google"
+	"github.com/banzaicloud/pipeline/internal/ark/providers/oracle"
+	"github.com/banzaicloud/pipeline/internal/ark/providers/s3"
 	pkgErrors "github.com/banzaicloud/pipeline/pkg/errors"
 	"github.com/banzaicloud/pipeline/pkg/providers"
+	pkgSecret "github.com/banzaicloud/pipeline/pkg/secret"
 	"github.com/banzaicloud/pipeline/secret"
 )
 
@@ -64,9 +68,9 @@ type secretContents struct {
 }
 
 type configuration struct {
-	PersistentVolumeProvider persistentVolumeProvider `json:"persistentVolumeProvider"`
-	BackupStorageProvider    backupStorageProvider    `json:"backupStorageProvider"`
-	RestoreOnlyMode          bool                     `json:"restoreOnlyMode"`
+	PersistentVolumeProvider *persistentVolumeProvider `json:"persistentVolumeProvider,omitempty"`
+	BackupStorageProvider    backupStorageProvider     `json:"backupStorageProvider"`
+	RestoreOnlyMode          bool                      `json:"restoreOnlyMode"`
 }
 
 type persistentVolumeProvider struct {
@@ -175,9 +179,10 @@ func (req ConfigRequest) Get() (values ValueOverrides, err error) {
 	}, nil
 }
 
-func (req ConfigRequest) getPVPConfig() (persistentVolumeProvider, error) {
+// getPVPConfig returns the persistent volume provider config of the cluster,
+// or nil if volume snapshots are not supported on the cloud of the cluster
+func (req ConfigRequest) getPVPConfig() (*persistentVolumeProvider, error) {
 
-	var config persistentVolumeProvider
 	var pvc string
 
 	switch req.Cluster.Provider {
@@ -187,11 +192,13 @@ func (req ConfigRequest) getPVPConfig() (persistentVolumeProvider, error) {
 		pvc = azure.PersistentVolumeProvider
 	case providers.Google:
 		pvc = google.PersistentVolumeProvider
+	case providers.Alibaba, providers.Oracle:
+		return nil, nil
 	default:
-		return config, pkgErrors.ErrorNotSupportedCloudType
+		return nil, pkgErrors.ErrorNotSupportedCloudType
 	}
 
-	return persistentVolumeProvider{
+	return &persistentVolumeProvider{
 		Name: pvc,
 		Config: persistentVolumeProviderConfig{
 			Region:     req.Cluster.Location,
@@ -212,6 +219,12 @@ func (req ConfigRequest) getBSPConfig() (backupStorageProvider, error) {
 		bsp = azure.BackupStorageProvider
 	case providers.Google:
 		bsp = google.BackupStorageProvider
+	case providers.Alibaba:
+		bsp = alibaba.BackupStorageProvider
+	case providers.Oracle:
+		bsp = oracle.BackupStorageProvider
+	case s3.Provider:
+		bsp = s3.BackupStorageProvider
 	default:
 		return config, pkgErrors.ErrorNotSupportedCloudType
 	}
@@ -227,6 +240,28 @@ func (req ConfigRequest) getBSPConfig() (backupStorageProvider, error) {
 		}
 	}
 
+	// the following object storages are accessed through their Amazon S3 compatible API
+	switch req.Bucket.Provider {
+	case providers.Alibaba:
+		config.Config.Region = alibaba.GetS3Region(req.Bucket.Location)
+		config.Config.S3Url = alibaba.GetS3URL(req.Bucket.Location)
+	case providers.Oracle:
+		s3URL, err := oracle.GetS3URL(req.BucketSecret, req.Bucket.Location)
+		if err != nil {
+			return config, err
+		}
+
+		config.Config.S3Url = s3URL
+		config.Config.S3ForcePathStyle = "true"
+	case s3.Provider:
+		if config.Config.Region == "" {
+			config.Config.Region = s3.DefaultRegion
+		}
+
+		config.Config.S3Url = req.BucketSecret.Values[pkgSecret.S3Endpoint]
+		config.Config.S3ForcePathStyle = "true"
+	}
+
 	return config, nil
 }
 
@@ -253,6 +288,8 @@ func (req ConfigRequest) getCredentials() (credentials, error) {
 		if err != nil {
 			return config, err
 		}
+	case providers.Alibaba, providers.Oracle:
+		// volume snapshots are not supported, no cluster credentials are needed
 	default:
 		return config, pkgErrors.ErrorNotSupportedCloudType
 	}
@@ -273,6 +310,21 @@ func (req ConfigRequest) getCredentials() (credentials, error) {
 		if err != nil {
 			return config, err
 		}
+	case providers.Alibaba:
+		BucketSecretContents, err = alibaba.GetSecret(req.BucketSecret)
+		if err != nil {
+			return config, err
+		}
+	case providers.Oracle:
+		BucketSecretContents, err = oracle.GetSecret(req.BucketSecret)
+		if err != nil {
+			return config, err
+		}
+	case s3.Provider:
+		BucketSecretContents, err = s3.GetSecret(req.BucketSecret)
+		if err != nil {
+			return config, err
+		}
 	default:
 		return config, pkgErrors.ErrorNotSupportedCloudType
 	}
@@ -0,0 +1,110 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ark
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/ark/providers/s3"
+	"github.com/banzaicloud/pipeline/pkg/providers"
+	pkgSecret "github.com/banzaicloud/pipeline/pkg/secret"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+func TestConfigRequest_Get_S3CompatibleBuckets(t *testing.T) {
+	clusterSecret := &secret.SecretItemResponse{
+		Type: providers.Alibaba,
+		Values: map[string]string{
+			pkgSecret.AlibabaAccessKeyId:     "cluster-key-id",
+			pkgSecret.AlibabaSecretAccessKey: "cluster-key",
+		},
+	}
+
+	testCases := map[string]struct {
+		bucket       bucketConfig
+		bucketSecret *secret.SecretItemResponse
+		expected     backupStorageProviderConfig
+	}{
+		"alibaba": {
+			bucket: bucketConfig{
+				Name:     "backups",
+				Provider: providers.Alibaba,
+				Location: "eu-central-1",
+			},
+			bucketSecret: &secret.SecretItemResponse{
+				Type: providers.Alibaba,
+				Values: map[string]string{
+					pkgSecret.AlibabaAccessKeyId:     "key-id",
+					pkgSecret.AlibabaSecretAccessKey: "key",
+				},
+			},
+			expected: backupStorageProviderConfig{
+				Region: "oss-eu-central-1",
+				S3Url:  "https://oss-eu-central-1.aliyuncs.com",
+			},
+		},
+		"s3": {
+			bucket: bucketConfig{
+				Name:     "backups",
+				Provider: s3.Provider,
+			},
+			bucketSecret: &secret.SecretItemResponse{
+				Type: s3.Provider,
+				Values: map[string]string{
+					pkgSecret.S3Endpoint:        "http://minio:9000",
+					pkgSecret.S3AccessKeyId:     "key-id",
+					pkgSecret.S3SecretAccessKey: "key",
+				},
+			},
+			expected: backupStorageProviderConfig{
+				Region:           s3.DefaultRegion,
+				S3Url:            "http://minio:9000",
+				S3ForcePathStyle: "true",
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(name, func(t *testing.T) {
+			values, err := ConfigRequest{
+				Cluster: clusterConfig{
+					Name:     "cluster",
+					Provider: providers.Alibaba,
+					Location: "eu-central-1",
+				},
+				ClusterSecret: clusterSecret,
+				Bucket:        testCase.bucket,
+				BucketSecret:  testCase.bucketSecret,
+			}.Get()
+			require.NoError(t, err)
+
+			// volume snapshots are not supported on Alibaba
+			assert.Nil(t, values.Configuration.PersistentVolumeProvider)
+
+			assert.Equal(t, "aws", values.Configuration.BackupStorageProvider.Name)
+			assert.Equal(t, "backups", values.Configuration.BackupStorageProvider.Bucket)
+			assert.Equal(t, testCase.expected, values.Configuration.BackupStorageProvider.Config)
+
+			assert.Empty(t, values.Credentials.SecretContents.Cluster)
+			assert.Contains(t, values.Credentials.SecretContents.Bucket, `aws_access_key_id = "key-id"`)
+			assert.Contains(t, values.Credentials.SecretContents.Bucket, `aws_secret_access_key = "key"`)
+		})
+	}
+}
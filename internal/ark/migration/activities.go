@@ -0,0 +1,478 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"emperror.dev/emperror"
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/ark"
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+	"github.com/banzaicloud/pipeline/internal/ark/sync"
+	"github.com/banzaicloud/pipeline/pkg/k8sclient"
+)
+
+const (
+	migratedByLabelKey   = "migrated-by"
+	migratedByLabelValue = "pipeline"
+)
+
+// nolint: gochecknoglobals
+var nonMigratableNamespaces = []string{
+	"kube-system",
+}
+
+// ClusterGetter returns clusters of an organization
+type ClusterGetter interface {
+	GetClusterByID(ctx context.Context, organizationID uint, clusterID uint) (api.Cluster, error)
+}
+
+func getOrganizationAndCluster(ctx context.Context, clusters ClusterGetter, organizationID uint, clusterID uint) (*auth.Organization, api.Cluster, error) {
+	org, err := auth.GetOrganizationById(organizationID)
+	if err != nil {
+		return nil, nil, emperror.Wrap(err, "could not get organization")
+	}
+
+	cluster, err := clusters.GetClusterByID(ctx, organizationID, clusterID)
+	if err != nil {
+		return nil, nil, emperror.WrapWith(err, "could not get cluster", "clusterId", clusterID)
+	}
+
+	return org, cluster, nil
+}
+
+const GetClusterStatusActivityName = "ark-migration-get-cluster-status"
+
+type GetClusterStatusActivityInput struct {
+	OrganizationID uint
+	ClusterID      uint
+}
+
+type GetClusterStatusActivity struct {
+	clusters ClusterGetter
+}
+
+func NewGetClusterStatusActivity(clusters ClusterGetter) GetClusterStatusActivity {
+	return GetClusterStatusActivity{
+		clusters: clusters,
+	}
+}
+
+func (a GetClusterStatusActivity) Execute(ctx context.Context, input GetClusterStatusActivityInput) (string, error) {
+	cluster, err := a.clusters.GetClusterByID(ctx, input.OrganizationID, input.ClusterID)
+	if err != nil {
+		return "", emperror.WrapWith(err, "could not get cluster", "clusterId", input.ClusterID)
+	}
+
+	status, err := cluster.GetStatus()
+	if err != nil {
+		return "", emperror.WrapWith(err, "could not get cluster status", "clusterId", input.ClusterID)
+	}
+
+	return status.Status, nil
+}
+
+const CreateBackupActivityName = "ark-migration-create-backup"
+
+type CreateBackupActivityInput struct {
+	OrganizationID uint
+	ClusterID      uint
+	BackupName     string
+	TTL            time.Duration
+	Namespaces     []string
+}
+
+// CreateBackupActivity creates a backup of the source cluster through its active ARK deployment.
+type CreateBackupActivity struct {
+	clusters ClusterGetter
+	db       *gorm.DB
+	logger   logrus.FieldLogger
+}
+
+func NewCreateBackupActivity(clusters ClusterGetter, db *gorm.DB, logger logrus.FieldLogger) CreateBackupActivity {
+	return CreateBackupActivity{
+		clusters: clusters,
+		db:       db,
+		logger:   logger,
+	}
+}
+
+func (a CreateBackupActivity) Execute(ctx context.Context, input CreateBackupActivityInput) error {
+	org, cluster, err := getOrganizationAndCluster(ctx, a.clusters, input.OrganizationID, input.ClusterID)
+	if err != nil {
+		return err
+	}
+
+	svc := ark.NewARKService(org, cluster, a.db, a.logger)
+
+	return svc.GetClusterBackupsService().Create(api.CreateBackupRequest{
+		Name:   input.BackupName,
+		TTL:    metav1.Duration{Duration: input.TTL},
+		Labels: labels.Set{migratedByLabelKey: migratedByLabelValue},
+		Options: api.BackupOptions{
+			IncludedNamespaces: input.Namespaces,
+			ExcludedNamespaces: nonMigratableNamespaces,
+		},
+	})
+}
+
+// GetPhaseActivityInput describes the input of the activities returning the phase of an ARK backup or restore
+type GetPhaseActivityInput struct {
+	OrganizationID uint
+	ClusterID      uint
+	Name           string
+}
+
+const GetBackupPhaseActivityName = "ark-migration-get-backup-phase"
+
+type GetBackupPhaseActivity struct {
+	clusters ClusterGetter
+	db       *gorm.DB
+	logger   logrus.FieldLogger
+}
+
+func NewGetBackupPhaseActivity(clusters ClusterGetter, db *gorm.DB, logger logrus.FieldLogger) GetBackupPhaseActivity {
+	return GetBackupPhaseActivity{
+		clusters: clusters,
+		db:       db,
+		logger:   logger,
+	}
+}
+
+func (a GetBackupPhaseActivity) Execute(ctx context.Context, input GetPhaseActivityInput) (string, error) {
+	org, cluster, err := getOrganizationAndCluster(ctx, a.clusters, input.OrganizationID, input.ClusterID)
+	if err != nil {
+		return "", err
+	}
+
+	err = sync.NewBackupsSyncService(org, a.db, a.logger).SyncBackupsForCluster(cluster)
+	if err != nil {
+		return "", emperror.Wrap(err, "could not sync backups")
+	}
+
+	backup, err := ark.BackupsServiceFactory(org, a.db, a.logger).GetModelByName(input.Name)
+	if err != nil {
+		return "", err
+	}
+
+	return backup.Status, nil
+}
+
+const CreateRestoreActivityName = "ark-migration-create-restore"
+
+type CreateRestoreActivityInput struct {
+	OrganizationID  uint
+	MigrationID     uint
+	SourceClusterID uint
+	TargetClusterID uint
+	BackupName      string
+	Options         api.MigrationOptions
+}
+
+// CreateRestoreActivity prepares the storage classes of the target cluster,
+// deploys ARK into it in restore mode (unless it already uses the bucket of the backup)
+// and starts restoring the backup.
+type CreateRestoreActivity struct {
+	clusters ClusterGetter
+	db       *gorm.DB
+	logger   logrus.FieldLogger
+}
+
+func NewCreateRestoreActivity(clusters ClusterGetter, db *gorm.DB, logger logrus.FieldLogger) CreateRestoreActivity {
+	return CreateRestoreActivity{
+		clusters: clusters,
+		db:       db,
+		logger:   logger,
+	}
+}
+
+func (a CreateRestoreActivity) Execute(ctx context.Context, input CreateRestoreActivityInput) (string, error) {
+	org, cluster, err := getOrganizationAndCluster(ctx, a.clusters, input.OrganizationID, input.TargetClusterID)
+	if err != nil {
+		return "", err
+	}
+
+	backup, err := ark.BackupsServiceFactory(org, a.db, a.logger).GetModelByName(input.BackupName)
+	if err != nil {
+		return "", err
+	}
+
+	if len(input.Options.StorageClassMapping) > 0 {
+		k8sConfig, err := cluster.GetK8sConfig()
+		if err != nil {
+			return "", emperror.Wrap(err, "could not get k8s config")
+		}
+
+		client, err := k8sclient.NewClientFromKubeConfig(k8sConfig)
+		if err != nil {
+			return "", emperror.Wrap(err, "could not create k8s client")
+		}
+
+		err = MapStorageClasses(client, input.Options.StorageClassMapping)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	svc := ark.NewARKService(org, cluster, a.db, a.logger)
+
+	deployment, err := svc.GetDeploymentsService().GetActiveDeployment()
+	if gorm.IsRecordNotFoundError(err) {
+		err = svc.GetDeploymentsService().Deploy(&backup.Bucket, true)
+		if err != nil {
+			return "", emperror.Wrap(err, "could not deploy ARK in restore mode")
+		}
+	} else if err != nil {
+		return "", emperror.Wrap(err, "could not get active deployment")
+	} else if deployment.BucketID != backup.BucketID {
+		return "", errors.NewWithDetails(
+			"backup service of target cluster uses a different bucket",
+			"clusterId", input.TargetClusterID,
+			"bucketId", deployment.BucketID,
+		)
+	}
+
+	restore, err := svc.GetRestoresService().Create(api.CreateRestoreRequest{
+		BackupName: input.BackupName,
+		Labels:     labels.Set{migratedByLabelKey: migratedByLabelValue},
+		Options: api.RestoreOptions{
+			ExcludedNamespaces: nonMigratableNamespaces,
+			NamespaceMapping:   input.Options.NamespaceMapping,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	err = ark.MigrationsServiceFactory(org, a.db, a.logger).UpdateRestoreName(input.MigrationID, restore.Name)
+	if err != nil {
+		return "", err
+	}
+
+	return restore.Name, nil
+}
+
+const GetRestorePhaseActivityName = "ark-migration-get-restore-phase"
+
+type GetRestorePhaseActivity struct {
+	clusters ClusterGetter
+	db       *gorm.DB
+	logger   logrus.FieldLogger
+}
+
+func NewGetRestorePhaseActivity(clusters ClusterGetter, db *gorm.DB, logger logrus.FieldLogger) GetRestorePhaseActivity {
+	return GetRestorePhaseActivity{
+		clusters: clusters,
+		db:       db,
+		logger:   logger,
+	}
+}
+
+func (a GetRestorePhaseActivity) Execute(ctx context.Context, input GetPhaseActivityInput) (string, error) {
+	org, cluster, err := getOrganizationAndCluster(ctx, a.clusters, input.OrganizationID, input.ClusterID)
+	if err != nil {
+		return "", err
+	}
+
+	err = sync.NewRestoresSyncService(org, a.db, a.logger).SyncRestoresForCluster(cluster)
+	if err != nil {
+		return "", emperror.Wrap(err, "could not sync restores")
+	}
+
+	restore, err := ark.NewARKService(org, cluster, a.db, a.logger).GetRestoresService().GetByName(input.Name)
+	if err != nil {
+		return "", err
+	}
+
+	return restore.Status, nil
+}
+
+const FinishRestoreActivityName = "ark-migration-finish-restore"
+
+type FinishRestoreActivityInput struct {
+	OrganizationID  uint
+	MigrationID     uint
+	TargetClusterID uint
+	BackupName      string
+	RestoreName     string
+}
+
+// FinishRestoreActivity records the per-resource results of the restore
+// and removes the ARK deployment of the target cluster if it was deployed in restore mode.
+type FinishRestoreActivity struct {
+	clusters ClusterGetter
+	db       *gorm.DB
+	logger   logrus.FieldLogger
+}
+
+func NewFinishRestoreActivity(clusters ClusterGetter, db *gorm.DB, logger logrus.FieldLogger) FinishRestoreActivity {
+	return FinishRestoreActivity{
+		clusters: clusters,
+		db:       db,
+		logger:   logger,
+	}
+}
+
+func (a FinishRestoreActivity) Execute(ctx context.Context, input FinishRestoreActivityInput) error {
+	org, cluster, err := getOrganizationAndCluster(ctx, a.clusters, input.OrganizationID, input.TargetClusterID)
+	if err != nil {
+		return err
+	}
+
+	logger := a.logger.WithFields(logrus.Fields{
+		"migration": input.MigrationID,
+		"restore":   input.RestoreName,
+	})
+
+	backup, err := ark.BackupsServiceFactory(org, a.db, a.logger).GetModelByName(input.BackupName)
+	if err != nil {
+		return err
+	}
+
+	results, err := getRestoreResults(org, a.db, a.logger, backup.BucketID, input.BackupName, input.RestoreName)
+	if err != nil {
+		logger.WithError(err).Warning("could not get restore results")
+	} else {
+		err = ark.MigrationsServiceFactory(org, a.db, a.logger).UpdateResults(input.MigrationID, results)
+		if err != nil {
+			return err
+		}
+	}
+
+	deployments := ark.DeploymentsServiceFactory(org, cluster, a.db, a.logger)
+
+	deployment, err := deployments.GetActiveDeployment()
+	if err != nil {
+		return emperror.Wrap(err, "could not get active deployment")
+	}
+
+	if !deployment.RestoreMode {
+		return nil
+	}
+
+	return emperror.Wrap(deployments.Remove(), "could not remove ARK deployment")
+}
+
+func getRestoreResults(
+	org *auth.Organization,
+	db *gorm.DB,
+	logger logrus.FieldLogger,
+	bucketID uint,
+	backupName string,
+	restoreName string,
+) (*api.RestoreResults, error) {
+
+	bs := ark.BucketsServiceFactory(org, db, logger)
+	bucket, err := bs.GetByID(bucketID)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	err = bs.StreamRestoreResultsFromObjectStore(bucket, backupName, restoreName, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	var results api.RestoreResults
+	err = json.Unmarshal(buf.Bytes(), &results)
+	if err != nil {
+		return nil, err
+	}
+
+	return &results, nil
+}
+
+const StopExternalDNSActivityName = "ark-migration-stop-external-dns"
+
+type StopExternalDNSActivityInput struct {
+	OrganizationID uint
+	ClusterID      uint
+}
+
+// StopExternalDNSActivity scales the external-dns deployment of the source cluster to zero,
+// so that it does not recreate the records which are handed over to the target cluster.
+type StopExternalDNSActivity struct {
+	clusters    ClusterGetter
+	namespace   string
+	releaseName string
+}
+
+func NewStopExternalDNSActivity(clusters ClusterGetter, namespace string, releaseName string) StopExternalDNSActivity {
+	return StopExternalDNSActivity{
+		clusters:    clusters,
+		namespace:   namespace,
+		releaseName: releaseName,
+	}
+}
+
+func (a StopExternalDNSActivity) Execute(ctx context.Context, input StopExternalDNSActivityInput) error {
+	cluster, err := a.clusters.GetClusterByID(ctx, input.OrganizationID, input.ClusterID)
+	if err != nil {
+		return emperror.WrapWith(err, "could not get cluster", "clusterId", input.ClusterID)
+	}
+
+	k8sConfig, err := cluster.GetK8sConfig()
+	if err != nil {
+		return emperror.Wrap(err, "could not get k8s config")
+	}
+
+	client, err := k8sclient.NewClientFromKubeConfig(k8sConfig)
+	if err != nil {
+		return emperror.Wrap(err, "could not create k8s client")
+	}
+
+	return ScaleDownRelease(client, a.namespace, a.releaseName)
+}
+
+const UpdateStatusActivityName = "ark-migration-update-status"
+
+type UpdateStatusActivityInput struct {
+	OrganizationID uint
+	MigrationID    uint
+	Status         string
+	StatusMessage  string
+}
+
+type UpdateStatusActivity struct {
+	db     *gorm.DB
+	logger logrus.FieldLogger
+}
+
+func NewUpdateStatusActivity(db *gorm.DB, logger logrus.FieldLogger) UpdateStatusActivity {
+	return UpdateStatusActivity{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (a UpdateStatusActivity) Execute(ctx context.Context, input UpdateStatusActivityInput) error {
+	org, err := auth.GetOrganizationById(input.OrganizationID)
+	if err != nil {
+		return emperror.Wrap(err, "could not get organization")
+	}
+
+	return ark.MigrationsServiceFactory(org, a.db, a.logger).UpdateStatus(input.MigrationID, input.Status, input.StatusMessage)
+}
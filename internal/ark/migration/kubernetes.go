@@ -0,0 +1,88 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migration
+
+import (
+	"emperror.dev/emperror"
+	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// MapStorageClasses makes the storage classes of the source cluster available in the target cluster.
+// For every source class missing from the target cluster a class with the same name is created,
+// which provisions volumes the same way as the mapped target class.
+func MapStorageClasses(client kubernetes.Interface, mapping map[string]string) error {
+	storageClasses := client.StorageV1().StorageClasses()
+
+	for sourceName, targetName := range mapping {
+		_, err := storageClasses.Get(sourceName, metav1.GetOptions{})
+		if err == nil {
+			continue
+		}
+		if !k8serrors.IsNotFound(err) {
+			return emperror.WrapWith(err, "could not get storage class", "storageClass", sourceName)
+		}
+
+		target, err := storageClasses.Get(targetName, metav1.GetOptions{})
+		if err != nil {
+			return emperror.WrapWith(err, "could not get storage class", "storageClass", targetName)
+		}
+
+		_, err = storageClasses.Create(&storagev1.StorageClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   sourceName,
+				Labels: map[string]string{migratedByLabelKey: migratedByLabelValue},
+			},
+			Provisioner:          target.Provisioner,
+			Parameters:           target.Parameters,
+			ReclaimPolicy:        target.ReclaimPolicy,
+			MountOptions:         target.MountOptions,
+			AllowVolumeExpansion: target.AllowVolumeExpansion,
+			VolumeBindingMode:    target.VolumeBindingMode,
+			AllowedTopologies:    target.AllowedTopologies,
+		})
+		if err != nil {
+			return emperror.WrapWith(err, "could not create storage class", "storageClass", sourceName)
+		}
+	}
+
+	return nil
+}
+
+// ScaleDownRelease scales every deployment of a Helm release to zero replicas.
+func ScaleDownRelease(client kubernetes.Interface, namespace string, releaseName string) error {
+	deployments := client.AppsV1().Deployments(namespace)
+
+	list, err := deployments.List(metav1.ListOptions{LabelSelector: "release=" + releaseName})
+	if err != nil {
+		return emperror.WrapWith(err, "could not list deployments", "release", releaseName)
+	}
+
+	for _, deployment := range list.Items {
+		deployment := deployment
+
+		replicas := int32(0)
+		deployment.Spec.Replicas = &replicas
+
+		_, err = deployments.Update(&deployment)
+		if err != nil {
+			return emperror.WrapWith(err, "could not scale down deployment", "deployment", deployment.Name)
+		}
+	}
+
+	return nil
+}
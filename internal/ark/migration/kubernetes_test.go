@@ -0,0 +1,82 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestMapStorageClasses(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&storagev1.StorageClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: "standard"},
+			Provisioner: "kubernetes.io/gce-pd",
+			Parameters:  map[string]string{"type": "pd-standard"},
+		},
+		&storagev1.StorageClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: "fast"},
+			Provisioner: "kubernetes.io/gce-pd",
+		},
+	)
+
+	err := MapStorageClasses(client, map[string]string{
+		"gp2":  "standard",
+		"fast": "standard",
+	})
+	require.NoError(t, err)
+
+	gp2, err := client.StorageV1().StorageClasses().Get("gp2", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "kubernetes.io/gce-pd", gp2.Provisioner)
+	assert.Equal(t, map[string]string{"type": "pd-standard"}, gp2.Parameters)
+
+	fast, err := client.StorageV1().StorageClasses().Get("fast", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, fast.Parameters)
+
+	err = MapStorageClasses(client, map[string]string{"io1": "missing"})
+	assert.Error(t, err)
+}
+
+func TestScaleDownRelease(t *testing.T) {
+	replicas := int32(1)
+	client := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "dns-external-dns", Namespace: "pipeline-system", Labels: map[string]string{"release": "dns"}},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "pipeline-system", Labels: map[string]string{"release": "other"}},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		},
+	)
+
+	require.NoError(t, ScaleDownRelease(client, "pipeline-system", "dns"))
+
+	dns, err := client.AppsV1().Deployments("pipeline-system").Get("dns-external-dns", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), *dns.Spec.Replicas)
+
+	other, err := client.AppsV1().Deployments("pipeline-system").Get("other", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), *other.Spec.Replicas)
+}
@@ -0,0 +1,267 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"emperror.dev/errors"
+	"go.uber.org/cadence/client"
+	"go.uber.org/cadence/workflow"
+
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+	intClusterWorkflow "github.com/banzaicloud/pipeline/internal/cluster/workflow"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+const (
+	// MigrateClusterWorkflowName moves workloads from a cluster to another using an ARK backup.
+	MigrateClusterWorkflowName = "ark-migrate-cluster"
+
+	taskList = "pipeline"
+
+	pollInterval = 30 * time.Second
+)
+
+// nolint: gochecknoglobals
+var failedPhases = []string{"Failed", "FailedValidation", "PartiallyFailed"}
+
+// MigrateClusterWorkflowInput describes the input of the cluster migration workflow
+type MigrateClusterWorkflowInput struct {
+	OrganizationID   uint
+	MigrationID      uint
+	SourceClusterID  uint
+	SourceClusterUID string
+	TargetClusterID  uint
+	BackupName       string
+	BackupTTL        time.Duration
+	Options          api.MigrationOptions
+}
+
+// MigrateClusterWorkflow backs up the selected namespaces of the source cluster,
+// waits for the target cluster to be ready and restores the backup into it.
+// The DNS records of the source cluster are released for the target cluster if requested.
+func MigrateClusterWorkflow(ctx workflow.Context, input MigrateClusterWorkflowInput) (err error) {
+	ao := workflow.ActivityOptions{
+		ScheduleToStartTimeout: 5 * time.Minute,
+		StartToCloseTimeout:    10 * time.Minute,
+		WaitForCancellation:    true,
+	}
+	ctx = workflow.WithActivityOptions(ctx, ao)
+
+	defer func() {
+		status, message := api.MigrationStatusCompleted, ""
+		if err != nil {
+			status, message = api.MigrationStatusFailed, err.Error()
+		}
+
+		if serr := updateStatus(ctx, input, status, message); serr != nil && err == nil {
+			err = serr
+		}
+	}()
+
+	if err = waitForCluster(ctx, input.OrganizationID, input.TargetClusterID); err != nil {
+		return err
+	}
+
+	if err = updateStatus(ctx, input, api.MigrationStatusBackingUp, ""); err != nil {
+		return err
+	}
+
+	{
+		activityInput := CreateBackupActivityInput{
+			OrganizationID: input.OrganizationID,
+			ClusterID:      input.SourceClusterID,
+			BackupName:     input.BackupName,
+			TTL:            input.BackupTTL,
+			Namespaces:     input.Options.Namespaces,
+		}
+
+		if err = workflow.ExecuteActivity(ctx, CreateBackupActivityName, activityInput).Get(ctx, nil); err != nil {
+			return err
+		}
+	}
+
+	{
+		activityInput := GetPhaseActivityInput{
+			OrganizationID: input.OrganizationID,
+			ClusterID:      input.SourceClusterID,
+			Name:           input.BackupName,
+		}
+
+		if err = waitForPhase(ctx, GetBackupPhaseActivityName, activityInput); err != nil {
+			return errors.WrapIf(err, "failed to back up source cluster")
+		}
+	}
+
+	if err = updateStatus(ctx, input, api.MigrationStatusRestoring, ""); err != nil {
+		return err
+	}
+
+	var restoreName string
+	{
+		activityInput := CreateRestoreActivityInput{
+			OrganizationID:  input.OrganizationID,
+			MigrationID:     input.MigrationID,
+			SourceClusterID: input.SourceClusterID,
+			TargetClusterID: input.TargetClusterID,
+			BackupName:      input.BackupName,
+			Options:         input.Options,
+		}
+
+		if err = workflow.ExecuteActivity(ctx, CreateRestoreActivityName, activityInput).Get(ctx, &restoreName); err != nil {
+			return err
+		}
+	}
+
+	{
+		activityInput := GetPhaseActivityInput{
+			OrganizationID: input.OrganizationID,
+			ClusterID:      input.TargetClusterID,
+			Name:           restoreName,
+		}
+
+		err = waitForPhase(ctx, GetRestorePhaseActivityName, activityInput)
+		err = errors.WrapIf(err, "failed to restore into target cluster")
+	}
+
+	{
+		activityInput := FinishRestoreActivityInput{
+			OrganizationID:  input.OrganizationID,
+			MigrationID:     input.MigrationID,
+			TargetClusterID: input.TargetClusterID,
+			BackupName:      input.BackupName,
+			RestoreName:     restoreName,
+		}
+
+		ferr := workflow.ExecuteActivity(ctx, FinishRestoreActivityName, activityInput).Get(ctx, nil)
+		if err = errors.Combine(err, ferr); err != nil {
+			return err
+		}
+	}
+
+	if !input.Options.CutoverDNS {
+		return nil
+	}
+
+	if err = updateStatus(ctx, input, api.MigrationStatusCuttingOver, ""); err != nil {
+		return err
+	}
+
+	{
+		activityInput := StopExternalDNSActivityInput{
+			OrganizationID: input.OrganizationID,
+			ClusterID:      input.SourceClusterID,
+		}
+
+		if err = workflow.ExecuteActivity(ctx, StopExternalDNSActivityName, activityInput).Get(ctx, nil); err != nil {
+			return err
+		}
+	}
+
+	{
+		activityInput := intClusterWorkflow.DeleteClusterDNSRecordsActivityInput{
+			OrganizationID: input.OrganizationID,
+			ClusterUID:     input.SourceClusterUID,
+		}
+
+		err = workflow.ExecuteActivity(ctx, intClusterWorkflow.DeleteClusterDNSRecordsActivityName, activityInput).Get(ctx, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func updateStatus(ctx workflow.Context, input MigrateClusterWorkflowInput, status string, message string) error {
+	activityInput := UpdateStatusActivityInput{
+		OrganizationID: input.OrganizationID,
+		MigrationID:    input.MigrationID,
+		Status:         status,
+		StatusMessage:  message,
+	}
+
+	return workflow.ExecuteActivity(ctx, UpdateStatusActivityName, activityInput).Get(ctx, nil)
+}
+
+// waitForCluster polls the status of a cluster until it is running.
+func waitForCluster(ctx workflow.Context, organizationID uint, clusterID uint) error {
+	activityInput := GetClusterStatusActivityInput{
+		OrganizationID: organizationID,
+		ClusterID:      clusterID,
+	}
+
+	for {
+		var status string
+		if err := workflow.ExecuteActivity(ctx, GetClusterStatusActivityName, activityInput).Get(ctx, &status); err != nil {
+			return err
+		}
+
+		switch status {
+		case pkgCluster.Running:
+			return nil
+		case pkgCluster.Error:
+			return errors.NewWithDetails("target cluster is in error state", "clusterId", clusterID)
+		}
+
+		if err := workflow.Sleep(ctx, pollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// waitForPhase polls the phase of an ARK backup or restore until it is completed.
+func waitForPhase(ctx workflow.Context, activityName string, activityInput GetPhaseActivityInput) error {
+	for {
+		var phase string
+		if err := workflow.ExecuteActivity(ctx, activityName, activityInput).Get(ctx, &phase); err != nil {
+			return err
+		}
+
+		if phase == "Completed" {
+			return nil
+		}
+
+		for _, failedPhase := range failedPhases {
+			if phase == failedPhase {
+				return errors.NewWithDetails("unexpected phase", "name", activityInput.Name, "phase", phase)
+			}
+		}
+
+		if err := workflow.Sleep(ctx, pollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// StartMigration starts the migration workflow of a persisted migration.
+func StartMigration(ctx context.Context, workflowClient client.Client, input MigrateClusterWorkflowInput) (string, error) {
+	workflowOptions := client.StartWorkflowOptions{
+		ID:                           fmt.Sprintf("ark-migrate-cluster-%d", input.MigrationID),
+		WorkflowIDReusePolicy:        client.WorkflowIDReusePolicyAllowDuplicate,
+		TaskList:                     taskList,
+		ExecutionStartToCloseTimeout: 6 * time.Hour,
+	}
+
+	execution, err := workflowClient.StartWorkflow(ctx, workflowOptions, MigrateClusterWorkflowName, input)
+	if err != nil {
+		return "", err
+	}
+
+	return execution.ID, nil
+}
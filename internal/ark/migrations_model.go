@@ -0,0 +1,114 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ark
+
+import (
+	"encoding/json"
+	"time"
+
+	"emperror.dev/emperror"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+)
+
+// ClusterMigrationsModel describes an ARK migration model
+type ClusterMigrationsModel struct {
+	ID uint `gorm:"primary_key"`
+
+	SourceClusterID uint `gorm:"index;not null"`
+	TargetClusterID uint `gorm:"index;not null"`
+	BackupName      string
+	RestoreName     string
+
+	Options []byte `sql:"type:json"`
+	Results []byte `sql:"type:json"`
+
+	Organization   auth.Organization `gorm:"foreignkey:OrganizationID"`
+	OrganizationID uint              `gorm:"index;not null"`
+
+	Status        string
+	StatusMessage string `sql:"type:text;"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	CreatedBy uint
+}
+
+// TableName changes the default table name
+func (ClusterMigrationsModel) TableName() string {
+	return clusterMigrationsTableName
+}
+
+// SetValuesFromRequest set values from a PersistMigrationRequest to the migration object
+func (migration *ClusterMigrationsModel) SetValuesFromRequest(req *api.PersistMigrationRequest) error {
+
+	optionsJSON, err := json.Marshal(req.Options)
+	if err != nil {
+		return emperror.Wrap(err, "error converting options to json")
+	}
+
+	migration.SourceClusterID = req.SourceClusterID
+	migration.TargetClusterID = req.TargetClusterID
+	migration.BackupName = req.BackupName
+	migration.Options = optionsJSON
+	migration.Status = api.MigrationStatusPending
+	migration.CreatedBy = req.CreatedBy
+
+	return nil
+}
+
+// ConvertModelToEntity converts ClusterMigrationsModel to api.Migration
+func (migration *ClusterMigrationsModel) ConvertModelToEntity() *api.Migration {
+
+	return &api.Migration{
+		ID:              migration.ID,
+		SourceClusterID: migration.SourceClusterID,
+		TargetClusterID: migration.TargetClusterID,
+		BackupName:      migration.BackupName,
+		RestoreName:     migration.RestoreName,
+		Status:          migration.Status,
+		StatusMessage:   migration.StatusMessage,
+		Options:         migration.GetOptions(),
+		Results:         migration.GetResults(),
+		CreatedAt:       migration.CreatedAt,
+		UpdatedAt:       migration.UpdatedAt,
+	}
+}
+
+// GetOptions unmarshals the stored options JSON into api.MigrationOptions
+func (migration *ClusterMigrationsModel) GetOptions() api.MigrationOptions {
+
+	var options api.MigrationOptions
+	_ = json.Unmarshal(migration.Options, &options)
+
+	return options
+}
+
+// GetResults unmarshals the stored results JSON into api.RestoreResults
+func (migration *ClusterMigrationsModel) GetResults() *api.RestoreResults {
+
+	if len(migration.Results) == 0 {
+		return nil
+	}
+
+	var results *api.RestoreResults
+	err := json.Unmarshal(migration.Results, &results)
+	if err != nil {
+		return nil
+	}
+
+	return results
+}
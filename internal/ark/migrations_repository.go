@@ -0,0 +1,119 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ark
+
+import (
+	"encoding/json"
+
+	"emperror.dev/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+)
+
+// MigrationsRepository is a repository for managing ARK migration models
+type MigrationsRepository struct {
+	org    *auth.Organization
+	db     *gorm.DB
+	logger logrus.FieldLogger
+}
+
+// NewMigrationsRepository creates and returns a MigrationsRepository instance
+func NewMigrationsRepository(org *auth.Organization, db *gorm.DB, logger logrus.FieldLogger) *MigrationsRepository {
+
+	return &MigrationsRepository{
+		org:    org,
+		db:     db,
+		logger: logger,
+	}
+}
+
+// FindBySourceClusterID finds all ClusterMigrationsModel of a source cluster
+func (r *MigrationsRepository) FindBySourceClusterID(clusterID uint) ([]*ClusterMigrationsModel, error) {
+	var migrations []*ClusterMigrationsModel
+
+	query := ClusterMigrationsModel{
+		OrganizationID:  r.org.ID,
+		SourceClusterID: clusterID,
+	}
+
+	err := r.db.Where(&query).Order("id").Find(&migrations).Error
+
+	return migrations, err
+}
+
+// FindOneByID finds one ClusterMigrationsModel by ID
+func (r *MigrationsRepository) FindOneByID(id uint) (*ClusterMigrationsModel, error) {
+	var migration ClusterMigrationsModel
+
+	query := ClusterMigrationsModel{
+		ID:             id,
+		OrganizationID: r.org.ID,
+	}
+
+	err := r.db.Where(&query).First(&migration).Error
+
+	return &migration, err
+}
+
+// Persist persists a ClusterMigrationsModel by a PersistMigrationRequest
+func (r *MigrationsRepository) Persist(req *api.PersistMigrationRequest) (*ClusterMigrationsModel, error) {
+
+	migration := ClusterMigrationsModel{
+		OrganizationID: r.org.ID,
+	}
+
+	err := migration.SetValuesFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Create(&migration).Error
+	if err != nil {
+		return nil, emperror.Wrap(err, "error persisting migration")
+	}
+
+	r.logger.WithField("migration-id", migration.ID).Debug("migration persisted")
+
+	return &migration, nil
+}
+
+// UpdateStatus updates the status of a ClusterMigrationsModel
+func (r *MigrationsRepository) UpdateStatus(migration *ClusterMigrationsModel, status, message string) error {
+
+	return r.db.Model(migration).Updates(map[string]interface{}{
+		"status":         status,
+		"status_message": message,
+	}).Error
+}
+
+// UpdateRestoreName updates the name of the restore of a ClusterMigrationsModel
+func (r *MigrationsRepository) UpdateRestoreName(migration *ClusterMigrationsModel, name string) error {
+
+	return r.db.Model(migration).Update("restore_name", name).Error
+}
+
+// UpdateResults updates the restore results of a ClusterMigrationsModel
+func (r *MigrationsRepository) UpdateResults(migration *ClusterMigrationsModel, results *api.RestoreResults) error {
+
+	resultsJSON, err := json.Marshal(results)
+	if err != nil {
+		return emperror.Wrap(err, "error converting results to json")
+	}
+
+	return r.db.Model(migration).Update("results", resultsJSON).Error
+}
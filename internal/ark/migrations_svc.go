@@ -0,0 +1,134 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ark
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+)
+
+// MigrationsService is for managing ARK migrations between clusters
+type MigrationsService struct {
+	org        *auth.Organization
+	repository *MigrationsRepository
+	logger     logrus.FieldLogger
+}
+
+// MigrationsServiceFactory creates and returns an initialized MigrationsService instance
+func MigrationsServiceFactory(org *auth.Organization, db *gorm.DB, logger logrus.FieldLogger) *MigrationsService {
+
+	return NewMigrationsService(org, NewMigrationsRepository(org, db, logger), logger)
+}
+
+// NewMigrationsService creates and returns an initialized MigrationsService instance
+func NewMigrationsService(
+	org *auth.Organization,
+	repository *MigrationsRepository,
+	logger logrus.FieldLogger,
+) *MigrationsService {
+
+	return &MigrationsService{
+		org:        org,
+		repository: repository,
+		logger:     logger,
+	}
+}
+
+// GetModelByID gets a ClusterMigrationsModel by ID
+func (s *MigrationsService) GetModelByID(id uint) (*ClusterMigrationsModel, error) {
+
+	model, err := s.repository.FindOneByID(id)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get migration from database")
+	}
+
+	return model, nil
+}
+
+// GetByID gets a Migration by ID
+func (s *MigrationsService) GetByID(id uint) (*api.Migration, error) {
+
+	model, err := s.GetModelByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.ConvertModelToEntity(), nil
+}
+
+// ListBySourceCluster gets all migrations started from the given cluster
+func (s *MigrationsService) ListBySourceCluster(clusterID uint) ([]*api.Migration, error) {
+
+	migrations := make([]*api.Migration, 0)
+
+	items, err := s.repository.FindBySourceClusterID(clusterID)
+	if err != nil {
+		return migrations, err
+	}
+
+	for _, item := range items {
+		migrations = append(migrations, item.ConvertModelToEntity())
+	}
+
+	return migrations, nil
+}
+
+// Create persists a new migration in pending status
+func (s *MigrationsService) Create(req *api.PersistMigrationRequest) (*api.Migration, error) {
+
+	model, err := s.repository.Persist(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.ConvertModelToEntity(), nil
+}
+
+// UpdateStatus updates the status of a migration
+func (s *MigrationsService) UpdateStatus(id uint, status string, message string) error {
+
+	model, err := s.GetModelByID(id)
+	if err != nil {
+		return err
+	}
+
+	return s.repository.UpdateStatus(model, status, message)
+}
+
+// UpdateRestoreName records the name of the restore created by a migration
+func (s *MigrationsService) UpdateRestoreName(id uint, name string) error {
+
+	model, err := s.GetModelByID(id)
+	if err != nil {
+		return err
+	}
+
+	return s.repository.UpdateRestoreName(model, name)
+}
+
+// UpdateResults records the per-resource results of the restore created by a migration
+func (s *MigrationsService) UpdateResults(id uint, results *api.RestoreResults) error {
+
+	model, err := s.GetModelByID(id)
+	if err != nil {
+		return err
+	}
+
+	return s.repository.UpdateResults(model, results)
+}
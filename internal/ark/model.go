@@ -28,6 +28,7 @@ const (
 	clusterBackupBucketsTableName     = "ark_backup_buckets"
 	clusterBackupDeploymentsTableName = "ark_deployments"
 	clusterBackupsTableName           = "ark_backups"
+	clusterMigrationsTableName        = "ark_migrations"
 )
 
 // Migrate executes the table migrations for Ark.
@@ -37,6 +38,7 @@ func Migrate(db *gorm.DB, logger logrus.FieldLogger) error {
 		&ClusterBackupBucketsModel{},
 		&ClusterBackupRestoresModel{},
 		&ClusterBackupDeploymentsModel{},
+		&ClusterMigrationsModel{},
 	}
 
 	var tableNames string
@@ -17,9 +17,12 @@ package ark
 import (
 	"github.com/heptio/ark/pkg/cloudprovider"
 
+	"github.com/banzaicloud/pipeline/internal/ark/providers/alibaba"
 	"github.com/banzaicloud/pipeline/internal/ark/providers/amazon"
 	"github.com/banzaicloud/pipeline/internal/ark/providers/azure"
 	"github.com/banzaicloud/pipeline/internal/ark/providers/google"
+	"github.com/banzaicloud/pipeline/internal/ark/providers/oracle"
+	"github.com/banzaicloud/pipeline/internal/ark/providers/s3"
 	iProviders "github.com/banzaicloud/pipeline/internal/providers"
 	pkgErrors "github.com/banzaicloud/pipeline/pkg/errors"
 	"github.com/banzaicloud/pipeline/pkg/providers"
@@ -35,6 +38,12 @@ func NewObjectStore(ctx iProviders.ObjectStoreContext) (cloudprovider.ObjectStor
 		return amazon.NewObjectStore(ctx)
 	case providers.Azure:
 		return azure.NewObjectStore(ctx)
+	case providers.Alibaba:
+		return alibaba.NewObjectStore(ctx)
+	case providers.Oracle:
+		return oracle.NewObjectStore(ctx)
+	case s3.Provider:
+		return s3.NewObjectStore(ctx)
 	default:
 		return nil, pkgErrors.ErrorNotSupportedCloudType
 	}
@@ -0,0 +1,35 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alibaba
+
+import (
+	"fmt"
+)
+
+const (
+	// BackupStorageProvider is a config value for ARK
+	// OSS is accessed through its Amazon S3 compatible API
+	BackupStorageProvider = "aws"
+)
+
+// GetS3URL returns the Amazon S3 compatible endpoint of the given OSS region
+func GetS3URL(location string) string {
+	return fmt.Sprintf("https://oss-%s.aliyuncs.com", location)
+}
+
+// GetS3Region returns the signing region of the given OSS region
+func GetS3Region(location string) string {
+	return fmt.Sprintf("oss-%s", location)
+}
@@ -0,0 +1,44 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oracle
+
+import (
+	"fmt"
+
+	"emperror.dev/emperror"
+
+	pkgSecret "github.com/banzaicloud/pipeline/pkg/secret"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+const (
+	// BackupStorageProvider is a config value for ARK
+	// Object Storage is accessed through its Amazon S3 compatibility API
+	BackupStorageProvider = "aws"
+)
+
+// GetS3URL returns the Amazon S3 compatibility API endpoint of the object storage namespace of the secret's tenancy
+func GetS3URL(secret *secret.SecretItemResponse, location string) (string, error) {
+	if location == "" {
+		location = secret.Values[pkgSecret.OracleRegion]
+	}
+
+	os, err := newObjectStore(secret, location)
+	if err != nil {
+		return "", emperror.Wrap(err, "could not get object storage namespace")
+	}
+
+	return fmt.Sprintf("https://%s.compat.objectstorage.%s.oraclecloud.com", os.GetNamespace(), location), nil
+}
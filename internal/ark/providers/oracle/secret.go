@@ -0,0 +1,55 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oracle
+
+import (
+	"github.com/pelletier/go-toml"
+	"github.com/pkg/errors"
+
+	pkgSecret "github.com/banzaicloud/pipeline/pkg/secret"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+type secretContents struct {
+	Credentials credentials `toml:"default"`
+}
+
+type credentials struct {
+	KeyID string `toml:"aws_access_key_id"`
+	Key   string `toml:"aws_secret_access_key"`
+}
+
+// GetSecret gets formatted secret for ARK
+// The Amazon S3 compatibility API only accepts customer secret keys, so they must be present in the secret.
+func GetSecret(secret *secret.SecretItemResponse) (string, error) {
+
+	if secret.Values[pkgSecret.OracleCustomerSecretKeyID] == "" || secret.Values[pkgSecret.OracleCustomerSecretKey] == "" {
+		return "", errors.Errorf("%s and %s must be set in the secret to use Oracle object storage for backups", pkgSecret.OracleCustomerSecretKeyID, pkgSecret.OracleCustomerSecretKey)
+	}
+
+	a := secretContents{
+		Credentials: credentials{
+			KeyID: secret.Values[pkgSecret.OracleCustomerSecretKeyID],
+			Key:   secret.Values[pkgSecret.OracleCustomerSecretKey],
+		},
+	}
+
+	values, err := toml.Marshal(a)
+	if err != nil {
+		return "", err
+	}
+
+	return string(values), nil
+}
@@ -0,0 +1,31 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3
+
+import (
+	pkgSecret "github.com/banzaicloud/pipeline/pkg/secret"
+)
+
+const (
+	// Provider is the name of generic S3 compatible object storages (eg. MinIO)
+	// It matches the type of the secrets holding the endpoint and the access keys of the object storage.
+	Provider = pkgSecret.S3SecretType
+
+	// BackupStorageProvider is a config value for ARK
+	BackupStorageProvider = "aws"
+
+	// DefaultRegion is used for signing requests when no location is given for the bucket
+	DefaultRegion = "us-east-1"
+)
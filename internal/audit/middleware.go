@@ -178,11 +178,18 @@ func LogWriter(
 			userID = user.ID
 		}
 
+		// The organization is resolved by a later middleware, so it's only available after processing the request
+		var organizationID uint
+		if organization := auth.GetCurrentOrganization(c.Request); organization != nil {
+			organizationID = organization.ID
+		}
+
 		responseEvent := AuditEvent{
-			UserID:       userID,
-			StatusCode:   c.Writer.Status(),
-			ResponseSize: c.Writer.Size(),
-			ResponseTime: int(time.Since(start).Nanoseconds() / 1000 / 1000), // ms
+			UserID:         userID,
+			OrganizationID: organizationID,
+			StatusCode:     c.Writer.Status(),
+			ResponseSize:   c.Writer.Size(),
+			ResponseTime:   int(time.Since(start).Nanoseconds() / 1000 / 1000), // ms
 		}
 
 		if c.IsAborted() {
@@ -0,0 +1,140 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// DefaultQueryLimit is the number of events returned when no limit is specified.
+	DefaultQueryLimit = 100
+
+	// MaxQueryLimit is the maximum number of events returned by a single query.
+	MaxQueryLimit = 1000
+)
+
+// EventQuery contains the filters for listing audit events of an organization.
+type EventQuery struct {
+	OrganizationID uint
+
+	UserID        *uint
+	From          *time.Time
+	To            *time.Time
+	Method        string
+	PathPrefix    string
+	StatusCode    int
+	CorrelationID string
+
+	// Cursor is the ID of the last event of the previous page (events are listed in descending ID order).
+	Cursor uint
+	Limit  int
+}
+
+// EventQueryResult is a page of audit events.
+type EventQueryResult struct {
+	Events []AuditEvent
+
+	// NextCursor can be used to fetch the next page, zero if there are no more events.
+	NextCursor uint
+}
+
+// EventStore reads audit events from the database.
+type EventStore struct {
+	db *gorm.DB
+}
+
+// NewEventStore returns a new EventStore instance.
+func NewEventStore(db *gorm.DB) *EventStore {
+	return &EventStore{
+		db: db,
+	}
+}
+
+// ListEvents returns the audit events of an organization matching the query, latest first.
+func (s *EventStore) ListEvents(query EventQuery) (EventQueryResult, error) {
+	if query.OrganizationID == 0 {
+		return EventQueryResult{}, errors.New("organization ID is required for querying audit events")
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultQueryLimit
+	} else if limit > MaxQueryLimit {
+		limit = MaxQueryLimit
+	}
+
+	db := s.db.Where("organization_id = ?", query.OrganizationID)
+
+	if query.UserID != nil {
+		db = db.Where("user_id = ?", *query.UserID)
+	}
+
+	if query.From != nil {
+		db = db.Where("time >= ?", *query.From)
+	}
+
+	if query.To != nil {
+		db = db.Where("time < ?", *query.To)
+	}
+
+	if query.Method != "" {
+		db = db.Where("method = ?", strings.ToUpper(query.Method))
+	}
+
+	if query.PathPrefix != "" {
+		db = db.Where("path LIKE ? ESCAPE '!'", escapeLike(query.PathPrefix)+"%")
+	}
+
+	if query.StatusCode != 0 {
+		db = db.Where("status_code = ?", query.StatusCode)
+	}
+
+	if query.CorrelationID != "" {
+		db = db.Where("correlation_id = ?", query.CorrelationID)
+	}
+
+	if query.Cursor != 0 {
+		db = db.Where("id < ?", query.Cursor)
+	}
+
+	var events []AuditEvent
+
+	// Fetch one more event than requested to know if there is a next page
+	err := db.Order("id DESC").Limit(limit + 1).Find(&events).Error
+	if err != nil {
+		return EventQueryResult{}, errors.WrapIf(err, "failed to query audit events")
+	}
+
+	var result EventQueryResult
+
+	if len(events) > limit {
+		events = events[:limit]
+		result.NextCursor = events[limit-1].ID
+	}
+
+	result.Events = events
+
+	return result, nil
+}
+
+// escapeLike escapes the special characters of a LIKE pattern using '!' as escape character.
+func escapeLike(s string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s)
+}
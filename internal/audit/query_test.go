@@ -0,0 +1,129 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStore_ListEvents(t *testing.T) {
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, db.AutoMigrate(&AuditEvent{}).Error)
+
+	start := time.Date(2019, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	events := []AuditEvent{
+		{Time: start, OrganizationID: 1, UserID: 1, Method: http.MethodPost, Path: "/api/v1/orgs/1/clusters", StatusCode: 201, CorrelationID: "a"},
+		{Time: start.Add(time.Minute), OrganizationID: 1, UserID: 2, Method: http.MethodGet, Path: "/api/v1/orgs/1/clusters/1", StatusCode: 200, CorrelationID: "b"},
+		{Time: start.Add(2 * time.Minute), OrganizationID: 1, UserID: 1, Method: http.MethodDelete, Path: "/api/v1/orgs/1/clusters/1", StatusCode: 202, CorrelationID: "c"},
+		{Time: start.Add(3 * time.Minute), OrganizationID: 2, UserID: 3, Method: http.MethodDelete, Path: "/api/v1/orgs/2/clusters/2", StatusCode: 202, CorrelationID: "d"},
+		{Time: start.Add(4 * time.Minute), OrganizationID: 1, UserID: 2, Method: http.MethodGet, Path: "/api/v1/orgs/1/secrets_x", StatusCode: 404, CorrelationID: "e"},
+	}
+
+	for i := range events {
+		require.NoError(t, db.Create(&events[i]).Error)
+	}
+
+	store := NewEventStore(db)
+
+	userID := uint(1)
+	from := start.Add(time.Minute)
+	to := start.Add(3 * time.Minute)
+
+	tests := map[string]struct {
+		query          EventQuery
+		expectedEvents []string
+	}{
+		"all events of the organization": {
+			query:          EventQuery{OrganizationID: 1},
+			expectedEvents: []string{"e", "c", "b", "a"},
+		},
+		"by user": {
+			query:          EventQuery{OrganizationID: 1, UserID: &userID},
+			expectedEvents: []string{"c", "a"},
+		},
+		"by time range": {
+			query:          EventQuery{OrganizationID: 1, From: &from, To: &to},
+			expectedEvents: []string{"c", "b"},
+		},
+		"by method": {
+			query:          EventQuery{OrganizationID: 1, Method: "delete"},
+			expectedEvents: []string{"c"},
+		},
+		"by path prefix": {
+			query:          EventQuery{OrganizationID: 1, PathPrefix: "/api/v1/orgs/1/clusters/"},
+			expectedEvents: []string{"c", "b"},
+		},
+		"by path prefix with wildcard characters": {
+			query:          EventQuery{OrganizationID: 1, PathPrefix: "/api/v1/orgs/1/secrets_"},
+			expectedEvents: []string{"e"},
+		},
+		"by status code": {
+			query:          EventQuery{OrganizationID: 1, StatusCode: 404},
+			expectedEvents: []string{"e"},
+		},
+		"by correlation ID": {
+			query:          EventQuery{OrganizationID: 2, CorrelationID: "d"},
+			expectedEvents: []string{"d"},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			result, err := store.ListEvents(test.query)
+			require.NoError(t, err)
+
+			var correlationIDs []string
+			for _, event := range result.Events {
+				correlationIDs = append(correlationIDs, event.CorrelationID)
+			}
+
+			assert.Equal(t, test.expectedEvents, correlationIDs)
+			assert.Zero(t, result.NextCursor)
+		})
+	}
+
+	t.Run("pagination", func(t *testing.T) {
+		result, err := store.ListEvents(EventQuery{OrganizationID: 1, Limit: 3})
+		require.NoError(t, err)
+
+		require.Len(t, result.Events, 3)
+		assert.Equal(t, result.Events[2].ID, result.NextCursor)
+
+		result, err = store.ListEvents(EventQuery{OrganizationID: 1, Limit: 3, Cursor: result.NextCursor})
+		require.NoError(t, err)
+
+		require.Len(t, result.Events, 1)
+		assert.Equal(t, "a", result.Events[0].CorrelationID)
+		assert.Zero(t, result.NextCursor)
+	})
+
+	t.Run("organization is required", func(t *testing.T) {
+		_, err := store.ListEvents(EventQuery{})
+		assert.Error(t, err)
+	})
+}
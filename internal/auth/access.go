@@ -16,8 +16,9 @@ package auth
 
 // AccessManager is responsible for managing authorization rules.
 // NOTE:
-// Currently this implementation is a dummy one, and is a placeholder for a future implementation.
-// It hasn't been removed to mark the places where it should been called.
+// Organization roles (admin, member, viewer) are stored in the user_organizations table
+// and evaluated by the Enforcer, so there are no policies to be managed here at the moment.
+// The methods haven't been removed to mark the places where they should be called.
 type AccessManager struct {
 	enforcer Enforcer
 	basePath string
@@ -56,8 +56,9 @@ func (e *basicEnforcer) Enforce(org *auth.Organization, user *auth.User, path, m
 		return org.Name == orgName, nil
 	}
 
-	err := e.db.Model(user).Where(org).Related(org, "Organizations").Error
+	var membership auth.UserOrganization
 
+	err := e.db.Where(&auth.UserOrganization{UserID: user.ID, OrganizationID: org.ID}).First(&membership).Error
 	if err != nil {
 		if gorm.IsRecordNotFoundError(err) {
 			return false, nil
@@ -65,7 +66,7 @@ func (e *basicEnforcer) Enforce(org *auth.Organization, user *auth.User, path, m
 		return false, emperror.Wrap(err, "failed to query user's organizations from db")
 	}
 
-	return true, nil
+	return roleGrants(membership.Role, path, method), nil
 }
 
 // NewEnforcer returns a new enforcer.
@@ -0,0 +1,100 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/banzaicloud/pipeline/auth"
+)
+
+func addUserToOrgWithRole(t *testing.T, db *gorm.DB, user *auth.User, org *auth.Organization, role string) {
+	db.AutoMigrate(auth.UserOrganization{})
+	if err := db.Create(&auth.UserOrganization{OrganizationID: org.ID, UserID: user.ID, Role: role}).Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnforcer_Roles(t *testing.T) {
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	enforcer := NewEnforcer(db)
+
+	org := newOrg(t, db, 1, "org")
+	admin := newUser(t, db, 1, "admin")
+	member := newUser(t, db, 2, "member")
+	viewer := newUser(t, db, 3, "viewer")
+	unknown := newUser(t, db, 4, "unknown")
+
+	addUserToOrgWithRole(t, db, admin, org, auth.RoleAdmin)
+	addUserToOrgWithRole(t, db, member, org, auth.RoleMember)
+	addUserToOrgWithRole(t, db, viewer, org, auth.RoleViewer)
+	addUserToOrgWithRole(t, db, unknown, org, "unknown")
+
+	tests := []struct {
+		user           *auth.User
+		path           string
+		method         string
+		expectedResult bool
+	}{
+		{user: admin, path: "/api/v1/orgs/1", method: http.MethodDelete, expectedResult: true},
+		{user: admin, path: "/api/v1/orgs/1/users/2", method: http.MethodPut, expectedResult: true},
+		{user: admin, path: "/api/v1/orgs/1/clusters/1/config", method: http.MethodGet, expectedResult: true},
+		{user: admin, path: "/api/v1/orgs/1/audit", method: http.MethodGet, expectedResult: true},
+
+		{user: member, path: "/api/v1/orgs/1", method: http.MethodGet, expectedResult: true},
+		{user: member, path: "/api/v1/orgs/1", method: http.MethodDelete, expectedResult: false},
+		{user: member, path: "/api/v1/orgs/1/users", method: http.MethodGet, expectedResult: true},
+		{user: member, path: "/api/v1/orgs/1/users/3", method: http.MethodPost, expectedResult: false},
+		{user: member, path: "/api/v1/orgs/1/users/3", method: http.MethodDelete, expectedResult: false},
+		{user: member, path: "/api/v1/orgs/1/clusters/1", method: http.MethodDelete, expectedResult: true},
+		{user: member, path: "/api/v1/orgs/1/audit", method: http.MethodGet, expectedResult: false},
+
+		{user: viewer, path: "/api/v1/orgs/1/clusters", method: http.MethodGet, expectedResult: true},
+		{user: viewer, path: "/api/v1/orgs/1/clusters/1", method: http.MethodHead, expectedResult: true},
+		{user: viewer, path: "/dashboard/orgs/1/clusters", method: http.MethodGet, expectedResult: true},
+		{user: viewer, path: "/api/v1/orgs/1/clusters/1", method: http.MethodDelete, expectedResult: false},
+		{user: viewer, path: "/api/v1/orgs/1/clusters", method: http.MethodPost, expectedResult: false},
+		{user: viewer, path: "/api/v1/orgs/1/clusters/1/config", method: http.MethodGet, expectedResult: false},
+		{user: viewer, path: "/api/v1/orgs/1/clusters/1/proxy/api/v1/pods", method: http.MethodGet, expectedResult: false},
+		{user: viewer, path: "/api/v1/orgs/1/secrets", method: http.MethodGet, expectedResult: false},
+		{user: viewer, path: "/api/v1/orgs/1/secrets/abc", method: http.MethodGet, expectedResult: false},
+		{user: viewer, path: "/api/v1/orgs/1/clusters/1/secrets", method: http.MethodGet, expectedResult: false},
+
+		{user: unknown, path: "/api/v1/orgs/1/clusters", method: http.MethodGet, expectedResult: false},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.user.Login+" "+test.method+" "+test.path, func(t *testing.T) {
+			granted, err := enforcer.Enforce(org, test.user, test.path, test.method)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+
+			assert.Equal(t, test.expectedResult, granted)
+		})
+	}
+}
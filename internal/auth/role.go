@@ -0,0 +1,157 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/banzaicloud/pipeline/auth"
+)
+
+// pathRule matches organization relative paths (eg. "clusters/1/config" for "/api/v1/orgs/1/clusters/1/config").
+// Rule paths are matched segment by segment, where "*" matches any single segment.
+type pathRule struct {
+	// path is the organization relative path prefix of the rule
+	path string
+
+	// exact restricts the rule to the path itself (and not the resources under it)
+	exact bool
+
+	// methods is the list of HTTP methods the rule applies to (empty means every method)
+	methods []string
+}
+
+// rolePolicy describes what a role is allowed to do within an organization.
+type rolePolicy struct {
+	// readOnly restricts the role to safe HTTP methods
+	readOnly bool
+
+	// denied lists the paths the role cannot access
+	denied []pathRule
+}
+
+// nolint: gochecknoglobals
+var mutatingMethods = []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// rolePolicies contains the policies of the known organization roles.
+// nolint: gochecknoglobals
+var rolePolicies = map[string]rolePolicy{
+	auth.RoleAdmin: {},
+	auth.RoleMember: {
+		denied: []pathRule{
+			{path: "", exact: true, methods: []string{http.MethodDelete}},
+			{path: "users", methods: mutatingMethods},
+			{path: "audit"},
+		},
+	},
+	auth.RoleViewer: {
+		readOnly: true,
+		denied: []pathRule{
+			{path: "audit"},
+			// secret listings can return plaintext values (?values=true), the enforcer does not see the query
+			{path: "secrets"},
+			{path: "clusters/*/secrets"},
+			{path: "clusters/*/config"},
+			{path: "clusters/*/proxy"},
+		},
+	},
+}
+
+// roleGrants checks whether a role grants access to an organization resource under path with method.
+func roleGrants(role string, path string, method string) bool {
+	// The database defaults to the admin role, legacy records might not have it set explicitly
+	if role == "" {
+		role = auth.RoleAdmin
+	}
+
+	policy, ok := rolePolicies[role]
+	if !ok {
+		return false
+	}
+
+	if policy.readOnly && !isSafeMethod(method) {
+		return false
+	}
+
+	segments := orgRelativePathSegments(path)
+
+	for _, rule := range policy.denied {
+		if rule.matches(segments, method) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (r pathRule) matches(segments []string, method string) bool {
+	if len(r.methods) > 0 && !containsMethod(r.methods, method) {
+		return false
+	}
+
+	var ruleSegments []string
+	if r.path != "" {
+		ruleSegments = strings.Split(r.path, "/")
+	}
+
+	if len(segments) < len(ruleSegments) || (r.exact && len(segments) != len(ruleSegments)) {
+		return false
+	}
+
+	for i, ruleSegment := range ruleSegments {
+		if ruleSegment != "*" && ruleSegment != segments[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// orgRelativePathSegments returns the path segments following the organization ID.
+func orgRelativePathSegments(path string) []string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	for i, segment := range segments {
+		if segment == "orgs" && i+1 < len(segments) {
+			segments = segments[i+2:]
+
+			break
+		}
+	}
+
+	var result []string
+	for _, segment := range segments {
+		if segment != "" {
+			result = append(result, segment)
+		}
+	}
+
+	return result
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+func containsMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
@@ -115,3 +115,8 @@ func GetMachineDetails(logger logrus.FieldLogger, cloud string, service string,
 
 	return &vmDetails, nil
 }
+
+// GetProductDetails returns the details of an instance type, including its prices, either from local cache or CloudInfo
+func (c *Client) GetProductDetails(cloud string, service string, region string, instanceType string) (*cloudinfo.ProductDetails, error) {
+	return GetMachineDetails(c.logger, cloud, service, region, instanceType)
+}
@@ -0,0 +1,163 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterclone_test
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/emperror"
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterclone"
+	"github.com/banzaicloud/pipeline/internal/clusterfeature"
+)
+
+func newManager(t *testing.T) *clusterclone.Manager {
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+
+	db.DB().SetMaxOpenConns(1)
+
+	logger, _ := test.NewNullLogger()
+	require.NoError(t, clusterclone.Migrate(db, logger))
+
+	return clusterclone.NewManager(db)
+}
+
+func TestManager(t *testing.T) {
+	manager := newManager(t)
+
+	_, err := manager.GetTask(2)
+	assert.True(t, errors.As(err, &clusterclone.TaskNotFoundError{}))
+
+	_, err = manager.CreateTask(1, 2, true, false, 1)
+	require.NoError(t, err)
+
+	task, err := manager.GetTask(2)
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), task.SourceClusterID)
+	assert.True(t, task.CopyReleases)
+	assert.False(t, task.CopyFeatures)
+	assert.Equal(t, clusterclone.StatusPending, task.Status)
+
+	require.NoError(t, manager.RecordResult(2, errors.New("failed to copy releases")))
+
+	task, err = manager.GetTask(2)
+	require.NoError(t, err)
+	assert.Equal(t, clusterclone.StatusFailed, task.Status)
+	assert.Equal(t, "failed to copy releases", task.StatusMessage)
+}
+
+type clusterGetterStub map[uint]cluster.CommonCluster
+
+func (s clusterGetterStub) GetClusterByIDOnly(ctx context.Context, clusterID uint) (cluster.CommonCluster, error) {
+	c, ok := s[clusterID]
+	if !ok {
+		return nil, errors.New("cluster not found")
+	}
+
+	return c, nil
+}
+
+type featureServiceStub struct {
+	features  []clusterfeature.Feature
+	activated map[uint][]string
+}
+
+func (s *featureServiceStub) List(ctx context.Context, clusterID uint) ([]clusterfeature.Feature, error) {
+	return s.features, nil
+}
+
+func (s *featureServiceStub) Activate(ctx context.Context, clusterID uint, featureName string, spec clusterfeature.FeatureSpec) error {
+	s.activated[clusterID] = append(s.activated[clusterID], featureName)
+
+	return nil
+}
+
+type releaseCopierStub struct {
+	copied int
+	err    error
+}
+
+func (s *releaseCopierStub) CopyReleases(source cluster.CommonCluster, target cluster.CommonCluster) ([]string, error) {
+	s.copied++
+
+	return nil, s.err
+}
+
+func TestRunner_ClusterCreated(t *testing.T) {
+	manager := newManager(t)
+	logger, _ := test.NewNullLogger()
+
+	clusters := clusterGetterStub{
+		1: &cluster.DummyCluster{},
+		2: &cluster.DummyCluster{},
+		3: &cluster.DummyCluster{},
+	}
+	features := &featureServiceStub{
+		features: []clusterfeature.Feature{
+			{Name: "dns", Status: clusterfeature.FeatureStatusActive},
+			{Name: "vault", Status: clusterfeature.FeatureStatusPending},
+		},
+		activated: make(map[uint][]string),
+	}
+	releases := &releaseCopierStub{}
+
+	runner := clusterclone.NewRunner(manager, clusters, features, releases, logger, emperror.NewNoopHandler())
+
+	t.Run("not a clone", func(t *testing.T) {
+		runner.ClusterCreated(1)
+
+		assert.Equal(t, 0, releases.copied)
+		assert.Empty(t, features.activated)
+	})
+
+	t.Run("clone", func(t *testing.T) {
+		_, err := manager.CreateTask(1, 2, true, true, 1)
+		require.NoError(t, err)
+
+		runner.ClusterCreated(2)
+
+		assert.Equal(t, 1, releases.copied)
+		assert.Equal(t, []string{"dns"}, features.activated[2])
+
+		task, err := manager.GetTask(2)
+		require.NoError(t, err)
+		assert.Equal(t, clusterclone.StatusSucceeded, task.Status)
+	})
+
+	t.Run("failed copy", func(t *testing.T) {
+		releases.err = errors.New("install failed")
+
+		_, err := manager.CreateTask(1, 3, true, true, 1)
+		require.NoError(t, err)
+
+		runner.ClusterCreated(3)
+
+		assert.Empty(t, features.activated[3])
+
+		task, err := manager.GetTask(3)
+		require.NoError(t, err)
+		assert.Equal(t, clusterclone.StatusFailed, task.Status)
+		assert.Contains(t, task.StatusMessage, "install failed")
+	})
+}
@@ -0,0 +1,81 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterclone
+
+import (
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+)
+
+// Manager manages the clone tasks of clusters.
+type Manager struct {
+	db *gorm.DB
+}
+
+// NewManager returns a new Manager.
+func NewManager(db *gorm.DB) *Manager {
+	return &Manager{
+		db: db,
+	}
+}
+
+// CreateTask records what has to be copied to a cloned cluster once it is created.
+func (m *Manager) CreateTask(sourceClusterID uint, targetClusterID uint, copyReleases bool, copyFeatures bool, userID uint) (*Task, error) {
+	task := &Task{
+		SourceClusterID: sourceClusterID,
+		TargetClusterID: targetClusterID,
+		CopyReleases:    copyReleases,
+		CopyFeatures:    copyFeatures,
+		Status:          StatusPending,
+		CreatedBy:       userID,
+	}
+
+	if err := m.db.Create(task).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to save clone task")
+	}
+
+	return task, nil
+}
+
+// GetTask returns the clone task of a cloned cluster.
+func (m *Manager) GetTask(targetClusterID uint) (*Task, error) {
+	var task Task
+
+	err := m.db.Where(&Task{TargetClusterID: targetClusterID}).First(&task).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, TaskNotFoundError{TargetClusterID: targetClusterID}
+	} else if err != nil {
+		return nil, errors.WrapIf(err, "failed to get clone task")
+	}
+
+	return &task, nil
+}
+
+// RecordResult records the outcome of a clone task.
+func (m *Manager) RecordResult(targetClusterID uint, taskErr error) error {
+	fields := map[string]interface{}{
+		"status":         StatusSucceeded,
+		"status_message": "",
+	}
+
+	if taskErr != nil {
+		fields["status"] = StatusFailed
+		fields["status_message"] = taskErr.Error()
+	}
+
+	err := m.db.Model(&Task{}).Where(&Task{TargetClusterID: targetClusterID}).Updates(fields).Error
+
+	return errors.WrapIf(err, "failed to record clone task result")
+}
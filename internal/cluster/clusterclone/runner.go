@@ -0,0 +1,185 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterclone
+
+import (
+	"context"
+
+	"emperror.dev/emperror"
+	"emperror.dev/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/cluster"
+	"github.com/banzaicloud/pipeline/helm"
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/internal/clusterfeature"
+)
+
+const clusterCreatedTopic = "cluster_created"
+
+type eventBus interface {
+	SubscribeAsync(topic string, fn interface{}, transactional bool) error
+}
+
+// ClusterGetter returns clusters.
+type ClusterGetter interface {
+	GetClusterByIDOnly(ctx context.Context, clusterID uint) (cluster.CommonCluster, error)
+}
+
+// FeatureService manages cluster features.
+type FeatureService interface {
+	List(ctx context.Context, clusterID uint) ([]clusterfeature.Feature, error)
+	Activate(ctx context.Context, clusterID uint, featureName string, spec clusterfeature.FeatureSpec) error
+}
+
+// ReleaseCopier installs the Helm releases of a cluster on another one.
+type ReleaseCopier interface {
+	CopyReleases(source cluster.CommonCluster, target cluster.CommonCluster) ([]string, error)
+}
+
+// Runner copies the releases and features of the source cluster to its clone once the clone is created.
+type Runner struct {
+	tasks    *Manager
+	clusters ClusterGetter
+	features FeatureService
+	releases ReleaseCopier
+
+	logger       logrus.FieldLogger
+	errorHandler emperror.Handler
+}
+
+// NewRunner returns a new Runner.
+func NewRunner(
+	tasks *Manager,
+	clusters ClusterGetter,
+	features FeatureService,
+	releases ReleaseCopier,
+	logger logrus.FieldLogger,
+	errorHandler emperror.Handler,
+) *Runner {
+	return &Runner{
+		tasks:        tasks,
+		clusters:     clusters,
+		features:     features,
+		releases:     releases,
+		logger:       logger,
+		errorHandler: errorHandler,
+	}
+}
+
+// Register subscribes to the cluster created events of the event bus.
+func (r *Runner) Register(eb eventBus) error {
+	err := eb.SubscribeAsync(clusterCreatedTopic, r.ClusterCreated, false)
+
+	return errors.WrapIfWithDetails(err, "failed to subscribe to cluster events", "topic", clusterCreatedTopic)
+}
+
+// ClusterCreated runs the clone task of a newly created cluster (if there is any).
+func (r *Runner) ClusterCreated(clusterID uint) {
+	task, err := r.tasks.GetTask(clusterID)
+	if errors.As(err, &TaskNotFoundError{}) {
+		return
+	} else if err != nil {
+		r.errorHandler.Handle(err)
+
+		return
+	}
+
+	err = r.run(context.Background(), *task)
+	if err != nil {
+		r.errorHandler.Handle(err)
+	}
+
+	if err := r.tasks.RecordResult(clusterID, err); err != nil {
+		r.errorHandler.Handle(err)
+	}
+}
+
+func (r *Runner) run(ctx context.Context, task Task) error {
+	logger := r.logger.WithFields(logrus.Fields{
+		"sourceClusterID": task.SourceClusterID,
+		"clusterID":       task.TargetClusterID,
+	})
+
+	source, err := r.clusters.GetClusterByIDOnly(ctx, task.SourceClusterID)
+	if intCluster.IsClusterNotFoundError(err) {
+		return errors.New("source cluster is deleted")
+	} else if err != nil {
+		return emperror.WrapWith(err, "failed to retrieve source cluster", "clusterID", task.SourceClusterID)
+	}
+
+	target, err := r.clusters.GetClusterByIDOnly(ctx, task.TargetClusterID)
+	if err != nil {
+		return emperror.WrapWith(err, "failed to retrieve cluster", "clusterID", task.TargetClusterID)
+	}
+
+	if task.CopyReleases {
+		logger.Info("copying releases of the source cluster")
+
+		copied, err := r.releases.CopyReleases(source, target)
+		if err != nil {
+			return emperror.WrapWith(err, "failed to copy releases", "clusterID", task.TargetClusterID)
+		}
+
+		logger.WithField("releases", copied).Info("releases copied")
+	}
+
+	if task.CopyFeatures {
+		logger.Info("activating features of the source cluster")
+
+		features, err := r.features.List(ctx, task.SourceClusterID)
+		if err != nil {
+			return emperror.WrapWith(err, "failed to list features of the source cluster", "clusterID", task.SourceClusterID)
+		}
+
+		for _, feature := range features {
+			if feature.Status != clusterfeature.FeatureStatusActive {
+				continue
+			}
+
+			if err := r.features.Activate(ctx, task.TargetClusterID, feature.Name, feature.Spec); err != nil {
+				return emperror.WrapWith(err, "failed to activate feature", "clusterID", task.TargetClusterID, "feature", feature.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+type helmReleaseCopier struct {
+	skipNamespaces []string
+}
+
+// NewHelmReleaseCopier returns a ReleaseCopier which installs the deployed releases of the source cluster
+// with the same charts and values, leaving out the ones in the skipped (eg. system) namespaces.
+func NewHelmReleaseCopier(skipNamespaces ...string) ReleaseCopier {
+	return helmReleaseCopier{
+		skipNamespaces: skipNamespaces,
+	}
+}
+
+func (c helmReleaseCopier) CopyReleases(source cluster.CommonCluster, target cluster.CommonCluster) ([]string, error) {
+	sourceKubeConfig, err := source.GetK8sConfig()
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get kubeconfig of the source cluster")
+	}
+
+	targetKubeConfig, err := target.GetK8sConfig()
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get kubeconfig of the target cluster")
+	}
+
+	return helm.CopyDeployments(sourceKubeConfig, targetKubeConfig, c.skipNamespaces...)
+}
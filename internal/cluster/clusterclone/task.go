@@ -0,0 +1,73 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterclone
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+)
+
+// Task statuses
+const (
+	StatusPending   = "PENDING"
+	StatusSucceeded = "SUCCEEDED"
+	StatusFailed    = "FAILED"
+)
+
+// Task records what has to be copied from the source cluster to its clone once the clone is created.
+type Task struct {
+	ID              uint `gorm:"primary_key"`
+	SourceClusterID uint `gorm:"not null"`
+	TargetClusterID uint `gorm:"unique_index:idx_cluster_clone_tasks_target_cluster_id;not null"`
+
+	// CopyReleases requests the Helm releases of the source cluster to be installed on the clone.
+	CopyReleases bool `gorm:"not null;default:false"`
+	// CopyFeatures requests the active features of the source cluster to be activated on the clone.
+	CopyFeatures bool `gorm:"not null;default:false"`
+
+	Status        string `gorm:"not null"`
+	StatusMessage string `gorm:"type:text"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	CreatedBy uint
+}
+
+// TableName changes the default table name.
+func (Task) TableName() string {
+	return "cluster_clone_tasks"
+}
+
+// Migrate executes the table migrations for the cluster clone tasks.
+func Migrate(db *gorm.DB, logger logrus.FieldLogger) error {
+	logger.WithFields(logrus.Fields{
+		"table_names": Task{}.TableName(),
+	}).Info("migrating cluster clone tables")
+
+	return db.AutoMigrate(&Task{}).Error
+}
+
+// TaskNotFoundError is returned when a cluster is not a clone with pending copy tasks.
+type TaskNotFoundError struct {
+	TargetClusterID uint
+}
+
+// Error implements the error interface.
+func (e TaskNotFoundError) Error() string {
+	return fmt.Sprintf("cluster %d has no clone task", e.TargetClusterID)
+}
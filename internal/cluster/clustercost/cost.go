@@ -0,0 +1,244 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustercost
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/.gen/cloudinfo"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// HoursPerMonth is the average number of hours in a month used for monthly figures.
+const HoursPerMonth = 730
+
+// Currency of the prices returned by CloudInfo.
+const Currency = "USD"
+
+// PriceSource returns the details (including the prices) of an instance type.
+type PriceSource interface {
+	GetProductDetails(cloud string, service string, region string, instanceType string) (*cloudinfo.ProductDetails, error)
+}
+
+// NodePoolCost describes the cost of a node pool.
+type NodePoolCost struct {
+	Name          string  `json:"name"`
+	InstanceType  string  `json:"instanceType"`
+	Count         int     `json:"count"`
+	SpotCount     int     `json:"spotCount"`
+	OnDemandPrice float64 `json:"onDemandPrice"`
+	SpotPrice     float64 `json:"spotPrice,omitempty"`
+	HourlyCost    float64 `json:"hourlyCost"`
+	MonthlyCost   float64 `json:"monthlyCost"`
+	Error         string  `json:"error,omitempty"`
+}
+
+// Cost describes the cost of a cluster.
+// Incomplete is set when the price of some node pools is unknown, so they are missing from the totals.
+type Cost struct {
+	Currency           string         `json:"currency"`
+	HourlyCost         float64        `json:"hourlyCost"`
+	MonthlyCost        float64        `json:"monthlyCost"`
+	OnDemandHourlyCost float64        `json:"onDemandHourlyCost"`
+	SpotHourlyCost     float64        `json:"spotHourlyCost"`
+	Incomplete         bool           `json:"incomplete,omitempty"`
+	NodePools          []NodePoolCost `json:"nodePools"`
+}
+
+// Summary describes the total cost of several clusters.
+type Summary struct {
+	Currency           string  `json:"currency"`
+	Clusters           int     `json:"clusters"`
+	HourlyCost         float64 `json:"hourlyCost"`
+	MonthlyCost        float64 `json:"monthlyCost"`
+	OnDemandHourlyCost float64 `json:"onDemandHourlyCost"`
+	SpotHourlyCost     float64 `json:"spotHourlyCost"`
+	Incomplete         bool    `json:"incomplete,omitempty"`
+}
+
+// Estimator calculates the cost of clusters from instance prices.
+type Estimator struct {
+	prices PriceSource
+}
+
+// NewEstimator returns a new Estimator.
+func NewEstimator(prices PriceSource) *Estimator {
+	return &Estimator{
+		prices: prices,
+	}
+}
+
+// Estimate returns the hourly and monthly cost of the node pools of a cluster.
+//
+// Node pools with a spot price, preemptible node pools and the ones labeled as not on-demand run on spot instances.
+// When the cluster has scale options enabled, OnDemandPct percent of the nodes of these node pools
+// are counted as on-demand instances.
+func (e *Estimator) Estimate(status *pkgCluster.GetClusterStatusResponse, scaleOptions *pkgCluster.ScaleOptions) *Cost {
+	cost := &Cost{
+		Currency:  Currency,
+		NodePools: make([]NodePoolCost, 0, len(status.NodePools)),
+	}
+
+	names := make([]string, 0, len(status.NodePools))
+	for name := range status.NodePools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	onDemandPct := 0
+	if scaleOptions != nil && scaleOptions.Enabled {
+		onDemandPct = scaleOptions.OnDemandPct
+	}
+
+	for _, name := range names {
+		nodePool := status.NodePools[name]
+
+		nodePoolCost := NodePoolCost{
+			Name:         name,
+			InstanceType: nodePool.InstanceType,
+			Count:        nodePool.Count,
+		}
+
+		if isSpot(nodePool) {
+			onDemandCount := int(math.Ceil(float64(nodePool.Count) * float64(onDemandPct) / 100))
+			nodePoolCost.SpotCount = nodePool.Count - onDemandCount
+		}
+
+		err := e.priceNodePool(status, nodePool, &nodePoolCost)
+		if err != nil {
+			nodePoolCost.Error = err.Error()
+			cost.Incomplete = true
+		}
+
+		onDemandCost := float64(nodePoolCost.Count-nodePoolCost.SpotCount) * nodePoolCost.OnDemandPrice
+		spotCost := float64(nodePoolCost.SpotCount) * nodePoolCost.SpotPrice
+
+		nodePoolCost.HourlyCost = round(onDemandCost + spotCost)
+		nodePoolCost.MonthlyCost = round((onDemandCost + spotCost) * HoursPerMonth)
+
+		cost.OnDemandHourlyCost += onDemandCost
+		cost.SpotHourlyCost += spotCost
+		cost.NodePools = append(cost.NodePools, nodePoolCost)
+	}
+
+	cost.HourlyCost = round(cost.OnDemandHourlyCost + cost.SpotHourlyCost)
+	cost.MonthlyCost = round((cost.OnDemandHourlyCost + cost.SpotHourlyCost) * HoursPerMonth)
+	cost.OnDemandHourlyCost = round(cost.OnDemandHourlyCost)
+	cost.SpotHourlyCost = round(cost.SpotHourlyCost)
+
+	return cost
+}
+
+func (e *Estimator) priceNodePool(status *pkgCluster.GetClusterStatusResponse, nodePool *pkgCluster.NodePoolStatus, cost *NodePoolCost) error {
+	if nodePool.Count == 0 {
+		return nil
+	}
+
+	details, err := e.prices.GetProductDetails(status.Cloud, status.Distribution, status.Region, nodePool.InstanceType)
+	if err != nil {
+		cost.SpotCount = 0
+
+		return errors.WrapIf(err, "failed to get instance type price")
+	}
+
+	if details == nil || details.OnDemandPrice == 0 {
+		cost.SpotCount = 0
+
+		return errors.Errorf("no price information for instance type %q", nodePool.InstanceType)
+	}
+
+	cost.OnDemandPrice = details.OnDemandPrice
+
+	if cost.SpotCount > 0 {
+		cost.SpotPrice = spotPrice(details, nodePool)
+	}
+
+	return nil
+}
+
+// Summarize returns the total cost of several clusters.
+func Summarize(costs []*Cost) *Summary {
+	summary := &Summary{
+		Currency: Currency,
+	}
+
+	for _, cost := range costs {
+		if cost == nil {
+			summary.Incomplete = true
+			continue
+		}
+
+		summary.Clusters++
+		summary.HourlyCost += cost.HourlyCost
+		summary.MonthlyCost += cost.MonthlyCost
+		summary.OnDemandHourlyCost += cost.OnDemandHourlyCost
+		summary.SpotHourlyCost += cost.SpotHourlyCost
+		summary.Incomplete = summary.Incomplete || cost.Incomplete
+	}
+
+	summary.HourlyCost = round(summary.HourlyCost)
+	summary.MonthlyCost = round(summary.MonthlyCost)
+	summary.OnDemandHourlyCost = round(summary.OnDemandHourlyCost)
+	summary.SpotHourlyCost = round(summary.SpotHourlyCost)
+
+	return summary
+}
+
+// isSpot returns whether a node pool runs on spot (or preemptible) instances.
+// The on-demand node label takes precedence over the node pool settings.
+func isSpot(nodePool *pkgCluster.NodePoolStatus) bool {
+	if onDemand, err := strconv.ParseBool(nodePool.Labels[pkgCommon.OnDemandLabelKey]); err == nil {
+		return !onDemand
+	}
+
+	if p, err := strconv.ParseFloat(nodePool.SpotPrice, 64); err == nil && p > 0.0 {
+		return true
+	}
+
+	return nodePool.Preemptible
+}
+
+// spotPrice returns the average spot price of an instance type across the zones.
+// It falls back to the maximum bid of the node pool and then to the on-demand price.
+func spotPrice(details *cloudinfo.ProductDetails, nodePool *pkgCluster.NodePoolStatus) float64 {
+	var sum float64
+	var zones int
+
+	for _, zonePrice := range details.SpotPrice {
+		if zonePrice.Price > 0 {
+			sum += zonePrice.Price
+			zones++
+		}
+	}
+
+	if zones > 0 {
+		return sum / float64(zones)
+	}
+
+	if p, err := strconv.ParseFloat(nodePool.SpotPrice, 64); err == nil && p > 0.0 {
+		return math.Min(p, details.OnDemandPrice)
+	}
+
+	return details.OnDemandPrice
+}
+
+func round(value float64) float64 {
+	return math.Round(value*10000) / 10000
+}
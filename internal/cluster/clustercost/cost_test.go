@@ -0,0 +1,117 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustercost
+
+import (
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/.gen/cloudinfo"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+)
+
+type priceSourceStub map[string]cloudinfo.ProductDetails
+
+func (s priceSourceStub) GetProductDetails(cloud string, service string, region string, instanceType string) (*cloudinfo.ProductDetails, error) {
+	details, ok := s[instanceType]
+	if !ok {
+		return nil, errors.New("unknown instance type")
+	}
+
+	return &details, nil
+}
+
+func TestEstimator_Estimate(t *testing.T) {
+	estimator := NewEstimator(priceSourceStub{
+		"m5.large": {
+			OnDemandPrice: 0.1,
+			SpotPrice:     []cloudinfo.ZonePrice{{Zone: "a", Price: 0.03}, {Zone: "b", Price: 0.05}},
+		},
+		"c5.large": {
+			OnDemandPrice: 0.2,
+		},
+	})
+
+	status := &pkgCluster.GetClusterStatusResponse{
+		Cloud:        pkgCluster.Amazon,
+		Distribution: pkgCluster.EKS,
+		Region:       "eu-west-1",
+		NodePools: map[string]*pkgCluster.NodePoolStatus{
+			"ondemand": {InstanceType: "m5.large", Count: 2},
+			"spot":     {InstanceType: "m5.large", Count: 4, SpotPrice: "0.08"},
+			"bid":      {InstanceType: "c5.large", Count: 1, SpotPrice: "0.15"},
+			"labeled":  {InstanceType: "m5.large", Count: 1, SpotPrice: "0.08", Labels: map[string]string{pkgCommon.OnDemandLabelKey: "true"}},
+			"unknown":  {InstanceType: "x1.huge", Count: 1},
+		},
+	}
+
+	t.Run("WithoutScaleOptions", func(t *testing.T) {
+		cost := estimator.Estimate(status, nil)
+
+		require.Len(t, cost.NodePools, 5)
+		assert.Equal(t, "bid", cost.NodePools[0].Name)
+
+		nodePools := make(map[string]NodePoolCost)
+		for _, nodePool := range cost.NodePools {
+			nodePools[nodePool.Name] = nodePool
+		}
+
+		assert.Equal(t, 0.2, nodePools["ondemand"].HourlyCost)
+		assert.Equal(t, 0, nodePools["ondemand"].SpotCount)
+		assert.Equal(t, 4, nodePools["spot"].SpotCount)
+		assert.Equal(t, 0.16, nodePools["spot"].HourlyCost)
+		assert.Equal(t, 0.15, nodePools["bid"].SpotPrice)
+		assert.Equal(t, 0.1, nodePools["labeled"].HourlyCost)
+		assert.NotEmpty(t, nodePools["unknown"].Error)
+
+		assert.True(t, cost.Incomplete)
+		assert.Equal(t, 0.3, cost.OnDemandHourlyCost)
+		assert.Equal(t, 0.31, cost.SpotHourlyCost)
+		assert.Equal(t, 0.61, cost.HourlyCost)
+		assert.Equal(t, 445.3, cost.MonthlyCost)
+	})
+
+	t.Run("WithOnDemandPct", func(t *testing.T) {
+		cost := estimator.Estimate(status, &pkgCluster.ScaleOptions{Enabled: true, OnDemandPct: 50})
+
+		for _, nodePool := range cost.NodePools {
+			if nodePool.Name == "spot" {
+				assert.Equal(t, 2, nodePool.SpotCount)
+				assert.Equal(t, 0.28, nodePool.HourlyCost)
+			}
+		}
+	})
+}
+
+func TestSummarize(t *testing.T) {
+	summary := Summarize([]*Cost{
+		{HourlyCost: 1, MonthlyCost: 730, OnDemandHourlyCost: 1},
+		{HourlyCost: 0.5, MonthlyCost: 365, SpotHourlyCost: 0.5, Incomplete: true},
+	})
+
+	assert.Equal(t, &Summary{
+		Currency:           Currency,
+		Clusters:           2,
+		HourlyCost:         1.5,
+		MonthlyCost:        1095,
+		OnDemandHourlyCost: 1,
+		SpotHourlyCost:     0.5,
+		Incomplete:         true,
+	}, summary)
+}
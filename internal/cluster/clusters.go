@@ -162,6 +162,31 @@ func (c *Clusters) FindBySecret(organizationID uint, secretID string) ([]*model.
 	return clusters, nil
 }
 
+// FindStatusHistory returns the status transitions of a cluster in chronological order.
+// The history of deleted clusters is returned as well.
+func (c *Clusters) FindStatusHistory(organizationID uint, clusterID uint) ([]StatusHistoryModel, error) {
+	var cluster model.ClusterModel
+
+	err := c.db.Unscoped().Where(model.ClusterModel{OrganizationId: organizationID, ID: clusterID}).First(&cluster).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, errors.WithStack(&clusterModelNotFoundError{
+			cluster: model.ClusterModel{OrganizationId: organizationID, ID: clusterID},
+		})
+	}
+	if err != nil {
+		return nil, emperror.With(err, "clusterID", clusterID, "organizationID", organizationID)
+	}
+
+	var history []StatusHistoryModel
+
+	err = c.db.Where(StatusHistoryModel{ClusterID: clusterID}).Order("created_at ASC, id ASC").Find(&history).Error
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not fetch cluster status history", "clusterID", clusterID)
+	}
+
+	return history, nil
+}
+
 // GetConfigSecretIDByClusterID returns the kubeconfig's secretID stored in DB
 func (c *Clusters) GetConfigSecretIDByClusterID(organizationID uint, clusterID uint) (string, error) {
 	cluster := model.ClusterModel{ID: clusterID}
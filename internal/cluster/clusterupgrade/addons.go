@@ -0,0 +1,106 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterupgrade
+
+import (
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/Masterminds/semver"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// UpdateDaemonSetImage updates the image tag of a DaemonSet container to the given version unless it already runs a newer one.
+// It returns false if the DaemonSet does not exist.
+func UpdateDaemonSetImage(client kubernetes.Interface, namespace string, name string, container string, version string) (bool, error) {
+	daemonSet, err := client.AppsV1().DaemonSets(namespace).Get(name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.WrapIfWithDetails(err, "failed to get daemon set", "namespace", namespace, "daemonSet", name)
+	}
+
+	if !updateContainerImage(daemonSet.Spec.Template.Spec.Containers, container, version) {
+		return true, nil
+	}
+
+	_, err = client.AppsV1().DaemonSets(namespace).Update(daemonSet)
+
+	return true, errors.WrapIfWithDetails(err, "failed to update daemon set", "namespace", namespace, "daemonSet", name)
+}
+
+// UpdateDeploymentImage updates the image tag of a Deployment container to the given version unless it already runs a newer one.
+// It returns false if the Deployment does not exist.
+func UpdateDeploymentImage(client kubernetes.Interface, namespace string, name string, container string, version string) (bool, error) {
+	deployment, err := client.AppsV1().Deployments(namespace).Get(name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.WrapIfWithDetails(err, "failed to get deployment", "namespace", namespace, "deployment", name)
+	}
+
+	if !updateContainerImage(deployment.Spec.Template.Spec.Containers, container, version) {
+		return true, nil
+	}
+
+	_, err = client.AppsV1().Deployments(namespace).Update(deployment)
+
+	return true, errors.WrapIfWithDetails(err, "failed to update deployment", "namespace", namespace, "deployment", name)
+}
+
+func updateContainerImage(containers []corev1.Container, name string, version string) bool {
+	for i := range containers {
+		if containers[i].Name != name {
+			continue
+		}
+
+		image, ok := upgradeImageTag(containers[i].Image, version)
+		if !ok {
+			return false
+		}
+
+		containers[i].Image = image
+
+		return true
+	}
+
+	return false
+}
+
+// upgradeImageTag replaces the tag of an image with the given version (keeping the repository)
+// if the current tag is an older version.
+func upgradeImageTag(image string, version string) (string, bool) {
+	repository, tag := image, ""
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		repository, tag = image[:i], image[i+1:]
+	}
+
+	targetVersion, err := semver.NewVersion(version)
+	if err != nil {
+		return image, false
+	}
+
+	// tags like v1.13.7-eksbuild.1 are compared by their version part
+	if currentVersion, err := semver.NewVersion(strings.SplitN(tag, "-", 2)[0]); err == nil && !currentVersion.LessThan(targetVersion) {
+		return image, false
+	}
+
+	return repository + ":v" + strings.TrimPrefix(version, "v"), true
+}
@@ -0,0 +1,94 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterupgrade_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterupgrade"
+)
+
+func TestUpdateDaemonSetImage(t *testing.T) {
+	newDaemonSet := func(name string, image string) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kube-system"},
+			Spec: appsv1.DaemonSetSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: name, Image: image}},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		image         string
+		version       string
+		expectedImage string
+	}{
+		{
+			name:          "older version",
+			image:         "602401143452.dkr.ecr.us-west-2.amazonaws.com/eks/kube-proxy:v1.12.6",
+			version:       "1.13.7",
+			expectedImage: "602401143452.dkr.ecr.us-west-2.amazonaws.com/eks/kube-proxy:v1.13.7",
+		},
+		{
+			name:          "newer version",
+			image:         "602401143452.dkr.ecr.us-west-2.amazonaws.com/amazon-k8s-cni:v1.5.4",
+			version:       "1.5.3",
+			expectedImage: "602401143452.dkr.ecr.us-west-2.amazonaws.com/amazon-k8s-cni:v1.5.4",
+		},
+		{
+			name:          "registry with port",
+			image:         "registry:5000/kube-proxy:v1.12.6-eksbuild.1",
+			version:       "1.13.7",
+			expectedImage: "registry:5000/kube-proxy:v1.13.7",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(newDaemonSet("kube-proxy", test.image))
+
+			found, err := clusterupgrade.UpdateDaemonSetImage(client, "kube-system", "kube-proxy", "kube-proxy", test.version)
+			require.NoError(t, err)
+			assert.True(t, found)
+
+			daemonSet, err := client.AppsV1().DaemonSets("kube-system").Get("kube-proxy", metav1.GetOptions{})
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedImage, daemonSet.Spec.Template.Spec.Containers[0].Image)
+		})
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		client := fake.NewSimpleClientset()
+
+		found, err := clusterupgrade.UpdateDaemonSetImage(client, "kube-system", "kube-proxy", "kube-proxy", "1.13.7")
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+}
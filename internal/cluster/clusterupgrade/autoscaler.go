@@ -0,0 +1,96 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterupgrade
+
+import (
+	"emperror.dev/errors"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// AutoscalerPauser scales the cluster autoscaler deployments to zero while nodes are replaced,
+// so that the autoscaler does not change the size of the auto scaling groups being rolled.
+type AutoscalerPauser struct {
+	client    kubernetes.Interface
+	namespace string
+	selector  string
+	logger    logrus.FieldLogger
+}
+
+// NewAutoscalerPauser returns a new AutoscalerPauser instance
+// for the deployments matching the label selector in the namespace.
+func NewAutoscalerPauser(client kubernetes.Interface, namespace string, selector string, logger logrus.FieldLogger) *AutoscalerPauser {
+	return &AutoscalerPauser{
+		client:    client,
+		namespace: namespace,
+		selector:  selector,
+		logger:    logger,
+	}
+}
+
+// Pause scales the autoscaler deployments to zero and returns a function restoring their original replica counts.
+func (p *AutoscalerPauser) Pause() (func() error, error) {
+	deployments, err := p.client.AppsV1().Deployments(p.namespace).List(metav1.ListOptions{LabelSelector: p.selector})
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to list cluster autoscaler deployments", "namespace", p.namespace)
+	}
+
+	replicas := make(map[string]int32)
+	resume := func() error {
+		var errs []error
+		for name, count := range replicas {
+			p.logger.WithField("deployment", name).Info("resuming cluster autoscaler")
+
+			errs = append(errs, p.scale(name, count))
+		}
+
+		return errors.Combine(errs...)
+	}
+
+	for _, deployment := range deployments.Items {
+		if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == 0 {
+			continue
+		}
+
+		count := int32(1)
+		if deployment.Spec.Replicas != nil {
+			count = *deployment.Spec.Replicas
+		}
+
+		p.logger.WithField("deployment", deployment.Name).Info("pausing cluster autoscaler")
+
+		if err := p.scale(deployment.Name, 0); err != nil {
+			return nil, errors.Combine(err, resume())
+		}
+
+		replicas[deployment.Name] = count
+	}
+
+	return resume, nil
+}
+
+func (p *AutoscalerPauser) scale(name string, replicas int32) error {
+	deployment, err := p.client.AppsV1().Deployments(p.namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to get cluster autoscaler deployment", "deployment", name)
+	}
+
+	deployment.Spec.Replicas = &replicas
+
+	_, err = p.client.AppsV1().Deployments(p.namespace).Update(deployment)
+
+	return errors.WrapIfWithDetails(err, "failed to scale cluster autoscaler deployment", "deployment", name, "replicas", replicas)
+}
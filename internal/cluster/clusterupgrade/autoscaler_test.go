@@ -0,0 +1,65 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterupgrade_test
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterupgrade"
+)
+
+func TestAutoscalerPauser_Pause(t *testing.T) {
+	replicas := func(count int32) *int32 { return &count }
+
+	client := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "autoscaler", Namespace: "kube-system", Labels: map[string]string{"release": "autoscaler"}},
+			Spec:       appsv1.DeploymentSpec{Replicas: replicas(2)},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "kube-system", Labels: map[string]string{"release": "other"}},
+			Spec:       appsv1.DeploymentSpec{Replicas: replicas(1)},
+		},
+	)
+
+	logger, _ := test.NewNullLogger()
+
+	pauser := clusterupgrade.NewAutoscalerPauser(client, "kube-system", "release=autoscaler", logger)
+
+	getReplicas := func(name string) int32 {
+		deployment, err := client.AppsV1().Deployments("kube-system").Get(name, metav1.GetOptions{})
+		require.NoError(t, err)
+
+		return *deployment.Spec.Replicas
+	}
+
+	resume, err := pauser.Pause()
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(0), getReplicas("autoscaler"))
+	assert.Equal(t, int32(1), getReplicas("other"))
+
+	require.NoError(t, resume())
+
+	assert.Equal(t, int32(2), getReplicas("autoscaler"))
+	assert.Equal(t, int32(1), getReplicas("other"))
+}
@@ -0,0 +1,117 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterupgrade
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/Masterminds/semver"
+	"gopkg.in/yaml.v2"
+)
+
+// RemovedAPI describes a Kubernetes API group version and kind which is no longer served starting from a version.
+type RemovedAPI struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	RemovedIn  string `json:"removedIn"`
+	ReplacedBy string `json:"replacedBy,omitempty"`
+}
+
+// nolint: gochecknoglobals
+var removedAPIs = []RemovedAPI{
+	{APIVersion: "extensions/v1beta1", Kind: "Deployment", RemovedIn: "1.16", ReplacedBy: "apps/v1"},
+	{APIVersion: "extensions/v1beta1", Kind: "DaemonSet", RemovedIn: "1.16", ReplacedBy: "apps/v1"},
+	{APIVersion: "extensions/v1beta1", Kind: "ReplicaSet", RemovedIn: "1.16", ReplacedBy: "apps/v1"},
+	{APIVersion: "extensions/v1beta1", Kind: "NetworkPolicy", RemovedIn: "1.16", ReplacedBy: "networking.k8s.io/v1"},
+	{APIVersion: "extensions/v1beta1", Kind: "PodSecurityPolicy", RemovedIn: "1.16", ReplacedBy: "policy/v1beta1"},
+	{APIVersion: "apps/v1beta1", Kind: "Deployment", RemovedIn: "1.16", ReplacedBy: "apps/v1"},
+	{APIVersion: "apps/v1beta1", Kind: "StatefulSet", RemovedIn: "1.16", ReplacedBy: "apps/v1"},
+	{APIVersion: "apps/v1beta2", Kind: "Deployment", RemovedIn: "1.16", ReplacedBy: "apps/v1"},
+	{APIVersion: "apps/v1beta2", Kind: "StatefulSet", RemovedIn: "1.16", ReplacedBy: "apps/v1"},
+	{APIVersion: "apps/v1beta2", Kind: "DaemonSet", RemovedIn: "1.16", ReplacedBy: "apps/v1"},
+	{APIVersion: "apps/v1beta2", Kind: "ReplicaSet", RemovedIn: "1.16", ReplacedBy: "apps/v1"},
+	{APIVersion: "extensions/v1beta1", Kind: "Ingress", RemovedIn: "1.22", ReplacedBy: "networking.k8s.io/v1"},
+	{APIVersion: "networking.k8s.io/v1beta1", Kind: "Ingress", RemovedIn: "1.22", ReplacedBy: "networking.k8s.io/v1"},
+	{APIVersion: "apiextensions.k8s.io/v1beta1", Kind: "CustomResourceDefinition", RemovedIn: "1.22", ReplacedBy: "apiextensions.k8s.io/v1"},
+	{APIVersion: "admissionregistration.k8s.io/v1beta1", Kind: "MutatingWebhookConfiguration", RemovedIn: "1.22", ReplacedBy: "admissionregistration.k8s.io/v1"},
+	{APIVersion: "admissionregistration.k8s.io/v1beta1", Kind: "ValidatingWebhookConfiguration", RemovedIn: "1.22", ReplacedBy: "admissionregistration.k8s.io/v1"},
+	{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "ClusterRole", RemovedIn: "1.22", ReplacedBy: "rbac.authorization.k8s.io/v1"},
+	{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "ClusterRoleBinding", RemovedIn: "1.22", ReplacedBy: "rbac.authorization.k8s.io/v1"},
+	{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "Role", RemovedIn: "1.22", ReplacedBy: "rbac.authorization.k8s.io/v1"},
+	{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "RoleBinding", RemovedIn: "1.22", ReplacedBy: "rbac.authorization.k8s.io/v1"},
+	{APIVersion: "scheduling.k8s.io/v1beta1", Kind: "PriorityClass", RemovedIn: "1.22", ReplacedBy: "scheduling.k8s.io/v1"},
+	{APIVersion: "storage.k8s.io/v1beta1", Kind: "StorageClass", RemovedIn: "1.22", ReplacedBy: "storage.k8s.io/v1"},
+	{APIVersion: "batch/v1beta1", Kind: "CronJob", RemovedIn: "1.25", ReplacedBy: "batch/v1"},
+	{APIVersion: "policy/v1beta1", Kind: "PodDisruptionBudget", RemovedIn: "1.25", ReplacedBy: "policy/v1"},
+	{APIVersion: "policy/v1beta1", Kind: "PodSecurityPolicy", RemovedIn: "1.25"},
+}
+
+// nolint: gochecknoglobals
+var manifestSeparator = regexp.MustCompile("(?m)^---")
+
+type manifestObject struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+}
+
+// FindRemovedAPIs returns the APIs used by the objects of a (multi document YAML) manifest
+// which are no longer served by the target Kubernetes version.
+func FindRemovedAPIs(manifest string, targetVersion string) ([]RemovedAPI, error) {
+	target, err := semver.NewVersion(targetVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[RemovedAPI]bool)
+
+	for _, doc := range manifestSeparator.Split(manifest, -1) {
+		var object manifestObject
+		if err := yaml.Unmarshal([]byte(doc), &object); err != nil {
+			// not every document is a valid Kubernetes object (eg. broken templates), skip them
+			continue
+		}
+
+		for _, api := range removedAPIs {
+			if api.APIVersion != object.APIVersion || api.Kind != object.Kind {
+				continue
+			}
+
+			removedIn, err := semver.NewVersion(api.RemovedIn)
+			if err != nil {
+				return nil, err
+			}
+
+			if !target.LessThan(removedIn) {
+				found[api] = true
+			}
+		}
+	}
+
+	result := make([]RemovedAPI, 0, len(found))
+	for api := range found {
+		result = append(result, api)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].APIVersion != result[j].APIVersion {
+			return result[i].APIVersion < result[j].APIVersion
+		}
+
+		return result[i].Kind < result[j].Kind
+	})
+
+	return result, nil
+}
@@ -0,0 +1,87 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterupgrade_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterupgrade"
+)
+
+const testManifest = `
+---
+# Source: chart/templates/deployment.yaml
+apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: app
+---
+# Source: chart/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+---
+# Source: chart/templates/ingress.yaml
+apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: app
+---
+# Source: chart/templates/worker.yaml
+apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: other
+`
+
+func TestFindRemovedAPIs(t *testing.T) {
+	t.Run("1.15", func(t *testing.T) {
+		apis, err := clusterupgrade.FindRemovedAPIs(testManifest, "1.15.3")
+		require.NoError(t, err)
+
+		assert.Empty(t, apis)
+	})
+
+	t.Run("1.16", func(t *testing.T) {
+		apis, err := clusterupgrade.FindRemovedAPIs(testManifest, "1.16.0")
+		require.NoError(t, err)
+
+		assert.Equal(
+			t,
+			[]clusterupgrade.RemovedAPI{
+				{APIVersion: "extensions/v1beta1", Kind: "Deployment", RemovedIn: "1.16", ReplacedBy: "apps/v1"},
+			},
+			apis,
+		)
+	})
+
+	t.Run("1.22", func(t *testing.T) {
+		apis, err := clusterupgrade.FindRemovedAPIs(testManifest, "1.22")
+		require.NoError(t, err)
+
+		assert.Equal(
+			t,
+			[]clusterupgrade.RemovedAPI{
+				{APIVersion: "extensions/v1beta1", Kind: "Deployment", RemovedIn: "1.16", ReplacedBy: "apps/v1"},
+				{APIVersion: "extensions/v1beta1", Kind: "Ingress", RemovedIn: "1.22", ReplacedBy: "networking.k8s.io/v1"},
+			},
+			apis,
+		)
+	})
+}
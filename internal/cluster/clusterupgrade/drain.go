@@ -0,0 +1,183 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterupgrade
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+)
+
+const mirrorPodAnnotation = "kubernetes.io/config.mirror"
+
+// NodeDrainer cordons Kubernetes nodes and evicts the pods running on them.
+type NodeDrainer struct {
+	client kubernetes.Interface
+	logger logrus.FieldLogger
+
+	// PollInterval is the time waited between checks of evicted pods.
+	PollInterval time.Duration
+}
+
+// NewNodeDrainer returns a new NodeDrainer instance.
+func NewNodeDrainer(client kubernetes.Interface, logger logrus.FieldLogger) *NodeDrainer {
+	return &NodeDrainer{
+		client: client,
+		logger: logger,
+
+		PollInterval: 5 * time.Second,
+	}
+}
+
+// Cordon marks a node unschedulable.
+func (d *NodeDrainer) Cordon(nodeName string) error {
+	node, err := d.client.CoreV1().Nodes().Get(nodeName, metav1.GetOptions{})
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to get node", "node", nodeName)
+	}
+
+	if node.Spec.Unschedulable {
+		return nil
+	}
+
+	node.Spec.Unschedulable = true
+
+	_, err = d.client.CoreV1().Nodes().Update(node)
+
+	return errors.WrapIfWithDetails(err, "failed to cordon node", "node", nodeName)
+}
+
+// Drain cordons a node and evicts every pod from it except the ones managed by DaemonSets and mirror pods.
+// It returns once the evicted pods are gone or the context is done.
+func (d *NodeDrainer) Drain(ctx context.Context, nodeName string) error {
+	logger := d.logger.WithField("node", nodeName)
+
+	if err := d.Cordon(nodeName); err != nil {
+		return err
+	}
+
+	pods, err := d.listEvictablePods(nodeName)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("evicting %d pods", len(pods))
+
+	for _, pod := range pods {
+		if err := d.evictPod(ctx, pod); err != nil {
+			return err
+		}
+	}
+
+	for {
+		pods, err := d.listEvictablePods(nodeName)
+		if err != nil {
+			return err
+		}
+
+		if len(pods) == 0 {
+			logger.Info("node drained")
+
+			return nil
+		}
+
+		logger.Debugf("waiting for %d pods to terminate", len(pods))
+
+		if err := sleep(ctx, d.PollInterval); err != nil {
+			return errors.WrapIfWithDetails(err, "node drain timed out", "node", nodeName, "pods", len(pods))
+		}
+	}
+}
+
+func (d *NodeDrainer) listEvictablePods(nodeName string) ([]corev1.Pod, error) {
+	podList, err := d.client.CoreV1().Pods(metav1.NamespaceAll).List(metav1.ListOptions{
+		FieldSelector: fields.SelectorFromSet(fields.Set{"spec.nodeName": nodeName}).String(),
+	})
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to list pods", "node", nodeName)
+	}
+
+	var pods []corev1.Pod
+	for _, pod := range podList.Items {
+		// the field selector is not respected by every client (eg. fake ones)
+		if pod.Spec.NodeName != nodeName {
+			continue
+		}
+
+		if _, ok := pod.Annotations[mirrorPodAnnotation]; ok {
+			continue
+		}
+
+		if isDaemonSetPod(pod) {
+			continue
+		}
+
+		pods = append(pods, pod)
+	}
+
+	return pods, nil
+}
+
+func (d *NodeDrainer) evictPod(ctx context.Context, pod corev1.Pod) error {
+	eviction := &policyv1beta1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+	}
+
+	for {
+		err := d.client.PolicyV1beta1().Evictions(pod.Namespace).Evict(eviction)
+		if err == nil || k8serrors.IsNotFound(err) {
+			return nil
+		}
+
+		// the eviction would violate a pod disruption budget, try again later
+		if !k8serrors.IsTooManyRequests(err) {
+			return errors.WrapIfWithDetails(err, "failed to evict pod", "namespace", pod.Namespace, "pod", pod.Name)
+		}
+
+		if err := sleep(ctx, d.PollInterval); err != nil {
+			return errors.WrapIfWithDetails(err, "pod eviction timed out", "namespace", pod.Namespace, "pod", pod.Name)
+		}
+	}
+}
+
+func isDaemonSetPod(pod corev1.Pod) bool {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Kind == "DaemonSet" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
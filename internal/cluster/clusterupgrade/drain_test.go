@@ -0,0 +1,101 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterupgrade_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterupgrade"
+)
+
+func TestNodeDrainer_Drain(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			Spec:       corev1.PodSpec{NodeName: "node1"},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+			Spec:       corev1.PodSpec{NodeName: "node2"},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "agent",
+				Namespace:       "kube-system",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "DaemonSet", Name: "agent"}},
+			},
+			Spec: corev1.PodSpec{NodeName: "node1"},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "kube-proxy",
+				Namespace:   "kube-system",
+				Annotations: map[string]string{"kubernetes.io/config.mirror": "hash"},
+			},
+			Spec: corev1.PodSpec{NodeName: "node1"},
+		},
+	)
+
+	var evicted []string
+	client.PrependReactor("post", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+
+		name := action.(k8stesting.GetAction).GetName()
+		evicted = append(evicted, name)
+
+		// the fake client is locked while running reactors
+		go func() {
+			_ = client.CoreV1().Pods(action.GetNamespace()).Delete(name, &metav1.DeleteOptions{})
+		}()
+
+		return true, nil, nil
+	})
+
+	logger, _ := test.NewNullLogger()
+	drainer := clusterupgrade.NewNodeDrainer(client, logger)
+	drainer.PollInterval = time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := drainer.Drain(ctx, "node1")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"app"}, evicted)
+
+	node, err := client.CoreV1().Nodes().Get("node1", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	assert.True(t, node.Spec.Unschedulable)
+
+	pods, err := client.CoreV1().Pods(metav1.NamespaceAll).List(metav1.ListOptions{})
+	require.NoError(t, err)
+
+	assert.Len(t, pods.Items, 3)
+}
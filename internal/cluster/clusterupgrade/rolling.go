@@ -0,0 +1,278 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterupgrade
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// the cluster autoscaler release deployed by Pipeline
+const (
+	autoscalerNamespace = "kube-system"
+	autoscalerSelector  = "release=autoscaler"
+)
+
+// AutoScalingGroupRoller replaces the instances of an AWS auto scaling group which are not running
+// with the current launch configuration of the group.
+type AutoScalingGroupRoller struct {
+	autoscaling autoscalingiface.AutoScalingAPI
+	client      kubernetes.Interface
+	drainer     *NodeDrainer
+	pauser      *AutoscalerPauser
+	logger      logrus.FieldLogger
+
+	// PollInterval is the time waited between checks of the group and its nodes.
+	PollInterval time.Duration
+
+	// StepTimeout limits the time a single instance replacement can take.
+	StepTimeout time.Duration
+}
+
+// NewAutoScalingGroupRoller returns a new AutoScalingGroupRoller instance.
+func NewAutoScalingGroupRoller(
+	autoscaling autoscalingiface.AutoScalingAPI,
+	client kubernetes.Interface,
+	logger logrus.FieldLogger,
+) *AutoScalingGroupRoller {
+	return &AutoScalingGroupRoller{
+		autoscaling: autoscaling,
+		client:      client,
+		drainer:     NewNodeDrainer(client, logger),
+		pauser:      NewAutoscalerPauser(client, autoscalerNamespace, autoscalerSelector, logger),
+		logger:      logger,
+
+		PollInterval: 15 * time.Second,
+		StepTimeout:  20 * time.Minute,
+	}
+}
+
+// Roll replaces outdated instances one by one: it surges the group with new instances,
+// then drains and terminates the old ones after the new nodes joined the cluster.
+// The cluster autoscaler is paused during the replacement.
+func (r *AutoScalingGroupRoller) Roll(ctx context.Context, groupName string, maxSurge int) (err error) {
+	logger := r.logger.WithField("autoScalingGroup", groupName)
+
+	group, err := r.describeGroup(groupName)
+	if err != nil {
+		return err
+	}
+
+	var outdated []*autoscaling.Instance
+	for _, instance := range group.Instances {
+		if instance.LaunchConfigurationName == nil || aws.StringValue(instance.LaunchConfigurationName) != aws.StringValue(group.LaunchConfigurationName) {
+			outdated = append(outdated, instance)
+		}
+	}
+
+	if len(outdated) == 0 {
+		logger.Info("every instance is up to date")
+
+		return nil
+	}
+
+	resume, err := r.pauser.Pause()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Combine(err, resume())
+	}()
+
+	surge := maxSurge
+	if surge < 1 {
+		surge = 1
+	}
+	if surge > len(outdated) {
+		surge = len(outdated)
+	}
+
+	originalMaxSize := aws.Int64Value(group.MaxSize)
+	desired := aws.Int64Value(group.DesiredCapacity) + int64(surge)
+
+	input := &autoscaling.UpdateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String(groupName),
+		DesiredCapacity:      aws.Int64(desired),
+	}
+	if desired > originalMaxSize {
+		input.MaxSize = aws.Int64(desired)
+	}
+
+	logger.Infof("replacing %d instances (surge: %d)", len(outdated), surge)
+
+	if _, err := r.autoscaling.UpdateAutoScalingGroup(input); err != nil {
+		return errors.WrapIfWithDetails(err, "failed to surge auto scaling group", "autoScalingGroup", groupName)
+	}
+
+	for i, instance := range outdated {
+		instanceID := aws.StringValue(instance.InstanceId)
+
+		if err := r.waitForGroup(ctx, groupName); err != nil {
+			return err
+		}
+
+		nodeName, err := r.findNodeName(instanceID)
+		if err != nil {
+			return err
+		}
+
+		if nodeName != "" {
+			drainCtx, cancel := context.WithTimeout(ctx, r.StepTimeout)
+			err := r.drainer.Drain(drainCtx, nodeName)
+			cancel()
+			if err != nil {
+				return err
+			}
+		}
+
+		// the last instances are not replaced to scale the group back to its original size
+		decrement := i >= len(outdated)-surge
+
+		logger.WithField("instance", instanceID).Info("terminating instance")
+
+		_, err = r.autoscaling.TerminateInstanceInAutoScalingGroup(&autoscaling.TerminateInstanceInAutoScalingGroupInput{
+			InstanceId:                     aws.String(instanceID),
+			ShouldDecrementDesiredCapacity: aws.Bool(decrement),
+		})
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "failed to terminate instance", "autoScalingGroup", groupName, "instance", instanceID)
+		}
+	}
+
+	if desired > originalMaxSize {
+		_, err := r.autoscaling.UpdateAutoScalingGroup(&autoscaling.UpdateAutoScalingGroupInput{
+			AutoScalingGroupName: aws.String(groupName),
+			MaxSize:              aws.Int64(originalMaxSize),
+		})
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "failed to restore auto scaling group size", "autoScalingGroup", groupName)
+		}
+	}
+
+	return nil
+}
+
+func (r *AutoScalingGroupRoller) describeGroup(groupName string) (*autoscaling.Group, error) {
+	output, err := r.autoscaling.DescribeAutoScalingGroups(&autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: aws.StringSlice([]string{groupName}),
+	})
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to describe auto scaling group", "autoScalingGroup", groupName)
+	}
+
+	if len(output.AutoScalingGroups) == 0 {
+		return nil, errors.NewWithDetails("auto scaling group not found", "autoScalingGroup", groupName)
+	}
+
+	return output.AutoScalingGroups[0], nil
+}
+
+// waitForGroup waits until the group reaches its desired capacity and each of its instances joined the cluster as a ready node.
+func (r *AutoScalingGroupRoller) waitForGroup(ctx context.Context, groupName string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.StepTimeout)
+	defer cancel()
+
+	for {
+		ready, err := r.isGroupReady(groupName)
+		if err != nil {
+			return err
+		}
+
+		if ready {
+			return nil
+		}
+
+		if err := sleep(ctx, r.PollInterval); err != nil {
+			return errors.WrapIfWithDetails(err, "waiting for auto scaling group instances timed out", "autoScalingGroup", groupName)
+		}
+	}
+}
+
+func (r *AutoScalingGroupRoller) isGroupReady(groupName string) (bool, error) {
+	group, err := r.describeGroup(groupName)
+	if err != nil {
+		return false, err
+	}
+
+	var inService []string
+	for _, instance := range group.Instances {
+		if aws.StringValue(instance.LifecycleState) == autoscaling.LifecycleStateInService {
+			inService = append(inService, aws.StringValue(instance.InstanceId))
+		}
+	}
+
+	if int64(len(inService)) < aws.Int64Value(group.DesiredCapacity) {
+		return false, nil
+	}
+
+	nodes, err := r.client.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return false, errors.WrapIf(err, "failed to list nodes")
+	}
+
+	for _, instanceID := range inService {
+		node := findNodeByInstanceID(nodes.Items, instanceID)
+		if node == nil || !isNodeReady(*node) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (r *AutoScalingGroupRoller) findNodeName(instanceID string) (string, error) {
+	nodes, err := r.client.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return "", errors.WrapIf(err, "failed to list nodes")
+	}
+
+	node := findNodeByInstanceID(nodes.Items, instanceID)
+	if node == nil {
+		return "", nil
+	}
+
+	return node.Name, nil
+}
+
+// findNodeByInstanceID matches nodes based on their AWS provider ID (aws:///<zone>/<instance ID>).
+func findNodeByInstanceID(nodes []corev1.Node, instanceID string) *corev1.Node {
+	for i := range nodes {
+		if strings.HasSuffix(nodes[i].Spec.ProviderID, "/"+instanceID) {
+			return &nodes[i]
+		}
+	}
+
+	return nil
+}
+
+func isNodeReady(node corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
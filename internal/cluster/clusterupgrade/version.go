@@ -0,0 +1,60 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterupgrade
+
+import (
+	"fmt"
+
+	"emperror.dev/errors"
+	"github.com/Masterminds/semver"
+)
+
+// ValidateVersionUpgrade checks whether a cluster can be upgraded from the current Kubernetes version to the target one.
+// Only upgrades to a newer version within the same major version are allowed, moving at most one minor version at a time.
+func ValidateVersionUpgrade(current string, target string) error {
+	currentVersion, err := semver.NewVersion(current)
+	if err != nil {
+		return errors.WrapIf(err, "invalid current Kubernetes version")
+	}
+
+	targetVersion, err := semver.NewVersion(target)
+	if err != nil {
+		return errors.WrapIf(err, "invalid target Kubernetes version")
+	}
+
+	if !targetVersion.GreaterThan(currentVersion) {
+		return errors.Errorf("target Kubernetes version %s must be newer than the current version %s", target, current)
+	}
+
+	if targetVersion.Major() != currentVersion.Major() {
+		return errors.New("upgrading to a different major Kubernetes version is not supported")
+	}
+
+	if targetVersion.Minor() > currentVersion.Minor()+1 {
+		return errors.Errorf("Kubernetes minor versions cannot be skipped: upgrade to %d.%d first", currentVersion.Major(), currentVersion.Minor()+1)
+	}
+
+	return nil
+}
+
+// MinorVersion returns the major.minor part of a Kubernetes version.
+func MinorVersion(version string) (string, error) {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return "", errors.WrapIf(err, "invalid Kubernetes version")
+	}
+
+	return fmt.Sprintf("%d.%d", v.Major(), v.Minor()), nil
+}
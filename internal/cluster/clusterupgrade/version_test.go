@@ -0,0 +1,54 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterupgrade_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/clusterupgrade"
+)
+
+func TestValidateVersionUpgrade(t *testing.T) {
+	testCases := map[string]struct {
+		current string
+		target  string
+		valid   bool
+	}{
+		"next minor":      {current: "1.13.7", target: "1.14", valid: true},
+		"patch":           {current: "1.13.7", target: "1.13.10", valid: true},
+		"same version":    {current: "1.13.7", target: "1.13.7", valid: false},
+		"downgrade":       {current: "1.14.3", target: "1.13.7", valid: false},
+		"skipping minor":  {current: "1.12.9", target: "1.14.3", valid: false},
+		"major":           {current: "1.14.3", target: "2.0.0", valid: false},
+		"invalid target":  {current: "1.14.3", target: "latest", valid: false},
+		"invalid current": {current: "", target: "1.14.3", valid: false},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			err := clusterupgrade.ValidateVersionUpgrade(tc.current, tc.target)
+
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sleepschedule
+
+import (
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+)
+
+// Events notifies the sleep controller about schedule changes.
+type Events interface {
+	SleepScheduleChanged(clusterID uint)
+}
+
+// SetScheduleRequest contains the parameters of a sleep schedule.
+type SetScheduleRequest struct {
+	ClusterID uint
+	UserID    uint
+	SleepAt   string
+	WakeAt    string
+	Timezone  string
+}
+
+// Manager manages the sleep schedules of clusters.
+type Manager struct {
+	db     *gorm.DB
+	events Events
+}
+
+// NewManager returns a new Manager.
+func NewManager(db *gorm.DB, events Events) *Manager {
+	return &Manager{
+		db:     db,
+		events: events,
+	}
+}
+
+// GetSchedule returns the sleep schedule of a cluster.
+func (m *Manager) GetSchedule(clusterID uint) (*Schedule, error) {
+	var schedule Schedule
+
+	err := m.db.Where(&Schedule{ClusterID: clusterID}).First(&schedule).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, ScheduleNotFoundError{ClusterID: clusterID}
+	} else if err != nil {
+		return nil, errors.WrapIf(err, "failed to get sleep schedule")
+	}
+
+	return &schedule, nil
+}
+
+// ListSchedules returns every sleep schedule.
+func (m *Manager) ListSchedules() ([]Schedule, error) {
+	var schedules []Schedule
+
+	err := m.db.Find(&schedules).Error
+
+	return schedules, errors.WrapIf(err, "failed to list sleep schedules")
+}
+
+// SetSchedule creates or replaces the sleep schedule of a cluster.
+// The current state of the cluster is kept, an active override is cleared.
+func (m *Manager) SetSchedule(request SetScheduleRequest) (*Schedule, error) {
+	if request.Timezone == "" {
+		request.Timezone = "UTC"
+	}
+
+	if _, err := parseSchedule(request.SleepAt, request.WakeAt, request.Timezone); err != nil {
+		return nil, err
+	}
+
+	schedule, err := m.GetSchedule(request.ClusterID)
+	if errors.As(err, &ScheduleNotFoundError{}) {
+		schedule = &Schedule{
+			ClusterID: request.ClusterID,
+			CreatedBy: request.UserID,
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	schedule.SleepAt = request.SleepAt
+	schedule.WakeAt = request.WakeAt
+	schedule.Timezone = request.Timezone
+	schedule.OverrideState = ""
+	schedule.OverrideUntil = nil
+
+	if err := m.db.Save(schedule).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to save sleep schedule")
+	}
+
+	m.events.SleepScheduleChanged(request.ClusterID)
+
+	return schedule, nil
+}
+
+// DeleteSchedule deletes the sleep schedule of a cluster.
+// The schedule of a sleeping cluster cannot be deleted, as it holds the node pool sizes to restore.
+func (m *Manager) DeleteSchedule(clusterID uint) error {
+	schedule, err := m.GetSchedule(clusterID)
+	if err != nil {
+		return err
+	}
+
+	if schedule.Asleep {
+		return ValidationError{"cluster is asleep, wake it up before deleting its sleep schedule"}
+	}
+
+	err = m.db.Delete(schedule).Error
+
+	return errors.WrapIf(err, "failed to delete sleep schedule")
+}
+
+// PurgeSchedule deletes the sleep schedule of a deleted cluster regardless of its state.
+func (m *Manager) PurgeSchedule(clusterID uint) error {
+	err := m.db.Where("cluster_id = ?", clusterID).Delete(&Schedule{}).Error
+
+	return errors.WrapIf(err, "failed to purge sleep schedule")
+}
+
+// Override keeps a cluster in the given state until the given time,
+// or until the next scheduled transition into the opposite state if until is nil.
+func (m *Manager) Override(clusterID uint, state string, until *time.Time) (*Schedule, error) {
+	if state != StateAsleep && state != StateAwake {
+		return nil, ValidationError{"state must be either asleep or awake"}
+	}
+
+	schedule, err := m.GetSchedule(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if until == nil {
+		parsed, err := parseSchedule(schedule.SleepAt, schedule.WakeAt, schedule.Timezone)
+		if err != nil {
+			return nil, err
+		}
+
+		var next time.Time
+		if state == StateAsleep {
+			next = parsed.nextWake(time.Now())
+		} else {
+			next = parsed.nextSleep(time.Now())
+		}
+
+		until = &next
+	} else if !until.After(time.Now()) {
+		return nil, ValidationError{"override end must be in the future"}
+	}
+
+	schedule.OverrideState = state
+	schedule.OverrideUntil = until
+
+	if err := m.db.Save(schedule).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to save sleep schedule override")
+	}
+
+	m.events.SleepScheduleChanged(clusterID)
+
+	return schedule, nil
+}
+
+// ClearOverride makes a cluster follow its schedule again.
+func (m *Manager) ClearOverride(clusterID uint) (*Schedule, error) {
+	schedule, err := m.GetSchedule(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.OverrideState = ""
+	schedule.OverrideUntil = nil
+
+	if err := m.db.Save(schedule).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to clear sleep schedule override")
+	}
+
+	m.events.SleepScheduleChanged(clusterID)
+
+	return schedule, nil
+}
+
+// RecordTransition records that a cluster has been put to sleep (with its previous node pool sizes) or woken up.
+func (m *Manager) RecordTransition(clusterID uint, asleep bool, sizes NodePoolSizes) error {
+	now := time.Now()
+
+	if !asleep {
+		sizes = nil
+	}
+
+	err := m.db.Model(&Schedule{}).Where("cluster_id = ?", clusterID).Updates(map[string]interface{}{
+		"asleep":             asleep,
+		"node_pool_sizes":    sizes,
+		"last_transition_at": &now,
+		"last_error":         "",
+	}).Error
+
+	return errors.WrapIf(err, "failed to record sleep schedule transition")
+}
+
+// RecordError records a failed transition.
+func (m *Manager) RecordError(clusterID uint, message string) error {
+	err := m.db.Model(&Schedule{}).Where("cluster_id = ?", clusterID).Update("last_error", message).Error
+
+	return errors.WrapIf(err, "failed to record sleep schedule error")
+}
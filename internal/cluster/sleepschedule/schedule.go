@@ -0,0 +1,199 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sleepschedule
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/robfig/cron"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+)
+
+// States of a cluster
+const (
+	StateAsleep = "asleep"
+	StateAwake  = "awake"
+)
+
+// NodePoolSizes maps node pool names to node counts.
+type NodePoolSizes map[string]int
+
+// Scan implements the sql.Scanner interface.
+func (s *NodePoolSizes) Scan(src interface{}) error {
+	value, err := cast.ToStringE(src)
+	if err != nil {
+		return err
+	}
+
+	if value == "" {
+		*s = nil
+
+		return nil
+	}
+
+	return json.Unmarshal([]byte(value), s)
+}
+
+// Value implements the driver.Valuer interface.
+func (s NodePoolSizes) Value() (driver.Value, error) {
+	if s == nil {
+		return "", nil
+	}
+
+	v, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+
+	return string(v), nil
+}
+
+// Schedule puts a cluster to sleep (scales its node pools to zero) and wakes it up (restores the node pool sizes)
+// according to a pair of cron expressions evaluated in a timezone.
+type Schedule struct {
+	ID        uint `gorm:"primary_key"`
+	ClusterID uint `gorm:"unique_index:idx_cluster_sleep_schedules_cluster_id;not null"`
+
+	// SleepAt is a standard cron expression (eg. "0 20 * * 1-5") of the times the cluster is put to sleep.
+	SleepAt string `gorm:"not null"`
+	// WakeAt is a standard cron expression (eg. "0 7 * * 1-5") of the times the cluster is woken up.
+	WakeAt string `gorm:"not null"`
+	// Timezone is the IANA name of the timezone the cron expressions are evaluated in.
+	Timezone string `gorm:"not null"`
+
+	// Asleep is the current state of the cluster.
+	Asleep bool `gorm:"not null;default:false"`
+	// NodePoolSizes are the node pool sizes recorded before the cluster was put to sleep.
+	NodePoolSizes NodePoolSizes `gorm:"type:text"`
+
+	// OverrideState (if set) is kept instead of the scheduled one until OverrideUntil.
+	OverrideState string
+	OverrideUntil *time.Time
+
+	LastTransitionAt *time.Time
+	LastError        string `gorm:"type:text"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	CreatedBy uint
+}
+
+// TableName changes the default table name.
+func (Schedule) TableName() string {
+	return "cluster_sleep_schedules"
+}
+
+// Migrate executes the table migrations for the cluster sleep schedules.
+func Migrate(db *gorm.DB, logger logrus.FieldLogger) error {
+	logger.WithFields(logrus.Fields{
+		"table_names": Schedule{}.TableName(),
+	}).Info("migrating cluster sleep schedule tables")
+
+	return db.AutoMigrate(&Schedule{}).Error
+}
+
+// ValidationError is returned when a schedule or an override is invalid.
+type ValidationError struct {
+	message string
+}
+
+// Error implements the error interface.
+func (e ValidationError) Error() string {
+	return e.message
+}
+
+// ScheduleNotFoundError is returned when a cluster has no sleep schedule.
+type ScheduleNotFoundError struct {
+	ClusterID uint
+}
+
+// Error implements the error interface.
+func (e ScheduleNotFoundError) Error() string {
+	return fmt.Sprintf("cluster %d has no sleep schedule", e.ClusterID)
+}
+
+type parsedSchedule struct {
+	sleep    cron.Schedule
+	wake     cron.Schedule
+	location *time.Location
+}
+
+func parseSchedule(sleepAt string, wakeAt string, timezone string) (*parsedSchedule, error) {
+	sleep, err := cron.ParseStandard(sleepAt)
+	if err != nil {
+		return nil, ValidationError{fmt.Sprintf("invalid sleep schedule: %s", err)}
+	}
+
+	wake, err := cron.ParseStandard(wakeAt)
+	if err != nil {
+		return nil, ValidationError{fmt.Sprintf("invalid wake schedule: %s", err)}
+	}
+
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, ValidationError{fmt.Sprintf("invalid timezone: %s", err)}
+	}
+
+	s := &parsedSchedule{
+		sleep:    sleep,
+		wake:     wake,
+		location: location,
+	}
+
+	// cron returns zero time for expressions never matching (eg. "0 0 30 2 *")
+	now := time.Now()
+	if s.nextSleep(now).IsZero() || s.nextWake(now).IsZero() {
+		return nil, ValidationError{"sleep and wake schedules must match at least once a year"}
+	}
+
+	return s, nil
+}
+
+func (s *parsedSchedule) nextSleep(t time.Time) time.Time {
+	return s.sleep.Next(t.In(s.location))
+}
+
+func (s *parsedSchedule) nextWake(t time.Time) time.Time {
+	return s.wake.Next(t.In(s.location))
+}
+
+// DesiredState returns whether the cluster should be asleep at the given time, and when it should change next.
+// A cluster should be asleep if the next scheduled event is waking up. An active override takes precedence.
+func (s Schedule) DesiredState(now time.Time) (bool, time.Time, error) {
+	parsed, err := parseSchedule(s.SleepAt, s.WakeAt, s.Timezone)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+
+	if s.OverrideState != "" && s.OverrideUntil != nil && now.Before(*s.OverrideUntil) {
+		return s.OverrideState == StateAsleep, *s.OverrideUntil, nil
+	}
+
+	nextSleep, nextWake := parsed.nextSleep(now), parsed.nextWake(now)
+	if nextWake.Before(nextSleep) {
+		return true, nextWake, nil
+	}
+
+	return false, nextSleep, nil
+}
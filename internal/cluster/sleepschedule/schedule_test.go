@@ -0,0 +1,198 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sleepschedule_test
+
+import (
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/sleepschedule"
+)
+
+func TestSchedule_DesiredState(t *testing.T) {
+	location, err := time.LoadLocation("Europe/Budapest")
+	require.NoError(t, err)
+
+	schedule := sleepschedule.Schedule{
+		SleepAt:  "0 20 * * 1-5",
+		WakeAt:   "0 7 * * 1-5",
+		Timezone: "Europe/Budapest",
+	}
+
+	testCases := map[string]struct {
+		now      time.Time
+		asleep   bool
+		nextTime time.Time
+	}{
+		"working hours": {
+			now:      time.Date(2019, 9, 2, 12, 0, 0, 0, location), // Monday
+			asleep:   false,
+			nextTime: time.Date(2019, 9, 2, 20, 0, 0, 0, location),
+		},
+		"night": {
+			now:      time.Date(2019, 9, 2, 23, 0, 0, 0, location),
+			asleep:   true,
+			nextTime: time.Date(2019, 9, 3, 7, 0, 0, 0, location),
+		},
+		"weekend": {
+			now:      time.Date(2019, 9, 7, 12, 0, 0, 0, location), // Saturday
+			asleep:   true,
+			nextTime: time.Date(2019, 9, 9, 7, 0, 0, 0, location),
+		},
+		"different timezone": {
+			now:      time.Date(2019, 9, 2, 19, 30, 0, 0, time.UTC), // 21:30 in Budapest
+			asleep:   true,
+			nextTime: time.Date(2019, 9, 3, 7, 0, 0, 0, location),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(name, func(t *testing.T) {
+			asleep, next, err := schedule.DesiredState(testCase.now)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.asleep, asleep)
+			assert.True(t, testCase.nextTime.Equal(next), "expected %s, got %s", testCase.nextTime, next)
+		})
+	}
+
+	t.Run("override", func(t *testing.T) {
+		now := time.Date(2019, 9, 2, 23, 0, 0, 0, location)
+		until := now.Add(2 * time.Hour)
+
+		schedule := schedule
+		schedule.OverrideState = sleepschedule.StateAwake
+		schedule.OverrideUntil = &until
+
+		asleep, next, err := schedule.DesiredState(now)
+		require.NoError(t, err)
+		assert.False(t, asleep)
+		assert.True(t, until.Equal(next))
+
+		asleep, _, err = schedule.DesiredState(until.Add(time.Minute))
+		require.NoError(t, err)
+		assert.True(t, asleep)
+	})
+}
+
+type eventsStub struct {
+	changes []uint
+}
+
+func (e *eventsStub) SleepScheduleChanged(clusterID uint) {
+	e.changes = append(e.changes, clusterID)
+}
+
+func TestManager(t *testing.T) {
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	db.DB().SetMaxOpenConns(1)
+
+	logger, _ := test.NewNullLogger()
+	require.NoError(t, sleepschedule.Migrate(db, logger))
+
+	const clusterID = 1
+
+	events := &eventsStub{}
+	manager := sleepschedule.NewManager(db, events)
+
+	t.Run("InvalidSchedules", func(t *testing.T) {
+		for name, request := range map[string]sleepschedule.SetScheduleRequest{
+			"invalid sleep":    {ClusterID: clusterID, SleepAt: "invalid", WakeAt: "0 7 * * *"},
+			"invalid wake":     {ClusterID: clusterID, SleepAt: "0 20 * * *", WakeAt: "0 25 * * *"},
+			"invalid timezone": {ClusterID: clusterID, SleepAt: "0 20 * * *", WakeAt: "0 7 * * *", Timezone: "Mars/Olympus"},
+			"never matching":   {ClusterID: clusterID, SleepAt: "0 0 30 2 *", WakeAt: "0 7 * * *"},
+		} {
+			_, err := manager.SetSchedule(request)
+			assert.True(t, errors.As(err, &sleepschedule.ValidationError{}), name)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := manager.GetSchedule(clusterID)
+		assert.True(t, errors.As(err, &sleepschedule.ScheduleNotFoundError{}))
+	})
+
+	t.Run("SetSchedule", func(t *testing.T) {
+		schedule, err := manager.SetSchedule(sleepschedule.SetScheduleRequest{
+			ClusterID: clusterID,
+			UserID:    1,
+			SleepAt:   "0 20 * * 1-5",
+			WakeAt:    "0 7 * * 1-5",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "UTC", schedule.Timezone)
+		assert.Equal(t, []uint{clusterID}, events.changes)
+	})
+
+	t.Run("Override", func(t *testing.T) {
+		_, err := manager.Override(clusterID, "hibernated", nil)
+		assert.True(t, errors.As(err, &sleepschedule.ValidationError{}))
+
+		past := time.Now().Add(-time.Hour)
+		_, err = manager.Override(clusterID, sleepschedule.StateAwake, &past)
+		assert.True(t, errors.As(err, &sleepschedule.ValidationError{}))
+
+		schedule, err := manager.Override(clusterID, sleepschedule.StateAwake, nil)
+		require.NoError(t, err)
+		require.NotNil(t, schedule.OverrideUntil)
+
+		asleep, _, err := schedule.DesiredState(time.Now())
+		require.NoError(t, err)
+		assert.False(t, asleep)
+
+		schedule, err = manager.ClearOverride(clusterID)
+		require.NoError(t, err)
+		assert.Nil(t, schedule.OverrideUntil)
+	})
+
+	t.Run("RecordTransition", func(t *testing.T) {
+		require.NoError(t, manager.RecordTransition(clusterID, true, sleepschedule.NodePoolSizes{"pool1": 3}))
+
+		schedule, err := manager.GetSchedule(clusterID)
+		require.NoError(t, err)
+		assert.True(t, schedule.Asleep)
+		assert.Equal(t, sleepschedule.NodePoolSizes{"pool1": 3}, schedule.NodePoolSizes)
+
+		err = manager.DeleteSchedule(clusterID)
+		assert.True(t, errors.As(err, &sleepschedule.ValidationError{}))
+
+		require.NoError(t, manager.RecordTransition(clusterID, false, nil))
+
+		schedule, err = manager.GetSchedule(clusterID)
+		require.NoError(t, err)
+		assert.False(t, schedule.Asleep)
+		assert.Nil(t, schedule.NodePoolSizes)
+	})
+
+	t.Run("DeleteSchedule", func(t *testing.T) {
+		require.NoError(t, manager.DeleteSchedule(clusterID))
+
+		_, err := manager.GetSchedule(clusterID)
+		assert.True(t, errors.As(err, &sleepschedule.ScheduleNotFoundError{}))
+	})
+}
@@ -59,20 +59,35 @@ func (m asyncFeatureManagerStub) Activate(ctx context.Context, clusterID uint, s
 		return errors.WrapIf(err, msg)
 	}
 
-	return m.dispatchAction(ctx, clusterID, workflow.ActionActivate, spec)
+	revision, err := m.featureRepository.CreateFeatureRevision(ctx, clusterID, m.Name(), clusterfeature.FeatureRevisionActionActivate, spec)
+	if err != nil {
+		const msg = "failed to create feature revision"
+		logger.Debug(msg)
+		return errors.WrapIf(err, msg)
+	}
+
+	return m.dispatchAction(ctx, clusterID, workflow.ActionActivate, spec, revision)
 }
 
 // Removes feature from the given cluster
 func (m asyncFeatureManagerStub) Deactivate(ctx context.Context, clusterID uint) error {
 	logger := m.logger.WithContext(ctx).WithFields(map[string]interface{}{"clusterID": clusterID, "feature": m.Name()})
 
-	if _, err := m.featureRepository.UpdateFeatureStatus(ctx, clusterID, m.Name(), clusterfeature.FeatureStatusPending); err != nil {
+	feature, err := m.featureRepository.UpdateFeatureStatus(ctx, clusterID, m.Name(), clusterfeature.FeatureStatusPending)
+	if err != nil {
 		const msg = "failed to create or update feature"
 		logger.Debug(msg)
 		return errors.WrapIf(err, msg)
 	}
 
-	return m.dispatchAction(ctx, clusterID, workflow.ActionDeactivate, nil)
+	revision, err := m.featureRepository.CreateFeatureRevision(ctx, clusterID, m.Name(), clusterfeature.FeatureRevisionActionDeactivate, feature.Spec)
+	if err != nil {
+		const msg = "failed to create feature revision"
+		logger.Debug(msg)
+		return errors.WrapIf(err, msg)
+	}
+
+	return m.dispatchAction(ctx, clusterID, workflow.ActionDeactivate, nil, revision)
 }
 
 // Updates a feature on the given cluster
@@ -85,10 +100,17 @@ func (m asyncFeatureManagerStub) Update(ctx context.Context, clusterID uint, spe
 		return errors.WrapIf(err, msg)
 	}
 
-	return m.dispatchAction(ctx, clusterID, workflow.ActionUpdate, spec)
+	revision, err := m.featureRepository.CreateFeatureRevision(ctx, clusterID, m.Name(), clusterfeature.FeatureRevisionActionUpdate, spec)
+	if err != nil {
+		const msg = "failed to create feature revision"
+		logger.Debug(msg)
+		return errors.WrapIf(err, msg)
+	}
+
+	return m.dispatchAction(ctx, clusterID, workflow.ActionUpdate, spec, revision)
 }
 
-func (m asyncFeatureManagerStub) dispatchAction(ctx context.Context, clusterID uint, action string, spec clusterfeature.FeatureSpec) error {
+func (m asyncFeatureManagerStub) dispatchAction(ctx context.Context, clusterID uint, action string, spec clusterfeature.FeatureSpec, revision uint) error {
 	const workflowName = workflow.ClusterFeatureJobWorkflowName
 	featureName := m.Name()
 	workflowID := getWorkflowID(workflowName, clusterID, featureName)
@@ -96,6 +118,7 @@ func (m asyncFeatureManagerStub) dispatchAction(ctx context.Context, clusterID u
 	signalArg := workflow.ClusterFeatureJobSignalInput{
 		Action:        action,
 		FeatureSpec:   spec,
+		Revision:      revision,
 		RetryInterval: 1 * time.Minute,
 	}
 	options := client.StartWorkflowOptions{
@@ -25,6 +25,7 @@ import (
 func Migrate(db *gorm.DB, logger logrus.FieldLogger) error {
 	tables := []interface{}{
 		&clusterFeatureModel{},
+		&clusterFeatureRevisionModel{},
 	}
 
 	var tableNames string
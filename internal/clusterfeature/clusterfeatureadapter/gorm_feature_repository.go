@@ -32,7 +32,8 @@ import (
 
 // TableName constants
 const (
-	clusterFeatureTableName = "cluster_features"
+	clusterFeatureTableName         = "cluster_features"
+	clusterFeatureRevisionTableName = "cluster_feature_revisions"
 )
 
 type featureSpec map[string]interface{}
@@ -77,18 +78,56 @@ func (cfm clusterFeatureModel) String() string {
 	return fmt.Sprintf("Id: %d, Creation date: %s, Name: %s", cfm.ID, cfm.CreatedAt, cfm.Name)
 }
 
+// clusterFeatureRevisionModel describes an immutable revision of a cluster feature.
+type clusterFeatureRevisionModel struct {
+	ID        uint `gorm:"primary_key"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	ClusterID   uint        `gorm:"unique_index:idx_cluster_feature_revision"`
+	FeatureName string      `gorm:"unique_index:idx_cluster_feature_revision"`
+	Revision    uint        `gorm:"unique_index:idx_cluster_feature_revision"`
+	Action      string      `gorm:"type:varchar(255)"`
+	Spec        featureSpec `gorm:"type:text"`
+	Status      string      `gorm:"type:varchar(255)"`
+	CreatedBy   uint
+}
+
+// TableName changes the default table name.
+func (cfrm clusterFeatureRevisionModel) TableName() string {
+	return clusterFeatureRevisionTableName
+}
+
+// UserIDContextExtractor extracts a user ID from a context (if there is any).
+type UserIDContextExtractor interface {
+	// GetUserID extracts a user ID from a context (if there is any).
+	GetUserID(ctx context.Context) (uint, bool)
+}
+
+// UserIDContextExtractorFunc converts an ordinary function to a UserIDContextExtractor
+// (given it's method signature is compatible with the interface).
+type UserIDContextExtractorFunc func(ctx context.Context) (uint, bool)
+
+// GetUserID implements the UserIDContextExtractor interface.
+func (f UserIDContextExtractorFunc) GetUserID(ctx context.Context) (uint, bool) {
+	return f(ctx)
+}
+
 // gormFeatureRepository component in charge for executing persistence operation on Features.
 // TODO: write integration tests
 type gormFeatureRepository struct {
-	db *gorm.DB
+	db            *gorm.DB
+	userExtractor UserIDContextExtractor
 
 	logger common.Logger
 }
 
 // NewGormFeatureRepository returns a feature repository persisting feature state into database using Gorm.
-func NewGormFeatureRepository(db *gorm.DB, logger common.Logger) clusterfeature.FeatureRepository {
+// The user extractor is used to record the author of feature revisions.
+func NewGormFeatureRepository(db *gorm.DB, userExtractor UserIDContextExtractor, logger common.Logger) clusterfeature.FeatureRepository {
 	return &gormFeatureRepository{
-		db: db,
+		db:            db,
+		userExtractor: userExtractor,
 
 		logger: logger,
 	}
@@ -221,3 +260,103 @@ func (r *gormFeatureRepository) DeleteFeature(ctx context.Context, clusterID uin
 	return nil
 
 }
+
+// CreateFeatureRevision records a new revision of the feature with the next revision number
+func (r *gormFeatureRepository) CreateFeatureRevision(ctx context.Context, clusterID uint, featureName string, action string, spec clusterfeature.FeatureSpec) (uint, error) {
+	userID, _ := r.userExtractor.GetUserID(ctx)
+
+	revision := clusterFeatureRevisionModel{
+		ClusterID:   clusterID,
+		FeatureName: featureName,
+		Action:      action,
+		Spec:        spec,
+		Status:      clusterfeature.FeatureRevisionStatusPending,
+		CreatedBy:   userID,
+	}
+
+	tx := r.db.Begin()
+
+	var last clusterFeatureRevisionModel
+
+	err := tx.Where(&clusterFeatureRevisionModel{ClusterID: clusterID, FeatureName: featureName}).Order("revision DESC").First(&last).Error
+	if err != nil && !gorm.IsRecordNotFoundError(err) {
+		tx.Rollback()
+
+		return 0, errors.WrapIfWithDetails(err, "could not retrieve last feature revision", "clusterID", clusterID, "feature", featureName)
+	}
+
+	revision.Revision = last.Revision + 1
+
+	if err := tx.Create(&revision).Error; err != nil {
+		tx.Rollback()
+
+		return 0, errors.WrapIfWithDetails(err, "could not create feature revision", "clusterID", clusterID, "feature", featureName)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return 0, errors.WrapIfWithDetails(err, "could not create feature revision", "clusterID", clusterID, "feature", featureName)
+	}
+
+	return revision.Revision, nil
+}
+
+// UpdateFeatureRevisionStatus updates the (resulting) status of the feature revision
+func (r *gormFeatureRepository) UpdateFeatureRevisionStatus(ctx context.Context, clusterID uint, featureName string, revision uint, status string) error {
+	model := clusterFeatureRevisionModel{ClusterID: clusterID, FeatureName: featureName, Revision: revision}
+
+	result := r.db.Model(&clusterFeatureRevisionModel{}).Where(&model).Update("status", status)
+	if result.Error != nil {
+		return errors.WrapIfWithDetails(result.Error, "could not update feature revision status", "feature", featureName, "revision", revision)
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.NewWithDetails("feature revision not found", "feature", featureName, "revision", revision)
+	}
+
+	return nil
+}
+
+// GetFeatureRevisions retrieves the revisions of the feature ordered by revision number
+func (r *gormFeatureRepository) GetFeatureRevisions(ctx context.Context, clusterID uint, featureName string) ([]clusterfeature.FeatureRevision, error) {
+	var models []clusterFeatureRevisionModel
+
+	err := r.db.Where(&clusterFeatureRevisionModel{ClusterID: clusterID, FeatureName: featureName}).Order("revision").Find(&models).Error
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "could not retrieve feature revisions", "clusterID", clusterID, "feature", featureName)
+	}
+
+	revisions := make([]clusterfeature.FeatureRevision, len(models))
+	for i, model := range models {
+		revisions[i] = r.modelToFeatureRevision(model)
+	}
+
+	return revisions, nil
+}
+
+// GetFeatureRevision retrieves a feature revision by its revision number.
+// Returns (nil, nil) in case the revision is not found
+func (r *gormFeatureRepository) GetFeatureRevision(ctx context.Context, clusterID uint, featureName string, revision uint) (*clusterfeature.FeatureRevision, error) {
+	var model clusterFeatureRevisionModel
+
+	err := r.db.Where(&clusterFeatureRevisionModel{ClusterID: clusterID, FeatureName: featureName, Revision: revision}).First(&model).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "could not retrieve feature revision", "feature", featureName, "revision", revision)
+	}
+
+	featureRevision := r.modelToFeatureRevision(model)
+
+	return &featureRevision, nil
+}
+
+func (r *gormFeatureRepository) modelToFeatureRevision(model clusterFeatureRevisionModel) clusterfeature.FeatureRevision {
+	return clusterfeature.FeatureRevision{
+		Revision:  model.Revision,
+		Action:    model.Action,
+		Spec:      model.Spec,
+		Status:    model.Status,
+		CreatedBy: model.CreatedBy,
+		CreatedAt: model.CreatedAt,
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterfeatureadapter
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/cluster"
+	"github.com/banzaicloud/pipeline/internal/clusterfeature/features"
+)
+
+// kubernetesSecretInstaller is an adapter installing secrets through the core cluster layer.
+type kubernetesSecretInstaller struct {
+	clusterGetter ClusterGetter
+}
+
+// NewKubernetesSecretInstaller returns a new KubernetesSecretInstaller instance.
+func NewKubernetesSecretInstaller(getter ClusterGetter) features.KubernetesSecretInstaller {
+	return &kubernetesSecretInstaller{
+		clusterGetter: getter,
+	}
+}
+
+func (s *kubernetesSecretInstaller) InstallSecret(ctx context.Context, clusterID uint, namespace string, secretName string, values map[string]string) error {
+	c, err := s.clusterGetter.GetClusterByIDOnly(ctx, clusterID)
+	if err != nil {
+
+		return errors.WrapIfWithDetails(err, "failed to retrieve cluster", "clusterId", clusterID)
+	}
+
+	spec := make(map[string]cluster.InstallSecretRequestSpecItem, len(values))
+	for key, value := range values {
+		spec[key] = cluster.InstallSecretRequestSpecItem{Value: value}
+	}
+
+	_, err = cluster.InstallSecret(c, secretName, cluster.InstallSecretRequest{
+		Namespace: namespace,
+		Spec:      spec,
+		Update:    true,
+	})
+	if err != nil {
+
+		return errors.WrapIfWithDetails(err, "failed to install secret", "clusterId", clusterID, "secret", secretName)
+	}
+
+	return nil
+}
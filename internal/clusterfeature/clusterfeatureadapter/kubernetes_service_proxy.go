@@ -0,0 +1,72 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterfeatureadapter
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/clusterfeature/features"
+	"github.com/banzaicloud/pipeline/pkg/k8sclient"
+)
+
+// kubernetesServiceProxy is an adapter calling cluster services through the Kubernetes API server.
+type kubernetesServiceProxy struct {
+	clusterGetter ClusterGetter
+}
+
+// NewKubernetesServiceProxy returns a new KubernetesServiceProxy instance.
+func NewKubernetesServiceProxy(getter ClusterGetter) features.KubernetesServiceProxy {
+	return &kubernetesServiceProxy{
+		clusterGetter: getter,
+	}
+}
+
+func (p *kubernetesServiceProxy) ProxyGet(
+	ctx context.Context,
+	clusterID uint,
+	namespace string,
+	service string,
+	port string,
+	path string,
+	params map[string]string,
+) ([]byte, error) {
+	c, err := p.clusterGetter.GetClusterByIDOnly(ctx, clusterID)
+	if err != nil {
+
+		return nil, errors.WrapIfWithDetails(err, "failed to retrieve cluster", "clusterId", clusterID)
+	}
+
+	kubeConfig, err := c.GetK8sConfig()
+	if err != nil {
+
+		return nil, errors.WrapIfWithDetails(err, "failed to get cluster config", "clusterId", clusterID)
+	}
+
+	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	if err != nil {
+
+		return nil, errors.WrapIfWithDetails(err, "failed to create client from kubeconfig", "clusterId", clusterID)
+	}
+
+	body, err := client.CoreV1().Services(namespace).ProxyGet("http", service, port, path, params).DoRaw()
+	if err != nil {
+
+		return nil, errors.WrapIfWithDetails(err, "failed to call service", "clusterId", clusterID, "service", service, "path", path)
+	}
+
+	return body, nil
+}
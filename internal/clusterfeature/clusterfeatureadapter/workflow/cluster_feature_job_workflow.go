@@ -49,6 +49,7 @@ type ClusterFeatureJobWorkflowInput struct {
 type ClusterFeatureJobSignalInput struct {
 	Action        string
 	FeatureSpec   clusterfeature.FeatureSpec
+	Revision      uint
 	RetryInterval time.Duration
 }
 
@@ -171,16 +172,26 @@ func executeActivity(ctx workflow.Context, activityName string, activityInput in
 
 func executeJob(ctx workflow.Context, workflowInput ClusterFeatureJobWorkflowInput, signalInput ClusterFeatureJobSignalInput, jobsChannel workflow.Channel) error {
 	for {
+		revision := signalInput.Revision
+
 		activityName, activityInput, err := getActivity(workflowInput, signalInput)
 		if err != nil {
+			setClusterFeatureRevisionStatus(ctx, workflowInput, revision, clusterfeature.FeatureRevisionStatusFailed)
 			return err
 		}
 
 		newJob, err := executeActivity(ctx, activityName, activityInput, jobsChannel, &signalInput)
 		if newJob {
+			setClusterFeatureRevisionStatus(ctx, workflowInput, revision, clusterfeature.FeatureRevisionStatusSuperseded)
 			continue
 		}
 
+		if err != nil {
+			setClusterFeatureRevisionStatus(ctx, workflowInput, revision, clusterfeature.FeatureRevisionStatusFailed)
+		} else {
+			setClusterFeatureRevisionStatus(ctx, workflowInput, revision, clusterfeature.FeatureRevisionStatusSucceeded)
+		}
+
 		return err
 	}
 }
@@ -203,6 +214,25 @@ func setClusterFeatureSpec(ctx workflow.Context, input ClusterFeatureJobWorkflow
 	return workflow.ExecuteActivity(ctx, ClusterFeatureSetSpecActivityName, activityInput).Get(ctx, nil)
 }
 
+// setClusterFeatureRevisionStatus records the result of a job. It's best effort, failures are only logged.
+func setClusterFeatureRevisionStatus(ctx workflow.Context, input ClusterFeatureJobWorkflowInput, revision uint, status string) {
+	// jobs dispatched before revisions were recorded have no revision
+	if revision == 0 {
+		return
+	}
+
+	activityInput := ClusterFeatureSetRevisionStatusActivityInput{
+		ClusterID:   input.ClusterID,
+		FeatureName: input.FeatureName,
+		Revision:    revision,
+		Status:      status,
+	}
+	err := workflow.ExecuteActivity(ctx, ClusterFeatureSetRevisionStatusActivityName, activityInput).Get(ctx, nil)
+	if err != nil {
+		workflow.GetLogger(ctx).Error("failed to set cluster feature revision status", zap.Error(err), zap.Uint("revision", revision))
+	}
+}
+
 func deleteClusterFeature(ctx workflow.Context, input ClusterFeatureJobWorkflowInput) error {
 	activityInput := ClusterFeatureDeleteActivityInput{
 		ClusterID:   input.ClusterID,
@@ -0,0 +1,44 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow
+
+import (
+	"context"
+
+	"github.com/banzaicloud/pipeline/internal/clusterfeature"
+)
+
+const ClusterFeatureSetRevisionStatusActivityName = "cluster-feature-set-revision-status"
+
+type ClusterFeatureSetRevisionStatusActivityInput struct {
+	ClusterID   uint
+	FeatureName string
+	Revision    uint
+	Status      string
+}
+
+type ClusterFeatureSetRevisionStatusActivity struct {
+	features clusterfeature.FeatureRepository
+}
+
+func MakeClusterFeatureSetRevisionStatusActivity(features clusterfeature.FeatureRepository) ClusterFeatureSetRevisionStatusActivity {
+	return ClusterFeatureSetRevisionStatusActivity{
+		features: features,
+	}
+}
+
+func (a ClusterFeatureSetRevisionStatusActivity) Execute(ctx context.Context, input ClusterFeatureSetRevisionStatusActivityInput) error {
+	return a.features.UpdateFeatureRevisionStatus(ctx, input.ClusterID, input.FeatureName, input.Revision, input.Status)
+}
@@ -22,9 +22,10 @@ import (
 
 // DummyFeatureService is used for testing purposes.
 type DummyFeatureService struct {
-	FeatureList    []clusterfeature.Feature
-	FeatureDetails clusterfeature.Feature
-	Err            error
+	FeatureList      []clusterfeature.Feature
+	FeatureDetails   clusterfeature.Feature
+	FeatureRevisions []clusterfeature.FeatureRevision
+	Err              error
 }
 
 func (s *DummyFeatureService) List(ctx context.Context, clusterID uint) ([]clusterfeature.Feature, error) {
@@ -54,3 +55,15 @@ func (s *DummyFeatureService) Deactivate(ctx context.Context, clusterID uint, fe
 func (s *DummyFeatureService) Update(ctx context.Context, clusterID uint, featureName string, spec map[string]interface{}) error {
 	return s.Err
 }
+
+func (s *DummyFeatureService) Revisions(ctx context.Context, clusterID uint, featureName string) ([]clusterfeature.FeatureRevision, error) {
+	if s.Err != nil {
+		return nil, s.Err
+	}
+
+	return s.FeatureRevisions, nil
+}
+
+func (s *DummyFeatureService) ApplyRevision(ctx context.Context, clusterID uint, featureName string, revision uint) error {
+	return s.Err
+}
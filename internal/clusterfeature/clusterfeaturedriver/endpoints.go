@@ -16,6 +16,7 @@ package clusterfeaturedriver
 
 import (
 	"context"
+	"time"
 
 	"github.com/banzaicloud/pipeline/client"
 	"github.com/go-kit/kit/endpoint"
@@ -40,28 +41,38 @@ type FeatureService interface {
 
 	// Update updates a feature.
 	Update(ctx context.Context, clusterID uint, featureName string, spec map[string]interface{}) error
+
+	// Revisions returns the revisions of a feature (latest first).
+	Revisions(ctx context.Context, clusterID uint, featureName string) ([]clusterfeature.FeatureRevision, error)
+
+	// ApplyRevision applies the spec of a previous feature revision.
+	ApplyRevision(ctx context.Context, clusterID uint, featureName string, revision uint) error
 }
 
 // Endpoints collects all of the endpoints that compose the cluster feature service.
 // It's meant to be used as a helper struct, to collect all of the endpoints into a
 // single parameter.
 type Endpoints struct {
-	List       endpoint.Endpoint
-	Details    endpoint.Endpoint
-	Activate   endpoint.Endpoint
-	Deactivate endpoint.Endpoint
-	Update     endpoint.Endpoint
+	List          endpoint.Endpoint
+	Details       endpoint.Endpoint
+	Activate      endpoint.Endpoint
+	Deactivate    endpoint.Endpoint
+	Update        endpoint.Endpoint
+	Revisions     endpoint.Endpoint
+	ApplyRevision endpoint.Endpoint
 }
 
 // MakeEndpoints returns an Endpoints struct where each endpoint invokes
 // the corresponding method on the provided service.
 func MakeEndpoints(s FeatureService) Endpoints {
 	return Endpoints{
-		List:       kitoc.TraceEndpoint("clusterfeature.List")(MakeListEndpoint(s)),
-		Details:    kitoc.TraceEndpoint("clusterfeature.Details")(MakeDetailsEndpoint(s)),
-		Activate:   kitoc.TraceEndpoint("clusterfeature.Activate")(MakeActivateEndpoint(s)),
-		Deactivate: kitoc.TraceEndpoint("clusterfeature.Deactivate")(MakeDeactivateEndpoint(s)),
-		Update:     kitoc.TraceEndpoint("clusterfeature.Update")(MakeUpdateEndpoint(s)),
+		List:          kitoc.TraceEndpoint("clusterfeature.List")(MakeListEndpoint(s)),
+		Details:       kitoc.TraceEndpoint("clusterfeature.Details")(MakeDetailsEndpoint(s)),
+		Activate:      kitoc.TraceEndpoint("clusterfeature.Activate")(MakeActivateEndpoint(s)),
+		Deactivate:    kitoc.TraceEndpoint("clusterfeature.Deactivate")(MakeDeactivateEndpoint(s)),
+		Update:        kitoc.TraceEndpoint("clusterfeature.Update")(MakeUpdateEndpoint(s)),
+		Revisions:     kitoc.TraceEndpoint("clusterfeature.Revisions")(MakeRevisionsEndpoint(s)),
+		ApplyRevision: kitoc.TraceEndpoint("clusterfeature.ApplyRevision")(MakeApplyRevisionEndpoint(s)),
 	}
 }
 
@@ -146,6 +157,67 @@ func MakeUpdateEndpoint(s FeatureService) endpoint.Endpoint {
 	}
 }
 
+type ClusterFeatureRevisionsRequest struct {
+	ClusterID   uint
+	FeatureName string
+}
+
+// MakeRevisionsEndpoint returns an endpoint for the matching method of the underlying service.
+func MakeRevisionsEndpoint(s FeatureService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ClusterFeatureRevisionsRequest)
+		result, err := s.Revisions(ctx, req.ClusterID, req.FeatureName)
+		if err != nil {
+
+			return nil, err
+		}
+
+		return transformRevisions(result), nil
+	}
+}
+
+type ApplyClusterFeatureRevisionRequest struct {
+	ClusterID   uint
+	FeatureName string
+	Revision    uint
+}
+
+// MakeApplyRevisionEndpoint returns an endpoint for the matching method of the underlying service.
+func MakeApplyRevisionEndpoint(s FeatureService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ApplyClusterFeatureRevisionRequest)
+		err := s.ApplyRevision(ctx, req.ClusterID, req.FeatureName, req.Revision)
+		return nil, err
+	}
+}
+
+// ClusterFeatureRevision describes a recorded change of a cluster feature.
+type ClusterFeatureRevision struct {
+	Revision  uint                   `json:"revision"`
+	Action    string                 `json:"action"`
+	Spec      map[string]interface{} `json:"spec"`
+	Status    string                 `json:"status"`
+	CreatedBy uint                   `json:"createdBy,omitempty"`
+	CreatedAt time.Time              `json:"createdAt"`
+}
+
+func transformRevisions(revisions []clusterfeature.FeatureRevision) []ClusterFeatureRevision {
+	featureRevisions := make([]ClusterFeatureRevision, len(revisions))
+
+	for i, r := range revisions {
+		featureRevisions[i] = ClusterFeatureRevision{
+			Revision:  r.Revision,
+			Action:    r.Action,
+			Spec:      r.Spec,
+			Status:    r.Status,
+			CreatedBy: r.CreatedBy,
+			CreatedAt: r.CreatedAt,
+		}
+	}
+
+	return featureRevisions
+}
+
 func transformDetails(feature clusterfeature.Feature) client.ClusterFeatureDetails {
 	return client.ClusterFeatureDetails{
 		Spec:   feature.Spec,
@@ -194,3 +194,73 @@ func TestMakeUpdateEndpoint(t *testing.T) {
 
 	featureService.AssertExpectations(t)
 }
+
+func TestMakeRevisionsEndpoint(t *testing.T) {
+	featureService := &MockFeatureService{}
+
+	ctx := context.Background()
+	clusterID := uint(1)
+	featureName := "example"
+
+	revisions := []clusterfeature.FeatureRevision{
+		{
+			Revision: 1,
+			Action:   clusterfeature.FeatureRevisionActionActivate,
+			Spec: map[string]interface{}{
+				"hello": "world",
+			},
+			Status: clusterfeature.FeatureRevisionStatusPending,
+		},
+	}
+
+	featureService.On("Revisions", ctx, clusterID, featureName).Return(revisions, nil)
+
+	e := MakeRevisionsEndpoint(featureService)
+
+	req := ClusterFeatureRevisionsRequest{
+		ClusterID:   clusterID,
+		FeatureName: featureName,
+	}
+
+	result, err := e(ctx, req)
+
+	require.NoError(t, err)
+	assert.Equal(t, []ClusterFeatureRevision{
+		{
+			Revision: 1,
+			Action:   "activate",
+			Spec: map[string]interface{}{
+				"hello": "world",
+			},
+			Status: "PENDING",
+		},
+	}, result)
+
+	featureService.AssertExpectations(t)
+}
+
+func TestMakeApplyRevisionEndpoint(t *testing.T) {
+	featureService := &MockFeatureService{}
+
+	ctx := context.Background()
+	clusterID := uint(1)
+	featureName := "example"
+	revision := uint(2)
+
+	featureService.On("ApplyRevision", ctx, clusterID, featureName, revision).Return(nil)
+
+	e := MakeApplyRevisionEndpoint(featureService)
+
+	req := ApplyClusterFeatureRevisionRequest{
+		ClusterID:   clusterID,
+		FeatureName: featureName,
+		Revision:    revision,
+	}
+
+	result, err := e(ctx, req)
+
+	require.NoError(t, err)
+	assert.Nil(t, result)
+
+	featureService.AssertExpectations(t)
+}
@@ -85,6 +85,43 @@ func (_m *MockFeatureService) List(ctx context.Context, clusterID uint) ([]clust
 	return r0, r1
 }
 
+// ApplyRevision provides a mock function with given fields: ctx, clusterID, featureName, revision
+func (_m *MockFeatureService) ApplyRevision(ctx context.Context, clusterID uint, featureName string, revision uint) error {
+	ret := _m.Called(ctx, clusterID, featureName, revision)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string, uint) error); ok {
+		r0 = rf(ctx, clusterID, featureName, revision)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Revisions provides a mock function with given fields: ctx, clusterID, featureName
+func (_m *MockFeatureService) Revisions(ctx context.Context, clusterID uint, featureName string) ([]clusterfeature.FeatureRevision, error) {
+	ret := _m.Called(ctx, clusterID, featureName)
+
+	var r0 []clusterfeature.FeatureRevision
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string) []clusterfeature.FeatureRevision); ok {
+		r0 = rf(ctx, clusterID, featureName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]clusterfeature.FeatureRevision)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string) error); ok {
+		r1 = rf(ctx, clusterID, featureName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, clusterID, featureName, spec
 func (_m *MockFeatureService) Update(ctx context.Context, clusterID uint, featureName string, spec map[string]interface{}) error {
 	ret := _m.Called(ctx, clusterID, featureName, spec)
@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"emperror.dev/emperror"
 	"emperror.dev/errors"
@@ -33,11 +34,13 @@ import (
 // It's meant to be used as a helper struct, to collect all of the handlers into a
 // single parameter.
 type HTTPHandlers struct {
-	List       http.Handler
-	Details    http.Handler
-	Activate   http.Handler
-	Deactivate http.Handler
-	Update     http.Handler
+	List          http.Handler
+	Details       http.Handler
+	Activate      http.Handler
+	Deactivate    http.Handler
+	Update        http.Handler
+	Revisions     http.Handler
+	ApplyRevision http.Handler
 }
 
 // MakeHTTPHandlers returns an HTTP Handlers struct where each handler invokes
@@ -79,6 +82,18 @@ func MakeHTTPHandlers(endpoints Endpoints, errorHandler emperror.Handler) HTTPHa
 			encodeUpdateClusterFeatureResponse,
 			options...,
 		),
+		Revisions: httptransport.NewServer(
+			endpoints.Revisions,
+			decodeClusterFeatureRevisionsRequest,
+			encodeClusterFeatureRevisionsResponse,
+			options...,
+		),
+		ApplyRevision: httptransport.NewServer(
+			endpoints.ApplyRevision,
+			decodeApplyClusterFeatureRevisionRequest,
+			encodeApplyClusterFeatureRevisionResponse,
+			options...,
+		),
 	}
 }
 
@@ -89,7 +104,8 @@ func encodeHTTPError(_ context.Context, err error, w http.ResponseWriter) {
 	var notFound interface{ NotFound() bool }
 
 	switch {
-	case errors.As(err, &clusterfeature.UnknownFeatureError{}), errors.As(err, &clusterfeature.FeatureNotFoundError{}):
+	case errors.As(err, &clusterfeature.UnknownFeatureError{}), errors.As(err, &clusterfeature.FeatureNotFoundError{}),
+		errors.As(err, &clusterfeature.FeatureRevisionNotFoundError{}):
 		problem = problems.NewDetailedProblem(http.StatusNotFound, err.Error())
 	case errors.As(err, &clusterfeature.FeatureAlreadyActivatedError{}), errors.As(err, &clusterfeature.FeatureNotActiveError{}):
 		problem = problems.NewDetailedProblem(http.StatusConflict, err.Error())
@@ -234,6 +250,65 @@ func encodeUpdateClusterFeatureResponse(_ context.Context, w http.ResponseWriter
 	return nil
 }
 
+func decodeClusterFeatureRevisionsRequest(ctx context.Context, _ *http.Request) (interface{}, error) {
+	clusterID, ok := ctxutil.ClusterID(ctx)
+	if !ok {
+		// TODO: better error handling?
+		return nil, errors.New("cluster ID not found in the context")
+	}
+
+	params, _ := ctxutil.Params(ctx)
+	featureName := params["featureName"]
+
+	return ClusterFeatureRevisionsRequest{
+		ClusterID:   clusterID,
+		FeatureName: featureName,
+	}, nil
+}
+
+func encodeClusterFeatureRevisionsResponse(_ context.Context, w http.ResponseWriter, resp interface{}) error {
+	w.Header().Set("Content-Type", "application/json")
+
+	return json.NewEncoder(w).Encode(resp)
+}
+
+func decodeApplyClusterFeatureRevisionRequest(ctx context.Context, _ *http.Request) (interface{}, error) {
+	clusterID, ok := ctxutil.ClusterID(ctx)
+	if !ok {
+		// TODO: better error handling?
+		return nil, errors.New("cluster ID not found in the context")
+	}
+
+	params, _ := ctxutil.Params(ctx)
+	featureName := params["featureName"]
+
+	revision, err := strconv.ParseUint(params["revision"], 10, 32)
+	if err != nil || revision == 0 {
+		return nil, invalidRevisionError{revision: params["revision"]}
+	}
+
+	return ApplyClusterFeatureRevisionRequest{
+		ClusterID:   clusterID,
+		FeatureName: featureName,
+		Revision:    uint(revision),
+	}, nil
+}
+
+func encodeApplyClusterFeatureRevisionResponse(_ context.Context, w http.ResponseWriter, _ interface{}) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+
+	return nil
+}
+
+type invalidRevisionError struct {
+	revision string
+}
+
+func (invalidRevisionError) Error() string            { return "invalid revision" }
+func (e invalidRevisionError) Details() []interface{} { return []interface{}{"revision", e.revision} }
+func (invalidRevisionError) BadRequest() bool         { return true }
+
 func decodeRequestBody(req *http.Request, result interface{}) error {
 	if err := json.NewDecoder(req.Body).Decode(result); err != nil {
 		return invalidRequestBodyError{errors.WrapIf(err, "failed to decode request body")}
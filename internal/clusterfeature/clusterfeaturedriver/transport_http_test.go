@@ -20,6 +20,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"emperror.dev/emperror"
 	"github.com/banzaicloud/pipeline/client"
@@ -249,3 +250,103 @@ func TestMakeHTTPHandlers_Update(t *testing.T) {
 
 	assert.Equal(t, resp.StatusCode, http.StatusAccepted)
 }
+
+func TestMakeHTTPHandlers_Revisions(t *testing.T) {
+	createdAt := time.Date(2019, 8, 20, 10, 0, 0, 0, time.UTC)
+
+	featureService := &DummyFeatureService{
+		FeatureRevisions: []clusterfeature.FeatureRevision{
+			{
+				Revision: 1,
+				Action:   clusterfeature.FeatureRevisionActionActivate,
+				Spec: map[string]interface{}{
+					"hello": "world",
+				},
+				Status:    clusterfeature.FeatureRevisionStatusSucceeded,
+				CreatedBy: 1,
+				CreatedAt: createdAt,
+			},
+		},
+	}
+
+	handler := MakeHTTPHandlers(MakeEndpoints(featureService), emperror.NewNoopHandler()).Revisions
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(ctxutil.WithParams(
+			ctxutil.WithClusterID(r.Context(), 1),
+			map[string]string{
+				"featureName": "example",
+			},
+		))
+
+		handler.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+
+	var revisions []ClusterFeatureRevision
+
+	err = json.NewDecoder(resp.Body).Decode(&revisions)
+	require.NoError(t, err)
+
+	assert.Equal(t, []ClusterFeatureRevision{
+		{
+			Revision: 1,
+			Action:   "activate",
+			Spec: map[string]interface{}{
+				"hello": "world",
+			},
+			Status:    "SUCCEEDED",
+			CreatedBy: 1,
+			CreatedAt: createdAt,
+		},
+	}, revisions)
+}
+
+func TestMakeHTTPHandlers_ApplyRevision(t *testing.T) {
+	featureService := &DummyFeatureService{}
+
+	handler := MakeHTTPHandlers(MakeEndpoints(featureService), emperror.NewNoopHandler()).ApplyRevision
+
+	tests := map[string]struct {
+		revision   string
+		statusCode int
+	}{
+		"valid revision":   {revision: "2", statusCode: http.StatusAccepted},
+		"invalid revision": {revision: "two", statusCode: http.StatusBadRequest},
+		"zero revision":    {revision: "0", statusCode: http.StatusBadRequest},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				r = r.WithContext(ctxutil.WithParams(
+					ctxutil.WithClusterID(r.Context(), 1),
+					map[string]string{
+						"featureName": "example",
+						"revision":    test.revision,
+					},
+				))
+
+				handler.ServeHTTP(w, r)
+			}))
+			defer ts.Close()
+
+			req, err := http.NewRequest(http.MethodPost, ts.URL, nil)
+			require.NoError(t, err)
+
+			resp, err := ts.Client().Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, resp.StatusCode, test.statusCode)
+		})
+	}
+}
@@ -136,6 +136,19 @@ func (m *syncFeatureManager) Activate(ctx context.Context, clusterID uint, spec
 		}
 	}
 
+	revision, err := m.featureRepository.CreateFeatureRevision(ctx, clusterID, m.Name(), FeatureRevisionActionActivate, spec)
+	if err != nil {
+		const msg = "failed to create feature revision in repository"
+		logger.Debug(msg)
+
+		// Deletion is best effort here, activation cannot be recorded anyway
+		if err := m.featureRepository.DeleteFeature(ctx, clusterID, m.Name()); err != nil {
+			logger.Error("failed to delete feature from repository", map[string]interface{}{"error": err.Error()})
+		}
+
+		return errors.WrapIf(err, msg)
+	}
+
 	if err := m.FeatureManager.Activate(ctx, clusterID, spec); err != nil {
 		const msg = "cluster feature activation failed"
 		logger.Debug(msg)
@@ -145,9 +158,13 @@ func (m *syncFeatureManager) Activate(ctx context.Context, clusterID uint, spec
 			logger.Error("failed to delete feature from repository", map[string]interface{}{"error": err.Error()})
 		}
 
+		m.setRevisionStatus(ctx, clusterID, revision, FeatureRevisionStatusFailed)
+
 		return errors.WrapIf(err, msg)
 	}
 
+	m.setRevisionStatus(ctx, clusterID, revision, FeatureRevisionStatusSucceeded)
+
 	if _, err := m.featureRepository.UpdateFeatureStatus(ctx, clusterID, m.Name(), FeatureStatusActive); err != nil {
 		const msg = "failed to update feature status"
 		logger.Debug(msg)
@@ -173,6 +190,8 @@ func (e FeatureNotActiveError) Details() []interface{} {
 func (m *syncFeatureManager) Deactivate(ctx context.Context, clusterID uint) error {
 	logger := m.logger.WithContext(ctx).WithFields(map[string]interface{}{"clusterId": clusterID, "feature": m.Name()})
 
+	var spec FeatureSpec
+
 	// This block should be executed atomically. UpdateFeatureStatus won't fail if the feature's status changes concurrently.
 	{
 		feature, err := m.featureRepository.GetFeature(ctx, clusterID, m.Name())
@@ -192,6 +211,20 @@ func (m *syncFeatureManager) Deactivate(ctx context.Context, clusterID uint) err
 			logger.Debug(msg)
 			return errors.WrapIf(err, msg)
 		}
+
+		spec = feature.Spec
+	}
+
+	revision, err := m.featureRepository.CreateFeatureRevision(ctx, clusterID, m.Name(), FeatureRevisionActionDeactivate, spec)
+	if err != nil {
+		const msg = "failed to create feature revision in repository"
+		logger.Debug(msg)
+
+		if _, err := m.featureRepository.UpdateFeatureStatus(ctx, clusterID, m.Name(), FeatureStatusActive); err != nil {
+			logger.Error("failed to update feature status", map[string]interface{}{"error": err.Error()})
+		}
+
+		return errors.WrapIf(err, msg)
 	}
 
 	if err := m.FeatureManager.Deactivate(ctx, clusterID); err != nil {
@@ -203,9 +236,13 @@ func (m *syncFeatureManager) Deactivate(ctx context.Context, clusterID uint) err
 			logger.Error("failed to delete feature from repository", map[string]interface{}{"error": err.Error()})
 		}
 
+		m.setRevisionStatus(ctx, clusterID, revision, FeatureRevisionStatusFailed)
+
 		return errors.WrapIf(err, msg)
 	}
 
+	m.setRevisionStatus(ctx, clusterID, revision, FeatureRevisionStatusSucceeded)
+
 	if err := m.featureRepository.DeleteFeature(ctx, clusterID, m.Name()); err != nil {
 		const msg = "failed to delete feature from repository"
 		logger.Debug(msg)
@@ -239,6 +276,18 @@ func (m *syncFeatureManager) Update(ctx context.Context, clusterID uint, spec Fe
 		}
 	}
 
+	revision, err := m.featureRepository.CreateFeatureRevision(ctx, clusterID, m.Name(), FeatureRevisionActionUpdate, spec)
+	if err != nil {
+		const msg = "failed to create feature revision in repository"
+		logger.Debug(msg)
+
+		if _, err := m.featureRepository.UpdateFeatureStatus(ctx, clusterID, m.Name(), FeatureStatusActive); err != nil {
+			logger.Error("failed to update feature status", map[string]interface{}{"error": err.Error()})
+		}
+
+		return errors.WrapIf(err, msg)
+	}
+
 	if err := m.FeatureManager.Update(ctx, clusterID, spec); err != nil {
 		const msg = "cluster feature update failed"
 		logger.Debug(msg)
@@ -248,9 +297,13 @@ func (m *syncFeatureManager) Update(ctx context.Context, clusterID uint, spec Fe
 			logger.Error("failed to update feature status", map[string]interface{}{"error": err.Error()})
 		}
 
+		m.setRevisionStatus(ctx, clusterID, revision, FeatureRevisionStatusFailed)
+
 		return errors.WrapIf(err, msg)
 	}
 
+	m.setRevisionStatus(ctx, clusterID, revision, FeatureRevisionStatusSucceeded)
+
 	if _, err := m.featureRepository.UpdateFeatureSpec(ctx, clusterID, m.Name(), spec); err != nil {
 		const msg = "failed to update feature spec"
 		logger.Debug(msg)
@@ -265,3 +318,14 @@ func (m *syncFeatureManager) Update(ctx context.Context, clusterID uint, spec Fe
 
 	return nil
 }
+
+// setRevisionStatus records the result of a feature revision.
+// It's best effort: the feature operation is already done at this point.
+func (m *syncFeatureManager) setRevisionStatus(ctx context.Context, clusterID uint, revision uint, status string) {
+	if err := m.featureRepository.UpdateFeatureRevisionStatus(ctx, clusterID, m.Name(), revision, status); err != nil {
+		m.logger.WithContext(ctx).Error(
+			"failed to update feature revision status",
+			map[string]interface{}{"clusterId": clusterID, "feature": m.Name(), "revision": revision, "error": err.Error()},
+		)
+	}
+}
@@ -16,6 +16,8 @@ package features
 
 import (
 	"context"
+
+	pkgHelm "github.com/banzaicloud/pipeline/pkg/helm"
 )
 
 // HelmService provides an interface for using Helm on a specific cluster.
@@ -44,4 +46,10 @@ type HelmService interface {
 
 	// DeleteDeployment deletes a deployment from a specific cluster.
 	DeleteDeployment(ctx context.Context, clusterID uint, releaseName string) error
+
+	// GetDeploymentHistory returns the revisions of a deployment on a specific cluster (latest first).
+	GetDeploymentHistory(ctx context.Context, clusterID uint, releaseName string, max int32) (*pkgHelm.GetDeploymentHistoryResponse, error)
+
+	// RollbackDeployment rolls back a deployment on a specific cluster to the given revision.
+	RollbackDeployment(ctx context.Context, clusterID uint, releaseName string, version int32, wait bool) error
 }
@@ -15,9 +15,23 @@
 package features
 
 import (
+	"context"
+
 	"github.com/banzaicloud/pipeline/internal/common"
 )
 
 // SecretStore is a common interface for various parts of the application
 // to read secrets from the platform's secret store.
 type SecretStore = common.SecretStore
+
+// KubernetesSecretInstaller installs secrets to a specific cluster.
+type KubernetesSecretInstaller interface {
+	// InstallSecret creates or updates a Kubernetes secret with the given values in a namespace of the cluster.
+	InstallSecret(ctx context.Context, clusterID uint, namespace string, secretName string, values map[string]string) error
+}
+
+// KubernetesServiceProxy calls HTTP endpoints of services running on a specific cluster.
+type KubernetesServiceProxy interface {
+	// ProxyGet sends a GET request to a service of the cluster through the Kubernetes API server proxy.
+	ProxyGet(ctx context.Context, clusterID uint, namespace string, service string, port string, path string, params map[string]string) ([]byte, error)
+}
@@ -0,0 +1,525 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"emperror.dev/errors"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/clustersecret"
+	"github.com/banzaicloud/pipeline/internal/clusterfeature"
+	"github.com/banzaicloud/pipeline/internal/clusterfeature/clusterfeatureadapter"
+	"github.com/banzaicloud/pipeline/internal/clusterfeature/features"
+	"github.com/banzaicloud/pipeline/internal/common"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/pkg/providers/azure"
+	azureObjectstore "github.com/banzaicloud/pipeline/pkg/providers/azure/objectstore"
+	pkgSecret "github.com/banzaicloud/pipeline/pkg/secret"
+)
+
+const (
+	featureName = "logging"
+
+	// hardcoded values for logging feature
+	loggingOperatorChartVersion = "2.3.0"
+
+	loggingOperatorChartName = "banzaicloud-stable/logging-operator"
+
+	loggingChartName = "banzaicloud-stable/logging-operator-logging"
+
+	loggingNamespace = "pipeline-system"
+
+	loggingOperatorRelease = "logging-operator"
+
+	loggingRelease = "logging-operator-logging"
+
+	tlsSecretName = "logging-tls"
+
+	fluentdTLSSecretName = "logging-fluentd-tls"
+
+	fluentbitTLSSecretName = "logging-fluentbit-tls"
+)
+
+// loggingAlreadyInstalledError is returned when logging was installed on the cluster outside of the feature.
+type loggingAlreadyInstalledError struct {
+	clusterID uint
+}
+
+func (loggingAlreadyInstalledError) Error() string {
+	return "logging is already installed on the cluster"
+}
+
+func (e loggingAlreadyInstalledError) Details() []interface{} {
+	return []interface{}{"clusterId", e.clusterID}
+}
+
+func (loggingAlreadyInstalledError) BadRequest() bool {
+	return true
+}
+
+// ClusterSecretStore creates secrets bound to a cluster in the secret store.
+type ClusterSecretStore interface {
+	// EnsureSecretExists creates a secret for a cluster if it cannot be found and returns it's ID.
+	EnsureSecretExists(ctx context.Context, clusterID uint, secret clustersecret.SecretCreateRequest) (string, error)
+}
+
+// loggingFeatureManager synchronous feature manager
+type loggingFeatureManager struct {
+	featureRepository  clusterfeature.FeatureRepository
+	secretStore        features.SecretStore
+	clusterSecretStore ClusterSecretStore
+	secretInstaller    features.KubernetesSecretInstaller
+	clusterGetter      clusterfeatureadapter.ClusterGetter
+	clusterService     clusterfeature.ClusterService
+	helmService        features.HelmService
+
+	logger common.Logger
+}
+
+// NewLoggingFeatureManager builds a new feature manager component
+func NewLoggingFeatureManager(
+	featureRepository clusterfeature.FeatureRepository,
+	secretStore features.SecretStore,
+	clusterSecretStore ClusterSecretStore,
+	secretInstaller features.KubernetesSecretInstaller,
+	clusterService clusterfeature.ClusterService,
+	clusterGetter clusterfeatureadapter.ClusterGetter,
+	helmService features.HelmService,
+
+	logger common.Logger,
+) clusterfeature.FeatureManager {
+	return &loggingFeatureManager{
+		featureRepository:  featureRepository,
+		secretStore:        secretStore,
+		clusterSecretStore: clusterSecretStore,
+		secretInstaller:    secretInstaller,
+		clusterService:     clusterService,
+		clusterGetter:      clusterGetter,
+		helmService:        helmService,
+		logger:             logger,
+	}
+}
+
+func (m *loggingFeatureManager) Details(ctx context.Context, clusterID uint) (*clusterfeature.Feature, error) {
+	feature, err := m.featureRepository.GetFeature(ctx, clusterID, featureName)
+	if err != nil {
+
+		return nil, err
+	}
+
+	if feature == nil {
+
+		return nil, clusterfeature.FeatureNotFoundError{FeatureName: featureName}
+	}
+
+	return feature, nil
+}
+
+func (m *loggingFeatureManager) Name() string {
+	return featureName
+}
+
+func (m *loggingFeatureManager) Activate(ctx context.Context, clusterID uint, spec clusterfeature.FeatureSpec) error {
+	if err := m.clusterService.CheckClusterReady(ctx, clusterID); err != nil {
+		return err
+	}
+
+	ctx, err := m.ensureOrgIDInContext(ctx, clusterID)
+	if err != nil {
+
+		return err
+	}
+
+	logger := m.logger.WithContext(ctx).WithFields(map[string]interface{}{"cluster": clusterID, "feature": featureName})
+
+	boundSpec, err := bindFeatureSpec(spec)
+	if err != nil {
+
+		return err
+	}
+
+	commonCluster, err := m.clusterGetter.GetClusterByIDOnly(ctx, clusterID)
+	if err != nil {
+
+		return errors.WrapIf(err, "failed to get cluster")
+	}
+
+	// the feature cannot manage the logging stack installed by the cluster post hook
+	if commonCluster.GetLogging() {
+
+		return loggingAlreadyInstalledError{clusterID: clusterID}
+	}
+
+	operatorValues := operatorChartValues{
+		Tolerations: cluster.GetHeadNodeTolerations(),
+	}
+
+	headNodeAffinity := cluster.GetHeadNodeAffinity(commonCluster)
+	if headNodeAffinity != (v1.Affinity{}) {
+		operatorValues.Affinity = &headNodeAffinity
+	}
+
+	operatorValuesBytes, err := json.Marshal(operatorValues)
+	if err != nil {
+		logger.Debug("failed to marshal operator values")
+
+		return errors.WrapIf(err, "failed to decode operator values")
+	}
+
+	if err = m.helmService.InstallDeployment(
+		ctx,
+		clusterID,
+		loggingNamespace,
+		loggingOperatorChartName,
+		loggingOperatorRelease,
+		operatorValuesBytes,
+		loggingOperatorChartVersion,
+		true,
+	); err != nil {
+		return errors.WrapIf(err, "failed to deploy logging operator")
+	}
+
+	valuesBytes, err := m.processValues(ctx, clusterID, boundSpec)
+	if err != nil {
+		logger.Debug("failed to process logging values")
+
+		return errors.WrapIf(err, "failed to process logging values")
+	}
+
+	if err = m.helmService.InstallDeployment(
+		ctx,
+		clusterID,
+		loggingNamespace,
+		loggingChartName,
+		loggingRelease,
+		valuesBytes,
+		loggingOperatorChartVersion,
+		false,
+	); err != nil {
+		return errors.WrapIf(err, "failed to deploy feature")
+	}
+
+	if err := m.setClusterLogging(commonCluster, true); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *loggingFeatureManager) ValidateSpec(ctx context.Context, spec clusterfeature.FeatureSpec) error {
+	loggingSpec, err := bindFeatureSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	if err := loggingSpec.Validate(); err != nil {
+		return clusterfeature.InvalidFeatureSpecError{
+			FeatureName: featureName,
+			Problem:     err.Error(),
+		}
+	}
+
+	return nil
+}
+
+func (m *loggingFeatureManager) Deactivate(ctx context.Context, clusterID uint) error {
+	if err := m.clusterService.CheckClusterReady(ctx, clusterID); err != nil {
+		return err
+	}
+
+	ctx, err := m.ensureOrgIDInContext(ctx, clusterID)
+	if err != nil {
+
+		return err
+	}
+
+	logger := m.logger.WithContext(ctx).WithFields(map[string]interface{}{"cluster": clusterID, "feature": featureName})
+
+	if err := m.helmService.DeleteDeployment(ctx, clusterID, loggingRelease); err != nil {
+		logger.Info("failed to delete feature deployment")
+
+		return errors.WrapIf(err, "failed to uninstall feature")
+	}
+
+	if err := m.helmService.DeleteDeployment(ctx, clusterID, loggingOperatorRelease); err != nil {
+		logger.Info("failed to delete logging operator deployment")
+
+		return errors.WrapIf(err, "failed to uninstall logging operator")
+	}
+
+	commonCluster, err := m.clusterGetter.GetClusterByIDOnly(ctx, clusterID)
+	if err != nil {
+
+		return errors.WrapIf(err, "failed to get cluster")
+	}
+
+	return m.setClusterLogging(commonCluster, false)
+}
+
+func (m *loggingFeatureManager) Update(ctx context.Context, clusterID uint, spec clusterfeature.FeatureSpec) error {
+	if err := m.clusterService.CheckClusterReady(ctx, clusterID); err != nil {
+		return err
+	}
+
+	ctx, err := m.ensureOrgIDInContext(ctx, clusterID)
+	if err != nil {
+
+		return err
+	}
+
+	logger := m.logger.WithContext(ctx).WithFields(map[string]interface{}{"cluster": clusterID, "feature": featureName})
+
+	boundSpec, err := bindFeatureSpec(spec)
+	if err != nil {
+
+		return err
+	}
+
+	valuesBytes, err := m.processValues(ctx, clusterID, boundSpec)
+	if err != nil {
+		logger.Debug("failed to process logging values")
+
+		return errors.WrapIf(err, "failed to process logging values")
+	}
+
+	if _, err = m.featureRepository.UpdateFeatureSpec(ctx, clusterID, featureName, spec); err != nil {
+		logger.Debug("failed to update feature spec")
+
+		return err
+	}
+
+	if err = m.helmService.UpdateDeployment(ctx,
+		clusterID,
+		loggingNamespace,
+		loggingChartName,
+		loggingRelease,
+		valuesBytes,
+		loggingOperatorChartVersion); err != nil {
+		logger.Debug("failed to update")
+
+		return errors.WrapIf(err, "failed to update feature")
+	}
+
+	// feature status set back to active
+	if _, err = m.featureRepository.UpdateFeatureStatus(ctx, clusterID, featureName, clusterfeature.FeatureStatusActive); err != nil {
+		logger.Debug("failed to update feature status")
+
+		return err
+	}
+
+	logger.Info("successfully updated feature")
+
+	return nil
+}
+
+// processValues installs the secrets required by the outputs and TLS and returns the logging chart values
+func (m *loggingFeatureManager) processValues(ctx context.Context, clusterID uint, spec loggingFeatureSpec) ([]byte, error) {
+	var values loggingChartValues
+
+	for _, output := range spec.Outputs {
+		definition, err := m.processOutput(ctx, clusterID, output)
+		if err != nil {
+
+			return nil, errors.WrapIfWithDetails(err, "failed to process output", "output", output.Name)
+		}
+
+		values.ClusterOutputs = append(values.ClusterOutputs, outputResource{
+			Name: output.Name,
+			Spec: definition,
+		})
+	}
+
+	values.ClusterFlows, values.Flows = flowDefinitions(spec)
+
+	if spec.TLS.Enabled {
+		if err := m.processTLS(ctx, clusterID, spec.TLS); err != nil {
+
+			return nil, errors.WrapIf(err, "failed to process TLS")
+		}
+
+		values.TLS = tlsValues{
+			Enabled:             true,
+			FluentdSecretName:   fluentdTLSSecretName,
+			FluentbitSecretName: fluentbitTLSSecretName,
+		}
+	}
+
+	valuesBytes, err := json.Marshal(values)
+	if err != nil {
+
+		return nil, errors.WrapIf(err, "failed to marshal values")
+	}
+
+	return valuesBytes, nil
+}
+
+func (m *loggingFeatureManager) processOutput(ctx context.Context, clusterID uint, output outputSpec) (map[string]interface{}, error) {
+	var secretID string
+	switch {
+	case output.ObjectStore != nil:
+		secretID = output.ObjectStore.SecretID
+	case output.Elasticsearch != nil:
+		secretID = output.Elasticsearch.SecretID
+	case output.Loki != nil:
+		secretID = output.Loki.SecretID
+	}
+
+	var secretValues map[string]string
+	if secretID != "" {
+		values, err := m.secretStore.GetSecretValues(ctx, secretID)
+		if err != nil {
+
+			return nil, errors.WrapIf(err, "failed to get output secret")
+		}
+
+		secretValues = values
+	}
+
+	if output.ObjectStore != nil && output.ObjectStore.Provider == pkgCluster.Azure {
+		storageAccountKey, err := getAzureStorageAccountKey(*output.ObjectStore, secretValues)
+		if err != nil {
+
+			return nil, err
+		}
+
+		secretValues[storageAccountKeyKey] = storageAccountKey
+	}
+
+	definition, kubernetesSecretValues, err := outputDefinition(output, secretValues)
+	if err != nil {
+
+		return nil, err
+	}
+
+	if len(kubernetesSecretValues) > 0 {
+		err := m.secretInstaller.InstallSecret(ctx, clusterID, loggingNamespace, output.secretName(), kubernetesSecretValues)
+		if err != nil {
+
+			return nil, errors.WrapIf(err, "failed to install output secret")
+		}
+	}
+
+	return definition, nil
+}
+
+// processTLS installs the certificates used between fluent-bit and fluentd,
+// a TLS secret is generated for the cluster in the secret store unless one is specified in the spec
+func (m *loggingFeatureManager) processTLS(ctx context.Context, clusterID uint, spec tlsSpec) error {
+	secretID := spec.SecretID
+	if secretID == "" {
+		id, err := m.clusterSecretStore.EnsureSecretExists(ctx, clusterID, clustersecret.SecretCreateRequest{
+			Name: tlsSecretName,
+			Type: pkgSecret.TLSSecretType,
+			Values: map[string]string{
+				pkgSecret.TLSHosts: fmt.Sprintf("%s-fluentd.%s.svc.cluster.local", loggingRelease, loggingNamespace),
+			},
+			Tags: []string{
+				pkgSecret.TagBanzaiReadonly,
+				fmt.Sprintf("feature:%s", featureName),
+			},
+		})
+		if err != nil {
+
+			return errors.WrapIf(err, "failed to generate TLS secret")
+		}
+
+		secretID = id
+	}
+
+	values, err := m.secretStore.GetSecretValues(ctx, secretID)
+	if err != nil {
+
+		return errors.WrapIf(err, "failed to get TLS secret")
+	}
+
+	for _, key := range []string{pkgSecret.CACert, pkgSecret.ServerCert, pkgSecret.ServerKey, pkgSecret.ClientCert, pkgSecret.ClientKey} {
+		if values[key] == "" {
+			return errors.WithDetails(errors.New("TLS secret does not contain the required certificates"), "key", key)
+		}
+	}
+
+	err = m.secretInstaller.InstallSecret(ctx, clusterID, loggingNamespace, fluentdTLSSecretName, map[string]string{
+		v1.ServiceAccountRootCAKey: values[pkgSecret.CACert],
+		v1.TLSCertKey:              values[pkgSecret.ServerCert],
+		v1.TLSPrivateKeyKey:        values[pkgSecret.ServerKey],
+	})
+	if err != nil {
+
+		return errors.WrapIf(err, "failed to install fluentd TLS secret")
+	}
+
+	err = m.secretInstaller.InstallSecret(ctx, clusterID, loggingNamespace, fluentbitTLSSecretName, map[string]string{
+		v1.ServiceAccountRootCAKey: values[pkgSecret.CACert],
+		v1.TLSCertKey:              values[pkgSecret.ClientCert],
+		v1.TLSPrivateKeyKey:        values[pkgSecret.ClientKey],
+	})
+	if err != nil {
+
+		return errors.WrapIf(err, "failed to install fluent-bit TLS secret")
+	}
+
+	return nil
+}
+
+func getAzureStorageAccountKey(output objectStoreOutput, secretValues map[string]string) (string, error) {
+	credentials := *azure.NewCredentials(secretValues)
+
+	storageAccountClient, err := azureObjectstore.NewAuthorizedStorageAccountClientFromSecret(credentials)
+	if err != nil {
+
+		return "", errors.WrapIf(err, "failed to create storage account client")
+	}
+
+	key, err := storageAccountClient.GetStorageAccountKey(output.ResourceGroup, output.StorageAccount)
+	if err != nil {
+
+		return "", errors.WrapIfWithDetails(err, "failed to get storage account key", "storageAccount", output.StorageAccount)
+	}
+
+	return key, nil
+}
+
+// setClusterLogging keeps the logging flag of the cluster in sync with the feature
+func (m *loggingFeatureManager) setClusterLogging(commonCluster cluster.CommonCluster, enabled bool) error {
+	commonCluster.SetLogging(enabled)
+
+	if err := commonCluster.Persist(); err != nil {
+
+		return errors.WrapIf(err, "failed to save cluster")
+	}
+
+	return nil
+}
+
+func (m *loggingFeatureManager) ensureOrgIDInContext(ctx context.Context, clusterID uint) (context.Context, error) {
+	if _, ok := auth.GetCurrentOrganizationID(ctx); !ok {
+		cl, err := m.clusterGetter.GetClusterByIDOnly(ctx, clusterID)
+		if err != nil {
+			return ctx, errors.WrapIf(err, "failed to get cluster by ID")
+		}
+		org, err := auth.GetOrganizationById(cl.GetOrganizationId())
+		if err != nil {
+			return ctx, errors.WrapIf(err, "failed to get organization by ID")
+		}
+		ctx = context.WithValue(ctx, auth.CurrentOrganization, org)
+	}
+	return ctx, nil
+}
@@ -0,0 +1,261 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"fmt"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/mitchellh/mapstructure"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/banzaicloud/pipeline/internal/clusterfeature"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// Supported syslog transports
+const (
+	syslogTransportUDP = "udp"
+	syslogTransportTCP = "tcp"
+	syslogTransportTLS = "tls"
+)
+
+type loggingFeatureSpec struct {
+	Outputs []outputSpec `json:"outputs" mapstructure:"outputs"`
+	Filters []filterSpec `json:"filters" mapstructure:"filters"`
+	TLS     tlsSpec      `json:"tls" mapstructure:"tls"`
+}
+
+type outputSpec struct {
+	Name          string               `json:"name" mapstructure:"name"`
+	ObjectStore   *objectStoreOutput   `json:"objectStore,omitempty" mapstructure:"objectStore"`
+	Elasticsearch *elasticsearchOutput `json:"elasticsearch,omitempty" mapstructure:"elasticsearch"`
+	Loki          *lokiOutput          `json:"loki,omitempty" mapstructure:"loki"`
+	Syslog        *syslogOutput        `json:"syslog,omitempty" mapstructure:"syslog"`
+}
+
+// objectStoreOutput ships logs into an object store bucket using one of the existing cloud secrets
+type objectStoreOutput struct {
+	Provider       string `json:"provider" mapstructure:"provider"`
+	BucketName     string `json:"bucketName" mapstructure:"bucketName"`
+	Region         string `json:"region,omitempty" mapstructure:"region"`
+	ResourceGroup  string `json:"resourceGroup,omitempty" mapstructure:"resourceGroup"`
+	StorageAccount string `json:"storageAccount,omitempty" mapstructure:"storageAccount"`
+	SecretID       string `json:"secretId" mapstructure:"secretId"`
+}
+
+// elasticsearchOutput ships logs into Elasticsearch, the optional secret is a password secret used for basic auth
+type elasticsearchOutput struct {
+	Host     string `json:"host" mapstructure:"host"`
+	Port     int    `json:"port" mapstructure:"port"`
+	Scheme   string `json:"scheme,omitempty" mapstructure:"scheme"`
+	Index    string `json:"index,omitempty" mapstructure:"index"`
+	SecretID string `json:"secretId,omitempty" mapstructure:"secretId"`
+}
+
+// lokiOutput ships logs into Loki, the optional secret is a password secret used for basic auth
+type lokiOutput struct {
+	URL      string `json:"url" mapstructure:"url"`
+	SecretID string `json:"secretId,omitempty" mapstructure:"secretId"`
+}
+
+type syslogOutput struct {
+	Host      string `json:"host" mapstructure:"host"`
+	Port      int    `json:"port" mapstructure:"port"`
+	Transport string `json:"transport,omitempty" mapstructure:"transport"`
+}
+
+// filterSpec routes the logs of a namespace (optionally restricted to pods with the given labels) to the given outputs
+type filterSpec struct {
+	Namespace string            `json:"namespace" mapstructure:"namespace"`
+	Selectors map[string]string `json:"selectors,omitempty" mapstructure:"selectors"`
+	Outputs   []string          `json:"outputs" mapstructure:"outputs"`
+}
+
+// tlsSpec secures the connection between the log collectors and forwarders,
+// a TLS secret is generated in the secret store unless one is specified
+type tlsSpec struct {
+	Enabled  bool   `json:"enabled" mapstructure:"enabled"`
+	SecretID string `json:"secretId,omitempty" mapstructure:"secretId"`
+}
+
+func bindFeatureSpec(spec clusterfeature.FeatureSpec) (loggingFeatureSpec, error) {
+	var loggingSpec loggingFeatureSpec
+
+	if err := mapstructure.Decode(spec, &loggingSpec); err != nil {
+		return loggingSpec, clusterfeature.InvalidFeatureSpecError{
+			FeatureName: featureName,
+			Problem:     "failed to bind feature spec",
+		}
+	}
+
+	return loggingSpec, nil
+}
+
+// Validate validates the logging feature spec
+func (s loggingFeatureSpec) Validate() error {
+	if len(s.Outputs) == 0 {
+		return errors.New("at least one output must be provided")
+	}
+
+	outputs := make(map[string]bool, len(s.Outputs))
+	for _, output := range s.Outputs {
+		if errs := validation.IsDNS1123Label(output.Name); len(errs) > 0 {
+			return errors.WithDetails(errors.Errorf("invalid output name: %s", strings.Join(errs, ", ")), "output", output.Name)
+		}
+
+		if outputs[output.Name] {
+			return errors.WithDetails(errors.New("duplicate output name"), "output", output.Name)
+		}
+		outputs[output.Name] = true
+
+		if err := output.Validate(); err != nil {
+			return errors.WithDetails(err, "output", output.Name)
+		}
+	}
+
+	for _, filter := range s.Filters {
+		if errs := validation.IsDNS1123Label(filter.Namespace); len(errs) > 0 {
+			return errors.WithDetails(errors.Errorf("invalid filter namespace: %s", strings.Join(errs, ", ")), "namespace", filter.Namespace)
+		}
+
+		if len(filter.Outputs) == 0 {
+			return errors.WithDetails(errors.New("at least one output must be provided for the filter"), "namespace", filter.Namespace)
+		}
+
+		for _, output := range filter.Outputs {
+			if !outputs[output] {
+				return errors.WithDetails(errors.New("filter refers to an unknown output"), "namespace", filter.Namespace, "output", output)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Validate validates an output spec
+func (o outputSpec) Validate() error {
+	var count int
+	var err error
+
+	if o.ObjectStore != nil {
+		count++
+		err = o.ObjectStore.Validate()
+	}
+
+	if o.Elasticsearch != nil {
+		count++
+		err = o.Elasticsearch.Validate()
+	}
+
+	if o.Loki != nil {
+		count++
+		err = o.Loki.Validate()
+	}
+
+	if o.Syslog != nil {
+		count++
+		err = o.Syslog.Validate()
+	}
+
+	if count != 1 {
+		return errors.New("exactly one of the objectStore, elasticsearch, loki and syslog outputs must be set")
+	}
+
+	return err
+}
+
+// Validate validates an object store output spec
+func (o objectStoreOutput) Validate() error {
+	if o.BucketName == "" {
+		return errors.New("bucket name must be provided")
+	}
+
+	if o.SecretID == "" {
+		return errors.New("secret ID with object store credentials must be provided")
+	}
+
+	switch o.Provider {
+	case pkgCluster.Amazon, pkgCluster.Alibaba:
+		if o.Region == "" {
+			return errors.New("bucket region must be provided")
+		}
+
+	case pkgCluster.Azure:
+		if o.ResourceGroup == "" || o.StorageAccount == "" {
+			return errors.New("resource group and storage account must be provided")
+		}
+
+	case pkgCluster.Google:
+
+	default:
+		return errors.WithDetails(errors.New("unsupported object store provider"), "provider", o.Provider)
+	}
+
+	return nil
+}
+
+// Validate validates an Elasticsearch output spec
+func (o elasticsearchOutput) Validate() error {
+	if o.Host == "" {
+		return errors.New("Elasticsearch host must be provided")
+	}
+
+	if o.Port <= 0 {
+		return errors.New("Elasticsearch port must be provided")
+	}
+
+	switch o.Scheme {
+	case "", "http", "https":
+	default:
+		return errors.WithDetails(errors.New("unsupported Elasticsearch scheme"), "scheme", o.Scheme)
+	}
+
+	return nil
+}
+
+// Validate validates a Loki output spec
+func (o lokiOutput) Validate() error {
+	if o.URL == "" {
+		return errors.New("Loki URL must be provided")
+	}
+
+	return nil
+}
+
+// Validate validates a syslog output spec
+func (o syslogOutput) Validate() error {
+	if o.Host == "" {
+		return errors.New("syslog host must be provided")
+	}
+
+	if o.Port <= 0 {
+		return errors.New("syslog port must be provided")
+	}
+
+	switch o.Transport {
+	case "", syslogTransportUDP, syslogTransportTCP, syslogTransportTLS:
+	default:
+		return errors.WithDetails(errors.New("unsupported syslog transport"), "transport", o.Transport)
+	}
+
+	return nil
+}
+
+// secretName returns the name of the Kubernetes secret holding the credentials of the output
+func (o outputSpec) secretName() string {
+	return fmt.Sprintf("logging-output-%s", o.Name)
+}
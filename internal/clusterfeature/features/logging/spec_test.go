@@ -0,0 +1,106 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggingFeatureSpec_Validate(t *testing.T) {
+	elasticsearch := outputSpec{
+		Name:          "es",
+		Elasticsearch: &elasticsearchOutput{Host: "elasticsearch", Port: 9200},
+	}
+
+	tests := map[string]struct {
+		spec    loggingFeatureSpec
+		isValid bool
+	}{
+		"valid spec": {
+			spec: loggingFeatureSpec{
+				Outputs: []outputSpec{elasticsearch},
+				Filters: []filterSpec{{Namespace: "default", Outputs: []string{"es"}}},
+			},
+			isValid: true,
+		},
+		"no outputs": {
+			spec: loggingFeatureSpec{},
+		},
+		"duplicate output names": {
+			spec: loggingFeatureSpec{
+				Outputs: []outputSpec{elasticsearch, elasticsearch},
+			},
+		},
+		"multiple output types": {
+			spec: loggingFeatureSpec{
+				Outputs: []outputSpec{{
+					Name:          "mixed",
+					Elasticsearch: elasticsearch.Elasticsearch,
+					Loki:          &lokiOutput{URL: "http://loki:3100"},
+				}},
+			},
+		},
+		"unknown output in filter": {
+			spec: loggingFeatureSpec{
+				Outputs: []outputSpec{elasticsearch},
+				Filters: []filterSpec{{Namespace: "default", Outputs: []string{"loki"}}},
+			},
+		},
+		"unsupported object store provider": {
+			spec: loggingFeatureSpec{
+				Outputs: []outputSpec{{
+					Name:        "bucket",
+					ObjectStore: &objectStoreOutput{Provider: "unknown", BucketName: "logs", SecretID: "secret"},
+				}},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			err := test.spec.Validate()
+
+			if test.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestFlowDefinitions(t *testing.T) {
+	spec := loggingFeatureSpec{
+		Outputs: []outputSpec{{Name: "es"}, {Name: "loki"}},
+	}
+
+	clusterFlows, flows := flowDefinitions(spec)
+	require.Len(t, clusterFlows, 1)
+	assert.Empty(t, flows)
+	assert.Equal(t, []string{"es", "loki"}, clusterFlows[0].Spec.OutputRefs)
+
+	spec.Filters = []filterSpec{{Namespace: "default", Outputs: []string{"loki"}}}
+
+	clusterFlows, flows = flowDefinitions(spec)
+	assert.Empty(t, clusterFlows)
+	require.Len(t, flows, 1)
+	assert.Equal(t, "default", flows[0].Namespace)
+	assert.Equal(t, []string{"loki"}, flows[0].Spec.OutputRefs)
+}
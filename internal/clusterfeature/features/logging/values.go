@@ -0,0 +1,267 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"emperror.dev/errors"
+	v1 "k8s.io/api/core/v1"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgSecret "github.com/banzaicloud/pipeline/pkg/secret"
+)
+
+// Keys of the generated output secrets
+const (
+	accessKeyIDKey        = "accessKeyId"
+	secretAccessKeyKey    = "secretAccessKey"
+	credentialsJSONKey    = "credentials.json"
+	storageAccountNameKey = "storageAccountName"
+	storageAccountKeyKey  = "storageAccountKey"
+	usernameKey           = "username"
+	passwordKey           = "password"
+)
+
+type operatorChartValues struct {
+	Affinity    *v1.Affinity    `json:"affinity,omitempty"`
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+}
+
+type loggingChartValues struct {
+	TLS            tlsValues        `json:"tls"`
+	ClusterOutputs []outputResource `json:"clusterOutputs,omitempty"`
+	ClusterFlows   []flowResource   `json:"clusterFlows,omitempty"`
+	Flows          []flowResource   `json:"flows,omitempty"`
+}
+
+type tlsValues struct {
+	Enabled             bool   `json:"enabled"`
+	FluentdSecretName   string `json:"fluentdSecretName,omitempty"`
+	FluentbitSecretName string `json:"fluentbitSecretName,omitempty"`
+}
+
+type outputResource struct {
+	Name string                 `json:"name"`
+	Spec map[string]interface{} `json:"spec"`
+}
+
+type flowResource struct {
+	Name      string         `json:"name"`
+	Namespace string         `json:"namespace,omitempty"`
+	Spec      flowSpecValues `json:"spec"`
+}
+
+type flowSpecValues struct {
+	Selectors  map[string]string `json:"selectors"`
+	OutputRefs []string          `json:"outputRefs"`
+}
+
+// secretKeyRef refers to a key of the output secret from the output definition
+func secretKeyRef(secretName string, key string) map[string]interface{} {
+	return map[string]interface{}{
+		"valueFrom": map[string]interface{}{
+			"secretKeyRef": map[string]interface{}{
+				"name": secretName,
+				"key":  key,
+			},
+		},
+	}
+}
+
+// outputDefinition returns the output definition of the logging operator
+// and the values of the Kubernetes secret the definition refers to.
+// secretValues are the values of the secret referenced by the output spec.
+func outputDefinition(output outputSpec, secretValues map[string]string) (map[string]interface{}, map[string]string, error) {
+	secretName := output.secretName()
+
+	switch {
+	case output.ObjectStore != nil:
+		return objectStoreOutputDefinition(*output.ObjectStore, secretName, secretValues)
+
+	case output.Elasticsearch != nil:
+		es := output.Elasticsearch
+
+		scheme := es.Scheme
+		if scheme == "" {
+			scheme = "http"
+		}
+
+		definition := map[string]interface{}{
+			"host":   es.Host,
+			"port":   es.Port,
+			"scheme": scheme,
+		}
+
+		if es.Index != "" {
+			definition["index_name"] = es.Index
+		} else {
+			definition["logstash_format"] = true
+		}
+
+		if es.SecretID == "" {
+			return map[string]interface{}{"elasticsearch": definition}, nil, nil
+		}
+
+		definition["user"] = secretKeyRef(secretName, usernameKey)
+		definition["password"] = secretKeyRef(secretName, passwordKey)
+
+		return map[string]interface{}{"elasticsearch": definition}, basicAuthSecret(secretValues), nil
+
+	case output.Loki != nil:
+		definition := map[string]interface{}{
+			"url": output.Loki.URL,
+		}
+
+		if output.Loki.SecretID == "" {
+			return map[string]interface{}{"loki": definition}, nil, nil
+		}
+
+		definition["username"] = secretKeyRef(secretName, usernameKey)
+		definition["password"] = secretKeyRef(secretName, passwordKey)
+
+		return map[string]interface{}{"loki": definition}, basicAuthSecret(secretValues), nil
+
+	case output.Syslog != nil:
+		transport := output.Syslog.Transport
+		if transport == "" {
+			transport = syslogTransportUDP
+		}
+
+		return map[string]interface{}{
+			"syslog": map[string]interface{}{
+				"host":      output.Syslog.Host,
+				"port":      output.Syslog.Port,
+				"transport": transport,
+			},
+		}, nil, nil
+
+	default:
+		return nil, nil, errors.WithDetails(errors.New("output type is not set"), "output", output.Name)
+	}
+}
+
+// objectStoreOutputDefinition expects the Azure storage account key resolved into the secret values
+func objectStoreOutputDefinition(output objectStoreOutput, secretName string, secretValues map[string]string) (map[string]interface{}, map[string]string, error) {
+	switch output.Provider {
+	case pkgCluster.Amazon:
+		definition := map[string]interface{}{
+			"aws_key_id":  secretKeyRef(secretName, accessKeyIDKey),
+			"aws_sec_key": secretKeyRef(secretName, secretAccessKeyKey),
+			"s3_bucket":   output.BucketName,
+			"s3_region":   output.Region,
+		}
+		secret := map[string]string{
+			accessKeyIDKey:     secretValues[pkgSecret.AwsAccessKeyId],
+			secretAccessKeyKey: secretValues[pkgSecret.AwsSecretAccessKey],
+		}
+
+		return map[string]interface{}{"s3": definition}, secret, nil
+
+	case pkgCluster.Google:
+		credentials, err := json.Marshal(secretValues)
+		if err != nil {
+			return nil, nil, errors.WrapIf(err, "failed to marshal Google credentials")
+		}
+
+		definition := map[string]interface{}{
+			"project":          secretValues[pkgSecret.ProjectId],
+			"bucket":           output.BucketName,
+			"credentials_json": secretKeyRef(secretName, credentialsJSONKey),
+		}
+		secret := map[string]string{
+			credentialsJSONKey: string(credentials),
+		}
+
+		return map[string]interface{}{"gcs": definition}, secret, nil
+
+	case pkgCluster.Azure:
+		definition := map[string]interface{}{
+			"azure_storage_account":    secretKeyRef(secretName, storageAccountNameKey),
+			"azure_storage_access_key": secretKeyRef(secretName, storageAccountKeyKey),
+			"azure_container":          output.BucketName,
+		}
+		secret := map[string]string{
+			storageAccountNameKey: output.StorageAccount,
+			storageAccountKeyKey:  secretValues[storageAccountKeyKey],
+		}
+
+		return map[string]interface{}{"azurestorage": definition}, secret, nil
+
+	case pkgCluster.Alibaba:
+		definition := map[string]interface{}{
+			"endpoint":          fmt.Sprintf("oss-%s.aliyuncs.com", output.Region),
+			"bucket":            output.BucketName,
+			"access_key_id":     secretKeyRef(secretName, accessKeyIDKey),
+			"access_key_secret": secretKeyRef(secretName, secretAccessKeyKey),
+		}
+		secret := map[string]string{
+			accessKeyIDKey:     secretValues[pkgSecret.AlibabaAccessKeyId],
+			secretAccessKeyKey: secretValues[pkgSecret.AlibabaSecretAccessKey],
+		}
+
+		return map[string]interface{}{"oss": definition}, secret, nil
+
+	default:
+		return nil, nil, errors.WithDetails(errors.New("unsupported object store provider"), "provider", output.Provider)
+	}
+}
+
+func basicAuthSecret(secretValues map[string]string) map[string]string {
+	return map[string]string{
+		usernameKey: secretValues[pkgSecret.Username],
+		passwordKey: secretValues[pkgSecret.Password],
+	}
+}
+
+// flowDefinitions routes the logs of the cluster to every output unless filters are specified,
+// in which case the logs of each filtered namespace are routed to the outputs of the filter
+func flowDefinitions(spec loggingFeatureSpec) (clusterFlows []flowResource, flows []flowResource) {
+	if len(spec.Filters) == 0 {
+		outputRefs := make([]string, 0, len(spec.Outputs))
+		for _, output := range spec.Outputs {
+			outputRefs = append(outputRefs, output.Name)
+		}
+
+		return []flowResource{
+			{
+				Name: "all",
+				Spec: flowSpecValues{
+					Selectors:  map[string]string{},
+					OutputRefs: outputRefs,
+				},
+			},
+		}, nil
+	}
+
+	for i, filter := range spec.Filters {
+		selectors := filter.Selectors
+		if selectors == nil {
+			selectors = map[string]string{}
+		}
+
+		flows = append(flows, flowResource{
+			Name:      fmt.Sprintf("%s-%d", filter.Namespace, i),
+			Namespace: filter.Namespace,
+			Spec: flowSpecValues{
+				Selectors:  selectors,
+				OutputRefs: filter.Outputs,
+			},
+		})
+	}
+
+	return nil, flows
+}
@@ -0,0 +1,411 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"emperror.dev/errors"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/cluster"
+	"github.com/banzaicloud/pipeline/internal/clusterfeature"
+	"github.com/banzaicloud/pipeline/internal/clusterfeature/clusterfeatureadapter"
+	"github.com/banzaicloud/pipeline/internal/clusterfeature/features"
+	"github.com/banzaicloud/pipeline/internal/common"
+)
+
+const (
+	featureName = "monitoring"
+
+	// hardcoded values for monitoring feature
+	prometheusOperatorChartVersion = "6.7.3"
+
+	prometheusOperatorChartName = "stable/prometheus-operator"
+
+	monitoringNamespace = "pipeline-system"
+
+	monitoringRelease = "monitoring"
+
+	alertmanagerService = monitoringRelease + "-alertmanager"
+
+	// alertmanagerConfigSecretName is the Kubernetes secret holding the Alertmanager configuration (including receiver credentials)
+	alertmanagerConfigSecretName = monitoringRelease + "-alertmanager-config"
+
+	alertmanagerPort = "web"
+
+	// watchdogAlertName is the always firing alert of the default rules used to check the alerting pipeline
+	watchdogAlertName = "Watchdog"
+)
+
+// monitoringFeatureManager synchronous feature manager
+type monitoringFeatureManager struct {
+	featureRepository clusterfeature.FeatureRepository
+	secretStore       features.SecretStore
+	secretInstaller   features.KubernetesSecretInstaller
+	serviceProxy      features.KubernetesServiceProxy
+	clusterGetter     clusterfeatureadapter.ClusterGetter
+	clusterService    clusterfeature.ClusterService
+	helmService       features.HelmService
+
+	logger common.Logger
+}
+
+// NewMonitoringFeatureManager builds a new feature manager component
+func NewMonitoringFeatureManager(
+	featureRepository clusterfeature.FeatureRepository,
+	secretStore features.SecretStore,
+	secretInstaller features.KubernetesSecretInstaller,
+	serviceProxy features.KubernetesServiceProxy,
+	clusterService clusterfeature.ClusterService,
+	clusterGetter clusterfeatureadapter.ClusterGetter,
+	helmService features.HelmService,
+
+	logger common.Logger,
+) clusterfeature.FeatureManager {
+	return &monitoringFeatureManager{
+		featureRepository: featureRepository,
+		secretStore:       secretStore,
+		secretInstaller:   secretInstaller,
+		serviceProxy:      serviceProxy,
+		clusterService:    clusterService,
+		clusterGetter:     clusterGetter,
+		helmService:       helmService,
+		logger:            logger,
+	}
+}
+
+func (m *monitoringFeatureManager) Details(ctx context.Context, clusterID uint) (*clusterfeature.Feature, error) {
+	feature, err := m.featureRepository.GetFeature(ctx, clusterID, featureName)
+	if err != nil {
+
+		return nil, err
+	}
+
+	if feature == nil {
+
+		return nil, clusterfeature.FeatureNotFoundError{FeatureName: featureName}
+	}
+
+	if feature.Status != clusterfeature.FeatureStatusActive {
+
+		return feature, nil
+	}
+
+	logger := m.logger.WithContext(ctx).WithFields(map[string]interface{}{"cluster": clusterID, "feature": featureName})
+
+	// the feature details are still useful when Alertmanager cannot be reached
+	alerts, err := m.getFiringAlerts(ctx, clusterID)
+	if err != nil {
+		logger.Warn("failed to get firing alerts", map[string]interface{}{"error": err.Error()})
+
+		return feature, nil
+	}
+
+	feature.Output = map[string]interface{}{
+		"firingAlerts": alerts,
+	}
+
+	return feature, nil
+}
+
+func (m *monitoringFeatureManager) Name() string {
+	return featureName
+}
+
+func (m *monitoringFeatureManager) Activate(ctx context.Context, clusterID uint, spec clusterfeature.FeatureSpec) error {
+	if err := m.clusterService.CheckClusterReady(ctx, clusterID); err != nil {
+		return err
+	}
+
+	ctx, err := m.ensureOrgIDInContext(ctx, clusterID)
+	if err != nil {
+
+		return err
+	}
+
+	logger := m.logger.WithContext(ctx).WithFields(map[string]interface{}{"cluster": clusterID, "feature": featureName})
+
+	boundSpec, err := bindFeatureSpec(spec)
+	if err != nil {
+
+		return err
+	}
+
+	valuesBytes, err := m.processValues(ctx, clusterID, boundSpec)
+	if err != nil {
+		logger.Debug("failed to process monitoring values")
+
+		return errors.WrapIf(err, "failed to process monitoring values")
+	}
+
+	if err = m.helmService.InstallDeployment(
+		ctx,
+		clusterID,
+		monitoringNamespace,
+		prometheusOperatorChartName,
+		monitoringRelease,
+		valuesBytes,
+		prometheusOperatorChartVersion,
+		false,
+	); err != nil {
+		return errors.WrapIf(err, "failed to deploy feature")
+	}
+
+	return nil
+}
+
+func (m *monitoringFeatureManager) ValidateSpec(ctx context.Context, spec clusterfeature.FeatureSpec) error {
+	monitoringSpec, err := bindFeatureSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	if err := monitoringSpec.Validate(); err != nil {
+		return clusterfeature.InvalidFeatureSpecError{
+			FeatureName: featureName,
+			Problem:     err.Error(),
+		}
+	}
+
+	return nil
+}
+
+func (m *monitoringFeatureManager) Deactivate(ctx context.Context, clusterID uint) error {
+	if err := m.clusterService.CheckClusterReady(ctx, clusterID); err != nil {
+		return err
+	}
+
+	ctx, err := m.ensureOrgIDInContext(ctx, clusterID)
+	if err != nil {
+
+		return err
+	}
+
+	logger := m.logger.WithContext(ctx).WithFields(map[string]interface{}{"cluster": clusterID, "feature": featureName})
+
+	if err := m.helmService.DeleteDeployment(ctx, clusterID, monitoringRelease); err != nil {
+		logger.Info("failed to delete feature deployment")
+
+		return errors.WrapIf(err, "failed to uninstall feature")
+	}
+
+	return nil
+}
+
+func (m *monitoringFeatureManager) Update(ctx context.Context, clusterID uint, spec clusterfeature.FeatureSpec) error {
+	if err := m.clusterService.CheckClusterReady(ctx, clusterID); err != nil {
+		return err
+	}
+
+	ctx, err := m.ensureOrgIDInContext(ctx, clusterID)
+	if err != nil {
+
+		return err
+	}
+
+	logger := m.logger.WithContext(ctx).WithFields(map[string]interface{}{"cluster": clusterID, "feature": featureName})
+
+	boundSpec, err := bindFeatureSpec(spec)
+	if err != nil {
+
+		return err
+	}
+
+	valuesBytes, err := m.processValues(ctx, clusterID, boundSpec)
+	if err != nil {
+		logger.Debug("failed to process monitoring values")
+
+		return errors.WrapIf(err, "failed to process monitoring values")
+	}
+
+	if _, err = m.featureRepository.UpdateFeatureSpec(ctx, clusterID, featureName, spec); err != nil {
+		logger.Debug("failed to update feature spec")
+
+		return err
+	}
+
+	if err = m.helmService.UpdateDeployment(ctx,
+		clusterID,
+		monitoringNamespace,
+		prometheusOperatorChartName,
+		monitoringRelease,
+		valuesBytes,
+		prometheusOperatorChartVersion); err != nil {
+		logger.Debug("failed to update")
+
+		return errors.WrapIf(err, "failed to update feature")
+	}
+
+	// feature status set back to active
+	if _, err = m.featureRepository.UpdateFeatureStatus(ctx, clusterID, featureName, clusterfeature.FeatureStatusActive); err != nil {
+		logger.Debug("failed to update feature status")
+
+		return err
+	}
+
+	logger.Info("successfully updated feature")
+
+	return nil
+}
+
+// processValues resolves the receiver credentials, installs the Alertmanager configuration and renders the chart values,
+// the configuration is kept out of the chart values, because the release values are readable by every organization member
+func (m *monitoringFeatureManager) processValues(ctx context.Context, clusterID uint, spec monitoringFeatureSpec) ([]byte, error) {
+	secrets := make(map[string]map[string]string, len(spec.Receivers))
+	for _, receiver := range spec.Receivers {
+		secretID := receiver.secretID()
+		if secretID == "" {
+			continue
+		}
+
+		values, err := m.secretStore.GetSecretValues(ctx, secretID)
+		if err != nil {
+
+			return nil, errors.WrapIfWithDetails(err, "failed to get receiver secret", "receiver", receiver.Name)
+		}
+
+		secrets[receiver.Name] = values
+	}
+
+	alertmanagerConfig, err := alertmanagerConfiguration(spec, secrets)
+	if err != nil {
+
+		return nil, errors.WrapIf(err, "failed to render alertmanager configuration")
+	}
+
+	alertmanagerConfigValues, err := alertmanagerConfigSecretValues(alertmanagerConfig)
+	if err != nil {
+
+		return nil, err
+	}
+
+	err = m.secretInstaller.InstallSecret(ctx, clusterID, monitoringNamespace, alertmanagerConfigSecretName, alertmanagerConfigValues)
+	if err != nil {
+
+		return nil, errors.WrapIf(err, "failed to install alertmanager configuration secret")
+	}
+
+	commonCluster, err := m.clusterGetter.GetClusterByIDOnly(ctx, clusterID)
+	if err != nil {
+
+		return nil, errors.WrapIf(err, "failed to get cluster")
+	}
+
+	placement := podPlacementValues{
+		Tolerations: cluster.GetHeadNodeTolerations(),
+	}
+
+	headNodeAffinity := cluster.GetHeadNodeAffinity(commonCluster)
+	if headNodeAffinity != (v1.Affinity{}) {
+		placement.Affinity = &headNodeAffinity
+	}
+
+	values := chartValues{
+		FullnameOverride:   monitoringRelease,
+		PrometheusOperator: placement,
+		Prometheus: prometheusValues{
+			PrometheusSpec: placement,
+		},
+		Alertmanager: alertmanagerValues{
+			AlertmanagerSpec: alertmanagerSpecValues{
+				podPlacementValues: placement,
+				UseExistingSecret:  true,
+				ConfigSecret:       alertmanagerConfigSecretName,
+			},
+		},
+		AdditionalPrometheusRules: prometheusRules(spec),
+	}
+
+	valuesBytes, err := json.Marshal(values)
+	if err != nil {
+
+		return nil, errors.WrapIf(err, "failed to marshal values")
+	}
+
+	return valuesBytes, nil
+}
+
+type firingAlert struct {
+	Name        string            `json:"name"`
+	Severity    string            `json:"severity,omitempty"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt"`
+}
+
+// getFiringAlerts lists the active alerts from the Alertmanager API of the cluster
+func (m *monitoringFeatureManager) getFiringAlerts(ctx context.Context, clusterID uint) ([]firingAlert, error) {
+	body, err := m.serviceProxy.ProxyGet(ctx, clusterID, monitoringNamespace, alertmanagerService, alertmanagerPort, "api/v2/alerts", map[string]string{
+		"active":    "true",
+		"silenced":  "false",
+		"inhibited": "false",
+	})
+	if err != nil {
+
+		return nil, errors.WrapIf(err, "failed to list alerts")
+	}
+
+	return parseFiringAlerts(body)
+}
+
+func parseFiringAlerts(body []byte) ([]firingAlert, error) {
+	var alerts []struct {
+		Labels      map[string]string `json:"labels"`
+		Annotations map[string]string `json:"annotations"`
+		StartsAt    time.Time         `json:"startsAt"`
+	}
+
+	if err := json.Unmarshal(body, &alerts); err != nil {
+
+		return nil, errors.WrapIf(err, "failed to decode alerts")
+	}
+
+	firingAlerts := make([]firingAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		name := alert.Labels["alertname"]
+		if name == watchdogAlertName {
+			continue
+		}
+
+		firingAlerts = append(firingAlerts, firingAlert{
+			Name:        name,
+			Severity:    alert.Labels["severity"],
+			Labels:      alert.Labels,
+			Annotations: alert.Annotations,
+			StartsAt:    alert.StartsAt,
+		})
+	}
+
+	return firingAlerts, nil
+}
+
+func (m *monitoringFeatureManager) ensureOrgIDInContext(ctx context.Context, clusterID uint) (context.Context, error) {
+	if _, ok := auth.GetCurrentOrganizationID(ctx); !ok {
+		cl, err := m.clusterGetter.GetClusterByIDOnly(ctx, clusterID)
+		if err != nil {
+			return ctx, errors.WrapIf(err, "failed to get cluster by ID")
+		}
+		org, err := auth.GetOrganizationById(cl.GetOrganizationId())
+		if err != nil {
+			return ctx, errors.WrapIf(err, "failed to get organization by ID")
+		}
+		ctx = context.WithValue(ctx, auth.CurrentOrganization, org)
+	}
+	return ctx, nil
+}
@@ -0,0 +1,279 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"net/mail"
+	"net/url"
+
+	"emperror.dev/errors"
+	"github.com/mitchellh/mapstructure"
+	"github.com/prometheus/common/model"
+
+	"github.com/banzaicloud/pipeline/internal/clusterfeature"
+)
+
+type monitoringFeatureSpec struct {
+	AlertRules []alertRuleGroupSpec `json:"alertRules" mapstructure:"alertRules"`
+	Receivers  []receiverSpec       `json:"receivers" mapstructure:"receivers"`
+	Routes     []routeSpec          `json:"routes" mapstructure:"routes"`
+}
+
+// alertRuleGroupSpec is a group of Prometheus alerting rules evaluated together
+type alertRuleGroupSpec struct {
+	Name  string          `json:"name" mapstructure:"name"`
+	Rules []alertRuleSpec `json:"rules" mapstructure:"rules"`
+}
+
+type alertRuleSpec struct {
+	Alert       string            `json:"alert" mapstructure:"alert"`
+	Expr        string            `json:"expr" mapstructure:"expr"`
+	For         string            `json:"for,omitempty" mapstructure:"for"`
+	Labels      map[string]string `json:"labels,omitempty" mapstructure:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty" mapstructure:"annotations"`
+}
+
+// receiverSpec is an Alertmanager notification channel
+type receiverSpec struct {
+	Name      string             `json:"name" mapstructure:"name"`
+	Slack     *slackReceiver     `json:"slack,omitempty" mapstructure:"slack"`
+	Email     *emailReceiver     `json:"email,omitempty" mapstructure:"email"`
+	PagerDuty *pagerDutyReceiver `json:"pagerDuty,omitempty" mapstructure:"pagerDuty"`
+	Webhook   *webhookReceiver   `json:"webhook,omitempty" mapstructure:"webhook"`
+}
+
+// slackReceiver reads the incoming webhook URL from a generic secret
+type slackReceiver struct {
+	Channel      string `json:"channel" mapstructure:"channel"`
+	SendResolved bool   `json:"sendResolved" mapstructure:"sendResolved"`
+	SecretID     string `json:"secretId" mapstructure:"secretId"`
+}
+
+// emailReceiver reads the optional SMTP credentials from a password secret
+type emailReceiver struct {
+	To           string `json:"to" mapstructure:"to"`
+	From         string `json:"from" mapstructure:"from"`
+	SmartHost    string `json:"smartHost" mapstructure:"smartHost"`
+	RequireTLS   bool   `json:"requireTls" mapstructure:"requireTls"`
+	SendResolved bool   `json:"sendResolved" mapstructure:"sendResolved"`
+	SecretID     string `json:"secretId,omitempty" mapstructure:"secretId"`
+}
+
+// pagerDutyReceiver reads the integration key from a generic secret
+type pagerDutyReceiver struct {
+	SendResolved bool   `json:"sendResolved" mapstructure:"sendResolved"`
+	SecretID     string `json:"secretId" mapstructure:"secretId"`
+}
+
+// webhookReceiver reads the optional basic auth credentials from a password secret
+type webhookReceiver struct {
+	URL          string `json:"url" mapstructure:"url"`
+	SendResolved bool   `json:"sendResolved" mapstructure:"sendResolved"`
+	SecretID     string `json:"secretId,omitempty" mapstructure:"secretId"`
+}
+
+// routeSpec sends alerts matching all labels to a receiver
+type routeSpec struct {
+	Receiver string            `json:"receiver" mapstructure:"receiver"`
+	Match    map[string]string `json:"match,omitempty" mapstructure:"match"`
+	Continue bool              `json:"continue" mapstructure:"continue"`
+}
+
+func bindFeatureSpec(spec clusterfeature.FeatureSpec) (monitoringFeatureSpec, error) {
+	var monitoringSpec monitoringFeatureSpec
+
+	if err := mapstructure.Decode(spec, &monitoringSpec); err != nil {
+		return monitoringSpec, clusterfeature.InvalidFeatureSpecError{
+			FeatureName: featureName,
+			Problem:     "failed to bind feature spec",
+		}
+	}
+
+	return monitoringSpec, nil
+}
+
+func (s monitoringFeatureSpec) Validate() error {
+	groups := make(map[string]bool, len(s.AlertRules))
+	for _, group := range s.AlertRules {
+		if group.Name == "" {
+			return errors.New("alert rule group name must be provided")
+		}
+
+		if groups[group.Name] {
+			return errors.WithDetails(errors.New("duplicate alert rule group name"), "group", group.Name)
+		}
+		groups[group.Name] = true
+
+		if len(group.Rules) == 0 {
+			return errors.WithDetails(errors.New("at least one rule must be provided for the group"), "group", group.Name)
+		}
+
+		for _, rule := range group.Rules {
+			if err := rule.Validate(); err != nil {
+				return errors.WithDetails(err, "group", group.Name, "alert", rule.Alert)
+			}
+		}
+	}
+
+	receivers := make(map[string]bool, len(s.Receivers))
+	for _, receiver := range s.Receivers {
+		if receiver.Name == "" || receiver.Name == nullReceiverName {
+			return errors.WithDetails(errors.New("invalid receiver name"), "receiver", receiver.Name)
+		}
+
+		if receivers[receiver.Name] {
+			return errors.WithDetails(errors.New("duplicate receiver name"), "receiver", receiver.Name)
+		}
+		receivers[receiver.Name] = true
+
+		if err := receiver.Validate(); err != nil {
+			return errors.WithDetails(err, "receiver", receiver.Name)
+		}
+	}
+
+	for _, route := range s.Routes {
+		if !receivers[route.Receiver] {
+			return errors.WithDetails(errors.New("route refers to an unknown receiver"), "receiver", route.Receiver)
+		}
+
+		for name := range route.Match {
+			if !model.LabelName(name).IsValid() {
+				return errors.WithDetails(errors.New("invalid route match label name"), "receiver", route.Receiver, "label", name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r alertRuleSpec) Validate() error {
+	if !model.IsValidMetricName(model.LabelValue(r.Alert)) {
+		return errors.New("invalid alert name")
+	}
+
+	if r.Expr == "" {
+		return errors.New("alert expression must be provided")
+	}
+
+	if r.For != "" {
+		if _, err := model.ParseDuration(r.For); err != nil {
+			return errors.WrapIf(err, "invalid alert duration")
+		}
+	}
+
+	for name := range r.Labels {
+		if !model.LabelName(name).IsValid() {
+			return errors.WithDetails(errors.New("invalid alert label name"), "label", name)
+		}
+	}
+
+	return nil
+}
+
+func (r receiverSpec) Validate() error {
+	var count int
+	var err error
+
+	if r.Slack != nil {
+		count++
+		err = r.Slack.Validate()
+	}
+
+	if r.Email != nil {
+		count++
+		err = r.Email.Validate()
+	}
+
+	if r.PagerDuty != nil {
+		count++
+		err = r.PagerDuty.Validate()
+	}
+
+	if r.Webhook != nil {
+		count++
+		err = r.Webhook.Validate()
+	}
+
+	if count != 1 {
+		return errors.New("exactly one of the slack, email, pagerDuty and webhook receivers must be set")
+	}
+
+	return err
+}
+
+func (r slackReceiver) Validate() error {
+	if r.Channel == "" {
+		return errors.New("slack channel must be provided")
+	}
+
+	if r.SecretID == "" {
+		return errors.New("secret ID with the slack webhook URL must be provided")
+	}
+
+	return nil
+}
+
+func (r emailReceiver) Validate() error {
+	if _, err := mail.ParseAddressList(r.To); err != nil {
+		return errors.WrapIf(err, "invalid email recipient")
+	}
+
+	if _, err := mail.ParseAddress(r.From); err != nil {
+		return errors.WrapIf(err, "invalid email sender")
+	}
+
+	if r.SmartHost == "" {
+		return errors.New("SMTP smart host must be provided")
+	}
+
+	return nil
+}
+
+func (r pagerDutyReceiver) Validate() error {
+	if r.SecretID == "" {
+		return errors.New("secret ID with the PagerDuty integration key must be provided")
+	}
+
+	return nil
+}
+
+func (r webhookReceiver) Validate() error {
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return errors.WrapIf(err, "invalid webhook URL")
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("webhook URL scheme must be http or https")
+	}
+
+	return nil
+}
+
+// secretID returns the secret holding the credentials of the receiver
+func (r receiverSpec) secretID() string {
+	switch {
+	case r.Slack != nil:
+		return r.Slack.SecretID
+	case r.Email != nil:
+		return r.Email.SecretID
+	case r.PagerDuty != nil:
+		return r.PagerDuty.SecretID
+	case r.Webhook != nil:
+		return r.Webhook.SecretID
+	}
+
+	return ""
+}
@@ -0,0 +1,121 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonitoringFeatureSpec_Validate(t *testing.T) {
+	rules := alertRuleGroupSpec{
+		Name: "node",
+		Rules: []alertRuleSpec{{
+			Alert:  "NodeDown",
+			Expr:   `up{job="node-exporter"} == 0`,
+			For:    "5m",
+			Labels: map[string]string{"severity": "critical"},
+		}},
+	}
+
+	slack := receiverSpec{
+		Name:  "slack",
+		Slack: &slackReceiver{Channel: "#alerts", SecretID: "secret"},
+	}
+
+	tests := map[string]struct {
+		spec    monitoringFeatureSpec
+		isValid bool
+	}{
+		"valid spec": {
+			spec: monitoringFeatureSpec{
+				AlertRules: []alertRuleGroupSpec{rules},
+				Receivers:  []receiverSpec{slack},
+				Routes:     []routeSpec{{Receiver: "slack", Match: map[string]string{"severity": "critical"}}},
+			},
+			isValid: true,
+		},
+		"empty spec": {
+			isValid: true,
+		},
+		"invalid alert duration": {
+			spec: monitoringFeatureSpec{
+				AlertRules: []alertRuleGroupSpec{{
+					Name:  "node",
+					Rules: []alertRuleSpec{{Alert: "NodeDown", Expr: "up == 0", For: "five minutes"}},
+				}},
+			},
+		},
+		"missing alert expression": {
+			spec: monitoringFeatureSpec{
+				AlertRules: []alertRuleGroupSpec{{
+					Name:  "node",
+					Rules: []alertRuleSpec{{Alert: "NodeDown"}},
+				}},
+			},
+		},
+		"duplicate receiver names": {
+			spec: monitoringFeatureSpec{
+				Receivers: []receiverSpec{slack, slack},
+			},
+		},
+		"reserved receiver name": {
+			spec: monitoringFeatureSpec{
+				Receivers: []receiverSpec{{
+					Name:    nullReceiverName,
+					Webhook: &webhookReceiver{URL: "http://example.com"},
+				}},
+			},
+		},
+		"multiple receiver types": {
+			spec: monitoringFeatureSpec{
+				Receivers: []receiverSpec{{
+					Name:      "mixed",
+					Slack:     slack.Slack,
+					PagerDuty: &pagerDutyReceiver{SecretID: "secret"},
+				}},
+			},
+		},
+		"invalid email address": {
+			spec: monitoringFeatureSpec{
+				Receivers: []receiverSpec{{
+					Name:  "email",
+					Email: &emailReceiver{To: "ops", From: "alertmanager@example.com", SmartHost: "smtp.example.com:587"},
+				}},
+			},
+		},
+		"unknown receiver in route": {
+			spec: monitoringFeatureSpec{
+				Receivers: []receiverSpec{slack},
+				Routes:    []routeSpec{{Receiver: "pagerduty"}},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			err := test.spec.Validate()
+
+			if test.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"encoding/json"
+
+	"emperror.dev/errors"
+	v1 "k8s.io/api/core/v1"
+
+	pkgSecret "github.com/banzaicloud/pipeline/pkg/secret"
+)
+
+// Keys of the generic secrets used by the receivers
+const (
+	slackWebhookURLKey         = "webhookUrl"
+	pagerDutyIntegrationKeyKey = "integrationKey"
+)
+
+// alertmanagerConfigKey is the key of the configuration file in the Alertmanager configuration secret
+const alertmanagerConfigKey = "alertmanager.yaml"
+
+const (
+	// nullReceiverName is the receiver of the root route dropping unrouted alerts
+	nullReceiverName = "null"
+
+	defaultAlertmanagerResolveTimeout = "5m"
+)
+
+type chartValues struct {
+	FullnameOverride          string                 `json:"fullnameOverride"`
+	PrometheusOperator        podPlacementValues     `json:"prometheusOperator"`
+	Prometheus                prometheusValues       `json:"prometheus"`
+	Alertmanager              alertmanagerValues     `json:"alertmanager"`
+	AdditionalPrometheusRules []prometheusRuleValues `json:"additionalPrometheusRules"`
+}
+
+type podPlacementValues struct {
+	Affinity    *v1.Affinity    `json:"affinity,omitempty"`
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+}
+
+type prometheusValues struct {
+	PrometheusSpec podPlacementValues `json:"prometheusSpec"`
+}
+
+type alertmanagerValues struct {
+	AlertmanagerSpec alertmanagerSpecValues `json:"alertmanagerSpec"`
+}
+
+type alertmanagerSpecValues struct {
+	podPlacementValues
+
+	UseExistingSecret bool   `json:"useExistingSecret"`
+	ConfigSecret      string `json:"configSecret"`
+}
+
+type prometheusRuleValues struct {
+	Name   string               `json:"name"`
+	Groups []alertRuleGroupSpec `json:"groups"`
+}
+
+// alertmanagerConfig is the subset of the Alertmanager configuration file managed by the feature
+type alertmanagerConfig struct {
+	Global    alertmanagerGlobalConfig `json:"global"`
+	Route     alertmanagerRoute        `json:"route"`
+	Receivers []alertmanagerReceiver   `json:"receivers"`
+}
+
+type alertmanagerGlobalConfig struct {
+	ResolveTimeout string `json:"resolve_timeout"`
+}
+
+type alertmanagerRoute struct {
+	Receiver string              `json:"receiver"`
+	GroupBy  []string            `json:"group_by,omitempty"`
+	Match    map[string]string   `json:"match,omitempty"`
+	Continue bool                `json:"continue,omitempty"`
+	Routes   []alertmanagerRoute `json:"routes,omitempty"`
+}
+
+type alertmanagerReceiver struct {
+	Name             string                   `json:"name"`
+	SlackConfigs     []map[string]interface{} `json:"slack_configs,omitempty"`
+	EmailConfigs     []map[string]interface{} `json:"email_configs,omitempty"`
+	PagerdutyConfigs []map[string]interface{} `json:"pagerduty_configs,omitempty"`
+	WebhookConfigs   []map[string]interface{} `json:"webhook_configs,omitempty"`
+}
+
+// alertmanagerConfiguration renders the Alertmanager configuration from the spec,
+// receiver credentials are expected to be resolved from the secret store by receiver name
+func alertmanagerConfiguration(spec monitoringFeatureSpec, secrets map[string]map[string]string) (alertmanagerConfig, error) {
+	config := alertmanagerConfig{
+		Global: alertmanagerGlobalConfig{
+			ResolveTimeout: defaultAlertmanagerResolveTimeout,
+		},
+		Route: alertmanagerRoute{
+			Receiver: nullReceiverName,
+			GroupBy:  []string{"alertname", "job"},
+		},
+		Receivers: []alertmanagerReceiver{
+			{Name: nullReceiverName},
+		},
+	}
+
+	for _, receiver := range spec.Receivers {
+		r, err := receiverConfiguration(receiver, secrets[receiver.Name])
+		if err != nil {
+			return config, errors.WithDetails(err, "receiver", receiver.Name)
+		}
+
+		config.Receivers = append(config.Receivers, r)
+	}
+
+	for _, route := range spec.Routes {
+		config.Route.Routes = append(config.Route.Routes, alertmanagerRoute{
+			Receiver: route.Receiver,
+			Match:    route.Match,
+			Continue: route.Continue,
+		})
+	}
+
+	// without explicit routes every receiver gets all alerts
+	if len(spec.Routes) == 0 {
+		for _, receiver := range spec.Receivers {
+			config.Route.Routes = append(config.Route.Routes, alertmanagerRoute{
+				Receiver: receiver.Name,
+				Continue: true,
+			})
+		}
+	}
+
+	return config, nil
+}
+
+// alertmanagerConfigSecretValues returns the values of the Kubernetes secret the Alertmanager configuration is read from
+func alertmanagerConfigSecretValues(config alertmanagerConfig) (map[string]string, error) {
+	// JSON is valid YAML
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+
+		return nil, errors.WrapIf(err, "failed to marshal alertmanager configuration")
+	}
+
+	return map[string]string{
+		alertmanagerConfigKey: string(configBytes),
+	}, nil
+}
+
+func receiverConfiguration(receiver receiverSpec, secretValues map[string]string) (alertmanagerReceiver, error) {
+	r := alertmanagerReceiver{
+		Name: receiver.Name,
+	}
+
+	switch {
+	case receiver.Slack != nil:
+		webhookURL := secretValues[slackWebhookURLKey]
+		if webhookURL == "" {
+			return r, errors.Errorf("secret does not contain the %q key", slackWebhookURLKey)
+		}
+
+		r.SlackConfigs = []map[string]interface{}{{
+			"api_url":       webhookURL,
+			"channel":       receiver.Slack.Channel,
+			"send_resolved": receiver.Slack.SendResolved,
+		}}
+
+	case receiver.Email != nil:
+		emailConfig := map[string]interface{}{
+			"to":            receiver.Email.To,
+			"from":          receiver.Email.From,
+			"smarthost":     receiver.Email.SmartHost,
+			"require_tls":   receiver.Email.RequireTLS,
+			"send_resolved": receiver.Email.SendResolved,
+		}
+
+		if receiver.Email.SecretID != "" {
+			emailConfig["auth_username"] = secretValues[pkgSecret.Username]
+			emailConfig["auth_password"] = secretValues[pkgSecret.Password]
+		}
+
+		r.EmailConfigs = []map[string]interface{}{emailConfig}
+
+	case receiver.PagerDuty != nil:
+		integrationKey := secretValues[pagerDutyIntegrationKeyKey]
+		if integrationKey == "" {
+			return r, errors.Errorf("secret does not contain the %q key", pagerDutyIntegrationKeyKey)
+		}
+
+		r.PagerdutyConfigs = []map[string]interface{}{{
+			"routing_key":   integrationKey,
+			"send_resolved": receiver.PagerDuty.SendResolved,
+		}}
+
+	case receiver.Webhook != nil:
+		webhookConfig := map[string]interface{}{
+			"url":           receiver.Webhook.URL,
+			"send_resolved": receiver.Webhook.SendResolved,
+		}
+
+		if receiver.Webhook.SecretID != "" {
+			webhookConfig["http_config"] = map[string]interface{}{
+				"basic_auth": map[string]interface{}{
+					"username": secretValues[pkgSecret.Username],
+					"password": secretValues[pkgSecret.Password],
+				},
+			}
+		}
+
+		r.WebhookConfigs = []map[string]interface{}{webhookConfig}
+	}
+
+	return r, nil
+}
+
+// prometheusRules renders the alerting rules of the spec as a single PrometheusRule resource
+func prometheusRules(spec monitoringFeatureSpec) []prometheusRuleValues {
+	if len(spec.AlertRules) == 0 {
+		return nil
+	}
+
+	return []prometheusRuleValues{
+		{
+			Name:   "alert-rules",
+			Groups: spec.AlertRules,
+		},
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertmanagerConfiguration(t *testing.T) {
+	spec := monitoringFeatureSpec{
+		Receivers: []receiverSpec{
+			{
+				Name:  "slack",
+				Slack: &slackReceiver{Channel: "#alerts", SecretID: "slack-secret"},
+			},
+			{
+				Name:    "webhook",
+				Webhook: &webhookReceiver{URL: "https://example.com/alerts"},
+			},
+		},
+	}
+
+	config, err := alertmanagerConfiguration(spec, map[string]map[string]string{
+		"slack": {slackWebhookURLKey: "https://hooks.slack.com/services/xxx"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, nullReceiverName, config.Route.Receiver)
+	require.Len(t, config.Receivers, 3)
+	assert.Equal(t, "https://hooks.slack.com/services/xxx", config.Receivers[1].SlackConfigs[0]["api_url"])
+	assert.Equal(t, "https://example.com/alerts", config.Receivers[2].WebhookConfigs[0]["url"])
+
+	require.Len(t, config.Route.Routes, 2)
+	assert.True(t, config.Route.Routes[0].Continue)
+
+	_, err = alertmanagerConfiguration(spec, nil)
+	assert.Error(t, err)
+}
+
+func TestAlertmanagerConfigSecretValues(t *testing.T) {
+	config, err := alertmanagerConfiguration(monitoringFeatureSpec{
+		Receivers: []receiverSpec{
+			{
+				Name:      "pagerduty",
+				PagerDuty: &pagerDutyReceiver{SecretID: "pagerduty-secret"},
+			},
+		},
+	}, map[string]map[string]string{
+		"pagerduty": {pagerDutyIntegrationKeyKey: "integration-key"},
+	})
+	require.NoError(t, err)
+
+	values, err := alertmanagerConfigSecretValues(config)
+	require.NoError(t, err)
+
+	var parsedConfig alertmanagerConfig
+	require.NoError(t, yaml.Unmarshal([]byte(values[alertmanagerConfigKey]), &parsedConfig))
+	assert.Equal(t, config, parsedConfig)
+}
+
+func TestParseFiringAlerts(t *testing.T) {
+	body := []byte(`[
+		{"labels": {"alertname": "Watchdog", "severity": "none"}, "annotations": {}, "startsAt": "2019-08-28T10:00:00Z"},
+		{"labels": {"alertname": "NodeDown", "severity": "critical"}, "annotations": {"summary": "node is down"}, "startsAt": "2019-08-28T10:05:00Z"}
+	]`)
+
+	alerts, err := parseFiringAlerts(body)
+	require.NoError(t, err)
+
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "NodeDown", alerts[0].Name)
+	assert.Equal(t, "critical", alerts[0].Severity)
+	assert.Equal(t, "node is down", alerts[0].Annotations["summary"])
+}
@@ -0,0 +1,117 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"time"
+
+	"emperror.dev/errors"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8sapierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/banzaicloud/pipeline/internal/backoff"
+)
+
+const (
+	// tokenReviewerName is the name of the service account (and its role binding) Vault uses to review tokens
+	tokenReviewerName = "vault-token-reviewer"
+
+	authDelegatorClusterRole = "system:auth-delegator"
+)
+
+// ensureTokenReviewer creates a service account allowed to review tokens and returns its token
+func ensureTokenReviewer(client kubernetes.Interface, namespace string) (string, error) {
+	_, err := client.CoreV1().ServiceAccounts(namespace).Create(&corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: tokenReviewerName,
+		},
+	})
+	if err != nil && !k8sapierrors.IsAlreadyExists(err) {
+		return "", errors.WrapIf(err, "failed to create token reviewer service account")
+	}
+
+	_, err = client.RbacV1().ClusterRoleBindings().Create(&rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: tokenReviewerName,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     authDelegatorClusterRole,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      tokenReviewerName,
+				Namespace: namespace,
+			},
+		},
+	})
+	if err != nil && !k8sapierrors.IsAlreadyExists(err) {
+		return "", errors.WrapIf(err, "failed to create token reviewer cluster role binding")
+	}
+
+	var token string
+
+	// the token secret of the service account is created asynchronously
+	backoffPolicy := backoff.NewConstantBackoffPolicy(&backoff.ConstantBackoffConfig{
+		Delay:      time.Second,
+		MaxRetries: 10,
+	})
+	err = backoff.Retry(func() error {
+		serviceAccount, err := client.CoreV1().ServiceAccounts(namespace).Get(tokenReviewerName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		for _, secretRef := range serviceAccount.Secrets {
+			secret, err := client.CoreV1().Secrets(namespace).Get(secretRef.Name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+
+			if secret.Type == corev1.SecretTypeServiceAccountToken && len(secret.Data[corev1.ServiceAccountTokenKey]) > 0 {
+				token = string(secret.Data[corev1.ServiceAccountTokenKey])
+
+				return nil
+			}
+		}
+
+		return errors.New("service account token is not available yet")
+	}, backoffPolicy)
+	if err != nil {
+		return "", errors.WrapIf(err, "failed to get token reviewer token")
+	}
+
+	return token, nil
+}
+
+// deleteTokenReviewer removes the token reviewer service account and its role binding
+func deleteTokenReviewer(client kubernetes.Interface, namespace string) error {
+	err := client.RbacV1().ClusterRoleBindings().Delete(tokenReviewerName, &metav1.DeleteOptions{})
+	if err != nil && !k8sapierrors.IsNotFound(err) {
+		return errors.WrapIf(err, "failed to delete token reviewer cluster role binding")
+	}
+
+	err = client.CoreV1().ServiceAccounts(namespace).Delete(tokenReviewerName, &metav1.DeleteOptions{})
+	if err != nil && !k8sapierrors.IsNotFound(err) {
+		return errors.WrapIf(err, "failed to delete token reviewer service account")
+	}
+
+	return nil
+}
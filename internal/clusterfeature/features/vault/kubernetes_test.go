@@ -0,0 +1,56 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEnsureTokenReviewer(t *testing.T) {
+	const namespace = "pipeline-system"
+
+	client := fake.NewSimpleClientset(
+		&corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{Name: tokenReviewerName, Namespace: namespace},
+			Secrets:    []corev1.ObjectReference{{Name: "vault-token-reviewer-token-abcde"}},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "vault-token-reviewer-token-abcde", Namespace: namespace},
+			Type:       corev1.SecretTypeServiceAccountToken,
+			Data:       map[string][]byte{corev1.ServiceAccountTokenKey: []byte("token")},
+		},
+	)
+
+	token, err := ensureTokenReviewer(client, namespace)
+	require.NoError(t, err)
+
+	assert.Equal(t, "token", token)
+
+	binding, err := client.RbacV1().ClusterRoleBindings().Get(tokenReviewerName, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, authDelegatorClusterRole, binding.RoleRef.Name)
+
+	require.NoError(t, deleteTokenReviewer(client, namespace))
+
+	_, err = client.CoreV1().ServiceAccounts(namespace).Get(tokenReviewerName, metav1.GetOptions{})
+	assert.Error(t, err)
+}
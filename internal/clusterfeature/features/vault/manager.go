@@ -0,0 +1,410 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"encoding/json"
+
+	"emperror.dev/errors"
+	vaultapi "github.com/hashicorp/vault/api"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/cluster"
+	"github.com/banzaicloud/pipeline/internal/clusterfeature"
+	"github.com/banzaicloud/pipeline/internal/clusterfeature/clusterfeatureadapter"
+	"github.com/banzaicloud/pipeline/internal/clusterfeature/features"
+	"github.com/banzaicloud/pipeline/internal/common"
+	"github.com/banzaicloud/pipeline/pkg/k8sclient"
+	"github.com/banzaicloud/pipeline/pkg/k8sutil"
+)
+
+const (
+	featureName = "vault"
+
+	// hardcoded values for vault feature
+	secretsWebhookChartVersion = "0.5.2"
+
+	secretsWebhookChartName = "banzaicloud-stable/vault-secrets-webhook"
+
+	vaultNamespace = "pipeline-system"
+
+	secretsWebhookRelease = "vault-secrets-webhook"
+)
+
+// vaultFeatureManager synchronous feature manager
+type vaultFeatureManager struct {
+	featureRepository clusterfeature.FeatureRepository
+	secretStore       features.SecretStore
+	vaultClient       *vaultapi.Client
+	vaultAddress      string
+	clusterGetter     clusterfeatureadapter.ClusterGetter
+	clusterService    clusterfeature.ClusterService
+	helmService       features.HelmService
+
+	logger common.Logger
+}
+
+// NewVaultFeatureManager builds a new feature manager component,
+// workloads reach Vault on the given address or on the address of the client if it is empty
+func NewVaultFeatureManager(
+	featureRepository clusterfeature.FeatureRepository,
+	secretStore features.SecretStore,
+	vaultClient *vaultapi.Client,
+	vaultAddress string,
+	clusterService clusterfeature.ClusterService,
+	clusterGetter clusterfeatureadapter.ClusterGetter,
+	helmService features.HelmService,
+
+	logger common.Logger,
+) clusterfeature.FeatureManager {
+	if vaultAddress == "" {
+		vaultAddress = vaultClient.Address()
+	}
+
+	return &vaultFeatureManager{
+		featureRepository: featureRepository,
+		secretStore:       secretStore,
+		vaultClient:       vaultClient,
+		vaultAddress:      vaultAddress,
+		clusterService:    clusterService,
+		clusterGetter:     clusterGetter,
+		helmService:       helmService,
+		logger:            logger,
+	}
+}
+
+func (m *vaultFeatureManager) Details(ctx context.Context, clusterID uint) (*clusterfeature.Feature, error) {
+	feature, err := m.featureRepository.GetFeature(ctx, clusterID, featureName)
+	if err != nil {
+
+		return nil, err
+	}
+
+	if feature == nil {
+
+		return nil, clusterfeature.FeatureNotFoundError{FeatureName: featureName}
+	}
+
+	feature.Output = map[string]interface{}{
+		"vaultAddress": m.vaultAddress,
+		"authPath":     authPath(clusterID),
+		"role":         workloadRole,
+		"policy":       policyName(clusterID),
+	}
+
+	return feature, nil
+}
+
+func (m *vaultFeatureManager) Name() string {
+	return featureName
+}
+
+func (m *vaultFeatureManager) Activate(ctx context.Context, clusterID uint, spec clusterfeature.FeatureSpec) error {
+	if err := m.clusterService.CheckClusterReady(ctx, clusterID); err != nil {
+		return err
+	}
+
+	ctx, err := m.ensureOrgIDInContext(ctx, clusterID)
+	if err != nil {
+
+		return err
+	}
+
+	logger := m.logger.WithContext(ctx).WithFields(map[string]interface{}{"cluster": clusterID, "feature": featureName})
+
+	boundSpec, err := bindFeatureSpec(spec)
+	if err != nil {
+
+		return err
+	}
+
+	commonCluster, err := m.clusterGetter.GetClusterByIDOnly(ctx, clusterID)
+	if err != nil {
+
+		return errors.WrapIf(err, "failed to get cluster")
+	}
+
+	if err := m.configureVault(ctx, commonCluster, boundSpec); err != nil {
+		logger.Debug("failed to configure vault")
+
+		return errors.WrapIf(err, "failed to configure vault")
+	}
+
+	valuesBytes, err := m.webhookValues(commonCluster)
+	if err != nil {
+
+		return err
+	}
+
+	if err = m.helmService.InstallDeployment(
+		ctx,
+		clusterID,
+		vaultNamespace,
+		secretsWebhookChartName,
+		secretsWebhookRelease,
+		valuesBytes,
+		secretsWebhookChartVersion,
+		false,
+	); err != nil {
+		return errors.WrapIf(err, "failed to deploy feature")
+	}
+
+	return nil
+}
+
+func (m *vaultFeatureManager) ValidateSpec(ctx context.Context, spec clusterfeature.FeatureSpec) error {
+	vaultSpec, err := bindFeatureSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	if err := vaultSpec.Validate(); err != nil {
+		return clusterfeature.InvalidFeatureSpecError{
+			FeatureName: featureName,
+			Problem:     err.Error(),
+		}
+	}
+
+	return nil
+}
+
+func (m *vaultFeatureManager) Deactivate(ctx context.Context, clusterID uint) error {
+	if err := m.clusterService.CheckClusterReady(ctx, clusterID); err != nil {
+		return err
+	}
+
+	ctx, err := m.ensureOrgIDInContext(ctx, clusterID)
+	if err != nil {
+
+		return err
+	}
+
+	logger := m.logger.WithContext(ctx).WithFields(map[string]interface{}{"cluster": clusterID, "feature": featureName})
+
+	if err := m.helmService.DeleteDeployment(ctx, clusterID, secretsWebhookRelease); err != nil {
+		logger.Info("failed to delete feature deployment")
+
+		return errors.WrapIf(err, "failed to uninstall feature")
+	}
+
+	if err := removeClusterAccess(m.vaultClient, authPath(clusterID), policyName(clusterID)); err != nil {
+		logger.Info("failed to remove cluster access from vault")
+
+		return errors.WrapIf(err, "failed to remove cluster access from vault")
+	}
+
+	commonCluster, err := m.clusterGetter.GetClusterByIDOnly(ctx, clusterID)
+	if err != nil {
+
+		return errors.WrapIf(err, "failed to get cluster")
+	}
+
+	kubeConfig, err := commonCluster.GetK8sConfig()
+	if err != nil {
+
+		return errors.WrapIf(err, "failed to get cluster config")
+	}
+
+	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	if err != nil {
+
+		return errors.WrapIf(err, "failed to create client from kubeconfig")
+	}
+
+	if err := deleteTokenReviewer(client, vaultNamespace); err != nil {
+
+		return err
+	}
+
+	return nil
+}
+
+func (m *vaultFeatureManager) Update(ctx context.Context, clusterID uint, spec clusterfeature.FeatureSpec) error {
+	if err := m.clusterService.CheckClusterReady(ctx, clusterID); err != nil {
+		return err
+	}
+
+	ctx, err := m.ensureOrgIDInContext(ctx, clusterID)
+	if err != nil {
+
+		return err
+	}
+
+	logger := m.logger.WithContext(ctx).WithFields(map[string]interface{}{"cluster": clusterID, "feature": featureName})
+
+	boundSpec, err := bindFeatureSpec(spec)
+	if err != nil {
+
+		return err
+	}
+
+	commonCluster, err := m.clusterGetter.GetClusterByIDOnly(ctx, clusterID)
+	if err != nil {
+
+		return errors.WrapIf(err, "failed to get cluster")
+	}
+
+	if _, err = m.featureRepository.UpdateFeatureSpec(ctx, clusterID, featureName, spec); err != nil {
+		logger.Debug("failed to update feature spec")
+
+		return err
+	}
+
+	if err := m.configureVault(ctx, commonCluster, boundSpec); err != nil {
+		logger.Debug("failed to configure vault")
+
+		return errors.WrapIf(err, "failed to configure vault")
+	}
+
+	valuesBytes, err := m.webhookValues(commonCluster)
+	if err != nil {
+
+		return err
+	}
+
+	if err = m.helmService.UpdateDeployment(ctx,
+		clusterID,
+		vaultNamespace,
+		secretsWebhookChartName,
+		secretsWebhookRelease,
+		valuesBytes,
+		secretsWebhookChartVersion); err != nil {
+		logger.Debug("failed to update")
+
+		return errors.WrapIf(err, "failed to update feature")
+	}
+
+	// feature status set back to active
+	if _, err = m.featureRepository.UpdateFeatureStatus(ctx, clusterID, featureName, clusterfeature.FeatureStatusActive); err != nil {
+		logger.Debug("failed to update feature status")
+
+		return err
+	}
+
+	logger.Info("successfully updated feature")
+
+	return nil
+}
+
+// configureVault sets up the Kubernetes auth method of the cluster and the policy of its workloads
+func (m *vaultFeatureManager) configureVault(ctx context.Context, commonCluster cluster.CommonCluster, spec vaultFeatureSpec) error {
+	if err := spec.validateSecrets(ctx, m.secretStore); err != nil {
+
+		return err
+	}
+
+	kubeConfig, err := commonCluster.GetK8sConfig()
+	if err != nil {
+
+		return errors.WrapIf(err, "failed to get cluster config")
+	}
+
+	restConfig, err := k8sclient.NewClientConfig(kubeConfig)
+	if err != nil {
+
+		return errors.WrapIf(err, "failed to create client config")
+	}
+
+	client, err := k8sclient.NewClientFromConfig(restConfig)
+	if err != nil {
+
+		return errors.WrapIf(err, "failed to create client")
+	}
+
+	if err := k8sutil.EnsureNamespace(client, vaultNamespace); err != nil {
+
+		return errors.WrapIf(err, "failed to create namespace")
+	}
+
+	tokenReviewerJWT, err := ensureTokenReviewer(client, vaultNamespace)
+	if err != nil {
+
+		return err
+	}
+
+	clusterID := commonCluster.GetID()
+	path := authPath(clusterID)
+	policy := policyName(clusterID)
+
+	if err := ensureAuthMethod(m.vaultClient, path); err != nil {
+
+		return err
+	}
+
+	err = configureAuthMethod(m.vaultClient, path, kubernetesAuthConfig{
+		Host:             restConfig.Host,
+		CACert:           string(restConfig.TLSClientConfig.CAData),
+		TokenReviewerJWT: tokenReviewerJWT,
+	})
+	if err != nil {
+
+		return err
+	}
+
+	if err := m.vaultClient.Sys().PutPolicy(policy, spec.policy(commonCluster.GetOrganizationId())); err != nil {
+
+		return errors.WrapIfWithDetails(err, "failed to write policy", "policy", policy)
+	}
+
+	return putRole(m.vaultClient, path, policy, spec)
+}
+
+type webhookValues struct {
+	Env         map[string]string `json:"env"`
+	Affinity    *v1.Affinity      `json:"affinity,omitempty"`
+	Tolerations []v1.Toleration   `json:"tolerations,omitempty"`
+}
+
+// webhookValues points the secrets webhook to Vault and the auth method of the cluster
+func (m *vaultFeatureManager) webhookValues(commonCluster cluster.CommonCluster) ([]byte, error) {
+	values := webhookValues{
+		Env: map[string]string{
+			"VAULT_ADDR": m.vaultAddress,
+			"VAULT_PATH": authPath(commonCluster.GetID()),
+			"VAULT_ROLE": workloadRole,
+		},
+		Tolerations: cluster.GetHeadNodeTolerations(),
+	}
+
+	headNodeAffinity := cluster.GetHeadNodeAffinity(commonCluster)
+	if headNodeAffinity != (v1.Affinity{}) {
+		values.Affinity = &headNodeAffinity
+	}
+
+	valuesBytes, err := json.Marshal(values)
+	if err != nil {
+
+		return nil, errors.WrapIf(err, "failed to marshal values")
+	}
+
+	return valuesBytes, nil
+}
+
+func (m *vaultFeatureManager) ensureOrgIDInContext(ctx context.Context, clusterID uint) (context.Context, error) {
+	if _, ok := auth.GetCurrentOrganizationID(ctx); !ok {
+		cl, err := m.clusterGetter.GetClusterByIDOnly(ctx, clusterID)
+		if err != nil {
+			return ctx, errors.WrapIf(err, "failed to get cluster by ID")
+		}
+		org, err := auth.GetOrganizationById(cl.GetOrganizationId())
+		if err != nil {
+			return ctx, errors.WrapIf(err, "failed to get organization by ID")
+		}
+		ctx = context.WithValue(ctx, auth.CurrentOrganization, org)
+	}
+	return ctx, nil
+}
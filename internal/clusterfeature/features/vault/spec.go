@@ -0,0 +1,111 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/banzaicloud/pipeline/internal/clusterfeature"
+	"github.com/banzaicloud/pipeline/internal/clusterfeature/features"
+)
+
+type vaultFeatureSpec struct {
+	// SecretIDs are the IDs of the organization secrets the workloads can read
+	SecretIDs []string `json:"secretIds" mapstructure:"secretIds"`
+
+	// ServiceAccounts and Namespaces allowed to authenticate
+	ServiceAccounts []string `json:"serviceAccounts" mapstructure:"serviceAccounts"`
+	Namespaces      []string `json:"namespaces" mapstructure:"namespaces"`
+}
+
+func bindFeatureSpec(spec clusterfeature.FeatureSpec) (vaultFeatureSpec, error) {
+	var vaultSpec vaultFeatureSpec
+
+	if err := mapstructure.Decode(spec, &vaultSpec); err != nil {
+		return vaultSpec, clusterfeature.InvalidFeatureSpecError{
+			FeatureName: featureName,
+			Problem:     "failed to bind feature spec",
+		}
+	}
+
+	return vaultSpec, nil
+}
+
+func (s vaultFeatureSpec) Validate() error {
+	if len(s.SecretIDs) == 0 {
+		return errors.New("at least one secret ID must be provided")
+	}
+
+	for _, secretID := range s.SecretIDs {
+		if err := validateSecretID(secretID); err != nil {
+			return errors.WithDetails(err, "secretId", secretID)
+		}
+	}
+
+	if len(s.ServiceAccounts) == 0 {
+		return errors.New("at least one service account must be provided")
+	}
+
+	if len(s.Namespaces) == 0 {
+		return errors.New("at least one namespace must be provided")
+	}
+
+	for _, name := range append(s.ServiceAccounts, s.Namespaces...) {
+		if name == "" {
+			return errors.New("service account and namespace names must not be empty")
+		}
+	}
+
+	return nil
+}
+
+func validateSecretID(secretID string) error {
+	if secretID == "" {
+		return errors.New("secret ID must not be empty")
+	}
+
+	if strings.ContainsAny(secretID, "/*\"\\") {
+		return errors.New("secret ID must not contain path separators, globs or quotes")
+	}
+
+	return nil
+}
+
+// validateSecrets checks that every secret of the spec exists in the organization
+func (s vaultFeatureSpec) validateSecrets(ctx context.Context, secretStore features.SecretStore) error {
+	for _, secretID := range s.SecretIDs {
+		if _, err := secretStore.GetSecretValues(ctx, secretID); err != nil {
+			return errors.WrapIfWithDetails(err, "failed to get secret", "secretId", secretID)
+		}
+	}
+
+	return nil
+}
+
+// policy renders a Vault policy granting read access to the chosen secrets of the organization
+func (s vaultFeatureSpec) policy(orgID uint) string {
+	var rules strings.Builder
+
+	for _, secretID := range s.SecretIDs {
+		_, _ = fmt.Fprintf(&rules, "path \"secret/data/orgs/%d/%s\" {\n  capabilities = [\"read\"]\n}\n", orgID, secretID)
+	}
+
+	return rules.String()
+}
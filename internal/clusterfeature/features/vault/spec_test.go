@@ -0,0 +1,143 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/banzaicloud/pipeline/internal/common"
+)
+
+func TestVaultFeatureSpec_Validate(t *testing.T) {
+	tests := map[string]struct {
+		spec    vaultFeatureSpec
+		isValid bool
+	}{
+		"valid spec": {
+			spec: vaultFeatureSpec{
+				SecretIDs:       []string{"5c82a2fc5c8c4ad5ab6f4c0c8d9a1f09"},
+				ServiceAccounts: []string{"app"},
+				Namespaces:      []string{"default"},
+			},
+			isValid: true,
+		},
+		"no secret IDs": {
+			spec: vaultFeatureSpec{
+				ServiceAccounts: []string{"app"},
+				Namespaces:      []string{"default"},
+			},
+		},
+		"empty secret ID": {
+			spec: vaultFeatureSpec{
+				SecretIDs:       []string{""},
+				ServiceAccounts: []string{"app"},
+				Namespaces:      []string{"default"},
+			},
+		},
+		"secret path instead of ID": {
+			spec: vaultFeatureSpec{
+				SecretIDs:       []string{"../2/mysql-credentials"},
+				ServiceAccounts: []string{"app"},
+				Namespaces:      []string{"default"},
+			},
+		},
+		"glob secret ID": {
+			spec: vaultFeatureSpec{
+				SecretIDs:       []string{"*"},
+				ServiceAccounts: []string{"app"},
+				Namespaces:      []string{"default"},
+			},
+		},
+		"no service accounts": {
+			spec: vaultFeatureSpec{
+				SecretIDs:  []string{"5c82a2fc5c8c4ad5ab6f4c0c8d9a1f09"},
+				Namespaces: []string{"default"},
+			},
+		},
+		"no namespaces": {
+			spec: vaultFeatureSpec{
+				SecretIDs:       []string{"5c82a2fc5c8c4ad5ab6f4c0c8d9a1f09"},
+				ServiceAccounts: []string{"app"},
+			},
+		},
+		"empty namespace": {
+			spec: vaultFeatureSpec{
+				SecretIDs:       []string{"5c82a2fc5c8c4ad5ab6f4c0c8d9a1f09"},
+				ServiceAccounts: []string{"app"},
+				Namespaces:      []string{""},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			err := test.spec.Validate()
+
+			if test.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+type secretStoreStub map[string]map[string]string
+
+func (s secretStoreStub) GetSecretValues(_ context.Context, secretID string) (map[string]string, error) {
+	values, ok := s[secretID]
+	if !ok {
+		return nil, common.SecretNotFoundError{SecretID: secretID}
+	}
+
+	return values, nil
+}
+
+func TestVaultFeatureSpec_ValidateSecrets(t *testing.T) {
+	secretStore := secretStoreStub{
+		"existing": {"password": "secret"},
+	}
+
+	spec := vaultFeatureSpec{
+		SecretIDs: []string{"existing"},
+	}
+	assert.NoError(t, spec.validateSecrets(context.Background(), secretStore))
+
+	spec.SecretIDs = append(spec.SecretIDs, "missing")
+	err := spec.validateSecrets(context.Background(), secretStore)
+	assert.True(t, errors.As(err, &common.SecretNotFoundError{}))
+}
+
+func TestVaultFeatureSpec_Policy(t *testing.T) {
+	spec := vaultFeatureSpec{
+		SecretIDs: []string{"5c82a2fc5c8c4ad5ab6f4c0c8d9a1f09", "9f5a0d3e8f1c4b6a8e2d7c1b3a4f5e6d"},
+	}
+
+	expected := `path "secret/data/orgs/1/5c82a2fc5c8c4ad5ab6f4c0c8d9a1f09" {
+  capabilities = ["read"]
+}
+path "secret/data/orgs/1/9f5a0d3e8f1c4b6a8e2d7c1b3a4f5e6d" {
+  capabilities = ["read"]
+}
+`
+
+	assert.Equal(t, expected, spec.policy(1))
+}
@@ -0,0 +1,118 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"fmt"
+
+	"emperror.dev/errors"
+	vaultapi "github.com/hashicorp/vault/api"
+)
+
+const (
+	kubernetesAuthType = "kubernetes"
+
+	// workloadRole is the role used by the secrets webhook unless a pod requests another one
+	workloadRole = "default"
+
+	workloadTokenTTL = "1h"
+)
+
+// authPath returns the mount path of the Kubernetes auth method of a cluster
+func authPath(clusterID uint) string {
+	return fmt.Sprintf("kubernetes-cluster-%d", clusterID)
+}
+
+// policyName returns the name of the Vault policy of the cluster workloads
+func policyName(clusterID uint) string {
+	return fmt.Sprintf("pipeline-cluster-%d", clusterID)
+}
+
+type kubernetesAuthConfig struct {
+	Host             string
+	CACert           string
+	TokenReviewerJWT string
+}
+
+// ensureAuthMethod enables the Kubernetes auth method on the given path unless it is already enabled
+func ensureAuthMethod(client *vaultapi.Client, path string) error {
+	authMethods, err := client.Sys().ListAuth()
+	if err != nil {
+		return errors.WrapIf(err, "failed to list auth methods")
+	}
+
+	if _, ok := authMethods[path+"/"]; ok {
+		return nil
+	}
+
+	err = client.Sys().EnableAuthWithOptions(path, &vaultapi.EnableAuthOptions{
+		Type:        kubernetesAuthType,
+		Description: "Kubernetes auth method managed by Pipeline",
+	})
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to enable auth method", "path", path)
+	}
+
+	return nil
+}
+
+// configureAuthMethod sets the cluster the Kubernetes auth method reviews the tokens with
+func configureAuthMethod(client *vaultapi.Client, path string, config kubernetesAuthConfig) error {
+	_, err := client.Logical().Write(fmt.Sprintf("auth/%s/config", path), map[string]interface{}{
+		"kubernetes_host":    config.Host,
+		"kubernetes_ca_cert": config.CACert,
+		"token_reviewer_jwt": config.TokenReviewerJWT,
+	})
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to configure auth method", "path", path)
+	}
+
+	return nil
+}
+
+// putRole binds service accounts of the cluster to a policy
+func putRole(client *vaultapi.Client, path string, policy string, spec vaultFeatureSpec) error {
+	_, err := client.Logical().Write(fmt.Sprintf("auth/%s/role/%s", path, workloadRole), map[string]interface{}{
+		"bound_service_account_names":      spec.ServiceAccounts,
+		"bound_service_account_namespaces": spec.Namespaces,
+		"policies":                         []string{policy},
+		"ttl":                              workloadTokenTTL,
+	})
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to write role", "path", path, "role", workloadRole)
+	}
+
+	return nil
+}
+
+// removeClusterAccess disables the auth method (along with its roles) and deletes the policy of the cluster
+func removeClusterAccess(client *vaultapi.Client, path string, policy string) error {
+	authMethods, err := client.Sys().ListAuth()
+	if err != nil {
+		return errors.WrapIf(err, "failed to list auth methods")
+	}
+
+	if _, ok := authMethods[path+"/"]; ok {
+		if err := client.Sys().DisableAuth(path); err != nil {
+			return errors.WrapIfWithDetails(err, "failed to disable auth method", "path", path)
+		}
+	}
+
+	if err := client.Sys().DeletePolicy(policy); err != nil {
+		return errors.WrapIfWithDetails(err, "failed to delete policy", "policy", policy)
+	}
+
+	return nil
+}
@@ -17,6 +17,7 @@ package clusterfeature
 import (
 	"context"
 	"sync"
+	"time"
 
 	"emperror.dev/errors"
 )
@@ -24,7 +25,8 @@ import (
 // InMemoryFeatureRepository keeps features in the memory.
 // Use it in tests or for development/demo purposes.
 type InMemoryFeatureRepository struct {
-	features map[uint]map[string]Feature
+	features  map[uint]map[string]Feature
+	revisions map[uint]map[string][]FeatureRevision
 
 	mu sync.RWMutex
 }
@@ -32,7 +34,8 @@ type InMemoryFeatureRepository struct {
 // NewInMemoryFeatureRepository returns a new inmemory feature repository.
 func NewInMemoryFeatureRepository() *InMemoryFeatureRepository {
 	return &InMemoryFeatureRepository{
-		features: make(map[uint]map[string]Feature),
+		features:  make(map[uint]map[string]Feature),
+		revisions: make(map[uint]map[string][]FeatureRevision),
 	}
 }
 
@@ -166,3 +169,62 @@ func (r *InMemoryFeatureRepository) DeleteFeature(ctx context.Context, clusterID
 
 	return nil
 }
+
+func (r *InMemoryFeatureRepository) CreateFeatureRevision(ctx context.Context, clusterID uint, featureName string, action string, spec FeatureSpec) (uint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.revisions[clusterID]; !ok {
+		r.revisions[clusterID] = make(map[string][]FeatureRevision)
+	}
+
+	revision := FeatureRevision{
+		Revision:  uint(len(r.revisions[clusterID][featureName]) + 1),
+		Action:    action,
+		Spec:      spec,
+		Status:    FeatureRevisionStatusPending,
+		CreatedAt: time.Now(),
+	}
+
+	r.revisions[clusterID][featureName] = append(r.revisions[clusterID][featureName], revision)
+
+	return revision.Revision, nil
+}
+
+func (r *InMemoryFeatureRepository) UpdateFeatureRevisionStatus(ctx context.Context, clusterID uint, featureName string, revision uint, status string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	revisions := r.revisions[clusterID][featureName]
+	if revision == 0 || revision > uint(len(revisions)) {
+		return errors.NewWithDetails("feature revision not found", "feature", featureName, "revision", revision)
+	}
+
+	revisions[revision-1].Status = status
+
+	return nil
+}
+
+func (r *InMemoryFeatureRepository) GetFeatureRevisions(ctx context.Context, clusterID uint, featureName string) ([]FeatureRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	revisions := make([]FeatureRevision, len(r.revisions[clusterID][featureName]))
+	copy(revisions, r.revisions[clusterID][featureName])
+
+	return revisions, nil
+}
+
+func (r *InMemoryFeatureRepository) GetFeatureRevision(ctx context.Context, clusterID uint, featureName string, revision uint) (*FeatureRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	revisions := r.revisions[clusterID][featureName]
+	if revision == 0 || revision > uint(len(revisions)) {
+		return nil, nil
+	}
+
+	featureRevision := revisions[revision-1]
+
+	return &featureRevision, nil
+}
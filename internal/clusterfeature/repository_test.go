@@ -108,3 +108,40 @@ func TestInmemoryFeatureRepository_DeleteFeature(t *testing.T) {
 
 	assert.NotContains(t, repository.features[clusterID], feature.Name)
 }
+
+func TestInmemoryFeatureRepository_FeatureRevisions(t *testing.T) {
+	repository := NewInMemoryFeatureRepository()
+
+	clusterID := uint(1)
+	featureName := "myFeature"
+
+	revision, err := repository.CreateFeatureRevision(context.Background(), clusterID, featureName, FeatureRevisionActionActivate, FeatureSpec{"key": "value"})
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), revision)
+
+	revision, err = repository.CreateFeatureRevision(context.Background(), clusterID, featureName, FeatureRevisionActionUpdate, FeatureSpec{"key": "other"})
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), revision)
+
+	err = repository.UpdateFeatureRevisionStatus(context.Background(), clusterID, featureName, 1, FeatureRevisionStatusSucceeded)
+	require.NoError(t, err)
+
+	err = repository.UpdateFeatureRevisionStatus(context.Background(), clusterID, featureName, 3, FeatureRevisionStatusSucceeded)
+	require.Error(t, err)
+
+	revisions, err := repository.GetFeatureRevisions(context.Background(), clusterID, featureName)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+
+	assert.Equal(t, FeatureRevisionStatusSucceeded, revisions[0].Status)
+	assert.Equal(t, FeatureRevisionStatusPending, revisions[1].Status)
+	assert.Equal(t, FeatureSpec{"key": "other"}, revisions[1].Spec)
+
+	featureRevision, err := repository.GetFeatureRevision(context.Background(), clusterID, featureName, 2)
+	require.NoError(t, err)
+	assert.Equal(t, revisions[1], *featureRevision)
+
+	featureRevision, err = repository.GetFeatureRevision(context.Background(), clusterID, featureName, 3)
+	require.NoError(t, err)
+	assert.Nil(t, featureRevision)
+}
@@ -16,6 +16,7 @@ package clusterfeature
 
 import (
 	"context"
+	"time"
 
 	"emperror.dev/errors"
 
@@ -39,6 +40,31 @@ const (
 	FeatureStatusActive  = "ACTIVE"
 )
 
+// FeatureRevision is an immutable record of a requested feature state change.
+type FeatureRevision struct {
+	Revision  uint        `json:"revision"`
+	Action    string      `json:"action"`
+	Spec      FeatureSpec `json:"spec"`
+	Status    string      `json:"status"`
+	CreatedBy uint        `json:"createdBy"`
+	CreatedAt time.Time   `json:"createdAt"`
+}
+
+// Feature revision action constants
+const (
+	FeatureRevisionActionActivate   = "activate"
+	FeatureRevisionActionDeactivate = "deactivate"
+	FeatureRevisionActionUpdate     = "update"
+)
+
+// Feature revision status constants
+const (
+	FeatureRevisionStatusPending    = "PENDING"
+	FeatureRevisionStatusSucceeded  = "SUCCEEDED"
+	FeatureRevisionStatusFailed     = "FAILED"
+	FeatureRevisionStatusSuperseded = "SUPERSEDED"
+)
+
 // FeatureService manages features on Kubernetes clusters.
 type FeatureService struct {
 	featureRegistry   FeatureRegistry
@@ -76,6 +102,19 @@ type FeatureRepository interface {
 
 	// DeleteFeature deletes a feature.
 	DeleteFeature(ctx context.Context, clusterID uint, featureName string) error
+
+	// CreateFeatureRevision records a new revision of a feature and returns its revision number.
+	CreateFeatureRevision(ctx context.Context, clusterID uint, featureName string, action string, spec FeatureSpec) (uint, error)
+
+	// UpdateFeatureRevisionStatus updates the (resulting) status of a feature revision.
+	UpdateFeatureRevisionStatus(ctx context.Context, clusterID uint, featureName string, revision uint, status string) error
+
+	// GetFeatureRevisions retrieves the revisions of a feature (oldest first).
+	GetFeatureRevisions(ctx context.Context, clusterID uint, featureName string) ([]FeatureRevision, error)
+
+	// GetFeatureRevision retrieves a feature revision.
+	// Returns (nil, nil) in case the revision is not found.
+	GetFeatureRevision(ctx context.Context, clusterID uint, featureName string, revision uint) (*FeatureRevision, error)
 }
 
 // NewFeatureService returns a new FeatureService instance.
@@ -138,6 +177,20 @@ func (e FeatureNotFoundError) Details() []interface{} {
 	return []interface{}{"feature", e.FeatureName}
 }
 
+// FeatureRevisionNotFoundError is returned when a feature revision is not found.
+type FeatureRevisionNotFoundError struct {
+	FeatureName string
+	Revision    uint
+}
+
+func (FeatureRevisionNotFoundError) Error() string {
+	return "feature revision is not found"
+}
+
+func (e FeatureRevisionNotFoundError) Details() []interface{} {
+	return []interface{}{"feature", e.FeatureName, "revision", e.Revision}
+}
+
 // Details returns the details of an activated feature.
 func (s *FeatureService) Details(ctx context.Context, clusterID uint, featureName string) (*Feature, error) {
 	logger := s.logger.WithContext(ctx).WithFields(map[string]interface{}{"clusterId": clusterID, "feature": featureName})
@@ -258,3 +311,56 @@ func (s *FeatureService) Update(ctx context.Context, clusterID uint, featureName
 
 	return nil
 }
+
+// Revisions returns the revisions of a feature (latest first).
+func (s *FeatureService) Revisions(ctx context.Context, clusterID uint, featureName string) ([]FeatureRevision, error) {
+	logger := s.logger.WithContext(ctx).WithFields(map[string]interface{}{"clusterId": clusterID, "feature": featureName})
+	logger.Info("listing feature revisions")
+
+	logger.Debug("retieving feature manager")
+	if _, err := s.featureRegistry.GetFeatureManager(featureName); err != nil {
+		const msg = "failed to retieve feature manager"
+		logger.Debug(msg)
+		return nil, errors.WrapIf(err, msg)
+	}
+
+	revisions, err := s.featureRepository.GetFeatureRevisions(ctx, clusterID, featureName)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to retrieve feature revisions", "clusterId", clusterID, "feature", featureName)
+	}
+
+	for i, j := 0, len(revisions)-1; i < j; i, j = i+1, j-1 {
+		revisions[i], revisions[j] = revisions[j], revisions[i]
+	}
+
+	logger.Info("feature revisions successfully listed")
+
+	return revisions, nil
+}
+
+// ApplyRevision applies the spec of a previous feature revision.
+// The feature is activated if it's not active, updated otherwise.
+func (s *FeatureService) ApplyRevision(ctx context.Context, clusterID uint, featureName string, revision uint) error {
+	logger := s.logger.WithContext(ctx).WithFields(map[string]interface{}{"clusterId": clusterID, "feature": featureName, "revision": revision})
+	logger.Info("processing feature revision apply request")
+
+	featureRevision, err := s.featureRepository.GetFeatureRevision(ctx, clusterID, featureName, revision)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to retrieve feature revision", "clusterId", clusterID, "feature", featureName, "revision", revision)
+	}
+
+	if featureRevision == nil {
+		return FeatureRevisionNotFoundError{FeatureName: featureName, Revision: revision}
+	}
+
+	feature, err := s.featureRepository.GetFeature(ctx, clusterID, featureName)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to retrieve feature", "clusterId", clusterID, "feature", featureName)
+	}
+
+	if feature == nil {
+		return s.Activate(ctx, clusterID, featureName, featureRevision.Spec)
+	}
+
+	return s.Update(ctx, clusterID, featureName, featureRevision.Spec)
+}
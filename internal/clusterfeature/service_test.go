@@ -359,3 +359,103 @@ func TestFeatureService_Update_UpdateFails(t *testing.T) {
 	// TODO(laszlop): write tests for this scenario
 	// TODO(laszlop): specify the expected behavior
 }
+
+func TestFeatureService_Revisions(t *testing.T) {
+	repository := NewInMemoryFeatureRepository()
+	featureManager := NewSyncFeatureManager(&dummyFeatureManager{}, repository, commonadapter.NewNoopLogger())
+	registry := NewFeatureRegistry(map[string]FeatureManager{
+		featureManager.Name(): featureManager,
+	})
+	service := NewFeatureService(registry, repository, commonadapter.NewNoopLogger())
+
+	clusterID := uint(1)
+	featureName := featureManager.Name()
+
+	require.NoError(t, service.Activate(context.Background(), clusterID, featureName, FeatureSpec{"key": "value"}))
+	require.NoError(t, service.Update(context.Background(), clusterID, featureName, FeatureSpec{"key": "value", "other": "value"}))
+	require.Error(t, service.Deactivate(context.Background(), clusterID, featureName))
+
+	revisions, err := service.Revisions(context.Background(), clusterID, featureName)
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+
+	assert.Equal(t, uint(3), revisions[0].Revision)
+	assert.Equal(t, FeatureRevisionActionDeactivate, revisions[0].Action)
+	assert.Equal(t, FeatureRevisionStatusFailed, revisions[0].Status)
+	assert.Equal(t, FeatureSpec{"key": "value", "other": "value"}, revisions[0].Spec)
+
+	assert.Equal(t, uint(2), revisions[1].Revision)
+	assert.Equal(t, FeatureRevisionActionUpdate, revisions[1].Action)
+	assert.Equal(t, FeatureRevisionStatusSucceeded, revisions[1].Status)
+
+	assert.Equal(t, uint(1), revisions[2].Revision)
+	assert.Equal(t, FeatureRevisionActionActivate, revisions[2].Action)
+	assert.Equal(t, FeatureRevisionStatusSucceeded, revisions[2].Status)
+	assert.Equal(t, FeatureSpec{"key": "value"}, revisions[2].Spec)
+}
+
+func TestFeatureService_Revisions_UnknownFeature(t *testing.T) {
+	repository := NewInMemoryFeatureRepository()
+
+	registry := NewFeatureRegistry(map[string]FeatureManager{})
+	service := NewFeatureService(registry, repository, commonadapter.NewNoopLogger())
+
+	_, err := service.Revisions(context.Background(), 1, "notMyFeature")
+	require.Error(t, err)
+
+	assert.True(t, errors.As(err, &UnknownFeatureError{}))
+}
+
+func TestFeatureService_ApplyRevision(t *testing.T) {
+	repository := NewInMemoryFeatureRepository()
+	featureManager := NewSyncFeatureManager(&dummyFeatureManager{}, repository, commonadapter.NewNoopLogger())
+	registry := NewFeatureRegistry(map[string]FeatureManager{
+		featureManager.Name(): featureManager,
+	})
+	service := NewFeatureService(registry, repository, commonadapter.NewNoopLogger())
+
+	clusterID := uint(2)
+	featureName := featureManager.Name()
+
+	require.NoError(t, service.Activate(context.Background(), clusterID, featureName, FeatureSpec{"key": "value"}))
+	require.NoError(t, service.Update(context.Background(), clusterID, featureName, FeatureSpec{"key": "value", "other": "value"}))
+
+	err := service.ApplyRevision(context.Background(), clusterID, featureName, 1)
+	require.NoError(t, err)
+
+	feature, err := repository.GetFeature(context.Background(), clusterID, featureName)
+	require.NoError(t, err)
+	assert.Equal(t, FeatureSpec{"key": "value"}, feature.Spec)
+
+	// Deactivated features are activated again
+	require.NoError(t, service.Deactivate(context.Background(), clusterID, featureName))
+
+	err = service.ApplyRevision(context.Background(), clusterID, featureName, 2)
+	require.NoError(t, err)
+
+	feature, err = repository.GetFeature(context.Background(), clusterID, featureName)
+	require.NoError(t, err)
+	assert.Equal(t, FeatureSpec{"key": "value", "other": "value"}, feature.Spec)
+	assert.Equal(t, FeatureStatusActive, feature.Status)
+
+	revisions, err := service.Revisions(context.Background(), clusterID, featureName)
+	require.NoError(t, err)
+	require.Len(t, revisions, 5)
+	assert.Equal(t, FeatureRevisionActionActivate, revisions[0].Action)
+}
+
+func TestFeatureService_ApplyRevision_RevisionNotFound(t *testing.T) {
+	repository := NewInMemoryFeatureRepository()
+	featureManager := NewSyncFeatureManager(&dummyFeatureManager{}, repository, commonadapter.NewNoopLogger())
+	registry := NewFeatureRegistry(map[string]FeatureManager{
+		featureManager.Name(): featureManager,
+	})
+	service := NewFeatureService(registry, repository, commonadapter.NewNoopLogger())
+
+	featureName := featureManager.Name()
+
+	err := service.ApplyRevision(context.Background(), 1, featureName, 1)
+	require.Error(t, err)
+
+	assert.True(t, errors.Is(err, FeatureRevisionNotFoundError{FeatureName: featureName, Revision: 1}))
+}
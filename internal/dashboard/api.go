@@ -30,6 +30,7 @@ import (
 
 	"github.com/banzaicloud/pipeline/auth"
 	"github.com/banzaicloud/pipeline/cluster"
+	"github.com/banzaicloud/pipeline/internal/cluster/clustercost"
 	"github.com/banzaicloud/pipeline/internal/clustergroup"
 	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
 	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
@@ -41,6 +42,7 @@ import (
 type DashboardAPI struct {
 	clusterManager      *cluster.Manager
 	clusterGroupManager *clustergroup.Manager
+	costEstimator       *clustercost.Estimator
 	logger              logrus.FieldLogger
 	errorHandler        emperror.Handler
 }
@@ -48,12 +50,14 @@ type DashboardAPI struct {
 func NewDashboardAPI(
 	clusterManager *cluster.Manager,
 	clusterGroupManager *clustergroup.Manager,
+	costEstimator *clustercost.Estimator,
 	logger logrus.FieldLogger,
 	errorHandler emperror.Handler,
 ) *DashboardAPI {
 	return &DashboardAPI{
 		clusterManager:      clusterManager,
 		clusterGroupManager: clusterGroupManager,
+		costEstimator:       costEstimator,
 		logger:              logger,
 		errorHandler:        errorHandler,
 	}
@@ -101,16 +105,23 @@ func (d *DashboardAPI) GetDashboard(c *gin.Context) {
 	}
 
 	clusterResponse := make([]ClusterInfo, 0)
+	clusterCosts := make([]*clustercost.Cost, 0, i)
 	for j := 0; j < i; j++ {
 		c := <-clusterResponseChan
 		clusterResponse = append(clusterResponse, c)
+		clusterCosts = append(clusterCosts, c.Cost)
+	}
+
+	response := GetDashboardResponse{
+		Clusters: clusterResponse,
+		Cost:     clustercost.Summarize(clusterCosts),
 	}
 
 	if partialResponse {
-		c.JSON(http.StatusPartialContent, GetDashboardResponse{Clusters: clusterResponse})
+		c.JSON(http.StatusPartialContent, response)
 		return
 	}
-	c.JSON(http.StatusOK, GetDashboardResponse{Clusters: clusterResponse})
+	c.JSON(http.StatusOK, response)
 
 }
 
@@ -249,6 +260,8 @@ func (d *DashboardAPI) getClusterDashboardInfo(logger *logrus.Entry, commonClust
 		}
 	}
 
+	clusterInfo.Cost = d.costEstimator.Estimate(clusterStatus, commonCluster.GetScaleOptions())
+
 	kubeConfig, err := commonCluster.GetK8sConfig()
 	if err != nil {
 		partialResponse = true
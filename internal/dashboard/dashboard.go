@@ -16,6 +16,8 @@ package dashboard
 
 import (
 	"time"
+
+	"github.com/banzaicloud/pipeline/internal/cluster/clustercost"
 )
 
 type Allocatable struct {
@@ -96,12 +98,14 @@ type ClusterInfo struct {
 	CpuUsagePercent     float64             `json:"cpuUsagePercent"`
 	StorageUsagePercent float64             `json:"storageUsagePercent"`
 	MemoryUsagePercent  float64             `json:"memoryUsagePercent"`
+	Cost                *clustercost.Cost   `json:"cost,omitempty"`
 }
 
 // GetDashboardResponse Api object to be mapped to Get dashboard request
 // swagger:model GetDashboardResponse
 type GetDashboardResponse struct {
-	Clusters []ClusterInfo `json:"clusters"`
+	Clusters []ClusterInfo        `json:"clusters"`
+	Cost     *clustercost.Summary `json:"cost"`
 }
 
 // GetProviderPathParams is a placeholder for the GetDashboard route path parameters
@@ -40,7 +40,7 @@ func newCertGenerator() *cert.Generator {
 		)
 
 	case "vault":
-		caLoader = cert.NewVaultCALoader(secret.Store.Logical, viper.GetString("cert.path"))
+		caLoader = cert.NewVaultCALoader(secret.VaultClient().Vault().Logical(), viper.GetString("cert.path"))
 	}
 
 	generator := cert.NewGenerator(cert.NewCACache(caLoader))
@@ -0,0 +1,116 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	k8sHelm "k8s.io/helm/pkg/helm"
+	rls "k8s.io/helm/pkg/proto/hapi/services"
+
+	"github.com/banzaicloud/pipeline/helm"
+	pkgHelm "github.com/banzaicloud/pipeline/pkg/helm"
+)
+
+// releaseClient manages Helm releases of a cluster.
+type releaseClient interface {
+	ListDeployments(releaseName string, kubeConfig []byte) (*rls.ListReleasesResponse, error)
+	CreateDeployment(
+		organizationName string,
+		chartName string,
+		chartVersion string,
+		namespace string,
+		releaseName string,
+		kubeConfig []byte,
+		options ...k8sHelm.InstallOption,
+	) error
+	UpgradeDeployment(
+		organizationName string,
+		releaseName string,
+		chartName string,
+		chartVersion string,
+		values []byte,
+		kubeConfig []byte,
+	) error
+	DeleteDeployment(releaseName string, kubeConfig []byte) error
+	GetDeploymentHistory(releaseName string, kubeConfig []byte, max int32) (*pkgHelm.GetDeploymentHistoryResponse, error)
+	RollbackDeployment(releaseName string, kubeConfig []byte, version int32, wait bool) error
+}
+
+// legacyReleaseClient manages releases through the legacy helm package.
+type legacyReleaseClient struct{}
+
+func (legacyReleaseClient) ListDeployments(releaseName string, kubeConfig []byte) (*rls.ListReleasesResponse, error) {
+	return helm.ListDeployments(&releaseName, "", kubeConfig)
+}
+
+func (legacyReleaseClient) CreateDeployment(
+	organizationName string,
+	chartName string,
+	chartVersion string,
+	namespace string,
+	releaseName string,
+	kubeConfig []byte,
+	options ...k8sHelm.InstallOption,
+) error {
+	_, err := helm.CreateDeployment(
+		chartName,
+		chartVersion,
+		nil,
+		namespace,
+		releaseName,
+		false,
+		nil,
+		kubeConfig,
+		helm.GenerateHelmRepoEnv(organizationName), // TODO: refactor!!!!!!
+		options...,
+	)
+
+	return err
+}
+
+func (legacyReleaseClient) UpgradeDeployment(
+	organizationName string,
+	releaseName string,
+	chartName string,
+	chartVersion string,
+	values []byte,
+	kubeConfig []byte,
+) error {
+	_, err := helm.UpgradeDeployment(
+		releaseName,
+		chartName,
+		chartVersion,
+		nil,
+		values,
+		false,
+		kubeConfig,
+		helm.GenerateHelmRepoEnv(organizationName), // TODO: refactor!!!!!!
+	)
+
+	return err
+}
+
+func (legacyReleaseClient) DeleteDeployment(releaseName string, kubeConfig []byte) error {
+	return helm.DeleteDeployment(releaseName, kubeConfig)
+}
+
+func (legacyReleaseClient) GetDeploymentHistory(releaseName string, kubeConfig []byte, max int32) (*pkgHelm.GetDeploymentHistoryResponse, error) {
+	return helm.GetDeploymentHistory(releaseName, kubeConfig, max)
+}
+
+func (legacyReleaseClient) RollbackDeployment(releaseName string, kubeConfig []byte, version int32, wait bool) error {
+	_, err := helm.RollbackDeployment(releaseName, kubeConfig, version, wait, 0)
+
+	return err
+}
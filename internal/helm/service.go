@@ -21,8 +21,8 @@ import (
 	k8sHelm "k8s.io/helm/pkg/helm"
 	"k8s.io/helm/pkg/proto/hapi/release"
 
-	"github.com/banzaicloud/pipeline/helm"
 	"github.com/banzaicloud/pipeline/internal/common"
+	pkgHelm "github.com/banzaicloud/pipeline/pkg/helm"
 )
 
 // ClusterService provides a thin access layer to clusters.
@@ -40,6 +40,7 @@ type Cluster struct {
 // HelmService provides an interface for using Helm on a specific cluster.
 type HelmService struct {
 	clusters ClusterService
+	releases releaseClient
 
 	logger common.Logger
 }
@@ -48,6 +49,7 @@ type HelmService struct {
 func NewHelmService(clusters ClusterService, logger common.Logger) *HelmService {
 	return &HelmService{
 		clusters: clusters,
+		releases: legacyReleaseClient{},
 
 		logger: logger.WithFields(map[string]interface{}{"component": "helm"}),
 	}
@@ -86,7 +88,7 @@ func (s *HelmService) InstallDeployment(
 
 			return nil
 		case release.Status_FAILED:
-			err := helm.DeleteDeployment(releaseName, cluster.KubeConfig)
+			err := s.releases.DeleteDeployment(releaseName, cluster.KubeConfig)
 			if err != nil {
 				return errors.WrapIfWithDetails(
 					err, "failed to delete deployment",
@@ -101,16 +103,13 @@ func (s *HelmService) InstallDeployment(
 		k8sHelm.InstallWait(wait),
 		k8sHelm.ValueOverrides(values),
 	}
-	_, err = helm.CreateDeployment(
+	err = s.releases.CreateDeployment(
+		cluster.OrganizationName,
 		chartName,
 		chartVersion,
-		nil,
 		namespace,
 		releaseName,
-		false,
-		nil,
 		cluster.KubeConfig,
-		helm.GenerateHelmRepoEnv(cluster.OrganizationName), // TODO: refactor!!!!!!
 		options...,
 	)
 	if err != nil {
@@ -153,22 +152,35 @@ func (s *HelmService) UpdateDeployment(
 	if foundRelease != nil {
 		switch foundRelease.GetInfo().GetStatus().GetCode() {
 		case release.Status_DEPLOYED:
-			_, err = helm.UpgradeDeployment(
+			err = s.releases.UpgradeDeployment(
+				cluster.OrganizationName,
 				releaseName,
 				chartName,
 				chartVersion,
-				nil,
 				values,
-				false,
 				cluster.KubeConfig,
-				helm.GenerateHelmRepoEnv(cluster.OrganizationName), // TODO: refactor!!!!!!
 			)
 			if err != nil {
-				return errors.WrapIfWithDetails(
+				err = errors.WrapIfWithDetails(
 					err, "failed to update deployment",
 					"chart", chartName,
 					"release", releaseName,
 				)
+
+				// roll back to the last successfully deployed revision
+				previousVersion := foundRelease.GetVersion()
+				logger.Info("rolling back failed deployment update", map[string]interface{}{"version": previousVersion})
+
+				rollbackErr := s.releases.RollbackDeployment(releaseName, cluster.KubeConfig, previousVersion, false)
+				if rollbackErr != nil {
+					return errors.Combine(err, errors.WrapIfWithDetails(
+						rollbackErr, "failed to roll back deployment",
+						"release", releaseName,
+						"version", previousVersion,
+					))
+				}
+
+				return err
 			}
 		}
 	}
@@ -194,7 +206,7 @@ func (s *HelmService) DeleteDeployment(ctx context.Context, clusterID uint, rele
 	}
 
 	if foundRelease != nil {
-		err = helm.DeleteDeployment(releaseName, cluster.KubeConfig)
+		err = s.releases.DeleteDeployment(releaseName, cluster.KubeConfig)
 		if err != nil {
 			return errors.WrapIfWithDetails(
 				err, "failed to delete deployment",
@@ -209,8 +221,58 @@ func (s *HelmService) DeleteDeployment(ctx context.Context, clusterID uint, rele
 
 }
 
+// GetDeploymentHistory returns the revisions of a deployment on a specific cluster (latest first).
+func (s *HelmService) GetDeploymentHistory(
+	ctx context.Context,
+	clusterID uint,
+	releaseName string,
+	max int32,
+) (*pkgHelm.GetDeploymentHistoryResponse, error) {
+	cluster, err := s.clusters.GetCluster(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.releases.GetDeploymentHistory(releaseName, cluster.KubeConfig, max)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to get deployment history", "release", releaseName)
+	}
+
+	return history, nil
+}
+
+// RollbackDeployment rolls back a deployment on a specific cluster to the given revision.
+func (s *HelmService) RollbackDeployment(
+	ctx context.Context,
+	clusterID uint,
+	releaseName string,
+	version int32,
+	wait bool,
+) error {
+	logger := s.logger.WithContext(ctx).WithFields(map[string]interface{}{"release": releaseName, "version": version})
+	logger.Info("rolling back deployment")
+
+	cluster, err := s.clusters.GetCluster(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+
+	err = s.releases.RollbackDeployment(releaseName, cluster.KubeConfig, version, wait)
+	if err != nil {
+		return errors.WrapIfWithDetails(
+			err, "failed to roll back deployment",
+			"release", releaseName,
+			"version", version,
+		)
+	}
+
+	logger.Info("deployment rolled back successfully")
+
+	return nil
+}
+
 func (s *HelmService) findRelease(releaseName string, cluster *Cluster) (*release.Release, error) {
-	deployments, err := helm.ListDeployments(&releaseName, "", cluster.KubeConfig)
+	deployments, err := s.releases.ListDeployments(releaseName, cluster.KubeConfig)
 	if err != nil {
 		return nil, errors.WrapIfWithDetails(err, "failed to fetch deployments", "release", releaseName)
 	}
@@ -0,0 +1,164 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8sHelm "k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	rls "k8s.io/helm/pkg/proto/hapi/services"
+
+	"github.com/banzaicloud/pipeline/internal/common/commonadapter"
+	pkgHelm "github.com/banzaicloud/pipeline/pkg/helm"
+)
+
+type releaseClientStub struct {
+	releases []*release.Release
+
+	upgradeErr  error
+	rollbackErr error
+
+	upgraded   bool
+	rolledBack []int32
+}
+
+func (c *releaseClientStub) ListDeployments(releaseName string, kubeConfig []byte) (*rls.ListReleasesResponse, error) {
+	return &rls.ListReleasesResponse{Releases: c.releases}, nil
+}
+
+func (c *releaseClientStub) CreateDeployment(string, string, string, string, string, []byte, ...k8sHelm.InstallOption) error {
+	return nil
+}
+
+func (c *releaseClientStub) UpgradeDeployment(string, string, string, string, []byte, []byte) error {
+	c.upgraded = true
+
+	return c.upgradeErr
+}
+
+func (c *releaseClientStub) DeleteDeployment(releaseName string, kubeConfig []byte) error {
+	return nil
+}
+
+func (c *releaseClientStub) GetDeploymentHistory(string, []byte, int32) (*pkgHelm.GetDeploymentHistoryResponse, error) {
+	return &pkgHelm.GetDeploymentHistoryResponse{}, nil
+}
+
+func (c *releaseClientStub) RollbackDeployment(releaseName string, kubeConfig []byte, version int32, wait bool) error {
+	c.rolledBack = append(c.rolledBack, version)
+
+	return c.rollbackErr
+}
+
+func newTestRelease(name string, version int32, status release.Status_Code) *release.Release {
+	return &release.Release{
+		Name:    name,
+		Version: version,
+		Info:    &release.Info{Status: &release.Status{Code: status}},
+	}
+}
+
+func TestHelmService_UpdateDeployment(t *testing.T) {
+	const releaseName = "dns"
+
+	tests := map[string]struct {
+		releases    []*release.Release
+		upgradeErr  error
+		rollbackErr error
+
+		expectedUpgrade    bool
+		expectedRolledBack []int32
+		expectedErr        bool
+	}{
+		"successful update": {
+			releases:        []*release.Release{newTestRelease(releaseName, 3, release.Status_DEPLOYED)},
+			expectedUpgrade: true,
+		},
+		"release not installed": {
+			releases: []*release.Release{newTestRelease("other", 1, release.Status_DEPLOYED)},
+		},
+		"release not deployed": {
+			releases: []*release.Release{newTestRelease(releaseName, 2, release.Status_FAILED)},
+		},
+		"failed update is rolled back": {
+			releases:           []*release.Release{newTestRelease(releaseName, 3, release.Status_DEPLOYED)},
+			upgradeErr:         errors.New("upgrade failed"),
+			expectedUpgrade:    true,
+			expectedRolledBack: []int32{3},
+			expectedErr:        true,
+		},
+		"failed rollback": {
+			releases:           []*release.Release{newTestRelease(releaseName, 3, release.Status_DEPLOYED)},
+			upgradeErr:         errors.New("upgrade failed"),
+			rollbackErr:        errors.New("rollback failed"),
+			expectedUpgrade:    true,
+			expectedRolledBack: []int32{3},
+			expectedErr:        true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			releases := &releaseClientStub{
+				releases:    test.releases,
+				upgradeErr:  test.upgradeErr,
+				rollbackErr: test.rollbackErr,
+			}
+
+			service := NewHelmService(&clusterServiceStub{}, commonadapter.NewNoopLogger())
+			service.releases = releases
+
+			err := service.UpdateDeployment(context.Background(), 1, "pipeline-system", "stable/chart", releaseName, nil, "1.0.0")
+
+			if test.expectedErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "upgrade failed")
+
+				if test.rollbackErr != nil {
+					assert.Contains(t, err.Error(), "rollback failed")
+				}
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expectedUpgrade, releases.upgraded)
+			assert.Equal(t, test.expectedRolledBack, releases.rolledBack)
+		})
+	}
+}
+
+func TestHelmService_RollbackDeployment(t *testing.T) {
+	releases := &releaseClientStub{}
+
+	service := NewHelmService(&clusterServiceStub{}, commonadapter.NewNoopLogger())
+	service.releases = releases
+
+	err := service.RollbackDeployment(context.Background(), 1, "dns", 2, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, []int32{2}, releases.rolledBack)
+
+	releases.rollbackErr = errors.New("rollback failed")
+
+	err = service.RollbackDeployment(context.Background(), 1, "dns", 1, false)
+	assert.Error(t, err)
+}
@@ -0,0 +1,159 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"fmt"
+	"net"
+)
+
+// ValidationError is returned when a network or subnetwork cannot be created as requested
+type ValidationError struct {
+	message string
+}
+
+// NewValidationError returns a new ValidationError with a formatted message
+func NewValidationError(format string, args ...interface{}) ValidationError {
+	return ValidationError{message: fmt.Sprintf(format, args...)}
+}
+
+// Error implements the error interface
+func (e ValidationError) Error() string {
+	return e.message
+}
+
+// InputValidationError marks the error as a client side one
+func (ValidationError) InputValidationError() bool {
+	return true
+}
+
+// NetworkNotFoundError is returned when the network of a subnetwork does not exist
+type NetworkNotFoundError struct {
+	NetworkID string
+}
+
+// Error implements the error interface
+func (e NetworkNotFoundError) Error() string {
+	return fmt.Sprintf("network %q not found", e.NetworkID)
+}
+
+// ParseCIDR parses an IPv4 CIDR block
+func ParseCIDR(cidr string) (*net.IPNet, error) {
+	ip, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil || ip.To4() == nil {
+		return nil, NewValidationError("invalid IPv4 CIDR block: %q", cidr)
+	}
+
+	if !ip.Equal(ipNet.IP) {
+		return nil, NewValidationError("%q is not a network address, did you mean %q?", cidr, ipNet.String())
+	}
+
+	return ipNet, nil
+}
+
+// Overlaps returns whether two CIDR blocks have common addresses
+func Overlaps(a *net.IPNet, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// Contains returns whether the outer CIDR block contains every address of the inner one
+func Contains(outer *net.IPNet, inner *net.IPNet) bool {
+	outerSize, _ := outer.Mask.Size()
+	innerSize, _ := inner.Mask.Size()
+
+	return outer.Contains(inner.IP) && outerSize <= innerSize
+}
+
+// ValidateNetworkCIDR checks that the CIDR block of a new network does not overlap with the existing networks
+func ValidateNetworkCIDR(cidr string, networks []Network) error {
+	ipNet, err := ParseCIDR(cidr)
+	if err != nil {
+		return err
+	}
+
+	for _, network := range networks {
+		for _, existing := range network.CIDRs() {
+			if overlapsWith(ipNet, existing) {
+				return NewValidationError("CIDR block %s overlaps with %s of network %s", cidr, existing, describe(network.ID(), network.Name()))
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateSubnetCIDR checks that the CIDR block of a new subnetwork is within the network
+// and does not overlap with the existing subnetworks
+func ValidateSubnetCIDR(cidr string, network Network, subnets []Subnet) error {
+	ipNet, err := ParseCIDR(cidr)
+	if err != nil {
+		return err
+	}
+
+	// networks without address space (eg. Google VPC networks) accept any subnetwork
+	networkCIDRs := nonEmpty(network.CIDRs())
+	if len(networkCIDRs) > 0 {
+		var inNetwork bool
+		for _, networkCIDR := range networkCIDRs {
+			_, networkNet, err := net.ParseCIDR(networkCIDR)
+			if err == nil && Contains(networkNet, ipNet) {
+				inNetwork = true
+				break
+			}
+		}
+
+		if !inNetwork {
+			return NewValidationError("CIDR block %s is not within the address space of network %s", cidr, describe(network.ID(), network.Name()))
+		}
+	}
+
+	for _, subnet := range subnets {
+		for _, existing := range subnet.CIDRs() {
+			if overlapsWith(ipNet, existing) {
+				return NewValidationError("CIDR block %s overlaps with %s of subnetwork %s", cidr, existing, describe(subnet.ID(), subnet.Name()))
+			}
+		}
+	}
+
+	return nil
+}
+
+func overlapsWith(ipNet *net.IPNet, cidr string) bool {
+	_, other, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false
+	}
+
+	return Overlaps(ipNet, other)
+}
+
+func nonEmpty(values []string) []string {
+	var result []string
+	for _, value := range values {
+		if value != "" {
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
+
+func describe(id string, name string) string {
+	if name == "" || name == id {
+		return id
+	}
+
+	return fmt.Sprintf("%s (%s)", name, id)
+}
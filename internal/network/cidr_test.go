@@ -0,0 +1,149 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testNetwork struct {
+	cidrs []string
+	id    string
+}
+
+func (n testNetwork) CIDRs() []string { return n.cidrs }
+func (n testNetwork) ID() string      { return n.id }
+func (n testNetwork) Name() string    { return n.id }
+
+type testSubnet struct {
+	cidrs []string
+	id    string
+}
+
+func (s testSubnet) CIDRs() []string  { return s.cidrs }
+func (s testSubnet) ID() string       { return s.id }
+func (s testSubnet) Location() string { return "" }
+func (s testSubnet) Name() string     { return s.id }
+
+type testService struct {
+	networks []Network
+	subnets  map[string][]Subnet
+
+	createdNetworks []CreateNetworkRequest
+	createdSubnets  []CreateSubnetRequest
+}
+
+func (s *testService) ListNetworks() ([]Network, error) {
+	return s.networks, nil
+}
+
+func (s *testService) ListRouteTables(networkID string) ([]RouteTable, error) {
+	return nil, nil
+}
+
+func (s *testService) ListSubnets(networkID string) ([]Subnet, error) {
+	return s.subnets[networkID], nil
+}
+
+func (s *testService) CreateNetwork(request CreateNetworkRequest) (Network, error) {
+	s.createdNetworks = append(s.createdNetworks, request)
+	return testNetwork{cidrs: []string{request.CIDR}, id: request.Name}, nil
+}
+
+func (s *testService) DeleteNetwork(networkID string) error {
+	return nil
+}
+
+func (s *testService) CreateSubnet(request CreateSubnetRequest) (Subnet, error) {
+	s.createdSubnets = append(s.createdSubnets, request)
+	return testSubnet{cidrs: []string{request.CIDR}, id: request.Name}, nil
+}
+
+func (s *testService) DeleteSubnet(networkID string, subnetID string) error {
+	return nil
+}
+
+func TestParseCIDR(t *testing.T) {
+	_, err := ParseCIDR("10.0.0.0/16")
+	assert.NoError(t, err)
+
+	for _, cidr := range []string{"", "10.0.0.0", "10.0.0.0/33", "10.0.1.0/16", "fd00::/8"} {
+		_, err := ParseCIDR(cidr)
+		assert.True(t, errors.As(err, &ValidationError{}), cidr)
+	}
+}
+
+func TestCreateNetwork(t *testing.T) {
+	svc := &testService{
+		networks: []Network{
+			testNetwork{cidrs: []string{"10.0.0.0/16"}, id: "vpc-1"},
+			testNetwork{cidrs: []string{""}, id: "vpc-2"},
+		},
+	}
+
+	for _, cidr := range []string{"10.0.0.0/16", "10.0.128.0/17", "10.0.0.0/8"} {
+		_, err := CreateNetwork(svc, CreateNetworkRequest{Name: "new", CIDR: cidr})
+		assert.True(t, errors.As(err, &ValidationError{}), cidr)
+	}
+
+	_, err := CreateNetwork(svc, CreateNetworkRequest{CIDR: "10.1.0.0/16"})
+	assert.True(t, errors.As(err, &ValidationError{}))
+
+	network, err := CreateNetwork(svc, CreateNetworkRequest{Name: "new", CIDR: "10.1.0.0/16"})
+	require.NoError(t, err)
+	assert.Equal(t, "new", network.ID())
+	assert.Len(t, svc.createdNetworks, 1)
+}
+
+func TestCreateSubnet(t *testing.T) {
+	svc := &testService{
+		networks: []Network{
+			testNetwork{cidrs: []string{"10.0.0.0/16", "10.2.0.0/16"}, id: "vpc-1"},
+			testNetwork{id: "global"},
+		},
+		subnets: map[string][]Subnet{
+			"vpc-1":  {testSubnet{cidrs: []string{"10.0.0.0/24"}, id: "subnet-1"}},
+			"global": {testSubnet{cidrs: []string{"192.168.0.0/24"}, id: "subnet-2"}},
+		},
+	}
+
+	for name, request := range map[string]CreateSubnetRequest{
+		"missing cidr":       {NetworkID: "vpc-1", Name: "new"},
+		"outside network":    {NetworkID: "vpc-1", Name: "new", CIDR: "10.1.0.0/24"},
+		"larger than vpc":    {NetworkID: "vpc-1", Name: "new", CIDR: "10.0.0.0/15"},
+		"overlapping subnet": {NetworkID: "vpc-1", Name: "new", CIDR: "10.0.0.128/25"},
+		"overlapping global": {NetworkID: "global", Name: "new", CIDR: "192.168.0.0/16"},
+	} {
+		_, err := CreateSubnet(svc, request)
+		assert.True(t, errors.As(err, &ValidationError{}), name)
+	}
+
+	_, err := CreateSubnet(svc, CreateSubnetRequest{NetworkID: "vpc-2", Name: "new", CIDR: "10.0.1.0/24"})
+	assert.True(t, errors.As(err, &NetworkNotFoundError{}))
+
+	for _, request := range []CreateSubnetRequest{
+		{NetworkID: "vpc-1", Name: "new", CIDR: "10.0.1.0/24"},
+		{NetworkID: "vpc-1", Name: "new", CIDR: "10.2.0.0/16"},
+		{NetworkID: "global", Name: "new", CIDR: "172.16.0.0/24"},
+	} {
+		_, err := CreateSubnet(svc, request)
+		assert.NoError(t, err, request.CIDR)
+	}
+	assert.Len(t, svc.createdSubnets, 3)
+}
@@ -0,0 +1,108 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"encoding/binary"
+	"net"
+
+	"github.com/banzaicloud/pipeline/internal/network"
+)
+
+// DefaultPools are the private address ranges (RFC 1918) free blocks are suggested from by default
+// nolint: gochecknoglobals
+var DefaultPools = []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"}
+
+// NoFreeBlockError is returned when none of the pools has a free block of the requested size
+type NoFreeBlockError struct {
+	PrefixLength int
+}
+
+// Error implements the error interface
+func (e NoFreeBlockError) Error() string {
+	return "no free block of the requested size is available"
+}
+
+type block struct {
+	first uint32
+	last  uint32
+}
+
+func newBlock(ipNet *net.IPNet) block {
+	first := binary.BigEndian.Uint32(ipNet.IP.To4())
+	ones, _ := ipNet.Mask.Size()
+
+	return block{
+		first: first,
+		last:  first | uint32(uint64(1)<<uint(32-ones)-1),
+	}
+}
+
+// NextFreeBlock returns the first block with the given prefix length in the pools
+// which does not overlap with any of the allocations (including candidate ones).
+func NextFreeBlock(prefixLength int, pools []string, allocations []Allocation) (string, error) {
+	if prefixLength < 1 || prefixLength > 32 {
+		return "", network.NewValidationError("invalid prefix length: %d", prefixLength)
+	}
+
+	if len(pools) == 0 {
+		pools = DefaultPools
+	}
+
+	var used []block
+	for _, allocation := range allocations {
+		if _, ipNet, err := net.ParseCIDR(allocation.CIDR); err == nil && ipNet.IP.To4() != nil {
+			used = append(used, newBlock(ipNet))
+		}
+	}
+
+	size := uint64(1) << uint(32-prefixLength)
+
+	for _, pool := range pools {
+		poolNet, err := network.ParseCIDR(pool)
+		if err != nil {
+			return "", err
+		}
+
+		poolBlock := newBlock(poolNet)
+		if poolOnes, _ := poolNet.Mask.Size(); poolOnes > prefixLength {
+			continue
+		}
+
+		for candidate := uint64(poolBlock.first); candidate+size-1 <= uint64(poolBlock.last); {
+			next := block{first: uint32(candidate), last: uint32(candidate + size - 1)}
+
+			var overlapping *block
+			for i := range used {
+				if used[i].first <= next.last && next.first <= used[i].last {
+					overlapping = &used[i]
+					break
+				}
+			}
+
+			if overlapping == nil {
+				ip := make(net.IP, net.IPv4len)
+				binary.BigEndian.PutUint32(ip, next.first)
+
+				return (&net.IPNet{IP: ip, Mask: net.CIDRMask(prefixLength, 32)}).String(), nil
+			}
+
+			// continue with the first aligned block after the overlapping range
+			candidate = (uint64(overlapping.last)/size + 1) * size
+		}
+	}
+
+	return "", NoFreeBlockError{PrefixLength: prefixLength}
+}
@@ -0,0 +1,319 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/cluster"
+	"github.com/banzaicloud/pipeline/internal/network"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// Kinds of allocated address ranges
+const (
+	KindPod     = "pod"
+	KindService = "service"
+	KindNetwork = "network"
+)
+
+// Allocation describes an address range used by a cluster or a network of an organization
+type Allocation struct {
+	CIDR        string `json:"cidr"`
+	Kind        string `json:"kind"`
+	ClusterID   uint   `json:"clusterId,omitempty"`
+	ClusterName string `json:"clusterName,omitempty"`
+	NetworkID   string `json:"networkId,omitempty"`
+	NetworkName string `json:"networkName,omitempty"`
+
+	// Candidate is set for ranges which the cluster may use, but it is not known whether it actually does
+	// (eg. EKS picks the service range from two fixed ones).
+	// Candidates are not reported as overlaps, but new blocks are never suggested from them.
+	Candidate bool `json:"candidate,omitempty"`
+}
+
+func (a Allocation) String() string {
+	switch {
+	case a.ClusterName != "":
+		return fmt.Sprintf("%s range %s of cluster %s", a.Kind, a.CIDR, a.ClusterName)
+	case a.NetworkID != "":
+		return fmt.Sprintf("%s range %s of network %s", a.Kind, a.CIDR, a.NetworkID)
+	default:
+		return fmt.Sprintf("%s range %s", a.Kind, a.CIDR)
+	}
+}
+
+// UnknownCluster describes a cluster whose address ranges could not be determined
+type UnknownCluster struct {
+	ClusterID   uint   `json:"clusterId"`
+	ClusterName string `json:"clusterName"`
+	Error       string `json:"error"`
+}
+
+// AllocationList describes the address ranges used in an organization
+type AllocationList struct {
+	Allocations     []Allocation     `json:"allocations"`
+	UnknownClusters []UnknownCluster `json:"unknownClusters,omitempty"`
+}
+
+// AddNetworks adds the address ranges of VPC networks to the list
+func (l *AllocationList) AddNetworks(networks []network.Network) {
+	for _, n := range networks {
+		for _, cidr := range n.CIDRs() {
+			if cidr == "" {
+				continue
+			}
+
+			l.Allocations = append(l.Allocations, Allocation{
+				CIDR:        cidr,
+				Kind:        KindNetwork,
+				NetworkID:   n.ID(),
+				NetworkName: n.Name(),
+			})
+		}
+	}
+}
+
+// Overlap describes a requested address range which overlaps with an existing one
+type Overlap struct {
+	Requested Allocation `json:"requested"`
+	Existing  Allocation `json:"existing"`
+}
+
+// OverlapError is returned when the address ranges of a new cluster overlap with existing ones
+type OverlapError struct {
+	Overlaps []Overlap
+}
+
+// Error implements the error interface
+func (e OverlapError) Error() string {
+	descriptions := make([]string, 0, len(e.Overlaps))
+	for _, overlap := range e.Overlaps {
+		descriptions = append(descriptions, fmt.Sprintf("%s overlaps with %s", overlap.Requested, overlap.Existing))
+	}
+
+	return strings.Join(descriptions, "; ")
+}
+
+// ClusterLister lists the clusters of an organization
+type ClusterLister interface {
+	GetClusters(ctx context.Context, organizationID uint) ([]cluster.CommonCluster, error)
+}
+
+// Planner collects the address ranges used in an organization and plans new ones
+type Planner struct {
+	clusters ClusterLister
+	logger   logrus.FieldLogger
+}
+
+// NewPlanner returns a new Planner
+func NewPlanner(clusters ClusterLister, logger logrus.FieldLogger) *Planner {
+	return &Planner{
+		clusters: clusters,
+		logger:   logger,
+	}
+}
+
+// ListAllocations returns the pod, service and network ranges of the clusters of an organization.
+// Clusters whose ranges cannot be determined are listed separately.
+func (p *Planner) ListAllocations(ctx context.Context, organizationID uint) (*AllocationList, error) {
+	clusters, err := p.clusters.GetClusters(ctx, organizationID)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to list clusters", "organization", organizationID)
+	}
+
+	list := &AllocationList{
+		Allocations: []Allocation{},
+	}
+
+	for _, c := range clusters {
+		allocations, err := ClusterAllocations(c)
+		if err != nil {
+			p.logger.WithFields(logrus.Fields{
+				"organization": organizationID,
+				"cluster":      c.GetID(),
+			}).Warnf("failed to get address ranges of cluster: %s", err.Error())
+
+			list.UnknownClusters = append(list.UnknownClusters, UnknownCluster{
+				ClusterID:   c.GetID(),
+				ClusterName: c.GetName(),
+				Error:       err.Error(),
+			})
+			continue
+		}
+
+		list.Allocations = append(list.Allocations, allocations...)
+	}
+
+	return list, nil
+}
+
+// CheckOverlaps returns an OverlapError if any of the requested ranges overlap with the ones used by
+// the clusters of the organization.
+func (p *Planner) CheckOverlaps(ctx context.Context, organizationID uint, requested []Allocation) error {
+	if len(requested) == 0 {
+		return nil
+	}
+
+	list, err := p.ListAllocations(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+
+	if overlaps := FindOverlaps(requested, list.Allocations); len(overlaps) > 0 {
+		return OverlapError{Overlaps: overlaps}
+	}
+
+	return nil
+}
+
+// ClusterAllocations returns the address ranges used by a cluster
+func ClusterAllocations(c cluster.CommonCluster) ([]Allocation, error) {
+	cidrs, err := c.GetK8sIpv4Cidrs()
+	if err != nil {
+		return nil, err
+	}
+
+	var allocations []Allocation
+
+	add := func(kind string, cidr string, candidate bool) {
+		if cidr == "" {
+			return
+		}
+
+		allocations = append(allocations, Allocation{
+			CIDR:        cidr,
+			Kind:        kind,
+			ClusterID:   c.GetID(),
+			ClusterName: c.GetName(),
+			Candidate:   candidate,
+		})
+	}
+
+	if cidrs != nil {
+		for _, cidr := range cidrs.PodIPRanges {
+			add(KindPod, cidr, false)
+		}
+
+		// several service ranges mean that the actual one is not known
+		for _, cidr := range cidrs.ServiceClusterIPRanges {
+			add(KindService, cidr, len(cidrs.ServiceClusterIPRanges) > 1)
+		}
+	}
+
+	if eksCluster, ok := c.(*cluster.EKSCluster); ok {
+		if eks := eksCluster.GetModel().EKS; eks.VpcCidr != nil {
+			add(KindNetwork, *eks.VpcCidr, false)
+		}
+	}
+
+	return allocations, nil
+}
+
+// RequestedAllocations returns the address ranges requested for a new cluster.
+// Ranges of existing VPCs and subnets are not included, as those may be shared by several clusters.
+// GKE and AKS requests are not included either: they only refer to existing networks and subnets by name or ID,
+// while the pod and service ranges are picked by the cloud provider (those are checked once the cluster is running).
+// PKE on Azure clusters are created from a different request, see RequestedPKEOnAzureAllocations.
+func RequestedAllocations(request *pkgCluster.CreateClusterRequest) []Allocation {
+	var allocations []Allocation
+
+	add := func(kind string, cidr string) {
+		if cidr != "" {
+			allocations = append(allocations, Allocation{
+				CIDR:        cidr,
+				Kind:        kind,
+				ClusterName: request.Name,
+			})
+		}
+	}
+
+	if pke := request.Properties.CreateClusterPKE; pke != nil {
+		add(KindPod, pke.Network.PodCIDR)
+		add(KindService, pke.Network.ServiceCIDR)
+	}
+
+	if eks := request.Properties.CreateClusterEKS; eks != nil {
+		if eks.Vpc != nil && eks.Vpc.VpcId == "" {
+			add(KindNetwork, eks.Vpc.Cidr)
+		}
+
+		for _, subnet := range eks.Subnets {
+			if subnet != nil && subnet.SubnetId == "" {
+				add(KindPod, subnet.Cidr)
+			}
+		}
+	}
+
+	return allocations
+}
+
+// RequestedPKEOnAzureAllocations returns the address ranges requested for a new PKE on Azure cluster.
+// Subnet ranges are not included, as they are part of the virtual network range.
+func RequestedPKEOnAzureAllocations(clusterName string, networkCIDR string, podCIDR string, serviceCIDR string) []Allocation {
+	var allocations []Allocation
+
+	add := func(kind string, cidr string) {
+		if cidr != "" {
+			allocations = append(allocations, Allocation{
+				CIDR:        cidr,
+				Kind:        kind,
+				ClusterName: clusterName,
+			})
+		}
+	}
+
+	add(KindNetwork, networkCIDR)
+	add(KindPod, podCIDR)
+	add(KindService, serviceCIDR)
+
+	return allocations
+}
+
+// FindOverlaps returns the requested ranges which overlap with existing ones.
+// Candidate ranges and ranges of the same cluster are ignored.
+func FindOverlaps(requested []Allocation, existing []Allocation) []Overlap {
+	var overlaps []Overlap
+
+	for _, r := range requested {
+		_, requestedNet, err := net.ParseCIDR(r.CIDR)
+		if err != nil {
+			continue
+		}
+
+		for _, e := range existing {
+			if e.Candidate || (r.ClusterID != 0 && r.ClusterID == e.ClusterID) {
+				continue
+			}
+
+			_, existingNet, err := net.ParseCIDR(e.CIDR)
+			if err != nil {
+				continue
+			}
+
+			if network.Overlaps(requestedNet, existingNet) {
+				overlaps = append(overlaps, Overlap{Requested: r, Existing: e})
+			}
+		}
+	}
+
+	return overlaps
+}
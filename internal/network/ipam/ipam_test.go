@@ -0,0 +1,160 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/cluster"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/pkg/cluster/eks"
+	"github.com/banzaicloud/pipeline/pkg/cluster/pke"
+)
+
+type clusterStub struct {
+	cluster.CommonCluster
+
+	id    uint
+	name  string
+	cidrs *pkgCluster.Ipv4Cidrs
+	err   error
+}
+
+func (c clusterStub) GetID() uint     { return c.id }
+func (c clusterStub) GetName() string { return c.name }
+
+func (c clusterStub) GetK8sIpv4Cidrs() (*pkgCluster.Ipv4Cidrs, error) {
+	return c.cidrs, c.err
+}
+
+type clusterListerStub []cluster.CommonCluster
+
+func (s clusterListerStub) GetClusters(ctx context.Context, organizationID uint) ([]cluster.CommonCluster, error) {
+	return s, nil
+}
+
+func newTestPlanner() *Planner {
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+
+	return NewPlanner(clusterListerStub{
+		clusterStub{id: 1, name: "pke", cidrs: &pkgCluster.Ipv4Cidrs{
+			PodIPRanges:            []string{"10.200.0.0/16"},
+			ServiceClusterIPRanges: []string{"10.32.0.0/24"},
+		}},
+		clusterStub{id: 2, name: "eks", cidrs: &pkgCluster.Ipv4Cidrs{
+			PodIPRanges:            []string{"192.168.64.0/20"},
+			ServiceClusterIPRanges: []string{"172.20.0.0/16", "10.100.0.0/16"},
+		}},
+		clusterStub{id: 3, name: "imported", err: errors.New("not implemented")},
+	}, logger)
+}
+
+func TestPlanner_ListAllocations(t *testing.T) {
+	list, err := newTestPlanner().ListAllocations(context.Background(), 1)
+	require.NoError(t, err)
+
+	assert.Len(t, list.Allocations, 5)
+	assert.Equal(t, []UnknownCluster{{ClusterID: 3, ClusterName: "imported", Error: "not implemented"}}, list.UnknownClusters)
+
+	for _, allocation := range list.Allocations {
+		assert.Equal(t, allocation.Kind == KindService && allocation.ClusterID == 2, allocation.Candidate, allocation.CIDR)
+	}
+}
+
+func TestPlanner_CheckOverlaps(t *testing.T) {
+	planner := newTestPlanner()
+
+	request := &pkgCluster.CreateClusterRequest{
+		Name: "new",
+		Properties: &pkgCluster.CreateClusterProperties{
+			CreateClusterPKE: &pke.CreateClusterPKE{
+				Network: pke.Network{PodCIDR: "10.200.0.0/16", ServiceCIDR: "10.100.0.0/24"},
+			},
+		},
+	}
+
+	err := planner.CheckOverlaps(context.Background(), 1, RequestedAllocations(request))
+
+	var overlapErr OverlapError
+	require.True(t, errors.As(err, &overlapErr))
+	require.Len(t, overlapErr.Overlaps, 1)
+	assert.Equal(t, "10.200.0.0/16", overlapErr.Overlaps[0].Existing.CIDR)
+	assert.Equal(t, "pod range 10.200.0.0/16 of cluster new overlaps with pod range 10.200.0.0/16 of cluster pke", err.Error())
+
+	request = &pkgCluster.CreateClusterRequest{
+		Name: "new",
+		Properties: &pkgCluster.CreateClusterProperties{
+			CreateClusterEKS: &eks.CreateClusterEKS{
+				Vpc:     &eks.ClusterVPC{Cidr: "192.168.128.0/17"},
+				Subnets: []*eks.ClusterSubnet{{Cidr: "192.168.128.0/20"}, {SubnetId: "subnet-1", Cidr: "192.168.64.0/20"}},
+			},
+		},
+	}
+
+	assert.NoError(t, planner.CheckOverlaps(context.Background(), 1, RequestedAllocations(request)))
+
+	requested := RequestedPKEOnAzureAllocations("azure", "10.240.0.0/16", "10.200.0.0/16", "")
+	assert.Equal(t, []Allocation{
+		{CIDR: "10.240.0.0/16", Kind: KindNetwork, ClusterName: "azure"},
+		{CIDR: "10.200.0.0/16", Kind: KindPod, ClusterName: "azure"},
+	}, requested)
+
+	err = planner.CheckOverlaps(context.Background(), 1, requested)
+	require.True(t, errors.As(err, &overlapErr))
+	require.Len(t, overlapErr.Overlaps, 1)
+	assert.Equal(t, KindPod, overlapErr.Overlaps[0].Requested.Kind)
+}
+
+func TestNextFreeBlock(t *testing.T) {
+	allocations := []Allocation{
+		{CIDR: "10.0.0.0/16"},
+		{CIDR: "10.1.4.0/22"},
+		{CIDR: "10.2.0.0/15", Candidate: true},
+	}
+
+	tests := []struct {
+		prefixLength int
+		pools        []string
+		expected     string
+	}{
+		{prefixLength: 16, expected: "10.4.0.0/16"},
+		{prefixLength: 22, expected: "10.1.0.0/22"},
+		{prefixLength: 21, expected: "10.1.8.0/21"},
+		{prefixLength: 12, expected: "10.16.0.0/12"},
+		{prefixLength: 8, pools: []string{"10.0.0.0/8", "172.16.0.0/12"}, expected: ""},
+		{prefixLength: 24, pools: []string{"10.1.4.0/22", "192.168.0.0/24"}, expected: "192.168.0.0/24"},
+	}
+
+	for _, test := range tests {
+		cidr, err := NextFreeBlock(test.prefixLength, test.pools, allocations)
+		if test.expected == "" {
+			assert.True(t, errors.As(err, &NoFreeBlockError{}), test.prefixLength)
+			continue
+		}
+
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, cidr)
+	}
+
+	_, err := NextFreeBlock(33, nil, nil)
+	assert.Error(t, err)
+}
@@ -14,6 +14,10 @@
 
 package network
 
+import (
+	"emperror.dev/errors"
+)
+
 // Network is an interface that cloud specific VPC network implementations must implement
 type Network interface {
 	CIDRs() []string
@@ -35,9 +39,90 @@ type RouteTable interface {
 	Name() string
 }
 
+// CreateNetworkRequest describes a VPC network to be created
+type CreateNetworkRequest struct {
+	Name string
+	CIDR string
+	// Location is used by providers where networks are not bound to the region of the service (eg. Azure)
+	Location string
+}
+
+// CreateSubnetRequest describes a VPC subnetwork to be created
+type CreateSubnetRequest struct {
+	NetworkID string
+	Name      string
+	CIDR      string
+	// Location is the availability zone (or domain) of the subnetwork, where applicable
+	Location string
+}
+
 // Service defines the interface of provider specific network service implementations
 type Service interface {
 	ListNetworks() ([]Network, error)
 	ListRouteTables(networkID string) ([]RouteTable, error)
 	ListSubnets(networkID string) ([]Subnet, error)
+
+	CreateNetwork(request CreateNetworkRequest) (Network, error)
+	DeleteNetwork(networkID string) error
+	CreateSubnet(request CreateSubnetRequest) (Subnet, error)
+	DeleteSubnet(networkID string, subnetID string) error
+}
+
+// CreateNetwork validates the request against the existing networks, then creates the network
+func CreateNetwork(svc Service, request CreateNetworkRequest) (Network, error) {
+	if request.Name == "" {
+		return nil, NewValidationError("network name is required")
+	}
+
+	if request.CIDR != "" {
+		networks, err := svc.ListNetworks()
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to list networks")
+		}
+
+		if err := ValidateNetworkCIDR(request.CIDR, networks); err != nil {
+			return nil, err
+		}
+	}
+
+	return svc.CreateNetwork(request)
+}
+
+// CreateSubnet validates the request against the network and its existing subnetworks, then creates the subnetwork
+func CreateSubnet(svc Service, request CreateSubnetRequest) (Subnet, error) {
+	if request.Name == "" {
+		return nil, NewValidationError("subnetwork name is required")
+	}
+
+	if request.CIDR == "" {
+		return nil, NewValidationError("subnetwork CIDR block is required")
+	}
+
+	networks, err := svc.ListNetworks()
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to list networks")
+	}
+
+	var network Network
+	for _, n := range networks {
+		if n.ID() == request.NetworkID {
+			network = n
+			break
+		}
+	}
+
+	if network == nil {
+		return nil, errors.WithStack(NetworkNotFoundError{NetworkID: request.NetworkID})
+	}
+
+	subnets, err := svc.ListSubnets(request.NetworkID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to list subnetworks")
+	}
+
+	if err := ValidateSubnetCIDR(request.CIDR, network, subnets); err != nil {
+		return nil, err
+	}
+
+	return svc.CreateSubnet(request)
 }
@@ -0,0 +1,172 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apply
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/banzaicloud/pipeline/internal/pipelinectl/cli/common"
+)
+
+type applyOptions struct {
+	file   string
+	dryRun bool
+	prune  bool
+}
+
+type applyItem struct {
+	Kind    string `json:"kind"`
+	Cluster string `json:"cluster,omitempty"`
+	Name    string `json:"name"`
+	Action  string `json:"action,omitempty"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+type applyResult struct {
+	DryRun bool        `json:"dryRun"`
+	Items  []applyItem `json:"items"`
+}
+
+type errorResponse struct {
+	Message string `json:"message"`
+	Error   string `json:"error"`
+}
+
+// NewApplyCommand creates a new cobra.Command for `pipelinectl apply`.
+func NewApplyCommand() *cobra.Command {
+	options := applyOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Apply a manifest",
+		Long: "Reconcile the secrets, clusters, deployments and cluster features of the organization " +
+			"from a multi-document YAML manifest.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			return runApply(options)
+		},
+	}
+
+	flags := cmd.Flags()
+
+	flags.StringVarP(&options.file, "file", "f", "", "Manifest file (- reads the standard input)")
+	flags.BoolVar(&options.dryRun, "dry-run", false, "Only show the plan without executing it")
+	flags.BoolVar(&options.prune, "prune", false, "Delete the resources applied earlier which are missing from the manifest")
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+func runApply(options applyOptions) error {
+	var manifest []byte
+	var err error
+	if options.file == "-" {
+		manifest, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		manifest, err = ioutil.ReadFile(options.file)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %s", options.file)
+	}
+
+	orgID, err := common.OrganizationID()
+	if err != nil {
+		return err
+	}
+
+	token := viper.GetString("api.token")
+	if token == "" {
+		return errors.New("no access token specified, use --token or the PIPELINECTL_API_TOKEN environment variable")
+	}
+
+	query := url.Values{}
+	query.Set("dryRun", strconv.FormatBool(options.dryRun))
+	query.Set("prune", strconv.FormatBool(options.prune))
+
+	u := fmt.Sprintf("%s/api/v1/orgs/%d/apply?%s", strings.TrimSuffix(viper.GetString("api.url"), "/"), orgID, query.Encode())
+
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(manifest))
+	if err != nil {
+		return errors.Wrap(err, "failed to create HTTP request")
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/yaml")
+	req.Header.Set("User-Agent", "pipelinectl")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "applying manifest failed")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read response")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp errorResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Message != "" {
+			return errors.Errorf("applying manifest failed: %s: %s", errResp.Message, errResp.Error)
+		}
+
+		return errors.Errorf("applying manifest failed: %s", resp.Status)
+	}
+
+	var result applyResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return errors.Wrap(err, "failed to parse response")
+	}
+
+	table := &common.Table{
+		Headers: []string{"KIND", "CLUSTER", "NAME", "ACTION", "STATUS", "MESSAGE"},
+	}
+
+	failed := 0
+	for _, item := range result.Items {
+		table.AddRow(item.Kind, item.Cluster, item.Name, item.Action, item.Status, item.Message)
+
+		if item.Status == "failed" {
+			failed++
+		}
+	}
+
+	if err := common.Output(os.Stdout, result, table); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return errors.Errorf("%d resource(s) failed to apply", failed)
+	}
+
+	return nil
+}
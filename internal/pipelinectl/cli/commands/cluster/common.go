@@ -0,0 +1,50 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/banzaicloud/pipeline/client"
+	"github.com/banzaicloud/pipeline/internal/pipelinectl/cli/common"
+)
+
+func parseClusterID(arg string) (int32, error) {
+	id, err := strconv.ParseInt(arg, 10, 32)
+	if err != nil || id <= 0 {
+		return 0, errors.Errorf("invalid cluster ID: %q", arg)
+	}
+
+	return int32(id), nil
+}
+
+func clusterTable(clusters ...client.GetClusterStatusResponse) *common.Table {
+	table := &common.Table{
+		Headers: []string{"ID", "NAME", "DISTRIBUTION", "LOCATION", "STATUS", "NODES", "CREATED"},
+	}
+
+	for _, cluster := range clusters {
+		var nodes int32
+		for _, nodePool := range cluster.NodePools {
+			nodes += nodePool.Count
+		}
+
+		table.AddRow(cluster.Id, cluster.Name, cluster.Distribution, cluster.Location, cluster.Status, nodes, cluster.CreatedAt)
+	}
+
+	return table
+}
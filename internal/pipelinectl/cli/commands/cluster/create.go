@@ -0,0 +1,78 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/banzaicloud/pipeline/internal/pipelinectl/cli/common"
+)
+
+type createOptions struct {
+	file string
+}
+
+// NewCreateCommand creates a new cobra.Command for `pipelinectl cluster create`.
+func NewCreateCommand() *cobra.Command {
+	options := createOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a cluster",
+		Long:  "Create a cluster from a JSON or YAML cluster creation request.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			return runCreate(options)
+		},
+	}
+
+	flags := cmd.Flags()
+
+	flags.StringVarP(&options.file, "file", "f", "", "Cluster creation request file (- reads the standard input)")
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+func runCreate(options createOptions) error {
+	var request map[string]interface{}
+	if err := common.ReadFile(options.file, &request); err != nil {
+		return err
+	}
+
+	orgID, err := common.OrganizationID()
+	if err != nil {
+		return err
+	}
+
+	pipeline, ctx, err := common.NewClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, _, err := pipeline.ClustersApi.CreateCluster(ctx, orgID, request)
+	if err != nil {
+		return common.APIError(err, "creating cluster failed")
+	}
+
+	fmt.Printf("Cluster %s is being created with ID %d.\n", cluster.Name, cluster.Id)
+
+	return nil
+}
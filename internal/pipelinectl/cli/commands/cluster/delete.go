@@ -0,0 +1,81 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/antihax/optional"
+	"github.com/spf13/cobra"
+
+	"github.com/banzaicloud/pipeline/client"
+	"github.com/banzaicloud/pipeline/internal/pipelinectl/cli/common"
+)
+
+type deleteOptions struct {
+	force bool
+}
+
+// NewDeleteCommand creates a new cobra.Command for `pipelinectl cluster delete`.
+func NewDeleteCommand() *cobra.Command {
+	options := deleteOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "delete CLUSTER_ID",
+		Aliases: []string{"rm"},
+		Short:   "Delete a cluster",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			return runDelete(args[0], options)
+		},
+	}
+
+	flags := cmd.Flags()
+
+	flags.BoolVar(&options.force, "force", false, "Delete the cluster even if some of its resources cannot be removed")
+
+	return cmd
+}
+
+func runDelete(arg string, options deleteOptions) error {
+	clusterID, err := parseClusterID(arg)
+	if err != nil {
+		return err
+	}
+
+	orgID, err := common.OrganizationID()
+	if err != nil {
+		return err
+	}
+
+	pipeline, ctx, err := common.NewClient()
+	if err != nil {
+		return err
+	}
+
+	_, _, err = pipeline.ClustersApi.DeleteCluster(ctx, orgID, clusterID, &client.DeleteClusterOpts{
+		Force: optional.NewBool(options.force),
+	})
+	if err != nil {
+		return common.APIError(err, "deleting cluster failed")
+	}
+
+	fmt.Printf("Cluster %d is being deleted.\n", clusterID)
+
+	return nil
+}
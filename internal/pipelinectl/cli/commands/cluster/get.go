@@ -0,0 +1,90 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/banzaicloud/pipeline/internal/pipelinectl/cli/common"
+)
+
+// NewGetCommand creates a new cobra.Command for `pipelinectl cluster get`.
+func NewGetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get CLUSTER_ID",
+		Short: "Get the details of a cluster",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			return runGet(args[0])
+		},
+	}
+
+	return cmd
+}
+
+func runGet(arg string) error {
+	clusterID, err := parseClusterID(arg)
+	if err != nil {
+		return err
+	}
+
+	orgID, err := common.OrganizationID()
+	if err != nil {
+		return err
+	}
+
+	pipeline, ctx, err := common.NewClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, _, err := pipeline.ClustersApi.GetCluster(ctx, orgID, clusterID)
+	if err != nil {
+		return common.APIError(err, "getting cluster failed")
+	}
+
+	if !common.IsTableOutput() {
+		return common.Output(os.Stdout, cluster, nil)
+	}
+
+	if err := common.Output(os.Stdout, cluster, clusterTable(cluster)); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(cluster.NodePools))
+	for name := range cluster.NodePools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	table := &common.Table{
+		Headers: []string{"NODE POOL", "INSTANCE TYPE", "COUNT", "MIN", "MAX", "AUTOSCALING"},
+	}
+	for _, name := range names {
+		nodePool := cluster.NodePools[name]
+		table.AddRow(name, nodePool.InstanceType, nodePool.Count, nodePool.MinCount, nodePool.MaxCount, nodePool.Autoscaling)
+	}
+
+	fmt.Println()
+
+	return common.Output(os.Stdout, nil, table)
+}
@@ -0,0 +1,82 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/banzaicloud/pipeline/internal/pipelinectl/cli/common"
+)
+
+type kubeconfigOptions struct {
+	output string
+}
+
+// NewKubeconfigCommand creates a new cobra.Command for `pipelinectl cluster kubeconfig`.
+func NewKubeconfigCommand() *cobra.Command {
+	options := kubeconfigOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "kubeconfig CLUSTER_ID",
+		Short: "Download the kubeconfig of a cluster",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			return runKubeconfig(args[0], options)
+		},
+	}
+
+	flags := cmd.Flags()
+
+	flags.StringVar(&options.output, "save", "", "Save the kubeconfig to the file instead of printing it")
+
+	return cmd
+}
+
+func runKubeconfig(arg string, options kubeconfigOptions) error {
+	clusterID, err := parseClusterID(arg)
+	if err != nil {
+		return err
+	}
+
+	orgID, err := common.OrganizationID()
+	if err != nil {
+		return err
+	}
+
+	pipeline, ctx, err := common.NewClient()
+	if err != nil {
+		return err
+	}
+
+	config, _, err := pipeline.ClustersApi.GetClusterConfig(ctx, orgID, clusterID)
+	if err != nil {
+		return common.APIError(err, "getting kubeconfig failed")
+	}
+
+	if options.output == "" {
+		fmt.Print(config.Data)
+
+		return nil
+	}
+
+	return errors.Wrap(ioutil.WriteFile(options.output, []byte(config.Data), 0600), "failed to save kubeconfig")
+}
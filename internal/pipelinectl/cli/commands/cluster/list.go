@@ -0,0 +1,60 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/banzaicloud/pipeline/internal/pipelinectl/cli/common"
+)
+
+// NewListCommand creates a new cobra.Command for `pipelinectl cluster list`.
+func NewListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List clusters",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			return runList()
+		},
+	}
+
+	return cmd
+}
+
+func runList() error {
+	orgID, err := common.OrganizationID()
+	if err != nil {
+		return err
+	}
+
+	pipeline, ctx, err := common.NewClient()
+	if err != nil {
+		return err
+	}
+
+	clusters, _, err := pipeline.ClustersApi.ListClusters(ctx, orgID)
+	if err != nil {
+		return common.APIError(err, "listing clusters failed")
+	}
+
+	return common.Output(os.Stdout, clusters, clusterTable(clusters...))
+}
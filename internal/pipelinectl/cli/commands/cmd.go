@@ -15,7 +15,11 @@
 package commands
 
 import (
+	"github.com/banzaicloud/pipeline/internal/pipelinectl/cli/commands/apply"
+	"github.com/banzaicloud/pipeline/internal/pipelinectl/cli/commands/cluster"
+	"github.com/banzaicloud/pipeline/internal/pipelinectl/cli/commands/deployment"
 	"github.com/banzaicloud/pipeline/internal/pipelinectl/cli/commands/drain"
+	"github.com/banzaicloud/pipeline/internal/pipelinectl/cli/commands/secret"
 	"github.com/spf13/cobra"
 )
 
@@ -23,5 +27,9 @@ import (
 func AddCommands(cmd *cobra.Command) {
 	cmd.AddCommand(
 		drain.NewDrainCommand(),
+		cluster.NewClusterCommand(),
+		secret.NewSecretCommand(),
+		deployment.NewDeploymentCommand(),
+		apply.NewApplyCommand(),
 	)
 }
@@ -0,0 +1,64 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+
+	"github.com/banzaicloud/pipeline/client"
+	"github.com/banzaicloud/pipeline/internal/pipelinectl/cli/common"
+)
+
+type deploymentOptions struct {
+	clusterID  int32
+	version    string
+	valuesFile string
+	wait       bool
+	timeout    int64
+}
+
+func (o *deploymentOptions) addFlags(flags *pflag.FlagSet) {
+	flags.Int32VarP(&o.clusterID, "cluster", "c", 0, "ID of the cluster")
+	flags.StringVar(&o.version, "version", "", "Chart version (the latest version is used by default)")
+	flags.StringVarP(&o.valuesFile, "values", "f", "", "Values file of the chart in JSON or YAML format (- reads the standard input)")
+	flags.BoolVar(&o.wait, "wait", false, "Wait until the resources of the release are ready")
+	flags.Int64Var(&o.timeout, "timeout", 0, "Time in seconds to wait for any individual Kubernetes operation")
+}
+
+func (o *deploymentOptions) request(chart string) (client.CreateUpdateDeploymentRequest, error) {
+	request := client.CreateUpdateDeploymentRequest{
+		Name:    chart,
+		Version: o.version,
+		Wait:    o.wait,
+		Timeout: o.timeout,
+	}
+
+	if o.valuesFile != "" {
+		if err := common.ReadFile(o.valuesFile, &request.Values); err != nil {
+			return request, err
+		}
+	}
+
+	return request, nil
+}
+
+func clusterID(options deploymentOptions) (int32, error) {
+	if options.clusterID <= 0 {
+		return 0, errors.New("no cluster specified, use --cluster")
+	}
+
+	return options.clusterID, nil
+}
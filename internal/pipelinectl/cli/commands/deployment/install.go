@@ -0,0 +1,91 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/banzaicloud/pipeline/internal/pipelinectl/cli/common"
+)
+
+type installOptions struct {
+	deploymentOptions
+
+	releaseName string
+	namespace   string
+	dryRun      bool
+}
+
+// NewInstallCommand creates a new cobra.Command for `pipelinectl deployment install`.
+func NewInstallCommand() *cobra.Command {
+	options := installOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "install CHART",
+		Short: "Install a chart to a cluster",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			return runInstall(args[0], options)
+		},
+	}
+
+	flags := cmd.Flags()
+
+	options.addFlags(flags)
+	flags.StringVar(&options.releaseName, "release-name", "", "Name of the release (generated by default)")
+	flags.StringVarP(&options.namespace, "namespace", "n", "", "Namespace of the release")
+	flags.BoolVar(&options.dryRun, "dry-run", false, "Simulate the installation")
+
+	return cmd
+}
+
+func runInstall(chart string, options installOptions) error {
+	clusterID, err := clusterID(options.deploymentOptions)
+	if err != nil {
+		return err
+	}
+
+	request, err := options.request(chart)
+	if err != nil {
+		return err
+	}
+	request.ReleaseName = options.releaseName
+	request.Namespace = options.namespace
+	request.DryRun = options.dryRun
+
+	orgID, err := common.OrganizationID()
+	if err != nil {
+		return err
+	}
+
+	pipeline, ctx, err := common.NewClient()
+	if err != nil {
+		return err
+	}
+
+	deployment, _, err := pipeline.DeploymentsApi.CreateDeployment(ctx, orgID, clusterID, request)
+	if err != nil {
+		return common.APIError(err, "installing chart failed")
+	}
+
+	fmt.Printf("Release %s installed.\n", deployment.ReleaseName)
+
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/banzaicloud/pipeline/internal/pipelinectl/cli/common"
+)
+
+// NewListCommand creates a new cobra.Command for `pipelinectl deployment list`.
+func NewListCommand() *cobra.Command {
+	options := deploymentOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the deployments of a cluster",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			return runList(options)
+		},
+	}
+
+	flags := cmd.Flags()
+
+	flags.Int32VarP(&options.clusterID, "cluster", "c", 0, "ID of the cluster")
+
+	return cmd
+}
+
+func runList(options deploymentOptions) error {
+	clusterID, err := clusterID(options)
+	if err != nil {
+		return err
+	}
+
+	orgID, err := common.OrganizationID()
+	if err != nil {
+		return err
+	}
+
+	pipeline, ctx, err := common.NewClient()
+	if err != nil {
+		return err
+	}
+
+	deployments, _, err := pipeline.DeploymentsApi.ListDeployments(ctx, orgID, clusterID, nil)
+	if err != nil {
+		return common.APIError(err, "listing deployments failed")
+	}
+
+	table := &common.Table{
+		Headers: []string{"RELEASE", "NAMESPACE", "CHART", "VERSION", "STATUS", "UPDATED"},
+	}
+	for _, deployment := range deployments {
+		table.AddRow(deployment.ReleaseName, deployment.Namespace, deployment.ChartName, deployment.ChartVersion, deployment.Status, deployment.UpdatedAt)
+	}
+
+	return common.Output(os.Stdout, deployments, table)
+}
@@ -0,0 +1,85 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/banzaicloud/pipeline/internal/pipelinectl/cli/common"
+)
+
+type upgradeOptions struct {
+	deploymentOptions
+
+	reuseValues bool
+}
+
+// NewUpgradeCommand creates a new cobra.Command for `pipelinectl deployment upgrade`.
+func NewUpgradeCommand() *cobra.Command {
+	options := upgradeOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "upgrade RELEASE CHART",
+		Short: "Upgrade a release of a cluster",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			return runUpgrade(args[0], args[1], options)
+		},
+	}
+
+	flags := cmd.Flags()
+
+	options.addFlags(flags)
+	flags.BoolVar(&options.reuseValues, "reuse-values", false, "Merge the values with the ones of the current release")
+
+	return cmd
+}
+
+func runUpgrade(releaseName string, chart string, options upgradeOptions) error {
+	clusterID, err := clusterID(options.deploymentOptions)
+	if err != nil {
+		return err
+	}
+
+	request, err := options.request(chart)
+	if err != nil {
+		return err
+	}
+	request.ReuseValues = options.reuseValues
+
+	orgID, err := common.OrganizationID()
+	if err != nil {
+		return err
+	}
+
+	pipeline, ctx, err := common.NewClient()
+	if err != nil {
+		return err
+	}
+
+	_, _, err = pipeline.DeploymentsApi.UpdateDeployment(ctx, orgID, clusterID, releaseName, request)
+	if err != nil {
+		return common.APIError(err, "upgrading release failed")
+	}
+
+	fmt.Printf("Release %s upgraded.\n", releaseName)
+
+	return nil
+}
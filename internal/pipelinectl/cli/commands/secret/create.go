@@ -0,0 +1,120 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/antihax/optional"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/banzaicloud/pipeline/client"
+	"github.com/banzaicloud/pipeline/internal/pipelinectl/cli/common"
+)
+
+type createOptions struct {
+	file       string
+	name       string
+	secretType string
+	tags       []string
+	values     []string
+	validate   bool
+}
+
+// NewCreateCommand creates a new cobra.Command for `pipelinectl secret create`.
+func NewCreateCommand() *cobra.Command {
+	options := createOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a secret",
+		Long:  "Create a secret from a JSON or YAML secret creation request or from the command line flags.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			return runCreate(options)
+		},
+	}
+
+	flags := cmd.Flags()
+
+	flags.StringVarP(&options.file, "file", "f", "", "Secret creation request file (- reads the standard input)")
+	flags.StringVar(&options.name, "name", "", "Name of the secret")
+	flags.StringVar(&options.secretType, "type", "", "Type of the secret")
+	flags.StringSliceVar(&options.tags, "tag", nil, "Tags of the secret")
+	flags.StringArrayVar(&options.values, "value", nil, "Value of the secret in KEY=VALUE format")
+	flags.BoolVar(&options.validate, "validate", true, "Validate the secret against the cloud provider")
+
+	return cmd
+}
+
+func runCreate(options createOptions) error {
+	var request client.CreateSecretRequest
+	if options.file != "" {
+		if err := common.ReadFile(options.file, &request); err != nil {
+			return err
+		}
+	}
+
+	if options.name != "" {
+		request.Name = options.name
+	}
+	if options.secretType != "" {
+		request.Type = options.secretType
+	}
+	if len(options.tags) > 0 {
+		request.Tags = options.tags
+	}
+	for _, value := range options.values {
+		kv := strings.SplitN(value, "=", 2)
+		if len(kv) != 2 {
+			return errors.Errorf("invalid secret value (KEY=VALUE expected): %q", value)
+		}
+
+		if request.Values == nil {
+			request.Values = make(map[string]interface{})
+		}
+		request.Values[kv[0]] = kv[1]
+	}
+
+	if request.Name == "" || request.Type == "" {
+		return errors.New("name and type of the secret are required")
+	}
+
+	orgID, err := common.OrganizationID()
+	if err != nil {
+		return err
+	}
+
+	pipeline, ctx, err := common.NewClient()
+	if err != nil {
+		return err
+	}
+
+	secret, _, err := pipeline.SecretsApi.AddSecrets(ctx, orgID, request, &client.AddSecretsOpts{
+		Validate: optional.NewBool(options.validate),
+	})
+	if err != nil {
+		return common.APIError(err, "creating secret failed")
+	}
+
+	fmt.Printf("Secret %s created with ID %s.\n", secret.Name, secret.Id)
+
+	return nil
+}
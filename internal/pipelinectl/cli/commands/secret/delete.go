@@ -0,0 +1,61 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/banzaicloud/pipeline/internal/pipelinectl/cli/common"
+)
+
+// NewDeleteCommand creates a new cobra.Command for `pipelinectl secret delete`.
+func NewDeleteCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "delete SECRET_ID",
+		Aliases: []string{"rm"},
+		Short:   "Delete a secret",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			return runDelete(args[0])
+		},
+	}
+
+	return cmd
+}
+
+func runDelete(secretID string) error {
+	orgID, err := common.OrganizationID()
+	if err != nil {
+		return err
+	}
+
+	pipeline, ctx, err := common.NewClient()
+	if err != nil {
+		return err
+	}
+
+	if _, err := pipeline.SecretsApi.DeleteSecrets(ctx, orgID, secretID); err != nil {
+		return common.APIError(err, "deleting secret failed")
+	}
+
+	fmt.Printf("Secret %s deleted.\n", secretID)
+
+	return nil
+}
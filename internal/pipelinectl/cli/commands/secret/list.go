@@ -0,0 +1,90 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"os"
+	"strings"
+
+	"github.com/antihax/optional"
+	"github.com/spf13/cobra"
+
+	"github.com/banzaicloud/pipeline/client"
+	"github.com/banzaicloud/pipeline/internal/pipelinectl/cli/common"
+)
+
+type listOptions struct {
+	secretType string
+	tags       []string
+}
+
+// NewListCommand creates a new cobra.Command for `pipelinectl secret list`.
+func NewListCommand() *cobra.Command {
+	options := listOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List secrets",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			return runList(options)
+		},
+	}
+
+	flags := cmd.Flags()
+
+	flags.StringVar(&options.secretType, "type", "", "List secrets of the given type only")
+	flags.StringSliceVar(&options.tags, "tag", nil, "List secrets with the given tags only")
+
+	return cmd
+}
+
+func runList(options listOptions) error {
+	orgID, err := common.OrganizationID()
+	if err != nil {
+		return err
+	}
+
+	pipeline, ctx, err := common.NewClient()
+	if err != nil {
+		return err
+	}
+
+	opts := &client.GetSecretsOpts{}
+	if options.secretType != "" {
+		opts.Type_ = optional.NewString(options.secretType)
+	}
+	if len(options.tags) > 0 {
+		opts.Tags = optional.NewInterface(options.tags)
+	}
+
+	secrets, _, err := pipeline.SecretsApi.GetSecrets(ctx, orgID, opts)
+	if err != nil {
+		return common.APIError(err, "listing secrets failed")
+	}
+
+	table := &common.Table{
+		Headers: []string{"ID", "NAME", "TYPE", "TAGS", "VERSION", "UPDATED"},
+	}
+	for _, secret := range secrets {
+		table.AddRow(secret.Id, secret.Name, secret.Type, strings.Join(secret.Tags, ","), secret.Version, secret.UpdatedAt.Format("2006-01-02 15:04:05"))
+	}
+
+	return common.Output(os.Stdout, secrets, table)
+}
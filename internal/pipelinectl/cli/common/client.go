@@ -0,0 +1,60 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+
+	"github.com/banzaicloud/pipeline/client"
+)
+
+// NewClient returns a Pipeline API client and a context carrying the access token.
+func NewClient() (*client.APIClient, context.Context, error) {
+	token := viper.GetString("api.token")
+	if token == "" {
+		return nil, nil, errors.New("no access token specified, use --token or the PIPELINECTL_API_TOKEN environment variable")
+	}
+
+	config := client.NewConfiguration()
+	config.BasePath = strings.TrimSuffix(viper.GetString("api.url"), "/")
+	config.UserAgent = "pipelinectl"
+
+	ctx := context.WithValue(context.Background(), client.ContextAccessToken, token)
+
+	return client.NewAPIClient(config), ctx, nil
+}
+
+// OrganizationID returns the ID of the organization the commands operate on.
+func OrganizationID() (int32, error) {
+	orgID := viper.GetInt("organization")
+	if orgID <= 0 {
+		return 0, errors.New("no organization specified, use --organization or the PIPELINECTL_ORGANIZATION environment variable")
+	}
+
+	return int32(orgID), nil
+}
+
+// APIError adds the response body of a failed API call to the error.
+func APIError(err error, message string) error {
+	if apiErr, ok := err.(client.GenericOpenAPIError); ok && len(apiErr.Body()) > 0 {
+		return errors.Errorf("%s: %s: %s", message, apiErr.Error(), strings.TrimSpace(string(apiErr.Body())))
+	}
+
+	return errors.Wrap(err, message)
+}
@@ -0,0 +1,115 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+// Output formats
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+)
+
+// Table describes the tabular output of a command.
+type Table struct {
+	Headers []string
+	Rows    [][]string
+}
+
+// AddRow adds a row to the table.
+func (t *Table) AddRow(values ...interface{}) {
+	row := make([]string, 0, len(values))
+	for _, value := range values {
+		row = append(row, fmt.Sprint(value))
+	}
+
+	t.Rows = append(t.Rows, row)
+}
+
+// IsTableOutput returns whether the output flag selects the table format.
+func IsTableOutput() bool {
+	format := viper.GetString("output.format")
+
+	return format == OutputTable || format == ""
+}
+
+// Output writes the data in the format selected by the output flag.
+// The table is written in table format, the data itself otherwise.
+func Output(out io.Writer, data interface{}, table *Table) error {
+	switch format := viper.GetString("output.format"); format {
+	case OutputTable, "":
+		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+
+		_, _ = fmt.Fprintln(w, strings.Join(table.Headers, "\t"))
+		for _, row := range table.Rows {
+			_, _ = fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+
+		return errors.Wrap(w.Flush(), "failed to write output")
+
+	case OutputJSON:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+
+		return errors.Wrap(encoder.Encode(data), "failed to write output")
+
+	case OutputYAML:
+		output, err := yaml.Marshal(data)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal output")
+		}
+
+		_, err = out.Write(output)
+
+		return errors.Wrap(err, "failed to write output")
+
+	default:
+		return errors.Errorf("unknown output format: %q", format)
+	}
+}
+
+// ReadFile reads a JSON or YAML file (or the standard input if the filename is "-") into the value pointed by v.
+func ReadFile(filename string, v interface{}) error {
+	var content []byte
+	var err error
+	if filename == "-" {
+		content, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		content, err = ioutil.ReadFile(filename)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %s", filename)
+	}
+
+	jsonContent, err := yaml.YAMLToJSON(content)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse %s", filename)
+	}
+
+	return errors.Wrapf(json.Unmarshal(jsonContent, v), "failed to parse %s", filename)
+}
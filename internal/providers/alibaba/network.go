@@ -159,3 +159,57 @@ func (ns *alibabaNetworkService) ListRouteTables(networkID string) ([]network.Ro
 	}
 	return routeTables, nil
 }
+
+// CreateNetwork creates a VPC
+func (ns *alibabaNetworkService) CreateNetwork(request network.CreateNetworkRequest) (network.Network, error) {
+	req := vpc.CreateCreateVpcRequest()
+	req.VpcName = request.Name
+	req.CidrBlock = request.CIDR
+	res, err := ns.client.CreateVpc(req)
+	if err != nil {
+		return nil, emperror.Wrap(err, "request to CreateVpc failed")
+	}
+	return &alibabaNetwork{
+		cidrs: []string{request.CIDR},
+		id:    res.VpcId,
+		name:  request.Name,
+	}, nil
+}
+
+// DeleteNetwork deletes a VPC
+func (ns *alibabaNetworkService) DeleteNetwork(networkID string) error {
+	req := vpc.CreateDeleteVpcRequest()
+	req.VpcId = networkID
+	_, err := ns.client.DeleteVpc(req)
+	return emperror.Wrap(err, "request to DeleteVpc failed")
+}
+
+// CreateSubnet creates a VSwitch in the specified zone
+func (ns *alibabaNetworkService) CreateSubnet(request network.CreateSubnetRequest) (network.Subnet, error) {
+	if request.Location == "" {
+		return nil, network.NewValidationError("zone is required for Alibaba VSwitches")
+	}
+	req := vpc.CreateCreateVSwitchRequest()
+	req.VpcId = request.NetworkID
+	req.VSwitchName = request.Name
+	req.CidrBlock = request.CIDR
+	req.ZoneId = request.Location
+	res, err := ns.client.CreateVSwitch(req)
+	if err != nil {
+		return nil, emperror.Wrap(err, "request to CreateVSwitch failed")
+	}
+	return &alibabaSubnet{
+		cidrs:    []string{request.CIDR},
+		id:       res.VSwitchId,
+		location: request.Location,
+		name:     request.Name,
+	}, nil
+}
+
+// DeleteSubnet deletes a VSwitch
+func (ns *alibabaNetworkService) DeleteSubnet(networkID string, subnetID string) error {
+	req := vpc.CreateDeleteVSwitchRequest()
+	req.VSwitchId = subnetID
+	_, err := ns.client.DeleteVSwitch(req)
+	return emperror.Wrap(err, "request to DeleteVSwitch failed")
+}
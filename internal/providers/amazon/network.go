@@ -15,6 +15,8 @@
 package amazon
 
 import (
+	"emperror.dev/errors"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/banzaicloud/pipeline/internal/network"
 	"github.com/banzaicloud/pipeline/secret"
@@ -156,6 +158,189 @@ func (ns *amazonNetworkService) ListRouteTables(networkID string) ([]network.Rou
 	return routeTables, nil
 }
 
+// CreateNetwork creates a VPC with an internet gateway routed from its main route table
+func (ns *amazonNetworkService) CreateNetwork(request network.CreateNetworkRequest) (network.Network, error) {
+	if request.CIDR == "" {
+		return nil, network.NewValidationError("CIDR block is required for Amazon VPCs")
+	}
+
+	res, err := ns.client.CreateVpc(&ec2.CreateVpcInput{
+		CidrBlock: aws.String(request.CIDR),
+	})
+	if err != nil {
+		return nil, err
+	}
+	vpcID := aws.StringValue(res.Vpc.VpcId)
+
+	if err := ns.setUpNetwork(vpcID, request.Name); err != nil {
+		if err := ns.DeleteNetwork(vpcID); err != nil {
+			ns.logger.WithField("networkID", vpcID).Warnf("failed to clean up network: %s", err.Error())
+		}
+		return nil, err
+	}
+
+	return &amazonNetwork{
+		cidrs: []string{request.CIDR},
+		id:    vpcID,
+		name:  request.Name,
+	}, nil
+}
+
+func (ns *amazonNetworkService) setUpNetwork(vpcID string, name string) error {
+	err := ns.client.WaitUntilVpcAvailable(&ec2.DescribeVpcsInput{
+		VpcIds: []*string{aws.String(vpcID)},
+	})
+	if err != nil {
+		return errors.WrapIf(err, "failed waiting for VPC to become available")
+	}
+
+	// EKS and PKE nodes need DNS hostnames to join the cluster
+	_, err = ns.client.ModifyVpcAttribute(&ec2.ModifyVpcAttributeInput{
+		VpcId:              aws.String(vpcID),
+		EnableDnsHostnames: &ec2.AttributeBooleanValue{Value: aws.Bool(true)},
+	})
+	if err != nil {
+		return err
+	}
+
+	igw, err := ns.client.CreateInternetGateway(&ec2.CreateInternetGatewayInput{})
+	if err != nil {
+		return err
+	}
+	igwID := igw.InternetGateway.InternetGatewayId
+
+	_, err = ns.client.AttachInternetGateway(&ec2.AttachInternetGatewayInput{
+		InternetGatewayId: igwID,
+		VpcId:             aws.String(vpcID),
+	})
+	if err != nil {
+		return err
+	}
+
+	rts, err := ns.client.DescribeRouteTables(&ec2.DescribeRouteTablesInput{
+		Filters: []*ec2.Filter{
+			makeNetworkIDFilter(vpcID),
+			{
+				Name:   aws.String("association.main"),
+				Values: []*string{aws.String("true")},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if len(rts.RouteTables) == 0 {
+		return errors.Errorf("main route table of VPC %s not found", vpcID)
+	}
+	routeTableID := rts.RouteTables[0].RouteTableId
+
+	_, err = ns.client.CreateRoute(&ec2.CreateRouteInput{
+		RouteTableId:         routeTableID,
+		DestinationCidrBlock: aws.String("0.0.0.0/0"),
+		GatewayId:            igwID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return ns.tagName(name, aws.String(vpcID), igwID, routeTableID)
+}
+
+// DeleteNetwork deletes a VPC with its internet gateways.
+// The subnets of the VPC must be deleted first.
+func (ns *amazonNetworkService) DeleteNetwork(networkID string) error {
+	igws, err := ns.client.DescribeInternetGateways(&ec2.DescribeInternetGatewaysInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("attachment.vpc-id"),
+				Values: []*string{aws.String(networkID)},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, igw := range igws.InternetGateways {
+		_, err := ns.client.DetachInternetGateway(&ec2.DetachInternetGatewayInput{
+			InternetGatewayId: igw.InternetGatewayId,
+			VpcId:             aws.String(networkID),
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = ns.client.DeleteInternetGateway(&ec2.DeleteInternetGatewayInput{
+			InternetGatewayId: igw.InternetGatewayId,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = ns.client.DeleteVpc(&ec2.DeleteVpcInput{
+		VpcId: aws.String(networkID),
+	})
+	return err
+}
+
+// CreateSubnet creates a subnet in a VPC which assigns public IP addresses to the instances launched into it
+func (ns *amazonNetworkService) CreateSubnet(request network.CreateSubnetRequest) (network.Subnet, error) {
+	input := &ec2.CreateSubnetInput{
+		VpcId:     aws.String(request.NetworkID),
+		CidrBlock: aws.String(request.CIDR),
+	}
+	if request.Location != "" {
+		input.AvailabilityZone = aws.String(request.Location)
+	}
+
+	res, err := ns.client.CreateSubnet(input)
+	if err != nil {
+		return nil, err
+	}
+	subnetID := res.Subnet.SubnetId
+
+	_, err = ns.client.ModifySubnetAttribute(&ec2.ModifySubnetAttributeInput{
+		SubnetId:            subnetID,
+		MapPublicIpOnLaunch: &ec2.AttributeBooleanValue{Value: aws.Bool(true)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ns.tagName(request.Name, subnetID); err != nil {
+		return nil, err
+	}
+
+	return &amazonSubnet{
+		cidrs:    []string{request.CIDR},
+		id:       aws.StringValue(subnetID),
+		location: aws.StringValue(res.Subnet.AvailabilityZone),
+		name:     request.Name,
+	}, nil
+}
+
+// DeleteSubnet deletes a subnet of a VPC
+func (ns *amazonNetworkService) DeleteSubnet(networkID string, subnetID string) error {
+	_, err := ns.client.DeleteSubnet(&ec2.DeleteSubnetInput{
+		SubnetId: aws.String(subnetID),
+	})
+	return err
+}
+
+func (ns *amazonNetworkService) tagName(name string, resources ...*string) error {
+	_, err := ns.client.CreateTags(&ec2.CreateTagsInput{
+		Resources: resources,
+		Tags: []*ec2.Tag{
+			{
+				Key:   aws.String("Name"),
+				Value: aws.String(name),
+			},
+		},
+	})
+	return err
+}
+
 func getNameFromTags(tags []*ec2.Tag) string {
 	for _, tag := range tags {
 		if *tag.Key == "Name" {
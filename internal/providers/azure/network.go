@@ -16,10 +16,12 @@ package azure
 
 import (
 	"context"
+	"path"
 
 	"emperror.dev/emperror"
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-10-01/network"
 	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
@@ -84,6 +86,7 @@ func (a azureRouteTable) Name() string {
 
 type azureNetworkService struct {
 	client            network.VirtualNetworksClient
+	subnetsClient     network.SubnetsClient
 	logger            logrus.FieldLogger
 	resourceGroupName string
 }
@@ -96,6 +99,7 @@ func NewNetworkService(resourceGroupName string, sir *secret.SecretItemResponse,
 	}
 	return &azureNetworkService{
 		client:            cc.GetVirtualNetworksClient().VirtualNetworksClient,
+		subnetsClient:     cc.GetSubnetsClient().SubnetsClient,
 		logger:            logger,
 		resourceGroupName: resourceGroupName,
 	}, nil
@@ -146,3 +150,78 @@ func (ns *azureNetworkService) ListSubnets(networkID string) ([]intNetwork.Subne
 	}
 	return res, nil
 }
+
+// CreateNetwork creates a virtual network in the resource group
+func (ns *azureNetworkService) CreateNetwork(request intNetwork.CreateNetworkRequest) (intNetwork.Network, error) {
+	if request.CIDR == "" {
+		return nil, intNetwork.NewValidationError("CIDR block is required for Azure virtual networks")
+	}
+	if request.Location == "" {
+		return nil, intNetwork.NewValidationError("location is required for Azure virtual networks")
+	}
+	future, err := ns.client.CreateOrUpdate(context.TODO(), ns.resourceGroupName, request.Name, network.VirtualNetwork{
+		Location: to.StringPtr(request.Location),
+		VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
+			AddressSpace: &network.AddressSpace{
+				AddressPrefixes: &[]string{request.CIDR},
+			},
+		},
+	})
+	if err != nil {
+		return nil, emperror.Wrap(err, "request to create virtual network failed")
+	}
+	err = future.WaitForCompletionRef(context.TODO(), ns.client.Client)
+	if err != nil {
+		return nil, emperror.Wrap(err, "waiting for the completion of create virtual network operation failed")
+	}
+	return &azureNetwork{
+		cidrs: []string{request.CIDR},
+		id:    request.Name,
+		name:  request.Name,
+	}, nil
+}
+
+// DeleteNetwork deletes a virtual network from the resource group
+func (ns *azureNetworkService) DeleteNetwork(networkID string) error {
+	future, err := ns.client.Delete(context.TODO(), ns.resourceGroupName, networkID)
+	if err != nil {
+		return emperror.Wrap(err, "request to delete virtual network failed")
+	}
+	err = future.WaitForCompletionRef(context.TODO(), ns.client.Client)
+	return emperror.Wrap(err, "waiting for the completion of delete virtual network operation failed")
+}
+
+// CreateSubnet creates a subnet in a virtual network
+func (ns *azureNetworkService) CreateSubnet(request intNetwork.CreateSubnetRequest) (intNetwork.Subnet, error) {
+	future, err := ns.subnetsClient.CreateOrUpdate(context.TODO(), ns.resourceGroupName, request.NetworkID, request.Name, network.Subnet{
+		SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+			AddressPrefix: to.StringPtr(request.CIDR),
+		},
+	})
+	if err != nil {
+		return nil, emperror.Wrap(err, "request to create subnet failed")
+	}
+	err = future.WaitForCompletionRef(context.TODO(), ns.subnetsClient.Client)
+	if err != nil {
+		return nil, emperror.Wrap(err, "waiting for the completion of create subnet operation failed")
+	}
+	s, err := future.Result(ns.subnetsClient)
+	if err != nil {
+		return nil, emperror.Wrap(err, "getting subnet create result failed")
+	}
+	return &azureSubnet{
+		cidrs: []string{request.CIDR},
+		id:    to.String(s.ID),
+		name:  request.Name,
+	}, nil
+}
+
+// DeleteSubnet deletes a subnet of a virtual network, the subnet can be specified either by its name or resource ID
+func (ns *azureNetworkService) DeleteSubnet(networkID string, subnetID string) error {
+	future, err := ns.subnetsClient.Delete(context.TODO(), ns.resourceGroupName, networkID, path.Base(subnetID))
+	if err != nil {
+		return emperror.Wrap(err, "request to delete subnet failed")
+	}
+	err = future.WaitForCompletionRef(context.TODO(), ns.subnetsClient.Client)
+	return emperror.Wrap(err, "waiting for the completion of delete subnet operation failed")
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/banzaicloud/pipeline/internal/providers/azure/pke"
 	"github.com/banzaicloud/pipeline/model"
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
 )
 
 const (
@@ -58,6 +59,7 @@ type gormAzurePKENodePoolModel struct {
 	Name         string `gorm:"unique_index:idx_azure_pke_np_cluster_id_name"`
 	Roles        string
 	SubnetName   string
+	Taints       pkgCommon.NodePoolTaints `gorm:"type:text"`
 	Zones        string
 }
 
@@ -169,6 +171,7 @@ func fillNodePoolFromModel(nodePool *pke.NodePool, model gormAzurePKENodePoolMod
 	nodePool.Name = model.Name
 	nodePool.Roles = unmarshalStringSlice(model.Roles)
 	nodePool.Subnet.Name = model.SubnetName
+	nodePool.Taints = model.Taints
 	nodePool.Zones = unmarshalStringSlice(model.Zones)
 }
 
@@ -182,6 +185,7 @@ func fillModelFromNodePool(model *gormAzurePKENodePoolModel, nodePool pke.NodePo
 	model.Name = nodePool.Name
 	model.Roles = marshalStringSlice(nodePool.Roles)
 	model.SubnetName = nodePool.Subnet.Name
+	model.Taints = nodePool.Taints
 	model.Zones = marshalStringSlice(nodePool.Zones)
 }
 
@@ -17,6 +17,7 @@ package pke
 import (
 	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
 	intPKE "github.com/banzaicloud/pipeline/internal/pke"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
 )
 
 const PKEOnAzure = "pke-on-azure"
@@ -44,6 +45,7 @@ type NodePool struct {
 	Name         string
 	Roles        []string
 	Subnet       Subnetwork
+	Taints       pkgCommon.NodePoolTaints
 	Zones        []string
 }
 
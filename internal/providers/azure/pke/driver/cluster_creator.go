@@ -36,6 +36,7 @@ import (
 	"github.com/banzaicloud/pipeline/internal/providers/azure/pke/workflow"
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
 	pkgPKE "github.com/banzaicloud/pipeline/pkg/cluster/pke"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
 	pkgAzure "github.com/banzaicloud/pipeline/pkg/providers/azure"
 	pkgSecret "github.com/banzaicloud/pipeline/pkg/secret"
 	"github.com/banzaicloud/pipeline/secret"
@@ -81,6 +82,7 @@ type NodePool struct {
 	Zones        []string
 	Roles        []string
 	Labels       map[string]string
+	Taints       pkgCommon.NodePoolTaints
 	Autoscaling  bool
 	Count        int
 	Min          int
@@ -106,6 +108,7 @@ func (np NodePool) toPke() (pnp pke.NodePool) {
 	pnp.Name = np.Name
 	pnp.Roles = np.Roles
 	pnp.Subnet = pke.Subnetwork{Name: np.Subnet.Name}
+	pnp.Taints = np.Taints
 	pnp.Zones = np.Zones
 	return
 }
@@ -179,7 +182,8 @@ func (cc AzurePKEClusterCreator) Create(ctx context.Context, params AzurePKEClus
 			Subnet: pke.Subnetwork{
 				Name: np.Subnet.Name,
 			},
-			Zones: np.Zones,
+			Taints: np.Taints,
+			Zones:  np.Zones,
 		}
 	}
 	createParams := pke.CreateParams{
@@ -223,6 +227,7 @@ func (cc AzurePKEClusterCreator) Create(ctx context.Context, params AzurePKEClus
 				MinCount:     np.Min,
 				MaxCount:     np.Max,
 				Labels:       np.Labels,
+				Taints:       np.Taints,
 			}
 		}
 		var labelsMap map[string]map[string]string
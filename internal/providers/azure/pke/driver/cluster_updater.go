@@ -203,6 +203,7 @@ func (cu AzurePKEClusterUpdater) Update(ctx context.Context, params AzurePKEClus
 				MinCount:     np.Min,
 				MaxCount:     np.Max,
 				Labels:       np.Labels,
+				Taints:       np.Taints,
 			}
 		}
 		labels, err = pipCluster.GetDesiredLabelsForCluster(ctx, commonCluster, nodePoolStatuses, true)
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"emperror.dev/emperror"
 	"github.com/Azure/go-autorest/autorest/to"
@@ -89,6 +90,15 @@ func (f nodePoolTemplateFactory) getTemplates(np NodePool) (workflow.VirtualMach
 		}
 	}
 
+	// node pool taints are registered by kubelet, so new instances of the scale set get them as well
+	if len(np.Taints) > 0 {
+		if taints == "" || taints == "," {
+			taints = strings.Join(np.Taints.Strings(), ",")
+		} else {
+			taints += "," + strings.Join(np.Taints.Strings(), ",")
+		}
+	}
+
 	vmssName := pke.GetVMSSName(f.ClusterName, np.Name)
 
 	cnsgn := nsgn
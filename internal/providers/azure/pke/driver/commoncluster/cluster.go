@@ -250,6 +250,7 @@ func (a *AzurePkeCluster) GetStatus() (*pkgCluster.GetClusterStatusResponse, err
 			InstanceType: np.InstanceType,
 			MinCount:     int(np.Min),
 			MaxCount:     int(np.Max),
+			Taints:       np.Taints,
 		}
 	}
 
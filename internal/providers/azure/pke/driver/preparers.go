@@ -21,12 +21,14 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"reflect"
 
 	"emperror.dev/emperror"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/sirupsen/logrus"
 
 	"github.com/banzaicloud/pipeline/internal/providers/azure/pke"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
 	"github.com/banzaicloud/pipeline/pkg/providers/azure"
 )
 
@@ -216,6 +218,10 @@ func (p NodePoolPreparer) prepareNewNodePool(ctx context.Context, nodePool *Node
 		return validationErrorf("%[1]s.Min must not be greater than %[1]s.Max", p.namespace)
 	}
 
+	if err := pkgCommon.ValidateNodePoolTaints(nodePool.Taints); err != nil {
+		return validationErrorf("%s.Taints are not valid: %s", p.namespace, err.Error())
+	}
+
 	if nodePool.Subnet.Name == "" {
 		if p.subnetName == "" {
 			nodePool.Subnet.Name = fmt.Sprintf("subnet-%s", nodePool.Name)
@@ -295,6 +301,13 @@ func (p NodePoolPreparer) prepareExistingNodePool(ctx context.Context, nodePool
 		}
 		nodePool.Zones = existing.Zones
 	}
+	// taints are set on the nodes when they join the cluster, so they cannot be changed on existing scale sets
+	if !reflect.DeepEqual(nodePool.Taints, existing.Taints) {
+		if nodePool.Taints != nil {
+			logMismatchOn(p, "Taints", existing.Taints, nodePool.Taints)
+		}
+		nodePool.Taints = existing.Taints
+	}
 
 	return nil
 }
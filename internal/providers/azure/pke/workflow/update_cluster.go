@@ -150,6 +150,20 @@ func UpdateClusterWorkflow(ctx workflow.Context, input UpdateClusterWorkflowInpu
 			return err
 		}
 	}
+	// restore node pool taints in the label sets
+	{
+		activityInput := cluster.RunPostHookActivityInput{
+			ClusterID: input.ClusterID,
+			HookName:  pkgCluster.SyncNodePoolTaints,
+			Status:    pkgCluster.Updating,
+		}
+		err := workflow.ExecuteActivity(ctx, cluster.RunPostHookActivityName, activityInput).Get(ctx, nil)
+		if err != nil {
+			err = errors.WrapIff(err, "%q activity failed", cluster.RunPostHookActivityName)
+			setClusterStatus(ctx, input.ClusterID, pkgCluster.Warning, err.Error()) // nolint: errcheck
+			return err
+		}
+	}
 	{
 		futures := make([]workflow.Future, len(input.VMSSToUpdate))
 		for i, vmssChanges := range input.VMSSToUpdate {
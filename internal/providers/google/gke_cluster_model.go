@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/banzaicloud/pipeline/internal/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
 	"github.com/jinzhu/gorm"
 )
 
@@ -117,8 +118,9 @@ type GKENodePoolModel struct {
 	NodeMaxCount     int
 	NodeCount        int
 	NodeInstanceType string
-	Labels           map[string]string `gorm:"-"`
-	Delete           bool              `gorm:"-"`
+	Taints           pkgCommon.NodePoolTaints `gorm:"type:text"`
+	Labels           map[string]string        `gorm:"-"`
+	Delete           bool                     `gorm:"-"`
 }
 
 // TableName changes the default table name.
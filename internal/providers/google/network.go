@@ -17,6 +17,9 @@ package google
 import (
 	"fmt"
 	"strconv"
+	"time"
+
+	"emperror.dev/errors"
 
 	"google.golang.org/api/compute/v1"
 
@@ -171,8 +174,106 @@ func (ns *googleNetworkService) ListRouteTables(networkID string) ([]network.Rou
 	return routeTables, nil
 }
 
+// CreateNetwork creates a custom mode VPC network in the project at Google.
+// Google VPC networks have no address space, the CIDR blocks belong to the subnetworks.
+func (ns *googleNetworkService) CreateNetwork(request network.CreateNetworkRequest) (network.Network, error) {
+	if request.CIDR != "" {
+		return nil, network.NewValidationError("Google VPC networks have no CIDR block, specify it for the subnetworks instead")
+	}
+	projectID := ns.serviceAccount.ProjectId
+	op, err := ns.computeService.Networks.Insert(projectID, &compute.Network{
+		Name:                  request.Name,
+		AutoCreateSubnetworks: false,
+		ForceSendFields:       []string{"AutoCreateSubnetworks"},
+	}).Do()
+	if err != nil {
+		return nil, err
+	}
+	if err := ns.waitForOperation(op); err != nil {
+		return nil, err
+	}
+	net, err := ns.computeService.Networks.Get(projectID, request.Name).Do()
+	if err != nil {
+		return nil, err
+	}
+	return &googleNetwork{
+		id:   idToString(net.Id),
+		name: net.Name,
+	}, nil
+}
+
+// DeleteNetwork deletes a VPC network of the project at Google
+func (ns *googleNetworkService) DeleteNetwork(networkID string) error {
+	op, err := ns.computeService.Networks.Delete(ns.serviceAccount.ProjectId, networkID).Do()
+	if err != nil {
+		return err
+	}
+	return ns.waitForOperation(op)
+}
+
+// CreateSubnet creates a subnetwork in the region of the service in the specified VPC network at Google
+func (ns *googleNetworkService) CreateSubnet(request network.CreateSubnetRequest) (network.Subnet, error) {
+	projectID := ns.serviceAccount.ProjectId
+	net, err := ns.computeService.Networks.Get(projectID, request.NetworkID).Do()
+	if err != nil {
+		return nil, err
+	}
+	op, err := ns.computeService.Subnetworks.Insert(projectID, ns.region, &compute.Subnetwork{
+		Name:        request.Name,
+		IpCidrRange: request.CIDR,
+		Network:     net.SelfLink,
+	}).Do()
+	if err != nil {
+		return nil, err
+	}
+	if err := ns.waitForOperation(op); err != nil {
+		return nil, err
+	}
+	subnet, err := ns.computeService.Subnetworks.Get(projectID, ns.region, request.Name).Do()
+	if err != nil {
+		return nil, err
+	}
+	return &googleSubnet{
+		cidrs:    []string{subnet.IpCidrRange},
+		id:       idToString(subnet.Id),
+		location: ns.region,
+		name:     subnet.Name,
+	}, nil
+}
+
+// DeleteSubnet deletes a subnetwork in the region of the service at Google
+func (ns *googleNetworkService) DeleteSubnet(networkID string, subnetID string) error {
+	op, err := ns.computeService.Subnetworks.Delete(ns.serviceAccount.ProjectId, ns.region, subnetID).Do()
+	if err != nil {
+		return err
+	}
+	return ns.waitForOperation(op)
+}
+
+// waitForOperation waits until a global or regional compute operation is done
+func (ns *googleNetworkService) waitForOperation(op *compute.Operation) error {
+	projectID := ns.serviceAccount.ProjectId
+	for op.Status != "DONE" {
+		time.Sleep(time.Second)
+
+		var err error
+		if op.Region != "" {
+			op, err = ns.computeService.RegionOperations.Get(projectID, ns.region, op.Name).Do()
+		} else {
+			op, err = ns.computeService.GlobalOperations.Get(projectID, op.Name).Do()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if op.Error != nil && len(op.Error.Errors) > 0 {
+		return errors.Errorf("operation %s failed: %s", op.Name, op.Error.Errors[0].Message)
+	}
+	return nil
+}
+
 func newComputeServiceFromServiceAccount(serviceAccount *verify.ServiceAccount) (*compute.Service, error) {
-	client, err := verify.CreateOath2Client(serviceAccount, compute.ComputeReadonlyScope)
+	client, err := verify.CreateOath2Client(serviceAccount, compute.ComputeScope)
 	if err != nil {
 		return nil, err
 	}
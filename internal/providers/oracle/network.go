@@ -16,6 +16,8 @@ package oracle
 
 import (
 	"emperror.dev/emperror"
+	"github.com/oracle/oci-go-sdk/common"
+	"github.com/oracle/oci-go-sdk/core"
 	"github.com/sirupsen/logrus"
 
 	"github.com/banzaicloud/pipeline/internal/network"
@@ -154,6 +156,63 @@ func (ns *oracleNetworkService) ListRouteTables(networkID string) ([]network.Rou
 	return routeTables, nil
 }
 
+// CreateNetwork creates a VCN in the compartment of the secret
+func (ns *oracleNetworkService) CreateNetwork(request network.CreateNetworkRequest) (network.Network, error) {
+	if request.CIDR == "" {
+		return nil, network.NewValidationError("CIDR block is required for Oracle VCNs")
+	}
+	vcn, err := ns.client.CreateVCN(core.CreateVcnRequest{
+		CreateVcnDetails: core.CreateVcnDetails{
+			CidrBlock:     common.String(request.CIDR),
+			CompartmentId: common.String(ns.client.CompartmentOCID),
+			DisplayName:   common.String(request.Name),
+		},
+	})
+	if err != nil {
+		return nil, emperror.Wrap(err, "failed to create VCN")
+	}
+	return &oracleNetwork{
+		cidrs: []string{deref(vcn.CidrBlock)},
+		id:    deref(vcn.Id),
+		name:  deref(vcn.DisplayName),
+	}, nil
+}
+
+// DeleteNetwork deletes a VCN
+func (ns *oracleNetworkService) DeleteNetwork(networkID string) error {
+	return emperror.WrapWith(ns.client.DeleteVCN(&networkID), "failed to delete VCN", "networkID", networkID)
+}
+
+// CreateSubnet creates a VCN subnetwork in the specified availability domain
+func (ns *oracleNetworkService) CreateSubnet(request network.CreateSubnetRequest) (network.Subnet, error) {
+	if request.Location == "" {
+		return nil, network.NewValidationError("availability domain is required for Oracle subnets")
+	}
+	subnet, err := ns.client.CreateSubnet(core.CreateSubnetRequest{
+		CreateSubnetDetails: core.CreateSubnetDetails{
+			AvailabilityDomain: common.String(request.Location),
+			CidrBlock:          common.String(request.CIDR),
+			CompartmentId:      common.String(ns.client.CompartmentOCID),
+			VcnId:              common.String(request.NetworkID),
+			DisplayName:        common.String(request.Name),
+		},
+	})
+	if err != nil {
+		return nil, emperror.WrapWith(err, "failed to create subnet", "networkID", request.NetworkID)
+	}
+	return &oracleSubnet{
+		cidrs:    []string{deref(subnet.CidrBlock)},
+		id:       deref(subnet.Id),
+		location: deref(subnet.AvailabilityDomain),
+		name:     deref(subnet.DisplayName),
+	}, nil
+}
+
+// DeleteSubnet deletes a VCN subnetwork
+func (ns *oracleNetworkService) DeleteSubnet(networkID string, subnetID string) error {
+	return emperror.WrapWith(ns.client.DeleteSubnet(&subnetID), "failed to delete subnet", "networkID", networkID, "subnetID", subnetID)
+}
+
 func deref(p *string) string {
 	if p == nil {
 		return ""
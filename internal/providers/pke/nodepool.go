@@ -22,6 +22,8 @@ import (
 	"time"
 
 	"github.com/spf13/cast"
+
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
 )
 
 type NodePools []NodePool
@@ -33,13 +35,14 @@ type NodePool struct {
 
 	ClusterID uint `gorm:"foreignkey:ClusterIDl;association_foreignkey:ClusterID;unique_index:idx_topology_nodepools_cluster_id_name"`
 
-	Name           string            `yaml:"name" gorm:"unique_index:idx_topology_nodepools_cluster_id_name"`
-	Roles          Roles             `yaml:"roles" gorm:"type:varchar(255)"`
-	Hosts          Hosts             `yaml:"hosts" gorm:"foreignkey:NodePoolID"`
-	Provider       NodePoolProvider  `yaml:"provider"`
-	ProviderConfig Config            `yaml:"providerConfig" gorm:"column:provider_config;type:text"`
-	Labels         map[string]string `yaml:"labels" gorm:"-"`
-	Autoscaling    bool              `yaml:"autoscaling" gorm:"default:false"`
+	Name           string                   `yaml:"name" gorm:"unique_index:idx_topology_nodepools_cluster_id_name"`
+	Roles          Roles                    `yaml:"roles" gorm:"type:varchar(255)"`
+	Hosts          Hosts                    `yaml:"hosts" gorm:"foreignkey:NodePoolID"`
+	Provider       NodePoolProvider         `yaml:"provider"`
+	ProviderConfig Config                   `yaml:"providerConfig" gorm:"column:provider_config;type:text"`
+	Labels         map[string]string        `yaml:"labels" gorm:"-"`
+	Taints         pkgCommon.NodePoolTaints `yaml:"taints" gorm:"type:text"`
+	Autoscaling    bool                     `yaml:"autoscaling" gorm:"default:false"`
 }
 
 // TableName changes the default table name.
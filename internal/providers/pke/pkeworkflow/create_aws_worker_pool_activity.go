@@ -78,7 +78,7 @@ func (a *CreateWorkerPoolActivity) Execute(ctx context.Context, input CreateWork
 		return "", emperror.Wrap(err, "can't get Kubernetes version")
 	}
 
-	imageID, err := getDefaultImageID(cluster.GetLocation(), ver)
+	imageID, err := GetDefaultImageID(cluster.GetLocation(), ver)
 	if err != nil {
 		return "", emperror.Wrapf(err, "failed to get default image for Kubernetes version %s", ver)
 	}
@@ -27,7 +27,8 @@ import (
 const CreateClusterWorkflowName = "pke-create-cluster"
 const pkeVersion = "0.4.9"
 
-func getDefaultImageID(region, kubernetesVersion string) (string, error) {
+// GetDefaultImageID returns the default PKE image of a Kubernetes version in a region.
+func GetDefaultImageID(region, kubernetesVersion string) (string, error) {
 	constraint112, err := semver.NewConstraint("~1.12.0")
 	if err != nil {
 		return "", errors.Wrap(err, "could not create semver constraint for Kubernetes version 1.12+")
@@ -0,0 +1,92 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// InstalledSecretModel records a Kubernetes secret installed into a cluster from a Pipeline secret.
+type InstalledSecretModel struct {
+	ID             uint   `gorm:"primary_key"`
+	OrganizationID uint   `gorm:"index;not null"`
+	SecretID       string `gorm:"index;size:64;not null"`
+	ClusterID      uint   `gorm:"unique_index:idx_installed_secrets_cluster_namespace_name;not null"`
+	Namespace      string `gorm:"unique_index:idx_installed_secrets_cluster_namespace_name;not null"`
+	Name           string `gorm:"unique_index:idx_installed_secrets_cluster_namespace_name;not null"`
+
+	// Spec is the JSON encoded key mapping the secret was installed with, empty if every key was installed.
+	Spec string `gorm:"type:text"`
+	// Merged is true if the secret was merged into an existing Kubernetes secret.
+	Merged bool
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TableName changes the default table name.
+func (InstalledSecretModel) TableName() string {
+	return "installed_secrets"
+}
+
+// Migrate executes the table migrations for the secret usage tracking.
+func Migrate(db *gorm.DB, logger logrus.FieldLogger) error {
+	logger.WithFields(logrus.Fields{
+		"table_names": InstalledSecretModel{}.TableName(),
+	}).Info("migrating secret tables")
+
+	return db.AutoMigrate(&InstalledSecretModel{}).Error
+}
+
+// RecordInstalledSecret records that a Kubernetes secret has been installed into a cluster from a Pipeline secret.
+// Installing another secret under the same name and namespace replaces the record.
+func RecordInstalledSecret(db *gorm.DB, installed InstalledSecretModel) error {
+	var model InstalledSecretModel
+
+	err := db.Where(InstalledSecretModel{ClusterID: installed.ClusterID, Namespace: installed.Namespace, Name: installed.Name}).
+		Assign(map[string]interface{}{
+			"organization_id": installed.OrganizationID,
+			"secret_id":       installed.SecretID,
+			"spec":            installed.Spec,
+			"merged":          installed.Merged,
+		}).
+		FirstOrCreate(&model).Error
+	if err != nil {
+		return errors.Wrap(err, "failed to record installed secret")
+	}
+
+	return nil
+}
+
+// ListInstalledSecrets returns the Kubernetes secrets installed from a Pipeline secret into existing clusters.
+func ListInstalledSecrets(db *gorm.DB, organizationID uint, secretID string) ([]InstalledSecretModel, error) {
+	var installed []InstalledSecretModel
+
+	err := db.
+		Joins("JOIN clusters ON clusters.id = installed_secrets.cluster_id").
+		Where("installed_secrets.organization_id = ? AND installed_secrets.secret_id = ?", organizationID, secretID).
+		Where("clusters.deleted_at IS NULL").
+		Order("installed_secrets.id").
+		Find(&installed).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list installed secrets")
+	}
+
+	return installed, nil
+}
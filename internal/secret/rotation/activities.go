@@ -0,0 +1,113 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rotation
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/emperror"
+)
+
+const ListDueRotationsActivityName = "list-due-secret-rotations"
+
+type DueRotation struct {
+	OrganizationID uint
+	SecretID       string
+}
+
+type ListDueRotationsActivity struct {
+	manager *Manager
+}
+
+func NewListDueRotationsActivity(manager *Manager) ListDueRotationsActivity {
+	return ListDueRotationsActivity{
+		manager: manager,
+	}
+}
+
+func (a ListDueRotationsActivity) Execute(_ context.Context) ([]DueRotation, error) {
+	policies, err := a.manager.ListDuePolicies(time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	rotations := make([]DueRotation, 0, len(policies))
+	for _, policy := range policies {
+		rotations = append(rotations, DueRotation{
+			OrganizationID: policy.OrganizationID,
+			SecretID:       policy.SecretID,
+		})
+	}
+
+	return rotations, nil
+}
+
+const RotateSecretActivityName = "rotate-secret"
+
+type RotateSecretActivityInput struct {
+	OrganizationID uint
+	SecretID       string
+	UpdatedBy      string
+}
+
+type RotateSecretActivity struct {
+	manager *Manager
+}
+
+func NewRotateSecretActivity(manager *Manager) RotateSecretActivity {
+	return RotateSecretActivity{
+		manager: manager,
+	}
+}
+
+func (a RotateSecretActivity) Execute(_ context.Context, input RotateSecretActivityInput) (*RotationResult, error) {
+	return a.manager.RotateSecret(input.OrganizationID, input.SecretID, input.UpdatedBy)
+}
+
+const InstallSecretActivityName = "install-rotated-secret"
+
+type InstallSecretActivityInput struct {
+	OrganizationID uint
+	SecretName     string
+	Installation   Installation
+}
+
+// SecretInstaller installs a secret into a cluster again.
+type SecretInstaller interface {
+	InstallSecret(ctx context.Context, organizationID uint, secretName string, installation Installation) error
+}
+
+type InstallSecretActivity struct {
+	installer SecretInstaller
+}
+
+func NewInstallSecretActivity(installer SecretInstaller) InstallSecretActivity {
+	return InstallSecretActivity{
+		installer: installer,
+	}
+}
+
+func (a InstallSecretActivity) Execute(ctx context.Context, input InstallSecretActivityInput) error {
+	err := a.installer.InstallSecret(ctx, input.OrganizationID, input.SecretName, input.Installation)
+
+	return emperror.WrapWith(
+		err,
+		"failed to install rotated secret",
+		"cluster", input.Installation.ClusterID,
+		"namespace", input.Installation.Namespace,
+		"name", input.Installation.Name,
+	)
+}
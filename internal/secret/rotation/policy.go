@@ -0,0 +1,335 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rotation
+
+import (
+	"fmt"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+
+	intSecret "github.com/banzaicloud/pipeline/internal/secret"
+	secretTypes "github.com/banzaicloud/pipeline/pkg/secret"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+// MinInterval is the minimum time between two scheduled rotations of a secret.
+const MinInterval = time.Hour
+
+// Policy describes when the values of a generated secret are regenerated.
+// Exactly one of Interval and DaysBeforeExpiry is set.
+type Policy struct {
+	ID             uint   `gorm:"primary_key"`
+	OrganizationID uint   `gorm:"unique_index:idx_secret_rotation_policies_organization_secret;not null"`
+	SecretID       string `gorm:"unique_index:idx_secret_rotation_policies_organization_secret;size:64;not null"`
+
+	// Interval rotates the secret periodically.
+	Interval time.Duration `gorm:"column:rotation_interval"`
+	// DaysBeforeExpiry rotates a TLS secret the given number of days before its certificate expires.
+	DaysBeforeExpiry int
+
+	NextRotationAt time.Time `gorm:"index;not null"`
+	LastRotatedAt  *time.Time
+	LastError      string `gorm:"type:text"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	CreatedBy uint
+}
+
+// TableName changes the default table name.
+func (Policy) TableName() string {
+	return "secret_rotation_policies"
+}
+
+// PolicyNotFoundError is returned when a secret has no rotation policy.
+type PolicyNotFoundError struct {
+	OrganizationID uint
+	SecretID       string
+}
+
+// Error implements the error interface.
+func (e PolicyNotFoundError) Error() string {
+	return fmt.Sprintf("secret %s has no rotation policy", e.SecretID)
+}
+
+// ValidationError is returned when a secret cannot be rotated as requested.
+type ValidationError struct {
+	message string
+}
+
+// Error implements the error interface.
+func (e ValidationError) Error() string {
+	return e.message
+}
+
+// SecretStore reads and rotates secrets.
+type SecretStore interface {
+	Get(organizationID uint, secretID string) (*secret.SecretItemResponse, error)
+	Rotate(organizationID uint, secretID string, updatedBy string) error
+}
+
+// SetPolicyRequest contains the parameters of a rotation policy.
+type SetPolicyRequest struct {
+	OrganizationID   uint
+	SecretID         string
+	UserID           uint
+	Interval         time.Duration
+	DaysBeforeExpiry int
+}
+
+// Installation is a Kubernetes secret installed from a rotated secret.
+type Installation struct {
+	ClusterID uint
+	Namespace string
+	Name      string
+	Spec      string
+	Merged    bool
+}
+
+// RotationResult describes a rotated secret.
+type RotationResult struct {
+	SecretName    string
+	Installations []Installation
+}
+
+// Manager manages secret rotation policies and rotates secrets.
+type Manager struct {
+	db      *gorm.DB
+	secrets SecretStore
+}
+
+// NewManager returns a new Manager.
+func NewManager(db *gorm.DB, secrets SecretStore) *Manager {
+	return &Manager{
+		db:      db,
+		secrets: secrets,
+	}
+}
+
+// ValidateRotation returns an error if a secret cannot be rotated.
+func (m *Manager) ValidateRotation(organizationID uint, secretID string) error {
+	_, err := m.getRotatableSecret(organizationID, secretID)
+
+	return err
+}
+
+// SetPolicy creates or replaces the rotation policy of a secret.
+func (m *Manager) SetPolicy(request SetPolicyRequest) (*Policy, error) {
+	secretItem, err := m.getRotatableSecret(request.OrganizationID, request.SecretID)
+	if err != nil {
+		return nil, err
+	}
+
+	if (request.Interval == 0) == (request.DaysBeforeExpiry == 0) {
+		return nil, ValidationError{"exactly one of interval and daysBeforeExpiry must be set"}
+	}
+
+	if request.Interval != 0 && request.Interval < MinInterval {
+		return nil, ValidationError{fmt.Sprintf("interval must be at least %s", MinInterval)}
+	}
+
+	if request.DaysBeforeExpiry < 0 {
+		return nil, ValidationError{"daysBeforeExpiry must be positive"}
+	}
+
+	if request.DaysBeforeExpiry != 0 && secretItem.Type != secretTypes.TLSSecretType {
+		return nil, ValidationError{"daysBeforeExpiry can only be used for TLS secrets"}
+	}
+
+	var policy Policy
+
+	err = m.db.Where(Policy{OrganizationID: request.OrganizationID, SecretID: request.SecretID}).First(&policy).Error
+	if err != nil && !gorm.IsRecordNotFoundError(err) {
+		return nil, errors.WrapIf(err, "failed to get rotation policy")
+	}
+
+	if policy.ID == 0 {
+		policy.OrganizationID = request.OrganizationID
+		policy.SecretID = request.SecretID
+		policy.CreatedBy = request.UserID
+	}
+
+	policy.Interval = request.Interval
+	policy.DaysBeforeExpiry = request.DaysBeforeExpiry
+
+	now := time.Now().UTC()
+
+	// an expiring certificate is rotated at the next check
+	policy.NextRotationAt, err = nextRotation(policy, secretItem, now, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.db.Save(&policy).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to save rotation policy")
+	}
+
+	return &policy, nil
+}
+
+// GetPolicy returns the rotation policy of a secret.
+func (m *Manager) GetPolicy(organizationID uint, secretID string) (*Policy, error) {
+	var policy Policy
+
+	err := m.db.Where(Policy{OrganizationID: organizationID, SecretID: secretID}).First(&policy).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, errors.WithStack(PolicyNotFoundError{OrganizationID: organizationID, SecretID: secretID})
+	} else if err != nil {
+		return nil, errors.WrapIf(err, "failed to get rotation policy")
+	}
+
+	return &policy, nil
+}
+
+// DeletePolicy deletes the rotation policy of a secret.
+func (m *Manager) DeletePolicy(organizationID uint, secretID string) error {
+	policy, err := m.GetPolicy(organizationID, secretID)
+	if err != nil {
+		return err
+	}
+
+	return errors.WrapIf(m.db.Delete(policy).Error, "failed to delete rotation policy")
+}
+
+// ListDuePolicies returns the policies of the secrets which should be rotated by now.
+func (m *Manager) ListDuePolicies(now time.Time) ([]Policy, error) {
+	var policies []Policy
+
+	err := m.db.Where("next_rotation_at <= ?", now).Order("next_rotation_at").Find(&policies).Error
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to list due rotation policies")
+	}
+
+	return policies, nil
+}
+
+// RotateSecret regenerates the values of a secret and schedules its next rotation.
+// It returns the Kubernetes secrets which were installed from the secret and should be updated.
+func (m *Manager) RotateSecret(organizationID uint, secretID string, updatedBy string) (*RotationResult, error) {
+	secretItem, err := m.getRotatableSecret(organizationID, secretID)
+	if err != nil {
+		return nil, err
+	}
+
+	rotationErr := m.secrets.Rotate(organizationID, secretID, updatedBy)
+
+	if err := m.recordRotation(organizationID, secretID, rotationErr); err != nil {
+		return nil, err
+	}
+
+	if rotationErr != nil {
+		return nil, errors.WrapIf(rotationErr, "failed to rotate secret")
+	}
+
+	installed, err := intSecret.ListInstalledSecrets(m.db, organizationID, secretID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RotationResult{
+		SecretName:    secretItem.Name,
+		Installations: make([]Installation, 0, len(installed)),
+	}
+
+	for _, i := range installed {
+		result.Installations = append(result.Installations, Installation{
+			ClusterID: i.ClusterID,
+			Namespace: i.Namespace,
+			Name:      i.Name,
+			Spec:      i.Spec,
+			Merged:    i.Merged,
+		})
+	}
+
+	return result, nil
+}
+
+// recordRotation updates the policy of a secret after a rotation attempt, if the secret has one.
+func (m *Manager) recordRotation(organizationID uint, secretID string, rotationErr error) error {
+	policy, err := m.GetPolicy(organizationID, secretID)
+	if errors.As(err, &PolicyNotFoundError{}) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+
+	if rotationErr != nil {
+		policy.LastError = rotationErr.Error()
+		policy.NextRotationAt = now.Add(MinInterval)
+	} else {
+		secretItem, err := m.secrets.Get(organizationID, secretID)
+		if err != nil {
+			return errors.WrapIf(err, "failed to get rotated secret")
+		}
+
+		policy.LastRotatedAt = &now
+		policy.LastError = ""
+
+		policy.NextRotationAt, err = nextRotation(*policy, secretItem, now, now.Add(MinInterval))
+		if err != nil {
+			return err
+		}
+	}
+
+	return errors.WrapIf(m.db.Save(policy).Error, "failed to save rotation policy")
+}
+
+func (m *Manager) getRotatableSecret(organizationID uint, secretID string) (*secret.SecretItemResponse, error) {
+	secretItem, err := m.secrets.Get(organizationID, secretID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !secret.IsRotatable(secretItem.Type) {
+		return nil, ValidationError{fmt.Sprintf("secrets of type %q cannot be rotated", secretItem.Type)}
+	}
+
+	// secrets generated and managed by Pipeline (eg. cluster CAs) must not be regenerated
+	if err := secret.HasForbiddenTag(secretItem.Tags); err != nil {
+		return nil, err
+	}
+
+	for _, tag := range secretItem.Tags {
+		if tag == secretTypes.TagBanzaiReadonly {
+			return nil, secret.ReadOnlyError{SecretID: secretItem.ID}
+		}
+	}
+
+	return secretItem, nil
+}
+
+// nextRotation returns the time of the next rotation of a secret, but not earlier than the given time.
+func nextRotation(policy Policy, secretItem *secret.SecretItemResponse, now time.Time, earliest time.Time) (time.Time, error) {
+	next := now.Add(policy.Interval)
+
+	if policy.DaysBeforeExpiry > 0 {
+		expiry, err := secretItem.CertificateExpiry()
+		if err != nil {
+			return time.Time{}, ValidationError{fmt.Sprintf("cannot determine certificate expiry: %s", err.Error())}
+		}
+
+		next = expiry.Add(-time.Duration(policy.DaysBeforeExpiry) * 24 * time.Hour).UTC()
+	}
+
+	if next.Before(earliest) {
+		return earliest, nil
+	}
+
+	return next, nil
+}
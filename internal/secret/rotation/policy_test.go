@@ -0,0 +1,194 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rotation_test
+
+import (
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	intSecret "github.com/banzaicloud/pipeline/internal/secret"
+	"github.com/banzaicloud/pipeline/internal/secret/rotation"
+	pkgSecret "github.com/banzaicloud/pipeline/pkg/secret"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+func TestManager(t *testing.T) {
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	db.DB().SetMaxOpenConns(1)
+
+	logger, _ := test.NewNullLogger()
+	require.NoError(t, intSecret.Migrate(db, logger))
+	require.NoError(t, rotation.Migrate(db, logger))
+	require.NoError(t, db.Exec("CREATE TABLE clusters (id integer primary key, deleted_at datetime)").Error)
+	require.NoError(t, db.Exec("INSERT INTO clusters VALUES (1, NULL)").Error)
+
+	const orgID = 1
+
+	store := secret.NewInMemorySecretStore()
+	manager := rotation.NewManager(db, store)
+
+	passwordID, err := store.Store(orgID, &secret.CreateSecretRequest{
+		Name:   "password",
+		Type:   pkgSecret.PasswordSecretType,
+		Values: map[string]string{pkgSecret.Username: "admin", pkgSecret.Password: "randAlphaNum,20"},
+	})
+	require.NoError(t, err)
+
+	tlsID, err := store.Store(orgID, &secret.CreateSecretRequest{
+		Name:   "tls",
+		Type:   pkgSecret.TLSSecretType,
+		Values: map[string]string{pkgSecret.TLSHosts: "example.com", pkgSecret.TLSValidity: "240h"},
+	})
+	require.NoError(t, err)
+
+	genericID, err := store.Store(orgID, &secret.CreateSecretRequest{
+		Name:   "generic",
+		Type:   pkgSecret.GenericSecret,
+		Values: map[string]string{"key": "value"},
+	})
+	require.NoError(t, err)
+
+	readOnlyID, err := store.Store(orgID, &secret.CreateSecretRequest{
+		Name:   "cluster-1-ca",
+		Type:   pkgSecret.TLSSecretType,
+		Values: map[string]string{pkgSecret.TLSHosts: "example.com"},
+		Tags:   []string{pkgSecret.TagBanzaiReadonly},
+	})
+	require.NoError(t, err)
+
+	forbiddenID, err := store.Store(orgID, &secret.CreateSecretRequest{
+		Name:   "kubeconfig-password",
+		Type:   pkgSecret.PasswordSecretType,
+		Values: map[string]string{pkgSecret.Username: "admin", pkgSecret.Password: "randAlphaNum,20"},
+		Tags:   []string{pkgSecret.TagKubeConfig},
+	})
+	require.NoError(t, err)
+
+	t.Run("RestrictedSecrets", func(t *testing.T) {
+		_, err := manager.SetPolicy(rotation.SetPolicyRequest{OrganizationID: orgID, SecretID: readOnlyID, Interval: 24 * time.Hour})
+		assert.True(t, errors.As(err, &secret.ReadOnlyError{}))
+
+		_, err = manager.SetPolicy(rotation.SetPolicyRequest{OrganizationID: orgID, SecretID: forbiddenID, Interval: 24 * time.Hour})
+		assert.True(t, errors.As(err, &secret.ForbiddenError{}))
+
+		_, err = manager.RotateSecret(orgID, readOnlyID, "admin")
+		assert.True(t, errors.As(err, &secret.ReadOnlyError{}))
+	})
+
+	t.Run("InvalidPolicies", func(t *testing.T) {
+		for name, request := range map[string]rotation.SetPolicyRequest{
+			"not rotatable":      {OrganizationID: orgID, SecretID: genericID, Interval: 24 * time.Hour},
+			"missing schedule":   {OrganizationID: orgID, SecretID: passwordID},
+			"both schedules":     {OrganizationID: orgID, SecretID: tlsID, Interval: 24 * time.Hour, DaysBeforeExpiry: 1},
+			"short interval":     {OrganizationID: orgID, SecretID: passwordID, Interval: time.Minute},
+			"expiry of password": {OrganizationID: orgID, SecretID: passwordID, DaysBeforeExpiry: 1},
+		} {
+			_, err := manager.SetPolicy(request)
+			assert.True(t, errors.As(err, &rotation.ValidationError{}), name)
+		}
+
+		_, err := manager.SetPolicy(rotation.SetPolicyRequest{OrganizationID: orgID, SecretID: "missing", Interval: 24 * time.Hour})
+		assert.Equal(t, secret.ErrSecretNotExists, err)
+	})
+
+	t.Run("Policies", func(t *testing.T) {
+		policy, err := manager.SetPolicy(rotation.SetPolicyRequest{OrganizationID: orgID, SecretID: passwordID, Interval: 24 * time.Hour})
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(24*time.Hour), policy.NextRotationAt, time.Minute)
+
+		tlsSecret, err := store.Get(orgID, tlsID)
+		require.NoError(t, err)
+		expiry, err := tlsSecret.CertificateExpiry()
+		require.NoError(t, err)
+
+		policy, err = manager.SetPolicy(rotation.SetPolicyRequest{OrganizationID: orgID, SecretID: tlsID, DaysBeforeExpiry: 3})
+		require.NoError(t, err)
+		assert.WithinDuration(t, expiry.Add(-72*time.Hour), policy.NextRotationAt, time.Second)
+
+		// the certificate expires within the given days, so it is due right away
+		policy, err = manager.SetPolicy(rotation.SetPolicyRequest{OrganizationID: orgID, SecretID: tlsID, DaysBeforeExpiry: 30})
+		require.NoError(t, err)
+		assert.Equal(t, 30, policy.DaysBeforeExpiry)
+
+		due, err := manager.ListDuePolicies(time.Now())
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		assert.Equal(t, tlsID, due[0].SecretID)
+
+		require.NoError(t, manager.DeletePolicy(orgID, passwordID))
+
+		_, err = manager.GetPolicy(orgID, passwordID)
+		assert.True(t, errors.As(err, &rotation.PolicyNotFoundError{}))
+	})
+
+	t.Run("RotateSecret", func(t *testing.T) {
+		err := intSecret.RecordInstalledSecret(db, intSecret.InstalledSecretModel{
+			OrganizationID: orgID,
+			SecretID:       tlsID,
+			ClusterID:      1,
+			Namespace:      "default",
+			Name:           "tls",
+		})
+		require.NoError(t, err)
+
+		before, err := store.Get(orgID, tlsID)
+		require.NoError(t, err)
+
+		result, err := manager.RotateSecret(orgID, tlsID, "admin")
+		require.NoError(t, err)
+		assert.Equal(t, "tls", result.SecretName)
+		assert.Equal(t, []rotation.Installation{{ClusterID: 1, Namespace: "default", Name: "tls"}}, result.Installations)
+
+		after, err := store.Get(orgID, tlsID)
+		require.NoError(t, err)
+		assert.Equal(t, before.Version+1, after.Version)
+		assert.Equal(t, "admin", after.UpdatedBy)
+		assert.Equal(t, "example.com", after.Values[pkgSecret.TLSHosts])
+		assert.NotEqual(t, before.Values[pkgSecret.ServerCert], after.Values[pkgSecret.ServerCert])
+
+		policy, err := manager.GetPolicy(orgID, tlsID)
+		require.NoError(t, err)
+		require.NotNil(t, policy.LastRotatedAt)
+		// a certificate expiring within the given days is not rotated again before the minimum interval
+		assert.WithinDuration(t, time.Now().Add(rotation.MinInterval), policy.NextRotationAt, time.Minute)
+
+		before, err = store.Get(orgID, passwordID)
+		require.NoError(t, err)
+
+		// secrets without a policy can be rotated as well
+		result, err = manager.RotateSecret(orgID, passwordID, "admin")
+		require.NoError(t, err)
+		assert.Empty(t, result.Installations)
+
+		after, err = store.Get(orgID, passwordID)
+		require.NoError(t, err)
+		assert.Len(t, after.Values[pkgSecret.Password], 20)
+		assert.NotEqual(t, before.Values[pkgSecret.Password], after.Values[pkgSecret.Password])
+		assert.Equal(t, "admin", after.Values[pkgSecret.Username])
+
+		_, err = manager.RotateSecret(orgID, genericID, "admin")
+		assert.True(t, errors.As(err, &rotation.ValidationError{}))
+	})
+}
@@ -0,0 +1,70 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rotationadapter
+
+import (
+	"context"
+	"encoding/json"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/cluster"
+	"github.com/banzaicloud/pipeline/internal/secret/rotation"
+)
+
+// ClusterManager returns clusters.
+type ClusterManager interface {
+	GetClusterByID(ctx context.Context, organizationID uint, clusterID uint) (cluster.CommonCluster, error)
+}
+
+// SecretInstaller installs rotated secrets into clusters the same way they were originally installed.
+type SecretInstaller struct {
+	clusters ClusterManager
+}
+
+// NewSecretInstaller returns a new SecretInstaller.
+func NewSecretInstaller(clusters ClusterManager) *SecretInstaller {
+	return &SecretInstaller{
+		clusters: clusters,
+	}
+}
+
+// InstallSecret installs a secret into a cluster again.
+func (i *SecretInstaller) InstallSecret(ctx context.Context, organizationID uint, secretName string, installation rotation.Installation) error {
+	c, err := i.clusters.GetClusterByID(ctx, organizationID, installation.ClusterID)
+	if err != nil {
+		return errors.WrapIf(err, "failed to get cluster")
+	}
+
+	request := cluster.InstallSecretRequest{
+		SourceSecretName: secretName,
+		Namespace:        installation.Namespace,
+		Update:           true,
+	}
+
+	if installation.Spec != "" {
+		if err := json.Unmarshal([]byte(installation.Spec), &request.Spec); err != nil {
+			return errors.WrapIf(err, "failed to decode secret installation spec")
+		}
+	}
+
+	if installation.Merged {
+		_, err = cluster.MergeSecret(c, installation.Name, request)
+	} else {
+		_, err = cluster.InstallSecret(c, installation.Name, request)
+	}
+
+	return err
+}
@@ -0,0 +1,171 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rotation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"emperror.dev/errors"
+	"go.uber.org/cadence/client"
+	"go.uber.org/cadence/workflow"
+)
+
+const (
+	// RotateSecretsWorkflowName periodically rotates the secrets with due rotation policies.
+	RotateSecretsWorkflowName = "rotate-secrets"
+
+	// RotateSecretWorkflowName rotates a single secret and installs it again into the clusters.
+	RotateSecretWorkflowName = "rotate-secret"
+
+	// ScheduledRotationUser is recorded as the updater of the secrets rotated by a policy.
+	ScheduledRotationUser = "secret-rotation"
+
+	taskList = "pipeline"
+)
+
+type RotateSecretWorkflowInput struct {
+	OrganizationID uint
+	SecretID       string
+	UpdatedBy      string
+}
+
+// RotateSecretsWorkflow rotates every secret whose rotation policy is due.
+// A failed rotation does not affect the others, it is retried after MinInterval.
+func RotateSecretsWorkflow(ctx workflow.Context) error {
+	logger := workflow.GetLogger(ctx).Sugar()
+
+	ao := workflow.ActivityOptions{
+		ScheduleToStartTimeout: 5 * time.Minute,
+		StartToCloseTimeout:    5 * time.Minute,
+		WaitForCancellation:    true,
+	}
+	cwo := workflow.ChildWorkflowOptions{
+		ExecutionStartToCloseTimeout: 30 * time.Minute,
+		TaskStartToCloseTimeout:      time.Minute,
+	}
+	ctx = workflow.WithChildOptions(workflow.WithActivityOptions(ctx, ao), cwo)
+
+	var rotations []DueRotation
+	if err := workflow.ExecuteActivity(ctx, ListDueRotationsActivityName).Get(ctx, &rotations); err != nil {
+		return err
+	}
+
+	futures := make([]workflow.Future, len(rotations))
+	for i, rotation := range rotations {
+		input := RotateSecretWorkflowInput{
+			OrganizationID: rotation.OrganizationID,
+			SecretID:       rotation.SecretID,
+			UpdatedBy:      ScheduledRotationUser,
+		}
+
+		childCtx := workflow.WithWorkflowID(ctx, rotateSecretWorkflowID(rotation.OrganizationID, rotation.SecretID))
+		futures[i] = workflow.ExecuteChildWorkflow(childCtx, RotateSecretWorkflowName, input)
+	}
+
+	for i, future := range futures {
+		if err := future.Get(ctx, nil); err != nil {
+			logger.Warnw(
+				"failed to rotate secret",
+				"organization", rotations[i].OrganizationID,
+				"secret", rotations[i].SecretID,
+				"error", err.Error(),
+			)
+		}
+	}
+
+	return nil
+}
+
+// RotateSecretWorkflow regenerates the values of a secret,
+// then updates the Kubernetes secrets which were installed from it.
+func RotateSecretWorkflow(ctx workflow.Context, input RotateSecretWorkflowInput) error {
+	ao := workflow.ActivityOptions{
+		ScheduleToStartTimeout: 5 * time.Minute,
+		StartToCloseTimeout:    5 * time.Minute,
+		WaitForCancellation:    true,
+	}
+	ctx = workflow.WithActivityOptions(ctx, ao)
+
+	var result RotationResult
+	{
+		activityInput := RotateSecretActivityInput{
+			OrganizationID: input.OrganizationID,
+			SecretID:       input.SecretID,
+			UpdatedBy:      input.UpdatedBy,
+		}
+
+		if err := workflow.ExecuteActivity(ctx, RotateSecretActivityName, activityInput).Get(ctx, &result); err != nil {
+			return err
+		}
+	}
+
+	futures := make([]workflow.Future, len(result.Installations))
+	for i, installation := range result.Installations {
+		activityInput := InstallSecretActivityInput{
+			OrganizationID: input.OrganizationID,
+			SecretName:     result.SecretName,
+			Installation:   installation,
+		}
+
+		futures[i] = workflow.ExecuteActivity(ctx, InstallSecretActivityName, activityInput)
+	}
+
+	var errs []error
+	for _, future := range futures {
+		if err := future.Get(ctx, nil); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Combine(errs...)
+}
+
+// ScheduleRotation starts the periodic rotation of the secrets.
+func ScheduleRotation(workflowClient client.Client, checkInterval time.Duration) error {
+	workflowOptions := client.StartWorkflowOptions{
+		ID:                           RotateSecretsWorkflowName,
+		WorkflowIDReusePolicy:        client.WorkflowIDReusePolicyAllowDuplicate,
+		TaskList:                     taskList,
+		ExecutionStartToCloseTimeout: checkInterval,
+		CronSchedule:                 "@every " + checkInterval.String(),
+	}
+
+	_, err := workflowClient.StartWorkflow(context.Background(), workflowOptions, RotateSecretsWorkflowName)
+
+	return err
+}
+
+// StartRotation starts the rotation of a secret, regardless of its rotation policy.
+func StartRotation(ctx context.Context, workflowClient client.Client, input RotateSecretWorkflowInput) (string, error) {
+	workflowOptions := client.StartWorkflowOptions{
+		ID:                           rotateSecretWorkflowID(input.OrganizationID, input.SecretID),
+		WorkflowIDReusePolicy:        client.WorkflowIDReusePolicyAllowDuplicate,
+		TaskList:                     taskList,
+		ExecutionStartToCloseTimeout: 30 * time.Minute,
+	}
+
+	execution, err := workflowClient.StartWorkflow(ctx, workflowOptions, RotateSecretWorkflowName, input)
+	if err != nil {
+		return "", err
+	}
+
+	return execution.ID, nil
+}
+
+func rotateSecretWorkflowID(organizationID uint, secretID string) string {
+	return fmt.Sprintf("rotate-secret-%d-%s", organizationID, secretID)
+}
@@ -0,0 +1,376 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// Usage types
+const (
+	UsageTypeCluster           = "cluster"
+	UsageTypeObjectStoreBucket = "objectStoreBucket"
+	UsageTypeBackupBucket      = "backupBucket"
+	UsageTypeClusterFeature    = "clusterFeature"
+	UsageTypeKubernetesSecret  = "kubernetesSecret"
+)
+
+// Usage describes a resource referencing a secret.
+type Usage struct {
+	Type        string `json:"type"`
+	ID          uint   `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Cloud       string `json:"cloud,omitempty"`
+	Field       string `json:"field,omitempty"`
+	ClusterID   uint   `json:"clusterId,omitempty"`
+	ClusterName string `json:"clusterName,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+}
+
+// UsageFinder finds the resources referencing a secret.
+// The tables are queried directly in order to avoid depending on every resource package.
+type UsageFinder struct {
+	db *gorm.DB
+}
+
+// NewUsageFinder returns a new UsageFinder.
+func NewUsageFinder(db *gorm.DB) *UsageFinder {
+	return &UsageFinder{
+		db: db,
+	}
+}
+
+// FindUsages returns the resources of an organization referencing a secret.
+func (f *UsageFinder) FindUsages(organizationID uint, secretID string) ([]Usage, error) {
+	usages := []Usage{}
+
+	for _, find := range []func(uint, string) ([]Usage, error){
+		f.findClusters,
+		f.findObjectStoreBuckets,
+		f.findBackupBuckets,
+		f.findClusterFeatures,
+		f.findKubernetesSecrets,
+	} {
+		u, err := find(organizationID, secretID)
+		if err != nil {
+			return nil, err
+		}
+
+		usages = append(usages, u...)
+	}
+
+	return usages, nil
+}
+
+func (f *UsageFinder) findClusters(organizationID uint, secretID string) ([]Usage, error) {
+	var clusters []struct {
+		ID          uint
+		Name        string
+		Cloud       string
+		SecretID    string
+		SshSecretID string
+	}
+
+	err := f.db.Table("clusters").
+		Select("id, name, cloud, secret_id, ssh_secret_id").
+		Where("organization_id = ? AND deleted_at IS NULL", organizationID).
+		Where("secret_id = ? OR ssh_secret_id = ?", secretID, secretID).
+		Order("id").
+		Scan(&clusters).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find clusters using the secret")
+	}
+
+	var usages []Usage
+	for _, c := range clusters {
+		usage := Usage{
+			Type:        UsageTypeCluster,
+			ID:          c.ID,
+			Name:        c.Name,
+			Cloud:       c.Cloud,
+			ClusterID:   c.ID,
+			ClusterName: c.Name,
+		}
+
+		if c.SecretID == secretID {
+			usage.Field = "secretId"
+			usages = append(usages, usage)
+		}
+
+		if c.SshSecretID == secretID {
+			usage.Field = "sshSecretId"
+			usages = append(usages, usage)
+		}
+	}
+
+	return usages, nil
+}
+
+func (f *UsageFinder) findObjectStoreBuckets(organizationID uint, secretID string) ([]Usage, error) {
+	references := []struct {
+		cloud                string
+		table                string
+		organizationIDColumn string
+		secretColumn         string
+		field                string
+	}{
+		{"alibaba", "alibaba_buckets", "org_id", "secret_ref", "secretId"},
+		{"amazon", "amazon_buckets", "organization_id", "secret_ref", "secretId"},
+		{"azure", "azure_buckets", "organization_id", "secret_ref", "secretId"},
+		{"azure", "azure_buckets", "organization_id", "access_secret_ref", "accessSecretId"},
+		{"google", "google_buckets", "organization_id", "secret_ref", "secretId"},
+		{"oracle", "oracle_buckets", "org_id", "secret_ref", "secretId"},
+	}
+
+	var usages []Usage
+	for _, ref := range references {
+		var buckets []struct {
+			ID   uint
+			Name string
+		}
+
+		err := f.db.Table(ref.table).
+			Select("id, name").
+			Where(ref.organizationIDColumn+" = ? AND "+ref.secretColumn+" = ?", organizationID, secretID).
+			Order("id").
+			Scan(&buckets).Error
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find %s buckets using the secret", ref.cloud)
+		}
+
+		for _, b := range buckets {
+			usages = append(usages, Usage{
+				Type:  UsageTypeObjectStoreBucket,
+				ID:    b.ID,
+				Name:  b.Name,
+				Cloud: ref.cloud,
+				Field: ref.field,
+			})
+		}
+	}
+
+	return usages, nil
+}
+
+func (f *UsageFinder) findBackupBuckets(organizationID uint, secretID string) ([]Usage, error) {
+	var buckets []struct {
+		ID         uint
+		BucketName string
+		Cloud      string
+	}
+
+	err := f.db.Table("ark_backup_buckets").
+		Select("id, bucket_name, cloud").
+		Where("organization_id = ? AND secret_id = ? AND deleted_at IS NULL", organizationID, secretID).
+		Order("id").
+		Scan(&buckets).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find backup buckets using the secret")
+	}
+
+	var usages []Usage
+	for _, b := range buckets {
+		usages = append(usages, Usage{
+			Type:  UsageTypeBackupBucket,
+			ID:    b.ID,
+			Name:  b.BucketName,
+			Cloud: b.Cloud,
+			Field: "secretId",
+		})
+	}
+
+	return usages, nil
+}
+
+func (f *UsageFinder) findClusterFeatures(organizationID uint, secretID string) ([]Usage, error) {
+	var features []struct {
+		ID          uint
+		Name        string
+		ClusterID   uint
+		ClusterName string
+		Spec        string
+	}
+
+	err := f.db.Table("cluster_features").
+		Select("cluster_features.id, cluster_features.name, cluster_features.cluster_id, clusters.name AS cluster_name, cluster_features.spec").
+		Joins("JOIN clusters ON clusters.id = cluster_features.cluster_id").
+		Where("clusters.organization_id = ? AND clusters.deleted_at IS NULL", organizationID).
+		Where("cluster_features.name IN (?)", []string{"dns", "logging", "monitoring"}).
+		Order("cluster_features.id").
+		Scan(&features).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find cluster features using the secret")
+	}
+
+	var usages []Usage
+	for _, feature := range features {
+		for _, ref := range featureSecretRefs(feature.Name, []byte(feature.Spec)) {
+			if ref.secretID != secretID {
+				continue
+			}
+
+			usages = append(usages, Usage{
+				Type:        UsageTypeClusterFeature,
+				ID:          feature.ID,
+				Name:        feature.Name,
+				Field:       ref.field,
+				ClusterID:   feature.ClusterID,
+				ClusterName: feature.ClusterName,
+			})
+		}
+	}
+
+	return usages, nil
+}
+
+// featureSecretRef is a secret referenced by a field of a cluster feature spec.
+type featureSecretRef struct {
+	field    string
+	secretID string
+}
+
+// featureSecretRefs returns the secrets referenced by a cluster feature spec.
+// A malformed spec cannot reference any secret.
+func featureSecretRefs(featureName string, rawSpec []byte) []featureSecretRef {
+	var refs []featureSecretRef
+	addRef := func(field string, secretID string) {
+		if secretID != "" {
+			refs = append(refs, featureSecretRef{field: field, secretID: secretID})
+		}
+	}
+
+	switch featureName {
+	case "dns":
+		var spec struct {
+			CustomDNS struct {
+				Provider struct {
+					SecretID string `json:"secret"`
+				} `json:"provider"`
+			} `json:"customDns"`
+		}
+
+		if err := json.Unmarshal(rawSpec, &spec); err != nil {
+			return nil
+		}
+
+		addRef("customDns.provider.secret", spec.CustomDNS.Provider.SecretID)
+
+	case "logging":
+		type secretRef struct {
+			SecretID string `json:"secretId"`
+		}
+
+		var spec struct {
+			Outputs []struct {
+				ObjectStore   *secretRef `json:"objectStore"`
+				Elasticsearch *secretRef `json:"elasticsearch"`
+				Loki          *secretRef `json:"loki"`
+			} `json:"outputs"`
+			TLS secretRef `json:"tls"`
+		}
+
+		if err := json.Unmarshal(rawSpec, &spec); err != nil {
+			return nil
+		}
+
+		for i, output := range spec.Outputs {
+			if output.ObjectStore != nil {
+				addRef(fmt.Sprintf("outputs[%d].objectStore.secretId", i), output.ObjectStore.SecretID)
+			}
+			if output.Elasticsearch != nil {
+				addRef(fmt.Sprintf("outputs[%d].elasticsearch.secretId", i), output.Elasticsearch.SecretID)
+			}
+			if output.Loki != nil {
+				addRef(fmt.Sprintf("outputs[%d].loki.secretId", i), output.Loki.SecretID)
+			}
+		}
+
+		addRef("tls.secretId", spec.TLS.SecretID)
+
+	case "monitoring":
+		type secretRef struct {
+			SecretID string `json:"secretId"`
+		}
+
+		var spec struct {
+			Receivers []struct {
+				Slack     *secretRef `json:"slack"`
+				PagerDuty *secretRef `json:"pagerDuty"`
+				Email     *secretRef `json:"email"`
+				Webhook   *secretRef `json:"webhook"`
+			} `json:"receivers"`
+		}
+
+		if err := json.Unmarshal(rawSpec, &spec); err != nil {
+			return nil
+		}
+
+		for i, receiver := range spec.Receivers {
+			if receiver.Slack != nil {
+				addRef(fmt.Sprintf("receivers[%d].slack.secretId", i), receiver.Slack.SecretID)
+			}
+			if receiver.PagerDuty != nil {
+				addRef(fmt.Sprintf("receivers[%d].pagerDuty.secretId", i), receiver.PagerDuty.SecretID)
+			}
+			if receiver.Email != nil {
+				addRef(fmt.Sprintf("receivers[%d].email.secretId", i), receiver.Email.SecretID)
+			}
+			if receiver.Webhook != nil {
+				addRef(fmt.Sprintf("receivers[%d].webhook.secretId", i), receiver.Webhook.SecretID)
+			}
+		}
+	}
+
+	return refs
+}
+
+func (f *UsageFinder) findKubernetesSecrets(organizationID uint, secretID string) ([]Usage, error) {
+	var secrets []struct {
+		ID          uint
+		Name        string
+		Namespace   string
+		ClusterID   uint
+		ClusterName string
+	}
+
+	err := f.db.Table(InstalledSecretModel{}.TableName()).
+		Select("installed_secrets.id, installed_secrets.name, installed_secrets.namespace, installed_secrets.cluster_id, clusters.name AS cluster_name").
+		Joins("JOIN clusters ON clusters.id = installed_secrets.cluster_id").
+		Where("installed_secrets.organization_id = ? AND installed_secrets.secret_id = ?", organizationID, secretID).
+		Where("clusters.deleted_at IS NULL").
+		Order("installed_secrets.id").
+		Scan(&secrets).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find kubernetes secrets installed from the secret")
+	}
+
+	var usages []Usage
+	for _, s := range secrets {
+		usages = append(usages, Usage{
+			Type:        UsageTypeKubernetesSecret,
+			ID:          s.ID,
+			Name:        s.Name,
+			Namespace:   s.Namespace,
+			ClusterID:   s.ClusterID,
+			ClusterName: s.ClusterName,
+		})
+	}
+
+	return usages, nil
+}
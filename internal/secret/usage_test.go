@@ -0,0 +1,103 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret_test
+
+import (
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/secret"
+)
+
+func TestUsageFinder_FindUsages(t *testing.T) {
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	db.DB().SetMaxOpenConns(1)
+
+	logger, _ := test.NewNullLogger()
+	require.NoError(t, secret.Migrate(db, logger))
+
+	// only the columns used by the usage finder
+	for _, statement := range []string{
+		"CREATE TABLE clusters (id integer primary key, name varchar(255), cloud varchar(255), organization_id integer, secret_id varchar(255), ssh_secret_id varchar(255), deleted_at datetime)",
+		"CREATE TABLE alibaba_buckets (id integer primary key, org_id integer, name varchar(255), secret_ref varchar(255))",
+		"CREATE TABLE amazon_buckets (id integer primary key, organization_id integer, name varchar(255), secret_ref varchar(255))",
+		"CREATE TABLE azure_buckets (id integer primary key, organization_id integer, name varchar(255), secret_ref varchar(255), access_secret_ref varchar(255))",
+		"CREATE TABLE google_buckets (id integer primary key, organization_id integer, name varchar(255), secret_ref varchar(255))",
+		"CREATE TABLE oracle_buckets (id integer primary key, org_id integer, name varchar(255), secret_ref varchar(255))",
+		"CREATE TABLE ark_backup_buckets (id integer primary key, organization_id integer, cloud varchar(255), bucket_name varchar(255), secret_id varchar(255), deleted_at datetime)",
+		"CREATE TABLE cluster_features (id integer primary key, cluster_id integer, name varchar(255), spec text)",
+
+		"INSERT INTO clusters VALUES (1, 'cluster', 'amazon', 1, 'secret', 'secret', NULL)",
+		"INSERT INTO clusters VALUES (2, 'other', 'amazon', 1, 'other', 'other', NULL)",
+		"INSERT INTO clusters VALUES (3, 'deleted', 'amazon', 1, 'secret', '', CURRENT_TIMESTAMP)",
+		"INSERT INTO clusters VALUES (4, 'other-org', 'amazon', 2, 'secret', '', NULL)",
+		"INSERT INTO azure_buckets VALUES (1, 1, 'azure-bucket', 'other', 'secret')",
+		"INSERT INTO oracle_buckets VALUES (1, 1, 'oracle-bucket', 'secret')",
+		"INSERT INTO ark_backup_buckets VALUES (1, 1, 'google', 'backups', 'secret', NULL)",
+		"INSERT INTO ark_backup_buckets VALUES (2, 1, 'google', 'deleted-backups', 'secret', CURRENT_TIMESTAMP)",
+		`INSERT INTO cluster_features VALUES (1, 2, 'dns', '{"customDns":{"provider":{"name":"route53","secret":"secret"}}}')`,
+		`INSERT INTO cluster_features VALUES (2, 1, 'dns', '{"customDns":{"provider":{"name":"route53","secret":"other"}}}')`,
+		`INSERT INTO cluster_features VALUES (3, 1, 'logging', '{"outputs":[{"name":"s3","objectStore":{"provider":"amazon","secretId":"other"}},{"name":"es","elasticsearch":{"host":"es","secretId":"secret"}},{"name":"loki","loki":{"url":"http://loki","secretId":"secret"}}],"tls":{"enabled":true,"secretId":"secret"}}')`,
+		`INSERT INTO cluster_features VALUES (4, 2, 'monitoring', '{"receivers":[{"name":"slack","slack":{"channel":"alerts","secretId":"secret"}},{"name":"pd","pagerDuty":{"secretId":"other"}},{"name":"mail","email":{"to":"ops@example.com","secretId":"secret"}},{"name":"hook","webhook":{"url":"http://hook","secretId":"secret"}}]}')`,
+	} {
+		require.NoError(t, db.Exec(statement).Error, statement)
+	}
+
+	require.NoError(t, secret.RecordInstalledSecret(db, secret.InstalledSecretModel{OrganizationID: 1, SecretID: "other", ClusterID: 2, Namespace: "default", Name: "installed"}))
+	// installing another secret under the same name replaces the record
+	require.NoError(t, secret.RecordInstalledSecret(db, secret.InstalledSecretModel{OrganizationID: 1, SecretID: "secret", ClusterID: 2, Namespace: "default", Name: "installed", Merged: true}))
+	require.NoError(t, secret.RecordInstalledSecret(db, secret.InstalledSecretModel{OrganizationID: 1, SecretID: "secret", ClusterID: 3, Namespace: "default", Name: "on-deleted-cluster"}))
+
+	usages, err := secret.NewUsageFinder(db).FindUsages(1, "secret")
+	require.NoError(t, err)
+
+	assert.Equal(
+		t,
+		[]secret.Usage{
+			{Type: secret.UsageTypeCluster, ID: 1, Name: "cluster", Cloud: "amazon", Field: "secretId", ClusterID: 1, ClusterName: "cluster"},
+			{Type: secret.UsageTypeCluster, ID: 1, Name: "cluster", Cloud: "amazon", Field: "sshSecretId", ClusterID: 1, ClusterName: "cluster"},
+			{Type: secret.UsageTypeObjectStoreBucket, ID: 1, Name: "azure-bucket", Cloud: "azure", Field: "accessSecretId"},
+			{Type: secret.UsageTypeObjectStoreBucket, ID: 1, Name: "oracle-bucket", Cloud: "oracle", Field: "secretId"},
+			{Type: secret.UsageTypeBackupBucket, ID: 1, Name: "backups", Cloud: "google", Field: "secretId"},
+			{Type: secret.UsageTypeClusterFeature, ID: 1, Name: "dns", Field: "customDns.provider.secret", ClusterID: 2, ClusterName: "other"},
+			{Type: secret.UsageTypeClusterFeature, ID: 3, Name: "logging", Field: "outputs[1].elasticsearch.secretId", ClusterID: 1, ClusterName: "cluster"},
+			{Type: secret.UsageTypeClusterFeature, ID: 3, Name: "logging", Field: "outputs[2].loki.secretId", ClusterID: 1, ClusterName: "cluster"},
+			{Type: secret.UsageTypeClusterFeature, ID: 3, Name: "logging", Field: "tls.secretId", ClusterID: 1, ClusterName: "cluster"},
+			{Type: secret.UsageTypeClusterFeature, ID: 4, Name: "monitoring", Field: "receivers[0].slack.secretId", ClusterID: 2, ClusterName: "other"},
+			{Type: secret.UsageTypeClusterFeature, ID: 4, Name: "monitoring", Field: "receivers[2].email.secretId", ClusterID: 2, ClusterName: "other"},
+			{Type: secret.UsageTypeClusterFeature, ID: 4, Name: "monitoring", Field: "receivers[3].webhook.secretId", ClusterID: 2, ClusterName: "other"},
+			{Type: secret.UsageTypeKubernetesSecret, ID: 1, Name: "installed", Namespace: "default", ClusterID: 2, ClusterName: "other"},
+		},
+		usages,
+	)
+
+	installed, err := secret.ListInstalledSecrets(db, 1, "secret")
+	require.NoError(t, err)
+	require.Len(t, installed, 1)
+	assert.Equal(t, "installed", installed[0].Name)
+	assert.True(t, installed[0].Merged)
+
+	usages, err = secret.NewUsageFinder(db).FindUsages(1, "unused")
+	require.NoError(t, err)
+	assert.Empty(t, usages)
+}
@@ -0,0 +1,314 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+
+	"emperror.dev/emperror"
+	"emperror.dev/errors"
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// SignatureHeader contains the HMAC-SHA256 signature of the payload.
+	SignatureHeader = "X-Pipeline-Signature"
+
+	// EventHeader contains the type of the event.
+	EventHeader = "X-Pipeline-Event"
+
+	// DeliveryHeader contains the unique ID of the event.
+	DeliveryHeader = "X-Pipeline-Delivery"
+)
+
+// Event is the payload sent to the subscribers.
+type Event struct {
+	ID             string      `json:"id"`
+	Type           string      `json:"type"`
+	Time           time.Time   `json:"time"`
+	OrganizationID uint        `json:"organizationId"`
+	Data           interface{} `json:"data"`
+}
+
+// NewEvent returns a new event with a unique ID.
+func NewEvent(organizationID uint, eventType string, data interface{}) Event {
+	return Event{
+		ID:             uuid.Must(uuid.NewV4()).String(),
+		Type:           eventType,
+		Time:           time.Now().UTC(),
+		OrganizationID: organizationID,
+		Data:           data,
+	}
+}
+
+// DispatcherConfig contains the delivery settings of the dispatcher.
+type DispatcherConfig struct {
+	// MaxAttempts is the number of delivery attempts (including the first one).
+	MaxAttempts int
+
+	// RetryInterval is the delay before the first retry, doubled after each failed attempt.
+	RetryInterval time.Duration
+
+	Timeout time.Duration
+}
+
+// Dispatcher delivers events to the subscriptions of an organization.
+type Dispatcher struct {
+	db      *gorm.DB
+	secrets SecretStore
+	config  DispatcherConfig
+	client  *http.Client
+
+	// isAllowedIP decides whether webhooks can be delivered to an address
+	isAllowedIP func(ip net.IP) bool
+
+	logger       logrus.FieldLogger
+	errorHandler emperror.Handler
+}
+
+// NewDispatcher returns a new Dispatcher instance.
+func NewDispatcher(
+	db *gorm.DB,
+	secrets SecretStore,
+	config DispatcherConfig,
+	logger logrus.FieldLogger,
+	errorHandler emperror.Handler,
+) *Dispatcher {
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 1
+	}
+
+	d := &Dispatcher{
+		db:          db,
+		secrets:     secrets,
+		config:      config,
+		isAllowedIP: isPublicIP,
+
+		logger:       logger,
+		errorHandler: errorHandler,
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		// the address is checked after name resolution, so that a host cannot be rebound to an internal address
+		// after the subscription was validated
+		Control: d.checkAddress,
+	}
+
+	d.client = &http.Client{
+		Timeout: config.Timeout,
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+		},
+		// redirects are not followed, they could point to internal addresses
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	return d
+}
+
+// checkAddress refuses connecting to non-public addresses.
+func (d *Dispatcher) checkAddress(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return errors.WrapIf(err, "invalid webhook address")
+	}
+
+	if ip := net.ParseIP(host); ip == nil || !d.isAllowedIP(ip) {
+		return errors.NewWithDetails("webhook host resolves to a non-public address", "address", host)
+	}
+
+	return nil
+}
+
+// Dispatch delivers an event to every matching subscription of the organization.
+// It returns when all deliveries have either succeeded or run out of attempts.
+func (d *Dispatcher) Dispatch(event Event) {
+	var subscriptions []Subscription
+
+	err := d.db.Where(&Subscription{OrganizationID: event.OrganizationID}).Find(&subscriptions).Error
+	if err != nil {
+		d.errorHandler.Handle(errors.WrapIfWithDetails(
+			err, "failed to list webhook subscriptions",
+			"organizationId", event.OrganizationID,
+		))
+
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		d.errorHandler.Handle(errors.WrapIf(err, "failed to marshal webhook event"))
+
+		return
+	}
+
+	var wg sync.WaitGroup
+
+	for _, subscription := range subscriptions {
+		if !subscription.Accepts(event.Type) {
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(subscription Subscription) {
+			defer wg.Done()
+
+			d.deliver(subscription, event, payload)
+		}(subscription)
+	}
+
+	wg.Wait()
+}
+
+func (d *Dispatcher) deliver(subscription Subscription, event Event, payload []byte) {
+	logger := d.logger.WithFields(logrus.Fields{
+		"organization": subscription.OrganizationID,
+		"subscription": subscription.ID,
+		"event":        event.Type,
+		"eventId":      event.ID,
+	})
+
+	signingKey, err := d.getSigningKey(subscription)
+	if err != nil {
+		d.errorHandler.Handle(errors.WithDetails(err, "subscriptionId", subscription.ID))
+
+		return
+	}
+
+	delay := d.config.RetryInterval
+
+	for attempt := 1; attempt <= d.config.MaxAttempts; attempt++ {
+		delivery := Delivery{
+			SubscriptionID: subscription.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        string(payload),
+			Attempt:        attempt,
+		}
+
+		start := time.Now()
+		statusCode, err := d.send(subscription.URL, event, payload, signingKey)
+		delivery.Duration = time.Since(start).Nanoseconds() / int64(time.Millisecond)
+		delivery.StatusCode = statusCode
+
+		retry := false
+		if err != nil {
+			delivery.Error = err.Error()
+			retry = statusCode == 0 || isRetryableStatus(statusCode)
+		} else {
+			delivery.Succeeded = true
+		}
+
+		if err := d.db.Create(&delivery).Error; err != nil {
+			d.errorHandler.Handle(errors.WrapIfWithDetails(
+				err, "failed to save webhook delivery",
+				"subscriptionId", subscription.ID,
+			))
+		}
+
+		if delivery.Succeeded {
+			logger.WithField("attempt", attempt).Debug("webhook delivered")
+
+			return
+		}
+
+		logger.WithField("attempt", attempt).Warnf("webhook delivery failed: %s", delivery.Error)
+
+		if !retry || attempt == d.config.MaxAttempts {
+			return
+		}
+
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+func (d *Dispatcher) getSigningKey(subscription Subscription) (string, error) {
+	secretItem, err := d.secrets.Get(subscription.OrganizationID, subscription.SecretID)
+	if err != nil {
+		return "", errors.WrapIf(err, "failed to get webhook signing key")
+	}
+
+	return secretItem.Values[signingKeySecretKey], nil
+}
+
+func (d *Dispatcher) send(url string, event Event, payload []byte, signingKey string) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, errors.WrapIf(err, "failed to create webhook request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Banzai-Cloud-Pipeline-Webhook")
+	req.Header.Set(EventHeader, event.Type)
+	req.Header.Set(DeliveryHeader, event.ID)
+	req.Header.Set(SignatureHeader, Sign(payload, signingKey))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, errors.WrapIf(err, "failed to send webhook request")
+	}
+	defer resp.Body.Close()
+
+	// Drain the body so that the connection can be reused
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, errors.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Sign returns the value of the signature header for a payload.
+func Sign(payload []byte, signingKey string) string {
+	mac := hmac.New(sha256.New, []byte(signingKey))
+	_, _ = mac.Write(payload)
+
+	return fmt.Sprintf("sha256=%s", hex.EncodeToString(mac.Sum(nil)))
+}
+
+// isRetryableStatus decides whether a failed delivery should be retried:
+// client errors are considered permanent, except for timeouts and rate limiting.
+func isRetryableStatus(statusCode int) bool {
+	if statusCode == http.StatusRequestTimeout || statusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	return statusCode >= 500
+}
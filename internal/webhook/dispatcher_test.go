@@ -0,0 +1,358 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"emperror.dev/emperror"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+type inmemorySecretStore struct {
+	mu      sync.Mutex
+	secrets map[string]map[string]string
+}
+
+func newInmemorySecretStore() *inmemorySecretStore {
+	return &inmemorySecretStore{
+		secrets: make(map[string]map[string]string),
+	}
+}
+
+func (s *inmemorySecretStore) Store(organizationID uint, request *secret.CreateSecretRequest) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := fmt.Sprintf("%d-%s", organizationID, request.Name)
+	s.secrets[id] = request.Values
+
+	return id, nil
+}
+
+func (s *inmemorySecretStore) Get(organizationID uint, secretID string) (*secret.SecretItemResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values, ok := s.secrets[secretID]
+	if !ok {
+		return nil, secret.ErrSecretNotExists
+	}
+
+	return &secret.SecretItemResponse{ID: secretID, Values: values}, nil
+}
+
+func (s *inmemorySecretStore) Delete(organizationID uint, secretID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.secrets, secretID)
+
+	return nil
+}
+
+func setUpDatabase(t *testing.T) *gorm.DB {
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+
+	db.DB().SetMaxOpenConns(1)
+
+	require.NoError(t, db.AutoMigrate(&Subscription{}, &Delivery{}).Error)
+
+	return db
+}
+
+// newTestManager returns a manager resolving every host to a public address
+func newTestManager(db *gorm.DB, secrets SecretStore) *Manager {
+	manager := NewManager(db, secrets)
+	manager.lookupIP = func(host string) ([]net.IP, error) {
+		return []net.IP{net.ParseIP("93.184.216.34")}, nil
+	}
+
+	return manager
+}
+
+// newTestDispatcher returns a dispatcher delivering to every address (including the local test servers)
+func newTestDispatcher(db *gorm.DB, secrets SecretStore, maxAttempts int) *Dispatcher {
+	logger, _ := logrustest.NewNullLogger()
+	dispatcher := NewDispatcher(
+		db,
+		secrets,
+		DispatcherConfig{MaxAttempts: maxAttempts, RetryInterval: time.Millisecond, Timeout: time.Second},
+		logger,
+		emperror.NewNoopHandler(),
+	)
+	dispatcher.isAllowedIP = func(ip net.IP) bool {
+		return true
+	}
+
+	return dispatcher
+}
+
+func TestManager_CreateSubscription_NonPublicHost(t *testing.T) {
+	db := setUpDatabase(t)
+	defer db.Close()
+
+	manager := NewManager(db, newInmemorySecretStore())
+
+	for _, url := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://10.0.0.1/hook",
+		"http://192.168.1.1/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook",
+	} {
+		_, _, err := manager.CreateSubscription(CreateSubscriptionRequest{OrganizationID: 1, URL: url})
+		assert.IsType(t, ValidationError{}, err, url)
+	}
+}
+
+func TestManager(t *testing.T) {
+	db := setUpDatabase(t)
+	defer db.Close()
+
+	secrets := newInmemorySecretStore()
+	manager := newTestManager(db, secrets)
+
+	_, _, err := manager.CreateSubscription(CreateSubscriptionRequest{OrganizationID: 1, URL: "ftp://example.com"})
+	assert.IsType(t, ValidationError{}, err)
+
+	_, _, err = manager.CreateSubscription(CreateSubscriptionRequest{OrganizationID: 1, URL: "https://example.com", Events: []string{"cluster.exploded"}})
+	assert.IsType(t, ValidationError{}, err)
+
+	subscription, signingKey, err := manager.CreateSubscription(CreateSubscriptionRequest{
+		OrganizationID: 1,
+		UserID:         2,
+		URL:            "https://example.com/hook",
+		Events:         []string{EventClusterCreated, EventClusterDeleted},
+	})
+	require.NoError(t, err)
+	assert.Len(t, signingKey, signingKeyLength)
+	assert.Equal(t, []string{EventClusterCreated, EventClusterDeleted}, subscription.EventTypes())
+	assert.Len(t, secrets.secrets, 1)
+
+	subscriptions, err := manager.ListSubscriptions(1)
+	require.NoError(t, err)
+	assert.Len(t, subscriptions, 1)
+
+	subscriptions, err = manager.ListSubscriptions(2)
+	require.NoError(t, err)
+	assert.Len(t, subscriptions, 0)
+
+	_, err = manager.GetSubscription(2, subscription.ID)
+	assert.IsType(t, SubscriptionNotFoundError{}, err)
+
+	require.NoError(t, manager.DeleteSubscription(1, subscription.ID))
+	assert.Len(t, secrets.secrets, 0)
+
+	_, err = manager.GetSubscription(1, subscription.ID)
+	assert.IsType(t, SubscriptionNotFoundError{}, err)
+}
+
+func TestSubscription_Accepts(t *testing.T) {
+	assert.True(t, Subscription{}.Accepts(EventClusterFailed))
+	assert.True(t, Subscription{Events: "cluster.created,cluster.failed"}.Accepts(EventClusterFailed))
+	assert.False(t, Subscription{Events: "cluster.created"}.Accepts(EventClusterFailed))
+}
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	db := setUpDatabase(t)
+	defer db.Close()
+
+	var (
+		mu       sync.Mutex
+		requests int
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+
+		mu.Lock()
+		requests++
+		attempt := requests
+		mu.Unlock()
+
+		if r.Header.Get(SignatureHeader) != Sign(body, "key") {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		// fail the first attempt
+		if attempt == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	secrets := newInmemorySecretStore()
+	manager := newTestManager(db, secrets)
+
+	subscription, _, err := manager.CreateSubscription(CreateSubscriptionRequest{
+		OrganizationID: 1,
+		URL:            server.URL,
+		Events:         []string{EventClusterFailed},
+		SigningKey:     "key",
+	})
+	require.NoError(t, err)
+
+	dispatcher := newTestDispatcher(db, secrets, 3)
+
+	// filtered out
+	dispatcher.Dispatch(NewEvent(1, EventClusterCreated, ClusterEventData{ClusterName: "test"}))
+	// other organization
+	dispatcher.Dispatch(NewEvent(2, EventClusterFailed, ClusterEventData{ClusterName: "test"}))
+
+	event := NewEvent(1, EventClusterFailed, ClusterEventData{ClusterID: 1, ClusterName: "test"})
+	dispatcher.Dispatch(event)
+
+	assert.Equal(t, 2, requests)
+
+	deliveries, err := manager.ListDeliveries(1, subscription.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+
+	assert.Equal(t, 2, deliveries[0].Attempt)
+	assert.True(t, deliveries[0].Succeeded)
+	assert.Equal(t, http.StatusOK, deliveries[0].StatusCode)
+
+	assert.Equal(t, 1, deliveries[1].Attempt)
+	assert.False(t, deliveries[1].Succeeded)
+	assert.Equal(t, http.StatusServiceUnavailable, deliveries[1].StatusCode)
+
+	var payload Event
+	require.NoError(t, json.Unmarshal([]byte(deliveries[0].Payload), &payload))
+	assert.Equal(t, event.ID, payload.ID)
+	assert.Equal(t, EventClusterFailed, payload.Type)
+}
+
+func TestDispatcher_Dispatch_PermanentFailure(t *testing.T) {
+	db := setUpDatabase(t)
+	defer db.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer server.Close()
+
+	secrets := newInmemorySecretStore()
+	manager := newTestManager(db, secrets)
+
+	subscription, _, err := manager.CreateSubscription(CreateSubscriptionRequest{OrganizationID: 1, URL: server.URL})
+	require.NoError(t, err)
+
+	dispatcher := newTestDispatcher(db, secrets, 3)
+
+	dispatcher.Dispatch(NewEvent(1, EventClusterDeleted, ClusterEventData{ClusterName: "test"}))
+
+	deliveries, err := manager.ListDeliveries(1, subscription.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, http.StatusGone, deliveries[0].StatusCode)
+}
+
+func TestDispatcher_Dispatch_Redirect(t *testing.T) {
+	db := setUpDatabase(t)
+	defer db.Close()
+
+	var redirected int
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusFound)
+	}))
+	defer server.Close()
+
+	secrets := newInmemorySecretStore()
+	manager := newTestManager(db, secrets)
+
+	subscription, _, err := manager.CreateSubscription(CreateSubscriptionRequest{OrganizationID: 1, URL: server.URL})
+	require.NoError(t, err)
+
+	dispatcher := newTestDispatcher(db, secrets, 3)
+
+	dispatcher.Dispatch(NewEvent(1, EventClusterDeleted, ClusterEventData{ClusterName: "test"}))
+
+	assert.Equal(t, 0, redirected)
+
+	deliveries, err := manager.ListDeliveries(1, subscription.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.False(t, deliveries[0].Succeeded)
+	assert.Equal(t, http.StatusFound, deliveries[0].StatusCode)
+}
+
+func TestDispatcher_Dispatch_NonPublicAddress(t *testing.T) {
+	db := setUpDatabase(t)
+	defer db.Close()
+
+	var requests int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	secrets := newInmemorySecretStore()
+	// the host resolved to a public address when the subscription was created
+	manager := newTestManager(db, secrets)
+
+	subscription, _, err := manager.CreateSubscription(CreateSubscriptionRequest{OrganizationID: 1, URL: server.URL})
+	require.NoError(t, err)
+
+	logger, _ := logrustest.NewNullLogger()
+	dispatcher := NewDispatcher(
+		db,
+		secrets,
+		DispatcherConfig{MaxAttempts: 1, Timeout: time.Second},
+		logger,
+		emperror.NewNoopHandler(),
+	)
+
+	dispatcher.Dispatch(NewEvent(1, EventClusterDeleted, ClusterEventData{ClusterName: "test"}))
+
+	assert.Equal(t, 0, requests)
+
+	deliveries, err := manager.ListDeliveries(1, subscription.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.False(t, deliveries[0].Succeeded)
+	assert.Contains(t, deliveries[0].Error, "non-public address")
+}
@@ -0,0 +1,183 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/emperror"
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/cluster"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// Event types that can be subscribed to.
+const (
+	EventClusterCreated       = "cluster.created"
+	EventClusterUpdated       = "cluster.updated"
+	EventClusterDeleted       = "cluster.deleted"
+	EventClusterFailed        = "cluster.failed"
+	EventClusterStatusChanged = "cluster.status_changed"
+)
+
+// EventTypes returns every supported event type.
+func EventTypes() []string {
+	return []string{
+		EventClusterCreated,
+		EventClusterUpdated,
+		EventClusterDeleted,
+		EventClusterFailed,
+		EventClusterStatusChanged,
+	}
+}
+
+// IsValidEventType returns true if the event type is supported.
+func IsValidEventType(eventType string) bool {
+	for _, t := range EventTypes() {
+		if t == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ClusterEventData is the data of cluster lifecycle events.
+type ClusterEventData struct {
+	ClusterID      uint   `json:"clusterId,omitempty"`
+	ClusterName    string `json:"clusterName"`
+	Cloud          string `json:"cloud,omitempty"`
+	Distribution   string `json:"distribution,omitempty"`
+	Status         string `json:"status,omitempty"`
+	StatusMessage  string `json:"statusMessage,omitempty"`
+	PreviousStatus string `json:"previousStatus,omitempty"`
+}
+
+type eventBus interface {
+	SubscribeAsync(topic string, fn interface{}, transactional bool) error
+}
+
+type clusterGetter interface {
+	GetClusterByIDOnly(ctx context.Context, clusterID uint) (cluster.CommonCluster, error)
+}
+
+const (
+	clusterCreatedTopic       = "cluster_created"
+	clusterUpdatedTopic       = "cluster_updated"
+	clusterDeletedTopic       = "cluster_deleted"
+	clusterStatusChangedTopic = "cluster_status_changed"
+)
+
+// ClusterEventSubscriber translates cluster events to webhook events.
+type ClusterEventSubscriber struct {
+	clusters     clusterGetter
+	dispatcher   *Dispatcher
+	errorHandler emperror.Handler
+}
+
+// NewClusterEventSubscriber returns a new ClusterEventSubscriber instance.
+func NewClusterEventSubscriber(clusters clusterGetter, dispatcher *Dispatcher, errorHandler emperror.Handler) *ClusterEventSubscriber {
+	return &ClusterEventSubscriber{
+		clusters:     clusters,
+		dispatcher:   dispatcher,
+		errorHandler: errorHandler,
+	}
+}
+
+// Register subscribes to the cluster events of the event bus.
+func (s *ClusterEventSubscriber) Register(eb eventBus) error {
+	subscriptions := map[string]interface{}{
+		clusterCreatedTopic:       s.ClusterCreated,
+		clusterUpdatedTopic:       s.ClusterUpdated,
+		clusterDeletedTopic:       s.ClusterDeleted,
+		clusterStatusChangedTopic: s.ClusterStatusChanged,
+	}
+
+	for topic, fn := range subscriptions {
+		if err := eb.SubscribeAsync(topic, fn, false); err != nil {
+			return errors.WrapIfWithDetails(err, "failed to subscribe to cluster events", "topic", topic)
+		}
+	}
+
+	return nil
+}
+
+// ClusterCreated dispatches the cluster created event.
+func (s *ClusterEventSubscriber) ClusterCreated(clusterID uint) {
+	s.dispatchClusterEvent(EventClusterCreated, clusterID)
+}
+
+// ClusterUpdated dispatches the cluster updated event.
+func (s *ClusterEventSubscriber) ClusterUpdated(clusterID uint) {
+	s.dispatchClusterEvent(EventClusterUpdated, clusterID)
+}
+
+// ClusterDeleted dispatches the cluster deleted event.
+func (s *ClusterEventSubscriber) ClusterDeleted(orgID uint, clusterName string) {
+	s.dispatcher.Dispatch(NewEvent(orgID, EventClusterDeleted, ClusterEventData{
+		ClusterName: clusterName,
+	}))
+}
+
+// ClusterStatusChanged dispatches the status changed event and, when the cluster ended up in an error state,
+// the cluster failed event.
+func (s *ClusterEventSubscriber) ClusterStatusChanged(change cluster.StatusChange) {
+	data := ClusterEventData{
+		ClusterID:      change.ClusterID,
+		ClusterName:    change.ClusterName,
+		Status:         change.ToStatus,
+		StatusMessage:  change.ToStatusMessage,
+		PreviousStatus: change.FromStatus,
+	}
+
+	event := NewEvent(change.OrganizationID, EventClusterStatusChanged, data)
+	if !change.Time.IsZero() {
+		event.Time = change.Time.UTC()
+	}
+
+	s.dispatcher.Dispatch(event)
+
+	if change.ToStatus == pkgCluster.Error {
+		failedEvent := NewEvent(change.OrganizationID, EventClusterFailed, data)
+		failedEvent.Time = event.Time
+
+		s.dispatcher.Dispatch(failedEvent)
+	}
+}
+
+func (s *ClusterEventSubscriber) dispatchClusterEvent(eventType string, clusterID uint) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	commonCluster, err := s.clusters.GetClusterByIDOnly(ctx, clusterID)
+	if err != nil {
+		s.errorHandler.Handle(errors.WrapIfWithDetails(
+			err, "failed to get cluster for webhook event",
+			"clusterId", clusterID,
+			"event", eventType,
+		))
+
+		return
+	}
+
+	s.dispatcher.Dispatch(NewEvent(commonCluster.GetOrganizationId(), eventType, ClusterEventData{
+		ClusterID:    commonCluster.GetID(),
+		ClusterName:  commonCluster.GetName(),
+		Cloud:        commonCluster.GetCloud(),
+		Distribution: commonCluster.GetDistribution(),
+	}))
+}
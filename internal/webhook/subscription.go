@@ -0,0 +1,351 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+
+	secretTypes "github.com/banzaicloud/pipeline/pkg/secret"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+const (
+	// DefaultDeliveryLimit is the number of deliveries returned when no limit is specified.
+	DefaultDeliveryLimit = 50
+
+	// MaxDeliveryLimit is the maximum number of deliveries returned by a single query.
+	MaxDeliveryLimit = 500
+
+	signingKeySecretKey = "signingKey"
+	signingKeyLength    = 32
+)
+
+// Subscription is an organization level webhook subscription.
+type Subscription struct {
+	ID             uint      `gorm:"primary_key" json:"id"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+	OrganizationID uint      `gorm:"index;not null" json:"organizationId"`
+	URL            string    `gorm:"not null" json:"url"`
+	// Events is a comma separated list of event types, empty means every event.
+	Events    string `json:"-"`
+	SecretID  string `gorm:"not null" json:"-"`
+	CreatedBy uint   `json:"createdBy,omitempty"`
+}
+
+// TableName changes the default table name.
+func (Subscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// EventTypes returns the event types the subscription is filtered to.
+func (s Subscription) EventTypes() []string {
+	if s.Events == "" {
+		return []string{}
+	}
+
+	return strings.Split(s.Events, ",")
+}
+
+// Accepts returns true if the subscription should be notified about an event type.
+func (s Subscription) Accepts(eventType string) bool {
+	if s.Events == "" {
+		return true
+	}
+
+	for _, t := range s.EventTypes() {
+		if t == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Delivery is a single attempt of delivering an event to a subscription.
+type Delivery struct {
+	ID             uint      `gorm:"primary_key" json:"id"`
+	CreatedAt      time.Time `json:"createdAt"`
+	SubscriptionID uint      `gorm:"index;not null" json:"subscriptionId"`
+	EventID        string    `gorm:"size:36;not null" json:"eventId"`
+	EventType      string    `gorm:"not null" json:"eventType"`
+	Payload        string    `gorm:"type:text" json:"payload"`
+	Attempt        int       `json:"attempt"`
+	StatusCode     int       `json:"statusCode,omitempty"`
+	Error          string    `gorm:"type:text" json:"error,omitempty"`
+	Duration       int64     `json:"duration"`
+	Succeeded      bool      `json:"succeeded"`
+}
+
+// TableName changes the default table name.
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// SubscriptionNotFoundError is returned when a subscription cannot be found.
+type SubscriptionNotFoundError struct {
+	OrganizationID uint
+	SubscriptionID uint
+}
+
+// Error implements the error interface.
+func (e SubscriptionNotFoundError) Error() string {
+	return fmt.Sprintf("webhook subscription %d not found", e.SubscriptionID)
+}
+
+// ValidationError is returned when a subscription request is invalid.
+type ValidationError struct {
+	message string
+}
+
+// Error implements the error interface.
+func (e ValidationError) Error() string {
+	return e.message
+}
+
+// SecretStore stores the signing keys of the subscriptions.
+type SecretStore interface {
+	Store(organizationID uint, request *secret.CreateSecretRequest) (string, error)
+	Get(organizationID uint, secretID string) (*secret.SecretItemResponse, error)
+	Delete(organizationID uint, secretID string) error
+}
+
+// CreateSubscriptionRequest contains the parameters of a new subscription.
+type CreateSubscriptionRequest struct {
+	OrganizationID uint
+	UserID         uint
+	URL            string
+	Events         []string
+
+	// SigningKey is used for signing the payloads, a random key is generated when empty.
+	SigningKey string
+}
+
+// Manager manages the webhook subscriptions of organizations.
+type Manager struct {
+	db      *gorm.DB
+	secrets SecretStore
+
+	// lookupIP resolves the webhook hosts
+	lookupIP func(host string) ([]net.IP, error)
+}
+
+// NewManager returns a new Manager instance.
+func NewManager(db *gorm.DB, secrets SecretStore) *Manager {
+	return &Manager{
+		db:      db,
+		secrets: secrets,
+
+		lookupIP: net.LookupIP,
+	}
+}
+
+// CreateSubscription creates a new subscription and returns it along with its signing key.
+func (m *Manager) CreateSubscription(request CreateSubscriptionRequest) (*Subscription, string, error) {
+	if err := m.validateURL(request.URL); err != nil {
+		return nil, "", err
+	}
+
+	for _, eventType := range request.Events {
+		if !IsValidEventType(eventType) {
+			return nil, "", ValidationError{message: fmt.Sprintf("unknown event type: %s", eventType)}
+		}
+	}
+
+	signingKey := request.SigningKey
+	if signingKey == "" {
+		var err error
+
+		signingKey, err = secret.RandomString("randAlphaNum", signingKeyLength)
+		if err != nil {
+			return nil, "", errors.WrapIf(err, "failed to generate signing key")
+		}
+	}
+
+	secretName, err := secret.RandomString("randAlphaNum", 8)
+	if err != nil {
+		return nil, "", errors.WrapIf(err, "failed to generate secret name")
+	}
+
+	secretID, err := m.secrets.Store(request.OrganizationID, &secret.CreateSecretRequest{
+		Name:   fmt.Sprintf("webhook-%s", strings.ToLower(secretName)),
+		Type:   secretTypes.GenericSecret,
+		Values: map[string]string{signingKeySecretKey: signingKey},
+		Tags:   []string{secretTypes.TagBanzaiHidden, secretTypes.TagBanzaiReadonly},
+	})
+	if err != nil {
+		return nil, "", errors.WrapIf(err, "failed to store webhook signing key")
+	}
+
+	subscription := &Subscription{
+		OrganizationID: request.OrganizationID,
+		URL:            request.URL,
+		Events:         strings.Join(request.Events, ","),
+		SecretID:       secretID,
+		CreatedBy:      request.UserID,
+	}
+
+	if err := m.db.Create(subscription).Error; err != nil {
+		if serr := m.secrets.Delete(request.OrganizationID, secretID); serr != nil {
+			err = errors.Combine(err, serr)
+		}
+
+		return nil, "", errors.WrapIf(err, "failed to save webhook subscription")
+	}
+
+	return subscription, signingKey, nil
+}
+
+// ListSubscriptions returns the subscriptions of an organization.
+func (m *Manager) ListSubscriptions(organizationID uint) ([]Subscription, error) {
+	subscriptions := make([]Subscription, 0)
+
+	err := m.db.Where(&Subscription{OrganizationID: organizationID}).Order("id").Find(&subscriptions).Error
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to list webhook subscriptions")
+	}
+
+	return subscriptions, nil
+}
+
+// GetSubscription returns a subscription of an organization.
+func (m *Manager) GetSubscription(organizationID uint, subscriptionID uint) (*Subscription, error) {
+	var subscription Subscription
+
+	err := m.db.Where(&Subscription{ID: subscriptionID, OrganizationID: organizationID}).First(&subscription).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, SubscriptionNotFoundError{OrganizationID: organizationID, SubscriptionID: subscriptionID}
+	} else if err != nil {
+		return nil, errors.WrapIf(err, "failed to get webhook subscription")
+	}
+
+	return &subscription, nil
+}
+
+// DeleteSubscription deletes a subscription along with its signing key and delivery log.
+func (m *Manager) DeleteSubscription(organizationID uint, subscriptionID uint) error {
+	subscription, err := m.GetSubscription(organizationID, subscriptionID)
+	if err != nil {
+		return err
+	}
+
+	tx := m.db.Begin()
+
+	if err := tx.Where(&Delivery{SubscriptionID: subscription.ID}).Delete(&Delivery{}).Error; err != nil {
+		tx.Rollback()
+
+		return errors.WrapIf(err, "failed to delete webhook deliveries")
+	}
+
+	if err := tx.Delete(subscription).Error; err != nil {
+		tx.Rollback()
+
+		return errors.WrapIf(err, "failed to delete webhook subscription")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return errors.WrapIf(err, "failed to delete webhook subscription")
+	}
+
+	if err := m.secrets.Delete(organizationID, subscription.SecretID); err != nil && err != secret.ErrSecretNotExists {
+		return errors.WrapIf(err, "failed to delete webhook signing key")
+	}
+
+	return nil
+}
+
+// ListDeliveries returns the latest deliveries of a subscription, latest first.
+func (m *Manager) ListDeliveries(organizationID uint, subscriptionID uint, limit int) ([]Delivery, error) {
+	if _, err := m.GetSubscription(organizationID, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = DefaultDeliveryLimit
+	} else if limit > MaxDeliveryLimit {
+		limit = MaxDeliveryLimit
+	}
+
+	deliveries := make([]Delivery, 0)
+
+	err := m.db.Where(&Delivery{SubscriptionID: subscriptionID}).Order("id desc").Limit(limit).Find(&deliveries).Error
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to list webhook deliveries")
+	}
+
+	return deliveries, nil
+}
+
+// nonPublicNetworks are the address ranges webhooks cannot be delivered to
+// nolint: gochecknoglobals
+var nonPublicNetworks = []string{
+	"0.0.0.0/8",      // "this" network
+	"10.0.0.0/8",     // private
+	"100.64.0.0/10",  // carrier-grade NAT
+	"127.0.0.0/8",    // loopback
+	"169.254.0.0/16", // link-local (including cloud metadata services)
+	"172.16.0.0/12",  // private
+	"192.168.0.0/16", // private
+	"::1/128",        // loopback
+	"fc00::/7",       // unique local
+	"fe80::/10",      // link-local
+}
+
+func (m *Manager) validateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || !u.IsAbs() || u.Host == "" {
+		return ValidationError{message: fmt.Sprintf("invalid webhook URL: %q", rawURL)}
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ValidationError{message: fmt.Sprintf("unsupported webhook URL scheme: %q", u.Scheme)}
+	}
+
+	ips, err := m.lookupIP(u.Hostname())
+	if err != nil || len(ips) == 0 {
+		return ValidationError{message: fmt.Sprintf("cannot resolve webhook host: %q", u.Hostname())}
+	}
+
+	for _, ip := range ips {
+		if !isPublicIP(ip) {
+			return ValidationError{message: fmt.Sprintf("webhook host resolves to a non-public address: %q", u.Hostname())}
+		}
+	}
+
+	return nil
+}
+
+// isPublicIP decides whether an address is reachable on the public internet
+func isPublicIP(ip net.IP) bool {
+	if ip.IsUnspecified() || ip.IsLoopback() || ip.IsMulticast() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
+		return false
+	}
+
+	for _, cidr := range nonPublicNetworks {
+		_, network, err := net.ParseCIDR(cidr)
+		if err == nil && network.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/banzaicloud/pipeline/config"
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
 	modelOracle "github.com/banzaicloud/pipeline/pkg/providers/oracle/model"
 	"github.com/banzaicloud/pipeline/utils"
 )
@@ -112,8 +113,9 @@ type ACKNodePoolModel struct {
 	MaxCount                     int
 	AsgID                        string
 	ScalingConfigID              string
-	Labels                       map[string]string `gorm:"-"`
-	Delete                       bool              `gorm:"-"`
+	Taints                       pkgCommon.NodePoolTaints `gorm:"type:text"`
+	Labels                       map[string]string        `gorm:"-"`
+	Delete                       bool                     `gorm:"-"`
 }
 
 // ACKClusterModel describes the Alibaba Cloud CS cluster model
@@ -146,8 +148,9 @@ type AmazonNodePoolsModel struct {
 	Count            int
 	NodeImage        string
 	NodeInstanceType string
-	Labels           map[string]string `gorm:"-"`
-	Delete           bool              `gorm:"-"`
+	Taints           pkgCommon.NodePoolTaints `gorm:"type:text"`
+	Labels           map[string]string        `gorm:"-"`
+	Delete           bool                     `gorm:"-"`
 }
 
 // BeforeDelete deletes all nodepool labels that belongs to this AmazonNodePoolsModel
@@ -211,7 +214,8 @@ type AKSNodePoolModel struct {
 	Count            int
 	NodeInstanceType string
 	VNetSubnetID     string
-	Labels           map[string]string `gorm:"-"`
+	Taints           pkgCommon.NodePoolTaints `gorm:"type:text"`
+	Labels           map[string]string        `gorm:"-"`
 }
 
 // DummyClusterModel describes the dummy cluster model
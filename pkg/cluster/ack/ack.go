@@ -15,15 +15,18 @@
 package ack
 
 import (
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
 	pkgErrors "github.com/banzaicloud/pipeline/pkg/errors"
 )
 
 // NodePool describes Alibaba's node fields of a CreateCluster/Update request
 type NodePool struct {
-	InstanceType string            `json:"instanceType"`
-	MinCount     int               `json:"minCount"`
-	MaxCount     int               `json:"maxCount"`
-	Labels       map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	InstanceType string                    `json:"instanceType"`
+	MinCount     int                       `json:"minCount"`
+	MaxCount     int                       `json:"maxCount"`
+	Count        int                       `json:"count,omitempty" yaml:"count,omitempty"`
+	Labels       map[string]string         `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Taints       []pkgCommon.NodePoolTaint `json:"taints,omitempty" yaml:"taints,omitempty"`
 }
 
 type NodePools map[string]*NodePool
@@ -85,6 +88,12 @@ func ValidateNodePools(nps NodePools) error {
 		if np.MaxCount < np.MinCount && np.MaxCount > 1000 {
 			return pkgErrors.ErrorAlibabaMaxNumberOfNodes
 		}
+		if np.Count != 0 && (np.Count < np.MinCount || np.Count > np.MaxCount) {
+			return pkgErrors.ErrorNodePoolCountFieldError
+		}
+		if err := pkgCommon.ValidateNodePoolTaints(np.Taints); err != nil {
+			return err
+		}
 	}
 	return nil
 }
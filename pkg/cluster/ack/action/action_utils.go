@@ -203,7 +203,14 @@ func createNodePool(logger logrus.FieldLogger, nodePool *model.ACKNodePoolModel,
 
 	log.Info("creating scaling group")
 
-	scalingGroupRequest.MinSize = requests.NewInteger(nodePool.MinCount)
+	// scaling groups cannot be created with a desired capacity, so the requested node count is set as the minimum size
+	// until the nodes are ready
+	initialSize := nodePool.MinCount
+	if nodePool.Count > initialSize {
+		initialSize = nodePool.Count
+	}
+
+	scalingGroupRequest.MinSize = requests.NewInteger(initialSize)
 	scalingGroupRequest.MaxSize = requests.NewInteger(nodePool.MaxCount)
 	scalingGroupRequest.VSwitchId = cluster.VSwitchID
 	scalingGroupRequest.ScalingGroupName = fmt.Sprintf("asg-%s-%s", nodePool.Name, cluster.ClusterID)
@@ -262,12 +269,28 @@ func createNodePool(logger logrus.FieldLogger, nodePool *model.ACKNodePoolModel,
 		return
 	}
 
-	instanceIds, err := waitUntilScalingInstanceUpdated(log, essClient, cluster.RegionID, nodePool)
+	instanceIds, err := waitUntilScalingInstanceUpdated(log, essClient, cluster.RegionID, nodePool, initialSize)
 	if err != nil {
 		errChan <- emperror.With(err, "cluster", cluster.Name)
 		instanceIdsChan <- nil
 		return
 	}
+
+	if initialSize != nodePool.MinCount {
+		modifyScalingGroupReq := ess.CreateModifyScalingGroupRequest()
+		modifyScalingGroupReq.SetDomain(alibaba.GetESSServiceEndpoint(cluster.RegionID))
+		modifyScalingGroupReq.SetScheme(requests.HTTPS)
+		modifyScalingGroupReq.RegionId = cluster.RegionID
+		modifyScalingGroupReq.ScalingGroupId = nodePool.AsgID
+		modifyScalingGroupReq.MinSize = requests.NewInteger(nodePool.MinCount)
+
+		_, err = essClient.ModifyScalingGroup(modifyScalingGroupReq)
+		if err != nil {
+			errChan <- emperror.WrapWith(err, "could not modify ScalingGroup", "scalingGroupId", nodePool.AsgID, "nodePoolName", nodePool.Name, "cluster", cluster.Name)
+			instanceIdsChan <- nil
+			return
+		}
+	}
 	// set running instance count for nodePool in DB
 	nodePool.Count = len(instanceIds)
 
@@ -299,7 +322,7 @@ func updateNodePool(log logrus.FieldLogger, nodePool *model.ACKNodePoolModel, es
 		return
 	}
 
-	_, err = waitUntilScalingInstanceUpdated(log, essClient, regionId, nodePool)
+	_, err = waitUntilScalingInstanceUpdated(log, essClient, regionId, nodePool, nodePool.MinCount)
 	if err != nil {
 		errChan <- emperror.With(err, "cluster", clusterName)
 		createdInstanceIdsChan <- nil
@@ -332,7 +355,7 @@ func updateNodePool(log logrus.FieldLogger, nodePool *model.ACKNodePoolModel, es
 	createdInstanceIdsChan <- nil
 }
 
-func waitUntilScalingInstanceUpdated(log logrus.FieldLogger, essClient *ess.Client, regionId string, nodePool *model.ACKNodePoolModel) ([]string, error) {
+func waitUntilScalingInstanceUpdated(log logrus.FieldLogger, essClient *ess.Client, regionId string, nodePool *model.ACKNodePoolModel, minCount int) ([]string, error) {
 	log.WithField("nodePoolName", nodePool.Name).Info("waiting for instances to get ready")
 
 	for {
@@ -340,7 +363,7 @@ func waitUntilScalingInstanceUpdated(log logrus.FieldLogger, essClient *ess.Clie
 		if err != nil {
 			return nil, emperror.With(err, "nodePoolName", nodePool.Name)
 		}
-		if describeScalingInstancesResponse.TotalCount < nodePool.MinCount || describeScalingInstancesResponse.TotalCount > nodePool.MaxCount {
+		if describeScalingInstancesResponse.TotalCount < minCount || describeScalingInstancesResponse.TotalCount > nodePool.MaxCount {
 			continue
 		}
 		instanceIds := make([]string, 0)
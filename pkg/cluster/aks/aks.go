@@ -39,22 +39,24 @@ type CreateClusterAKS struct {
 
 // NodePoolCreate describes Azure's node fields of a CreateCluster request
 type NodePoolCreate struct {
-	Autoscaling      bool              `json:"autoscaling" yaml:"autoscaling"`
-	MinCount         int               `json:"minCount" yaml:"minCount"`
-	MaxCount         int               `json:"maxCount" yaml:"maxCount"`
-	Count            int               `json:"count" yaml:"count"`
-	NodeInstanceType string            `json:"instanceType" yaml:"instanceType"`
-	VNetSubnetID     string            `json:"vnetSubnetID,omitempty" yaml:"vnetSubnetID,omitempty"`
-	Labels           map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Autoscaling      bool                      `json:"autoscaling" yaml:"autoscaling"`
+	MinCount         int                       `json:"minCount" yaml:"minCount"`
+	MaxCount         int                       `json:"maxCount" yaml:"maxCount"`
+	Count            int                       `json:"count" yaml:"count"`
+	NodeInstanceType string                    `json:"instanceType" yaml:"instanceType"`
+	VNetSubnetID     string                    `json:"vnetSubnetID,omitempty" yaml:"vnetSubnetID,omitempty"`
+	Labels           map[string]string         `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Taints           []pkgCommon.NodePoolTaint `json:"taints,omitempty" yaml:"taints,omitempty"`
 }
 
 // NodePoolUpdate describes Azure's node count of a UpdateCluster request
 type NodePoolUpdate struct {
-	Autoscaling bool              `json:"autoscaling"`
-	MinCount    int               `json:"minCount"`
-	MaxCount    int               `json:"maxCount"`
-	Count       int               `json:"count"`
-	Labels      map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Autoscaling bool                      `json:"autoscaling"`
+	MinCount    int                       `json:"minCount"`
+	MaxCount    int                       `json:"maxCount"`
+	Count       int                       `json:"count"`
+	Labels      map[string]string         `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Taints      []pkgCommon.NodePoolTaint `json:"taints,omitempty" yaml:"taints,omitempty"`
 }
 
 // UpdateClusterAzure describes Azure's node fields of an UpdateCluster request
@@ -108,6 +110,10 @@ func (azure *CreateClusterAKS) Validate() error {
 		if err := pkgCommon.ValidateNodePoolLabels(np.Labels); err != nil {
 			return err
 		}
+
+		if err := validateNodePoolTaints(np.Autoscaling, np.Taints); err != nil {
+			return err
+		}
 	}
 
 	if len(azure.KubernetesVersion) == 0 {
@@ -158,9 +164,29 @@ func (a *UpdateClusterAzure) Validate() error {
 		return errors.New("'aks' field is empty") // todo move to errors
 	}
 
+	for _, np := range a.NodePools {
+		if np == nil {
+			continue
+		}
+
+		if err := validateNodePoolTaints(np.Autoscaling, np.Taints); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// validateNodePoolTaints validates the taints of a node pool.
+// AKS nodes cannot be registered with taints, so nodes added by the autoscaler would not be tainted.
+func validateNodePoolTaints(autoscaling bool, taints []pkgCommon.NodePoolTaint) error {
+	if autoscaling && len(taints) > 0 {
+		return pkgErrors.ErrorAzureAutoscaledNodePoolTaints
+	}
+
+	return pkgCommon.ValidateNodePoolTaints(taints)
+}
+
 // ClusterProfileAKS describes an Azure profile
 type ClusterProfileAKS struct {
 	KubernetesVersion string                     `json:"kubernetesVersion"`
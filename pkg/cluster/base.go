@@ -41,10 +41,14 @@ const (
 	Warning  = "WARNING"
 	Error    = "ERROR"
 
+	// Deleted is only recorded in the status history as the last transition of a cluster
+	Deleted = "DELETED"
+
 	CreatingMessage = "Cluster creation is in progress"
 	RunningMessage  = "Cluster is running"
 	UpdatingMessage = "Update is in progress"
 	DeletingMessage = "Termination is in progress"
+	DeletedMessage  = "Cluster is deleted"
 )
 
 // Cloud constants
@@ -85,6 +89,7 @@ const (
 	RegisterDomainPostHook                 = "RegisterDomainPostHook"
 	LabelNodesWithNodePoolName             = "LabelNodesWithNodePoolName"
 	TaintHeadNodes                         = "TaintHeadNodes"
+	SyncNodePoolTaints                     = "SyncNodePoolTaints"
 	InstallPVCOperator                     = "InstallPVCOperator"
 	InstallAnchoreImageValidator           = "InstallAnchoreImageValidator"
 	RestoreFromBackup                      = "RestoreFromBackup"
@@ -212,16 +217,17 @@ type GetClusterStatusResponse struct {
 
 // NodePoolStatus describes cluster's node status
 type NodePoolStatus struct {
-	Autoscaling  bool              `json:"autoscaling,omitempty"`
-	Count        int               `json:"count"`
-	InstanceType string            `json:"instanceType,omitempty"`
-	SpotPrice    string            `json:"spotPrice,omitempty"`
-	Preemptible  bool              `json:"preemptible,omitempty"`
-	MinCount     int               `json:"minCount"`
-	MaxCount     int               `json:"maxCount"`
-	Image        string            `json:"image,omitempty"`
-	Version      string            `json:"version,omitempty"`
-	Labels       map[string]string `json:"labels,omitempty"`
+	Autoscaling  bool                      `json:"autoscaling,omitempty"`
+	Count        int                       `json:"count"`
+	InstanceType string                    `json:"instanceType,omitempty"`
+	SpotPrice    string                    `json:"spotPrice,omitempty"`
+	Preemptible  bool                      `json:"preemptible,omitempty"`
+	MinCount     int                       `json:"minCount"`
+	MaxCount     int                       `json:"maxCount"`
+	Image        string                    `json:"image,omitempty"`
+	Version      string                    `json:"version,omitempty"`
+	Labels       map[string]string         `json:"labels,omitempty"`
+	Taints       []pkgCommon.NodePoolTaint `json:"taints,omitempty"`
 
 	pkgCommon.CreatorBaseFields
 }
@@ -374,8 +380,7 @@ func (r *CreateClusterRequest) Validate() error {
 	case Amazon:
 		// eks validate
 		if r.Properties.CreateClusterPKE != nil {
-			//r.Properties.CreateClusterPKE.Validate()
-			return nil
+			return r.Properties.CreateClusterPKE.Validate()
 		}
 		return r.Properties.CreateClusterEKS.Validate()
 	case Azure:
@@ -829,9 +829,17 @@ func (a *CreateUpdateNodePoolStackAction) ExecuteAction(input interface{}) (outp
 					fmt.Sprintf("%v=%v", common.LabelKey, nodePool.Name),
 				}
 
+				kubeletExtraArgs := fmt.Sprintf("--node-labels %v", strings.Join(nodeLabels, ","))
+
+				// taints are registered at node startup so no pods are scheduled before they are applied,
+				// later changes are reconciled by the SyncNodePoolTaints post hook
+				if len(nodePool.Taints) > 0 {
+					kubeletExtraArgs += fmt.Sprintf(" --register-with-taints %v", strings.Join(nodePool.Taints.Strings(), ","))
+				}
+
 				stackParams = append(stackParams, &cloudformation.Parameter{
 					ParameterKey:   aws.String("BootstrapArguments"),
-					ParameterValue: aws.String(fmt.Sprintf("--kubelet-extra-args '--node-labels %v'", strings.Join(nodeLabels, ","))),
+					ParameterValue: aws.String(fmt.Sprintf("--kubelet-extra-args '%v'", kubeletExtraArgs)),
 				})
 			} else {
 				stackParams = append(stackParams, &cloudformation.Parameter{
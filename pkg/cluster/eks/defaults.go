@@ -137,3 +137,50 @@ func GetDefaultImageID(region, kubernetesVersion string) (string, error) {
 
 	return "", fmt.Errorf("unsupported Kubernetes version %q", kubeVersion)
 }
+
+// AddonVersions contains the versions of the EKS cluster add-ons matching a Kubernetes version.
+type AddonVersions struct {
+	KubeProxy    string
+	CoreDNS      string
+	AmazonVPCCNI string
+}
+
+// Add-on versions taken from https://docs.aws.amazon.com/eks/latest/userguide/update-cluster.html
+// nolint: gochecknoglobals
+var addonMappings = []struct {
+	constraint *semver.Constraints
+	versions   AddonVersions
+}{
+	{
+		constraintForVersion("1.10"),
+		AddonVersions{KubeProxy: "1.10.13", CoreDNS: "1.1.3", AmazonVPCCNI: "1.5.3"},
+	},
+	{
+		constraintForVersion("1.11"),
+		AddonVersions{KubeProxy: "1.11.8", CoreDNS: "1.1.3", AmazonVPCCNI: "1.5.3"},
+	},
+	{
+		constraintForVersion("1.12"),
+		AddonVersions{KubeProxy: "1.12.6", CoreDNS: "1.2.2", AmazonVPCCNI: "1.5.3"},
+	},
+	{
+		constraintForVersion("1.13"),
+		AddonVersions{KubeProxy: "1.13.7", CoreDNS: "1.2.6", AmazonVPCCNI: "1.5.3"},
+	},
+}
+
+// GetAddonVersions returns the recommended add-on versions for the given Kubernetes version
+func GetAddonVersions(kubernetesVersion string) (AddonVersions, error) {
+	kubeVersion, err := semver.NewVersion(kubernetesVersion)
+	if err != nil {
+		return AddonVersions{}, emperror.WrapWith(err, "could not create semver from Kubernetes version", "kubernetesVersion", kubernetesVersion)
+	}
+
+	for _, m := range addonMappings {
+		if m.constraint.Check(kubeVersion) {
+			return m.versions, nil
+		}
+	}
+
+	return AddonVersions{}, fmt.Errorf("unsupported Kubernetes version %q", kubeVersion)
+}
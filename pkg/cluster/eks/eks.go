@@ -16,6 +16,7 @@ package eks
 
 import (
 	"emperror.dev/emperror"
+	"emperror.dev/errors"
 	"github.com/Masterminds/semver"
 
 	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
@@ -33,19 +34,21 @@ type CreateClusterEKS struct {
 
 // UpdateClusterAmazonEKS describes Amazon EKS's node fields of an UpdateCluster request
 type UpdateClusterAmazonEKS struct {
+	Version   string               `json:"version,omitempty"`
 	NodePools map[string]*NodePool `json:"nodePools,omitempty"`
 }
 
 // NodePool describes Amazon's node fields of a CreateCluster/Update request
 type NodePool struct {
-	InstanceType string            `json:"instanceType" yaml:"instanceType"`
-	SpotPrice    string            `json:"spotPrice" yaml:"spotPrice"`
-	Autoscaling  bool              `json:"autoscaling" yaml:"autoscaling"`
-	MinCount     int               `json:"minCount" yaml:"minCount"`
-	MaxCount     int               `json:"maxCount" yaml:"maxCount"`
-	Count        int               `json:"count" yaml:"count"`
-	Image        string            `json:"image" yaml:"image"`
-	Labels       map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	InstanceType string                    `json:"instanceType" yaml:"instanceType"`
+	SpotPrice    string                    `json:"spotPrice" yaml:"spotPrice"`
+	Autoscaling  bool                      `json:"autoscaling" yaml:"autoscaling"`
+	MinCount     int                       `json:"minCount" yaml:"minCount"`
+	MaxCount     int                       `json:"maxCount" yaml:"maxCount"`
+	Count        int                       `json:"count" yaml:"count"`
+	Image        string                    `json:"image" yaml:"image"`
+	Labels       map[string]string         `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Taints       []pkgCommon.NodePoolTaint `json:"taints,omitempty" yaml:"taints,omitempty"`
 }
 
 // ClusterVPC describes the VPC for creating an EKS cluster
@@ -113,6 +116,11 @@ func (a *NodePool) Validate() error {
 		return err
 	}
 
+	// --- [Taint validation]--- //
+	if err := pkgCommon.ValidateNodePoolTaints(a.Taints); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -155,6 +163,11 @@ func (a *NodePool) ValidateForUpdate() error {
 		return err
 	}
 
+	// --- [Taint validation]--- //
+	if err := pkgCommon.ValidateNodePoolTaints(a.Taints); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -179,6 +192,46 @@ func (eks *CreateClusterEKS) Validate() error {
 		}
 	}
 
+	return eks.ValidateNetwork()
+}
+
+// ValidateNetwork checks that the VPC and the subnets are either referenced by ID (existing ones) or given by CIDR (new ones).
+// Subnets given by CIDR can be created in an existing VPC as well.
+func (eks *CreateClusterEKS) ValidateNetwork() error {
+	if eks.Vpc != nil {
+		if eks.Vpc.VpcId != "" && eks.Vpc.Cidr != "" {
+			return errors.New("specifying both CIDR and ID for VPC is not allowed")
+		}
+
+		if eks.Vpc.VpcId == "" && eks.Vpc.Cidr == "" {
+			return errors.New("either CIDR or ID is required for VPC")
+		}
+	}
+
+	if len(eks.Subnets) == 0 {
+		return nil
+	}
+
+	subnetCidrUsed := eks.Subnets[0].Cidr != ""
+
+	if !subnetCidrUsed && (eks.Vpc == nil || eks.Vpc.VpcId == "") {
+		return errors.New("if Subnet ID is specified than VPC ID must be provided as well")
+	}
+
+	for _, subnet := range eks.Subnets {
+		if subnet.Cidr != "" && subnet.SubnetId != "" {
+			return errors.New("specifying both CIDR and ID for a Subnet is not allowed")
+		}
+
+		if subnet.Cidr == "" && subnet.SubnetId == "" {
+			return errors.New("either CIDR or ID is required for Subnet")
+		}
+
+		if subnetCidrUsed != (subnet.Cidr != "") {
+			return errors.New("specify either CIDR or ID for all Subnets")
+		}
+	}
+
 	return nil
 }
 
@@ -232,6 +285,16 @@ func (eks *UpdateClusterAmazonEKS) Validate() error {
 		return pkgErrors.ErrorAmazonEksFieldIsEmpty
 	}
 
+	if eks.Version != "" {
+		isValid, err := isValidVersion(eks.Version)
+		if err != nil {
+			return emperror.Wrap(err, "couldn't validate Kubernetes version")
+		}
+		if !isValid {
+			return pkgErrors.ErrorNotValidKubernetesVersion
+		}
+	}
+
 	for _, np := range eks.NodePools {
 		if err := np.ValidateForUpdate(); err != nil {
 			return err
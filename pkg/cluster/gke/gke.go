@@ -44,13 +44,14 @@ type Master struct {
 
 // NodePool describes Google's node fields of a CreateCluster/Update request
 type NodePool struct {
-	Autoscaling      bool              `json:"autoscaling" yaml:"autoscaling"`
-	MinCount         int               `json:"minCount" yaml:"minCount"`
-	MaxCount         int               `json:"maxCount" yaml:"maxCount"`
-	Count            int               `json:"count,omitempty" yaml:"count,omitempty"`
-	NodeInstanceType string            `json:"instanceType,omitempty" yaml:"instanceType,omitempty"`
-	Preemptible      bool              `json:"preemptible,omitempty" yaml:"preemptible,omitempty"`
-	Labels           map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Autoscaling      bool                      `json:"autoscaling" yaml:"autoscaling"`
+	MinCount         int                       `json:"minCount" yaml:"minCount"`
+	MaxCount         int                       `json:"maxCount" yaml:"maxCount"`
+	Count            int                       `json:"count,omitempty" yaml:"count,omitempty"`
+	NodeInstanceType string                    `json:"instanceType,omitempty" yaml:"instanceType,omitempty"`
+	Preemptible      bool                      `json:"preemptible,omitempty" yaml:"preemptible,omitempty"`
+	Labels           map[string]string         `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Taints           []pkgCommon.NodePoolTaint `json:"taints,omitempty" yaml:"taints,omitempty"`
 }
 
 // UpdateClusterGoogle describes Google's node fields of an UpdateCluster request
@@ -118,6 +119,10 @@ func (g *CreateClusterGKE) Validate() error {
 		if err := pkgCommon.ValidateNodePoolLabels(nodePool.Labels); err != nil {
 			return err
 		}
+
+		if err := pkgCommon.ValidateNodePoolTaints(nodePool.Taints); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -147,6 +152,16 @@ func (a *UpdateClusterGoogle) Validate() error {
 		return pkgErrors.ErrorNodePoolNotProvided
 	}
 
+	for _, nodePool := range a.NodePools {
+		if nodePool == nil {
+			continue
+		}
+
+		if err := pkgCommon.ValidateNodePoolTaints(nodePool.Taints); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
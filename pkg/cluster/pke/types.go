@@ -14,7 +14,11 @@
 
 package pke
 
-import "github.com/pkg/errors"
+import (
+	"github.com/pkg/errors"
+
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+)
 
 // TODO add required field to KubeADM if applicable
 
@@ -30,24 +34,36 @@ type CreateClusterPKE struct {
 
 // UpdateClusterPKE describes Pipeline's EC2/BanzaiCloud fields of a UpdateCluster request
 type UpdateClusterPKE struct {
-	NodePools UpdateNodePools `json:"nodepools,omitempty" yaml:"nodepools,omitempty" binding:"required"`
+	Version   string          `json:"version,omitempty" yaml:"version,omitempty"`
+	NodePools UpdateNodePools `json:"nodepools,omitempty" yaml:"nodepools,omitempty"`
 }
 
 func (a *UpdateClusterPKE) Validate() error {
-	// TODO implement
+	// node pools can only be omitted when upgrading the Kubernetes version
+	if a.Version == "" && len(a.NodePools) == 0 {
+		return errors.New("Required field 'nodepools' is empty.")
+	}
+
+	for _, np := range a.NodePools {
+		if err := pkgCommon.ValidateNodePoolTaints(np.Taints); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 type UpdateNodePools map[string]UpdateNodePool
 
 type UpdateNodePool struct {
-	InstanceType string  `json:"instanceType" yaml:"instanceType"`
-	SpotPrice    string  `json:"spotPrice" yaml:"spotPrice"`
-	Autoscaling  bool    `json:"autoscaling" yaml:"autoscaling"`
-	MinCount     int     `json:"minCount" yaml:"minCount"`
-	MaxCount     int     `json:"maxCount" yaml:"maxCount"`
-	Count        int     `json:"count" yaml:"count"`
-	Subnets      Subnets `json:"subnets,omitempty" yaml:"subnets,omitempty"`
+	InstanceType string                    `json:"instanceType" yaml:"instanceType"`
+	SpotPrice    string                    `json:"spotPrice" yaml:"spotPrice"`
+	Autoscaling  bool                      `json:"autoscaling" yaml:"autoscaling"`
+	MinCount     int                       `json:"minCount" yaml:"minCount"`
+	MaxCount     int                       `json:"maxCount" yaml:"maxCount"`
+	Count        int                       `json:"count" yaml:"count"`
+	Subnets      Subnets                   `json:"subnets,omitempty" yaml:"subnets,omitempty"`
+	Taints       []pkgCommon.NodePoolTaint `json:"taints,omitempty" yaml:"taints,omitempty"`
 }
 
 type Network struct {
@@ -67,13 +83,14 @@ const (
 type NodePools []NodePool
 
 type NodePool struct {
-	Name           string                 `json:"name" yaml:"name" binding:"required"`
-	Roles          Roles                  `json:"roles" yaml:"roles" binding:"required"`
-	Hosts          Hosts                  `json:"hosts" yaml:"hosts"`
-	Provider       NodePoolProvider       `json:"provider" yaml:"provider" binding:"required"`
-	ProviderConfig map[string]interface{} `json:"providerConfig" yaml:"providerConfig" binding:"required"`
-	Labels         map[string]string      `json:"labels,omitempty" yaml:"labels,omitempty"`
-	Autoscaling    bool                   `json:"autoscaling" yaml:"autoscaling"`
+	Name           string                    `json:"name" yaml:"name" binding:"required"`
+	Roles          Roles                     `json:"roles" yaml:"roles" binding:"required"`
+	Hosts          Hosts                     `json:"hosts" yaml:"hosts"`
+	Provider       NodePoolProvider          `json:"provider" yaml:"provider" binding:"required"`
+	ProviderConfig map[string]interface{}    `json:"providerConfig" yaml:"providerConfig" binding:"required"`
+	Labels         map[string]string         `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Taints         []pkgCommon.NodePoolTaint `json:"taints,omitempty" yaml:"taints,omitempty"`
+	Autoscaling    bool                      `json:"autoscaling" yaml:"autoscaling"`
 }
 
 type NodePoolProvider string
@@ -129,6 +146,21 @@ type AmazonProviderConfig struct {
 	} `json:"autoScalingGroup" yaml:"autoScalingGroup" binding:"required"`
 }
 
+// Validate validates the PKE fields of a create request
+func (pke *CreateClusterPKE) Validate() error {
+	if pke == nil {
+		return errors.New("Required field 'pke' is empty.")
+	}
+
+	for _, np := range pke.NodePools {
+		if err := pkgCommon.ValidateNodePoolTaints(np.Taints); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // AddDefaults puts default values to optional field(s)
 func (pke *CreateClusterPKE) AddDefaults() error {
 	if pke == nil {
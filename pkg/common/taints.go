@@ -0,0 +1,130 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"emperror.dev/emperror"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Taint effects supported on node pools
+const (
+	TaintEffectNoSchedule       = "NoSchedule"
+	TaintEffectPreferNoSchedule = "PreferNoSchedule"
+	TaintEffectNoExecute        = "NoExecute"
+)
+
+// NodePoolTaint describes a taint applied to every node of a node pool
+type NodePoolTaint struct {
+	Key    string `json:"key" yaml:"key"`
+	Value  string `json:"value,omitempty" yaml:"value,omitempty"`
+	Effect string `json:"effect" yaml:"effect"`
+}
+
+// String returns the taint in the key=value:effect format used by kubelet and kubectl
+func (t NodePoolTaint) String() string {
+	if t.Value == "" {
+		return fmt.Sprintf("%s:%s", t.Key, t.Effect)
+	}
+
+	return fmt.Sprintf("%s=%s:%s", t.Key, t.Value, t.Effect)
+}
+
+// NodePoolTaints describes the taints of a node pool
+type NodePoolTaints []NodePoolTaint
+
+// Value implements the driver.Valuer interface
+func (t NodePoolTaints) Value() (driver.Value, error) {
+	if len(t) == 0 {
+		return "", nil
+	}
+
+	r, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+
+	return string(r), nil
+}
+
+// Scan implements the sql.Scanner interface
+func (t *NodePoolTaints) Scan(src interface{}) error {
+	value, err := cast.ToStringE(src)
+	if err != nil {
+		return err
+	}
+
+	if value == "" {
+		*t = nil
+
+		return nil
+	}
+
+	return json.Unmarshal([]byte(value), t)
+}
+
+// Strings returns the taints in the key=value:effect format
+func (t NodePoolTaints) Strings() []string {
+	taints := make([]string, 0, len(t))
+	for _, taint := range t {
+		taints = append(taints, taint.String())
+	}
+
+	return taints
+}
+
+// ValidateNodePoolTaints checks whether the node pool taints are valid Kubernetes taints
+// and do not collide with taints set by Pipeline
+func ValidateNodePoolTaints(taints []NodePoolTaint) error {
+	keys := make(map[string]bool, len(taints))
+
+	for _, taint := range taints {
+		errs := validation.IsQualifiedName(taint.Key)
+		if len(errs) > 0 {
+			return emperror.WrapWith(errors.New(strings.Join(errs, "\n")), "invalid node taint key", "taintKey", taint.Key)
+		}
+
+		if taint.Key == NodePoolNameTaintKey {
+			return emperror.With(errors.New("node taint key is reserved by Pipeline"), "taintKey", taint.Key)
+		}
+
+		if taint.Value != "" {
+			errs = validation.IsValidLabelValue(taint.Value)
+			if len(errs) > 0 {
+				return emperror.WrapWith(errors.New(strings.Join(errs, "\n")), "invalid node taint value", "taintValue", taint.Value)
+			}
+		}
+
+		switch taint.Effect {
+		case TaintEffectNoSchedule, TaintEffectPreferNoSchedule, TaintEffectNoExecute:
+		default:
+			return emperror.With(errors.New("invalid node taint effect"), "taintKey", taint.Key, "taintEffect", taint.Effect)
+		}
+
+		if keys[taint.Key+":"+taint.Effect] {
+			return emperror.With(errors.New("duplicate node taint"), "taintKey", taint.Key, "taintEffect", taint.Effect)
+		}
+		keys[taint.Key+":"+taint.Effect] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateNodePoolTaints(t *testing.T) {
+	testCases := map[string]struct {
+		taints []NodePoolTaint
+		valid  bool
+	}{
+		"valid": {
+			taints: []NodePoolTaint{
+				{Key: "dedicated", Value: "gpu", Effect: TaintEffectNoSchedule},
+				{Key: "example.com/spot", Effect: TaintEffectPreferNoSchedule},
+				{Key: "dedicated", Value: "gpu", Effect: TaintEffectNoExecute},
+			},
+			valid: true,
+		},
+		"invalid key": {
+			taints: []NodePoolTaint{{Key: "invalid key", Effect: TaintEffectNoSchedule}},
+		},
+		"invalid value": {
+			taints: []NodePoolTaint{{Key: "dedicated", Value: "invalid value", Effect: TaintEffectNoSchedule}},
+		},
+		"invalid effect": {
+			taints: []NodePoolTaint{{Key: "dedicated", Effect: "NoWay"}},
+		},
+		"reserved key": {
+			taints: []NodePoolTaint{{Key: NodePoolNameTaintKey, Value: "head", Effect: TaintEffectNoSchedule}},
+		},
+		"duplicate": {
+			taints: []NodePoolTaint{
+				{Key: "dedicated", Value: "gpu", Effect: TaintEffectNoSchedule},
+				{Key: "dedicated", Value: "cpu", Effect: TaintEffectNoSchedule},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			err := ValidateNodePoolTaints(tc.taints)
+
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestNodePoolTaints_ValueScan(t *testing.T) {
+	taints := NodePoolTaints{
+		{Key: "dedicated", Value: "gpu", Effect: TaintEffectNoSchedule},
+		{Key: "spot", Effect: TaintEffectPreferNoSchedule},
+	}
+
+	value, err := taints.Value()
+	require.NoError(t, err)
+
+	var scanned NodePoolTaints
+	require.NoError(t, scanned.Scan(value))
+
+	assert.Equal(t, taints, scanned)
+	assert.Equal(t, []string{"dedicated=gpu:NoSchedule", "spot:PreferNoSchedule"}, scanned.Strings())
+}
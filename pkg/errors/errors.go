@@ -50,6 +50,7 @@ var (
 	ErrorNotValidNodeVersion                   = errors.New("not valid node version")
 	ErrorNotValidKubernetesVersion             = errors.New("not valid kubernetesVersion")
 	ErrorResourceGroupRequired                 = errors.New("resource group is required")
+	ErrorAzureAutoscaledNodePoolTaints         = errors.New("taints are not supported on autoscaled node pools")
 	ErrStateStorePathEmpty                     = errors.New("statestore path cannot be empty")
 	ErrorAlibabaFieldIsEmpty                   = errors.New("Required field 'alibaba' is empty.")
 	ErrorAlibabaRegionIDFieldIsEmpty           = errors.New("Required field 'region_id' is empty.")
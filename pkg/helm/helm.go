@@ -169,6 +169,45 @@ type GetDeploymentResponse struct {
 	Values       map[string]interface{} `json:"values"`
 }
 
+// GetDeploymentHistoryResponse lists the revisions of a helm deployment
+type GetDeploymentHistoryResponse struct {
+	ReleaseName string               `json:"releaseName"`
+	Revisions   []DeploymentRevision `json:"revisions"`
+}
+
+// DeploymentRevision describes a revision of a helm deployment
+type DeploymentRevision struct {
+	Version      int32         `json:"version"`
+	Status       string        `json:"status"`
+	Chart        string        `json:"chart"`
+	ChartName    string        `json:"chartName"`
+	ChartVersion string        `json:"chartVersion"`
+	Description  string        `json:"description"`
+	UpdatedAt    time.Time     `json:"updatedAt,omitempty"`
+	ValuesDiff   []ValueChange `json:"valuesDiff"`
+}
+
+// ValueChange describes a changed (user supplied) value compared to the previous revision
+type ValueChange struct {
+	Path     string      `json:"path"`
+	OldValue interface{} `json:"oldValue,omitempty"`
+	NewValue interface{} `json:"newValue,omitempty"`
+}
+
+// RollbackDeploymentRequest describes a helm deployment rollback request
+type RollbackDeploymentRequest struct {
+	Version int32 `json:"version" binding:"required,min=1"`
+	Wait    bool  `json:"wait,omitempty"`
+	Timeout int64 `json:"timeout,omitempty"`
+}
+
+// RollbackDeploymentResponse describes the result of a helm deployment rollback
+type RollbackDeploymentResponse struct {
+	ReleaseName string `json:"releaseName"`
+	Version     int32  `json:"version"`
+	Status      string `json:"status"`
+}
+
 // GetDeploymentResourcesResponse lists the resources of a helm deployment
 type GetDeploymentResourcesResponse struct {
 	DeploymentResources []DeploymentResource `json:"resources"`
@@ -0,0 +1,90 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"reflect"
+	"sort"
+)
+
+// DiffValues compares two sets of (nested) values and returns the changed leaf values
+// identified by their dot separated path, ordered by path.
+func DiffValues(oldValues map[string]interface{}, newValues map[string]interface{}) []ValueChange {
+	changes := make([]ValueChange, 0)
+
+	diffValues("", oldValues, newValues, &changes)
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes
+}
+
+func diffValues(prefix string, oldValues map[string]interface{}, newValues map[string]interface{}, changes *[]ValueChange) {
+	keys := make(map[string]bool, len(oldValues)+len(newValues))
+	for key := range oldValues {
+		keys[key] = true
+	}
+	for key := range newValues {
+		keys[key] = true
+	}
+
+	for key := range keys {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		oldValue, oldOK := oldValues[key]
+		newValue, newOK := newValues[key]
+
+		oldMap, oldIsMap := toValuesMap(oldValue)
+		newMap, newIsMap := toValuesMap(newValue)
+
+		switch {
+		case oldIsMap && newIsMap:
+			diffValues(path, oldMap, newMap, changes)
+		case oldIsMap && !newOK:
+			diffValues(path, oldMap, nil, changes)
+		case newIsMap && !oldOK:
+			diffValues(path, nil, newMap, changes)
+		case !reflect.DeepEqual(oldValue, newValue):
+			*changes = append(*changes, ValueChange{
+				Path:     path,
+				OldValue: oldValue,
+				NewValue: newValue,
+			})
+		}
+	}
+}
+
+func toValuesMap(value interface{}) (map[string]interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, true
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			if k, ok := key.(string); ok {
+				m[k] = value
+			}
+		}
+
+		return m, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffValues(t *testing.T) {
+	oldValues := map[string]interface{}{
+		"replicaCount": 1,
+		"image": map[string]interface{}{
+			"repository": "nginx",
+			"tag":        "1.15",
+		},
+		"removed": "value",
+		"nested": map[interface{}]interface{}{
+			"removed": map[string]interface{}{
+				"key": true,
+			},
+		},
+		"list": []interface{}{"a", "b"},
+	}
+
+	newValues := map[string]interface{}{
+		"replicaCount": 2,
+		"image": map[string]interface{}{
+			"repository": "nginx",
+			"tag":        "1.16",
+		},
+		"added": map[string]interface{}{
+			"key": "value",
+		},
+		"list": []interface{}{"a", "b"},
+	}
+
+	expected := []ValueChange{
+		{Path: "added.key", NewValue: "value"},
+		{Path: "image.tag", OldValue: "1.15", NewValue: "1.16"},
+		{Path: "nested.removed.key", OldValue: true},
+		{Path: "removed", OldValue: "value"},
+		{Path: "replicaCount", OldValue: 1, NewValue: 2},
+	}
+
+	assert.Equal(t, expected, DiffValues(oldValues, newValues))
+	assert.Empty(t, DiffValues(newValues, newValues))
+	assert.NotNil(t, DiffValues(nil, nil))
+}
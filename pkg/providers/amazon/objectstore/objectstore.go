@@ -48,6 +48,11 @@ type objectStore struct {
 type Config struct {
 	Region string
 	Opts   []Option
+
+	// Endpoint of an S3 compatible object storage (eg. MinIO), defaults to Amazon S3
+	Endpoint string
+	// ForcePathStyle addresses buckets in the path instead of the host name
+	ForcePathStyle bool
 }
 
 // Credentials represents credentials necessary for access
@@ -65,14 +70,21 @@ func NewPlainObjectStore() (*objectStore, error) {
 // New returns an Object Store instance that manages Amazon S3 buckets.
 func New(config Config, credentials Credentials) (*objectStore, error) {
 
-	sess, err := session.NewSession(&aws.Config{
+	awsConfig := &aws.Config{
 		Region: aws.String(config.Region),
 		Credentials: awsCredentials.NewStaticCredentials(
 			credentials.AccessKeyID,
 			credentials.SecretAccessKey,
 			"",
 		),
-	})
+	}
+
+	if config.Endpoint != "" {
+		awsConfig.Endpoint = aws.String(config.Endpoint)
+		awsConfig.S3ForcePathStyle = aws.Bool(config.ForcePathStyle)
+	}
+
+	sess, err := session.NewSession(awsConfig)
 	if err != nil {
 		return nil, emperror.Wrap(err, "cloud not create AWS session")
 	}
@@ -76,6 +76,17 @@ const (
 	OracleAPIKeyFingerprint = "api_key_fingerprint"
 	OracleRegion            = "region"
 	OracleCompartmentOCID   = "compartment_ocid"
+
+	// Customer secret keys are used to access the Amazon S3 compatibility API of the object storage
+	OracleCustomerSecretKeyID = "customer_secret_key_id"
+	OracleCustomerSecretKey   = "customer_secret_key"
+)
+
+// S3 compatible object storage keys
+const (
+	S3Endpoint        = "S3_ENDPOINT"
+	S3AccessKeyId     = "S3_ACCESS_KEY_ID"
+	S3SecretAccessKey = "S3_SECRET_ACCESS_KEY"
 )
 
 // Kubernetes keys
@@ -188,6 +199,8 @@ const (
 	CloudFlareSecretType = "cloudflare"
 	//
 	DigitalOceanSecretType = "digitalocean"
+	// S3SecretType marks secrets as of type "s3" (S3 compatible object storage, eg. MinIO)
+	S3SecretType = "s3"
 )
 
 // DefaultRules key matching for types
@@ -247,6 +260,8 @@ var DefaultRules = map[string]Meta{
 			{Name: OracleAPIKeyFingerprint, Required: true, Description: "Fingerprint of you public key"},
 			{Name: OracleRegion, Required: true, Description: "Oracle region"},
 			{Name: OracleCompartmentOCID, Required: true, Description: "Your compartment OCID"},
+			{Name: OracleCustomerSecretKeyID, Required: false, Description: "Your customer secret key ID (required for backups)"},
+			{Name: OracleCustomerSecretKey, Required: false, Description: "Your customer secret key (required for backups)"},
 		},
 	},
 	SSHSecretType: {
@@ -327,6 +342,13 @@ var DefaultRules = map[string]Meta{
 			{Name: DoToken, Required: true, Opaque: true, Description: "Your API Token"},
 		},
 	},
+	S3SecretType: {
+		Fields: []FieldMeta{
+			{Name: S3Endpoint, Required: true, Description: "URL of the S3 compatible object storage (eg. https://minio.example.com:9000)"},
+			{Name: S3AccessKeyId, Required: true, Description: "Your access key id"},
+			{Name: S3SecretAccessKey, Required: true, Description: "Your secret access key"},
+		},
+	},
 }
 
 // ListSecretsQuery represent a secret listing filter
@@ -0,0 +1,336 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// secretModel is the database representation of a secret.
+// Everything but the identifiers and the version is stored encrypted.
+type secretModel struct {
+	ID             uint   `gorm:"primary_key"`
+	OrganizationID uint   `gorm:"unique_index:idx_secrets_organization_secret;not null"`
+	SecretID       string `gorm:"unique_index:idx_secrets_organization_secret;size:64;not null"`
+	Version        int    `gorm:"not null"`
+	Data           string `gorm:"type:text;not null"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// TableName changes the default table name.
+func (secretModel) TableName() string {
+	return "secrets"
+}
+
+// secretVersionModel is the database representation of a version of a secret.
+type secretVersionModel struct {
+	ID             uint   `gorm:"primary_key"`
+	OrganizationID uint   `gorm:"unique_index:idx_secret_versions_organization_secret_version;not null"`
+	SecretID       string `gorm:"unique_index:idx_secret_versions_organization_secret_version;size:64;not null"`
+	Version        int    `gorm:"unique_index:idx_secret_versions_organization_secret_version;not null"`
+	Data           string `gorm:"type:text;not null"`
+	CreatedAt      time.Time
+}
+
+// TableName changes the default table name.
+func (secretVersionModel) TableName() string {
+	return "secret_versions"
+}
+
+// secretModelData is the encrypted part of a secret.
+type secretModelData struct {
+	Name      string            `json:"name"`
+	Type      string            `json:"type"`
+	Values    map[string]string `json:"values"`
+	Tags      []string          `json:"tags"`
+	UpdatedBy string            `json:"updatedBy,omitempty"`
+}
+
+// Migrate executes the table migrations for the database secret store.
+func Migrate(db *gorm.DB, logger logrus.FieldLogger) error {
+	logger.WithFields(logrus.Fields{
+		"table_names": strings.Join([]string{secretModel{}.TableName(), secretVersionModel{}.TableName()}, " "),
+	}).Info("migrating secret tables")
+
+	return db.AutoMigrate(&secretModel{}, &secretVersionModel{}).Error
+}
+
+// NewDatabaseSecretStore returns a secret store which keeps the secrets in the database,
+// encrypted with AES-GCM using a key derived from the given encryption key.
+func NewDatabaseSecretStore(db *gorm.DB, encryptionKey string) SecretStore {
+	key := sha256.Sum256([]byte(encryptionKey))
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		// cannot happen with a 32 byte key
+		panic(err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		panic(err)
+	}
+
+	return &localSecretStore{
+		storage: &databaseSecretStorage{
+			db:   db,
+			aead: aead,
+		},
+	}
+}
+
+type databaseSecretStorage struct {
+	db   *gorm.DB
+	aead cipher.AEAD
+}
+
+func (s *databaseSecretStorage) get(organizationID uint, secretID string) (*SecretItemResponse, error) {
+	var model secretModel
+
+	err := s.db.Where(&secretModel{OrganizationID: organizationID, SecretID: secretID}).First(&model).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, ErrSecretNotExists
+	} else if err != nil {
+		return nil, errors.Wrap(err, "Error during reading secret")
+	}
+
+	return s.decode(model)
+}
+
+func (s *databaseSecretStorage) list(organizationID uint) ([]*SecretItemResponse, error) {
+	var models []secretModel
+
+	err := s.db.Where(&secretModel{OrganizationID: organizationID}).Order("secret_id").Find(&models).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "Error during listing secrets")
+	}
+
+	secrets := make([]*SecretItemResponse, 0, len(models))
+	for _, model := range models {
+		secret, err := s.decode(model)
+		if err != nil {
+			return nil, err
+		}
+
+		secrets = append(secrets, secret)
+	}
+
+	return secrets, nil
+}
+
+func (s *databaseSecretStorage) put(organizationID uint, secret *SecretItemResponse, version int) error {
+	data, err := s.encrypt(secretModelData{
+		Name:      secret.Name,
+		Type:      secret.Type,
+		Values:    secret.Values,
+		Tags:      secret.Tags,
+		UpdatedBy: secret.UpdatedBy,
+	})
+	if err != nil {
+		return err
+	}
+
+	tx := s.db.Begin()
+
+	if err := s.putCurrent(tx, organizationID, secret.ID, version, data); err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	err = tx.Create(&secretVersionModel{
+		OrganizationID: organizationID,
+		SecretID:       secret.ID,
+		Version:        version + 1,
+		Data:           data,
+	}).Error
+	if err != nil {
+		tx.Rollback()
+
+		return errors.Wrap(err, "Error during saving secret version")
+	}
+
+	return tx.Commit().Error
+}
+
+func (s *databaseSecretStorage) putCurrent(tx *gorm.DB, organizationID uint, secretID string, version int, data string) error {
+	if version == 0 {
+		var count int
+		err := tx.Model(&secretModel{}).Where(&secretModel{OrganizationID: organizationID, SecretID: secretID}).Count(&count).Error
+		if err != nil {
+			return errors.Wrap(err, "Error during checking secret")
+		}
+
+		if count > 0 {
+			return errCASMismatch
+		}
+
+		// the unique index protects against concurrent inserts
+		return tx.Create(&secretModel{
+			OrganizationID: organizationID,
+			SecretID:       secretID,
+			Version:        1,
+			Data:           data,
+		}).Error
+	}
+
+	result := tx.Model(&secretModel{}).
+		Where("organization_id = ? AND secret_id = ? AND version = ?", organizationID, secretID, version).
+		Updates(map[string]interface{}{
+			"version": version + 1,
+			"data":    data,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errCASMismatch
+	}
+
+	return nil
+}
+
+func (s *databaseSecretStorage) getVersion(organizationID uint, secretID string, version int) (*SecretItemResponse, error) {
+	if _, err := s.get(organizationID, secretID); err != nil {
+		return nil, err
+	}
+
+	var model secretVersionModel
+
+	err := s.db.Where(&secretVersionModel{OrganizationID: organizationID, SecretID: secretID, Version: version}).First(&model).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, ErrSecretVersionNotExists
+	} else if err != nil {
+		return nil, errors.Wrap(err, "Error during reading secret version")
+	}
+
+	return s.decode(secretModel{SecretID: model.SecretID, Version: model.Version, Data: model.Data, UpdatedAt: model.CreatedAt})
+}
+
+func (s *databaseSecretStorage) listVersions(organizationID uint, secretID string) ([]*SecretItemResponse, error) {
+	if _, err := s.get(organizationID, secretID); err != nil {
+		return nil, err
+	}
+
+	var models []secretVersionModel
+
+	err := s.db.Where(&secretVersionModel{OrganizationID: organizationID, SecretID: secretID}).Order("version").Find(&models).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "Error during listing secret versions")
+	}
+
+	secrets := make([]*SecretItemResponse, 0, len(models))
+	for _, model := range models {
+		secret, err := s.decode(secretModel{SecretID: model.SecretID, Version: model.Version, Data: model.Data, UpdatedAt: model.CreatedAt})
+		if err != nil {
+			return nil, err
+		}
+
+		secrets = append(secrets, secret)
+	}
+
+	return secrets, nil
+}
+
+func (s *databaseSecretStorage) delete(organizationID uint, secretID string) error {
+	tx := s.db.Begin()
+
+	err := tx.Where(&secretVersionModel{OrganizationID: organizationID, SecretID: secretID}).Delete(&secretVersionModel{}).Error
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	err = tx.Where(&secretModel{OrganizationID: organizationID, SecretID: secretID}).Delete(&secretModel{}).Error
+	if err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (s *databaseSecretStorage) decode(model secretModel) (*SecretItemResponse, error) {
+	var data secretModelData
+	if err := s.decrypt(model.Data, &data); err != nil {
+		return nil, errors.Wrapf(err, "Error during decrypting secret %s", model.SecretID)
+	}
+
+	secret := &SecretItemResponse{
+		ID:        model.SecretID,
+		Name:      data.Name,
+		Type:      data.Type,
+		Values:    data.Values,
+		Tags:      data.Tags,
+		Version:   model.Version,
+		UpdatedAt: model.UpdatedAt,
+		UpdatedBy: data.UpdatedBy,
+	}
+
+	if secret.Tags == nil {
+		secret.Tags = []string{}
+	}
+
+	return secret, nil
+}
+
+func (s *databaseSecretStorage) encrypt(data secretModelData) (string, error) {
+	plaintext, err := json.Marshal(data)
+	if err != nil {
+		return "", errors.Wrap(err, "Error during encoding secret")
+	}
+
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", errors.Wrap(err, "Error during generating nonce")
+	}
+
+	return base64.StdEncoding.EncodeToString(s.aead.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+func (s *databaseSecretStorage) decrypt(encoded string, data *secretModelData) error {
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return err
+	}
+
+	nonceSize := s.aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return errors.New("ciphertext is too short")
+	}
+
+	plaintext, err := s.aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], nil)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(plaintext, data)
+}
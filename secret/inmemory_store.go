@@ -0,0 +1,135 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// NewInMemorySecretStore returns a secret store which keeps the secrets in memory.
+// It is meant to be used in development and tests only: secrets are lost when the process exits.
+func NewInMemorySecretStore() SecretStore {
+	return &localSecretStore{
+		storage: &inMemorySecretStorage{
+			secrets: make(map[uint]map[string][]*SecretItemResponse),
+		},
+	}
+}
+
+type inMemorySecretStorage struct {
+	// secrets contains every version of the secrets, oldest first
+	secrets map[uint]map[string][]*SecretItemResponse
+	mu      sync.RWMutex
+}
+
+func (s *inMemorySecretStorage) get(organizationID uint, secretID string) (*SecretItemResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	versions, ok := s.secrets[organizationID][secretID]
+	if !ok {
+		return nil, ErrSecretNotExists
+	}
+
+	return copySecretItem(versions[len(versions)-1]), nil
+}
+
+func (s *inMemorySecretStorage) list(organizationID uint) ([]*SecretItemResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	secrets := make([]*SecretItemResponse, 0, len(s.secrets[organizationID]))
+	for _, versions := range s.secrets[organizationID] {
+		secrets = append(secrets, copySecretItem(versions[len(versions)-1]))
+	}
+
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].ID < secrets[j].ID })
+
+	return secrets, nil
+}
+
+func (s *inMemorySecretStorage) getVersion(organizationID uint, secretID string, version int) (*SecretItemResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	versions, ok := s.secrets[organizationID][secretID]
+	if !ok {
+		return nil, ErrSecretNotExists
+	}
+
+	for _, secret := range versions {
+		if secret.Version == version {
+			return copySecretItem(secret), nil
+		}
+	}
+
+	return nil, ErrSecretVersionNotExists
+}
+
+func (s *inMemorySecretStorage) listVersions(organizationID uint, secretID string) ([]*SecretItemResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	versions, ok := s.secrets[organizationID][secretID]
+	if !ok {
+		return nil, ErrSecretNotExists
+	}
+
+	secrets := make([]*SecretItemResponse, 0, len(versions))
+	for _, secret := range versions {
+		secrets = append(secrets, copySecretItem(secret))
+	}
+
+	return secrets, nil
+}
+
+func (s *inMemorySecretStorage) put(organizationID uint, secret *SecretItemResponse, version int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	versions := s.secrets[organizationID][secret.ID]
+
+	currentVersion := 0
+	if len(versions) > 0 {
+		currentVersion = versions[len(versions)-1].Version
+	}
+
+	if currentVersion != version {
+		return errCASMismatch
+	}
+
+	secret = copySecretItem(secret)
+	secret.Version = version + 1
+	secret.UpdatedAt = time.Now().UTC()
+
+	if s.secrets[organizationID] == nil {
+		s.secrets[organizationID] = make(map[string][]*SecretItemResponse)
+	}
+
+	s.secrets[organizationID][secret.ID] = append(versions, secret)
+
+	return nil
+}
+
+func (s *inMemorySecretStorage) delete(organizationID uint, secretID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.secrets[organizationID], secretID)
+
+	return nil
+}
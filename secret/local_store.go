@@ -0,0 +1,228 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"sort"
+
+	secretTypes "github.com/banzaicloud/pipeline/pkg/secret"
+	"github.com/pkg/errors"
+)
+
+// errCASMismatch mimics the error message of Vault, so that IsCASError works for every backend.
+// nolint: gochecknoglobals
+var errCASMismatch = errors.New("check-and-set parameter did not match the current version")
+
+// secretStorage persists secrets for the secret stores which are not backed by Vault.
+type secretStorage interface {
+	// get returns ErrSecretNotExists if the secret cannot be found.
+	get(organizationID uint, secretID string) (*SecretItemResponse, error)
+	list(organizationID uint) ([]*SecretItemResponse, error)
+
+	// getVersion returns ErrSecretVersionNotExists if the version cannot be found.
+	getVersion(organizationID uint, secretID string, version int) (*SecretItemResponse, error)
+	// listVersions returns every version of a secret, oldest first.
+	listVersions(organizationID uint, secretID string) ([]*SecretItemResponse, error)
+
+	// put writes a secret if its current version matches the given one (0 means the secret must not exist yet).
+	// It returns errCASMismatch otherwise.
+	put(organizationID uint, secret *SecretItemResponse, version int) error
+	// delete removes a secret along with its versions.
+	delete(organizationID uint, secretID string) error
+}
+
+// localSecretStore implements the secret store logic on top of a secret storage.
+// Certificate authorities of PKE clusters are generated in process.
+type localSecretStore struct {
+	storage secretStorage
+}
+
+// Store saves a new secret.
+func (s *localSecretStore) Store(organizationID uint, request *CreateSecretRequest) (string, error) {
+	if err := prepareSecret(organizationID, request, generateClusterCAs); err != nil {
+		return "", err
+	}
+
+	secretID := GenerateSecretID(request)
+
+	if err := s.storage.put(organizationID, newSecretItem(secretID, request), 0); err != nil {
+		return "", errors.Wrap(err, "Error during storing secret")
+	}
+
+	return secretID, nil
+}
+
+// Update updates an existing secret.
+func (s *localSecretStore) Update(organizationID uint, secretID string, request *CreateSecretRequest) error {
+	if GenerateSecretID(request) != secretID {
+		return errors.New("Secret name cannot be changed")
+	}
+
+	sort.Strings(request.Tags)
+
+	version := 0
+	if request.Version != nil {
+		version = *request.Version
+	}
+
+	if err := s.storage.put(organizationID, newSecretItem(secretID, request), version); err != nil {
+		return errors.Wrap(err, "Error during updating secret")
+	}
+
+	return nil
+}
+
+// Get returns a secret.
+func (s *localSecretStore) Get(organizationID uint, secretID string) (*SecretItemResponse, error) {
+	return s.storage.get(organizationID, secretID)
+}
+
+// GetByName returns a secret by its name.
+func (s *localSecretStore) GetByName(organizationID uint, name string) (*SecretItemResponse, error) {
+	return getSecretByName(s, organizationID, name)
+}
+
+// List returns the secrets of an organization matching the query.
+func (s *localSecretStore) List(organizationID uint, query *secretTypes.ListSecretsQuery) ([]*SecretItemResponse, error) {
+	var secrets []*SecretItemResponse
+
+	if len(query.IDs) > 0 {
+		for _, secretID := range query.IDs {
+			secret, err := s.storage.get(organizationID, secretID)
+			if err == ErrSecretNotExists {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+
+			secrets = append(secrets, secret)
+		}
+	} else {
+		var err error
+
+		secrets, err = s.storage.list(organizationID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	responseItems := []*SecretItemResponse{}
+
+	for _, secret := range secrets {
+		if (query.Type == secretTypes.AllSecrets || secret.Type == query.Type) && hasTags(secret.Tags, query.Tags) {
+			if !query.Values {
+				for k := range secret.Values {
+					secret.Values[k] = "<hidden>"
+				}
+			}
+
+			responseItems = append(responseItems, secret)
+		}
+	}
+
+	return responseItems, nil
+}
+
+// Delete deletes a secret.
+func (s *localSecretStore) Delete(organizationID uint, secretID string) error {
+	if _, err := s.storage.get(organizationID, secretID); err != nil {
+		return errors.Wrap(err, "Error during querying secret before deletion")
+	}
+
+	if err := s.storage.delete(organizationID, secretID); err != nil {
+		return errors.Wrap(err, "Error during deleting secret")
+	}
+
+	return nil
+}
+
+// GetOrCreate creates a new secret or returns the existing one.
+func (s *localSecretStore) GetOrCreate(organizationID uint, request *CreateSecretRequest) (string, error) {
+	return getOrCreateSecret(s, organizationID, request)
+}
+
+// CreateOrUpdate creates a new secret or updates the existing one.
+func (s *localSecretStore) CreateOrUpdate(organizationID uint, request *CreateSecretRequest) (string, error) {
+	return createOrUpdateSecret(s, organizationID, request)
+}
+
+// DeleteByClusterUID deletes the secrets of a cluster.
+func (s *localSecretStore) DeleteByClusterUID(organizationID uint, clusterUID string) error {
+	return deleteSecretsByClusterUID(s, organizationID, clusterUID)
+}
+
+// ListVersions returns the versions of a secret, latest first.
+func (s *localSecretStore) ListVersions(organizationID uint, secretID string) ([]SecretVersion, error) {
+	current, err := s.storage.get(organizationID, secretID)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets, err := s.storage.listVersions(organizationID, secretID)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]SecretVersion, 0, len(secrets))
+	for i := len(secrets) - 1; i >= 0; i-- {
+		versions = append(versions, SecretVersion{
+			Version:   secrets[i].Version,
+			UpdatedAt: secrets[i].UpdatedAt,
+			UpdatedBy: secrets[i].UpdatedBy,
+			Current:   secrets[i].Version == current.Version,
+		})
+	}
+
+	return versions, nil
+}
+
+// GetVersion returns a version of a secret.
+func (s *localSecretStore) GetVersion(organizationID uint, secretID string, version int) (*SecretItemResponse, error) {
+	return s.storage.getVersion(organizationID, secretID, version)
+}
+
+// RestoreVersion writes a previous version of a secret as its new version.
+func (s *localSecretStore) RestoreVersion(organizationID uint, secretID string, version int, updatedBy string) error {
+	return restoreSecretVersion(s, organizationID, secretID, version, updatedBy)
+}
+
+// Rotate regenerates the values of a generated secret and writes them as its new version.
+func (s *localSecretStore) Rotate(organizationID uint, secretID string, updatedBy string) error {
+	return rotateSecret(s, organizationID, secretID, updatedBy, generateClusterCAs)
+}
+
+func newSecretItem(secretID string, request *CreateSecretRequest) *SecretItemResponse {
+	return &SecretItemResponse{
+		ID:        secretID,
+		Name:      request.Name,
+		Type:      request.Type,
+		Values:    request.Values,
+		Tags:      request.Tags,
+		UpdatedBy: request.UpdatedBy,
+	}
+}
+
+func copySecretItem(secret *SecretItemResponse) *SecretItemResponse {
+	c := *secret
+
+	c.Values = make(map[string]string, len(secret.Values))
+	for k, v := range secret.Values {
+		c.Values[k] = v
+	}
+
+	c.Tags = append([]string{}, secret.Tags...)
+
+	return &c
+}
@@ -0,0 +1,240 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret_test
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pkgSecret "github.com/banzaicloud/pipeline/pkg/secret"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+func TestLocalSecretStores(t *testing.T) {
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	db.DB().SetMaxOpenConns(1)
+
+	logger, _ := test.NewNullLogger()
+	require.NoError(t, secret.Migrate(db, logger))
+
+	stores := map[string]secret.SecretStore{
+		"memory":   secret.NewInMemorySecretStore(),
+		"database": secret.NewDatabaseSecretStore(db, "encryption-key"),
+	}
+
+	for name, store := range stores {
+		store := store
+
+		t.Run(name, func(t *testing.T) {
+			testSecretStore(t, store)
+		})
+
+		t.Run(name+"Versions", func(t *testing.T) {
+			testSecretStoreVersions(t, store)
+		})
+	}
+}
+
+func testSecretStore(t *testing.T, store secret.SecretStore) {
+	const orgID = 1
+
+	_, err := store.Store(orgID, &secret.CreateSecretRequest{Name: "Invalid_Name", Type: pkgSecret.GenericSecret})
+	require.Error(t, err)
+
+	request := &secret.CreateSecretRequest{
+		Name:   "my-password",
+		Type:   pkgSecret.PasswordSecretType,
+		Values: map[string]string{pkgSecret.Username: "admin", pkgSecret.Password: "randAlpha,16"},
+		Tags:   []string{"b", "a"},
+	}
+
+	secretID, err := store.Store(orgID, request)
+	require.NoError(t, err)
+	assert.Equal(t, secret.GenerateSecretIDFromName("my-password"), secretID)
+
+	_, err = store.Store(orgID, request)
+	require.Error(t, err)
+	assert.True(t, secret.IsCASError(err))
+
+	item, err := store.Get(orgID, secretID)
+	require.NoError(t, err)
+	assert.Equal(t, "my-password", item.Name)
+	assert.Equal(t, 1, item.Version)
+	assert.Equal(t, []string{"a", "b"}, item.Tags)
+	assert.Len(t, item.Values[pkgSecret.Password], 16)
+
+	_, err = store.Get(orgID+1, secretID)
+	assert.Equal(t, secret.ErrSecretNotExists, err)
+
+	byName, err := store.GetByName(orgID, "my-password")
+	require.NoError(t, err)
+	assert.Equal(t, item.Values, byName.Values)
+
+	// Updates must match the current version
+	staleVersion := 0
+	err = store.Update(orgID, secretID, &secret.CreateSecretRequest{
+		Name:    "my-password",
+		Type:    pkgSecret.PasswordSecretType,
+		Values:  map[string]string{pkgSecret.Username: "admin", pkgSecret.Password: "new"},
+		Version: &staleVersion,
+	})
+	require.Error(t, err)
+	assert.True(t, secret.IsCASError(err))
+
+	_, err = store.CreateOrUpdate(orgID, &secret.CreateSecretRequest{
+		Name:   "my-password",
+		Type:   pkgSecret.PasswordSecretType,
+		Values: map[string]string{pkgSecret.Username: "admin", pkgSecret.Password: "new"},
+		Tags:   []string{"clusterUID:abc"},
+	})
+	require.NoError(t, err)
+
+	item, err = store.Get(orgID, secretID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, item.Version)
+	assert.Equal(t, "new", item.Values[pkgSecret.Password])
+
+	otherID, err := store.GetOrCreate(orgID, &secret.CreateSecretRequest{
+		Name:   "generic",
+		Type:   pkgSecret.GenericSecret,
+		Values: map[string]string{"key": "value"},
+	})
+	require.NoError(t, err)
+
+	items, err := store.List(orgID, &pkgSecret.ListSecretsQuery{Type: pkgSecret.AllSecrets})
+	require.NoError(t, err)
+	assert.Len(t, items, 2)
+
+	items, err = store.List(orgID, &pkgSecret.ListSecretsQuery{Type: pkgSecret.GenericSecret, Values: true})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "value", items[0].Values["key"])
+
+	items, err = store.List(orgID, &pkgSecret.ListSecretsQuery{Type: pkgSecret.AllSecrets, Tags: []string{"clusterUID:abc"}})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "<hidden>", items[0].Values[pkgSecret.Password])
+
+	// Listing without values must not modify the stored secret
+	item, err = store.Get(orgID, secretID)
+	require.NoError(t, err)
+	assert.Equal(t, "new", item.Values[pkgSecret.Password])
+
+	require.NoError(t, store.DeleteByClusterUID(orgID, "abc"))
+
+	_, err = store.Get(orgID, secretID)
+	assert.Equal(t, secret.ErrSecretNotExists, err)
+
+	require.NoError(t, store.Delete(orgID, otherID))
+	assert.Error(t, store.Delete(orgID, otherID))
+}
+
+func testSecretStoreVersions(t *testing.T, store secret.SecretStore) {
+	const orgID = 2
+
+	request := func(password string, updatedBy string) *secret.CreateSecretRequest {
+		return &secret.CreateSecretRequest{
+			Name:      "credentials",
+			Type:      pkgSecret.PasswordSecretType,
+			Values:    map[string]string{pkgSecret.Username: "admin", pkgSecret.Password: password},
+			UpdatedBy: updatedBy,
+		}
+	}
+
+	_, err := store.ListVersions(orgID, "missing")
+	assert.Equal(t, secret.ErrSecretNotExists, err)
+
+	secretID, err := store.Store(orgID, request("first", "alice"))
+	require.NoError(t, err)
+
+	_, err = store.CreateOrUpdate(orgID, request("second", "bob"))
+	require.NoError(t, err)
+
+	versions, err := store.ListVersions(orgID, secretID)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, 2, versions[0].Version)
+	assert.Equal(t, "bob", versions[0].UpdatedBy)
+	assert.True(t, versions[0].Current)
+	assert.Equal(t, 1, versions[1].Version)
+	assert.Equal(t, "alice", versions[1].UpdatedBy)
+	assert.False(t, versions[1].Current)
+
+	first, err := store.GetVersion(orgID, secretID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "first", first.Values[pkgSecret.Password])
+
+	_, err = store.GetVersion(orgID, secretID, 3)
+	assert.Equal(t, secret.ErrSecretVersionNotExists, err)
+
+	require.NoError(t, store.RestoreVersion(orgID, secretID, 1, "carol"))
+
+	current, err := store.Get(orgID, secretID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, current.Version)
+	assert.Equal(t, "first", current.Values[pkgSecret.Password])
+	assert.Equal(t, "carol", current.UpdatedBy)
+
+	versions, err = store.ListVersions(orgID, secretID)
+	require.NoError(t, err)
+	assert.Len(t, versions, 3)
+
+	require.NoError(t, store.Delete(orgID, secretID))
+
+	_, err = store.GetVersion(orgID, secretID, 1)
+	assert.Equal(t, secret.ErrSecretNotExists, err)
+}
+
+func TestInMemorySecretStore_PKE(t *testing.T) {
+	store := secret.NewInMemorySecretStore()
+
+	secretID, err := store.Store(1, &secret.CreateSecretRequest{
+		Name:   "cluster-1-pke",
+		Type:   pkgSecret.PKESecretType,
+		Values: map[string]string{},
+		Tags:   []string{"clusterID:1"},
+	})
+	require.NoError(t, err)
+
+	item, err := store.Get(1, secretID)
+	require.NoError(t, err)
+
+	rootBlock, rest := pem.Decode([]byte(item.Values[pkgSecret.KubernetesCACert]))
+	require.NotNil(t, rootBlock)
+	intermediate, err := x509.ParseCertificate(rootBlock.Bytes)
+	require.NoError(t, err)
+
+	rootBlock, _ = pem.Decode(rest)
+	require.NotNil(t, rootBlock)
+	root, err := x509.ParseCertificate(rootBlock.Bytes)
+	require.NoError(t, err)
+
+	assert.Equal(t, pkgSecret.KubernetesCACommonName, intermediate.Subject.CommonName)
+	assert.NoError(t, intermediate.CheckSignatureFrom(root))
+
+	for _, key := range []string{pkgSecret.KubernetesCAKey, pkgSecret.EtcdCAKey, pkgSecret.FrontProxyCAKey, pkgSecret.SAKey, pkgSecret.SAPub, pkgSecret.EncryptionSecret} {
+		assert.NotEmpty(t, item.Values[key], key)
+	}
+}
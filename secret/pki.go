@@ -0,0 +1,115 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+
+	secretTypes "github.com/banzaicloud/pipeline/pkg/secret"
+	"github.com/pkg/errors"
+)
+
+const (
+	// Same validity as the Vault PKI engines of the clusters
+	rootCAValidity         = 43801 * time.Hour
+	intermediateCAValidity = 43800 * time.Hour
+
+	certificateBlockType = "CERTIFICATE"
+)
+
+// generateClusterCAs generates the certificate authorities of a PKE cluster in process,
+// following the same structure as the Vault PKI engines: a root CA signing the intermediate ones.
+func generateClusterCAs(_ uint, clusterID string) (*clusterCAs, error) {
+	rootKey, rootCert, err := generateCA(fmt.Sprintf("cluster-%s-ca", clusterID), rootCAValidity, nil, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error generating root CA for cluster %s", clusterID)
+	}
+
+	cas := &clusterCAs{
+		RootCA: string(encodeCertificatePEM(rootCert)),
+	}
+
+	for commonName, ca := range map[string]**certificate{
+		secretTypes.KubernetesCACommonName:           &cas.Kubernetes,
+		secretTypes.EtcdCACommonName:                 &cas.Etcd,
+		secretTypes.KubernetesFrontProxyCACommonName: &cas.FrontProxy,
+	} {
+		key, cert, err := generateCA(commonName, intermediateCAValidity, rootCert, rootKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error generating %s intermediate cert for cluster %s", commonName, clusterID)
+		}
+
+		*ca = &certificate{
+			Key:  string(encodePrivateKeyPEM(key)),
+			Cert: string(encodeCertificatePEM(cert)),
+		}
+	}
+
+	return cas, nil
+}
+
+// generateCA generates a CA certificate, self-signed if no parent is given.
+func generateCA(commonName string, validity time.Duration, parent *x509.Certificate, parentKey *rsa.PrivateKey) (*rsa.PrivateKey, *x509.Certificate, error) {
+	key, err := rsa.GenerateKey(rand.Reader, rsaKeySize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             now.Add(-30 * time.Second),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return key, cert, nil
+}
+
+func encodeCertificatePEM(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  certificateBlockType,
+		Bytes: cert.Raw,
+	})
+}
@@ -0,0 +1,107 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	secretTypes "github.com/banzaicloud/pipeline/pkg/secret"
+	"github.com/pkg/errors"
+)
+
+// ErrSecretNotRotatable is returned when the values of a secret cannot be regenerated.
+// nolint: gochecknoglobals
+var ErrSecretNotRotatable = errors.New("Secret values are not generated by Pipeline, so they cannot be rotated")
+
+// IsRotatable returns true if the values of the given secret type can be regenerated.
+func IsRotatable(secretType string) bool {
+	switch secretType {
+	case secretTypes.PasswordSecretType, secretTypes.HtpasswdSecretType, secretTypes.TLSSecretType:
+		return true
+	default:
+		return false
+	}
+}
+
+// CertificateExpiry returns the expiry of the server certificate (or the CA certificate if there is none) of a TLS secret.
+func (s *SecretItemResponse) CertificateExpiry() (time.Time, error) {
+	certificate := s.Values[secretTypes.ServerCert]
+	if certificate == "" {
+		certificate = s.Values[secretTypes.CACert]
+	}
+
+	block, _ := pem.Decode([]byte(certificate))
+	if block == nil {
+		return time.Time{}, errors.New("secret does not contain a PEM encoded certificate")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "Error during parsing certificate")
+	}
+
+	return cert.NotAfter, nil
+}
+
+// rotateSecret regenerates the values of a secret from the same parameters they were originally generated from.
+func rotateSecret(store SecretStore, organizationID uint, secretID string, updatedBy string, generateCAs clusterCAGenerator) error {
+	current, err := store.Get(organizationID, secretID)
+	if err != nil {
+		return err
+	}
+
+	values := map[string]string{}
+
+	switch current.Type {
+	case secretTypes.PasswordSecretType:
+		length := len(current.Values[secretTypes.Password])
+		if length == 0 {
+			return ErrSecretNotRotatable
+		}
+
+		values[secretTypes.Username] = current.Values[secretTypes.Username]
+		values[secretTypes.Password] = fmt.Sprintf("randAlphaNum,%d", length)
+
+	case secretTypes.HtpasswdSecretType:
+		values[secretTypes.Username] = current.Values[secretTypes.Username]
+
+	case secretTypes.TLSSecretType:
+		values[secretTypes.TLSHosts] = current.Values[secretTypes.TLSHosts]
+		if validity := current.Values[secretTypes.TLSValidity]; validity != "" {
+			values[secretTypes.TLSValidity] = validity
+		}
+
+	default:
+		return ErrSecretNotRotatable
+	}
+
+	request := &CreateSecretRequest{
+		Name:      current.Name,
+		Type:      current.Type,
+		Values:    values,
+		Tags:      current.Tags,
+		Version:   &current.Version,
+		UpdatedBy: updatedBy,
+	}
+
+	if err := generateValuesIfNeeded(organizationID, request, generateCAs); err != nil {
+		return errors.Wrap(err, "Error during regenerating secret values")
+	}
+
+	return store.Update(organizationID, secretID, request)
+}
@@ -26,10 +26,12 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/banzaicloud/bank-vaults/pkg/tls"
 	"github.com/banzaicloud/bank-vaults/pkg/vault"
+	"github.com/banzaicloud/pipeline/config"
 	secretTypes "github.com/banzaicloud/pipeline/pkg/secret"
 	"github.com/banzaicloud/pipeline/secret/verify"
 	vaultapi "github.com/hashicorp/vault/api"
@@ -48,9 +50,39 @@ const (
 	PublicKeyBlockType     = "PUBLIC KEY"
 )
 
-// Store object that wraps up vault logical store
+// Secret store backends
+const (
+	VaultBackend    = "vault"
+	DatabaseBackend = "database"
+	InMemoryBackend = "memory"
+)
+
+// SecretStore stores the secrets of organizations.
+type SecretStore interface {
+	Store(organizationID uint, request *CreateSecretRequest) (string, error)
+	Get(organizationID uint, secretID string) (*SecretItemResponse, error)
+	GetByName(organizationID uint, name string) (*SecretItemResponse, error)
+	List(organizationID uint, query *secretTypes.ListSecretsQuery) ([]*SecretItemResponse, error)
+	Update(organizationID uint, secretID string, request *CreateSecretRequest) error
+	Delete(organizationID uint, secretID string) error
+	GetOrCreate(organizationID uint, request *CreateSecretRequest) (string, error)
+	CreateOrUpdate(organizationID uint, request *CreateSecretRequest) (string, error)
+	DeleteByClusterUID(organizationID uint, clusterUID string) error
+
+	// ListVersions returns the available versions of a secret, latest first.
+	ListVersions(organizationID uint, secretID string) ([]SecretVersion, error)
+	// GetVersion returns a specific version of a secret.
+	GetVersion(organizationID uint, secretID string, version int) (*SecretItemResponse, error)
+	// RestoreVersion writes the content of a previous version of a secret as its new version.
+	RestoreVersion(organizationID uint, secretID string, version int, updatedBy string) error
+
+	// Rotate regenerates the values of a generated secret and writes them as its new version.
+	Rotate(organizationID uint, secretID string, updatedBy string) error
+}
+
+// Store object that wraps up the configured secret store backend
 // nolint: gochecknoglobals
-var Store *secretStore
+var Store SecretStore
 
 // RestrictedStore object that wraps the main secret store and restricts access to certain items
 // nolint: gochecknoglobals
@@ -60,12 +92,58 @@ var RestrictedStore *restrictedSecretStore
 // nolint: gochecknoglobals
 var ErrSecretNotExists = fmt.Errorf("There's no secret with this ID")
 
+// ErrSecretVersionNotExists denotes 'Not Found' errors for secret versions
+// nolint: gochecknoglobals
+var ErrSecretVersionNotExists = fmt.Errorf("There's no secret version with this number")
+
+// nolint: gochecknoglobals
+var (
+	vaultClient     *vault.Client
+	vaultClientOnce sync.Once
+)
+
 func init() {
-	Store = newVaultSecretStore()
+	Store = newSecretStore(viper.GetString(config.SecretStoreBackend))
 	RestrictedStore = &restrictedSecretStore{Store}
 }
 
-type secretStore struct {
+func newSecretStore(backend string) SecretStore {
+	switch backend {
+	case VaultBackend:
+		return newVaultSecretStore()
+
+	case DatabaseBackend:
+		encryptionKey := viper.GetString(config.SecretStoreEncryptionKey)
+		if encryptionKey == "" {
+			panic(errors.New("encryption key is required for the database secret store"))
+		}
+
+		return NewDatabaseSecretStore(config.DB(), encryptionKey)
+
+	case InMemoryBackend:
+		return NewInMemorySecretStore()
+
+	default:
+		panic(errors.Errorf("unknown secret store backend: %s", backend))
+	}
+}
+
+// VaultClient returns the Vault client of Pipeline.
+// The client is created on first use, so that Vault is only required when something actually relies on it.
+func VaultClient() *vault.Client {
+	vaultClientOnce.Do(func() {
+		client, err := vault.NewClient("pipeline")
+		if err != nil {
+			panic(err)
+		}
+
+		vaultClient = client
+	})
+
+	return vaultClient
+}
+
+type vaultSecretStore struct {
 	Client  *vault.Client
 	Logical *vaultapi.Logical
 }
@@ -128,6 +206,14 @@ func (s *SecretItemResponse) ValidateSecretType(validType string) error {
 	return nil
 }
 
+// SecretVersion describes a version of a secret
+type SecretVersion struct {
+	Version   int       `json:"version"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	UpdatedBy string    `json:"updatedBy,omitempty"`
+	Current   bool      `json:"current"`
+}
+
 // AllowedFilteredSecretTypesResponse for API response for AllowedSecretTypes/:type
 type AllowedFilteredSecretTypesResponse struct {
 	Keys secretTypes.Meta `json:"meta"`
@@ -136,14 +222,10 @@ type AllowedFilteredSecretTypesResponse struct {
 // AllowedSecretTypesResponse for API response for AllowedSecretTypes
 type AllowedSecretTypesResponse map[string]secretTypes.Meta
 
-func newVaultSecretStore() *secretStore {
-	role := "pipeline"
-	client, err := vault.NewClient(role)
-	if err != nil {
-		panic(err)
-	}
+func newVaultSecretStore() *vaultSecretStore {
+	client := VaultClient()
 	logical := client.Vault().Logical()
-	return &secretStore{Client: client, Logical: logical}
+	return &vaultSecretStore{Client: client, Logical: logical}
 }
 
 // GenerateSecretIDFromName generates a "unique by name per organization" id for Secrets
@@ -225,38 +307,12 @@ func (r *CreateSecretRequest) ValidateAsNew(verifier verify.Verifier) error {
 }
 
 // DeleteByClusterUID Delete secrets by ClusterUID
-func (ss *secretStore) DeleteByClusterUID(orgID uint, clusterUID string) error {
-	if clusterUID == "" {
-		return errors.New("clusterUID is empty")
-	}
-
-	log := log.WithFields(logrus.Fields{"organization": orgID, "clusterUID": clusterUID})
-
-	clusterUIDTag := clusterUIDTag(clusterUID)
-	secrets, err := Store.List(orgID,
-		&secretTypes.ListSecretsQuery{
-			Tags: []string{clusterUIDTag},
-		})
-
-	if err != nil {
-		log.Errorf("Error during list secrets: %s", err.Error())
-		return err
-	}
-
-	for _, s := range secrets {
-		log := log.WithFields(logrus.Fields{"secret": s.ID, "secretName": s.Name})
-		err := Store.Delete(orgID, s.ID)
-		if err != nil {
-			log.Errorf("Error during delete secret: %s", err.Error())
-		}
-		log.Infoln("Secret Deleted")
-	}
-
-	return nil
+func (ss *vaultSecretStore) DeleteByClusterUID(orgID uint, clusterUID string) error {
+	return deleteSecretsByClusterUID(ss, orgID, clusterUID)
 }
 
 // Delete secret secret/orgs/:orgid:/:id: scope
-func (ss *secretStore) Delete(organizationID uint, secretID string) error {
+func (ss *vaultSecretStore) Delete(organizationID uint, secretID string) error {
 
 	path := secretMetadataPath(organizationID, secretID)
 
@@ -305,22 +361,15 @@ func (ss *secretStore) Delete(organizationID uint, secretID string) error {
 }
 
 // Save secret secret/orgs/:orgid:/:id: scope
-func (ss *secretStore) Store(organizationID uint, request *CreateSecretRequest) (string, error) {
+func (ss *vaultSecretStore) Store(organizationID uint, request *CreateSecretRequest) (string, error) {
 
-	// We allow only Kubernetes compatible Secret names
-	if errorList := validation.IsDNS1123Subdomain(request.Name); errorList != nil {
-		return "", errors.New(errorList[0])
+	if err := prepareSecret(organizationID, request, ss.generateClusterCAs); err != nil {
+		return "", err
 	}
 
 	secretID := GenerateSecretID(request)
 	path := secretDataPath(organizationID, secretID)
 
-	if err := ss.generateValuesIfNeeded(organizationID, request); err != nil {
-		return "", err
-	}
-
-	sort.Strings(request.Tags)
-
 	data, err := secretData(0, request)
 	if err != nil {
 		return "", err
@@ -334,7 +383,7 @@ func (ss *secretStore) Store(organizationID uint, request *CreateSecretRequest)
 }
 
 // Update secret secret/orgs/:orgid:/:id: scope
-func (ss *secretStore) Update(organizationID uint, secretID string, request *CreateSecretRequest) error {
+func (ss *vaultSecretStore) Update(organizationID uint, secretID string, request *CreateSecretRequest) error {
 
 	if GenerateSecretID(request) != secretID {
 		return errors.New("Secret name cannot be changed")
@@ -365,49 +414,13 @@ func (ss *secretStore) Update(organizationID uint, secretID string, request *Cre
 }
 
 // GetOrCreate create new secret or get if it's exist. secret/orgs/:orgid:/:id: scope
-func (ss *secretStore) GetOrCreate(organizationID uint, value *CreateSecretRequest) (string, error) {
-	secretID := GenerateSecretID(value)
-
-	// Try to get the secret version first
-	if secret, err := Store.Get(organizationID, secretID); err != nil && err != ErrSecretNotExists {
-		log.Errorf("Error during checking secret: %s", err.Error())
-		return "", err
-	} else if secret != nil {
-		return secret.ID, nil
-	} else {
-		secretID, err = Store.Store(organizationID, value)
-		if err != nil {
-			log.Errorf("Error during storing secret: %s", err.Error())
-			return "", err
-		}
-	}
-	return secretID, nil
+func (ss *vaultSecretStore) GetOrCreate(organizationID uint, value *CreateSecretRequest) (string, error) {
+	return getOrCreateSecret(ss, organizationID, value)
 }
 
 // CreateOrUpdate create new secret or update if it's exist. secret/orgs/:orgid:/:id: scope
-func (ss *secretStore) CreateOrUpdate(organizationID uint, value *CreateSecretRequest) (string, error) {
-
-	secretID := GenerateSecretID(value)
-
-	// Try to get the secret version first
-	if secret, err := Store.Get(organizationID, secretID); err != nil && err != ErrSecretNotExists {
-		log.Errorf("Error during checking secret: %s", err.Error())
-		return "", err
-	} else if secret != nil {
-		value.Version = &(secret.Version)
-		err := Store.Update(organizationID, secretID, value)
-		if err != nil {
-			log.Errorf("Error during updating secret: %s", err.Error())
-			return "", err
-		}
-	} else {
-		secretID, err = Store.Store(organizationID, value)
-		if err != nil {
-			log.Errorf("Error during storing secret: %s", err.Error())
-			return "", err
-		}
-	}
-	return secretID, nil
+func (ss *vaultSecretStore) CreateOrUpdate(organizationID uint, value *CreateSecretRequest) (string, error) {
+	return createOrUpdateSecret(ss, organizationID, value)
 }
 
 func parseSecret(secretID string, secret *vaultapi.Secret, values bool) (*SecretItemResponse, error) {
@@ -444,7 +457,7 @@ func parseSecret(secretID string, secret *vaultapi.Secret, values bool) (*Secret
 }
 
 // Retrieve secret secret/orgs/:orgid:/:id: scope
-func (ss *secretStore) Get(organizationID uint, secretID string) (*SecretItemResponse, error) {
+func (ss *vaultSecretStore) Get(organizationID uint, secretID string) (*SecretItemResponse, error) {
 
 	path := secretDataPath(organizationID, secretID)
 
@@ -462,24 +475,86 @@ func (ss *secretStore) Get(organizationID uint, secretID string) (*SecretItemRes
 }
 
 // Retrieve secret by secret Name secret/orgs/:orgid:/:id: scope
-func (ss *secretStore) GetByName(organizationID uint, name string) (*SecretItemResponse, error) {
+func (ss *vaultSecretStore) GetByName(organizationID uint, name string) (*SecretItemResponse, error) {
+	return getSecretByName(ss, organizationID, name)
+}
 
-	secretID := GenerateSecretIDFromName(name)
-	secret, err := Store.Get(organizationID, secretID)
-	if err == ErrSecretNotExists {
-		return nil, err
-	} else if err != nil {
-		return nil, errors.Wrap(err, "Error during reading secret")
+// ListVersions lists the versions of a secret secret/orgs/:orgid:/:id: scope
+func (ss *vaultSecretStore) ListVersions(organizationID uint, secretID string) ([]SecretVersion, error) {
+
+	metadata, err := ss.Logical.Read(secretMetadataPath(organizationID, secretID))
+	if err != nil {
+		return nil, errors.Wrap(err, "Error during reading secret metadata")
 	}
 
-	if secret == nil {
+	if metadata == nil {
 		return nil, ErrSecretNotExists
 	}
 
-	return secret, nil
+	currentVersion, _ := metadata.Data["current_version"].(json.Number).Int64()
+
+	versions := []SecretVersion{}
+
+	for key, value := range cast.ToStringMap(metadata.Data["versions"]) {
+		version, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid secret version: %s", key)
+		}
+
+		// Skip deleted and destroyed versions
+		versionMetadata := cast.ToStringMap(value)
+		if destroyed, _ := versionMetadata["destroyed"].(bool); destroyed || cast.ToString(versionMetadata["deletion_time"]) != "" {
+			continue
+		}
+
+		secret, err := ss.GetVersion(organizationID, secretID, version)
+		if err == ErrSecretVersionNotExists {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, SecretVersion{
+			Version:   version,
+			UpdatedAt: secret.UpdatedAt,
+			UpdatedBy: secret.UpdatedBy,
+			Current:   int64(version) == currentVersion,
+		})
+	}
+
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version > versions[j].Version })
+
+	return versions, nil
 }
 
-func (ss *secretStore) getSecretIDs(orgid uint, query *secretTypes.ListSecretsQuery) ([]string, error) {
+// GetVersion retrieves a version of a secret secret/orgs/:orgid:/:id: scope
+func (ss *vaultSecretStore) GetVersion(organizationID uint, secretID string, version int) (*SecretItemResponse, error) {
+
+	path := secretDataPath(organizationID, secretID)
+
+	secret, err := ss.Logical.ReadWithData(path, map[string][]string{"version": {strconv.Itoa(version)}})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error during reading secret version")
+	}
+
+	if secret == nil || secret.Data["data"] == nil {
+		return nil, ErrSecretVersionNotExists
+	}
+
+	return parseSecret(secretID, secret, true)
+}
+
+// RestoreVersion restores a version of a secret secret/orgs/:orgid:/:id: scope
+func (ss *vaultSecretStore) RestoreVersion(organizationID uint, secretID string, version int, updatedBy string) error {
+	return restoreSecretVersion(ss, organizationID, secretID, version, updatedBy)
+}
+
+// Rotate regenerates the values of a secret secret/orgs/:orgid:/:id: scope
+func (ss *vaultSecretStore) Rotate(organizationID uint, secretID string, updatedBy string) error {
+	return rotateSecret(ss, organizationID, secretID, updatedBy, ss.generateClusterCAs)
+}
+
+func (ss *vaultSecretStore) getSecretIDs(orgid uint, query *secretTypes.ListSecretsQuery) ([]string, error) {
 	if len(query.IDs) > 0 {
 		return query.IDs, nil
 	}
@@ -504,7 +579,7 @@ func (ss *secretStore) getSecretIDs(orgid uint, query *secretTypes.ListSecretsQu
 }
 
 // List secret secret/orgs/:orgid:/ scope
-func (ss *secretStore) List(orgid uint, query *secretTypes.ListSecretsQuery) ([]*SecretItemResponse, error) {
+func (ss *vaultSecretStore) List(orgid uint, query *secretTypes.ListSecretsQuery) ([]*SecretItemResponse, error) {
 
 	log.Debugf("Searching for secrets [orgid: %d, query: %#v]", orgid, query)
 
@@ -539,6 +614,123 @@ func (ss *secretStore) List(orgid uint, query *secretTypes.ListSecretsQuery) ([]
 	return responseItems, nil
 }
 
+// deleteSecretsByClusterUID deletes the secrets of a cluster from a store.
+func deleteSecretsByClusterUID(store SecretStore, orgID uint, clusterUID string) error {
+	if clusterUID == "" {
+		return errors.New("clusterUID is empty")
+	}
+
+	log := log.WithFields(logrus.Fields{"organization": orgID, "clusterUID": clusterUID})
+
+	clusterUIDTag := clusterUIDTag(clusterUID)
+	secrets, err := store.List(orgID,
+		&secretTypes.ListSecretsQuery{
+			Tags: []string{clusterUIDTag},
+		})
+
+	if err != nil {
+		log.Errorf("Error during list secrets: %s", err.Error())
+		return err
+	}
+
+	for _, s := range secrets {
+		log := log.WithFields(logrus.Fields{"secret": s.ID, "secretName": s.Name})
+		err := store.Delete(orgID, s.ID)
+		if err != nil {
+			log.Errorf("Error during delete secret: %s", err.Error())
+		}
+		log.Infoln("Secret Deleted")
+	}
+
+	return nil
+}
+
+// getOrCreateSecret creates a new secret in a store or gets it if it already exists.
+func getOrCreateSecret(store SecretStore, organizationID uint, value *CreateSecretRequest) (string, error) {
+	secretID := GenerateSecretID(value)
+
+	// Try to get the secret version first
+	if secret, err := store.Get(organizationID, secretID); err != nil && err != ErrSecretNotExists {
+		log.Errorf("Error during checking secret: %s", err.Error())
+		return "", err
+	} else if secret != nil {
+		return secret.ID, nil
+	} else {
+		secretID, err = store.Store(organizationID, value)
+		if err != nil {
+			log.Errorf("Error during storing secret: %s", err.Error())
+			return "", err
+		}
+	}
+	return secretID, nil
+}
+
+// createOrUpdateSecret creates a new secret in a store or updates it if it already exists.
+func createOrUpdateSecret(store SecretStore, organizationID uint, value *CreateSecretRequest) (string, error) {
+
+	secretID := GenerateSecretID(value)
+
+	// Try to get the secret version first
+	if secret, err := store.Get(organizationID, secretID); err != nil && err != ErrSecretNotExists {
+		log.Errorf("Error during checking secret: %s", err.Error())
+		return "", err
+	} else if secret != nil {
+		value.Version = &(secret.Version)
+		err := store.Update(organizationID, secretID, value)
+		if err != nil {
+			log.Errorf("Error during updating secret: %s", err.Error())
+			return "", err
+		}
+	} else {
+		secretID, err = store.Store(organizationID, value)
+		if err != nil {
+			log.Errorf("Error during storing secret: %s", err.Error())
+			return "", err
+		}
+	}
+	return secretID, nil
+}
+
+// getSecretByName gets a secret from a store by its name.
+func getSecretByName(store SecretStore, organizationID uint, name string) (*SecretItemResponse, error) {
+
+	secretID := GenerateSecretIDFromName(name)
+	secret, err := store.Get(organizationID, secretID)
+	if err == ErrSecretNotExists {
+		return nil, err
+	} else if err != nil {
+		return nil, errors.Wrap(err, "Error during reading secret")
+	}
+
+	if secret == nil {
+		return nil, ErrSecretNotExists
+	}
+
+	return secret, nil
+}
+
+// restoreSecretVersion writes a previous version of a secret as the new version in a store.
+func restoreSecretVersion(store SecretStore, organizationID uint, secretID string, version int, updatedBy string) error {
+	current, err := store.Get(organizationID, secretID)
+	if err != nil {
+		return err
+	}
+
+	previous, err := store.GetVersion(organizationID, secretID, version)
+	if err != nil {
+		return err
+	}
+
+	return store.Update(organizationID, secretID, &CreateSecretRequest{
+		Name:      previous.Name,
+		Type:      previous.Type,
+		Values:    previous.Values,
+		Tags:      previous.Tags,
+		Version:   &current.Version,
+		UpdatedBy: updatedBy,
+	})
+}
+
 func secretData(version int, request *CreateSecretRequest) (map[string]interface{}, error) {
 	valueData := map[string]interface{}{}
 
@@ -589,7 +781,34 @@ func IsCASError(err error) bool {
 	return strings.Contains(err.Error(), "check-and-set parameter did not match the current version")
 }
 
-func (ss *secretStore) generateValuesIfNeeded(organizationID uint, value *CreateSecretRequest) error {
+// prepareSecret validates a new secret and generates its values if needed.
+func prepareSecret(organizationID uint, request *CreateSecretRequest, generateCAs clusterCAGenerator) error {
+	// We allow only Kubernetes compatible Secret names
+	if errorList := validation.IsDNS1123Subdomain(request.Name); errorList != nil {
+		return errors.New(errorList[0])
+	}
+
+	if err := generateValuesIfNeeded(organizationID, request, generateCAs); err != nil {
+		return err
+	}
+
+	sort.Strings(request.Tags)
+
+	return nil
+}
+
+// clusterCAs contains the certificate authorities of a PKE cluster.
+type clusterCAs struct {
+	RootCA     string
+	Kubernetes *certificate
+	Etcd       *certificate
+	FrontProxy *certificate
+}
+
+// clusterCAGenerator generates the certificate authorities of a PKE cluster.
+type clusterCAGenerator func(organizationID uint, clusterID string) (*clusterCAs, error)
+
+func generateValuesIfNeeded(organizationID uint, value *CreateSecretRequest, generateCAs clusterCAGenerator) error {
 	if value.Type == secretTypes.TLSSecretType && len(value.Values) <= 2 {
 		// If we are not storing a full TLS secret instead of it's a request to generate one
 
@@ -656,74 +875,8 @@ func (ss *secretStore) generateValuesIfNeeded(organizationID uint, value *Create
 			return errors.New("clusterID is missing from the tags")
 		}
 
-		mountInput := vaultapi.MountInput{
-			Type:        "pki",
-			Description: fmt.Sprintf("root PKI engine for cluster %s", clusterID),
-			Config: vaultapi.MountConfigInput{
-				MaxLeaseTTL:     "43801h",
-				DefaultLeaseTTL: "43801h",
-			},
-		}
-
-		// Mount a separate PKI engine for the cluster
-		basePath := clusterPKIPath(organizationID, clusterID)
-		path := fmt.Sprintf("%s/ca", basePath)
-
-		err := ss.Client.Vault().Sys().Mount(path, &mountInput)
-		if err != nil {
-			return errors.Wrapf(err, "Error mounting pki engine for cluster %s", clusterID)
-		}
-
-		// Generate the root CA
-		rootCAData := map[string]interface{}{
-			"common_name": fmt.Sprintf("cluster-%s-ca", clusterID),
-		}
-
-		_, err = ss.Logical.Write(fmt.Sprintf("%s/root/generate/internal", path), rootCAData)
-		if err != nil {
-			// Unmount the pki engine first
-			if err := ss.Client.Vault().Sys().Unmount(path); err != nil {
-				log.Warnf("failed to unmount %s: %s", path, err)
-			}
-			return errors.Wrapf(err, "Error generating root CA for cluster %s", clusterID)
-		}
-
-		// Get root CA
-		rootCA, err := ss.Logical.Read(fmt.Sprintf("%s/cert/ca", path))
-		if err != nil {
-			// Unmount the pki engine first
-			if err := ss.Client.Vault().Sys().Unmount(path); err != nil {
-				log.Warnf("failed to unmount %s: %s", path, err)
-			}
-			return errors.Wrapf(err, "Error reading root CA for cluster %s", clusterID)
-		}
-		ca := rootCA.Data["certificate"].(string)
-
-		// Generate the intermediate CAs
-		kubernetesCA, err := ss.generateIntermediateCert(clusterID, basePath, secretTypes.KubernetesCACommonName)
-		if err != nil {
-			// Unmount the pki backend first
-			if err := ss.Client.Vault().Sys().Unmount(path); err != nil {
-				log.Warnf("failed to unmount %s: %s", path, err)
-			}
-			return err
-		}
-
-		etcdCA, err := ss.generateIntermediateCert(clusterID, basePath, secretTypes.EtcdCACommonName)
-		if err != nil {
-			// Unmount the pki backend first
-			if err := ss.Client.Vault().Sys().Unmount(path); err != nil {
-				log.Warnf("failed to unmount %s: %s", path, err)
-			}
-			return err
-		}
-
-		frontProxyCA, err := ss.generateIntermediateCert(clusterID, basePath, secretTypes.KubernetesFrontProxyCACommonName)
+		cas, err := generateCAs(organizationID, clusterID)
 		if err != nil {
-			// Unmount the pki backend first
-			if err := ss.Client.Vault().Sys().Unmount(path); err != nil {
-				log.Warnf("failed to unmount %s: %s", path, err)
-			}
 			return err
 		}
 
@@ -741,13 +894,13 @@ func (ss *secretStore) generateValuesIfNeeded(organizationID uint, value *Create
 		}
 		encryptionSecret := base64.StdEncoding.EncodeToString(rnd)
 
-		value.Values[secretTypes.KubernetesCAKey] = kubernetesCA.Key
-		value.Values[secretTypes.KubernetesCACert] = kubernetesCA.Cert + "\n" + ca
-		value.Values[secretTypes.KubernetesCASigningCert] = kubernetesCA.Cert
-		value.Values[secretTypes.EtcdCAKey] = etcdCA.Key
-		value.Values[secretTypes.EtcdCACert] = etcdCA.Cert + "\n" + ca
-		value.Values[secretTypes.FrontProxyCAKey] = frontProxyCA.Key
-		value.Values[secretTypes.FrontProxyCACert] = frontProxyCA.Cert + "\n" + ca
+		value.Values[secretTypes.KubernetesCAKey] = cas.Kubernetes.Key
+		value.Values[secretTypes.KubernetesCACert] = cas.Kubernetes.Cert + "\n" + cas.RootCA
+		value.Values[secretTypes.KubernetesCASigningCert] = cas.Kubernetes.Cert
+		value.Values[secretTypes.EtcdCAKey] = cas.Etcd.Key
+		value.Values[secretTypes.EtcdCACert] = cas.Etcd.Cert + "\n" + cas.RootCA
+		value.Values[secretTypes.FrontProxyCAKey] = cas.FrontProxy.Key
+		value.Values[secretTypes.FrontProxyCACert] = cas.FrontProxy.Cert + "\n" + cas.RootCA
 		value.Values[secretTypes.SAPub] = saPub
 		value.Values[secretTypes.SAKey] = saPriv
 		value.Values[secretTypes.EncryptionSecret] = encryptionSecret
@@ -756,7 +909,88 @@ func (ss *secretStore) generateValuesIfNeeded(organizationID uint, value *Create
 	return nil
 }
 
-func (ss *secretStore) generateIntermediateCert(clusterID, basePath, commonName string) (*certificate, error) {
+// generateClusterCAs mounts separate PKI engines for the cluster CAs.
+func (ss *vaultSecretStore) generateClusterCAs(organizationID uint, clusterID string) (*clusterCAs, error) {
+	mountInput := vaultapi.MountInput{
+		Type:        "pki",
+		Description: fmt.Sprintf("root PKI engine for cluster %s", clusterID),
+		Config: vaultapi.MountConfigInput{
+			MaxLeaseTTL:     "43801h",
+			DefaultLeaseTTL: "43801h",
+		},
+	}
+
+	// Mount a separate PKI engine for the cluster
+	basePath := clusterPKIPath(organizationID, clusterID)
+	path := fmt.Sprintf("%s/ca", basePath)
+
+	err := ss.Client.Vault().Sys().Mount(path, &mountInput)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error mounting pki engine for cluster %s", clusterID)
+	}
+
+	// Generate the root CA
+	rootCAData := map[string]interface{}{
+		"common_name": fmt.Sprintf("cluster-%s-ca", clusterID),
+	}
+
+	_, err = ss.Logical.Write(fmt.Sprintf("%s/root/generate/internal", path), rootCAData)
+	if err != nil {
+		// Unmount the pki engine first
+		if err := ss.Client.Vault().Sys().Unmount(path); err != nil {
+			log.Warnf("failed to unmount %s: %s", path, err)
+		}
+		return nil, errors.Wrapf(err, "Error generating root CA for cluster %s", clusterID)
+	}
+
+	// Get root CA
+	rootCA, err := ss.Logical.Read(fmt.Sprintf("%s/cert/ca", path))
+	if err != nil {
+		// Unmount the pki engine first
+		if err := ss.Client.Vault().Sys().Unmount(path); err != nil {
+			log.Warnf("failed to unmount %s: %s", path, err)
+		}
+		return nil, errors.Wrapf(err, "Error reading root CA for cluster %s", clusterID)
+	}
+	ca := rootCA.Data["certificate"].(string)
+
+	// Generate the intermediate CAs
+	kubernetesCA, err := ss.generateIntermediateCert(clusterID, basePath, secretTypes.KubernetesCACommonName)
+	if err != nil {
+		// Unmount the pki backend first
+		if err := ss.Client.Vault().Sys().Unmount(path); err != nil {
+			log.Warnf("failed to unmount %s: %s", path, err)
+		}
+		return nil, err
+	}
+
+	etcdCA, err := ss.generateIntermediateCert(clusterID, basePath, secretTypes.EtcdCACommonName)
+	if err != nil {
+		// Unmount the pki backend first
+		if err := ss.Client.Vault().Sys().Unmount(path); err != nil {
+			log.Warnf("failed to unmount %s: %s", path, err)
+		}
+		return nil, err
+	}
+
+	frontProxyCA, err := ss.generateIntermediateCert(clusterID, basePath, secretTypes.KubernetesFrontProxyCACommonName)
+	if err != nil {
+		// Unmount the pki backend first
+		if err := ss.Client.Vault().Sys().Unmount(path); err != nil {
+			log.Warnf("failed to unmount %s: %s", path, err)
+		}
+		return nil, err
+	}
+
+	return &clusterCAs{
+		RootCA:     ca,
+		Kubernetes: kubernetesCA,
+		Etcd:       etcdCA,
+		FrontProxy: frontProxyCA,
+	}, nil
+}
+
+func (ss *vaultSecretStore) generateIntermediateCert(clusterID, basePath, commonName string) (*certificate, error) {
 	mountInput := vaultapi.MountInput{
 		Type:        "pki",
 		Description: fmt.Sprintf("%s intermediate PKI engine for cluster %s", commonName, clusterID),